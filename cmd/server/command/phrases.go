@@ -0,0 +1,123 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ardfard/sb-test/config"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/ardfard/sb-test/internal/infrastructure/repository/sqlite"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importUserID uint
+	importFormat string
+	importDryRun bool
+
+	phrasesCmd = &cobra.Command{
+		Use:   "phrases",
+		Short: "Manage phrases",
+	}
+	phrasesImportCmd = &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import phrases of a user from a CSV, TSV or plain text file",
+		Long: `Creates and updates phrases of a user from FILE, or standard input if FILE is "-".
+CSV and TSV files have a header row naming a text column and optionally id,
+language and tags columns; plain text files have one phrase per line. Rows with
+an id update that phrase, others create a phrase unless the user already has one
+with the same text. Nothing is changed if any row is invalid.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         importPhrases,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	phrasesImportCmd.Flags().UintVarP(&importUserID, "user", "u", 0, "ID of the user owning the phrases")
+	phrasesImportCmd.Flags().StringVarP(&importFormat, "format", "f", "", `"csv", "tsv" or "text"; guessed from the file extension if empty`)
+	phrasesImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "report what would change without changing anything")
+	phrasesImportCmd.MarkFlagRequired("user")
+	phrasesCmd.AddCommand(phrasesImportCmd)
+	rootCmd.AddCommand(phrasesCmd)
+}
+
+// phraseImportFormat returns the format flag, or the format of path guessed
+// from its extension.
+func phraseImportFormat(path string) (usecase.PhraseImportFormat, error) {
+	if importFormat != "" {
+		return usecase.ParsePhraseImportFormat(importFormat)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return usecase.PhraseImportCSV, nil
+	case ".tsv", ".tab":
+		return usecase.PhraseImportTSV, nil
+	}
+	return usecase.PhraseImportText, nil
+}
+
+func importPhrases(cmd *cobra.Command, args []string) error {
+	format, err := phraseImportFormat(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config: %v", err)
+	}
+	db, err := database.InitDB(cfg.SQLite.DBPath)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	userRepo, err := sqlite.NewUserRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create user repository: %v", err)
+	}
+	phraseRepo, err := sqlite.NewPhraseRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create phrase repository: %v", err)
+	}
+
+	var content io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open phrases: %v", err)
+		}
+		defer file.Close()
+		content = file
+	}
+
+	report, err := usecase.NewImportPhrasesUseCase(phraseRepo, userRepo).Import(cmd.Context(), importUserID, format, content, importDryRun)
+	if err != nil {
+		return err
+	}
+
+	out := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "LINE\tACTION\tPHRASE\tTEXT")
+	for _, row := range report.Rows {
+		phraseID := "-"
+		if row.PhraseID != 0 {
+			phraseID = fmt.Sprint(row.PhraseID)
+		}
+		fmt.Fprintf(out, "%d\t%s\t%s\t%s\n", row.Line, row.Action, phraseID, row.Text)
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("%d created, %d updated, %d unchanged, %d duplicates",
+		report.Created, report.Updated, report.Unchanged, report.Duplicates)
+	if report.DryRun {
+		summary += " (dry run, nothing was changed)"
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), summary)
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/ardfard/sb-test/config"
 	"github.com/ardfard/sb-test/internal/delivery/http/handler"
 	"github.com/ardfard/sb-test/internal/delivery/http/router"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/ardfard/sb-test/internal/infrastructure/converter"
 	"github.com/ardfard/sb-test/internal/infrastructure/database"
 	"github.com/ardfard/sb-test/internal/infrastructure/queue"
@@ -14,12 +16,18 @@ import (
 	"github.com/ardfard/sb-test/internal/infrastructure/storage"
 	"github.com/ardfard/sb-test/internal/usecase"
 	"github.com/ardfard/sb-test/internal/worker"
+	"github.com/ardfard/sb-test/pkg/pitch"
+	"github.com/ardfard/sb-test/pkg/quality"
+	"github.com/ardfard/sb-test/pkg/silence"
+	"github.com/ardfard/sb-test/pkg/spectrogram"
+	"github.com/ardfard/sb-test/pkg/waveform"
 	"github.com/spf13/cobra"
 
 	"context"
 	"net/http"
 	"os"
 	"os/signal"
+	"time"
 )
 
 var (
@@ -46,6 +54,87 @@ func run(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("error loading config: %v", err)
 	}
+	if cfg.Share.Secret == "" {
+		return fmt.Errorf("a share secret must be set with SHARE_SECRET or share.secret_file")
+	}
+	waveformOptions := waveform.Options{
+		PixelsPerSecond: cfg.Waveform.PixelsPerSecond,
+		Bits:            cfg.Waveform.Bits,
+		Channels:        cfg.Waveform.Channels,
+	}
+	if err := waveformOptions.Validate(); err != nil {
+		return fmt.Errorf("invalid waveform config: %v", err)
+	}
+	loudnessTarget := entity.LoudnessTarget{
+		Integrated: cfg.Loudness.IntegratedLUFS,
+		TruePeak:   cfg.Loudness.TruePeak,
+		Range:      cfg.Loudness.LoudnessRange,
+	}
+	if cfg.Loudness.Enabled {
+		if err := loudnessTarget.Validate(); err != nil {
+			return fmt.Errorf("invalid loudness config: %v", err)
+		}
+	}
+	// Trimming can be re-applied on request even when it is disabled for conversions.
+	trimOptions := silence.Options{
+		ThresholdDB: cfg.Trim.ThresholdDB,
+		MinDuration: cfg.Trim.MinSilence,
+		Padding:     cfg.Trim.Padding,
+	}
+	if err := trimOptions.Validate(); err != nil {
+		return fmt.Errorf("invalid trim config: %v", err)
+	}
+	segmentationOptions := silence.Options{
+		ThresholdDB: cfg.Segmentation.ThresholdDB,
+		MinDuration: cfg.Segmentation.MinSilence,
+		Padding:     cfg.Segmentation.Padding,
+	}
+	if err := segmentationOptions.Validate(); err != nil {
+		return fmt.Errorf("invalid segmentation config: %v", err)
+	}
+	analysisThresholds := quality.Thresholds{
+		MaxClippedRatio: cfg.Analysis.MaxClippedRatio,
+		MaxNoiseFloorDB: cfg.Analysis.MaxNoiseFloorDB,
+		MinSNRDB:        cfg.Analysis.MinSNRDB,
+		MinSpeechRatio:  cfg.Analysis.MinSpeechRatio,
+	}
+	if err := analysisThresholds.Validate(); err != nil {
+		return fmt.Errorf("invalid analysis config: %v", err)
+	}
+	spectrogramOptions := spectrogram.Options{
+		FFTSize:  cfg.Spectrogram.FFTSize,
+		Hop:      cfg.Spectrogram.Hop,
+		Scale:    cfg.Spectrogram.Scale,
+		MinDB:    cfg.Spectrogram.MinDB,
+		MaxDB:    cfg.Spectrogram.MaxDB,
+		ColorMap: cfg.Spectrogram.ColorMap,
+		Width:    cfg.Spectrogram.Width,
+		Height:   cfg.Spectrogram.Height,
+	}
+	if err := spectrogramOptions.Validate(); err != nil {
+		return fmt.Errorf("invalid spectrogram config: %v", err)
+	}
+	pitchOptions := pitch.Options{
+		Hop:       cfg.Pitch.Hop,
+		MinF0:     cfg.Pitch.MinF0,
+		MaxF0:     cfg.Pitch.MaxF0,
+		Threshold: cfg.Pitch.Threshold,
+	}
+	if err := pitchOptions.Validate(); err != nil {
+		return fmt.Errorf("invalid pitch config: %v", err)
+	}
+	nextPhraseStrategy := repository.NextPhraseStrategy(cfg.Recording.NextPhraseStrategy)
+	if !nextPhraseStrategy.Valid() {
+		return fmt.Errorf("invalid recording config: unknown next phrase strategy %q", nextPhraseStrategy)
+	}
+	profiles, err := processingProfiles(cfg.Profiles)
+	if err != nil {
+		return fmt.Errorf("invalid profiles config: %v", err)
+	}
+	speakerSchema := speakerSchema(cfg.SpeakerAttributes)
+	if err := speakerSchema.Validate(); err != nil {
+		return fmt.Errorf("invalid speaker attributes config: %v", err)
+	}
 
 	// Initialize database
 	db, err := database.InitDB(cfg.SQLite.DBPath)
@@ -67,12 +156,52 @@ func run(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create phrase repository: %v", err)
 	}
+	shareLinkRepo, err := sqlite.NewShareLinkRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create share link repository: %v", err)
+	}
+	uploadRepo, err := sqlite.NewUploadRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create upload repository: %v", err)
+	}
+	compilationRepo, err := sqlite.NewCompilationRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create compilation repository: %v", err)
+	}
+	collectionRepo, err := sqlite.NewCollectionRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create collection repository: %v", err)
+	}
+	analysisRepo, err := sqlite.NewAudioAnalysisRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create analysis repository: %v", err)
+	}
+	reviewRepo, err := sqlite.NewAudioReviewRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create review repository: %v", err)
+	}
+	annotationRepo, err := sqlite.NewAnnotationRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create annotation repository: %v", err)
+	}
+	sessionRepo, err := sqlite.NewRecordingSessionRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create recording session repository: %v", err)
+	}
+	userDeletionRepo, err := sqlite.NewUserDeletionRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create user deletion repository: %v", err)
+	}
 
 	// Initialize storage using configuration
 	storageInstance, err := storage.NewStorage(&cfg.Storage)
 	if err != nil {
 		return fmt.Errorf("failed to create storage: %v", err)
 	}
+	stagingArea, err := storage.NewLocalStagingArea(cfg.Tus.StagingDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to create staging area: %v", err)
+	}
 
 	// Initialize other components using configuration values.
 	converterInstance := converter.NewAudioConverter()
@@ -81,22 +210,125 @@ func run(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create queue: %v", err)
 	}
+	compilationQueue, err := queue.NewSQLiteQueue(db, "compilation")
+	if err != nil {
+		return fmt.Errorf("failed to create compilation queue: %v", err)
+	}
+	userDeletionQueue, err := queue.NewSQLiteQueue(db, "user_deletion")
+	if err != nil {
+		return fmt.Errorf("failed to create user deletion queue: %v", err)
+	}
+	rescoreQueue, err := queue.NewSQLiteQueue(db, "rescore")
+	if err != nil {
+		return fmt.Errorf("failed to create rescore queue: %v", err)
+	}
 
 	// Initialize use cases
-	uploadAudioUseCase := usecase.NewUploadAudioUseCase(repo, storageInstance, queueInstance, userRepo, phraseRepo)
-	convertAudioUseCase := usecase.NewConvertAudioUseCase(repo, storageInstance, converterInstance)
-	downloadAudioUseCase := usecase.NewDownloadAudioUseCase(repo, storageInstance, converterInstance, userRepo, phraseRepo)
+	uploadAudioUseCase := usecase.NewUploadAudioUseCase(repo, storageInstance, converterInstance, queueInstance, userRepo, phraseRepo, sessionRepo, reviewRepo, usecase.UploadPolicy{
+		AllowedFormats: cfg.Upload.AllowedFormats,
+		MaxSize:        cfg.Upload.MaxSize,
+		MinDuration:    cfg.Upload.MinDuration,
+		MaxDuration:    cfg.Upload.MaxDuration,
+		MinSampleRate:  cfg.Upload.MinSampleRate,
+		MaxSampleRate:  cfg.Upload.MaxSampleRate,
+	})
+	segmentedUploadUseCase := usecase.NewSegmentedUploadUseCase(uploadAudioUseCase, converterInstance, segmentationOptions)
+	waveformUseCase := usecase.NewWaveformUseCase(repo, storageInstance, waveformOptions)
+	spectrogramUseCase := usecase.NewSpectrogramUseCase(repo, storageInstance, spectrogramOptions)
+	trimUseCase := usecase.NewTrimUseCase(repo, storageInstance, trimOptions)
+	pitchUseCase := usecase.NewPitchUseCase(repo, storageInstance, pitchOptions)
+	similarityUseCase := usecase.NewSimilarityUseCase(repo, phraseRepo, storageInstance, converterInstance, rescoreQueue, uploadAudioUseCase.Policy())
+	reviewUseCase := usecase.NewReviewUseCase(reviewRepo, repo, userRepo, sessionRepo)
+	postProcessors := []usecase.AudioPostProcessor{waveformUseCase, pitchUseCase, similarityUseCase, reviewUseCase}
+	if cfg.Trim.Enabled {
+		postProcessors = append(postProcessors, trimUseCase)
+	}
+	if cfg.Loudness.Enabled {
+		postProcessors = append(postProcessors, usecase.NewLoudnessUseCase(storageInstance, converterInstance, loudnessTarget))
+	}
+	analysisUseCase := usecase.NewAnalysisUseCase(analysisRepo, repo, cfg.Analysis.SpeechThresholdDB, analysisThresholds)
+	if cfg.Analysis.Enabled {
+		postProcessors = append(postProcessors, analysisUseCase)
+	}
+	convertAudioUseCase := usecase.NewConvertAudioUseCase(repo, storageInstance, converterInstance, postProcessors...)
+	downloadAudioUseCase := usecase.NewDownloadAudioUseCase(repo, storageInstance, converterInstance, userRepo, phraseRepo, profiles, cfg.Trim.Enabled)
+	getAudioUseCase := usecase.NewGetAudioUseCase(repo)
+	clipAudioUseCase := usecase.NewClipAudioUseCase(repo, storageInstance, converterInstance)
+	shareAudioUseCase := usecase.NewShareAudioUseCase(repo, shareLinkRepo, storageInstance, downloadAudioUseCase, usecase.ShareLinkSettings{
+		Secret:          []byte(cfg.Share.Secret),
+		DefaultTTL:      cfg.Share.DefaultTTL,
+		MaxTTL:          cfg.Share.MaxTTL,
+		PresignRedirect: cfg.Storage.S3 != nil && cfg.Storage.S3.PresignRedirect,
+	})
+
+	compilationUseCase := usecase.NewCompilationUseCase(compilationRepo, repo, userRepo, storageInstance, converterInstance, compilationQueue)
 
-	createUserUseCase := usecase.NewCreateUserUseCase(userRepo)
-	createPhraseUseCase := usecase.NewCreatePhraseUseCase(phraseRepo)
+	resumableUploadUseCase := usecase.NewResumableUploadUseCase(uploadRepo, stagingArea, userRepo, phraseRepo, uploadAudioUseCase, cfg.Tus.MaxSize, cfg.Tus.Expiry)
+
+	createUserUseCase := usecase.NewCreateUserUseCase(userRepo, profiles, speakerSchema)
+	setProcessingProfileUseCase := usecase.NewSetProcessingProfileUseCase(userRepo, profiles)
+	getUserUseCase := usecase.NewGetUserUseCase(userRepo)
+	updateUserUseCase := usecase.NewUpdateUserUseCase(userRepo, profiles, speakerSchema)
+	deleteUserUseCase := usecase.NewDeleteUserUseCase(userDeletionRepo, userRepo, phraseRepo, repo, compilationRepo, uploadRepo,
+		storageInstance, stagingArea, userDeletionQueue, queueInstance, compilationQueue)
+	createPhraseUseCase := usecase.NewCreatePhraseUseCase(phraseRepo, userRepo)
+	sharePhraseUseCase := usecase.NewSharePhraseUseCase(phraseRepo, userRepo)
+	getPhraseUseCase := usecase.NewGetPhraseUseCase(phraseRepo, userRepo)
+	updatePhraseUseCase := usecase.NewUpdatePhraseUseCase(phraseRepo)
+	deletePhraseUseCase := usecase.NewDeletePhraseUseCase(phraseRepo, repo, uploadRepo, storageInstance, stagingArea, queueInstance)
+	importPhrasesUseCase := usecase.NewImportPhrasesUseCase(phraseRepo, userRepo)
+	collectionUseCase := usecase.NewCollectionUseCase(collectionRepo, userRepo, uploadAudioUseCase, downloadAudioUseCase)
+	progressUseCase := usecase.NewProgressUseCase(repo, phraseRepo, userRepo, nextPhraseStrategy)
+	annotationUseCase := usecase.NewAnnotationUseCase(annotationRepo, repo)
+	speakerReportUseCase := usecase.NewSpeakerReportUseCase(userRepo, speakerSchema)
+	sessionUseCase := usecase.NewRecordingSessionUseCase(sessionRepo, repo, userRepo)
 
 	// Initialize handler.
-	audioHandler := handler.NewAudioHandler(uploadAudioUseCase, downloadAudioUseCase)
-	userHandler := handler.NewUserHandler(createUserUseCase)
-	phraseHandler := handler.NewPhraseHandler(createPhraseUseCase)
+	audioHandler := handler.NewAudioHandler(uploadAudioUseCase, downloadAudioUseCase, getAudioUseCase)
+	userHandler := handler.NewUserHandler(createUserUseCase, setProcessingProfileUseCase, getUserUseCase, updateUserUseCase, deleteUserUseCase)
+	phraseHandler := handler.NewPhraseHandler(createPhraseUseCase, sharePhraseUseCase, getPhraseUseCase, updatePhraseUseCase, deletePhraseUseCase,
+		importPhrasesUseCase)
+	shareHandler := handler.NewShareHandler(shareAudioUseCase, cfg.Share.BaseURL)
+	tusHandler := handler.NewTusHandler(resumableUploadUseCase)
+	waveformHandler := handler.NewWaveformHandler(waveformUseCase)
+	trimHandler := handler.NewTrimHandler(trimUseCase)
+	clipHandler := handler.NewClipHandler(clipAudioUseCase)
+	compilationHandler := handler.NewCompilationHandler(compilationUseCase)
+	segmentedUploadHandler := handler.NewSegmentedUploadHandler(segmentedUploadUseCase)
+	analysisHandler := handler.NewAnalysisHandler(analysisUseCase)
+	spectrogramHandler := handler.NewSpectrogramHandler(spectrogramUseCase)
+	pitchHandler := handler.NewPitchHandler(pitchUseCase)
+	similarityHandler := handler.NewSimilarityHandler(similarityUseCase)
+	collectionHandler := handler.NewCollectionHandler(collectionUseCase)
+	progressHandler := handler.NewProgressHandler(progressUseCase)
+	reviewHandler := handler.NewReviewHandler(reviewUseCase)
+	annotationHandler := handler.NewAnnotationHandler(annotationUseCase)
+	speakerHandler := handler.NewSpeakerHandler(speakerReportUseCase)
+	sessionHandler := handler.NewRecordingSessionHandler(sessionUseCase)
 
 	// Initialize the router with all defined routes.
-	r := router.SetupRoutes(audioHandler, userHandler, phraseHandler)
+	r := router.SetupRoutes(router.Handlers{
+		Audio:       audioHandler,
+		User:        userHandler,
+		Phrase:      phraseHandler,
+		Share:       shareHandler,
+		Tus:         tusHandler,
+		Waveform:    waveformHandler,
+		Trim:        trimHandler,
+		Clip:        clipHandler,
+		Compilation: compilationHandler,
+		Segmented:   segmentedUploadHandler,
+		Analysis:    analysisHandler,
+		Spectrogram: spectrogramHandler,
+		Pitch:       pitchHandler,
+		Similarity:  similarityHandler,
+		Collection:  collectionHandler,
+		Progress:    progressHandler,
+		Review:      reviewHandler,
+		Annotation:  annotationHandler,
+		Speaker:     speakerHandler,
+		Session:     sessionHandler,
+	})
 
 	// Create server
 	srv := &http.Server{
@@ -119,6 +351,20 @@ func run(cmd *cobra.Command, args []string) error {
 	conversionWorker := worker.NewConversionWorker(queueInstance, convertAudioUseCase)
 	conversionWorker.Start()
 	defer conversionWorker.Stop()
+	compilationWorker := worker.NewCompilationWorker(compilationQueue, compilationUseCase)
+	compilationWorker.Start()
+	defer compilationWorker.Stop()
+	userDeletionWorker := worker.NewUserDeletionWorker(userDeletionQueue, deleteUserUseCase)
+	userDeletionWorker.Start()
+	defer userDeletionWorker.Stop()
+	rescoreWorker := worker.NewRescoreWorker(rescoreQueue, similarityUseCase)
+	rescoreWorker.Start()
+	defer rescoreWorker.Stop()
+	if cfg.Tus.Expiry > 0 {
+		uploadExpiryWorker := worker.NewUploadExpiryWorker(resumableUploadUseCase, time.Hour)
+		uploadExpiryWorker.Start()
+		defer uploadExpiryWorker.Stop()
+	}
 
 	// Start server and block until it's closed.
 	log.Printf("Starting server on %s", cfg.ServerAddress)
@@ -128,3 +374,32 @@ func run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// processingProfiles converts the configured profiles, rejecting filters the
+// converter does not allow.
+func processingProfiles(configured map[string][]config.FilterConfig) (usecase.ProcessingProfiles, error) {
+	profiles := usecase.ProcessingProfiles{}
+	for name, steps := range configured {
+		if name == usecase.ProfileNone {
+			return nil, fmt.Errorf("profile name %q is reserved", name)
+		}
+		filters := make([]entity.Filter, 0, len(steps))
+		for _, step := range steps {
+			filters = append(filters, entity.Filter{Name: step.Name, Params: step.Params})
+		}
+		if err := converter.ValidateFilters(filters); err != nil {
+			return nil, fmt.Errorf("profile %q: %v", name, err)
+		}
+		profiles[name] = filters
+	}
+	return profiles, nil
+}
+
+// speakerSchema converts the configured speaker attributes.
+func speakerSchema(configured map[string]config.SpeakerAttributeConfig) usecase.SpeakerSchema {
+	schema := usecase.SpeakerSchema{}
+	for name, attribute := range configured {
+		schema[name] = usecase.SpeakerAttribute{Values: attribute.Values, MaxLength: attribute.MaxLength}
+	}
+	return schema
+}
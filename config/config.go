@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -15,6 +18,9 @@ type S3StorageConfig struct {
 	Region          string `mapstructure:"region"`
 	AccessKeyID     string `mapstructure:"access_key_id"`
 	SecretAccessKey string `mapstructure:"secret_access_key"`
+	// PresignRedirect makes share links redirect to a presigned GetObject URL
+	// when the requested format matches the stored object.
+	PresignRedirect bool `mapstructure:"presign_redirect"`
 }
 
 // StorageConfig holds settings for the storage backend.
@@ -24,6 +30,114 @@ type StorageConfig struct {
 	Local *LocalStorageConfig `mapstructure:"local"`
 }
 
+// ShareConfig holds settings for signed, expiring download links.
+type ShareConfig struct {
+	Secret     string        `mapstructure:"secret"`      // HMAC key used to sign links, overridden by SHARE_SECRET
+	SecretFile string        `mapstructure:"secret_file"` // File holding the key, read when Secret is empty
+	BaseURL    string        `mapstructure:"base_url"`    // Prefix for minted links, e.g. "https://audio.example.com"
+	DefaultTTL time.Duration `mapstructure:"default_ttl"` // Used when the request does not specify a TTL
+	MaxTTL     time.Duration `mapstructure:"max_ttl"`     // Upper bound for requested TTLs
+}
+
+// TusConfig holds settings for resumable (tus) uploads.
+type TusConfig struct {
+	StagingDirectory string        `mapstructure:"staging_directory"` // Where partial uploads are assembled
+	MaxSize          int64         `mapstructure:"max_size"`          // Maximum Upload-Length in bytes, 0 means unlimited
+	Expiry           time.Duration `mapstructure:"expiry"`            // Unfinished uploads idle this long are discarded, 0 keeps them
+}
+
+// UploadConfig holds the policy applied to uploaded audio. Zero values
+// disable the corresponding limit.
+type UploadConfig struct {
+	AllowedFormats []string      `mapstructure:"allowed_formats"` // Detected container formats, e.g. "m4a"
+	MaxSize        int64         `mapstructure:"max_size"`        // Bytes
+	MinDuration    time.Duration `mapstructure:"min_duration"`
+	MaxDuration    time.Duration `mapstructure:"max_duration"`
+	MinSampleRate  int           `mapstructure:"min_sample_rate"` // Hz
+	MaxSampleRate  int           `mapstructure:"max_sample_rate"` // Hz
+}
+
+// WaveformConfig controls the peak data computed for converted audio.
+type WaveformConfig struct {
+	PixelsPerSecond int `mapstructure:"pixels_per_second"` // Min/max pairs per second of audio
+	Bits            int `mapstructure:"bits"`              // 8 or 16
+	Channels        int `mapstructure:"channels"`          // 1 (mixed down) or 2 (per channel)
+}
+
+// LoudnessConfig controls EBU R128 loudness normalization of converted audio.
+type LoudnessConfig struct {
+	Enabled        bool    `mapstructure:"enabled"`
+	IntegratedLUFS float64 `mapstructure:"integrated_lufs"` // Target integrated loudness, -70 to -5
+	TruePeak       float64 `mapstructure:"true_peak"`       // Maximum true peak in dBTP, -9 to 0
+	LoudnessRange  float64 `mapstructure:"loudness_range"`  // Target loudness range in LU, 1 to 50
+}
+
+// TrimConfig controls detection of leading and trailing silence in converted audio.
+type TrimConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	ThresholdDB float64       `mapstructure:"threshold_db"` // RMS level in dBFS below which audio is silent
+	MinSilence  time.Duration `mapstructure:"min_silence"`  // Shorter silence is not trimmed
+	Padding     time.Duration `mapstructure:"padding"`      // Silence kept around the audible part
+}
+
+// SegmentationConfig controls how recordings of several phrases are split
+// at the pauses between them.
+type SegmentationConfig struct {
+	ThresholdDB float64       `mapstructure:"threshold_db"` // RMS level in dBFS below which audio is silent
+	MinSilence  time.Duration `mapstructure:"min_silence"`  // Shorter pauses do not split a phrase
+	Padding     time.Duration `mapstructure:"padding"`      // Silence kept around each phrase
+}
+
+// AnalysisConfig controls quality analysis of converted audio. Takes that
+// violate a threshold are flagged for review; zero thresholds are not checked.
+type AnalysisConfig struct {
+	Enabled           bool    `mapstructure:"enabled"`
+	SpeechThresholdDB float64 `mapstructure:"speech_threshold_db"` // RMS level in dBFS from which audio counts as speech
+	MaxClippedRatio   float64 `mapstructure:"max_clipped_ratio"`   // Fraction of samples at full scale, 0 to 1
+	MaxNoiseFloorDB   float64 `mapstructure:"max_noise_floor_db"`  // dBFS
+	MinSNRDB          float64 `mapstructure:"min_snr_db"`          // dB
+	MinSpeechRatio    float64 `mapstructure:"min_speech_ratio"`    // Fraction of the audio with speech, 0 to 1
+}
+
+// SpectrogramConfig holds the default options of rendered spectrograms.
+type SpectrogramConfig struct {
+	FFTSize  int     `mapstructure:"fft_size"`  // Samples per transform, a power of two
+	Hop      int     `mapstructure:"hop"`       // Samples between transforms
+	Scale    string  `mapstructure:"scale"`     // "linear" or "mel"
+	MinDB    float64 `mapstructure:"min_db"`    // Level drawn with the lowest color
+	MaxDB    float64 `mapstructure:"max_db"`    // Level drawn with the highest color
+	ColorMap string  `mapstructure:"color_map"` // "gray", "inferno" or "viridis"
+	Width    int     `mapstructure:"width"`     // Pixels
+	Height   int     `mapstructure:"height"`    // Pixels
+}
+
+// PitchConfig controls F0 tracking of converted audio.
+type PitchConfig struct {
+	Hop       time.Duration `mapstructure:"hop"`       // Time between estimates
+	MinF0     float64       `mapstructure:"min_f0"`    // Hz
+	MaxF0     float64       `mapstructure:"max_f0"`    // Hz
+	Threshold float64       `mapstructure:"threshold"` // YIN aperiodicity threshold, 0 to 1
+}
+
+// RecordingConfig controls how speakers are guided through their phrases.
+type RecordingConfig struct {
+	NextPhraseStrategy string `mapstructure:"next_phrase_strategy"` // "creation", "random" or "failed_first"
+}
+
+// SpeakerAttributeConfig describes an optional attribute of speakers, such
+// as their age range or accent.
+type SpeakerAttributeConfig struct {
+	Values    []string `mapstructure:"values"`     // Allowed values; any text when empty
+	MaxLength int      `mapstructure:"max_length"` // Of free-text values in characters, 0 means the default
+}
+
+// FilterConfig is one step of a processing profile: an allow-listed ffmpeg
+// audio filter and its parameters.
+type FilterConfig struct {
+	Name   string            `mapstructure:"name"`
+	Params map[string]string `mapstructure:"params"`
+}
+
 // Config holds configuration values for the application.
 type Config struct {
 	ServerAddress string        `mapstructure:"server_address"`
@@ -31,6 +145,23 @@ type Config struct {
 	SQLite        struct {
 		DBPath string `mapstructure:"db_path"`
 	} `mapstructure:"sqlite"`
+	Share    ShareConfig    `mapstructure:"share"`
+	Tus      TusConfig      `mapstructure:"tus"`
+	Upload   UploadConfig   `mapstructure:"upload"`
+	Waveform WaveformConfig `mapstructure:"waveform"`
+	Loudness LoudnessConfig `mapstructure:"loudness"`
+	Trim     TrimConfig     `mapstructure:"trim"`
+	// Segmentation applies to recordings uploaded for several phrases at once.
+	Segmentation SegmentationConfig `mapstructure:"segmentation"`
+	Analysis     AnalysisConfig     `mapstructure:"analysis"`
+	Spectrogram  SpectrogramConfig  `mapstructure:"spectrogram"`
+	Pitch        PitchConfig        `mapstructure:"pitch"`
+	Recording    RecordingConfig    `mapstructure:"recording"`
+	// SpeakerAttributes are the attributes users may be described with as
+	// speakers, keyed by name.
+	SpeakerAttributes map[string]SpeakerAttributeConfig `mapstructure:"speaker_attributes"`
+	// Profiles are named filter chains that downloads can be processed with.
+	Profiles map[string][]FilterConfig `mapstructure:"profiles"`
 }
 
 // LoadConfig reads configuration from config.yaml (or other supported formats) in the current directory.
@@ -38,6 +169,47 @@ func LoadConfig(configPath string) (*Config, error) {
 	// Set the name of the config file (without extension)
 	viper.SetConfigFile(configPath)
 
+	if err := viper.BindEnv("share.secret", "SHARE_SECRET"); err != nil {
+		return nil, fmt.Errorf("error binding environment: %w", err)
+	}
+	viper.SetDefault("share.default_ttl", time.Hour)
+	viper.SetDefault("share.max_ttl", 7*24*time.Hour)
+	viper.SetDefault("tus.staging_directory", "./staging")
+	viper.SetDefault("tus.expiry", 24*time.Hour)
+	viper.SetDefault("upload.allowed_formats", []string{"m4a", "mp3", "wav", "flac", "ogg", "webm", "aac", "aiff"})
+	viper.SetDefault("upload.max_size", 100<<20)
+	viper.SetDefault("waveform.pixels_per_second", 100)
+	viper.SetDefault("waveform.bits", 8)
+	viper.SetDefault("waveform.channels", 1)
+	viper.SetDefault("loudness.integrated_lufs", -23.0)
+	viper.SetDefault("loudness.true_peak", -1.0)
+	viper.SetDefault("loudness.loudness_range", 7.0)
+	viper.SetDefault("trim.threshold_db", -50.0)
+	viper.SetDefault("trim.min_silence", 300*time.Millisecond)
+	viper.SetDefault("trim.padding", 100*time.Millisecond)
+	viper.SetDefault("segmentation.threshold_db", -40.0)
+	viper.SetDefault("segmentation.min_silence", 500*time.Millisecond)
+	viper.SetDefault("segmentation.padding", 150*time.Millisecond)
+	viper.SetDefault("analysis.enabled", true)
+	viper.SetDefault("analysis.speech_threshold_db", -40.0)
+	viper.SetDefault("analysis.max_clipped_ratio", 0.001)
+	viper.SetDefault("analysis.max_noise_floor_db", -50.0)
+	viper.SetDefault("analysis.min_snr_db", 20.0)
+	viper.SetDefault("analysis.min_speech_ratio", 0.1)
+	viper.SetDefault("spectrogram.fft_size", 1024)
+	viper.SetDefault("spectrogram.hop", 256)
+	viper.SetDefault("spectrogram.scale", "linear")
+	viper.SetDefault("spectrogram.min_db", -100.0)
+	viper.SetDefault("spectrogram.max_db", 0.0)
+	viper.SetDefault("spectrogram.color_map", "viridis")
+	viper.SetDefault("spectrogram.width", 800)
+	viper.SetDefault("spectrogram.height", 256)
+	viper.SetDefault("pitch.hop", 10*time.Millisecond)
+	viper.SetDefault("pitch.min_f0", 75.0)
+	viper.SetDefault("pitch.max_f0", 600.0)
+	viper.SetDefault("pitch.threshold", 0.15)
+	viper.SetDefault("recording.next_phrase_strategy", "creation")
+
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("error reading config file: %w", err)
 	}
@@ -46,5 +218,12 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("unable to decode into struct: %w", err)
 	}
+	if cfg.Share.Secret == "" && cfg.Share.SecretFile != "" {
+		secret, err := os.ReadFile(cfg.Share.SecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading share secret file: %w", err)
+		}
+		cfg.Share.Secret = strings.TrimSpace(string(secret))
+	}
 	return &cfg, nil
 }
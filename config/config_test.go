@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -26,6 +27,45 @@ storage:
     directory: "/tmp/storage"
 sqlite:
   db_path: "/tmp/app.db"
+share:
+  secret: "test-secret"
+  default_ttl: "30m"
+tus:
+  staging_directory: "/tmp/staging"
+  max_size: 1048576
+upload:
+  allowed_formats: ["m4a", "wav"]
+  max_duration: "5m"
+  min_sample_rate: 16000
+waveform:
+  bits: 16
+loudness:
+  enabled: true
+  integrated_lufs: -16
+trim:
+  enabled: true
+  padding: "250ms"
+segmentation:
+  min_silence: "750ms"
+analysis:
+  min_snr_db: 15
+spectrogram:
+  scale: mel
+pitch:
+  hop: "5ms"
+recording:
+  next_phrase_strategy: failed_first
+speaker_attributes:
+  gender:
+    values: ["female", "male"]
+  accent:
+    max_length: 40
+profiles:
+  clean:
+    - name: highpass
+      params:
+        f: 80
+    - name: dynaudnorm
 worker:
   num_workers: 4
 `
@@ -52,6 +92,58 @@ worker:
 				assert.Equal(t, "test-secret", cfg.Storage.S3.SecretAccessKey)
 				assert.Equal(t, "/tmp/storage", cfg.Storage.Local.Directory)
 				assert.Equal(t, "/tmp/app.db", cfg.SQLite.DBPath)
+				assert.Equal(t, "test-secret", cfg.Share.Secret)
+				assert.Equal(t, 30*time.Minute, cfg.Share.DefaultTTL)
+				assert.Equal(t, 7*24*time.Hour, cfg.Share.MaxTTL)
+				assert.Equal(t, "/tmp/staging", cfg.Tus.StagingDirectory)
+				assert.Equal(t, int64(1048576), cfg.Tus.MaxSize)
+				assert.Equal(t, []string{"m4a", "wav"}, cfg.Upload.AllowedFormats)
+				assert.Equal(t, int64(100<<20), cfg.Upload.MaxSize)
+				assert.Equal(t, time.Duration(0), cfg.Upload.MinDuration)
+				assert.Equal(t, 5*time.Minute, cfg.Upload.MaxDuration)
+				assert.Equal(t, 16000, cfg.Upload.MinSampleRate)
+				assert.Equal(t, 100, cfg.Waveform.PixelsPerSecond)
+				assert.Equal(t, 16, cfg.Waveform.Bits)
+				assert.Equal(t, 1, cfg.Waveform.Channels)
+				assert.True(t, cfg.Loudness.Enabled)
+				assert.Equal(t, -16.0, cfg.Loudness.IntegratedLUFS)
+				assert.Equal(t, -1.0, cfg.Loudness.TruePeak)
+				assert.Equal(t, 7.0, cfg.Loudness.LoudnessRange)
+				assert.True(t, cfg.Trim.Enabled)
+				assert.Equal(t, -50.0, cfg.Trim.ThresholdDB)
+				assert.Equal(t, 300*time.Millisecond, cfg.Trim.MinSilence)
+				assert.Equal(t, 250*time.Millisecond, cfg.Trim.Padding)
+				assert.Equal(t, -40.0, cfg.Segmentation.ThresholdDB)
+				assert.Equal(t, 750*time.Millisecond, cfg.Segmentation.MinSilence)
+				assert.Equal(t, 150*time.Millisecond, cfg.Segmentation.Padding)
+				assert.True(t, cfg.Analysis.Enabled)
+				assert.Equal(t, -40.0, cfg.Analysis.SpeechThresholdDB)
+				assert.Equal(t, 0.001, cfg.Analysis.MaxClippedRatio)
+				assert.Equal(t, -50.0, cfg.Analysis.MaxNoiseFloorDB)
+				assert.Equal(t, 15.0, cfg.Analysis.MinSNRDB)
+				assert.Equal(t, 0.1, cfg.Analysis.MinSpeechRatio)
+				assert.Equal(t, 1024, cfg.Spectrogram.FFTSize)
+				assert.Equal(t, 256, cfg.Spectrogram.Hop)
+				assert.Equal(t, "mel", cfg.Spectrogram.Scale)
+				assert.Equal(t, -100.0, cfg.Spectrogram.MinDB)
+				assert.Equal(t, "viridis", cfg.Spectrogram.ColorMap)
+				assert.Equal(t, 800, cfg.Spectrogram.Width)
+				assert.Equal(t, 256, cfg.Spectrogram.Height)
+				assert.Equal(t, 5*time.Millisecond, cfg.Pitch.Hop)
+				assert.Equal(t, 75.0, cfg.Pitch.MinF0)
+				assert.Equal(t, 600.0, cfg.Pitch.MaxF0)
+				assert.Equal(t, 0.15, cfg.Pitch.Threshold)
+				assert.Equal(t, "failed_first", cfg.Recording.NextPhraseStrategy)
+				assert.Equal(t, map[string]SpeakerAttributeConfig{
+					"gender": {Values: []string{"female", "male"}},
+					"accent": {MaxLength: 40},
+				}, cfg.SpeakerAttributes)
+				assert.Equal(t, map[string][]FilterConfig{
+					"clean": {
+						{Name: "highpass", Params: map[string]string{"f": "80"}},
+						{Name: "dynaudnorm"},
+					},
+				}, cfg.Profiles)
 			},
 		},
 		{
@@ -98,3 +190,39 @@ storage:
 	assert.Error(t, err)
 	assert.Nil(t, cfg)
 }
+
+func TestLoadConfig_ShareSecret(t *testing.T) {
+	tmpDir := t.TempDir()
+	secretPath := filepath.Join(tmpDir, "share_secret")
+	assert.NoError(t, os.WriteFile(secretPath, []byte("from-file\n"), 0600))
+
+	write := func(content string) string {
+		path := filepath.Join(tmpDir, "config.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		cfg, err := LoadConfig(write("server_address: \":8080\"\n"))
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.Share.Secret)
+	})
+
+	t.Run("from file", func(t *testing.T) {
+		cfg, err := LoadConfig(write("share:\n  secret_file: " + secretPath + "\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "from-file", cfg.Share.Secret)
+	})
+
+	t.Run("environment wins", func(t *testing.T) {
+		t.Setenv("SHARE_SECRET", "from-env")
+		cfg, err := LoadConfig(write("share:\n  secret: from-config\n  secret_file: " + secretPath + "\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "from-env", cfg.Share.Secret)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadConfig(write("share:\n  secret_file: " + filepath.Join(tmpDir, "missing") + "\n"))
+		assert.Error(t, err)
+	})
+}
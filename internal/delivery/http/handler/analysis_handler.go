@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// AnalysisHandler serves the quality analysis of converted audio.
+type AnalysisHandler struct {
+	analysisUseCase *usecase.AnalysisUseCase
+}
+
+// NewAnalysisHandler creates a new AnalysisHandler.
+func NewAnalysisHandler(analysisUseCase *usecase.AnalysisUseCase) *AnalysisHandler {
+	return &AnalysisHandler{
+		analysisUseCase: analysisUseCase,
+	}
+}
+
+// analysisResponse is the JSON representation of an audio analysis. Levels
+// are in dBFS.
+type analysisResponse struct {
+	AudioID      uint      `json:"audio_id"`
+	PeakDB       float64   `json:"peak_db"`
+	RMSDB        float64   `json:"rms_db"`
+	ClippedRatio float64   `json:"clipped_ratio"`
+	NoiseFloorDB float64   `json:"noise_floor_db"`
+	SNRDB        float64   `json:"snr_db"`
+	SpeechRatio  float64   `json:"speech_ratio"`
+	NeedsReview  bool      `json:"needs_review"`
+	Problems     []string  `json:"problems"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func newAnalysisResponse(a *entity.AudioAnalysis) analysisResponse {
+	problems := a.Problems
+	if problems == nil {
+		problems = []string{}
+	}
+	return analysisResponse{
+		AudioID:      a.AudioID,
+		PeakDB:       a.PeakDB,
+		RMSDB:        a.RMSDB,
+		ClippedRatio: a.ClippedRatio,
+		NoiseFloorDB: a.NoiseFloorDB,
+		SNRDB:        a.SNRDB,
+		SpeechRatio:  a.SpeechRatio,
+		NeedsReview:  len(problems) > 0,
+		Problems:     problems,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
+
+// Get returns the quality analysis of an audio.
+func (h *AnalysisHandler) Get(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	analysis, err := h.analysisUseCase.Get(r.Context(), uint(audioID))
+	if err != nil {
+		logger.Errorf("Failed to get audio analysis: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAnalysisResponse(analysis)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/quality"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalysisHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(*repoMocks.MockAudioAnalysisRepository, *repoMocks.MockAudioRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name: "flagged audio",
+			path: "/audio/1/analysis",
+			setupMocks: func(repo *repoMocks.MockAudioAnalysisRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1}, nil)
+				repo.On("GetByAudioID", mock.Anything, uint(1)).Return(&entity.AudioAnalysis{
+					AudioID:      1,
+					PeakDB:       0,
+					ClippedRatio: 0.02,
+					SpeechRatio:  0.6,
+					Problems:     []string{quality.ProblemClipping},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"audio_id":      float64(1),
+				"clipped_ratio": 0.02,
+				"needs_review":  true,
+				"problems":      []interface{}{"clipping"},
+			},
+		},
+		{
+			name: "not analyzed",
+			path: "/audio/1/analysis",
+			setupMocks: func(repo *repoMocks.MockAudioAnalysisRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1}, nil)
+				repo.On("GetByAudioID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid audio ID",
+			path:           "/audio/99999999999999999999/analysis",
+			setupMocks:     func(*repoMocks.MockAudioAnalysisRepository, *repoMocks.MockAudioRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioAnalysisRepository(t)
+			audioRepo := repoMocks.NewMockAudioRepository(t)
+			tt.setupMocks(repo, audioRepo)
+
+			h := handler.NewAnalysisHandler(usecase.NewAnalysisUseCase(repo, audioRepo, -40, quality.Thresholds{}))
+			router := mux.NewRouter()
+			router.HandleFunc("/audio/{audio_id:[0-9]+}/analysis", h.Get).Methods(http.MethodGet)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != nil {
+				var got map[string]interface{}
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				for key, value := range tt.expectedBody {
+					assert.Equal(t, value, got[key], key)
+				}
+			}
+		})
+	}
+}
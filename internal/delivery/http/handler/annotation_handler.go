@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// annotationExports maps export formats to their content type and file
+// extension.
+var annotationExports = map[usecase.AnnotationExportFormat]struct{ contentType, extension string }{
+	usecase.AnnotationExportTextGrid: {"text/plain; charset=utf-8", "TextGrid"},
+	usecase.AnnotationExportVTT:      {"text/vtt; charset=utf-8", "vtt"},
+	usecase.AnnotationExportJSON:     {"application/json", "json"},
+}
+
+// AnnotationHandler serves time-aligned annotations of recordings.
+type AnnotationHandler struct {
+	annotationUseCase *usecase.AnnotationUseCase
+}
+
+// NewAnnotationHandler creates a new AnnotationHandler.
+func NewAnnotationHandler(annotationUseCase *usecase.AnnotationUseCase) *AnnotationHandler {
+	return &AnnotationHandler{
+		annotationUseCase: annotationUseCase,
+	}
+}
+
+type CreateAnnotationRequest struct {
+	Tier       string                 `json:"tier"`
+	Label      string                 `json:"label"`
+	Start      float64                `json:"start"`
+	End        float64                `json:"end"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// UpdateAnnotationRequest holds the fields to change; omitted fields are kept.
+type UpdateAnnotationRequest struct {
+	Tier       *string                `json:"tier"`
+	Label      *string                `json:"label"`
+	Start      *float64               `json:"start"`
+	End        *float64               `json:"end"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type annotationResponse struct {
+	ID         uint                   `json:"id"`
+	AudioID    uint                   `json:"audio_id"`
+	Tier       string                 `json:"tier"`
+	Label      string                 `json:"label"`
+	Start      float64                `json:"start"`
+	End        float64                `json:"end"`
+	Attributes map[string]interface{} `json:"attributes"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+func newAnnotationResponse(annotation *entity.Annotation) annotationResponse {
+	attributes := annotation.Attributes
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	return annotationResponse{
+		ID:         annotation.ID,
+		AudioID:    annotation.AudioID,
+		Tier:       annotation.Tier,
+		Label:      annotation.Label,
+		Start:      annotation.Start,
+		End:        annotation.End,
+		Attributes: attributes,
+		CreatedAt:  annotation.CreatedAt,
+		UpdatedAt:  annotation.UpdatedAt,
+	}
+}
+
+// annotationPath parses the audio and annotation IDs in the path, responding
+// 400 Bad Request if either is invalid.
+func annotationPath(w http.ResponseWriter, r *http.Request) (audioID, annotationID uint, ok bool) {
+	vars := mux.Vars(r)
+	audio, err := strconv.ParseUint(vars["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	annotation, err := strconv.ParseUint(vars["annotation_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid annotation ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return uint(audio), uint(annotation), true
+}
+
+// Create adds an annotation to a completed audio.
+func (h *AnnotationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	var req CreateAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	annotation, err := h.annotationUseCase.Create(r.Context(), uint(audioID), usecase.AnnotationInput{
+		Tier:       req.Tier,
+		Label:      req.Label,
+		Start:      req.Start,
+		End:        req.End,
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		logger.Errorf("Failed to create annotation: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/audio/%d/annotations/%d", audioID, annotation.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newAnnotationResponse(annotation)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// List returns the annotations of an audio, only those of ?tier if given.
+func (h *AnnotationHandler) List(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	annotations, err := h.annotationUseCase.List(r.Context(), uint(audioID), r.URL.Query().Get("tier"))
+	if err != nil {
+		logger.Errorf("Failed to list annotations: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := make([]annotationResponse, 0, len(annotations))
+	for _, annotation := range annotations {
+		response = append(response, newAnnotationResponse(annotation))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Get returns an annotation of an audio.
+func (h *AnnotationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	audioID, annotationID, ok := annotationPath(w, r)
+	if !ok {
+		return
+	}
+
+	annotation, err := h.annotationUseCase.Get(r.Context(), audioID, annotationID)
+	if err != nil {
+		logger.Errorf("Failed to get annotation: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAnnotationResponse(annotation)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Update changes the fields of an annotation given in the request body.
+func (h *AnnotationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	audioID, annotationID, ok := annotationPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	annotation, err := h.annotationUseCase.Update(r.Context(), audioID, annotationID, usecase.AnnotationUpdate{
+		Tier:       req.Tier,
+		Label:      req.Label,
+		Start:      req.Start,
+		End:        req.End,
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		logger.Errorf("Failed to update annotation: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAnnotationResponse(annotation)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Delete removes an annotation of an audio.
+func (h *AnnotationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	audioID, annotationID, ok := annotationPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.annotationUseCase.Delete(r.Context(), audioID, annotationID); err != nil {
+		logger.Errorf("Failed to delete annotation: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Export downloads the annotations of an audio, only those of ?tier if
+// given, as a Praat TextGrid, WebVTT or JSON file selected with ?format.
+func (h *AnnotationHandler) Export(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	format, err := usecase.ParseAnnotationExportFormat(query.Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	content, err := h.annotationUseCase.Export(r.Context(), uint(audioID), query.Get("tier"), format)
+	if err != nil {
+		logger.Errorf("Failed to export annotations: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	export := annotationExports[format]
+	w.Header().Set("Content-Type", export.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"annotations-%d.%s\"", audioID, export.extension))
+	if _, err := w.Write(content); err != nil {
+		logger.Errorf("Failed to write annotations: %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/delivery/http/router"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type annotationMocks struct {
+	annotation *repoMocks.MockAnnotationRepository
+	audio      *repoMocks.MockAudioRepository
+}
+
+func newAnnotationTestRouter(t *testing.T) (*mux.Router, annotationMocks) {
+	m := annotationMocks{
+		annotation: repoMocks.NewMockAnnotationRepository(t),
+		audio:      repoMocks.NewMockAudioRepository(t),
+	}
+	h := handler.NewAnnotationHandler(usecase.NewAnnotationUseCase(m.annotation, m.audio))
+	return router.SetupRoutes(router.Handlers{Annotation: h}), m
+}
+
+var annotatedAudio = &entity.Audio{ID: 5, Status: entity.AudioStatusCompleted, Duration: 2}
+
+func TestAnnotationHandler_Create(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		router, m := newAnnotationTestRouter(t)
+		m.audio.On("GetByID", mock.Anything, uint(5)).Return(annotatedAudio, nil)
+		m.annotation.On("ListByAudioID", mock.Anything, uint(5)).Return([]*entity.Annotation{}, nil)
+		m.annotation.On("Create", mock.Anything, mock.AnythingOfType("*entity.Annotation")).
+			Return(&entity.Annotation{ID: 7, AudioID: 5, Tier: "words", Label: "hello", Start: 0.5, End: 1,
+				Attributes: map[string]interface{}{"confidence": 0.9}}, nil)
+
+		rr := httptest.NewRecorder()
+		body := `{"tier": "words", "label": "hello", "start": 0.5, "end": 1, "attributes": {"confidence": 0.9}}`
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/audio/5/annotations", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "/audio/5/annotations/7", rr.Header().Get("Location"))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, 7.0, response["id"])
+		assert.Equal(t, "words", response["tier"])
+		assert.Equal(t, map[string]interface{}{"confidence": 0.9}, response["attributes"])
+	})
+
+	t.Run("beyond the audio", func(t *testing.T) {
+		router, m := newAnnotationTestRouter(t)
+		m.audio.On("GetByID", mock.Anything, uint(5)).Return(annotatedAudio, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/audio/5/annotations", strings.NewReader(`{"label": "x", "start": 1, "end": 3}`)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		router, m := newAnnotationTestRouter(t)
+		m.audio.On("GetByID", mock.Anything, uint(5)).Return(annotatedAudio, nil)
+		m.annotation.On("ListByAudioID", mock.Anything, uint(5)).
+			Return([]*entity.Annotation{{ID: 1, AudioID: 5, Tier: usecase.DefaultAnnotationTier, Start: 0, End: 1}}, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/audio/5/annotations", strings.NewReader(`{"label": "x", "start": 0.5, "end": 1.5}`)))
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
+
+func TestAnnotationHandler_List(t *testing.T) {
+	router, m := newAnnotationTestRouter(t)
+	m.audio.On("GetByID", mock.Anything, uint(5)).Return(annotatedAudio, nil)
+	m.annotation.On("ListByAudioID", mock.Anything, uint(5)).Return([]*entity.Annotation{
+		{ID: 1, AudioID: 5, Tier: "noise", Label: "cough", Start: 0, End: 0.5},
+		{ID: 2, AudioID: 5, Tier: "words", Label: "hello", Start: 0.5, End: 1},
+	}, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audio/5/annotations?tier=words", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "hello", response[0]["label"])
+	assert.Equal(t, map[string]interface{}{}, response[0]["attributes"])
+}
+
+func TestAnnotationHandler_GetUpdateDelete(t *testing.T) {
+	stored := func() *entity.Annotation {
+		return &entity.Annotation{ID: 2, AudioID: 5, Tier: "words", Label: "hello", Start: 0.5, End: 1}
+	}
+
+	t.Run("get", func(t *testing.T) {
+		router, m := newAnnotationTestRouter(t)
+		m.annotation.On("GetByID", mock.Anything, uint(2)).Return(stored(), nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audio/5/annotations/2", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audio/6/annotations/2", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		router, m := newAnnotationTestRouter(t)
+		m.annotation.On("GetByID", mock.Anything, uint(2)).Return(stored(), nil)
+		m.audio.On("GetByID", mock.Anything, uint(5)).Return(annotatedAudio, nil)
+		m.annotation.On("ListByAudioID", mock.Anything, uint(5)).Return([]*entity.Annotation{stored()}, nil)
+		m.annotation.On("Update", mock.Anything, mock.MatchedBy(func(a *entity.Annotation) bool {
+			return a.Label == "hullo" && a.Start == 0.5 && a.End == 1.5
+		})).Return(nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/audio/5/annotations/2", strings.NewReader(`{"label": "hullo", "end": 1.5}`)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "hullo", response["label"])
+		assert.Equal(t, 1.5, response["end"])
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		router, m := newAnnotationTestRouter(t)
+		m.annotation.On("GetByID", mock.Anything, uint(2)).Return(stored(), nil)
+		m.annotation.On("Delete", mock.Anything, uint(2)).Return(nil)
+		m.annotation.On("GetByID", mock.Anything, uint(3)).Return(nil, fmt.Errorf("failed to get annotation 3: %w", repository.ErrNotFound))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/audio/5/annotations/2", nil))
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/audio/5/annotations/3", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestAnnotationHandler_Export(t *testing.T) {
+	tests := []struct {
+		format      string
+		contentType string
+		filename    string
+		prefix      string
+	}{
+		{"textgrid", "text/plain; charset=utf-8", "annotations-5.TextGrid", "File type = \"ooTextFile\"\n"},
+		{"vtt", "text/vtt; charset=utf-8", "annotations-5.vtt", "WEBVTT\n\n2\n00:00:00.500 --> 00:00:01.000\nhello\n"},
+		{"json", "application/json", "annotations-5.json", `{"audio_id":5,"duration":2,"annotations":[`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			router, m := newAnnotationTestRouter(t)
+			m.audio.On("GetByID", mock.Anything, uint(5)).Return(annotatedAudio, nil)
+			m.annotation.On("ListByAudioID", mock.Anything, uint(5)).
+				Return([]*entity.Annotation{{ID: 2, AudioID: 5, Tier: "words", Label: "hello", Start: 0.5, End: 1}}, nil)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audio/5/annotations/export?format="+tt.format, nil))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.contentType, rr.Header().Get("Content-Type"))
+			assert.Contains(t, rr.Header().Get("Content-Disposition"), tt.filename)
+			assert.True(t, strings.HasPrefix(rr.Body.String(), tt.prefix), rr.Body.String())
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		router, _ := newAnnotationTestRouter(t)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audio/5/annotations/export?format=srt", nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
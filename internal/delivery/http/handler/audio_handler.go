@@ -2,26 +2,117 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/internal/usecase"
 	"github.com/ardfard/sb-test/pkg/logger"
 	"github.com/gorilla/mux"
 )
 
+// multipartOverhead is the room allowed on top of the maximum file size for
+// multipart boundaries, part headers and other form fields.
+const multipartOverhead = 64 << 10
+
 // AudioHandler is a handler for audio-related operations.
 type AudioHandler struct {
 	uploadUseCase   *usecase.UploadAudioUseCase
 	downloadUseCase *usecase.DownloadAudioUseCase
+	getUseCase      *usecase.GetAudioUseCase
 }
 
 // NewAudioHandler creates a new AudioHandler with the given use case.
-func NewAudioHandler(uploadUseCase *usecase.UploadAudioUseCase, downloadUseCase *usecase.DownloadAudioUseCase) *AudioHandler {
+func NewAudioHandler(uploadUseCase *usecase.UploadAudioUseCase, downloadUseCase *usecase.DownloadAudioUseCase, getUseCase *usecase.GetAudioUseCase) *AudioHandler {
 	return &AudioHandler{
 		uploadUseCase:   uploadUseCase,
 		downloadUseCase: downloadUseCase,
+		getUseCase:      getUseCase,
+	}
+}
+
+// audioResponse is the JSON representation of an audio record.
+type audioResponse struct {
+	ID           uint               `json:"id"`
+	UserID       uint               `json:"user_id"`
+	PhraseID     uint               `json:"phrase_id"`
+	SessionID    uint               `json:"session_id,omitempty"`
+	OriginalName string             `json:"original_name"`
+	Status       entity.AudioStatus `json:"status"`
+	Error        string             `json:"error,omitempty"`
+	Format       string             `json:"format"`
+	Duration     float64            `json:"duration"`
+	SampleRate   int                `json:"sample_rate"`
+	Channels     int                `json:"channels"`
+	Codec        string             `json:"codec"`
+	BitRate      int64              `json:"bit_rate"`
+	FileSize     int64              `json:"file_size"`
+	Loudness     *loudnessResponse  `json:"loudness,omitempty"`
+	Trim         *trimResponse      `json:"trim,omitempty"`
+	NeedsReview  bool               `json:"needs_review"`
+	Similarity   *float64           `json:"similarity,omitempty"` // Score against the phrase reference, 0 to 1
+	Stale        bool               `json:"stale"`                // The phrase text changed after recording
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// loudnessResponse is the measured input loudness of an audio.
+type loudnessResponse struct {
+	Integrated float64 `json:"integrated"` // LUFS
+	TruePeak   float64 `json:"true_peak"`  // dBTP
+	Range      float64 `json:"range"`      // LU
+	Threshold  float64 `json:"threshold"`  // LUFS
+	Normalized bool    `json:"normalized"` // Whether the normalized variant can be downloaded
+}
+
+// trimResponse is the audible part of an audio, in seconds.
+type trimResponse struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+func newAudioResponse(audio *entity.Audio) audioResponse {
+	var loudness *loudnessResponse
+	if audio.LoudnessIntegrated != nil && audio.LoudnessTruePeak != nil &&
+		audio.LoudnessRange != nil && audio.LoudnessThreshold != nil {
+		loudness = &loudnessResponse{
+			Integrated: *audio.LoudnessIntegrated,
+			TruePeak:   *audio.LoudnessTruePeak,
+			Range:      *audio.LoudnessRange,
+			Threshold:  *audio.LoudnessThreshold,
+			Normalized: audio.NormalizedPath != "",
+		}
+	}
+	var trim *trimResponse
+	if audio.TrimStart != nil && audio.TrimEnd != nil {
+		trim = &trimResponse{Start: *audio.TrimStart, End: *audio.TrimEnd}
+	}
+	return audioResponse{
+		ID:           audio.ID,
+		UserID:       audio.UserID,
+		PhraseID:     audio.PhraseID,
+		SessionID:    audio.SessionID,
+		OriginalName: audio.OriginalName,
+		Status:       audio.Status,
+		Error:        audio.Error,
+		Format:       audio.CurrentFormat,
+		Duration:     audio.Duration,
+		SampleRate:   audio.SampleRate,
+		Channels:     audio.Channels,
+		Codec:        audio.Codec,
+		BitRate:      audio.BitRate,
+		FileSize:     audio.FileSize,
+		Loudness:     loudness,
+		Trim:         trim,
+		NeedsReview:  audio.NeedsReview,
+		Similarity:   audio.SimilarityScore,
+		Stale:        audio.Stale,
+		CreatedAt:    audio.CreatedAt,
+		UpdatedAt:    audio.UpdatedAt,
 	}
 }
 
@@ -33,10 +124,8 @@ func (h *AudioHandler) UploadAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	file, header, err := r.FormFile("audio_file")
-	if err != nil {
-		logger.Errorf("Failed to get file from request: %v", err)
-		http.Error(w, "Failed to get file from request", http.StatusBadRequest)
+	file, header, ok := readAudioFile(w, r, h.uploadUseCase.Policy())
+	if !ok {
 		return
 	}
 	defer file.Close()
@@ -58,22 +147,40 @@ func (h *AudioHandler) UploadAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	audio, err := h.uploadUseCase.Upload(r.Context(), header.Filename, file, uint(userIDUint), uint(phraseIDUint))
+	sessionID, err := parseSessionID(r.FormValue("session_id"))
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	audio, err := h.uploadUseCase.Upload(r.Context(), header.Filename, file, uint(userIDUint), uint(phraseIDUint), sessionID)
 	if err != nil {
 		logger.Errorf("Failed to upload audio: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeUploadError(w, err, statusFromError(err))
+		return
+	}
+
+	writeUploadedAudio(w, audio)
+}
+
+// GetAudioInfo returns an audio record and its metadata.
+func (h *AudioHandler) GetAudioInfo(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	audio, err := h.getUseCase.Get(r.Context(), uint(audioID))
+	if err != nil {
+		logger.Errorf("Failed to get audio: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
 		return
 	}
 
-	// Return JSON response with audio ID and status
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"id":     audio.ID,
-		"status": audio.Status,
-	}); err != nil {
+	if err := json.NewEncoder(w).Encode(newAudioResponse(audio)); err != nil {
 		logger.Errorf("Failed to encode response: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
 	}
 }
 
@@ -99,17 +206,74 @@ func (h *AudioHandler) GetAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	audio, err := h.downloadUseCase.Download(r.Context(), uint(userIDUint), uint(phraseIDUint), format)
+	opts, ok := parseDownloadOptions(w, r)
+	if !ok {
+		return
+	}
+	audio, err := h.downloadUseCase.Download(r.Context(), uint(userIDUint), uint(phraseIDUint), format, opts)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), statusFromError(err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "audio/mpeg")
-	w.WriteHeader(http.StatusOK)
-	_, err = io.Copy(w, audio)
+	writeAudio(w, audio, format)
+}
+
+// readAudioFile returns the audio_file part of a multipart upload, reading no
+// more of the body than policy allows.
+func readAudioFile(w http.ResponseWriter, r *http.Request, policy usecase.UploadPolicy) (multipart.File, *multipart.FileHeader, bool) {
+	if policy.MaxSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, policy.MaxSize+multipartOverhead)
+	}
+
+	file, header, err := r.FormFile("audio_file")
 	if err != nil {
+		logger.Errorf("Failed to get file from request: %v", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "Request body too large", usecase.RejectFileTooLarge)
+			return nil, nil, false
+		}
+		http.Error(w, "Failed to get file from request", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return file, header, true
+}
+
+// writeUploadedAudio responds with the audio record of a stored take and its
+// metadata.
+func writeUploadedAudio(w http.ResponseWriter, audio *entity.Audio) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAudioResponse(audio)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	}
+}
+
+// parseDownloadOptions reads the variant, profile and trim query parameters.
+func parseDownloadOptions(w http.ResponseWriter, r *http.Request) (usecase.DownloadOptions, bool) {
+	opts := usecase.DownloadOptions{
+		Variant: r.URL.Query().Get("variant"),
+		Profile: r.URL.Query().Get("profile"),
+	}
+	if value := r.URL.Query().Get("trim"); value != "" {
+		trim, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid trim", http.StatusBadRequest)
+			return usecase.DownloadOptions{}, false
+		}
+		opts.Trim = &trim
+	}
+	return opts, true
+}
+
+// writeAudio streams a downloaded take in format and closes it.
+func writeAudio(w http.ResponseWriter, audio io.ReadCloser, format string) {
+	defer audio.Close()
+
+	w.Header().Set("Content-Type", contentTypeForFormat(format))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, audio); err != nil {
+		logger.Errorf("Failed to write audio: %v", err)
 	}
 }
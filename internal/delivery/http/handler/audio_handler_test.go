@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"mime/multipart"
@@ -9,15 +10,18 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ardfard/sb-test/internal/delivery/http/handler"
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
 	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
 	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
 	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
 	"github.com/ardfard/sb-test/internal/usecase"
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -27,6 +31,7 @@ func TestAudioHandler_Upload(t *testing.T) {
 		method         string
 		userID         string
 		phraseID       string
+		sessionID      string
 		fileContent    string
 		skipFile       bool
 		expectedStatus int
@@ -37,11 +42,16 @@ func TestAudioHandler_Upload(t *testing.T) {
 			method:         "POST",
 			userID:         "1",
 			phraseID:       "1",
-			fileContent:    "test audio content",
+			fileContent:    "ID3\x04\x00\x00test audio content",
 			expectedStatus: http.StatusOK,
 			expectedBody: map[string]interface{}{
-				"id":     float64(1),
-				"status": "pending",
+				"id":          float64(1),
+				"status":      "pending",
+				"format":      "mp3",
+				"duration":    2.5,
+				"sample_rate": float64(44100),
+				"channels":    float64(2),
+				"codec":       "mp3",
 			},
 		},
 		{
@@ -68,6 +78,15 @@ func TestAudioHandler_Upload(t *testing.T) {
 			fileContent:    "test audio content",
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "invalid session ID",
+			method:         "POST",
+			userID:         "1",
+			phraseID:       "1",
+			sessionID:      "invalid",
+			fileContent:    "test audio content",
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "missing file",
 			method:         "POST",
@@ -76,6 +95,14 @@ func TestAudioHandler_Upload(t *testing.T) {
 			skipFile:       true,
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "phrase of another user",
+			method:         "POST",
+			userID:         "1",
+			phraseID:       "2",
+			fileContent:    "ID3\x04\x00\x00test audio content",
+			expectedStatus: http.StatusForbidden,
+		},
 	}
 
 	for _, tt := range tests {
@@ -100,31 +127,47 @@ func TestAudioHandler_Upload(t *testing.T) {
 					UserID: 1,
 					Phrase: "Test Phrase",
 				}, nil)
+				mockAudioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(nil, repository.ErrNotFound)
+
+				mockConverter.On("Probe", mock.Anything, mock.AnythingOfType("string")).Return(&entity.AudioMetadata{
+					Format:     "mp3",
+					Duration:   2.5,
+					SampleRate: 44100,
+					Channels:   2,
+					Codec:      "mp3",
+					BitRate:    128000,
+					FileSize:   24,
+				}, nil)
 
-				mockStorage.On("Upload", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+				mockStorage.On("Upload", mock.Anything, "audio/original/1-1.mp3", mock.Anything).Return(nil)
 
 				mockAudioRepo.On("Store", mock.Anything, mock.MatchedBy(func(audio *entity.Audio) bool {
-					return audio.UserID == 1 && audio.PhraseID == 1 && audio.Status == "pending"
-				})).Return(&entity.Audio{
-					ID:            1,
-					UserID:        1,
-					PhraseID:      1,
-					StoragePath:   "test-path",
-					Status:        "pending",
-					OriginalName:  "test.mp3",
-					CurrentFormat: ".mp3",
-				}, nil)
+					return audio.UserID == 1 && audio.PhraseID == 1 && audio.Status == "pending" && audio.SampleRate == 44100
+				})).Return(func(_ context.Context, audio *entity.Audio) (*entity.Audio, error) {
+					stored := *audio
+					stored.ID = 1
+					return &stored, nil
+				})
 
 				mockQueue.On("Enqueue", mock.Anything, uint(1)).Return(nil)
 			}
+			if tt.expectedStatus == http.StatusForbidden {
+				mockUserRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				mockPhraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+				mockPhraseRepo.On("IsSharedWith", mock.Anything, uint(2), uint(1)).Return(false, nil)
+			}
 
 			// Create use cases and handler
 			uploadUseCase := usecase.NewUploadAudioUseCase(
 				mockAudioRepo,
 				mockStorage,
+				mockConverter,
 				mockQueue,
 				mockUserRepo,
 				mockPhraseRepo,
+				repoMocks.NewMockRecordingSessionRepository(t),
+				repoMocks.NewMockAudioReviewRepository(t),
+				usecase.UploadPolicy{},
 			)
 
 			downloadUseCase := usecase.NewDownloadAudioUseCase(
@@ -133,9 +176,11 @@ func TestAudioHandler_Upload(t *testing.T) {
 				mockConverter,
 				mockUserRepo,
 				mockPhraseRepo,
+				nil,
+				false,
 			)
 
-			handler := handler.NewAudioHandler(uploadUseCase, downloadUseCase)
+			handler := handler.NewAudioHandler(uploadUseCase, downloadUseCase, usecase.NewGetAudioUseCase(mockAudioRepo))
 
 			var req *http.Request
 			if !tt.skipFile {
@@ -150,6 +195,11 @@ func TestAudioHandler_Upload(t *testing.T) {
 				if err != nil {
 					t.Fatal(err)
 				}
+				if tt.sessionID != "" {
+					if err := writer.WriteField("session_id", tt.sessionID); err != nil {
+						t.Fatal(err)
+					}
+				}
 				writer.Close()
 
 				req = httptest.NewRequest(tt.method, "/users/"+tt.userID+"/phrases/"+tt.phraseID+"/audio", body)
@@ -175,8 +225,10 @@ func TestAudioHandler_Upload(t *testing.T) {
 					t.Fatalf("Failed to decode response body: %v", err)
 				}
 
-				if got["id"] != tt.expectedBody["id"] || got["status"] != tt.expectedBody["status"] {
-					t.Errorf("handler returned unexpected body: got %v want %v", got, tt.expectedBody)
+				for key, want := range tt.expectedBody {
+					if got[key] != want {
+						t.Errorf("handler returned unexpected %s: got %v want %v", key, got[key], want)
+					}
 				}
 			}
 
@@ -189,6 +241,117 @@ func TestAudioHandler_Upload(t *testing.T) {
 	}
 }
 
+func TestAudioHandler_UploadRejected(t *testing.T) {
+	tests := []struct {
+		name           string
+		fileContent    string
+		policy         usecase.UploadPolicy
+		probe          *entity.AudioMetadata
+		expectedStatus int
+		expectedReason string
+	}{
+		{
+			name:           "not an audio file",
+			fileContent:    "%PDF-1.7 renamed document",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedReason: usecase.RejectUnsupportedFormat,
+		},
+		{
+			name:           "format not allowed",
+			fileContent:    "ID3\x04\x00\x00test audio content",
+			policy:         usecase.UploadPolicy{AllowedFormats: []string{"wav"}},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedReason: usecase.RejectFormatNotAllowed,
+		},
+		{
+			name:           "body larger than limit",
+			fileContent:    "ID3\x04\x00\x00" + strings.Repeat("x", 128<<10),
+			policy:         usecase.UploadPolicy{MaxSize: 1024},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedReason: usecase.RejectFileTooLarge,
+		},
+		{
+			name:           "file larger than limit",
+			fileContent:    "ID3\x04\x00\x00" + strings.Repeat("x", 2048),
+			policy:         usecase.UploadPolicy{MaxSize: 1024},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedReason: usecase.RejectFileTooLarge,
+		},
+		{
+			name:           "too short",
+			fileContent:    "ID3\x04\x00\x00test audio content",
+			policy:         usecase.UploadPolicy{MinDuration: time.Second},
+			probe:          &entity.AudioMetadata{Format: "mp3", Duration: 0.1, SampleRate: 44100},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedReason: usecase.RejectDurationTooShort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAudioRepo := repoMocks.NewMockAudioRepository(t)
+			mockUserRepo := repoMocks.NewMockUserRepository(t)
+			mockPhraseRepo := repoMocks.NewMockPhraseRepository(t)
+			mockConverter := converterMocks.NewMockAudioConverter(t)
+
+			mockUserRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil).Maybe()
+			mockPhraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil).Maybe()
+			mockAudioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(nil, repository.ErrNotFound).Maybe()
+			if tt.probe != nil {
+				mockConverter.On("Probe", mock.Anything, mock.Anything).Return(tt.probe, nil)
+			}
+
+			uploadUseCase := usecase.NewUploadAudioUseCase(
+				mockAudioRepo,
+				storageMocks.NewMockStorage(t),
+				mockConverter,
+				queueMocks.NewMockTaskQueue(t),
+				mockUserRepo,
+				mockPhraseRepo,
+				repoMocks.NewMockRecordingSessionRepository(t),
+				repoMocks.NewMockAudioReviewRepository(t),
+				tt.policy,
+			)
+			h := handler.NewAudioHandler(uploadUseCase, nil, nil)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("audio_file", "test.mp3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.Copy(part, strings.NewReader(tt.fileContent)); err != nil {
+				t.Fatal(err)
+			}
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/users/1/phrases/1/audio", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			router := mux.NewRouter()
+			router.HandleFunc("/users/{user_id}/phrases/{phrase_id}/audio", h.UploadAudio).Methods("POST")
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v (%s)", rr.Code, tt.expectedStatus, rr.Body.String())
+			}
+
+			var got map[string]interface{}
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			if got["reason"] != tt.expectedReason {
+				t.Errorf("handler returned unexpected reason: got %v want %v", got["reason"], tt.expectedReason)
+			}
+			if got["error"] == "" {
+				t.Error("handler returned empty error message")
+			}
+		})
+	}
+}
+
 func TestAudioHandler_Download(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -196,7 +359,9 @@ func TestAudioHandler_Download(t *testing.T) {
 		userID         string
 		phraseID       string
 		format         string
+		query          string
 		expectedStatus int
+		expectedType   string
 	}{
 		{
 			name:           "successful download",
@@ -205,6 +370,16 @@ func TestAudioHandler_Download(t *testing.T) {
 			phraseID:       "1",
 			format:         "mp3",
 			expectedStatus: http.StatusOK,
+			expectedType:   "audio/mpeg",
+		},
+		{
+			name:           "wav download",
+			method:         "GET",
+			userID:         "1",
+			phraseID:       "1",
+			format:         "wav",
+			expectedStatus: http.StatusOK,
+			expectedType:   "audio/wav",
 		},
 		{
 			name:           "method not allowed",
@@ -214,6 +389,24 @@ func TestAudioHandler_Download(t *testing.T) {
 			format:         "mp3",
 			expectedStatus: http.StatusMethodNotAllowed,
 		},
+		{
+			name:           "normalized variant not available",
+			method:         "GET",
+			userID:         "1",
+			phraseID:       "1",
+			format:         "wav",
+			query:          "?variant=normalized",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown variant",
+			method:         "GET",
+			userID:         "1",
+			phraseID:       "1",
+			format:         "wav",
+			query:          "?variant=louder",
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "invalid user ID",
 			method:         "GET",
@@ -241,8 +434,8 @@ func TestAudioHandler_Download(t *testing.T) {
 			mockConverter := converterMocks.NewMockAudioConverter(t)
 			mockQueue := queueMocks.NewMockTaskQueue(t)
 
-			// Set up mock expectations only for successful case
-			if tt.expectedStatus == http.StatusOK {
+			// Set up mock expectations only for requests that reach the use case
+			if tt.expectedStatus == http.StatusOK || tt.query != "" {
 				mockUserRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{
 					ID:   1,
 					Name: "Test User",
@@ -261,19 +454,28 @@ func TestAudioHandler_Download(t *testing.T) {
 					StoragePath:   "test-path",
 					Status:        "processed",
 					OriginalName:  "test.mp3",
-					CurrentFormat: ".mp3",
+					CurrentFormat: "wav",
 				}, nil)
 
-				mockStorage.On("Download", mock.Anything, "test-path").Return(io.NopCloser(strings.NewReader("test audio content")), nil)
-				mockConverter.On("ConvertFromReader", mock.Anything, mock.Anything, ".mp3", "mp3").Return(io.NopCloser(strings.NewReader("converted content")), nil)
+			}
+			stored := &closeRecorder{Reader: strings.NewReader("test audio content")}
+			if tt.expectedStatus == http.StatusOK {
+				mockStorage.On("Download", mock.Anything, "test-path").Return(stored, nil)
+			}
+			if tt.expectedStatus == http.StatusOK && tt.format == "mp3" {
+				mockConverter.On("ConvertFromReader", mock.Anything, mock.Anything, "wav", "mp3").Return(io.NopCloser(strings.NewReader("converted content")), nil)
 			}
 
 			uploadUseCase := usecase.NewUploadAudioUseCase(
 				mockAudioRepo,
 				mockStorage,
+				mockConverter,
 				mockQueue,
 				mockUserRepo,
 				mockPhraseRepo,
+				repoMocks.NewMockRecordingSessionRepository(t),
+				repoMocks.NewMockAudioReviewRepository(t),
+				usecase.UploadPolicy{},
 			)
 
 			downloadUseCase := usecase.NewDownloadAudioUseCase(
@@ -282,11 +484,13 @@ func TestAudioHandler_Download(t *testing.T) {
 				mockConverter,
 				mockUserRepo,
 				mockPhraseRepo,
+				nil,
+				false,
 			)
 
-			handler := handler.NewAudioHandler(uploadUseCase, downloadUseCase)
+			handler := handler.NewAudioHandler(uploadUseCase, downloadUseCase, usecase.NewGetAudioUseCase(mockAudioRepo))
 
-			req := httptest.NewRequest(tt.method, "/users/"+tt.userID+"/phrases/"+tt.phraseID+"/audio/"+tt.format, nil)
+			req := httptest.NewRequest(tt.method, "/users/"+tt.userID+"/phrases/"+tt.phraseID+"/audio/"+tt.format+tt.query, nil)
 
 			router := mux.NewRouter()
 			router.HandleFunc("/users/{user_id}/phrases/{phrase_id}/audio/{format}", handler.GetAudio).Methods("GET")
@@ -301,6 +505,12 @@ func TestAudioHandler_Download(t *testing.T) {
 			if tt.expectedStatus == http.StatusOK && rr.Body.Len() == 0 {
 				t.Error("Response body is empty")
 			}
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedType, rr.Header().Get("Content-Type"))
+			}
+			if tt.expectedStatus == http.StatusOK && tt.format == "wav" {
+				assert.True(t, stored.closed, "downloaded audio was not closed")
+			}
 
 			mockUserRepo.AssertExpectations(t)
 			mockPhraseRepo.AssertExpectations(t)
@@ -310,3 +520,14 @@ func TestAudioHandler_Download(t *testing.T) {
 		})
 	}
 }
+
+// closeRecorder is a download that records whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
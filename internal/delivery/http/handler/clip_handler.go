@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// ClipHandler serves segments of converted audio.
+type ClipHandler struct {
+	clipUseCase *usecase.ClipAudioUseCase
+}
+
+// NewClipHandler creates a new ClipHandler.
+func NewClipHandler(clipUseCase *usecase.ClipAudioUseCase) *ClipHandler {
+	return &ClipHandler{
+		clipUseCase: clipUseCase,
+	}
+}
+
+// Get returns the range between the start and end query parameters, in
+// seconds, in the requested format (wav by default).
+func (h *ClipHandler) Get(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	start, err := strconv.ParseFloat(query.Get("start"), 64)
+	if err != nil {
+		http.Error(w, "Invalid start", http.StatusBadRequest)
+		return
+	}
+	end, err := strconv.ParseFloat(query.Get("end"), 64)
+	if err != nil {
+		http.Error(w, "Invalid end", http.StatusBadRequest)
+		return
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = "wav"
+	}
+
+	clip, err := h.clipUseCase.Clip(r.Context(), uint(audioID), start, end, format)
+	if err != nil {
+		logger.Errorf("Failed to clip audio: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	defer clip.Close()
+
+	w.Header().Set("Content-Type", contentTypeForFormat(format))
+	if _, err := io.Copy(w, clip); err != nil {
+		logger.Errorf("Failed to write clip: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClipHandler_Get(t *testing.T) {
+	var source bytes.Buffer
+	require.NoError(t, wav.Encode(&source, 4, 1, []float64{0, 0.25, 0.5, 0.75}))
+	completed := &entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/1.wav", Duration: 1}
+
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expectedStatus int
+		expectedFrames int
+	}{
+		{
+			name: "wav clip",
+			path: "/audio/1/clip?start=0.25&end=0.75",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedFrames: 2,
+		},
+		{
+			name:           "missing start",
+			path:           "/audio/1/clip?end=0.75",
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported format",
+			path:           "/audio/1/clip?start=0&end=0.5&format=exe",
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "end past duration",
+			path: "/audio/1/clip?start=0.5&end=2",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			h := handler.NewClipHandler(usecase.NewClipAudioUseCase(repo, s, converterMocks.NewMockAudioConverter(t)))
+			router := mux.NewRouter()
+			router.HandleFunc("/audio/{audio_id:[0-9]+}/clip", h.Get).Methods(http.MethodGet)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "audio/wav", rr.Header().Get("Content-Type"))
+				clip, err := wav.Decode(rr.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedFrames, clip.Frames())
+			}
+		})
+	}
+}
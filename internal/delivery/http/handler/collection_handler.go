@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// CollectionHandler manages collections of phrases and the recordings of
+// their phrases by the users they are assigned to.
+type CollectionHandler struct {
+	collectionUseCase *usecase.CollectionUseCase
+}
+
+// NewCollectionHandler creates a new CollectionHandler.
+func NewCollectionHandler(collectionUseCase *usecase.CollectionUseCase) *CollectionHandler {
+	return &CollectionHandler{
+		collectionUseCase: collectionUseCase,
+	}
+}
+
+// CreateCollectionRequest holds the texts of the phrases in order.
+type CreateCollectionRequest struct {
+	Name     string   `json:"name"`
+	Language string   `json:"language"`
+	Tags     []string `json:"tags"`
+	Phrases  []string `json:"phrases"`
+}
+
+type collectionResponse struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	Name      string    `json:"name"`
+	Language  string    `json:"language,omitempty"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newCollectionResponse(c *entity.Collection) collectionResponse {
+	tags := c.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return collectionResponse{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		Name:      c.Name,
+		Language:  c.Language,
+		Tags:      tags,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+type collectionPhraseResponse struct {
+	Position int    `json:"position"`
+	PhraseID uint   `json:"phrase_id"`
+	Text     string `json:"text"`
+	Revision int    `json:"revision"`
+}
+
+type collectionDetailsResponse struct {
+	collectionResponse
+	Phrases   []collectionPhraseResponse `json:"phrases"`
+	Assignees []uint                     `json:"assignees"`
+}
+
+type collectionListResponse struct {
+	Owned    []collectionResponse `json:"owned"`
+	Assigned []collectionResponse `json:"assigned"`
+}
+
+// Create stores a collection owned by the user in the path and responds with
+// 201 Created.
+func (h *CollectionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req CreateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := h.collectionUseCase.Create(r.Context(), uint(userID), usecase.CollectionRequest{
+		Name:     req.Name,
+		Language: req.Language,
+		Tags:     req.Tags,
+		Phrases:  req.Phrases,
+	})
+	if err != nil {
+		logger.Errorf("Failed to create collection: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/collections/%d", collection.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newCollectionResponse(collection)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Get returns a collection with its phrases and assignees.
+func (h *CollectionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	collectionID, err := strconv.ParseUint(mux.Vars(r)["collection_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid collection ID", http.StatusBadRequest)
+		return
+	}
+
+	details, err := h.collectionUseCase.Get(r.Context(), uint(collectionID))
+	if err != nil {
+		logger.Errorf("Failed to get collection: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := collectionDetailsResponse{
+		collectionResponse: newCollectionResponse(details.Collection),
+		Phrases:            make([]collectionPhraseResponse, len(details.Phrases)),
+		Assignees:          details.Assignees,
+	}
+	for i, phrase := range details.Phrases {
+		response.Phrases[i] = collectionPhraseResponse{
+			Position: phrase.Position,
+			PhraseID: phrase.ID,
+			Text:     phrase.Phrase.Phrase,
+			Revision: phrase.Revision,
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// List returns the collections a user owns and those assigned to them.
+func (h *CollectionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	owned, assigned, err := h.collectionUseCase.List(r.Context(), uint(userID))
+	if err != nil {
+		logger.Errorf("Failed to list collections: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := collectionListResponse{
+		Owned:    make([]collectionResponse, len(owned)),
+		Assigned: make([]collectionResponse, len(assigned)),
+	}
+	for i, collection := range owned {
+		response.Owned[i] = newCollectionResponse(collection)
+	}
+	for i, collection := range assigned {
+		response.Assigned[i] = newCollectionResponse(collection)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Assign lets the user in the path record and download the phrases of the
+// collection. Only the owner in the path may assign it.
+func (h *CollectionHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	ownerID, collectionID, userID, ok := parseCollectionAssignment(w, r)
+	if !ok {
+		return
+	}
+	if err := h.collectionUseCase.Assign(r.Context(), ownerID, collectionID, userID); err != nil {
+		logger.Errorf("Failed to assign collection: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unassign lets the owner in the path revoke the assignment of the collection
+// to the user in the path.
+func (h *CollectionHandler) Unassign(w http.ResponseWriter, r *http.Request) {
+	ownerID, collectionID, userID, ok := parseCollectionAssignment(w, r)
+	if !ok {
+		return
+	}
+	if err := h.collectionUseCase.Unassign(r.Context(), ownerID, collectionID, userID); err != nil {
+		logger.Errorf("Failed to unassign collection: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadAudio stores the user's recording of the phrase at a position of the
+// collection, as AudioHandler.UploadAudio does for a phrase ID.
+func (h *CollectionHandler) UploadAudio(w http.ResponseWriter, r *http.Request) {
+	file, header, ok := readAudioFile(w, r, h.collectionUseCase.Policy())
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	collectionID, userID, position, ok := parseCollectionPhrase(w, r)
+	if !ok {
+		return
+	}
+	sessionID, err := parseSessionID(r.FormValue("session_id"))
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	audio, err := h.collectionUseCase.Upload(r.Context(), header.Filename, file, userID, collectionID, position, sessionID)
+	if err != nil {
+		logger.Errorf("Failed to upload collection audio: %v", err)
+		writeUploadError(w, err, statusFromError(err))
+		return
+	}
+	writeUploadedAudio(w, audio)
+}
+
+// GetAudio downloads the user's recording of the phrase at a position of the
+// collection, as AudioHandler.GetAudio does for a phrase ID.
+func (h *CollectionHandler) GetAudio(w http.ResponseWriter, r *http.Request) {
+	collectionID, userID, position, ok := parseCollectionPhrase(w, r)
+	if !ok {
+		return
+	}
+	opts, ok := parseDownloadOptions(w, r)
+	if !ok {
+		return
+	}
+
+	format := mux.Vars(r)["format"]
+	audio, err := h.collectionUseCase.Download(r.Context(), userID, collectionID, position, format, opts)
+	if err != nil {
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	writeAudio(w, audio, format)
+}
+
+func parseCollectionPhrase(w http.ResponseWriter, r *http.Request) (collectionID, userID uint, position int, ok bool) {
+	collectionID, userID, ok = parseCollectionUser(w, r)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	position, err := strconv.Atoi(mux.Vars(r)["position"])
+	if err != nil {
+		http.Error(w, "Invalid position", http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	return collectionID, userID, position, true
+}
+
+func parseCollectionAssignment(w http.ResponseWriter, r *http.Request) (ownerID, collectionID, userID uint, ok bool) {
+	owner, err := strconv.ParseUint(mux.Vars(r)["owner_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid owner ID", http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	collectionID, userID, ok = parseCollectionUser(w, r)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return uint(owner), collectionID, userID, true
+}
+
+func parseCollectionUser(w http.ResponseWriter, r *http.Request) (collectionID, userID uint, ok bool) {
+	collection, err := strconv.ParseUint(mux.Vars(r)["collection_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid collection ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	user, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return uint(collection), uint(user), true
+}
@@ -0,0 +1,254 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/delivery/http/router"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type collectionHandlerMocks struct {
+	repo       *repoMocks.MockCollectionRepository
+	userRepo   *repoMocks.MockUserRepository
+	phraseRepo *repoMocks.MockPhraseRepository
+	audioRepo  *repoMocks.MockAudioRepository
+	storage    *storageMocks.MockStorage
+}
+
+func newCollectionTestRouter(t *testing.T) (*mux.Router, collectionHandlerMocks) {
+	m := collectionHandlerMocks{
+		repo:       repoMocks.NewMockCollectionRepository(t),
+		userRepo:   repoMocks.NewMockUserRepository(t),
+		phraseRepo: repoMocks.NewMockPhraseRepository(t),
+		audioRepo:  repoMocks.NewMockAudioRepository(t),
+		storage:    storageMocks.NewMockStorage(t),
+	}
+	downloadUseCase := usecase.NewDownloadAudioUseCase(m.audioRepo, m.storage, converterMocks.NewMockAudioConverter(t), m.userRepo, m.phraseRepo, nil, false)
+	h := handler.NewCollectionHandler(usecase.NewCollectionUseCase(m.repo, m.userRepo, nil, downloadUseCase))
+	return router.SetupRoutes(router.Handlers{Collection: h}), m
+}
+
+func TestCollectionHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(collectionHandlerMocks)
+		expectedStatus int
+	}{
+		{
+			name: "create",
+			body: `{"name":"Greetings","language":"en-US","tags":["news"],"phrases":["Hello","Goodbye"]}`,
+			setupMocks: func(m collectionHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.repo.On("Create", mock.Anything, mock.Anything, []string{"Hello", "Goodbye"}).
+					Return(&entity.Collection{ID: 4, UserID: 1, Name: "Greetings", Language: "en-US", Tags: []string{"news"}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "no phrases",
+			body:           `{"name":"Greetings","phrases":[]}`,
+			setupMocks:     func(collectionHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `phrases`,
+			setupMocks:     func(collectionHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newCollectionTestRouter(t)
+			tt.setupMocks(m)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users/1/collections", strings.NewReader(tt.body)))
+			require.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			assert.Equal(t, "/collections/4", rr.Header().Get("Location"))
+			assert.JSONEq(t, `{"id":4,"user_id":1,"name":"Greetings","language":"en-US","tags":["news"],
+				"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`, rr.Body.String())
+		})
+	}
+}
+
+func TestCollectionHandler_Get(t *testing.T) {
+	router, m := newCollectionTestRouter(t)
+	m.repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1, Name: "Greetings"}, nil)
+	m.repo.On("ListPhrases", mock.Anything, uint(4)).Return([]*entity.CollectionPhrase{
+		{Position: 1, Phrase: entity.Phrase{ID: 7, Phrase: "Hello"}},
+		{Position: 2, Phrase: entity.Phrase{ID: 8, Phrase: "Goodbye", Revision: 1}},
+	}, nil)
+	m.repo.On("ListAssignees", mock.Anything, uint(4)).Return([]uint{2}, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/collections/4", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got struct {
+		Name    string `json:"name"`
+		Tags    []string
+		Phrases []struct {
+			Position int    `json:"position"`
+			PhraseID uint   `json:"phrase_id"`
+			Text     string `json:"text"`
+			Revision int    `json:"revision"`
+		} `json:"phrases"`
+		Assignees []uint `json:"assignees"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Equal(t, "Greetings", got.Name)
+	assert.Equal(t, []string{}, got.Tags)
+	require.Len(t, got.Phrases, 2)
+	assert.Equal(t, 2, got.Phrases[1].Position)
+	assert.Equal(t, uint(8), got.Phrases[1].PhraseID)
+	assert.Equal(t, "Goodbye", got.Phrases[1].Text)
+	assert.Equal(t, 1, got.Phrases[1].Revision)
+	assert.Equal(t, []uint{2}, got.Assignees)
+}
+
+func TestCollectionHandler_List(t *testing.T) {
+	router, m := newCollectionTestRouter(t)
+	m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+	m.repo.On("ListByUserID", mock.Anything, uint(2)).Return([]*entity.Collection{}, nil)
+	m.repo.On("ListAssigned", mock.Anything, uint(2)).Return([]*entity.Collection{{ID: 4, UserID: 1, Name: "Greetings"}}, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/2/collections", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got struct {
+		Owned    []map[string]interface{} `json:"owned"`
+		Assigned []map[string]interface{} `json:"assigned"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Empty(t, got.Owned)
+	require.Len(t, got.Assigned, 1)
+	assert.Equal(t, "Greetings", got.Assigned[0]["name"])
+}
+
+func TestCollectionHandler_Assign(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		setupMocks     func(collectionHandlerMocks)
+		expectedStatus int
+	}{
+		{
+			name:   "assign",
+			method: http.MethodPut,
+			path:   "/users/1/collections/4/assignments/2",
+			setupMocks: func(m collectionHandlerMocks) {
+				m.repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1}, nil)
+				m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				m.repo.On("Assign", mock.Anything, uint(4), uint(2)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "assign to owner",
+			method: http.MethodPut,
+			path:   "/users/1/collections/4/assignments/1",
+			setupMocks: func(m collectionHandlerMocks) {
+				m.repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "unassign",
+			method: http.MethodDelete,
+			path:   "/users/1/collections/4/assignments/2",
+			setupMocks: func(m collectionHandlerMocks) {
+				m.repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1}, nil)
+				m.repo.On("Unassign", mock.Anything, uint(4), uint(2)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "assign by someone else",
+			method: http.MethodPut,
+			path:   "/users/2/collections/4/assignments/2",
+			setupMocks: func(m collectionHandlerMocks) {
+				m.repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "unassign by someone else",
+			method: http.MethodDelete,
+			path:   "/users/3/collections/4/assignments/2",
+			setupMocks: func(m collectionHandlerMocks) {
+				m.repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "unknown collection",
+			method: http.MethodDelete,
+			path:   "/users/1/collections/4/assignments/2",
+			setupMocks: func(m collectionHandlerMocks) {
+				m.repo.On("GetByID", mock.Anything, uint(4)).Return(nil, fmt.Errorf("failed to get collection 4: %w", repository.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newCollectionTestRouter(t)
+			tt.setupMocks(m)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestCollectionHandler_GetAudio(t *testing.T) {
+	t.Run("downloads the take of the phrase at the position", func(t *testing.T) {
+		router, m := newCollectionTestRouter(t)
+		m.repo.On("GetPhrase", mock.Anything, uint(4), 2).Return(&entity.CollectionPhrase{Position: 2, Phrase: entity.Phrase{ID: 8, UserID: 1}}, nil)
+		m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+		m.phraseRepo.On("GetByID", mock.Anything, uint(8)).Return(&entity.Phrase{ID: 8, UserID: 1}, nil)
+		// The collection is assigned to the speaker.
+		m.phraseRepo.On("IsSharedWith", mock.Anything, uint(8), uint(2)).Return(true, nil)
+		m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(2), uint(8)).
+			Return(&entity.Audio{ID: 11, StoragePath: "audio/converted/11.wav", CurrentFormat: "wav"}, nil)
+		m.storage.On("Download", mock.Anything, "audio/converted/11.wav").Return(io.NopCloser(strings.NewReader("RIFF")), nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audio/user/2/collection/4/phrase/2/wav", nil))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "RIFF", rr.Body.String())
+	})
+
+	t.Run("unknown position", func(t *testing.T) {
+		router, m := newCollectionTestRouter(t)
+		m.repo.On("GetPhrase", mock.Anything, uint(4), 9).Return(nil, fmt.Errorf("failed to get phrase 9 of collection 4: %w", repository.ErrNotFound))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audio/user/2/collection/4/phrase/9/wav", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
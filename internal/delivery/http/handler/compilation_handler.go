@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// CompilationHandler creates and serves compilations of a user's phrases.
+type CompilationHandler struct {
+	compilationUseCase *usecase.CompilationUseCase
+}
+
+// NewCompilationHandler creates a new CompilationHandler.
+func NewCompilationHandler(compilationUseCase *usecase.CompilationUseCase) *CompilationHandler {
+	return &CompilationHandler{
+		compilationUseCase: compilationUseCase,
+	}
+}
+
+// CreateCompilationRequest lists the phrases to compile, in order. Gap and
+// crossfade are in seconds.
+type CreateCompilationRequest struct {
+	PhraseIDs []uint  `json:"phrase_ids"`
+	Format    string  `json:"format"`
+	Gap       float64 `json:"gap"`
+	Crossfade float64 `json:"crossfade"`
+}
+
+type compilationResponse struct {
+	ID        uint                     `json:"id"`
+	UserID    uint                     `json:"user_id"`
+	PhraseIDs []uint                   `json:"phrase_ids"`
+	Format    string                   `json:"format"`
+	Gap       float64                  `json:"gap"`
+	Crossfade float64                  `json:"crossfade"`
+	Status    entity.CompilationStatus `json:"status"`
+	Error     string                   `json:"error,omitempty"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+func newCompilationResponse(c *entity.Compilation) compilationResponse {
+	return compilationResponse{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		PhraseIDs: c.PhraseIDs,
+		Format:    c.Format,
+		Gap:       c.Gap,
+		Crossfade: c.Crossfade,
+		Status:    c.Status,
+		Error:     c.Error,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// Create queues a compilation and responds with 202 Accepted.
+func (h *CompilationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req CreateCompilationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	compilation, err := h.compilationUseCase.Create(r.Context(), uint(userID), usecase.CompilationRequest{
+		PhraseIDs: req.PhraseIDs,
+		Format:    req.Format,
+		Gap:       req.Gap,
+		Crossfade: req.Crossfade,
+	})
+	if err != nil {
+		logger.Errorf("Failed to create compilation: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/compilations/%d", compilation.ID))
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(newCompilationResponse(compilation)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Get returns the status of a compilation.
+func (h *CompilationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	compilationID, err := strconv.ParseUint(mux.Vars(r)["compilation_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid compilation ID", http.StatusBadRequest)
+		return
+	}
+
+	compilation, err := h.compilationUseCase.Get(r.Context(), uint(compilationID))
+	if err != nil {
+		logger.Errorf("Failed to get compilation: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newCompilationResponse(compilation)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Download streams the file of a completed compilation.
+func (h *CompilationHandler) Download(w http.ResponseWriter, r *http.Request) {
+	compilationID, err := strconv.ParseUint(mux.Vars(r)["compilation_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid compilation ID", http.StatusBadRequest)
+		return
+	}
+
+	reader, compilation, err := h.compilationUseCase.Download(r.Context(), uint(compilationID))
+	if err != nil {
+		logger.Errorf("Failed to download compilation: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	defer reader.Close()
+
+	w.Header().Set("Content-Type", contentTypeForFormat(compilation.Format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"compilation-%d.%s\"", compilation.ID, compilation.Format))
+	if _, err := io.Copy(w, reader); err != nil {
+		logger.Errorf("Failed to write compilation: %v", err)
+	}
+}
+
+// Manifest returns the phrase offsets of a completed compilation.
+func (h *CompilationHandler) Manifest(w http.ResponseWriter, r *http.Request) {
+	compilationID, err := strconv.ParseUint(mux.Vars(r)["compilation_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid compilation ID", http.StatusBadRequest)
+		return
+	}
+
+	reader, err := h.compilationUseCase.Manifest(r.Context(), uint(compilationID))
+	if err != nil {
+		logger.Errorf("Failed to get compilation manifest: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	defer reader.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := io.Copy(w, reader); err != nil {
+		logger.Errorf("Failed to write compilation manifest: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type compilationHandlerMocks struct {
+	repo      *repoMocks.MockCompilationRepository
+	audioRepo *repoMocks.MockAudioRepository
+	userRepo  *repoMocks.MockUserRepository
+	storage   *storageMocks.MockStorage
+	queue     *queueMocks.MockTaskQueue
+}
+
+func newCompilationTestRouter(t *testing.T) (*mux.Router, compilationHandlerMocks) {
+	m := compilationHandlerMocks{
+		repo:      repoMocks.NewMockCompilationRepository(t),
+		audioRepo: repoMocks.NewMockAudioRepository(t),
+		userRepo:  repoMocks.NewMockUserRepository(t),
+		storage:   storageMocks.NewMockStorage(t),
+		queue:     queueMocks.NewMockTaskQueue(t),
+	}
+	h := handler.NewCompilationHandler(usecase.NewCompilationUseCase(m.repo, m.audioRepo, m.userRepo, m.storage, converterMocks.NewMockAudioConverter(t), m.queue))
+	router := mux.NewRouter()
+	router.HandleFunc("/users/{user_id:[0-9]+}/compilations", h.Create).Methods(http.MethodPost)
+	router.HandleFunc("/compilations/{compilation_id:[0-9]+}", h.Get).Methods(http.MethodGet)
+	router.HandleFunc("/compilations/{compilation_id:[0-9]+}/audio", h.Download).Methods(http.MethodGet)
+	router.HandleFunc("/compilations/{compilation_id:[0-9]+}/manifest", h.Manifest).Methods(http.MethodGet)
+	return router, m
+}
+
+func TestCompilationHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(compilationHandlerMocks)
+		expectedStatus int
+	}{
+		{
+			name: "accepted",
+			body: `{"phrase_ids":[1],"format":"mp3","gap":0.25}`,
+			setupMocks: func(m compilationHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(&entity.Audio{ID: 4, Status: entity.AudioStatusCompleted}, nil)
+				m.repo.On("Create", mock.Anything, mock.Anything).Return(&entity.Compilation{
+					ID: 9, UserID: 1, PhraseIDs: []uint{1}, Format: "mp3", Gap: 0.25, Status: entity.CompilationStatusPending,
+				}, nil)
+				m.queue.On("Enqueue", mock.Anything, uint(9)).Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "unsupported format",
+			body:           `{"phrase_ids":[1],"format":"exe"}`,
+			setupMocks:     func(compilationHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no phrases",
+			body:           `{"phrase_ids":[]}`,
+			setupMocks:     func(compilationHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "phrase not converted",
+			body: `{"phrase_ids":[1]}`,
+			setupMocks: func(m compilationHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(&entity.Audio{Status: entity.AudioStatusPending}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newCompilationTestRouter(t)
+			tt.setupMocks(m)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users/1/compilations", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusAccepted {
+				assert.Equal(t, "/compilations/9", rr.Header().Get("Location"))
+				var resp map[string]interface{}
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+				assert.Equal(t, "pending", resp["status"])
+				assert.Equal(t, 0.25, resp["gap"])
+			}
+		})
+	}
+}
+
+func TestCompilationHandler_Download(t *testing.T) {
+	completed := &entity.Compilation{
+		ID:           9,
+		Format:       "mp3",
+		Status:       entity.CompilationStatusCompleted,
+		StoragePath:  "audio/compilations/9.mp3",
+		ManifestPath: "audio/compilations/9.json",
+	}
+
+	t.Run("audio", func(t *testing.T) {
+		router, m := newCompilationTestRouter(t)
+		m.repo.On("GetByID", mock.Anything, uint(9)).Return(completed, nil)
+		m.storage.On("Download", mock.Anything, completed.StoragePath).Return(io.NopCloser(bytes.NewReader([]byte("mp3 data"))), nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/compilations/9/audio", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "audio/mpeg", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "mp3 data", rr.Body.String())
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		router, m := newCompilationTestRouter(t)
+		m.repo.On("GetByID", mock.Anything, uint(9)).Return(completed, nil)
+		m.storage.On("Download", mock.Anything, completed.ManifestPath).Return(io.NopCloser(strings.NewReader(`{"phrases":[]}`)), nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/compilations/9/manifest", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"phrases":[]}`, rr.Body.String())
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		router, m := newCompilationTestRouter(t)
+		m.repo.On("GetByID", mock.Anything, uint(9)).Return(&entity.Compilation{ID: 9, Status: entity.CompilationStatusProcessing}, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/compilations/9/audio", nil))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		router, m := newCompilationTestRouter(t)
+		m.repo.On("GetByID", mock.Anything, uint(9)).Return(completed, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/compilations/9", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var resp map[string]interface{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		assert.Equal(t, "completed", resp["status"])
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+)
+
+// statusFromError maps use case errors to HTTP status codes.
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, usecase.ErrInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrGone):
+		return http.StatusGone
+	case errors.Is(err, usecase.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, usecase.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, usecase.ErrUnprocessable):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorResponse is the JSON body for errors clients are expected to act on.
+type errorResponse struct {
+	Error  string `json:"error"`
+	Reason string `json:"reason"`
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(errorResponse{Error: message, Reason: reason}); err != nil {
+		logger.Errorf("Failed to encode error response: %v", err)
+	}
+}
+
+// writeUploadError writes err with the given status, using a JSON body with a
+// machine-readable reason when the upload was rejected by policy.
+func writeUploadError(w http.ResponseWriter, err error, status int) {
+	var rejected *usecase.UploadRejectedError
+	if errors.As(err, &rejected) {
+		writeJSONError(w, status, rejected.Message, rejected.Reason)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
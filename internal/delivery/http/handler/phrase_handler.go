@@ -2,9 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/ardfard/sb-test/internal/usecase"
 	"github.com/ardfard/sb-test/pkg/logger"
 	"github.com/gorilla/mux"
@@ -12,11 +17,28 @@ import (
 
 type PhraseHandler struct {
 	createPhraseUseCase *usecase.CreatePhraseUseCase
+	sharePhraseUseCase  *usecase.SharePhraseUseCase
+	getPhraseUseCase    *usecase.GetPhraseUseCase
+	updatePhraseUseCase *usecase.UpdatePhraseUseCase
+	deletePhraseUseCase *usecase.DeletePhraseUseCase
+	importPhraseUseCase *usecase.ImportPhrasesUseCase
 }
 
-func NewPhraseHandler(createPhraseUseCase *usecase.CreatePhraseUseCase) *PhraseHandler {
+func NewPhraseHandler(
+	createPhraseUseCase *usecase.CreatePhraseUseCase,
+	sharePhraseUseCase *usecase.SharePhraseUseCase,
+	getPhraseUseCase *usecase.GetPhraseUseCase,
+	updatePhraseUseCase *usecase.UpdatePhraseUseCase,
+	deletePhraseUseCase *usecase.DeletePhraseUseCase,
+	importPhraseUseCase *usecase.ImportPhrasesUseCase,
+) *PhraseHandler {
 	return &PhraseHandler{
 		createPhraseUseCase: createPhraseUseCase,
+		sharePhraseUseCase:  sharePhraseUseCase,
+		getPhraseUseCase:    getPhraseUseCase,
+		updatePhraseUseCase: updatePhraseUseCase,
+		deletePhraseUseCase: deletePhraseUseCase,
+		importPhraseUseCase: importPhraseUseCase,
 	}
 }
 
@@ -25,10 +47,80 @@ type CreatePhraseRequest struct {
 }
 
 type CreatePhraseResponse struct {
-	ID   uint   `json:"id"`
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	Text      string    `json:"text"`
+	Revision  int       `json:"revision"`
+	Reference bool      `json:"reference"` // Whether takes are scored against a reference recording
+	Language  string    `json:"language"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newPhraseResponse(phrase *entity.Phrase) CreatePhraseResponse {
+	tags := phrase.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return CreatePhraseResponse{
+		ID:        phrase.ID,
+		UserID:    phrase.UserID,
+		Text:      phrase.Phrase,
+		Revision:  phrase.Revision,
+		Reference: phrase.ReferencePath != "",
+		Language:  phrase.Language,
+		Tags:      tags,
+		CreatedAt: phrase.CreatedAt,
+		UpdatedAt: phrase.UpdatedAt,
+	}
+}
+
+// UpdatePhraseRequest replaces the text of a phrase.
+type UpdatePhraseRequest struct {
 	Text string `json:"text"`
 }
 
+type phraseListResponse struct {
+	Phrases []CreatePhraseResponse `json:"phrases"`
+	Total   int                    `json:"total"`
+	Limit   int                    `json:"limit"`
+	Offset  int                    `json:"offset"`
+}
+
+type phraseRevisionResponse struct {
+	Revision   int       `json:"revision"`
+	Text       string    `json:"text"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// maxPhraseImportSize bounds the body of a phrase import.
+const maxPhraseImportSize = 10 << 20
+
+// phraseImportFormats maps the content types of phrase imports to their
+// format.
+var phraseImportFormats = map[string]usecase.PhraseImportFormat{
+	"text/csv":                  usecase.PhraseImportCSV,
+	"text/tab-separated-values": usecase.PhraseImportTSV,
+	"text/plain":                usecase.PhraseImportText,
+}
+
+type phraseImportRowResponse struct {
+	Line     int                        `json:"line"`
+	PhraseID uint                       `json:"phrase_id,omitempty"`
+	Text     string                     `json:"text"`
+	Action   usecase.PhraseImportAction `json:"action"`
+}
+
+type phraseImportResponse struct {
+	DryRun     bool                      `json:"dry_run"`
+	Created    int                       `json:"created"`
+	Updated    int                       `json:"updated"`
+	Unchanged  int                       `json:"unchanged"`
+	Duplicates int                       `json:"duplicates"`
+	Rows       []phraseImportRowResponse `json:"rows"`
+}
+
 func (h *PhraseHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -59,14 +151,11 @@ func (h *PhraseHandler) Create(w http.ResponseWriter, r *http.Request) {
 	phrase, err := h.createPhraseUseCase.Create(r.Context(), req.Text, uint(userIDUint))
 	if err != nil {
 		logger.Errorf("Failed to create phrase: %v", err)
-		http.Error(w, "Failed to create phrase", http.StatusInternalServerError)
+		http.Error(w, "Failed to create phrase", statusFromError(err))
 		return
 	}
 
-	response := CreatePhraseResponse{
-		ID:   phrase.ID,
-		Text: phrase.Phrase,
-	}
+	response := newPhraseResponse(phrase)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -75,3 +164,282 @@ func (h *PhraseHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// Get returns a phrase.
+func (h *PhraseHandler) Get(w http.ResponseWriter, r *http.Request) {
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	phrase, err := h.getPhraseUseCase.Get(r.Context(), uint(phraseID))
+	if err != nil {
+		logger.Errorf("Failed to get phrase: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newPhraseResponse(phrase)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// List returns a page of the phrases a user owns. ?has_audio=true|false
+// selects phrases with or without takes and ?audio_status those with a take
+// in that status.
+func (h *PhraseHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := repository.PhraseFilter{UserID: uint(userID), AudioStatus: entity.AudioStatus(query.Get("audio_status"))}
+	for param, target := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		if v := query.Get(param); v != "" {
+			if *target, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if v := query.Get("has_audio"); v != "" {
+		hasAudio, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "Invalid has_audio", http.StatusBadRequest)
+			return
+		}
+		filter.HasAudio = &hasAudio
+	}
+
+	page, err := h.getPhraseUseCase.List(r.Context(), filter)
+	if err != nil {
+		logger.Errorf("Failed to list phrases: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := phraseListResponse{
+		Phrases: make([]CreatePhraseResponse, 0, len(page.Phrases)),
+		Total:   page.Total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+	}
+	for _, phrase := range page.Phrases {
+		response.Phrases = append(response.Phrases, newPhraseResponse(phrase))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Update changes the text of a phrase. Takes recorded against the old text
+// are flagged as stale.
+func (h *PhraseHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdatePhraseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	phrase, err := h.updatePhraseUseCase.Update(r.Context(), uint(userID), uint(phraseID), req.Text)
+	if err != nil {
+		logger.Errorf("Failed to update phrase: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newPhraseResponse(phrase)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// ListRevisions returns the texts a phrase had before its edits.
+func (h *PhraseHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.getPhraseUseCase.ListRevisions(r.Context(), uint(phraseID))
+	if err != nil {
+		logger.Errorf("Failed to list phrase revisions: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	resp := make([]phraseRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		resp[i] = phraseRevisionResponse{Revision: revision.Revision, Text: revision.Phrase, ReplacedAt: revision.ReplacedAt}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Delete removes a phrase with every take of it.
+func (h *PhraseHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.deletePhraseUseCase.Delete(r.Context(), uint(userID), uint(phraseID)); err != nil {
+		logger.Errorf("Failed to delete phrase: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Claim makes the user in the path the owner of a phrase that has none.
+func (h *PhraseHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.sharePhraseUseCase.Claim(r.Context(), uint(userID), uint(phraseID)); err != nil {
+		logger.Errorf("Failed to claim phrase: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Share lets the user in the path record and download the phrase.
+func (h *PhraseHandler) Share(w http.ResponseWriter, r *http.Request) {
+	ownerID, phraseID, userID, ok := parsePhraseShare(w, r)
+	if !ok {
+		return
+	}
+	if err := h.sharePhraseUseCase.Share(r.Context(), ownerID, phraseID, userID); err != nil {
+		logger.Errorf("Failed to share phrase: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unshare revokes the access of the user in the path to the phrase.
+func (h *PhraseHandler) Unshare(w http.ResponseWriter, r *http.Request) {
+	ownerID, phraseID, userID, ok := parsePhraseShare(w, r)
+	if !ok {
+		return
+	}
+	if err := h.sharePhraseUseCase.Unshare(r.Context(), ownerID, phraseID, userID); err != nil {
+		logger.Errorf("Failed to unshare phrase: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parsePhraseShare(w http.ResponseWriter, r *http.Request) (ownerID, phraseID, userID uint, ok bool) {
+	owner, err := strconv.ParseUint(mux.Vars(r)["owner_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid owner ID", http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	phrase, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	user, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	return uint(owner), uint(phrase), uint(user), true
+}
+
+// Import creates and updates phrases of a user from a CSV, TSV or plain text
+// body. The format is given by ?format=csv|tsv|text or by the Content-Type;
+// ?dry_run=true reports what would change without changing anything.
+func (h *PhraseHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	format, ok := phraseImportFormats[strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0])]
+	if v := query.Get("format"); v != "" {
+		if format, err = usecase.ParsePhraseImportFormat(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else if !ok {
+		http.Error(w, "Unsupported import format", http.StatusUnsupportedMediaType)
+		return
+	}
+	var dryRun bool
+	if v := query.Get("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Invalid dry_run", http.StatusBadRequest)
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPhraseImportSize)
+	report, err := h.importPhraseUseCase.Import(r.Context(), uint(userID), format, r.Body, dryRun)
+	if err != nil {
+		logger.Errorf("Failed to import phrases: %v", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Import too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := phraseImportResponse{
+		DryRun:     report.DryRun,
+		Created:    report.Created,
+		Updated:    report.Updated,
+		Unchanged:  report.Unchanged,
+		Duplicates: report.Duplicates,
+		Rows:       make([]phraseImportRowResponse, 0, len(report.Rows)),
+	}
+	for _, row := range report.Rows {
+		response.Rows = append(response.Rows, phraseImportRowResponse(row))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
@@ -3,16 +3,24 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ardfard/sb-test/internal/delivery/http/handler"
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
 	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
 	"github.com/ardfard/sb-test/internal/usecase"
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPhraseHandler_Create(t *testing.T) {
@@ -37,6 +45,15 @@ func TestPhraseHandler_Create(t *testing.T) {
 				"text": "Hello, World!",
 			},
 		},
+		{
+			name:   "user not found",
+			method: "POST",
+			userID: "2",
+			requestBody: map[string]interface{}{
+				"text": "Hello, World!",
+			},
+			expectedStatus: http.StatusNotFound,
+		},
 		{
 			name:   "empty text",
 			method: "POST",
@@ -75,9 +92,14 @@ func TestPhraseHandler_Create(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Initialize mocks
 			mockPhraseRepo := repoMocks.NewMockPhraseRepository(t)
+			mockUserRepo := repoMocks.NewMockUserRepository(t)
+			if tt.userID == "2" {
+				mockUserRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+			}
 
 			// Use mock.MatchedBy for more flexible matching
 			if tt.requestBody != nil && tt.method == "POST" && tt.requestBody["text"] != "" && tt.userID == "1" {
+				mockUserRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
 				mockPhraseRepo.On("Create", mock.Anything, mock.MatchedBy(func(phrase *entity.Phrase) bool {
 					return phrase.UserID == 1 && phrase.Phrase == "Hello, World!"
 				})).Return(&entity.Phrase{
@@ -88,10 +110,10 @@ func TestPhraseHandler_Create(t *testing.T) {
 			}
 
 			// Create use case
-			createPhraseUseCase := usecase.NewCreatePhraseUseCase(mockPhraseRepo)
+			createPhraseUseCase := usecase.NewCreatePhraseUseCase(mockPhraseRepo, mockUserRepo)
 
 			// Create handler
-			handler := handler.NewPhraseHandler(createPhraseUseCase)
+			handler := handler.NewPhraseHandler(createPhraseUseCase, usecase.NewSharePhraseUseCase(mockPhraseRepo, mockUserRepo), nil, nil, nil, nil)
 
 			// Create request
 			var req *http.Request
@@ -134,3 +156,389 @@ func TestPhraseHandler_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestPhraseHandler_Share(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		setupMocks     func(*repoMocks.MockPhraseRepository, *repoMocks.MockUserRepository)
+		expectedStatus int
+	}{
+		{
+			name:   "share",
+			method: http.MethodPut,
+			path:   "/users/3/phrases/2/shares/1",
+			setupMocks: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("Share", mock.Anything, uint(2), uint(1)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "share with owner",
+			method: http.MethodPut,
+			path:   "/users/3/phrases/2/shares/3",
+			setupMocks: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "unshare",
+			method: http.MethodDelete,
+			path:   "/users/3/phrases/2/shares/1",
+			setupMocks: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+				phraseRepo.On("Unshare", mock.Anything, uint(2), uint(1)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "not the owner",
+			method: http.MethodPut,
+			path:   "/users/4/phrases/2/shares/1",
+			setupMocks: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "claim phrase without owner",
+			method: http.MethodPost,
+			path:   "/users/1/phrases/2/claim",
+			setupMocks: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2}, nil)
+				phraseRepo.On("Claim", mock.Anything, uint(2), uint(1)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "claim owned phrase",
+			method: http.MethodPost,
+			path:   "/users/1/phrases/2/claim",
+			setupMocks: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "unknown phrase",
+			method: http.MethodDelete,
+			path:   "/users/3/phrases/2/shares/1",
+			setupMocks: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			tt.setupMocks(phraseRepo, userRepo)
+
+			h := handler.NewPhraseHandler(usecase.NewCreatePhraseUseCase(phraseRepo, userRepo), usecase.NewSharePhraseUseCase(phraseRepo, userRepo), nil, nil, nil, nil)
+			router := mux.NewRouter()
+			router.HandleFunc("/users/{owner_id:[0-9]+}/phrases/{phrase_id:[0-9]+}/shares/{user_id:[0-9]+}", h.Share).Methods(http.MethodPut)
+			router.HandleFunc("/users/{owner_id:[0-9]+}/phrases/{phrase_id:[0-9]+}/shares/{user_id:[0-9]+}", h.Unshare).Methods(http.MethodDelete)
+			router.HandleFunc("/users/{user_id:[0-9]+}/phrases/{phrase_id:[0-9]+}/claim", h.Claim).Methods(http.MethodPost)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+type phraseHandlerMocks struct {
+	phraseRepo *repoMocks.MockPhraseRepository
+	userRepo   *repoMocks.MockUserRepository
+	audioRepo  *repoMocks.MockAudioRepository
+	uploadRepo *repoMocks.MockUploadRepository
+}
+
+func newPhraseTestRouter(t *testing.T) (*mux.Router, phraseHandlerMocks) {
+	m := phraseHandlerMocks{
+		phraseRepo: repoMocks.NewMockPhraseRepository(t),
+		userRepo:   repoMocks.NewMockUserRepository(t),
+		audioRepo:  repoMocks.NewMockAudioRepository(t),
+		uploadRepo: repoMocks.NewMockUploadRepository(t),
+	}
+	h := handler.NewPhraseHandler(
+		usecase.NewCreatePhraseUseCase(m.phraseRepo, m.userRepo),
+		usecase.NewSharePhraseUseCase(m.phraseRepo, m.userRepo),
+		usecase.NewGetPhraseUseCase(m.phraseRepo, m.userRepo),
+		usecase.NewUpdatePhraseUseCase(m.phraseRepo),
+		usecase.NewDeletePhraseUseCase(m.phraseRepo, m.audioRepo, m.uploadRepo, storageMocks.NewMockStorage(t), storageMocks.NewMockStagingArea(t), queueMocks.NewMockTaskQueue(t)),
+		usecase.NewImportPhrasesUseCase(m.phraseRepo, m.userRepo),
+	)
+	router := mux.NewRouter()
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases", h.List).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases/import", h.Import).Methods(http.MethodPost)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}", h.Get).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases/{phrase_id:[0-9]+}", h.Update).Methods(http.MethodPatch)
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases/{phrase_id:[0-9]+}", h.Delete).Methods(http.MethodDelete)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}/revisions", h.ListRevisions).Methods(http.MethodGet)
+	return router, m
+}
+
+func TestPhraseHandler_Get(t *testing.T) {
+	router, m := newPhraseTestRouter(t)
+	m.phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 3, Phrase: "hello", Revision: 2, ReferencePath: "audio/references/1.wav"}, nil)
+	m.phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("failed to get phrase 2: %w", repository.ErrNotFound))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/phrases/1", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got map[string]interface{}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Equal(t, "hello", got["text"])
+	assert.Equal(t, float64(3), got["user_id"])
+	assert.Equal(t, float64(2), got["revision"])
+	assert.Equal(t, true, got["reference"])
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/phrases/2", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPhraseHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(phraseHandlerMocks)
+		expectedStatus int
+	}{
+		{
+			name:  "filtered page",
+			query: "?has_audio=false&audio_status=failed&limit=1&offset=1",
+			setupMocks: func(m phraseHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phraseRepo.On("List", mock.Anything, mock.MatchedBy(func(filter repository.PhraseFilter) bool {
+					return filter.UserID == 1 && filter.HasAudio != nil && !*filter.HasAudio &&
+						filter.AudioStatus == entity.AudioStatusFailed && filter.Limit == 1 && filter.Offset == 1
+				})).Return([]*entity.Phrase{{ID: 2, UserID: 1, Phrase: "two"}}, 2, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid has_audio",
+			query:          "?has_audio=maybe",
+			setupMocks:     func(phraseHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown audio status",
+			query:          "?audio_status=lost",
+			setupMocks:     func(phraseHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "unknown user",
+			query: "",
+			setupMocks: func(m phraseHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get user 1: %w", repository.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newPhraseTestRouter(t)
+			tt.setupMocks(m)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/1/phrases"+tt.query, nil))
+			require.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var got struct {
+				Phrases []map[string]interface{} `json:"phrases"`
+				Total   int                      `json:"total"`
+				Limit   int                      `json:"limit"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+			assert.Equal(t, 2, got.Total)
+			assert.Equal(t, 1, got.Limit)
+			require.Len(t, got.Phrases, 1)
+			assert.Equal(t, "two", got.Phrases[0]["text"])
+		})
+	}
+}
+
+func TestPhraseHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(phraseHandlerMocks)
+		expectedStatus int
+	}{
+		{
+			name: "edit text",
+			body: `{"text":"hello"}`,
+			setupMocks: func(m phraseHandlerMocks) {
+				m.phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1, Phrase: "helo"}, nil)
+				m.phraseRepo.On("Revise", mock.Anything, uint(1), "hello").Return(&entity.Phrase{ID: 1, Phrase: "hello", Revision: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not the owner",
+			body: `{"text":"hello"}`,
+			setupMocks: func(m phraseHandlerMocks) {
+				m.phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 2, Phrase: "helo"}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "empty text",
+			body:           `{"text":""}`,
+			setupMocks:     func(phraseHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `text`,
+			setupMocks:     func(phraseHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newPhraseTestRouter(t)
+			tt.setupMocks(m)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/users/1/phrases/1", strings.NewReader(tt.body)))
+			require.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var got map[string]interface{}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+			assert.Equal(t, "hello", got["text"])
+			assert.Equal(t, float64(1), got["revision"])
+		})
+	}
+}
+
+func TestPhraseHandler_ListRevisions(t *testing.T) {
+	router, m := newPhraseTestRouter(t)
+	replacedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	m.phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, Phrase: "hello", Revision: 1}, nil)
+	m.phraseRepo.On("ListRevisions", mock.Anything, uint(1)).Return([]*entity.PhraseRevision{{PhraseID: 1, Revision: 0, Phrase: "helo", ReplacedAt: replacedAt}}, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/phrases/1/revisions", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"revision":0,"text":"helo","replaced_at":"2024-01-02T03:04:05Z"}]`, rr.Body.String())
+}
+
+func TestPhraseHandler_Delete(t *testing.T) {
+	router, m := newPhraseTestRouter(t)
+	m.phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+	m.audioRepo.On("ListByPhraseID", mock.Anything, uint(1)).Return([]*entity.Audio{}, nil)
+	m.uploadRepo.On("ListByPhraseID", mock.Anything, uint(1)).Return([]*entity.Upload{}, nil)
+	m.phraseRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+	m.phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("failed to get phrase 2: %w", repository.ErrNotFound))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/users/2/phrases/1", nil))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/users/1/phrases/1", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/users/1/phrases/2", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPhraseHandler_Import(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		contentType    string
+		body           string
+		setupMocks     func(phraseHandlerMocks)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "csv dry run",
+			query:       "?dry_run=true",
+			contentType: "text/csv; charset=utf-8",
+			body:        "text,tags\nHello,greeting\nGood morning,greeting\n",
+			setupMocks: func(m phraseHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return([]*entity.Phrase{{ID: 4, UserID: 1, Phrase: "Hello"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"dry_run":true,"created":1,"updated":0,"unchanged":0,"duplicates":1,"rows":[` +
+				`{"line":2,"phrase_id":4,"text":"Hello","action":"duplicate"},{"line":3,"text":"Good morning","action":"created"}]}`,
+		},
+		{
+			name:  "plain text",
+			query: "?format=text",
+			body:  "Hello\n",
+			setupMocks: func(m phraseHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return([]*entity.Phrase{}, nil)
+				m.phraseRepo.On("Import", mock.Anything, mock.Anything, []*entity.Phrase(nil)).Return([]*entity.Phrase{{ID: 9, Phrase: "Hello"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"dry_run":false,"created":1,"updated":0,"unchanged":0,"duplicates":0,"rows":[{"line":1,"phrase_id":9,"text":"Hello","action":"created"}]}`,
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "application/json",
+			body:           `["Hello"]`,
+			setupMocks:     func(phraseHandlerMocks) {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "unknown format",
+			query:          "?format=xlsx",
+			body:           "Hello\n",
+			setupMocks:     func(phraseHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing text column",
+			contentType:    "text/tab-separated-values",
+			body:           "id\tlanguage\n1\ten\n",
+			setupMocks:     func(phraseHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newPhraseTestRouter(t)
+			tt.setupMocks(m)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/1/phrases/import"+tt.query, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// PitchHandler serves the F0 contour of converted audio.
+type PitchHandler struct {
+	pitchUseCase *usecase.PitchUseCase
+}
+
+// NewPitchHandler creates a new PitchHandler.
+func NewPitchHandler(pitchUseCase *usecase.PitchUseCase) *PitchHandler {
+	return &PitchHandler{
+		pitchUseCase: pitchUseCase,
+	}
+}
+
+// Get returns the F0 contour of an audio and its summary as JSON, or the
+// contour alone as CSV when requested with ?format=csv.
+func (h *PitchHandler) Get(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "Unsupported pitch format", http.StatusBadRequest)
+		return
+	}
+
+	contour, err := h.pitchUseCase.Get(r.Context(), uint(audioID))
+	if err != nil {
+		logger.Errorf("Failed to get pitch contour: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"pitch-%d.csv\"", audioID))
+		if err := contour.WriteCSV(w); err != nil {
+			logger.Errorf("Failed to write pitch contour: %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(contour); err != nil {
+		logger.Errorf("Failed to encode pitch contour: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/pitch"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPitchHandler_Get(t *testing.T) {
+	stored := `{"hop":0.01,"duration":0.03,"points":[{"time":0.01,"f0":0,"voiced":false},{"time":0.02,"f0":210.5,"voiced":true}],"stats":{"mean_f0":210.5,"voiced_segments":1}}`
+
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expectedStatus int
+		expectedType   string
+		check          func(*testing.T, []byte)
+	}{
+		{
+			name: "json",
+			path: "/audio/1/pitch",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusCompleted}, nil)
+				s.On("Download", mock.Anything, "audio/pitch/1.json").Return(io.NopCloser(bytes.NewReader([]byte(stored))), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json",
+			check: func(t *testing.T, body []byte) {
+				var contour pitch.Contour
+				require.NoError(t, json.Unmarshal(body, &contour))
+				assert.Len(t, contour.Points, 2)
+				assert.Equal(t, 210.5, contour.Stats.MeanF0)
+			},
+		},
+		{
+			name: "csv",
+			path: "/audio/1/pitch?format=csv",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusCompleted}, nil)
+				s.On("Download", mock.Anything, "audio/pitch/1.json").Return(io.NopCloser(bytes.NewReader([]byte(stored))), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv",
+			check: func(t *testing.T, body []byte) {
+				assert.Equal(t, "time,f0,voiced\n0.0100,0.00,false\n0.0200,210.50,true\n", string(body))
+			},
+		},
+		{
+			name:           "unsupported format",
+			path:           "/audio/1/pitch?format=xml",
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "still converting",
+			path: "/audio/1/pitch",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			h := handler.NewPitchHandler(usecase.NewPitchUseCase(repo, s, pitch.Options{Hop: 10 * time.Millisecond, MinF0: 75, MaxF0: 500, Threshold: 0.15}))
+			router := mux.NewRouter()
+			router.HandleFunc("/audio/{audio_id:[0-9]+}/pitch", h.Get).Methods(http.MethodGet)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.check != nil {
+				assert.Equal(t, tt.expectedType, rr.Header().Get("Content-Type"))
+				tt.check(t, rr.Body.Bytes())
+			}
+		})
+	}
+}
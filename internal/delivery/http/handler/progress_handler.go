@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// ProgressHandler serves the recording progress of speakers.
+type ProgressHandler struct {
+	progressUseCase *usecase.ProgressUseCase
+}
+
+// NewProgressHandler creates a new ProgressHandler.
+func NewProgressHandler(progressUseCase *usecase.ProgressUseCase) *ProgressHandler {
+	return &ProgressHandler{
+		progressUseCase: progressUseCase,
+	}
+}
+
+type progressResponse struct {
+	Phrases          int     `json:"phrases"`
+	NoAudio          int     `json:"no_audio"`
+	Pending          int     `json:"pending"`
+	Converting       int     `json:"converting"`
+	Completed        int     `json:"completed"`
+	Failed           int     `json:"failed"`
+	RecordedDuration float64 `json:"recorded_duration"` // Seconds of completed takes
+}
+
+func newProgressResponse(progress *entity.RecordingProgress) progressResponse {
+	return progressResponse{
+		Phrases:          progress.Phrases,
+		NoAudio:          progress.NoAudio,
+		Pending:          progress.Pending,
+		Converting:       progress.Converting,
+		Completed:        progress.Completed,
+		Failed:           progress.Failed,
+		RecordedDuration: progress.RecordedDuration,
+	}
+}
+
+// Get returns how many of the phrases a user may record have no take, or a
+// take in each status.
+func (h *ProgressHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	progress, err := h.progressUseCase.Progress(r.Context(), uint(userID))
+	if err != nil {
+		logger.Errorf("Failed to get recording progress: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newProgressResponse(progress)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// NextPhrase returns the phrase a user should record next, picked with the
+// ?strategy query parameter or the configured one. It responds 404 when
+// nothing is left to record.
+func (h *ProgressHandler) NextPhrase(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	strategy := repository.NextPhraseStrategy(r.URL.Query().Get("strategy"))
+	phrase, err := h.progressUseCase.NextPhrase(r.Context(), uint(userID), strategy)
+	if err != nil {
+		logger.Errorf("Failed to get next phrase: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newPhraseResponse(phrase)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/delivery/http/router"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type progressMocks struct {
+	audio  *repoMocks.MockAudioRepository
+	phrase *repoMocks.MockPhraseRepository
+	user   *repoMocks.MockUserRepository
+}
+
+func newProgressTestRouter(t *testing.T) (*mux.Router, progressMocks) {
+	m := progressMocks{
+		audio:  repoMocks.NewMockAudioRepository(t),
+		phrase: repoMocks.NewMockPhraseRepository(t),
+		user:   repoMocks.NewMockUserRepository(t),
+	}
+	h := handler.NewProgressHandler(usecase.NewProgressUseCase(m.audio, m.phrase, m.user, repository.NextPhraseInOrder))
+	return router.SetupRoutes(router.Handlers{Progress: h}), m
+}
+
+func TestProgressHandler_Get(t *testing.T) {
+	router, m := newProgressTestRouter(t)
+	m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	m.audio.On("Progress", mock.Anything, uint(1)).
+		Return(&entity.RecordingProgress{Phrases: 5, NoAudio: 1, Pending: 1, Completed: 2, Failed: 1, RecordedDuration: 6.25}, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/1/progress", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{
+		"phrases":           5.0,
+		"no_audio":          1.0,
+		"pending":           1.0,
+		"converting":        0.0,
+		"completed":         2.0,
+		"failed":            1.0,
+		"recorded_duration": 6.25,
+	}, body)
+}
+
+func TestProgressHandler_NextPhrase(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(progressMocks)
+		expectedStatus int
+	}{
+		{
+			name: "configured strategy",
+			path: "/users/1/next-phrase",
+			setupMocks: func(m progressMocks) {
+				m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phrase.On("NextToRecord", mock.Anything, uint(1), repository.NextPhraseInOrder).Return(&entity.Phrase{ID: 4, Phrase: "hello"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "requested strategy",
+			path: "/users/1/next-phrase?strategy=failed_first",
+			setupMocks: func(m progressMocks) {
+				m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phrase.On("NextToRecord", mock.Anything, uint(1), repository.NextPhraseFailedFirst).Return(&entity.Phrase{ID: 4, Phrase: "hello"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown strategy",
+			path:           "/users/1/next-phrase?strategy=alphabetical",
+			setupMocks:     func(progressMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "nothing left to record",
+			path: "/users/1/next-phrase",
+			setupMocks: func(m progressMocks) {
+				m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phrase.On("NextToRecord", mock.Anything, uint(1), repository.NextPhraseInOrder).
+					Return(nil, fmt.Errorf("no phrase left to record for user 1: %w", repository.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newProgressTestRouter(t)
+			tt.setupMocks(m)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var phrase handler.CreatePhraseResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &phrase))
+				assert.Equal(t, uint(4), phrase.ID)
+				assert.Equal(t, "hello", phrase.Text)
+			}
+		})
+	}
+}
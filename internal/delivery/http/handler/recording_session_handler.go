@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// RecordingSessionHandler serves the sessions speakers record takes in.
+type RecordingSessionHandler struct {
+	sessionUseCase *usecase.RecordingSessionUseCase
+}
+
+// NewRecordingSessionHandler creates a new RecordingSessionHandler.
+func NewRecordingSessionHandler(sessionUseCase *usecase.RecordingSessionUseCase) *RecordingSessionHandler {
+	return &RecordingSessionHandler{
+		sessionUseCase: sessionUseCase,
+	}
+}
+
+type OpenSessionRequest struct {
+	Device     string `json:"device"`
+	Client     string `json:"client"` // Browser or app and its version
+	SampleRate int    `json:"sample_rate"`
+	Microphone string `json:"microphone"`
+	Location   string `json:"location"`
+}
+
+type sessionResponse struct {
+	ID         uint       `json:"id"`
+	UserID     uint       `json:"user_id"`
+	Device     string     `json:"device"`
+	Client     string     `json:"client"`
+	SampleRate int        `json:"sample_rate"`
+	Microphone string     `json:"microphone"`
+	Location   string     `json:"location"`
+	Closed     bool       `json:"closed"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type sessionListResponse struct {
+	Sessions []sessionResponse `json:"sessions"`
+	Total    int               `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+func newSessionResponse(session *entity.RecordingSession) sessionResponse {
+	return sessionResponse{
+		ID:         session.ID,
+		UserID:     session.UserID,
+		Device:     session.Device,
+		Client:     session.Client,
+		SampleRate: session.SampleRate,
+		Microphone: session.Microphone,
+		Location:   session.Location,
+		Closed:     session.ClosedAt != nil,
+		ClosedAt:   session.ClosedAt,
+		CreatedAt:  session.CreatedAt,
+		UpdatedAt:  session.UpdatedAt,
+	}
+}
+
+// parseSessionID parses the optional recording session of an upload. An
+// empty value means the take is not part of a session.
+func parseSessionID(value string) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// writeSession encodes a session as the JSON response.
+func writeSession(w http.ResponseWriter, session *entity.RecordingSession) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newSessionResponse(session)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Open starts a recording session of the user in the path.
+func (h *RecordingSessionHandler) Open(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req OpenSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.sessionUseCase.Open(r.Context(), uint(userID), usecase.RecordingSessionInput{
+		Device:     req.Device,
+		Client:     req.Client,
+		SampleRate: req.SampleRate,
+		Microphone: req.Microphone,
+		Location:   req.Location,
+	})
+	if err != nil {
+		logger.Errorf("Failed to open recording session: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/sessions/%d", session.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newSessionResponse(session)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// List returns a page of the recording sessions of the user in the path,
+// most recent first.
+func (h *RecordingSessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	var limit, offset int
+	for param, target := range map[string]*int{"limit": &limit, "offset": &offset} {
+		if v := query.Get(param); v != "" {
+			if *target, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	page, err := h.sessionUseCase.List(r.Context(), uint(userID), limit, offset)
+	if err != nil {
+		logger.Errorf("Failed to list recording sessions: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := sessionListResponse{
+		Sessions: make([]sessionResponse, 0, len(page.Sessions)),
+		Total:    page.Total,
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+	}
+	for _, session := range page.Sessions {
+		response.Sessions = append(response.Sessions, newSessionResponse(session))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Get returns a recording session.
+func (h *RecordingSessionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := strconv.ParseUint(mux.Vars(r)["session_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.sessionUseCase.Get(r.Context(), uint(sessionID))
+	if err != nil {
+		logger.Errorf("Failed to get recording session: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	writeSession(w, session)
+}
+
+// Close ends a recording session; later uploads in it are rejected.
+func (h *RecordingSessionHandler) Close(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := strconv.ParseUint(mux.Vars(r)["session_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.sessionUseCase.Close(r.Context(), uint(sessionID))
+	if err != nil {
+		logger.Errorf("Failed to close recording session: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	writeSession(w, session)
+}
+
+// Takes lists the takes uploaded in a recording session, oldest first.
+func (h *RecordingSessionHandler) Takes(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := strconv.ParseUint(mux.Vars(r)["session_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	audios, err := h.sessionUseCase.Takes(r.Context(), uint(sessionID))
+	if err != nil {
+		logger.Errorf("Failed to list takes of recording session: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := make([]audioResponse, 0, len(audios))
+	for _, audio := range audios {
+		response = append(response, newAudioResponse(audio))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
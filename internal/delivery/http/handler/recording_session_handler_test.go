@@ -0,0 +1,149 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/delivery/http/router"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type sessionMocks struct {
+	session *repoMocks.MockRecordingSessionRepository
+	audio   *repoMocks.MockAudioRepository
+	user    *repoMocks.MockUserRepository
+}
+
+func newSessionTestRouter(t *testing.T) (*mux.Router, sessionMocks) {
+	m := sessionMocks{
+		session: repoMocks.NewMockRecordingSessionRepository(t),
+		audio:   repoMocks.NewMockAudioRepository(t),
+		user:    repoMocks.NewMockUserRepository(t),
+	}
+	h := handler.NewRecordingSessionHandler(usecase.NewRecordingSessionUseCase(m.session, m.audio, m.user))
+	return router.SetupRoutes(router.Handlers{Session: h}), m
+}
+
+func TestRecordingSessionHandler_Open(t *testing.T) {
+	t.Run("opened", func(t *testing.T) {
+		router, m := newSessionTestRouter(t)
+		m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.session.On("Create", mock.Anything, mock.AnythingOfType("*entity.RecordingSession")).
+			Return(func(_ context.Context, s *entity.RecordingSession) *entity.RecordingSession {
+				created := *s
+				created.ID = 4
+				return &created
+			}, nil)
+
+		rr := httptest.NewRecorder()
+		body := `{"device": "Pixel 8", "client": "Chrome 129", "sample_rate": 48000, "microphone": "Built-in", "location": "studio-a"}`
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users/1/sessions", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "/sessions/4", rr.Header().Get("Location"))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, 4.0, response["id"])
+		assert.Equal(t, "Built-in", response["microphone"])
+		assert.Equal(t, 48000.0, response["sample_rate"])
+		assert.Equal(t, false, response["closed"])
+		assert.NotContains(t, response, "closed_at")
+	})
+
+	t.Run("invalid sample rate", func(t *testing.T) {
+		router, _ := newSessionTestRouter(t)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users/1/sessions", strings.NewReader(`{"sample_rate": -1}`)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestRecordingSessionHandler_List(t *testing.T) {
+	router, m := newSessionTestRouter(t)
+	m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	m.session.On("List", mock.Anything, repository.RecordingSessionFilter{UserID: 1, Limit: 5, Offset: 5}).
+		Return([]*entity.RecordingSession{{ID: 4, UserID: 1}}, 6, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/1/sessions?limit=5&offset=5", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Sessions []struct {
+			ID uint `json:"id"`
+		} `json:"sessions"`
+		Total int `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Sessions, 1)
+	assert.Equal(t, uint(4), response.Sessions[0].ID)
+	assert.Equal(t, 6, response.Total)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/1/sessions?limit=x", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestRecordingSessionHandler_GetCloseTakes(t *testing.T) {
+	t.Run("get", func(t *testing.T) {
+		router, m := newSessionTestRouter(t)
+		m.session.On("GetByID", mock.Anything, uint(4)).Return(&entity.RecordingSession{ID: 4, UserID: 1, Device: "Pixel 8"}, nil)
+		m.session.On("GetByID", mock.Anything, uint(5)).Return(nil, fmt.Errorf("failed to get recording session 5: %w", repository.ErrNotFound))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/sessions/4", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"device":"Pixel 8"`)
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/sessions/5", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		router, m := newSessionTestRouter(t)
+		closedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		m.session.On("Close", mock.Anything, uint(4), mock.AnythingOfType("time.Time")).
+			Return(&entity.RecordingSession{ID: 4, UserID: 1, ClosedAt: &closedAt}, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/sessions/4/close", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, true, response["closed"])
+		assert.Equal(t, "2024-05-01T12:00:00Z", response["closed_at"])
+	})
+
+	t.Run("takes", func(t *testing.T) {
+		router, m := newSessionTestRouter(t)
+		m.session.On("GetByID", mock.Anything, uint(4)).Return(&entity.RecordingSession{ID: 4, UserID: 1}, nil)
+		m.audio.On("ListBySessionID", mock.Anything, uint(4)).
+			Return([]*entity.Audio{{ID: 7, UserID: 1, PhraseID: 2, SessionID: 4, Status: entity.AudioStatusCompleted}}, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/sessions/4/audio", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response []map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.Len(t, response, 1)
+		assert.Equal(t, 7.0, response[0]["id"])
+		assert.Equal(t, 4.0, response[0]["session_id"])
+	})
+}
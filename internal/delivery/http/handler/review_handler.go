@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// ReviewHandler serves the review of recorded takes.
+type ReviewHandler struct {
+	reviewUseCase *usecase.ReviewUseCase
+}
+
+// NewReviewHandler creates a new ReviewHandler.
+func NewReviewHandler(reviewUseCase *usecase.ReviewUseCase) *ReviewHandler {
+	return &ReviewHandler{
+		reviewUseCase: reviewUseCase,
+	}
+}
+
+type ReviewRequest struct {
+	ReviewerID uint                `json:"reviewer_id"`
+	Status     entity.ReviewStatus `json:"status"`
+	Reasons    []string            `json:"reasons"`
+	Notes      string              `json:"notes"`
+}
+
+type reviewResponse struct {
+	AudioID    uint                `json:"audio_id"`
+	Status     entity.ReviewStatus `json:"status"`
+	ReviewerID uint                `json:"reviewer_id,omitempty"` // Omitted once the reviewer is deleted
+	Reasons    []string            `json:"reasons"`
+	Notes      string              `json:"notes"`
+	ReviewedAt *time.Time          `json:"reviewed_at,omitempty"`
+}
+
+func newReviewResponse(review *entity.AudioReview) reviewResponse {
+	reasons := review.Reasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+	response := reviewResponse{
+		AudioID:    review.AudioID,
+		Status:     review.Status,
+		ReviewerID: review.ReviewerID,
+		Reasons:    reasons,
+		Notes:      review.Notes,
+	}
+	if !review.UpdatedAt.IsZero() {
+		response.ReviewedAt = &review.UpdatedAt
+	}
+	return response
+}
+
+type reviewQueueResponse struct {
+	Audios []audioResponse `json:"audios"`
+	Total  int             `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+type rejectedTakeResponse struct {
+	reviewResponse
+	PhraseID uint   `json:"phrase_id"`
+	Phrase   string `json:"phrase"`
+}
+
+// Review records the verdict of a reviewer on a completed take.
+func (h *ReviewHandler) Review(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.reviewUseCase.Review(r.Context(), uint(audioID), usecase.ReviewRequest{
+		ReviewerID: req.ReviewerID,
+		Status:     req.Status,
+		Reasons:    req.Reasons,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		logger.Errorf("Failed to review audio: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newReviewResponse(review)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// ReviewSession records the verdict of a reviewer on every completed take of
+// a recording session and returns the reviews.
+func (h *ReviewHandler) ReviewSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := strconv.ParseUint(mux.Vars(r)["session_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := h.reviewUseCase.ReviewSession(r.Context(), uint(sessionID), usecase.ReviewRequest{
+		ReviewerID: req.ReviewerID,
+		Status:     req.Status,
+		Reasons:    req.Reasons,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		logger.Errorf("Failed to review recording session: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := make([]reviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		response = append(response, newReviewResponse(review))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Get returns the review of a take, with status "unreviewed" if it has none.
+func (h *ReviewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.reviewUseCase.Get(r.Context(), uint(audioID))
+	if err != nil {
+		logger.Errorf("Failed to get review: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newReviewResponse(review)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Queue returns a page of the completed takes nobody reviewed, optionally of
+// the speaker in ?user_id, the phrase in ?phrase_id or the recording session
+// in ?session_id.
+func (h *ReviewHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var userID, phraseID, sessionID, limit, offset int
+	params := map[string]*int{"user_id": &userID, "phrase_id": &phraseID, "session_id": &sessionID, "limit": &limit, "offset": &offset}
+	for param, target := range params {
+		if v := query.Get(param); v != "" {
+			var err error
+			if *target, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if userID < 0 || phraseID < 0 || sessionID < 0 {
+		http.Error(w, "Invalid filter", http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.reviewUseCase.Queue(r.Context(), repository.ReviewQueueFilter{
+		UserID:    uint(userID),
+		PhraseID:  uint(phraseID),
+		SessionID: uint(sessionID),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		logger.Errorf("Failed to list review queue: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := reviewQueueResponse{
+		Audios: make([]audioResponse, 0, len(page.Audios)),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	for _, audio := range page.Audios {
+		response.Audios = append(response.Audios, newAudioResponse(audio))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Rejected lists the takes of a speaker that were rejected or have to be
+// recorded again, most recently reviewed first.
+func (h *ReviewHandler) Rejected(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	takes, err := h.reviewUseCase.Rejected(r.Context(), uint(userID))
+	if err != nil {
+		logger.Errorf("Failed to list rejected takes: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := make([]rejectedTakeResponse, 0, len(takes))
+	for _, take := range takes {
+		response = append(response, rejectedTakeResponse{
+			reviewResponse: newReviewResponse(&take.AudioReview),
+			PhraseID:       take.PhraseID,
+			Phrase:         take.Phrase,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/delivery/http/router"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type reviewMocks struct {
+	review  *repoMocks.MockAudioReviewRepository
+	audio   *repoMocks.MockAudioRepository
+	user    *repoMocks.MockUserRepository
+	session *repoMocks.MockRecordingSessionRepository
+}
+
+func newReviewTestRouter(t *testing.T) (*mux.Router, reviewMocks) {
+	m := reviewMocks{
+		review:  repoMocks.NewMockAudioReviewRepository(t),
+		audio:   repoMocks.NewMockAudioRepository(t),
+		user:    repoMocks.NewMockUserRepository(t),
+		session: repoMocks.NewMockRecordingSessionRepository(t),
+	}
+	h := handler.NewReviewHandler(usecase.NewReviewUseCase(m.review, m.audio, m.user, m.session))
+	return router.SetupRoutes(router.Handlers{Review: h}), m
+}
+
+func TestReviewHandler_Review(t *testing.T) {
+	t.Run("reject", func(t *testing.T) {
+		router, m := newReviewTestRouter(t)
+		m.user.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+		m.audio.On("GetByID", mock.Anything, uint(5)).Return(&entity.Audio{ID: 5, UserID: 1, Status: entity.AudioStatusCompleted}, nil)
+		reviewedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		m.review.On("Save", mock.Anything, mock.AnythingOfType("*entity.AudioReview")).
+			Return(func(_ context.Context, review *entity.AudioReview) (*entity.AudioReview, error) {
+				stored := *review
+				stored.CreatedAt, stored.UpdatedAt = reviewedAt, reviewedAt
+				return &stored, nil
+			})
+
+		rr := httptest.NewRecorder()
+		body := `{"reviewer_id": 2, "status": "rejected", "reasons": ["noise"], "notes": "a dog barks"}`
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/audio/5/review", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, map[string]interface{}{
+			"audio_id":    5.0,
+			"status":      "rejected",
+			"reviewer_id": 2.0,
+			"reasons":     []interface{}{"noise"},
+			"notes":       "a dog barks",
+			"reviewed_at": "2024-01-02T03:04:05Z",
+		}, response)
+	})
+
+	t.Run("without reason", func(t *testing.T) {
+		router, _ := newReviewTestRouter(t)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/audio/5/review", strings.NewReader(`{"reviewer_id": 2, "status": "needs_rerecord"}`)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("own take", func(t *testing.T) {
+		router, m := newReviewTestRouter(t)
+		m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.audio.On("GetByID", mock.Anything, uint(5)).Return(&entity.Audio{ID: 5, UserID: 1, Status: entity.AudioStatusCompleted}, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/audio/5/review", strings.NewReader(`{"reviewer_id": 1, "status": "approved"}`)))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		router, _ := newReviewTestRouter(t)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/audio/5/review", strings.NewReader(`{`)))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestReviewHandler_ReviewSession(t *testing.T) {
+	t.Run("flags the session", func(t *testing.T) {
+		router, m := newReviewTestRouter(t)
+		m.user.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+		m.session.On("GetByID", mock.Anything, uint(4)).Return(&entity.RecordingSession{ID: 4, UserID: 1}, nil)
+		m.review.On("SaveSession", mock.Anything, mock.MatchedBy(func(r *entity.SessionReview) bool { return r.SessionID == 4 })).
+			Return(func(_ context.Context, r *entity.SessionReview) []*entity.AudioReview {
+				return []*entity.AudioReview{{AudioID: 5, Status: r.Status, ReviewerID: r.ReviewerID, Reasons: r.Reasons, Notes: r.Notes}}
+			}, nil)
+
+		rr := httptest.NewRecorder()
+		body := `{"reviewer_id": 2, "status": "needs_rerecord", "reasons": ["clipping"], "notes": "broken mic"}`
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/sessions/4/review", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response []map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.Len(t, response, 1)
+		assert.Equal(t, 5.0, response[0]["audio_id"])
+		assert.Equal(t, "needs_rerecord", response[0]["status"])
+	})
+
+	t.Run("own session", func(t *testing.T) {
+		router, m := newReviewTestRouter(t)
+		m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.session.On("GetByID", mock.Anything, uint(4)).Return(&entity.RecordingSession{ID: 4, UserID: 1}, nil)
+
+		rr := httptest.NewRecorder()
+		body := `{"reviewer_id": 1, "status": "approved"}`
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/sessions/4/review", strings.NewReader(body)))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
+
+func TestReviewHandler_Get(t *testing.T) {
+	router, m := newReviewTestRouter(t)
+	m.audio.On("GetByID", mock.Anything, uint(5)).Return(&entity.Audio{ID: 5}, nil)
+	m.review.On("GetByAudioID", mock.Anything, uint(5)).Return(nil, fmt.Errorf("failed to get review of audio 5: %w", repository.ErrNotFound))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audio/5/review", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, map[string]interface{}{
+		"audio_id": 5.0,
+		"status":   "unreviewed",
+		"reasons":  []interface{}{},
+		"notes":    "",
+	}, response)
+}
+
+func TestReviewHandler_Queue(t *testing.T) {
+	t.Run("filtered page", func(t *testing.T) {
+		router, m := newReviewTestRouter(t)
+		m.review.On("ListUnreviewed", mock.Anything, repository.ReviewQueueFilter{UserID: 1, PhraseID: 3, Limit: 10, Offset: 20}).
+			Return([]*entity.Audio{{ID: 5, UserID: 1, PhraseID: 3, Status: entity.AudioStatusCompleted, NeedsReview: true}}, 21, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reviews/queue?user_id=1&phrase_id=3&limit=10&offset=20", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response struct {
+			Audios []struct {
+				ID          uint `json:"id"`
+				NeedsReview bool `json:"needs_review"`
+			} `json:"audios"`
+			Total int `json:"total"`
+			Limit int `json:"limit"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.Len(t, response.Audios, 1)
+		assert.Equal(t, uint(5), response.Audios[0].ID)
+		assert.True(t, response.Audios[0].NeedsReview)
+		assert.Equal(t, 21, response.Total)
+		assert.Equal(t, 10, response.Limit)
+	})
+
+	t.Run("session", func(t *testing.T) {
+		router, m := newReviewTestRouter(t)
+		m.review.On("ListUnreviewed", mock.Anything, repository.ReviewQueueFilter{SessionID: 4, Limit: usecase.DefaultPageSize}).
+			Return([]*entity.Audio{{ID: 5, UserID: 1, SessionID: 4, Status: entity.AudioStatusCompleted}}, 1, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reviews/queue?session_id=4", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"session_id":4`)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		router, _ := newReviewTestRouter(t)
+		for _, query := range []string{"user_id=x", "phrase_id=-1", "session_id=-1", "limit=1000"} {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reviews/queue?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		}
+	})
+}
+
+func TestReviewHandler_Rejected(t *testing.T) {
+	t.Run("rejected takes", func(t *testing.T) {
+		router, m := newReviewTestRouter(t)
+		m.user.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.review.On("ListRejected", mock.Anything, uint(1)).Return([]*entity.RejectedTake{{
+			AudioReview: entity.AudioReview{AudioID: 5, Status: entity.ReviewStatusNeedsRerecord, ReviewerID: 2,
+				Reasons: []string{entity.ReviewReasonTruncated}, UpdatedAt: time.Now()},
+			PhraseID: 3,
+			Phrase:   "hello",
+		}}, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/1/rejected-takes", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response []map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.Len(t, response, 1)
+		assert.Equal(t, 5.0, response[0]["audio_id"])
+		assert.Equal(t, "needs_rerecord", response[0]["status"])
+		assert.Equal(t, []interface{}{"truncated"}, response[0]["reasons"])
+		assert.Equal(t, 3.0, response[0]["phrase_id"])
+		assert.Equal(t, "hello", response[0]["phrase"])
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		router, m := newReviewTestRouter(t)
+		m.user.On("GetByID", mock.Anything, uint(9)).Return(nil, fmt.Errorf("failed to get user 9: %w", repository.ErrNotFound))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/9/rejected-takes", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// SegmentedUploadHandler accepts one recording of several phrases.
+type SegmentedUploadHandler struct {
+	segmentedUploadUseCase *usecase.SegmentedUploadUseCase
+}
+
+// NewSegmentedUploadHandler creates a new SegmentedUploadHandler.
+func NewSegmentedUploadHandler(segmentedUploadUseCase *usecase.SegmentedUploadUseCase) *SegmentedUploadHandler {
+	return &SegmentedUploadHandler{
+		segmentedUploadUseCase: segmentedUploadUseCase,
+	}
+}
+
+type segmentationResponse struct {
+	Expected int               `json:"expected"`
+	Detected int               `json:"detected"`
+	Mismatch bool              `json:"mismatch"`
+	Segments []segmentResponse `json:"segments"`
+}
+
+type segmentResponse struct {
+	PhraseID uint           `json:"phrase_id,omitempty"`
+	Start    float64        `json:"start"`
+	End      float64        `json:"end"`
+	Audio    *audioResponse `json:"audio,omitempty"`
+}
+
+func newSegmentationResponse(result *usecase.SegmentationResult) segmentationResponse {
+	resp := segmentationResponse{
+		Expected: result.Expected,
+		Detected: len(result.Segments),
+		Mismatch: result.Mismatch,
+		Segments: make([]segmentResponse, len(result.Segments)),
+	}
+	for i, s := range result.Segments {
+		resp.Segments[i] = segmentResponse{PhraseID: s.PhraseID, Start: s.Start, End: s.End}
+		if s.Audio != nil {
+			audio := newAudioResponse(s.Audio)
+			resp.Segments[i].Audio = &audio
+		}
+	}
+	return resp
+}
+
+// Upload splits the audio_file form field at silences into one audio per
+// phrase in the comma-separated phrase_ids field. The threshold_db,
+// min_silence_ms and padding_ms fields override the configured silence
+// detection. A mismatch between segments and phrases is answered with
+// 422 Unprocessable Entity and the detected segments.
+func (h *SegmentedUploadHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if maxSize := h.segmentedUploadUseCase.Policy().MaxSize; maxSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+	}
+	file, header, err := r.FormFile("audio_file")
+	if err != nil {
+		logger.Errorf("Failed to get file from request: %v", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "Request body too large", usecase.RejectFileTooLarge)
+			return
+		}
+		http.Error(w, "Failed to get file from request", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var phraseIDs []uint
+	for _, field := range strings.Split(r.FormValue("phrase_ids"), ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid phrase IDs", http.StatusBadRequest)
+			return
+		}
+		phraseIDs = append(phraseIDs, uint(id))
+	}
+
+	sessionID, err := parseSessionID(r.FormValue("session_id"))
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	options := h.segmentedUploadUseCase.Options()
+	if v := r.FormValue("threshold_db"); v != "" {
+		if options.ThresholdDB, err = strconv.ParseFloat(v, 64); err != nil {
+			http.Error(w, "Invalid threshold_db", http.StatusBadRequest)
+			return
+		}
+	}
+	for field, target := range map[string]*time.Duration{"min_silence_ms": &options.MinDuration, "padding_ms": &options.Padding} {
+		if v := r.FormValue(field); v != "" {
+			ms, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid "+field, http.StatusBadRequest)
+				return
+			}
+			*target = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	result, err := h.segmentedUploadUseCase.Upload(r.Context(), header.Filename, file, uint(userID), phraseIDs, sessionID, options)
+	if err != nil {
+		logger.Errorf("Failed to upload segmented audio: %v", err)
+		writeUploadError(w, err, statusFromError(err))
+		return
+	}
+
+	status := http.StatusOK
+	if result.Mismatch {
+		status = http.StatusUnprocessableEntity
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(newSegmentationResponse(result)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
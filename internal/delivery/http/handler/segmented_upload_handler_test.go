@@ -0,0 +1,140 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/silence"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentedUploadHandler_Upload(t *testing.T) {
+	// Two 300ms tones separated by 500ms of silence, at 1 kHz.
+	var samples []float64
+	for i := 0; i < 1100; i++ {
+		v := 0.0
+		if i < 300 || i >= 800 {
+			v = 0.5 - float64(i%2)
+		}
+		samples = append(samples, v)
+	}
+	var recording bytes.Buffer
+	require.NoError(t, wav.Encode(&recording, 1000, 1, samples))
+
+	tests := []struct {
+		name           string
+		phraseIDs      string
+		setupMocks     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage, *converterMocks.MockAudioConverter, *queueMocks.MockTaskQueue, *repoMocks.MockUserRepository, *repoMocks.MockPhraseRepository)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:      "stores segments",
+			phraseIDs: "4, 2",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, q *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				for i, id := range []uint{4, 2} {
+					phraseRepo.On("GetByID", mock.Anything, id).Return(&entity.Phrase{ID: id, UserID: 1}, nil)
+					repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), id).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+					repo.On("Store", mock.Anything, mock.MatchedBy(func(a *entity.Audio) bool { return a.PhraseID == id })).
+						Return(&entity.Audio{ID: uint(i + 1), UserID: 1, PhraseID: id, Status: entity.AudioStatusPending}, nil)
+					q.On("Enqueue", mock.Anything, uint(i+1)).Return(nil)
+				}
+				conv.On("ConvertFromReader", mock.Anything, mock.Anything, "mp3", "wav").Return(io.NopCloser(bytes.NewReader(recording.Bytes())), nil)
+				conv.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "wav", Duration: 0.5}, nil)
+				s.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:      "segment count mismatch",
+			phraseIDs: "4,2,3",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, q *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				for _, id := range []uint{4, 2, 3} {
+					phraseRepo.On("GetByID", mock.Anything, id).Return(&entity.Phrase{ID: id, UserID: 1}, nil)
+					repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), id).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+				}
+				conv.On("ConvertFromReader", mock.Anything, mock.Anything, "mp3", "wav").Return(io.NopCloser(bytes.NewReader(recording.Bytes())), nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCount:  2,
+		},
+		{
+			name:      "invalid phrase IDs",
+			phraseIDs: "4,x",
+			setupMocks: func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage, *converterMocks.MockAudioConverter, *queueMocks.MockTaskQueue, *repoMocks.MockUserRepository, *repoMocks.MockPhraseRepository) {
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			conv := converterMocks.NewMockAudioConverter(t)
+			q := queueMocks.NewMockTaskQueue(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+			tt.setupMocks(repo, s, conv, q, userRepo, phraseRepo)
+
+			uploads := usecase.NewUploadAudioUseCase(repo, s, conv, q, userRepo, phraseRepo, repoMocks.NewMockRecordingSessionRepository(t), repoMocks.NewMockAudioReviewRepository(t), usecase.UploadPolicy{})
+			options := silence.Options{ThresholdDB: -40, MinDuration: 300 * time.Millisecond, Padding: 100 * time.Millisecond}
+			h := handler.NewSegmentedUploadHandler(usecase.NewSegmentedUploadUseCase(uploads, conv, options))
+			router := mux.NewRouter()
+			router.HandleFunc("/audio/user/{user_id:[0-9]+}/segmented", h.Upload).Methods(http.MethodPost)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("audio_file", "script.mp3")
+			require.NoError(t, err)
+			_, err = part.Write([]byte("ID3\x04\x00\x00script"))
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteField("phrase_ids", tt.phraseIDs))
+			require.NoError(t, writer.Close())
+			req := httptest.NewRequest(http.MethodPost, "/audio/user/1/segmented", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedCount > 0 {
+				var resp struct {
+					Detected int  `json:"detected"`
+					Mismatch bool `json:"mismatch"`
+					Segments []struct {
+						PhraseID uint            `json:"phrase_id"`
+						Audio    json.RawMessage `json:"audio"`
+					} `json:"segments"`
+				}
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+				assert.Equal(t, tt.expectedCount, resp.Detected)
+				assert.Equal(t, tt.expectedStatus == http.StatusUnprocessableEntity, resp.Mismatch)
+				assert.Equal(t, uint(4), resp.Segments[0].PhraseID)
+				assert.Equal(t, !resp.Mismatch, resp.Segments[0].Audio != nil)
+			}
+		})
+	}
+}
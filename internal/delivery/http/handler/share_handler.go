@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// ShareHandler mints and redeems signed download links.
+type ShareHandler struct {
+	shareUseCase *usecase.ShareAudioUseCase
+	baseURL      string
+}
+
+// NewShareHandler creates a new ShareHandler. Minted links are prefixed with baseURL.
+func NewShareHandler(shareUseCase *usecase.ShareAudioUseCase, baseURL string) *ShareHandler {
+	return &ShareHandler{
+		shareUseCase: shareUseCase,
+		baseURL:      baseURL,
+	}
+}
+
+type CreateShareLinkRequest struct {
+	Format     string `json:"format"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+	SingleUse  bool   `json:"single_use"`
+	IP         string `json:"ip"`
+}
+
+type CreateShareLinkResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	SingleUse bool      `json:"single_use"`
+}
+
+// Create mints a share link for an audio.
+func (h *ShareHandler) Create(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	var req CreateShareLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.IP != "" && net.ParseIP(req.IP) == nil {
+		http.Error(w, "Invalid IP address", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.shareUseCase.CreateLink(r.Context(), uint(audioID), usecase.ShareLinkRequest{
+		Format:    req.Format,
+		TTL:       time.Duration(req.TTLSeconds) * time.Second,
+		SingleUse: req.SingleUse,
+		IP:        req.IP,
+	})
+	if err != nil {
+		logger.Errorf("Failed to create share link: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := CreateShareLinkResponse{
+		URL:       h.linkURL(link),
+		ExpiresAt: link.ExpiresAt.UTC(),
+		SingleUse: link.SingleUse,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Open redeems a share link and streams (or redirects to) the audio.
+func (h *ShareHandler) Open(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	audioID, err := strconv.ParseUint(vars["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid link", http.StatusBadRequest)
+		return
+	}
+
+	link := &entity.ShareLink{
+		AudioID:   uint(audioID),
+		Format:    vars["format"],
+		ExpiresAt: time.Unix(expires, 0),
+		Nonce:     query.Get("nonce"),
+		SingleUse: query.Get("single_use") == "1",
+		IP:        query.Get("ip"),
+	}
+
+	shared, err := h.shareUseCase.Open(r.Context(), link, query.Get("sig"), clientIP(r))
+	if err != nil {
+		logger.Errorf("Failed to open share link: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	if shared.RedirectURL != "" {
+		http.Redirect(w, r, shared.RedirectURL, http.StatusFound)
+		return
+	}
+	defer shared.Reader.Close()
+
+	w.Header().Set("Content-Type", contentTypeForFormat(link.Format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"audio-%d.%s\"", link.AudioID, link.Format))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, shared.Reader); err != nil {
+		logger.Errorf("Failed to stream shared audio: %v", err)
+	}
+}
+
+func (h *ShareHandler) linkURL(link *entity.ShareLink) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(link.ExpiresAt.Unix(), 10))
+	query.Set("nonce", link.Nonce)
+	if link.SingleUse {
+		query.Set("single_use", "1")
+	}
+	if link.IP != "" {
+		query.Set("ip", link.IP)
+	}
+	query.Set("sig", h.shareUseCase.Sign(link))
+	return fmt.Sprintf("%s/share/audio/%d/%s?%s", h.baseURL, link.AudioID, link.Format, query.Encode())
+}
+
+// clientIP returns the address of the remote end of the connection.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// contentTypeForFormat returns the MIME type for an audio format.
+func contentTypeForFormat(format string) string {
+	switch format {
+	case "wav":
+		return "audio/wav"
+	case "flac":
+		return "audio/flac"
+	case "m4a":
+		return "audio/mp4"
+	default:
+		return "audio/mpeg"
+	}
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareHandler(t *testing.T) {
+	audio := &entity.Audio{
+		ID:            1,
+		UserID:        1,
+		PhraseID:      1,
+		CurrentFormat: "wav",
+		StoragePath:   "audio/converted/1.wav",
+		Status:        entity.AudioStatusCompleted,
+	}
+
+	mockAudioRepo := repoMocks.NewMockAudioRepository(t)
+	mockShareRepo := repoMocks.NewMockShareLinkRepository(t)
+	mockUserRepo := repoMocks.NewMockUserRepository(t)
+	mockPhraseRepo := repoMocks.NewMockPhraseRepository(t)
+	mockStorage := storageMocks.NewMockStorage(t)
+	mockConverter := converterMocks.NewMockAudioConverter(t)
+
+	mockAudioRepo.On("GetByID", mock.Anything, uint(1)).Return(audio, nil)
+	mockAudioRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, repository.ErrNotFound)
+	mockUserRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	mockPhraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+	mockAudioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(audio, nil)
+	mockStorage.On("Download", mock.Anything, audio.StoragePath).Return(io.NopCloser(strings.NewReader("wav data")), nil)
+
+	downloadUseCase := usecase.NewDownloadAudioUseCase(mockAudioRepo, mockStorage, mockConverter, mockUserRepo, mockPhraseRepo, nil, false)
+	shareUseCase := usecase.NewShareAudioUseCase(mockAudioRepo, mockShareRepo, mockStorage, downloadUseCase, usecase.ShareLinkSettings{
+		Secret:     []byte("secret"),
+		DefaultTTL: time.Hour,
+		MaxTTL:     24 * time.Hour,
+	})
+	h := handler.NewShareHandler(shareUseCase, "")
+
+	router := mux.NewRouter()
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/share", h.Create).Methods("POST")
+	router.HandleFunc("/share/audio/{audio_id:[0-9]+}/{format}", h.Open).Methods("GET")
+
+	createLink := func(t *testing.T, audioID string, body map[string]interface{}) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/audio/"+audioID+"/share", bytes.NewBuffer(payload))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("mint and redeem", func(t *testing.T) {
+		rr := createLink(t, "1", map[string]interface{}{"format": "wav", "ttl_seconds": 60})
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var resp handler.CreateShareLinkResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		assert.True(t, strings.HasPrefix(resp.URL, "/share/audio/1/wav?"))
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", resp.URL, nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "audio/wav", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "wav data", rr.Body.String())
+	})
+
+	t.Run("tampered link", func(t *testing.T) {
+		rr := createLink(t, "1", map[string]interface{}{"format": "wav"})
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var resp handler.CreateShareLinkResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+
+		u, err := url.Parse(resp.URL)
+		require.NoError(t, err)
+		query := u.Query()
+		query.Set("expires", "9999999999")
+		u.RawQuery = query.Encode()
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", u.String(), nil))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("ip bound link from another address", func(t *testing.T) {
+		rr := createLink(t, "1", map[string]interface{}{"format": "wav", "ip": "10.1.2.3"})
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var resp handler.CreateShareLinkResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", resp.URL, nil))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		rr := createLink(t, "1", map[string]interface{}{"format": "exe"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("unknown audio", func(t *testing.T) {
+		rr := createLink(t, "2", map[string]interface{}{"format": "mp3"})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("missing expiry", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/share/audio/1/wav?sig=abc", nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// SimilarityHandler manages phrase reference recordings and lists takes by
+// their similarity to them.
+type SimilarityHandler struct {
+	similarityUseCase *usecase.SimilarityUseCase
+}
+
+// NewSimilarityHandler creates a new SimilarityHandler.
+func NewSimilarityHandler(similarityUseCase *usecase.SimilarityUseCase) *SimilarityHandler {
+	return &SimilarityHandler{
+		similarityUseCase: similarityUseCase,
+	}
+}
+
+type referenceResponse struct {
+	PhraseID     uint `json:"phrase_id"`
+	HasReference bool `json:"has_reference"`
+}
+
+// SetReference stores the audio_file form field as the reference recording
+// of a phrase and queues its takes to be rescored.
+func (h *SimilarityHandler) SetReference(w http.ResponseWriter, r *http.Request) {
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	if maxSize := h.similarityUseCase.Policy().MaxSize; maxSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+	}
+	file, _, err := r.FormFile("audio_file")
+	if err != nil {
+		logger.Errorf("Failed to get file from request: %v", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "Request body too large", usecase.RejectFileTooLarge)
+			return
+		}
+		http.Error(w, "Failed to get file from request", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	phrase, err := h.similarityUseCase.SetReference(r.Context(), uint(phraseID), file)
+	if err != nil {
+		logger.Errorf("Failed to set reference: %v", err)
+		writeUploadError(w, err, statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(referenceResponse{PhraseID: phrase.ID, HasReference: true}); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// DeleteReference removes the reference recording of a phrase.
+func (h *SimilarityHandler) DeleteReference(w http.ResponseWriter, r *http.Request) {
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.similarityUseCase.DeleteReference(r.Context(), uint(phraseID)); err != nil {
+		logger.Errorf("Failed to delete reference: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAudio returns the takes of a phrase. ?sort=similarity lists the least
+// similar takes first and ?sort=-similarity the most similar first.
+func (h *SimilarityHandler) ListAudio(w http.ResponseWriter, r *http.Request) {
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	audios, err := h.similarityUseCase.List(r.Context(), uint(phraseID), r.URL.Query().Get("sort"))
+	if err != nil {
+		logger.Errorf("Failed to list audio: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	resp := make([]audioResponse, len(audios))
+	for i, audio := range audios {
+		resp[i] = newAudioResponse(audio)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSimilarityRouter(h *handler.SimilarityHandler) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}/reference", h.SetReference).Methods(http.MethodPut)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}/reference", h.DeleteReference).Methods(http.MethodDelete)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}/audio", h.ListAudio).Methods(http.MethodGet)
+	return router
+}
+
+func TestSimilarityHandler_ListAudio(t *testing.T) {
+	low, high := 0.25, 0.75
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*repoMocks.MockAudioRepository, *repoMocks.MockPhraseRepository)
+		expectedStatus int
+		expectedIDs    []uint
+	}{
+		{
+			name:  "most similar first",
+			query: "?sort=-similarity",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+				repo.On("ListByPhraseID", mock.Anything, uint(2)).Return([]*entity.Audio{
+					{ID: 1, PhraseID: 2, SimilarityScore: &low},
+					{ID: 2, PhraseID: 2},
+					{ID: 3, PhraseID: 2, SimilarityScore: &high},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []uint{3, 1, 2},
+		},
+		{
+			name:           "unknown sort order",
+			query:          "?sort=name",
+			setupMocks:     func(*repoMocks.MockAudioRepository, *repoMocks.MockPhraseRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "phrase not found",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+			tt.setupMocks(repo, phraseRepo)
+
+			h := handler.NewSimilarityHandler(usecase.NewSimilarityUseCase(repo, phraseRepo, nil, nil, nil, usecase.UploadPolicy{}))
+			req := httptest.NewRequest(http.MethodGet, "/phrases/2/audio"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			newSimilarityRouter(h).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var resp []struct {
+				ID         uint     `json:"id"`
+				Similarity *float64 `json:"similarity"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+			var ids []uint
+			for _, a := range resp {
+				ids = append(ids, a.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			require.NotNil(t, resp[0].Similarity)
+			assert.Equal(t, high, *resp[0].Similarity)
+			assert.Nil(t, resp[2].Similarity)
+		})
+	}
+}
+
+func TestSimilarityHandler_Reference(t *testing.T) {
+	t.Run("unsupported reference file", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+		h := handler.NewSimilarityHandler(usecase.NewSimilarityUseCase(nil, phraseRepo, nil, nil, nil, usecase.UploadPolicy{}))
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("audio_file", "reference.txt")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("not audio"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		req := httptest.NewRequest(http.MethodPut, "/phrases/2/reference", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		rr := httptest.NewRecorder()
+		newSimilarityRouter(h).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		h := handler.NewSimilarityHandler(usecase.NewSimilarityUseCase(nil, nil, nil, nil, nil, usecase.UploadPolicy{}))
+		req := httptest.NewRequest(http.MethodPut, "/phrases/2/reference", nil)
+		rr := httptest.NewRecorder()
+		newSimilarityRouter(h).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("delete reference", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		s := storageMocks.NewMockStorage(t)
+		q := queueMocks.NewMockTaskQueue(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, ReferencePath: "audio/references/2.wav"}, nil)
+		s.On("Delete", mock.Anything, "audio/references/2.wav").Return(nil)
+		phraseRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		q.On("Enqueue", mock.Anything, uint(2)).Return(nil)
+		h := handler.NewSimilarityHandler(usecase.NewSimilarityUseCase(nil, phraseRepo, s, nil, q, usecase.UploadPolicy{}))
+
+		req := httptest.NewRequest(http.MethodDelete, "/phrases/2/reference", nil)
+		rr := httptest.NewRecorder()
+		newSimilarityRouter(h).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("delete missing reference", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+		h := handler.NewSimilarityHandler(usecase.NewSimilarityUseCase(nil, phraseRepo, nil, nil, nil, usecase.UploadPolicy{}))
+
+		req := httptest.NewRequest(http.MethodDelete, "/phrases/2/reference", nil)
+		rr := httptest.NewRecorder()
+		newSimilarityRouter(h).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
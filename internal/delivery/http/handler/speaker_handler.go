@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// SpeakerHandler serves the speaker attribute schema and the reports of how
+// speakers and their recordings are spread over the attribute values.
+type SpeakerHandler struct {
+	speakerReportUseCase *usecase.SpeakerReportUseCase
+}
+
+// NewSpeakerHandler creates a new SpeakerHandler.
+func NewSpeakerHandler(speakerReportUseCase *usecase.SpeakerReportUseCase) *SpeakerHandler {
+	return &SpeakerHandler{
+		speakerReportUseCase: speakerReportUseCase,
+	}
+}
+
+type speakerAttributeResponse struct {
+	Name      string   `json:"name"`
+	Values    []string `json:"values,omitempty"`
+	MaxLength int      `json:"max_length,omitempty"`
+}
+
+type speakerValueResponse struct {
+	Value         string  `json:"value"`
+	Speakers      int     `json:"speakers"`
+	Recordings    int     `json:"recordings"`
+	RecordedHours float64 `json:"recorded_hours"`
+}
+
+type speakerReportResponse struct {
+	Attribute     string                 `json:"attribute"`
+	Speakers      int                    `json:"speakers"`
+	Recordings    int                    `json:"recordings"`
+	RecordedHours float64                `json:"recorded_hours"`
+	Values        []speakerValueResponse `json:"values"`
+}
+
+func newSpeakerValueResponse(stats *entity.SpeakerAttributeStats) speakerValueResponse {
+	return speakerValueResponse{
+		Value:         stats.Value,
+		Speakers:      stats.Speakers,
+		Recordings:    stats.Recordings,
+		RecordedHours: stats.RecordedDuration / 3600,
+	}
+}
+
+func newSpeakerReportResponse(report *usecase.SpeakerAttributeReport) speakerReportResponse {
+	response := speakerReportResponse{
+		Attribute: report.Attribute,
+		Values:    make([]speakerValueResponse, 0, len(report.Values)),
+	}
+	for _, stats := range report.Values {
+		value := newSpeakerValueResponse(stats)
+		response.Speakers += value.Speakers
+		response.Recordings += value.Recordings
+		response.RecordedHours += value.RecordedHours
+		response.Values = append(response.Values, value)
+	}
+	return response
+}
+
+// Schema returns the attributes speakers may be described with, ordered by
+// name. Attributes without values accept any text.
+func (h *SpeakerHandler) Schema(w http.ResponseWriter, r *http.Request) {
+	schema := h.speakerReportUseCase.Schema()
+	response := make([]speakerAttributeResponse, 0, len(schema))
+	for _, name := range schema.Names() {
+		attribute := schema[name]
+		item := speakerAttributeResponse{Name: name, Values: attribute.Values}
+		if len(attribute.Values) == 0 {
+			item.MaxLength = attribute.MaxLength
+			if item.MaxLength == 0 {
+				item.MaxLength = usecase.MaxSpeakerAttributeLength
+			}
+		}
+		response = append(response, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Report returns, for every speaker attribute, the number of speakers with
+// each value and the takes and hours they recorded.
+func (h *SpeakerHandler) Report(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.speakerReportUseCase.Report(r.Context())
+	if err != nil {
+		logger.Errorf("Failed to get speaker report: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := make([]speakerReportResponse, 0, len(reports))
+	for _, report := range reports {
+		response = append(response, newSpeakerReportResponse(report))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// AttributeReport returns the report of the speaker attribute in the path.
+func (h *SpeakerHandler) AttributeReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.speakerReportUseCase.Attribute(r.Context(), mux.Vars(r)["attribute"])
+	if err != nil {
+		logger.Errorf("Failed to get speaker report: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newSpeakerReportResponse(report)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/delivery/http/router"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpeakerTestRouter(t *testing.T) (*mux.Router, *repoMocks.MockUserRepository) {
+	userRepo := repoMocks.NewMockUserRepository(t)
+	schema := usecase.SpeakerSchema{
+		"gender": {Values: []string{"female", "male"}},
+		"accent": {MaxLength: 40},
+		"device": {},
+	}
+	h := handler.NewSpeakerHandler(usecase.NewSpeakerReportUseCase(userRepo, schema))
+	return router.SetupRoutes(router.Handlers{Speaker: h}), userRepo
+}
+
+func TestSpeakerHandler_Schema(t *testing.T) {
+	router, _ := newSpeakerTestRouter(t)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/speaker-attributes", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[
+		{"name": "accent", "max_length": 40},
+		{"name": "device", "max_length": 100},
+		{"name": "gender", "values": ["female", "male"]}
+	]`, rr.Body.String())
+}
+
+func TestSpeakerHandler_AttributeReport(t *testing.T) {
+	t.Run("report", func(t *testing.T) {
+		router, userRepo := newSpeakerTestRouter(t)
+		userRepo.On("AttributeStats", mock.Anything, "gender").Return([]*entity.SpeakerAttributeStats{
+			{Value: "", Speakers: 1, Recordings: 2, RecordedDuration: 1800},
+			{Value: "female", Speakers: 2, Recordings: 10, RecordedDuration: 7200},
+		}, nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reports/speakers/gender", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{
+			"attribute": "gender", "speakers": 3, "recordings": 12, "recorded_hours": 2.5,
+			"values": [
+				{"value": "female", "speakers": 2, "recordings": 10, "recorded_hours": 2},
+				{"value": "male", "speakers": 0, "recordings": 0, "recorded_hours": 0},
+				{"value": "", "speakers": 1, "recordings": 2, "recorded_hours": 0.5}
+			]
+		}`, rr.Body.String())
+	})
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		router, _ := newSpeakerTestRouter(t)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reports/speakers/height", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestSpeakerHandler_Report(t *testing.T) {
+	router, userRepo := newSpeakerTestRouter(t)
+	userRepo.On("AttributeStats", mock.Anything, mock.AnythingOfType("string")).Return([]*entity.SpeakerAttributeStats{}, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reports/speakers", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response, 3)
+	assert.Equal(t, "accent", response[0]["attribute"])
+	assert.Equal(t, []interface{}{}, response[0]["values"])
+	assert.Len(t, response[2]["values"], 2)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// SpectrogramHandler serves spectrogram images of converted audio.
+type SpectrogramHandler struct {
+	spectrogramUseCase *usecase.SpectrogramUseCase
+}
+
+// NewSpectrogramHandler creates a new SpectrogramHandler.
+func NewSpectrogramHandler(spectrogramUseCase *usecase.SpectrogramUseCase) *SpectrogramHandler {
+	return &SpectrogramHandler{
+		spectrogramUseCase: spectrogramUseCase,
+	}
+}
+
+// Get returns the spectrogram of an audio as a PNG image. The fft_size, hop,
+// scale, min_db, max_db, color_map, width and height query parameters
+// override the configured options.
+func (h *SpectrogramHandler) Get(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	options := h.spectrogramUseCase.Options()
+	query := r.URL.Query()
+	if v := query.Get("scale"); v != "" {
+		options.Scale = v
+	}
+	if v := query.Get("color_map"); v != "" {
+		options.ColorMap = v
+	}
+	for param, target := range map[string]*float64{"min_db": &options.MinDB, "max_db": &options.MaxDB} {
+		if v := query.Get(param); v != "" {
+			if *target, err = strconv.ParseFloat(v, 64); err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	for param, target := range map[string]*int{"fft_size": &options.FFTSize, "hop": &options.Hop, "width": &options.Width, "height": &options.Height} {
+		if v := query.Get(param); v != "" {
+			if *target, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	reader, err := h.spectrogramUseCase.Get(r.Context(), uint(audioID), options)
+	if err != nil {
+		logger.Errorf("Failed to get spectrogram: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+	defer reader.Close()
+
+	w.Header().Set("Content-Type", "image/png")
+	if _, err := io.Copy(w, reader); err != nil {
+		logger.Errorf("Failed to write spectrogram: %v", err)
+	}
+}
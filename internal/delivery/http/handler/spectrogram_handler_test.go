@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/spectrogram"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpectrogramHandler_Get(t *testing.T) {
+	var source bytes.Buffer
+	require.NoError(t, wav.Encode(&source, 8000, 1, make([]float64, 800)))
+	completed := &entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/1.wav"}
+
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expectedStatus int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{
+			name: "configured options",
+			path: "/audio/1/spectrogram.png",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/spectrogram/1/fft256-hop64-linear-db-100_0-viridis-40x20.png").Return(nil, fmt.Errorf("open: %w", storage.ErrObjectNotFound))
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+				s.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedWidth:  40,
+			expectedHeight: 20,
+		},
+		{
+			name: "overridden options",
+			path: "/audio/1/spectrogram.png?scale=mel&color_map=gray&fft_size=128&width=16&height=12&min_db=-80",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/spectrogram/1/fft128-hop64-mel-db-80_0-gray-16x12.png").Return(nil, fmt.Errorf("open: %w", storage.ErrObjectNotFound))
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+				s.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedWidth:  16,
+			expectedHeight: 12,
+		},
+		{
+			name:           "malformed parameter",
+			path:           "/audio/1/spectrogram.png?width=wide",
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid options",
+			path:           "/audio/1/spectrogram.png?fft_size=1000",
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "level not a number",
+			path:           "/audio/1/spectrogram.png?min_db=NaN",
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "still converting",
+			path: "/audio/1/spectrogram.png",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			h := handler.NewSpectrogramHandler(usecase.NewSpectrogramUseCase(repo, s, spectrogram.Options{
+				FFTSize: 256, Hop: 64, Scale: spectrogram.ScaleLinear, MinDB: -100, MaxDB: 0, ColorMap: "viridis", Width: 40, Height: 20,
+			}))
+			router := mux.NewRouter()
+			router.HandleFunc("/audio/{audio_id:[0-9]+}/spectrogram.png", h.Get).Methods(http.MethodGet)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+				img, err := png.Decode(rr.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedWidth, img.Bounds().Dx())
+				assert.Equal(t, tt.expectedHeight, img.Bounds().Dy())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// TrimHandler re-applies silence trimming to converted audio.
+type TrimHandler struct {
+	trimUseCase *usecase.TrimUseCase
+}
+
+// NewTrimHandler creates a new TrimHandler.
+func NewTrimHandler(trimUseCase *usecase.TrimUseCase) *TrimHandler {
+	return &TrimHandler{
+		trimUseCase: trimUseCase,
+	}
+}
+
+// TrimRequest overrides the configured silence detection options. Omitted
+// fields keep their configured values.
+type TrimRequest struct {
+	ThresholdDB  *float64 `json:"threshold_db"`
+	MinSilenceMS *int64   `json:"min_silence_ms"`
+	PaddingMS    *int64   `json:"padding_ms"`
+}
+
+// Retrim detects silence again with the requested options and returns the
+// audio with its new trim offsets.
+func (h *TrimHandler) Retrim(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	var req TrimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Errorf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	options := h.trimUseCase.Options()
+	if req.ThresholdDB != nil {
+		options.ThresholdDB = *req.ThresholdDB
+	}
+	if req.MinSilenceMS != nil {
+		options.MinDuration = time.Duration(*req.MinSilenceMS) * time.Millisecond
+	}
+	if req.PaddingMS != nil {
+		options.Padding = time.Duration(*req.PaddingMS) * time.Millisecond
+	}
+
+	audio, err := h.trimUseCase.Retrim(r.Context(), uint(audioID), options)
+	if err != nil {
+		logger.Errorf("Failed to trim audio: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAudioResponse(audio)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
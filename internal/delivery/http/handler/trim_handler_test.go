@@ -0,0 +1,110 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/silence"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimHandler_Retrim(t *testing.T) {
+	// 1 kHz mono: 400ms of silence, 200ms of tone, 400ms of silence.
+	samples := make([]float64, 1000)
+	for i := 400; i < 600; i++ {
+		samples[i] = 0.5
+	}
+	var source bytes.Buffer
+	require.NoError(t, wav.Encode(&source, 1000, 1, samples))
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expectedStatus int
+		expectedTrim   map[string]interface{}
+	}{
+		{
+			name: "configured options",
+			body: "",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/1.wav"}, nil)
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTrim:   map[string]interface{}{"start": 0.35, "end": 0.65},
+		},
+		{
+			name: "overridden padding",
+			body: `{"padding_ms": 0}`,
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/1.wav"}, nil)
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTrim:   map[string]interface{}{"start": 0.4, "end": 0.6},
+		},
+		{
+			name:           "invalid threshold",
+			body:           `{"threshold_db": 6}`,
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `{`,
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "still converting",
+			body: "{}",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			h := handler.NewTrimHandler(usecase.NewTrimUseCase(repo, s, silence.Options{ThresholdDB: -50, MinDuration: 200 * time.Millisecond, Padding: 50 * time.Millisecond}))
+			router := mux.NewRouter()
+			router.HandleFunc("/audio/{audio_id:[0-9]+}/trim", h.Retrim).Methods(http.MethodPost)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/audio/1/trim", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedTrim != nil {
+				var got map[string]interface{}
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				trim, ok := got["trim"].(map[string]interface{})
+				require.True(t, ok, "response has no trim: %v", got)
+				assert.InDelta(t, tt.expectedTrim["start"], trim["start"], 1e-9)
+				assert.InDelta(t, tt.expectedTrim["end"], trim["end"], 1e-9)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,termination,checksum,expiration"
+
+	// statusChecksumMismatch is the tus-specific status for a failed chunk checksum.
+	statusChecksumMismatch = 460
+)
+
+// TusHandler implements the tus 1.0 resumable upload protocol on top of the
+// regular audio upload path.
+type TusHandler struct {
+	resumableUseCase *usecase.ResumableUploadUseCase
+}
+
+// NewTusHandler creates a new TusHandler.
+func NewTusHandler(resumableUseCase *usecase.ResumableUploadUseCase) *TusHandler {
+	return &TusHandler{
+		resumableUseCase: resumableUseCase,
+	}
+}
+
+// Options advertises the supported protocol version and extensions.
+func (h *TusHandler) Options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Checksum-Algorithm", strings.Join(usecase.ChecksumAlgorithms, ","))
+	if maxSize := h.resumableUseCase.MaxSize(); maxSize > 0 {
+		w.Header().Set("Tus-Max-Size", strconv.FormatInt(maxSize, 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Create starts a new upload for the user and phrase in the path.
+func (h *TusHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVersion(w, r) {
+		return
+	}
+
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	phraseID, err := strconv.ParseUint(mux.Vars(r)["phrase_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid phrase ID", http.StatusBadRequest)
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		http.Error(w, "Invalid Upload-Length", http.StatusBadRequest)
+		return
+	}
+
+	rawMetadata := r.Header.Get("Upload-Metadata")
+	metadata, err := parseUploadMetadata(rawMetadata)
+	if err != nil {
+		http.Error(w, "Invalid Upload-Metadata", http.StatusBadRequest)
+		return
+	}
+	filename := metadata["filename"]
+	if filename == "" {
+		filename = metadata["name"]
+	}
+	sessionID, err := parseSessionID(metadata["session_id"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	upload, err := h.resumableUseCase.Create(r.Context(), uint(userID), uint(phraseID), sessionID, length, filename, rawMetadata)
+	if err != nil {
+		logger.Errorf("Failed to create upload: %v", err)
+		http.Error(w, err.Error(), tusStatusFromError(err))
+		return
+	}
+
+	h.writeUploadHeaders(w, upload)
+	if upload.AudioID != nil {
+		w.Header().Set("X-Audio-Id", strconv.FormatUint(uint64(*upload.AudioID), 10))
+	}
+	w.Header().Set("Location", "/uploads/"+upload.ID)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Head reports the current offset of an upload.
+func (h *TusHandler) Head(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVersion(w, r) {
+		return
+	}
+
+	upload, err := h.resumableUseCase.Get(r.Context(), mux.Vars(r)["upload_id"])
+	if err != nil {
+		w.WriteHeader(tusStatusFromError(err))
+		return
+	}
+
+	h.writeUploadHeaders(w, upload)
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	if upload.Metadata != "" {
+		w.Header().Set("Upload-Metadata", upload.Metadata)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// Patch appends a chunk to an upload.
+func (h *TusHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVersion(w, r) {
+		return
+	}
+
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		http.Error(w, "Content-Type must be application/offset+octet-stream", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		http.Error(w, "Invalid Upload-Offset", http.StatusBadRequest)
+		return
+	}
+
+	checksum, err := parseUploadChecksum(r.Header.Get("Upload-Checksum"))
+	if err != nil {
+		http.Error(w, "Invalid Upload-Checksum", http.StatusBadRequest)
+		return
+	}
+
+	upload, audio, err := h.resumableUseCase.WriteChunk(r.Context(), mux.Vars(r)["upload_id"], offset, r.Body, checksum)
+	if err != nil {
+		logger.Errorf("Failed to write chunk: %v", err)
+		writeUploadError(w, err, tusStatusFromError(err))
+		return
+	}
+
+	h.writeUploadHeaders(w, upload)
+	if audio != nil {
+		w.Header().Set("X-Audio-Id", strconv.FormatUint(uint64(audio.ID), 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delete terminates an upload.
+func (h *TusHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVersion(w, r) {
+		return
+	}
+
+	if err := h.resumableUseCase.Terminate(r.Context(), mux.Vars(r)["upload_id"]); err != nil {
+		logger.Errorf("Failed to terminate upload: %v", err)
+		http.Error(w, err.Error(), tusStatusFromError(err))
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkVersion rejects requests for an unsupported protocol version.
+func (h *TusHandler) checkVersion(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		http.Error(w, "Unsupported tus version", http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+func (h *TusHandler) writeUploadHeaders(w http.ResponseWriter, upload *entity.Upload) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	if expiresAt, ok := h.resumableUseCase.ExpiresAt(upload); ok {
+		w.Header().Set("Upload-Expires", expiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+func tusStatusFromError(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrUploadOffsetMismatch):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrChecksumMismatch):
+		return statusChecksumMismatch
+	case errors.Is(err, usecase.ErrUploadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return statusFromError(err)
+	}
+}
+
+// parseUploadMetadata decodes an Upload-Metadata header of comma separated
+// "key base64value" pairs.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if header == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.Fields(pair)
+		switch len(parts) {
+		case 1:
+			metadata[parts[0]] = ""
+		case 2:
+			value, err := base64.StdEncoding.DecodeString(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %q: %v", parts[0], err)
+			}
+			metadata[parts[0]] = string(value)
+		default:
+			return nil, fmt.Errorf("invalid metadata pair %q", pair)
+		}
+	}
+	return metadata, nil
+}
+
+// parseUploadChecksum decodes an Upload-Checksum header of the form
+// "algorithm base64digest". An empty header yields a nil checksum.
+func parseUploadChecksum(header string) (*usecase.UploadChecksum, error) {
+	if header == "" {
+		return nil, nil
+	}
+	parts := strings.Fields(header)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid checksum %q", header)
+	}
+	sum, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid checksum digest: %v", err)
+	}
+	return &usecase.UploadChecksum{Algorithm: parts[0], Sum: sum}, nil
+}
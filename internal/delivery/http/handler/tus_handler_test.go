@@ -0,0 +1,171 @@
+package handler_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/infrastructure/storage"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTusHandler(t *testing.T) {
+	mockUploadRepo := repoMocks.NewMockUploadRepository(t)
+	mockAudioRepo := repoMocks.NewMockAudioRepository(t)
+	mockUserRepo := repoMocks.NewMockUserRepository(t)
+	mockPhraseRepo := repoMocks.NewMockPhraseRepository(t)
+	mockStorage := storageMocks.NewMockStorage(t)
+	mockQueue := queueMocks.NewMockTaskQueue(t)
+	mockConverter := converterMocks.NewMockAudioConverter(t)
+
+	staging, err := storage.NewLocalStagingArea(t.TempDir())
+	require.NoError(t, err)
+
+	uploads := map[string]*entity.Upload{}
+	mockUserRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	mockPhraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+	mockAudioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(nil, repository.ErrNotFound)
+	mockUploadRepo.On("Create", mock.Anything, mock.Anything).Return(func(_ context.Context, u *entity.Upload) (*entity.Upload, error) {
+		uploads[u.ID] = u
+		return u, nil
+	})
+	mockUploadRepo.On("GetByID", mock.Anything, mock.Anything).Return(func(_ context.Context, id string) (*entity.Upload, error) {
+		if u, ok := uploads[id]; ok {
+			return u, nil
+		}
+		return nil, repository.ErrNotFound
+	})
+	mockUploadRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	uploadUseCase := usecase.NewUploadAudioUseCase(mockAudioRepo, mockStorage, mockConverter, mockQueue, mockUserRepo, mockPhraseRepo, repoMocks.NewMockRecordingSessionRepository(t), repoMocks.NewMockAudioReviewRepository(t), usecase.UploadPolicy{})
+	resumableUseCase := usecase.NewResumableUploadUseCase(mockUploadRepo, staging, mockUserRepo, mockPhraseRepo, uploadUseCase, 1024, 0)
+	h := handler.NewTusHandler(resumableUseCase)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/audio/user/{user_id}/phrase/{phrase_id}/uploads", h.Create).Methods(http.MethodPost)
+	router.HandleFunc("/uploads/{upload_id}", h.Head).Methods(http.MethodHead)
+	router.HandleFunc("/uploads/{upload_id}", h.Patch).Methods(http.MethodPatch)
+	router.HandleFunc("/uploads/{upload_id}", h.Options).Methods(http.MethodOptions)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("options advertises extensions", func(t *testing.T) {
+		rr := serve(httptest.NewRequest(http.MethodOptions, "/uploads/x", nil))
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "1.0.0", rr.Header().Get("Tus-Version"))
+		assert.Contains(t, rr.Header().Get("Tus-Extension"), "checksum")
+		assert.Contains(t, rr.Header().Get("Tus-Extension"), "expiration")
+		assert.Equal(t, "1024", rr.Header().Get("Tus-Max-Size"))
+	})
+
+	t.Run("missing tus version", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/audio/user/1/phrase/1/uploads", nil)
+		req.Header.Set("Upload-Length", "10")
+		rr := serve(req)
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	})
+
+	t.Run("upload too large", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/audio/user/1/phrase/1/uploads", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Length", "4096")
+		rr := serve(req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("empty upload goes through the upload checks", func(t *testing.T) {
+		mockUploadRepo.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+		req := httptest.NewRequest(http.MethodPost, "/audio/user/1/phrase/1/uploads", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Length", "0")
+		rr := serve(req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+
+	t.Run("full upload", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/audio/user/1/phrase/1/uploads", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Length", "11")
+		req.Header.Set("Upload-Metadata", "filename "+base64.StdEncoding.EncodeToString([]byte("take.m4a")))
+		rr := serve(req)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		location := rr.Header().Get("Location")
+		require.True(t, strings.HasPrefix(location, "/uploads/"))
+
+		patch := func(offset, body, checksum string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPatch, location, strings.NewReader(body))
+			req.Header.Set("Tus-Resumable", "1.0.0")
+			req.Header.Set("Content-Type", "application/offset+octet-stream")
+			req.Header.Set("Upload-Offset", offset)
+			if checksum != "" {
+				req.Header.Set("Upload-Checksum", checksum)
+			}
+			return serve(req)
+		}
+
+		rr = patch("0", "ID3lo ", "")
+		require.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "6", rr.Header().Get("Upload-Offset"))
+
+		head := httptest.NewRequest(http.MethodHead, location, nil)
+		head.Header.Set("Tus-Resumable", "1.0.0")
+		rr = serve(head)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "6", rr.Header().Get("Upload-Offset"))
+		assert.Equal(t, "11", rr.Header().Get("Upload-Length"))
+
+		rr = patch("0", "ID3lo ", "")
+		assert.Equal(t, http.StatusConflict, rr.Code)
+
+		bad := sha1.Sum([]byte("nope"))
+		rr = patch("6", "world", "sha1 "+base64.StdEncoding.EncodeToString(bad[:]))
+		assert.Equal(t, 460, rr.Code)
+
+		mockConverter.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "m4a", Codec: "aac"}, nil)
+		mockAudioRepo.On("Store", mock.Anything, mock.MatchedBy(func(a *entity.Audio) bool {
+			return a.OriginalName == "take.m4a"
+		})).Return(&entity.Audio{ID: 3, Status: entity.AudioStatusPending}, nil)
+		mockStorage.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockQueue.On("Enqueue", mock.Anything, uint(3)).Return(nil)
+
+		good := sha1.Sum([]byte("world"))
+		rr = patch("6", "world", "sha1 "+base64.StdEncoding.EncodeToString(good[:]))
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "11", rr.Header().Get("Upload-Offset"))
+		assert.Equal(t, "3", rr.Header().Get("X-Audio-Id"))
+	})
+
+	t.Run("wrong content type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/uploads/x", strings.NewReader("x"))
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		req.Header.Set("Upload-Offset", "0")
+		rr := serve(req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+
+	t.Run("unknown upload", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodHead, "/uploads/missing", nil)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		rr := serve(req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
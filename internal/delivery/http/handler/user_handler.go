@@ -2,29 +2,113 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/internal/usecase"
 	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
 )
 
 type UserHandler struct {
-	createUserUseCase *usecase.CreateUserUseCase
+	createUserUseCase           *usecase.CreateUserUseCase
+	setProcessingProfileUseCase *usecase.SetProcessingProfileUseCase
+	getUserUseCase              *usecase.GetUserUseCase
+	updateUserUseCase           *usecase.UpdateUserUseCase
+	deleteUserUseCase           *usecase.DeleteUserUseCase
 }
 
-func NewUserHandler(createUserUseCase *usecase.CreateUserUseCase) *UserHandler {
+func NewUserHandler(
+	createUserUseCase *usecase.CreateUserUseCase,
+	setProcessingProfileUseCase *usecase.SetProcessingProfileUseCase,
+	getUserUseCase *usecase.GetUserUseCase,
+	updateUserUseCase *usecase.UpdateUserUseCase,
+	deleteUserUseCase *usecase.DeleteUserUseCase,
+) *UserHandler {
 	return &UserHandler{
-		createUserUseCase: createUserUseCase,
+		createUserUseCase:           createUserUseCase,
+		setProcessingProfileUseCase: setProcessingProfileUseCase,
+		getUserUseCase:              getUserUseCase,
+		updateUserUseCase:           updateUserUseCase,
+		deleteUserUseCase:           deleteUserUseCase,
 	}
 }
 
 type CreateUserRequest struct {
-	Name string `json:"name"`
+	Name              string            `json:"name"`
+	ProcessingProfile string            `json:"processing_profile"`
+	Attributes        map[string]string `json:"attributes"`
 }
 
 type CreateUserResponse struct {
-	ID   uint   `json:"id"`
-	Name string `json:"name"`
+	ID                uint              `json:"id"`
+	Name              string            `json:"name"`
+	ProcessingProfile string            `json:"processing_profile,omitempty"`
+	Attributes        map[string]string `json:"attributes"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+func newUserResponse(user *entity.User) CreateUserResponse {
+	attributes := user.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return CreateUserResponse{
+		ID:                user.ID,
+		Name:              user.Name,
+		ProcessingProfile: user.ProcessingProfile,
+		Attributes:        attributes,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
+	}
+}
+
+type SetProcessingProfileRequest struct {
+	ProcessingProfile string `json:"processing_profile"`
+}
+
+// UpdateUserRequest holds the fields to change; omitted fields are kept.
+// Attributes, when given, replace all speaker attributes.
+type UpdateUserRequest struct {
+	Name              *string           `json:"name"`
+	ProcessingProfile *string           `json:"processing_profile"`
+	Attributes        map[string]string `json:"attributes"`
+}
+
+type userListResponse struct {
+	Users  []CreateUserResponse `json:"users"`
+	Total  int                  `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+type userDeletionResponse struct {
+	ID        uint                      `json:"id"`
+	UserID    uint                      `json:"user_id"`
+	Status    entity.UserDeletionStatus `json:"status"`
+	Phrases   int                       `json:"phrases"`
+	Audio     int                       `json:"audio"`
+	Error     string                    `json:"error,omitempty"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+func newUserDeletionResponse(d *entity.UserDeletion) userDeletionResponse {
+	return userDeletionResponse{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		Status:    d.Status,
+		Phrases:   d.Phrases,
+		Audio:     d.Audio,
+		Error:     d.Error,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
 }
 
 func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -46,17 +130,18 @@ func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.createUserUseCase.Create(r.Context(), req.Name)
+	user, err := h.createUserUseCase.Create(r.Context(), req.Name, req.ProcessingProfile, req.Attributes)
 	if err != nil {
 		logger.Errorf("Failed to create user: %v", err)
+		if errors.Is(err, usecase.ErrInvalidArgument) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Failed to create user", http.StatusInternalServerError)
 		return
 	}
 
-	response := CreateUserResponse{
-		ID:   user.ID,
-		Name: user.Name,
-	}
+	response := newUserResponse(user)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -65,3 +150,166 @@ func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// SetProcessingProfile changes the processing profile applied to a user's downloads.
+func (h *UserHandler) SetProcessingProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req SetProcessingProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.setProcessingProfileUseCase.Set(r.Context(), uint(userID), req.ProcessingProfile)
+	if err != nil {
+		logger.Errorf("Failed to set processing profile: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newUserResponse(user)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Get returns a user.
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.getUserUseCase.Get(r.Context(), uint(userID))
+	if err != nil {
+		logger.Errorf("Failed to get user: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newUserResponse(user)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// List returns a page of users ordered by ID. The name query parameter
+// searches the names, and limit and offset select the page.
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var limit, offset int
+	for param, target := range map[string]*int{"limit": &limit, "offset": &offset} {
+		if v := query.Get(param); v != "" {
+			var err error
+			if *target, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	page, err := h.getUserUseCase.List(r.Context(), query.Get("name"), limit, offset)
+	if err != nil {
+		logger.Errorf("Failed to list users: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	response := userListResponse{
+		Users:  make([]CreateUserResponse, 0, len(page.Users)),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	for _, user := range page.Users {
+		response.Users = append(response.Users, newUserResponse(user))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Update changes the name, processing profile or speaker attributes of a user.
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.updateUserUseCase.Update(r.Context(), uint(userID), usecase.UserUpdate{
+		Name:              req.Name,
+		ProcessingProfile: req.ProcessingProfile,
+		Attributes:        req.Attributes,
+	})
+	if err != nil {
+		logger.Errorf("Failed to update user: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newUserResponse(user)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// Delete queues the deletion of a user and everything they own, and responds
+// with 202 Accepted and the deletion to poll.
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(mux.Vars(r)["user_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	deletion, err := h.deleteUserUseCase.Delete(r.Context(), uint(userID))
+	if err != nil {
+		logger.Errorf("Failed to delete user: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/user_deletions/%d", deletion.ID))
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(newUserDeletionResponse(deletion)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
+
+// GetDeletion returns the status of a user deletion.
+func (h *UserHandler) GetDeletion(w http.ResponseWriter, r *http.Request) {
+	deletionID, err := strconv.ParseUint(mux.Vars(r)["deletion_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid deletion ID", http.StatusBadRequest)
+		return
+	}
+
+	deletion, err := h.deleteUserUseCase.Get(r.Context(), uint(deletionID))
+	if err != nil {
+		logger.Errorf("Failed to get user deletion: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newUserDeletionResponse(deletion)); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+	}
+}
@@ -3,15 +3,23 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/ardfard/sb-test/internal/delivery/http/handler"
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
 	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
 	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUserHandler_Create(t *testing.T) {
@@ -80,10 +88,10 @@ func TestUserHandler_Create(t *testing.T) {
 			}
 
 			// Create use case
-			createUserUseCase := usecase.NewCreateUserUseCase(mockUserRepo)
+			createUserUseCase := usecase.NewCreateUserUseCase(mockUserRepo, nil, nil)
 
 			// Create handler
-			handler := handler.NewUserHandler(createUserUseCase)
+			handler := handler.NewUserHandler(createUserUseCase, usecase.NewSetProcessingProfileUseCase(mockUserRepo, nil), nil, nil, nil)
 
 			// Create request
 			var req *http.Request
@@ -122,3 +130,232 @@ func TestUserHandler_Create(t *testing.T) {
 		})
 	}
 }
+
+type userHandlerMocks struct {
+	userRepo     *repoMocks.MockUserRepository
+	deletionRepo *repoMocks.MockUserDeletionRepository
+	queue        *queueMocks.MockTaskQueue
+}
+
+func newUserTestRouter(t *testing.T) (*mux.Router, userHandlerMocks) {
+	m := userHandlerMocks{
+		userRepo:     repoMocks.NewMockUserRepository(t),
+		deletionRepo: repoMocks.NewMockUserDeletionRepository(t),
+		queue:        queueMocks.NewMockTaskQueue(t),
+	}
+	profiles := usecase.ProcessingProfiles{"clean": {{Name: "highpass", Params: map[string]string{"f": "80"}}}}
+	speakerSchema := usecase.SpeakerSchema{"gender": {Values: []string{"female", "male"}}, "accent": {}}
+	deleteUserUseCase := usecase.NewDeleteUserUseCase(m.deletionRepo, m.userRepo,
+		repoMocks.NewMockPhraseRepository(t), repoMocks.NewMockAudioRepository(t), repoMocks.NewMockCompilationRepository(t), repoMocks.NewMockUploadRepository(t),
+		storageMocks.NewMockStorage(t), storageMocks.NewMockStagingArea(t), m.queue, queueMocks.NewMockTaskQueue(t), queueMocks.NewMockTaskQueue(t))
+	h := handler.NewUserHandler(
+		usecase.NewCreateUserUseCase(m.userRepo, profiles, speakerSchema),
+		usecase.NewSetProcessingProfileUseCase(m.userRepo, profiles),
+		usecase.NewGetUserUseCase(m.userRepo),
+		usecase.NewUpdateUserUseCase(m.userRepo, profiles, speakerSchema),
+		deleteUserUseCase,
+	)
+	router := mux.NewRouter()
+	router.HandleFunc("/users", h.List).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}", h.Get).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}", h.Update).Methods(http.MethodPatch)
+	router.HandleFunc("/users/{user_id:[0-9]+}", h.Delete).Methods(http.MethodDelete)
+	router.HandleFunc("/user_deletions/{deletion_id:[0-9]+}", h.GetDeletion).Methods(http.MethodGet)
+	return router, m
+}
+
+func TestUserHandler_Get(t *testing.T) {
+	router, m := newUserTestRouter(t)
+	m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Name: "John Doe", ProcessingProfile: "clean"}, nil)
+	m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("failed to get user 2: %w", repository.ErrNotFound))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got map[string]interface{}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Equal(t, "John Doe", got["name"])
+	assert.Equal(t, "clean", got["processing_profile"])
+	assert.Equal(t, map[string]interface{}{}, got["attributes"])
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/2", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestUserHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(userHandlerMocks)
+		expectedStatus int
+	}{
+		{
+			name:  "search page",
+			query: "?name=jo&limit=1&offset=1",
+			setupMocks: func(m userHandlerMocks) {
+				m.userRepo.On("List", mock.Anything, repository.UserFilter{Name: "jo", Limit: 1, Offset: 1}).
+					Return([]*entity.User{{ID: 2, Name: "Joan"}}, 2, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid limit",
+			query:          "?limit=many",
+			setupMocks:     func(userHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "limit too large",
+			query:          "?limit=1000",
+			setupMocks:     func(userHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newUserTestRouter(t)
+			tt.setupMocks(m)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users"+tt.query, nil))
+			require.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var got struct {
+				Users []map[string]interface{} `json:"users"`
+				Total int                      `json:"total"`
+				Limit int                      `json:"limit"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+			assert.Equal(t, 2, got.Total)
+			assert.Equal(t, 1, got.Limit)
+			require.Len(t, got.Users, 1)
+			assert.Equal(t, "Joan", got.Users[0]["name"])
+		})
+	}
+}
+
+func TestUserHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(userHandlerMocks)
+		expectedStatus int
+	}{
+		{
+			name: "rename",
+			body: `{"name":"Jane Doe"}`,
+			setupMocks: func(m userHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Name: "John Doe", ProcessingProfile: "clean"}, nil)
+				m.userRepo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Name == "Jane Doe" && user.ProcessingProfile == "clean"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "replace speaker attributes",
+			body: `{"attributes":{"gender":"Female","accent":" Scottish "}}`,
+			setupMocks: func(m userHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).
+					Return(&entity.User{ID: 1, Name: "John Doe", Attributes: map[string]string{"gender": "male"}}, nil)
+				m.userRepo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Name == "John Doe" && user.Attributes["gender"] == "female" && user.Attributes["accent"] == "Scottish"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown speaker attribute",
+			body:           `{"attributes":{"height":"tall"}}`,
+			setupMocks:     func(userHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "disallowed speaker attribute value",
+			body:           `{"attributes":{"gender":"robot"}}`,
+			setupMocks:     func(userHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown profile",
+			body:           `{"processing_profile":"studio"}`,
+			setupMocks:     func(userHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `{`,
+			setupMocks:     func(userHandlerMocks) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "user not found",
+			body: `{"name":"Jane Doe"}`,
+			setupMocks: func(m userHandlerMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get user 1: %w", repository.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newUserTestRouter(t)
+			tt.setupMocks(m)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestUserHandler_Delete(t *testing.T) {
+	t.Run("accepted", func(t *testing.T) {
+		router, m := newUserTestRouter(t)
+		m.userRepo.On("MarkDeleting", mock.Anything, uint(1)).Return(nil)
+		m.deletionRepo.On("Create", mock.Anything, mock.Anything).Return(&entity.UserDeletion{ID: 7, UserID: 1, Status: entity.UserDeletionStatusPending}, nil)
+		m.queue.On("Enqueue", mock.Anything, uint(7)).Return(nil)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/users/1", nil))
+		require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+		assert.Equal(t, "/user_deletions/7", rr.Header().Get("Location"))
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, "pending", got["status"])
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		router, m := newUserTestRouter(t)
+		m.userRepo.On("MarkDeleting", mock.Anything, uint(1)).Return(fmt.Errorf("failed to mark user 1 as deleting: %w", repository.ErrNotFound))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/users/1", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("deletion status", func(t *testing.T) {
+		router, m := newUserTestRouter(t)
+		m.deletionRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.UserDeletion{
+			ID: 7, UserID: 1, Status: entity.UserDeletionStatusCompleted, Phrases: 2, Audio: 5,
+		}, nil)
+		m.deletionRepo.On("GetByID", mock.Anything, uint(8)).Return(nil, fmt.Errorf("failed to get user deletion 8: %w", repository.ErrNotFound))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/user_deletions/7", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, "completed", got["status"])
+		assert.Equal(t, float64(5), got["audio"])
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/user_deletions/8", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
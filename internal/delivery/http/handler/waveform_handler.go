@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+// WaveformHandler serves peak data for drawing waveforms.
+type WaveformHandler struct {
+	waveformUseCase *usecase.WaveformUseCase
+}
+
+// NewWaveformHandler creates a new WaveformHandler.
+func NewWaveformHandler(waveformUseCase *usecase.WaveformUseCase) *WaveformHandler {
+	return &WaveformHandler{
+		waveformUseCase: waveformUseCase,
+	}
+}
+
+// Get returns the waveform of an audio as audiowaveform JSON, or in the
+// binary .dat format when requested with ?format=dat.
+func (h *WaveformHandler) Get(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["audio_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid audio ID", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "dat" {
+		http.Error(w, "Unsupported waveform format", http.StatusBadRequest)
+		return
+	}
+
+	waveform, err := h.waveformUseCase.Get(r.Context(), uint(audioID))
+	if err != nil {
+		logger.Errorf("Failed to get waveform: %v", err)
+		http.Error(w, err.Error(), statusFromError(err))
+		return
+	}
+
+	if format == "dat" {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if _, err := waveform.WriteTo(w); err != nil {
+			logger.Errorf("Failed to write waveform: %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(waveform); err != nil {
+		logger.Errorf("Failed to encode waveform: %v", err)
+	}
+}
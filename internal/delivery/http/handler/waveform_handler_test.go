@@ -0,0 +1,111 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/delivery/http/handler"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/waveform"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaveformHandler_Get(t *testing.T) {
+	stored := &waveform.Waveform{Version: 2, Channels: 1, SampleRate: 44100, SamplesPerPixel: 441, Bits: 8, Length: 2, Data: []int16{-3, 4, -5, 6}}
+	var dat bytes.Buffer
+	_, err := stored.WriteTo(&dat)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expectedStatus int
+		check          func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "json",
+			path: "/audio/1/waveform",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusCompleted}, nil)
+				s.On("Download", mock.Anything, "audio/waveform/1.dat").Return(io.NopCloser(bytes.NewReader(dat.Bytes())), nil)
+			},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+				var got map[string]interface{}
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, float64(2), got["version"])
+				assert.Equal(t, float64(441), got["samples_per_pixel"])
+				assert.Equal(t, float64(8), got["bits"])
+				assert.Equal(t, []interface{}{float64(-3), float64(4), float64(-5), float64(6)}, got["data"])
+			},
+		},
+		{
+			name: "binary",
+			path: "/audio/1/waveform?format=dat",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusCompleted}, nil)
+				s.On("Download", mock.Anything, "audio/waveform/1.dat").Return(io.NopCloser(bytes.NewReader(dat.Bytes())), nil)
+			},
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, "application/octet-stream", rr.Header().Get("Content-Type"))
+				assert.Equal(t, dat.Bytes(), rr.Body.Bytes())
+			},
+		},
+		{
+			name:           "unsupported format",
+			path:           "/audio/1/waveform?format=png",
+			setupMocks:     func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "still converting",
+			path: "/audio/1/waveform",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "unknown audio",
+			path: "/audio/1/waveform",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, repository.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			h := handler.NewWaveformHandler(usecase.NewWaveformUseCase(repo, s, waveform.Options{PixelsPerSecond: 100, Bits: 8, Channels: 1}))
+			router := mux.NewRouter()
+			router.HandleFunc("/audio/{audio_id:[0-9]+}/waveform", h.Get).Methods(http.MethodGet)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.check != nil {
+				tt.check(t, rr)
+			}
+		})
+	}
+}
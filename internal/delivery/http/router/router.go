@@ -7,17 +7,123 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// Handlers groups the HTTP handlers the router dispatches to.
+type Handlers struct {
+	Audio       *handler.AudioHandler
+	User        *handler.UserHandler
+	Phrase      *handler.PhraseHandler
+	Share       *handler.ShareHandler
+	Tus         *handler.TusHandler
+	Waveform    *handler.WaveformHandler
+	Trim        *handler.TrimHandler
+	Clip        *handler.ClipHandler
+	Compilation *handler.CompilationHandler
+	Segmented   *handler.SegmentedUploadHandler
+	Analysis    *handler.AnalysisHandler
+	Spectrogram *handler.SpectrogramHandler
+	Pitch       *handler.PitchHandler
+	Similarity  *handler.SimilarityHandler
+	Collection  *handler.CollectionHandler
+	Progress    *handler.ProgressHandler
+	Review      *handler.ReviewHandler
+	Annotation  *handler.AnnotationHandler
+	Speaker     *handler.SpeakerHandler
+	Session     *handler.RecordingSessionHandler
+}
+
 // SetupRoutes sets up all the HTTP routes for the application.
-func SetupRoutes(audioHandler *handler.AudioHandler, userHandler *handler.UserHandler, phraseHandler *handler.PhraseHandler) *mux.Router {
+func SetupRoutes(h Handlers) *mux.Router {
 	router := mux.NewRouter()
 
-	router.HandleFunc("/audio/user/{user_id}/phrase/{phrase_id}", audioHandler.UploadAudio).Methods(http.MethodPost)
-	router.HandleFunc("/audio/user/{user_id}/phrase/{phrase_id}/{format}", audioHandler.GetAudio).Methods(http.MethodGet)
+	router.HandleFunc("/audio/user/{user_id}/phrase/{phrase_id}", h.Audio.UploadAudio).Methods(http.MethodPost)
+	router.HandleFunc("/audio/user/{user_id:[0-9]+}/segmented", h.Segmented.Upload).Methods(http.MethodPost)
+	router.HandleFunc("/audio/user/{user_id}/phrase/{phrase_id}/{format}", h.Audio.GetAudio).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}", h.Audio.GetAudioInfo).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/waveform", h.Waveform.Get).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/spectrogram.png", h.Spectrogram.Get).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/pitch", h.Pitch.Get).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/trim", h.Trim.Retrim).Methods(http.MethodPost)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/clip", h.Clip.Get).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/analysis", h.Analysis.Get).Methods(http.MethodGet)
+
+	// Review routes
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/review", h.Review.Review).Methods(http.MethodPut)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/review", h.Review.Get).Methods(http.MethodGet)
+	router.HandleFunc("/reviews/queue", h.Review.Queue).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}/rejected-takes", h.Review.Rejected).Methods(http.MethodGet)
+
+	// Annotation routes
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/annotations", h.Annotation.Create).Methods(http.MethodPost)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/annotations", h.Annotation.List).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/annotations/export", h.Annotation.Export).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/annotations/{annotation_id:[0-9]+}", h.Annotation.Get).Methods(http.MethodGet)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/annotations/{annotation_id:[0-9]+}", h.Annotation.Update).Methods(http.MethodPatch)
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/annotations/{annotation_id:[0-9]+}", h.Annotation.Delete).Methods(http.MethodDelete)
+
+	// Resumable (tus) upload routes
+	router.HandleFunc("/audio/user/{user_id}/phrase/{phrase_id}/uploads", h.Tus.Create).Methods(http.MethodPost)
+	router.HandleFunc("/audio/user/{user_id}/phrase/{phrase_id}/uploads", h.Tus.Options).Methods(http.MethodOptions)
+	router.HandleFunc("/uploads/{upload_id}", h.Tus.Head).Methods(http.MethodHead)
+	router.HandleFunc("/uploads/{upload_id}", h.Tus.Patch).Methods(http.MethodPatch)
+	router.HandleFunc("/uploads/{upload_id}", h.Tus.Delete).Methods(http.MethodDelete)
+	router.HandleFunc("/uploads/{upload_id}", h.Tus.Options).Methods(http.MethodOptions)
 
-	router.HandleFunc("/users", userHandler.Create).Methods(http.MethodPost)
+	// Share link routes
+	router.HandleFunc("/audio/{audio_id:[0-9]+}/share", h.Share.Create).Methods(http.MethodPost)
+	router.HandleFunc("/share/audio/{audio_id:[0-9]+}/{format}", h.Share.Open).Methods(http.MethodGet)
+
+	router.HandleFunc("/users", h.User.Create).Methods(http.MethodPost)
+	router.HandleFunc("/users", h.User.List).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}", h.User.Get).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}", h.User.Update).Methods(http.MethodPatch)
+	router.HandleFunc("/users/{user_id:[0-9]+}", h.User.Delete).Methods(http.MethodDelete)
+	router.HandleFunc("/user_deletions/{deletion_id:[0-9]+}", h.User.GetDeletion).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}/processing_profile", h.User.SetProcessingProfile).Methods(http.MethodPut)
+	router.HandleFunc("/users/{user_id:[0-9]+}/progress", h.Progress.Get).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}/next-phrase", h.Progress.NextPhrase).Methods(http.MethodGet)
+
+	// Recording session routes
+	router.HandleFunc("/users/{user_id:[0-9]+}/sessions", h.Session.Open).Methods(http.MethodPost)
+	router.HandleFunc("/users/{user_id:[0-9]+}/sessions", h.Session.List).Methods(http.MethodGet)
+	router.HandleFunc("/sessions/{session_id:[0-9]+}", h.Session.Get).Methods(http.MethodGet)
+	router.HandleFunc("/sessions/{session_id:[0-9]+}/close", h.Session.Close).Methods(http.MethodPost)
+	router.HandleFunc("/sessions/{session_id:[0-9]+}/audio", h.Session.Takes).Methods(http.MethodGet)
+	router.HandleFunc("/sessions/{session_id:[0-9]+}/review", h.Review.ReviewSession).Methods(http.MethodPut)
+
+	// Speaker attribute routes
+	router.HandleFunc("/speaker-attributes", h.Speaker.Schema).Methods(http.MethodGet)
+	router.HandleFunc("/reports/speakers", h.Speaker.Report).Methods(http.MethodGet)
+	router.HandleFunc("/reports/speakers/{attribute}", h.Speaker.AttributeReport).Methods(http.MethodGet)
 
 	// Phrase routes
-	router.HandleFunc("/users/{user_id}/phrases", phraseHandler.Create).Methods(http.MethodPost)
+	router.HandleFunc("/users/{user_id}/phrases", h.Phrase.Create).Methods(http.MethodPost)
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases", h.Phrase.List).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases/import", h.Phrase.Import).Methods(http.MethodPost)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}", h.Phrase.Get).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases/{phrase_id:[0-9]+}", h.Phrase.Update).Methods(http.MethodPatch)
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases/{phrase_id:[0-9]+}", h.Phrase.Delete).Methods(http.MethodDelete)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}/revisions", h.Phrase.ListRevisions).Methods(http.MethodGet)
+	router.HandleFunc("/users/{user_id:[0-9]+}/phrases/{phrase_id:[0-9]+}/claim", h.Phrase.Claim).Methods(http.MethodPost)
+	router.HandleFunc("/users/{owner_id:[0-9]+}/phrases/{phrase_id:[0-9]+}/shares/{user_id:[0-9]+}", h.Phrase.Share).Methods(http.MethodPut)
+	router.HandleFunc("/users/{owner_id:[0-9]+}/phrases/{phrase_id:[0-9]+}/shares/{user_id:[0-9]+}", h.Phrase.Unshare).Methods(http.MethodDelete)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}/reference", h.Similarity.SetReference).Methods(http.MethodPut)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}/reference", h.Similarity.DeleteReference).Methods(http.MethodDelete)
+	router.HandleFunc("/phrases/{phrase_id:[0-9]+}/audio", h.Similarity.ListAudio).Methods(http.MethodGet)
+
+	// Compilation routes
+	router.HandleFunc("/users/{user_id:[0-9]+}/compilations", h.Compilation.Create).Methods(http.MethodPost)
+	router.HandleFunc("/compilations/{compilation_id:[0-9]+}", h.Compilation.Get).Methods(http.MethodGet)
+	router.HandleFunc("/compilations/{compilation_id:[0-9]+}/audio", h.Compilation.Download).Methods(http.MethodGet)
+	router.HandleFunc("/compilations/{compilation_id:[0-9]+}/manifest", h.Compilation.Manifest).Methods(http.MethodGet)
+
+	// Collection routes
+	router.HandleFunc("/users/{user_id:[0-9]+}/collections", h.Collection.Create).Methods(http.MethodPost)
+	router.HandleFunc("/users/{user_id:[0-9]+}/collections", h.Collection.List).Methods(http.MethodGet)
+	router.HandleFunc("/collections/{collection_id:[0-9]+}", h.Collection.Get).Methods(http.MethodGet)
+	router.HandleFunc("/users/{owner_id:[0-9]+}/collections/{collection_id:[0-9]+}/assignments/{user_id:[0-9]+}", h.Collection.Assign).Methods(http.MethodPut)
+	router.HandleFunc("/users/{owner_id:[0-9]+}/collections/{collection_id:[0-9]+}/assignments/{user_id:[0-9]+}", h.Collection.Unassign).Methods(http.MethodDelete)
+	router.HandleFunc("/audio/user/{user_id:[0-9]+}/collection/{collection_id:[0-9]+}/phrase/{position:[0-9]+}", h.Collection.UploadAudio).Methods(http.MethodPost)
+	router.HandleFunc("/audio/user/{user_id:[0-9]+}/collection/{collection_id:[0-9]+}/phrase/{position:[0-9]+}/{format}", h.Collection.GetAudio).Methods(http.MethodGet)
 
 	router.HandleFunc("/health", handler.HealthHandler).Methods("GET")
 	return router
@@ -13,12 +13,16 @@ import (
 
 func TestSetupRoutes(t *testing.T) {
 	// Create mock handlers
-	audioHandler := &handler.AudioHandler{}
-	userHandler := &handler.UserHandler{}
-	phraseHandler := &handler.PhraseHandler{}
+	handlers := Handlers{
+		Audio:  &handler.AudioHandler{},
+		User:   &handler.UserHandler{},
+		Phrase: &handler.PhraseHandler{},
+		Share:  &handler.ShareHandler{},
+		Tus:    &handler.TusHandler{},
+	}
 
 	// Setup router
-	router := SetupRoutes(audioHandler, userHandler, phraseHandler)
+	router := SetupRoutes(handlers)
 	require.NotNil(t, router)
 
 	// Test cases for route configuration
@@ -34,24 +38,390 @@ func TestSetupRoutes(t *testing.T) {
 			path:          "/audio/user/1/phrase/1",
 			expectedRoute: true,
 		},
+		{
+			name:          "Segmented Upload Route",
+			method:        http.MethodPost,
+			path:          "/audio/user/1/segmented",
+			expectedRoute: true,
+		},
 		{
 			name:          "Audio Get Route",
 			method:        http.MethodGet,
 			path:          "/audio/user/1/phrase/1/mp3",
 			expectedRoute: true,
 		},
+		{
+			name:          "Audio Info Route",
+			method:        http.MethodGet,
+			path:          "/audio/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "Waveform Route",
+			method:        http.MethodGet,
+			path:          "/audio/1/waveform",
+			expectedRoute: true,
+		},
+		{
+			name:          "Trim Route",
+			method:        http.MethodPost,
+			path:          "/audio/1/trim",
+			expectedRoute: true,
+		},
+		{
+			name:          "Clip Route",
+			method:        http.MethodGet,
+			path:          "/audio/1/clip?start=0&end=1",
+			expectedRoute: true,
+		},
+		{
+			name:          "Spectrogram Route",
+			method:        http.MethodGet,
+			path:          "/audio/1/spectrogram.png?scale=mel",
+			expectedRoute: true,
+		},
+		{
+			name:          "Pitch Route",
+			method:        http.MethodGet,
+			path:          "/audio/1/pitch?format=csv",
+			expectedRoute: true,
+		},
+		{
+			name:          "Analysis Route",
+			method:        http.MethodGet,
+			path:          "/audio/1/analysis",
+			expectedRoute: true,
+		},
+		{
+			name:          "Share Link Create Route",
+			method:        http.MethodPost,
+			path:          "/audio/1/share",
+			expectedRoute: true,
+		},
+		{
+			name:          "Share Link Open Route",
+			method:        http.MethodGet,
+			path:          "/share/audio/1/mp3",
+			expectedRoute: true,
+		},
+		{
+			name:          "Tus Upload Create Route",
+			method:        http.MethodPost,
+			path:          "/audio/user/1/phrase/1/uploads",
+			expectedRoute: true,
+		},
+		{
+			name:          "Tus Upload Patch Route",
+			method:        http.MethodPatch,
+			path:          "/uploads/01HXYZ",
+			expectedRoute: true,
+		},
+		{
+			name:          "Tus Upload Head Route",
+			method:        http.MethodHead,
+			path:          "/uploads/01HXYZ",
+			expectedRoute: true,
+		},
 		{
 			name:          "User Create Route",
 			method:        http.MethodPost,
 			path:          "/users",
 			expectedRoute: true,
 		},
+		{
+			name:          "User List Route",
+			method:        http.MethodGet,
+			path:          "/users?name=jo&limit=10",
+			expectedRoute: true,
+		},
+		{
+			name:          "User Get Route",
+			method:        http.MethodGet,
+			path:          "/users/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "User Update Route",
+			method:        http.MethodPatch,
+			path:          "/users/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "User Delete Route",
+			method:        http.MethodDelete,
+			path:          "/users/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "User Deletion Status Route",
+			method:        http.MethodGet,
+			path:          "/user_deletions/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "User Processing Profile Route",
+			method:        http.MethodPut,
+			path:          "/users/1/processing_profile",
+			expectedRoute: true,
+		},
 		{
 			name:          "Phrase Create Route",
 			method:        http.MethodPost,
 			path:          "/users/1/phrases",
 			expectedRoute: true,
 		},
+		{
+			name:          "Phrase List Route",
+			method:        http.MethodGet,
+			path:          "/users/1/phrases?has_audio=true",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Get Route",
+			method:        http.MethodGet,
+			path:          "/phrases/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Update Route",
+			method:        http.MethodPatch,
+			path:          "/users/1/phrases/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Delete Route",
+			method:        http.MethodDelete,
+			path:          "/users/1/phrases/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Revisions Route",
+			method:        http.MethodGet,
+			path:          "/phrases/1/revisions",
+			expectedRoute: true,
+		},
+		{
+			name:          "Collection Create Route",
+			method:        http.MethodPost,
+			path:          "/users/1/collections",
+			expectedRoute: true,
+		},
+		{
+			name:          "Collection List Route",
+			method:        http.MethodGet,
+			path:          "/users/1/collections",
+			expectedRoute: true,
+		},
+		{
+			name:          "Collection Get Route",
+			method:        http.MethodGet,
+			path:          "/collections/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "Collection Assign Route",
+			method:        http.MethodPut,
+			path:          "/users/1/collections/1/assignments/2",
+			expectedRoute: true,
+		},
+		{
+			name:          "Collection Unassign Route",
+			method:        http.MethodDelete,
+			path:          "/users/1/collections/1/assignments/2",
+			expectedRoute: true,
+		},
+		{
+			name:          "Collection Audio Upload Route",
+			method:        http.MethodPost,
+			path:          "/audio/user/1/collection/2/phrase/3",
+			expectedRoute: true,
+		},
+		{
+			name:          "Collection Audio Download Route",
+			method:        http.MethodGet,
+			path:          "/audio/user/1/collection/2/phrase/3/mp3",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Import Route",
+			method:        http.MethodPost,
+			path:          "/users/1/phrases/import?format=csv&dry_run=true",
+			expectedRoute: true,
+		},
+		{
+			name:          "Progress Route",
+			method:        http.MethodGet,
+			path:          "/users/1/progress",
+			expectedRoute: true,
+		},
+		{
+			name:          "Next Phrase Route",
+			method:        http.MethodGet,
+			path:          "/users/1/next-phrase?strategy=random",
+			expectedRoute: true,
+		},
+		{
+			name:          "Review Route",
+			method:        http.MethodPut,
+			path:          "/audio/1/review",
+			expectedRoute: true,
+		},
+		{
+			name:          "Review Get Route",
+			method:        http.MethodGet,
+			path:          "/audio/1/review",
+			expectedRoute: true,
+		},
+		{
+			name:          "Review Queue Route",
+			method:        http.MethodGet,
+			path:          "/reviews/queue?user_id=1&limit=10",
+			expectedRoute: true,
+		},
+		{
+			name:          "Rejected Takes Route",
+			method:        http.MethodGet,
+			path:          "/users/1/rejected-takes",
+			expectedRoute: true,
+		},
+		{
+			name:          "Annotation Create Route",
+			method:        http.MethodPost,
+			path:          "/audio/1/annotations",
+			expectedRoute: true,
+		},
+		{
+			name:          "Annotation Export Route",
+			method:        http.MethodGet,
+			path:          "/audio/1/annotations/export?format=textgrid",
+			expectedRoute: true,
+		},
+		{
+			name:          "Annotation Update Route",
+			method:        http.MethodPatch,
+			path:          "/audio/1/annotations/2",
+			expectedRoute: true,
+		},
+		{
+			name:          "Annotation Delete Route",
+			method:        http.MethodDelete,
+			path:          "/audio/1/annotations/2",
+			expectedRoute: true,
+		},
+		{
+			name:          "Session Open Route",
+			method:        http.MethodPost,
+			path:          "/users/1/sessions",
+			expectedRoute: true,
+		},
+		{
+			name:          "Session List Route",
+			method:        http.MethodGet,
+			path:          "/users/1/sessions",
+			expectedRoute: true,
+		},
+		{
+			name:          "Session Get Route",
+			method:        http.MethodGet,
+			path:          "/sessions/4",
+			expectedRoute: true,
+		},
+		{
+			name:          "Session Close Route",
+			method:        http.MethodPost,
+			path:          "/sessions/4/close",
+			expectedRoute: true,
+		},
+		{
+			name:          "Session Takes Route",
+			method:        http.MethodGet,
+			path:          "/sessions/4/audio",
+			expectedRoute: true,
+		},
+		{
+			name:          "Session Review Route",
+			method:        http.MethodPut,
+			path:          "/sessions/4/review",
+			expectedRoute: true,
+		},
+		{
+			name:          "Speaker Attributes Route",
+			method:        http.MethodGet,
+			path:          "/speaker-attributes",
+			expectedRoute: true,
+		},
+		{
+			name:          "Speaker Report Route",
+			method:        http.MethodGet,
+			path:          "/reports/speakers",
+			expectedRoute: true,
+		},
+		{
+			name:          "Speaker Attribute Report Route",
+			method:        http.MethodGet,
+			path:          "/reports/speakers/gender",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Claim Route",
+			method:        http.MethodPost,
+			path:          "/users/3/phrases/1/claim",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Share Route",
+			method:        http.MethodPut,
+			path:          "/users/3/phrases/1/shares/2",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Unshare Route",
+			method:        http.MethodDelete,
+			path:          "/users/3/phrases/1/shares/2",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Reference Set Route",
+			method:        http.MethodPut,
+			path:          "/phrases/1/reference",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Reference Delete Route",
+			method:        http.MethodDelete,
+			path:          "/phrases/1/reference",
+			expectedRoute: true,
+		},
+		{
+			name:          "Phrase Audio List Route",
+			method:        http.MethodGet,
+			path:          "/phrases/1/audio?sort=-similarity",
+			expectedRoute: true,
+		},
+		{
+			name:          "Compilation Create Route",
+			method:        http.MethodPost,
+			path:          "/users/1/compilations",
+			expectedRoute: true,
+		},
+		{
+			name:          "Compilation Get Route",
+			method:        http.MethodGet,
+			path:          "/compilations/1",
+			expectedRoute: true,
+		},
+		{
+			name:          "Compilation Audio Route",
+			method:        http.MethodGet,
+			path:          "/compilations/1/audio",
+			expectedRoute: true,
+		},
+		{
+			name:          "Compilation Manifest Route",
+			method:        http.MethodGet,
+			path:          "/compilations/1/manifest",
+			expectedRoute: true,
+		},
 		{
 			name:          "Health Check Route",
 			method:        http.MethodGet,
@@ -96,7 +466,7 @@ func TestSetupRoutes(t *testing.T) {
 
 func TestSetupRoutes_HandlerNilCheck(t *testing.T) {
 	// Test with nil handlers
-	router := SetupRoutes(nil, nil, nil)
+	router := SetupRoutes(Handlers{})
 	require.NotNil(t, router, "Router should be created even with nil handlers")
 
 	// Basic check that health endpoint still works
@@ -110,12 +480,16 @@ func TestSetupRoutes_HandlerNilCheck(t *testing.T) {
 
 func TestRouteHandlers(t *testing.T) {
 	// Create mock handlers
-	audioHandler := &handler.AudioHandler{}
-	userHandler := &handler.UserHandler{}
-	phraseHandler := &handler.PhraseHandler{}
+	handlers := Handlers{
+		Audio:  &handler.AudioHandler{},
+		User:   &handler.UserHandler{},
+		Phrase: &handler.PhraseHandler{},
+		Share:  &handler.ShareHandler{},
+		Tus:    &handler.TusHandler{},
+	}
 
 	// Setup router
-	router := SetupRoutes(audioHandler, userHandler, phraseHandler)
+	router := SetupRoutes(handlers)
 
 	// Create test server
 	server := httptest.NewServer(router)
@@ -3,12 +3,27 @@ package converter
 import (
 	"context"
 	"io"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
 )
 
 type AudioConverter interface {
-	// Convert converts an audio file from inputPath to outputPath in the specified outputFormat
-	Convert(ctx context.Context, inputPath, outputPath, outputFormat string) error
+	// Convert converts an audio file from inputPath to outputPath in the specified outputFormat,
+	// passing it through filters in order
+	Convert(ctx context.Context, inputPath, outputPath, outputFormat string, filters ...entity.Filter) error
+
+	// ConvertFromReader converts an audio file from input to output in the specified outputFormat,
+	// passing it through filters in order
+	ConvertFromReader(ctx context.Context, reader io.Reader, originalFormat, outputFormat string, filters ...entity.Filter) (io.ReadCloser, error)
+
+	// Probe inspects the audio file at inputPath and reports its container and stream properties
+	Probe(ctx context.Context, inputPath string) (*entity.AudioMetadata, error)
+
+	// MeasureLoudness runs a loudness analysis pass over the audio file at inputPath
+	// for the given target
+	MeasureLoudness(ctx context.Context, inputPath string, target entity.LoudnessTarget) (*entity.Loudness, error)
 
-	// ConvertFromReader converts an audio file from input to output in the specified outputFormat
-	ConvertFromReader(ctx context.Context, reader io.Reader, originalFormat, outputFormat string) (io.ReadCloser, error)
+	// NormalizeLoudness writes a WAV copy of inputPath to outputPath normalized to target,
+	// using a previous measurement of the input
+	NormalizeLoudness(ctx context.Context, inputPath, outputPath string, target entity.LoudnessTarget, measured *entity.Loudness) error
 }
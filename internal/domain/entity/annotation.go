@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Annotation marks a region of a recording, such as a word or a noise event.
+// Annotations of the same tier do not overlap.
+type Annotation struct {
+	ID      uint    `db:"id"`
+	AudioID uint    `db:"audio_id"`
+	Tier    string  `db:"tier"`
+	Label   string  `db:"label"`
+	Start   float64 `db:"start_time"` // Seconds
+	End     float64 `db:"end_time"`   // Seconds
+	// Attributes holds free-form details of the annotation.
+	Attributes map[string]interface{} `db:"-"`
+	CreatedAt  time.Time              `db:"created_at"`
+	UpdatedAt  time.Time              `db:"updated_at"`
+}
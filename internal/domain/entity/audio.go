@@ -22,4 +22,67 @@ type Audio struct {
 	Error         string      `db:"error"`
 	UserID        uint        `db:"user_id"`
 	PhraseID      uint        `db:"phrase_id"`
+	Duration      float64     `db:"duration"`    // Seconds
+	SampleRate    int         `db:"sample_rate"` // Hz
+	Channels      int         `db:"channels"`
+	Codec         string      `db:"codec"`
+	BitRate       int64       `db:"bit_rate"`  // Bits per second
+	FileSize      int64       `db:"file_size"` // Bytes
+	// Input loudness measured before normalization; nil if never measured.
+	LoudnessIntegrated *float64 `db:"loudness_integrated"` // LUFS
+	LoudnessTruePeak   *float64 `db:"loudness_true_peak"`  // dBTP
+	LoudnessRange      *float64 `db:"loudness_range"`      // LU
+	LoudnessThreshold  *float64 `db:"loudness_threshold"`  // LUFS
+	// NormalizedPath is the storage path of the loudness normalized WAV, if any.
+	NormalizedPath string `db:"normalized_path"`
+	// Trim offsets in seconds of the audible part of the canonical file; nil
+	// if silence detection never ran. The canonical file itself is not trimmed.
+	TrimStart *float64 `db:"trim_start"`
+	TrimEnd   *float64 `db:"trim_end"`
+	// NeedsReview is set when quality analysis found a problem with the take.
+	NeedsReview bool `db:"needs_review"`
+	// SimilarityScore compares the take with the reference recording of its
+	// phrase, from 0 to 1; nil if the phrase has no reference.
+	SimilarityScore *float64 `db:"similarity_score"`
+	// Stale is set when the text of the phrase changed after the take was
+	// recorded, so it may no longer match.
+	Stale bool `db:"stale"`
+	// SessionID is the recording session the take was uploaded in; 0 if none.
+	SessionID uint `db:"session_id"`
+}
+
+// ApplyLoudness records the measured input loudness.
+func (a *Audio) ApplyLoudness(l *Loudness) {
+	a.LoudnessIntegrated = &l.Integrated
+	a.LoudnessTruePeak = &l.TruePeak
+	a.LoudnessRange = &l.Range
+	a.LoudnessThreshold = &l.Threshold
+}
+
+// ApplyTrim records the range left after trimming silence.
+func (a *Audio) ApplyTrim(start, end float64) {
+	a.TrimStart = &start
+	a.TrimEnd = &end
+}
+
+// AudioMetadata describes an audio file as reported by a probe.
+type AudioMetadata struct {
+	Format     string // Container format, e.g. "m4a" or "wav"
+	Duration   float64
+	SampleRate int
+	Channels   int
+	Codec      string
+	BitRate    int64
+	FileSize   int64
+}
+
+// ApplyMetadata copies probed metadata onto the audio.
+func (a *Audio) ApplyMetadata(meta *AudioMetadata) {
+	a.CurrentFormat = meta.Format
+	a.Duration = meta.Duration
+	a.SampleRate = meta.SampleRate
+	a.Channels = meta.Channels
+	a.Codec = meta.Codec
+	a.BitRate = meta.BitRate
+	a.FileSize = meta.FileSize
 }
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// AudioAnalysis holds the quality measurements of a converted audio. Levels
+// are in dBFS.
+type AudioAnalysis struct {
+	AudioID      uint    `db:"audio_id"`
+	PeakDB       float64 `db:"peak_db"`
+	RMSDB        float64 `db:"rms_db"`
+	ClippedRatio float64 `db:"clipped_ratio"` // Fraction of samples at full scale
+	NoiseFloorDB float64 `db:"noise_floor_db"`
+	SNRDB        float64 `db:"snr_db"`       // dB
+	SpeechRatio  float64 `db:"speech_ratio"` // Fraction of the audio with speech
+	// Problems lists the thresholds the audio violated; it is flagged for
+	// review when there are any.
+	Problems  []string  `db:"-"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
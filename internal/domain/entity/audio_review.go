@@ -0,0 +1,87 @@
+package entity
+
+import "time"
+
+// ReviewStatus tells whether a reviewer accepted a take. It is independent of
+// the conversion status of the audio.
+type ReviewStatus string
+
+const (
+	ReviewStatusUnreviewed    ReviewStatus = "unreviewed"
+	ReviewStatusApproved      ReviewStatus = "approved"
+	ReviewStatusRejected      ReviewStatus = "rejected"
+	ReviewStatusNeedsRerecord ReviewStatus = "needs_rerecord"
+)
+
+// RerecordStatuses are the verdicts that send a take back to its speaker to
+// be recorded again.
+var RerecordStatuses = []ReviewStatus{ReviewStatusRejected, ReviewStatusNeedsRerecord}
+
+// RequiresRerecord reports whether s is one of RerecordStatuses.
+func (s ReviewStatus) RequiresRerecord() bool {
+	for _, status := range RerecordStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Reason codes a reviewer gives for rejecting a take.
+const (
+	ReviewReasonNoise         = "noise"
+	ReviewReasonClipping      = "clipping"
+	ReviewReasonTooQuiet      = "too_quiet"
+	ReviewReasonTruncated     = "truncated"
+	ReviewReasonMispronounced = "mispronounced"
+	ReviewReasonWrongText     = "wrong_text"
+	ReviewReasonOther         = "other"
+)
+
+// ReviewReasons lists the known reason codes.
+var ReviewReasons = []string{
+	ReviewReasonNoise,
+	ReviewReasonClipping,
+	ReviewReasonTooQuiet,
+	ReviewReasonTruncated,
+	ReviewReasonMispronounced,
+	ReviewReasonWrongText,
+	ReviewReasonOther,
+}
+
+// AudioReview is the latest verdict of a reviewer on a take. A take without
+// one is unreviewed.
+type AudioReview struct {
+	AudioID uint         `db:"audio_id"`
+	Status  ReviewStatus `db:"status"`
+	// ReviewerID is the user who reviewed the take; zero once they are
+	// deleted.
+	ReviewerID uint      `db:"reviewer_id"`
+	Reasons    []string  `db:"-"`
+	Notes      string    `db:"notes"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// RejectedTake is the review of a take that was rejected or has to be
+// recorded again, with the phrase it is a take of.
+type RejectedTake struct {
+	AudioReview
+	PhraseID uint   `db:"phrase_id"`
+	Phrase   string `db:"phrase"`
+}
+
+// SessionReview is the latest verdict of a reviewer on a whole recording
+// session. It applies to every take of the session, including those that
+// complete after it was given.
+type SessionReview struct {
+	SessionID uint         `db:"session_id"`
+	Status    ReviewStatus `db:"status"`
+	// ReviewerID is the user who reviewed the session; zero once they are
+	// deleted.
+	ReviewerID uint      `db:"reviewer_id"`
+	Reasons    []string  `db:"-"`
+	Notes      string    `db:"notes"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// Collection is a script: an ordered list of phrases owned by one user and
+// recorded by every user it is assigned to.
+type Collection struct {
+	ID        uint      `db:"id"`
+	UserID    uint      `db:"user_id"`
+	Name      string    `db:"name"`
+	Language  string    `db:"language"` // BCP 47 tag, e.g. "en-US"; may be empty
+	Tags      []string  `db:"-"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// CollectionPhrase is a phrase at a position of a collection, counted from 1.
+type CollectionPhrase struct {
+	Position int `db:"position"`
+	Phrase
+}
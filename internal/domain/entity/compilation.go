@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+type CompilationStatus string
+
+const (
+	CompilationStatusPending    CompilationStatus = "pending"
+	CompilationStatusProcessing CompilationStatus = "processing"
+	CompilationStatusCompleted  CompilationStatus = "completed"
+	CompilationStatusFailed     CompilationStatus = "failed"
+)
+
+// Compilation is a single file stitched together from the canonical audio of
+// several of a user's phrases, in the order given.
+type Compilation struct {
+	ID           uint              `db:"id"`
+	UserID       uint              `db:"user_id"`
+	PhraseIDs    []uint            `db:"-"`
+	Format       string            `db:"format"`
+	Gap          float64           `db:"gap"`       // Seconds of silence between phrases
+	Crossfade    float64           `db:"crossfade"` // Seconds adjacent phrases overlap
+	Status       CompilationStatus `db:"status"`
+	StoragePath  string            `db:"storage_path"`
+	ManifestPath string            `db:"manifest_path"`
+	Error        string            `db:"error"`
+	CreatedAt    time.Time         `db:"created_at"`
+	UpdatedAt    time.Time         `db:"updated_at"`
+}
+
+// CompilationManifest describes where each phrase lies in a compilation.
+type CompilationManifest struct {
+	Format     string                     `json:"format"`
+	SampleRate int                        `json:"sample_rate"`
+	Channels   int                        `json:"channels"`
+	Duration   float64                    `json:"duration"`
+	Phrases    []CompilationManifestEntry `json:"phrases"`
+}
+
+// CompilationManifestEntry holds the offsets, in seconds, of one phrase. With
+// a crossfade, an entry starts before the previous one ends.
+type CompilationManifestEntry struct {
+	PhraseID uint    `json:"phrase_id"`
+	AudioID  uint    `json:"audio_id"`
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+}
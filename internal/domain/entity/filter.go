@@ -0,0 +1,8 @@
+package entity
+
+// Filter is one step of a processing profile: an audio filter and its
+// parameters, e.g. {Name: "highpass", Params: {"f": "80"}}.
+type Filter struct {
+	Name   string
+	Params map[string]string
+}
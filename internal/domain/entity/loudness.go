@@ -0,0 +1,33 @@
+package entity
+
+import "fmt"
+
+// Loudness is an EBU R128 measurement of an audio file.
+type Loudness struct {
+	Integrated   float64 // LUFS
+	TruePeak     float64 // dBTP
+	Range        float64 // LU
+	Threshold    float64 // LUFS
+	TargetOffset float64 // LU, gain still needed after normalization to hit the target
+}
+
+// LoudnessTarget is the loudness audio is normalized to.
+type LoudnessTarget struct {
+	Integrated float64 // LUFS
+	TruePeak   float64 // dBTP
+	Range      float64 // LU
+}
+
+// Validate checks the target against the ranges ffmpeg's loudnorm filter accepts.
+func (t LoudnessTarget) Validate() error {
+	if t.Integrated < -70 || t.Integrated > -5 {
+		return fmt.Errorf("integrated loudness must be between -70 and -5 LUFS, got %v", t.Integrated)
+	}
+	if t.TruePeak < -9 || t.TruePeak > 0 {
+		return fmt.Errorf("true peak must be between -9 and 0 dBTP, got %v", t.TruePeak)
+	}
+	if t.Range < 1 || t.Range > 50 {
+		return fmt.Errorf("loudness range must be between 1 and 50 LU, got %v", t.Range)
+	}
+	return nil
+}
@@ -8,4 +8,19 @@ type Phrase struct {
 	Phrase    string    `db:"phrase"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+	// ReferencePath is the storage path of a reference recording takes are
+	// scored against, if any.
+	ReferencePath string `db:"reference_path"`
+	// Revision counts the edits of the text, starting at zero.
+	Revision int      `db:"revision"`
+	Language string   `db:"language"` // BCP 47 tag, e.g. "en-US"; may be empty
+	Tags     []string `db:"-"`
+}
+
+// PhraseRevision is a text a phrase had before it was edited.
+type PhraseRevision struct {
+	PhraseID   uint      `db:"phrase_id"`
+	Revision   int       `db:"revision"`
+	Phrase     string    `db:"phrase"`
+	ReplacedAt time.Time `db:"replaced_at"`
 }
@@ -0,0 +1,16 @@
+package entity
+
+// RecordingProgress summarizes the takes a user recorded of the phrases they
+// may record. Each phrase is counted once, under NoAudio or the status of its
+// take.
+type RecordingProgress struct {
+	Phrases    int `db:"phrases"`
+	NoAudio    int `db:"no_audio"`
+	Pending    int `db:"pending"`
+	Converting int `db:"converting"`
+	Completed  int `db:"completed"`
+	Failed     int `db:"failed"`
+	// RecordedDuration is the total duration of the completed takes, in
+	// seconds.
+	RecordedDuration float64 `db:"recorded_duration"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// RecordingSession is one sitting in which a speaker records takes with the
+// same equipment in the same place. Takes uploaded in a session refer to it,
+// so they can be reviewed together.
+type RecordingSession struct {
+	ID         uint   `db:"id"`
+	UserID     uint   `db:"user_id"`
+	Device     string `db:"device"`      // Computer or phone, e.g. "Pixel 8"
+	Client     string `db:"client"`      // Browser or app and its version
+	SampleRate int    `db:"sample_rate"` // Hz the client records at; 0 if unknown
+	Microphone string `db:"microphone"`  // Label of the input device
+	Location   string `db:"location"`    // Tag of where the session takes place
+	// ClosedAt is set when the speaker ends the session; closed sessions
+	// take no more uploads.
+	ClosedAt  *time.Time `db:"closed_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
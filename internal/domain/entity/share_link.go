@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// ShareLink is a signed, time-limited grant to download one audio in one format.
+type ShareLink struct {
+	ID        uint       `db:"id"`
+	Nonce     string     `db:"nonce"`
+	AudioID   uint       `db:"audio_id"`
+	Format    string     `db:"format"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	SingleUse bool       `db:"single_use"`
+	IP        string     `db:"ip"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Upload is a resumable upload that is assembled chunk by chunk before it is
+// handed to the regular audio upload path.
+type Upload struct {
+	ID        string    `db:"id"`
+	UserID    uint      `db:"user_id"`
+	PhraseID  uint      `db:"phrase_id"`
+	Filename  string    `db:"filename"`
+	Length    int64     `db:"length"`
+	Offset    int64     `db:"upload_offset"`
+	Metadata  string    `db:"metadata"`   // Raw Upload-Metadata header as sent by the client
+	AudioID   *uint     `db:"audio_id"`   // Set once the upload is complete and stored as audio
+	SessionID uint      `db:"session_id"` // Recording session of the take; 0 if none
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Completed reports whether all bytes of the upload have been received.
+func (u *Upload) Completed() bool {
+	return u.Offset == u.Length
+}
@@ -3,8 +3,27 @@ package entity
 import "time"
 
 type User struct {
-	ID        uint      `db:"id"`
-	Name      string    `db:"name"`
+	ID   uint   `db:"id"`
+	Name string `db:"name"`
+	// ProcessingProfile is applied to the user's downloads unless the request picks another.
+	ProcessingProfile string `db:"processing_profile"`
+	// Attributes describe the user as a speaker, e.g. their age range or
+	// accent, keyed by the attributes of the configured speaker schema.
+	Attributes map[string]string `db:"-"`
+	// Deleting is set once the user's deletion is requested; no more takes
+	// are accepted from them.
+	Deleting  bool      `db:"deleting"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
+
+// SpeakerAttributeStats summarizes the speakers with one value of a speaker
+// attribute and their completed takes.
+type SpeakerAttributeStats struct {
+	Value      string `db:"value"` // Empty for speakers without the attribute
+	Speakers   int    `db:"speakers"`
+	Recordings int    `db:"recordings"`
+	// RecordedDuration is the total duration of the completed takes, in
+	// seconds.
+	RecordedDuration float64 `db:"recorded_duration"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+type UserDeletionStatus string
+
+const (
+	UserDeletionStatusPending    UserDeletionStatus = "pending"
+	UserDeletionStatusProcessing UserDeletionStatus = "processing"
+	UserDeletionStatusCompleted  UserDeletionStatus = "completed"
+	UserDeletionStatusFailed     UserDeletionStatus = "failed"
+)
+
+// UserDeletion tracks the removal of a user together with their phrases,
+// recordings, queued tasks and stored files. It outlives the user.
+type UserDeletion struct {
+	ID     uint               `db:"id"`
+	UserID uint               `db:"user_id"`
+	Status UserDeletionStatus `db:"status"`
+	// Counts of what was removed, set once the deletion completes.
+	Phrases   int       `db:"phrases"`
+	Audio     int       `db:"audio"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
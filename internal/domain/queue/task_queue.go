@@ -28,4 +28,8 @@ type TaskQueue interface {
 
 	// Fail marks a task as failed with an error message
 	Fail(ctx context.Context, taskID string, errMsg string) error
+
+	// Cancel removes the tasks with the given payload, including ones being
+	// processed, which then fail to complete
+	Cancel(ctx context.Context, payload uint) error
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+type AnnotationRepository interface {
+	Create(ctx context.Context, annotation *entity.Annotation) (*entity.Annotation, error)
+	GetByID(ctx context.Context, id uint) (*entity.Annotation, error)
+	// ListByAudioID retrieves the annotations of an audio ordered by tier and
+	// start.
+	ListByAudioID(ctx context.Context, audioID uint) ([]*entity.Annotation, error)
+	Update(ctx context.Context, annotation *entity.Annotation) error
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+type AudioAnalysisRepository interface {
+	// Save stores the analysis of an audio, replacing any earlier one.
+	Save(ctx context.Context, analysis *entity.AudioAnalysis) error
+	GetByAudioID(ctx context.Context, audioID uint) (*entity.AudioAnalysis, error)
+}
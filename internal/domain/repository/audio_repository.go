@@ -11,4 +11,19 @@ type AudioRepository interface {
 	GetByID(ctx context.Context, id uint) (*entity.Audio, error)
 	GetByUserIDAndPhraseID(ctx context.Context, userID uint, phraseID uint) (*entity.Audio, error)
 	Update(ctx context.Context, audio *entity.Audio) error
+	// SetSimilarityScore sets the similarity score of an audio, leaving the
+	// rest of it as it is. A nil score clears it.
+	SetSimilarityScore(ctx context.Context, id uint, score *float64) error
+	// Replace stores audio in place of the take with ID oldID, which is
+	// deleted along with the rows that refer to it.
+	Replace(ctx context.Context, oldID uint, audio *entity.Audio) (*entity.Audio, error)
+	// Delete removes a take along with the rows that refer to it.
+	Delete(ctx context.Context, id uint) error
+	ListByPhraseID(ctx context.Context, phraseID uint) ([]*entity.Audio, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*entity.Audio, error)
+	ListBySessionID(ctx context.Context, sessionID uint) ([]*entity.Audio, error)
+	// Progress summarizes the takes userID recorded of the phrases they may
+	// record: the phrases they own, and those shared with them or in a
+	// collection assigned to them.
+	Progress(ctx context.Context, userID uint) (*entity.RecordingProgress, error)
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+// ReviewQueueFilter selects a page of the completed takes nobody reviewed.
+type ReviewQueueFilter struct {
+	UserID    uint // Speaker of the takes, if set
+	PhraseID  uint // Phrase of the takes, if set
+	SessionID uint // Recording session of the takes, if set
+	Limit     int
+	Offset    int
+}
+
+type AudioReviewRepository interface {
+	// Save stores the review of an audio, replacing any earlier one.
+	Save(ctx context.Context, review *entity.AudioReview) (*entity.AudioReview, error)
+	GetByAudioID(ctx context.Context, audioID uint) (*entity.AudioReview, error)
+	// SaveSession stores the review of a recording session, replacing any
+	// earlier one, and saves the same verdict as the review of each of its
+	// completed takes, all in one transaction. It returns the reviews of the
+	// takes by audio ID.
+	SaveSession(ctx context.Context, review *entity.SessionReview) ([]*entity.AudioReview, error)
+	GetBySessionID(ctx context.Context, sessionID uint) (*entity.SessionReview, error)
+	// ListUnreviewed returns the page of matching completed audio without a
+	// review, those flagged for review by quality analysis first and then by
+	// ID, and the number of them in total.
+	ListUnreviewed(ctx context.Context, filter ReviewQueueFilter) ([]*entity.Audio, int, error)
+	// ListRejected retrieves the takes of a user that were rejected or have
+	// to be recorded again, most recently reviewed first.
+	ListRejected(ctx context.Context, userID uint) ([]*entity.RejectedTake, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+type CollectionRepository interface {
+	// Create stores a collection with a new phrase, owned by the owner of
+	// the collection, for each text, in order.
+	Create(ctx context.Context, collection *entity.Collection, texts []string) (*entity.Collection, error)
+	GetByID(ctx context.Context, id uint) (*entity.Collection, error)
+	// ListByUserID retrieves the collections a user owns.
+	ListByUserID(ctx context.Context, userID uint) ([]*entity.Collection, error)
+	// ListAssigned retrieves the collections assigned to a user.
+	ListAssigned(ctx context.Context, userID uint) ([]*entity.Collection, error)
+	// ListPhrases retrieves the phrases of a collection in order.
+	ListPhrases(ctx context.Context, collectionID uint) ([]*entity.CollectionPhrase, error)
+	GetPhrase(ctx context.Context, collectionID uint, position int) (*entity.CollectionPhrase, error)
+	// Assign lets userID record and download the phrases of a collection.
+	Assign(ctx context.Context, collectionID, userID uint) error
+	Unassign(ctx context.Context, collectionID, userID uint) error
+	ListAssignees(ctx context.Context, collectionID uint) ([]uint, error)
+}
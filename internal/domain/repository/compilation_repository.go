@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+type CompilationRepository interface {
+	Create(ctx context.Context, compilation *entity.Compilation) (*entity.Compilation, error)
+	GetByID(ctx context.Context, id uint) (*entity.Compilation, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*entity.Compilation, error)
+	Update(ctx context.Context, compilation *entity.Compilation) error
+}
@@ -0,0 +1,6 @@
+package repository
+
+import "errors"
+
+// ErrNotFound is returned (possibly wrapped) when the requested record does not exist.
+var ErrNotFound = errors.New("record not found")
@@ -6,7 +6,72 @@ import (
 	"github.com/ardfard/sb-test/internal/domain/entity"
 )
 
+// PhraseFilter selects a page of the phrases a user owns.
+type PhraseFilter struct {
+	UserID uint
+	// HasAudio, if set, selects phrases with or without any take.
+	HasAudio *bool
+	// AudioStatus, if set, selects phrases with a take in that status.
+	AudioStatus entity.AudioStatus
+	Limit       int
+	Offset      int
+}
+
+// NextPhraseStrategy orders the phrases a user still has to record.
+type NextPhraseStrategy string
+
+const (
+	// NextPhraseInOrder picks the oldest phrase first.
+	NextPhraseInOrder NextPhraseStrategy = "creation"
+	// NextPhraseRandom picks any phrase.
+	NextPhraseRandom NextPhraseStrategy = "random"
+	// NextPhraseFailedFirst picks the oldest phrase whose take has to be
+	// recorded again, then the oldest phrase without a take.
+	NextPhraseFailedFirst NextPhraseStrategy = "failed_first"
+)
+
+// Valid reports whether s is a known strategy.
+func (s NextPhraseStrategy) Valid() bool {
+	switch s {
+	case NextPhraseInOrder, NextPhraseRandom, NextPhraseFailedFirst:
+		return true
+	}
+	return false
+}
+
 type PhraseRepository interface {
 	Create(ctx context.Context, phrase *entity.Phrase) (*entity.Phrase, error)
 	GetByID(ctx context.Context, id uint) (*entity.Phrase, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*entity.Phrase, error)
+	// List returns the page of matching phrases ordered by ID, and the number
+	// of phrases matching in total.
+	List(ctx context.Context, filter PhraseFilter) ([]*entity.Phrase, int, error)
+	Update(ctx context.Context, phrase *entity.Phrase) error
+	// Revise replaces the text of a phrase and bumps its revision, keeping
+	// the old text as an entity.PhraseRevision and flagging the existing
+	// takes as stale.
+	Revise(ctx context.Context, id uint, text string) (*entity.Phrase, error)
+	ListRevisions(ctx context.Context, phraseID uint) ([]*entity.PhraseRevision, error)
+	// Import creates the new phrases and applies the text, language and tags
+	// of the changed ones in a single transaction, revising changed texts as
+	// Revise does. It returns the created phrases.
+	Import(ctx context.Context, created, changed []*entity.Phrase) ([]*entity.Phrase, error)
+	// Delete removes the phrase with its takes and every row that refers to
+	// either.
+	Delete(ctx context.Context, id uint) error
+	// NextToRecord picks, by strategy, one of the phrases userID may record
+	// that has no take by them or only one an upload would replace: a failed
+	// take, or a completed one that is stale or whose review status is one of
+	// entity.RerecordStatuses.
+	NextToRecord(ctx context.Context, userID uint, strategy NextPhraseStrategy) (*entity.Phrase, error)
+	// Claim makes userID the owner of a phrase that has none. It returns
+	// ErrNotFound if there is no such phrase without an owner.
+	Claim(ctx context.Context, phraseID, userID uint) error
+	// Share lets userID record and download a phrase owned by another user.
+	Share(ctx context.Context, phraseID, userID uint) error
+	Unshare(ctx context.Context, phraseID, userID uint) error
+	// IsSharedWith reports whether userID may use a phrase owned by another
+	// user, because it is shared with them or is part of a collection
+	// assigned to them.
+	IsSharedWith(ctx context.Context, phraseID, userID uint) (bool, error)
 }
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+// RecordingSessionFilter selects a page of the recording sessions of a user.
+type RecordingSessionFilter struct {
+	UserID uint
+	Limit  int
+	Offset int
+}
+
+type RecordingSessionRepository interface {
+	Create(ctx context.Context, session *entity.RecordingSession) (*entity.RecordingSession, error)
+	GetByID(ctx context.Context, id uint) (*entity.RecordingSession, error)
+	// List returns the page of the user's sessions, most recent first, and
+	// the number of them in total.
+	List(ctx context.Context, filter RecordingSessionFilter) ([]*entity.RecordingSession, int, error)
+	// Close marks an open session as closed at closedAt. Closing a closed
+	// session keeps its original time.
+	Close(ctx context.Context, id uint, closedAt time.Time) (*entity.RecordingSession, error)
+}
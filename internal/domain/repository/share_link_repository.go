@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *entity.ShareLink) (*entity.ShareLink, error)
+	// MarkUsed atomically redeems a single-use link. It returns false when the
+	// link was already redeemed.
+	MarkUsed(ctx context.Context, nonce string) (bool, error)
+}
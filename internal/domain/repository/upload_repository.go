@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+type UploadRepository interface {
+	Create(ctx context.Context, upload *entity.Upload) (*entity.Upload, error)
+	GetByID(ctx context.Context, id string) (*entity.Upload, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*entity.Upload, error)
+	ListByPhraseID(ctx context.Context, phraseID uint) ([]*entity.Upload, error)
+	// ListStale lists the unfinished uploads last updated before the given time.
+	ListStale(ctx context.Context, before time.Time) ([]*entity.Upload, error)
+	Update(ctx context.Context, upload *entity.Upload) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+type UserDeletionRepository interface {
+	Create(ctx context.Context, deletion *entity.UserDeletion) (*entity.UserDeletion, error)
+	GetByID(ctx context.Context, id uint) (*entity.UserDeletion, error)
+	Update(ctx context.Context, deletion *entity.UserDeletion) error
+}
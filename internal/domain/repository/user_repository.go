@@ -6,7 +6,27 @@ import (
 	"github.com/ardfard/sb-test/internal/domain/entity"
 )
 
+// UserFilter selects a page of users.
+type UserFilter struct {
+	Name   string // Case-insensitive substring of the name; all users when empty
+	Limit  int
+	Offset int
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) (*entity.User, error)
 	GetByID(ctx context.Context, id uint) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+	// List returns the page of matching users ordered by ID, and the number
+	// of users matching in total.
+	List(ctx context.Context, filter UserFilter) ([]*entity.User, int, error)
+	// AttributeStats groups all users by their value of a speaker attribute,
+	// the empty value for those without it, ordered by value. Each group
+	// counts the completed takes its users recorded and their duration.
+	AttributeStats(ctx context.Context, attribute string) ([]*entity.SpeakerAttributeStats, error)
+	// MarkDeleting flags the user as being deleted.
+	MarkDeleting(ctx context.Context, id uint) error
+	// Delete removes the user with the phrases they own, the audio recorded
+	// by them or for their phrases, and every row that refers to either.
+	Delete(ctx context.Context, id uint) error
 }
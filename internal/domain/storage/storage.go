@@ -2,9 +2,14 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"io"
+	"time"
 )
 
+// ErrObjectNotFound is returned (wrapped) by Download when the object does not exist.
+var ErrObjectNotFound = errors.New("object not found")
+
 // Storage is a contract for any object storage provider.
 type Storage interface {
 	// Upload saves the data under a specified object name.
@@ -13,4 +18,28 @@ type Storage interface {
 	Download(ctx context.Context, objectName string) (io.ReadCloser, error)
 	// Delete deletes the data from the specified object name.
 	Delete(ctx context.Context, objectName string) error
+	// DeletePrefix deletes every object whose name starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// URLSigner is implemented by storage providers that can hand out
+// time-limited direct download URLs for stored objects.
+type URLSigner interface {
+	// SignedURL returns a URL that allows downloading objectName until ttl elapses.
+	SignedURL(ctx context.Context, objectName string, ttl time.Duration) (string, error)
+}
+
+// StagingArea holds partially received files until they are complete.
+type StagingArea interface {
+	// Create allocates an empty staged file.
+	Create(ctx context.Context, id string) error
+	// Append writes the reader's content at offset, which must equal the
+	// current size of the staged file, and returns the number of bytes written.
+	Append(ctx context.Context, id string, offset int64, reader io.Reader) (int64, error)
+	// Truncate shrinks the staged file to size, discarding a rejected chunk.
+	Truncate(ctx context.Context, id string, size int64) error
+	// Open opens the staged file for reading.
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+	// Delete removes the staged file.
+	Delete(ctx context.Context, id string) error
 }
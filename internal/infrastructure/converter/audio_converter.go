@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 
+	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/pkg/util"
 	ffmpeg "github.com/u2takey/ffmpeg-go"
 )
@@ -16,46 +17,49 @@ func NewAudioConverter() *AudioConverter {
 	return &AudioConverter{}
 }
 
-func (ac *AudioConverter) Convert(ctx context.Context, inputPath, outputPath, outputFormat string) error {
-	stream := ffmpeg.Input(inputPath)
+func (ac *AudioConverter) Convert(ctx context.Context, inputPath, outputPath, outputFormat string, filters ...entity.Filter) error {
+	err := convertStream(inputPath, outputPath, outputFormat, filters).OverWriteOutput().Run()
+	if err != nil {
+		return fmt.Errorf("failed to convert audio: %v", err)
+	}
 
+	return nil
+}
+
+func convertStream(inputPath, outputPath, outputFormat string, filters []entity.Filter) *ffmpeg.Stream {
+	var args ffmpeg.KwArgs
 	switch outputFormat {
 	case "wav":
-		stream = stream.Output(outputPath,
-			ffmpeg.KwArgs{
-				"acodec": "pcm_s16le",
-				"ar":     "44100",
-			})
+		args = ffmpeg.KwArgs{
+			"acodec": "pcm_s16le",
+			"ar":     wavSampleRate(filters),
+		}
 	case "mp3":
-		stream = stream.Output(outputPath,
-			ffmpeg.KwArgs{
-				"acodec": "libmp3lame",
-				"q:a":    "2",
-			})
+		args = ffmpeg.KwArgs{
+			"acodec": "libmp3lame",
+			"q:a":    "2",
+		}
 	case "m4a":
-		stream = stream.Output(outputPath,
-			ffmpeg.KwArgs{
-				"acodec": "aac",
-				"strict": "experimental",
-			})
+		args = ffmpeg.KwArgs{
+			"acodec": "aac",
+			"strict": "experimental",
+		}
 	case "flac":
-		stream = stream.Output(outputPath,
-			ffmpeg.KwArgs{
-				"acodec": "flac",
-			})
+		args = ffmpeg.KwArgs{
+			"acodec": "flac",
+		}
 	default:
-		stream = stream.Output(outputPath)
+		args = ffmpeg.KwArgs{}
 	}
 
-	err := stream.OverWriteOutput().Run()
-	if err != nil {
-		return fmt.Errorf("failed to convert audio: %v", err)
+	if len(filters) > 0 {
+		args["af"] = filterGraph(filters)
 	}
 
-	return nil
+	return ffmpeg.Input(inputPath).Output(outputPath, args)
 }
 
-func (ac *AudioConverter) ConvertFromReader(ctx context.Context, input io.Reader, originalFormat, outputFormat string) (io.ReadCloser, error) {
+func (ac *AudioConverter) ConvertFromReader(ctx context.Context, input io.Reader, originalFormat, outputFormat string, filters ...entity.Filter) (io.ReadCloser, error) {
 
 	inputPath, outputPath, err := util.CreateTemporaryFiles(originalFormat, outputFormat)
 	if err != nil {
@@ -73,7 +77,7 @@ func (ac *AudioConverter) ConvertFromReader(ctx context.Context, input io.Reader
 		return nil, fmt.Errorf("failed to write to temp input file: %v", err)
 	}
 
-	err = ac.Convert(ctx, inputPath, outputPath, outputFormat)
+	err = ac.Convert(ctx, inputPath, outputPath, outputFormat, filters...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert audio: %v", err)
 	}
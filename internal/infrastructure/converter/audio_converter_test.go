@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/pkg/projectpath"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,6 +35,16 @@ func TestAudioConverter(t *testing.T) {
 		assert.Contains(t, probe, "44100")
 	})
 
+	t.Run("Resample WAV", func(t *testing.T) {
+		outputPath := filepath.Join(tempDir, "resampled.wav")
+		err := converter.Convert(ctx, testInputPath, outputPath, "wav", entity.Filter{Name: "aresample", Params: map[string]string{"osr": "16000"}})
+
+		assert.NoError(t, err)
+		probe, err := ffmpeg.Probe(outputPath)
+		assert.NoError(t, err)
+		assert.Contains(t, probe, `"sample_rate": "16000"`)
+	})
+
 	t.Run("Convert to MP3", func(t *testing.T) {
 		outputPath := filepath.Join(tempDir, "output.mp3")
 		err := converter.Convert(ctx, testInputPath, outputPath, "mp3")
@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+// allowedFilters lists the ffmpeg audio filters processing profiles may use
+// and the parameters each accepts. Filters that read files or run code
+// (amovie, ladspa, ...) must never be added here.
+var allowedFilters = map[string][]string{
+	"highpass":    {"f", "poles", "t", "w"},
+	"lowpass":     {"f", "poles", "t", "w"},
+	"afftdn":      {"nr", "nf", "nt"},
+	"dynaudnorm":  {"f", "g", "p", "m", "r"},
+	"aresample":   {"osr", "ochl"},
+	"volume":      {"volume"},
+	"acompressor": {"threshold", "ratio", "attack", "release", "makeup"},
+	"loudnorm":    {"I", "TP", "LRA"},
+}
+
+// filterValue keeps parameter values from escaping into the filter graph syntax.
+var filterValue = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+
+// ValidateFilters checks that filters only use allow-listed filters and parameters.
+func ValidateFilters(filters []entity.Filter) error {
+	for _, f := range filters {
+		params, ok := allowedFilters[f.Name]
+		if !ok {
+			return fmt.Errorf("filter %q is not allowed", f.Name)
+		}
+		for key, value := range f.Params {
+			if !slices.Contains(params, key) {
+				return fmt.Errorf("filter %q does not accept parameter %q", f.Name, key)
+			}
+			if !filterValue.MatchString(value) {
+				return fmt.Errorf("invalid value %q for %s parameter %q", value, f.Name, key)
+			}
+		}
+	}
+	return nil
+}
+
+// wavSampleRate is the sample rate of WAV output: the one the last aresample
+// filter asks for, or 44.1 kHz.
+func wavSampleRate(filters []entity.Filter) string {
+	rate := "44100"
+	for _, f := range filters {
+		if osr := f.Params["osr"]; f.Name == "aresample" && osr != "" {
+			rate = osr
+		}
+	}
+	return rate
+}
+
+// filterGraph renders filters as an ffmpeg filter chain, e.g.
+// "highpass=f=80,afftdn=nr=12".
+func filterGraph(filters []entity.Filter) string {
+	steps := make([]string, 0, len(filters))
+	for _, f := range filters {
+		keys := make([]string, 0, len(f.Params))
+		for key := range f.Params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		params := make([]string, 0, len(keys))
+		for _, key := range keys {
+			params = append(params, key+"="+f.Params[key])
+		}
+		if len(params) == 0 {
+			steps = append(steps, f.Name)
+		} else {
+			steps = append(steps, f.Name+"="+strings.Join(params, ":"))
+		}
+	}
+	return strings.Join(steps, ",")
+}
@@ -0,0 +1,69 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []entity.Filter
+		wantErr bool
+	}{
+		{
+			name: "allowed chain",
+			filters: []entity.Filter{
+				{Name: "highpass", Params: map[string]string{"f": "80"}},
+				{Name: "afftdn", Params: map[string]string{"nr": "12", "nf": "-50"}},
+				{Name: "dynaudnorm"},
+				{Name: "aresample", Params: map[string]string{"osr": "16000"}},
+			},
+		},
+		{
+			name:    "filter not allowed",
+			filters: []entity.Filter{{Name: "amovie", Params: map[string]string{}}},
+			wantErr: true,
+		},
+		{
+			name:    "parameter not allowed",
+			filters: []entity.Filter{{Name: "highpass", Params: map[string]string{"enable": "1"}}},
+			wantErr: true,
+		},
+		{
+			name:    "value escapes filter syntax",
+			filters: []entity.Filter{{Name: "volume", Params: map[string]string{"volume": "2,amovie=/etc/passwd"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFilters(tt.filters)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConvertStream(t *testing.T) {
+	filters := []entity.Filter{
+		{Name: "highpass", Params: map[string]string{"poles": "2", "f": "80"}},
+		{Name: "dynaudnorm"},
+	}
+
+	args := convertStream("in.m4a", "out.wav", "wav", filters).GetArgs()
+	assert.Equal(t, []string{"-i", "in.m4a", "-acodec", "pcm_s16le", "-af", "highpass=f=80:poles=2,dynaudnorm", "-ar", "44100", "out.wav"}, args)
+
+	args = convertStream("in.m4a", "out.wav", "wav", nil).GetArgs()
+	assert.NotContains(t, args, "-af")
+
+	// The output rate follows a resampling filter instead of overriding it.
+	args = convertStream("in.wav", "out.wav", "wav", []entity.Filter{{Name: "aresample", Params: map[string]string{"osr": "16000"}}}).GetArgs()
+	assert.Equal(t, []string{"-i", "in.wav", "-acodec", "pcm_s16le", "-af", "aresample=osr=16000", "-ar", "16000", "out.wav"}, args)
+}
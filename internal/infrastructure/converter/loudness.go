@@ -0,0 +1,95 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// loudnormStats is the JSON block ffmpeg's loudnorm filter prints with print_format=json.
+type loudnormStats struct {
+	InputI       string `json:"input_i"`
+	InputTP      string `json:"input_tp"`
+	InputLRA     string `json:"input_lra"`
+	InputThresh  string `json:"input_thresh"`
+	TargetOffset string `json:"target_offset"`
+}
+
+func (ac *AudioConverter) MeasureLoudness(ctx context.Context, inputPath string, target entity.LoudnessTarget) (*entity.Loudness, error) {
+	var stderr bytes.Buffer
+	err := ffmpeg.Input(inputPath).
+		Output("-", ffmpeg.KwArgs{
+			"af": fmt.Sprintf("%s:print_format=json", loudnormFilter(target)),
+			"f":  "null",
+		}).
+		WithErrorOutput(&stderr).
+		Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure loudness: %v", err)
+	}
+	return parseLoudnormOutput(stderr.String())
+}
+
+func (ac *AudioConverter) NormalizeLoudness(ctx context.Context, inputPath, outputPath string, target entity.LoudnessTarget, measured *entity.Loudness) error {
+	filter := fmt.Sprintf("%s:measured_I=%.2f:measured_TP=%.2f:measured_LRA=%.2f:measured_thresh=%.2f:offset=%.2f:linear=true",
+		loudnormFilter(target), measured.Integrated, measured.TruePeak, measured.Range, measured.Threshold, measured.TargetOffset)
+
+	err := ffmpeg.Input(inputPath).
+		Output(outputPath, ffmpeg.KwArgs{
+			"af": filter,
+			// loudnorm upsamples internally; bring the result back to the canonical format
+			"acodec": "pcm_s16le",
+			"ar":     "44100",
+		}).
+		OverWriteOutput().
+		Run()
+	if err != nil {
+		return fmt.Errorf("failed to normalize loudness: %v", err)
+	}
+	return nil
+}
+
+func loudnormFilter(target entity.LoudnessTarget) string {
+	return fmt.Sprintf("loudnorm=I=%.1f:TP=%.1f:LRA=%.1f", target.Integrated, target.TruePeak, target.Range)
+}
+
+// parseLoudnormOutput extracts the measurement from ffmpeg's stderr, where
+// loudnorm prints it as the last JSON object.
+func parseLoudnormOutput(stderr string) (*entity.Loudness, error) {
+	start := strings.LastIndex(stderr, "{")
+	end := strings.LastIndex(stderr, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("failed to find loudness measurement in ffmpeg output")
+	}
+
+	var stats loudnormStats
+	if err := json.Unmarshal([]byte(stderr[start:end+1]), &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse loudness measurement: %v", err)
+	}
+
+	// Silent input reports -inf, which is not a usable measurement.
+	values := []string{stats.InputI, stats.InputTP, stats.InputLRA, stats.InputThresh, stats.TargetOffset}
+	parsed := make([]float64, len(values))
+	for i, v := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("invalid loudness value %q", v)
+		}
+		parsed[i] = f
+	}
+
+	return &entity.Loudness{
+		Integrated:   parsed[0],
+		TruePeak:     parsed[1],
+		Range:        parsed[2],
+		Threshold:    parsed[3],
+		TargetOffset: parsed[4],
+	}, nil
+}
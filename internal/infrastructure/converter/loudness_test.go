@@ -0,0 +1,52 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLoudnormOutput(t *testing.T) {
+	t.Run("measurement", func(t *testing.T) {
+		stderr := `size=N/A time=00:00:02.50 bitrate=N/A speed= 120x
+[Parsed_loudnorm_0 @ 0x5581] 
+{
+	"input_i" : "-31.42",
+	"input_tp" : "-12.07",
+	"input_lra" : "3.60",
+	"input_thresh" : "-41.68",
+	"output_i" : "-23.55",
+	"output_tp" : "-3.65",
+	"output_lra" : "2.80",
+	"output_thresh" : "-33.76",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.55"
+}
+`
+		loudness, err := parseLoudnormOutput(stderr)
+		require.NoError(t, err)
+		assert.Equal(t, &entity.Loudness{
+			Integrated:   -31.42,
+			TruePeak:     -12.07,
+			Range:        3.6,
+			Threshold:    -41.68,
+			TargetOffset: 0.55,
+		}, loudness)
+	})
+
+	t.Run("silent input", func(t *testing.T) {
+		_, err := parseLoudnormOutput(`{"input_i" : "-inf", "input_tp" : "-inf", "input_lra" : "0.00", "input_thresh" : "-70.00", "target_offset" : "inf"}`)
+		assert.Error(t, err)
+	})
+
+	t.Run("no measurement", func(t *testing.T) {
+		_, err := parseLoudnormOutput("Output file is empty, nothing was encoded")
+		assert.Error(t, err)
+	})
+}
+
+func TestLoudnormFilter(t *testing.T) {
+	assert.Equal(t, "loudnorm=I=-23.0:TP=-1.0:LRA=7.0", loudnormFilter(entity.LoudnessTarget{Integrated: -23, TruePeak: -1, Range: 7}))
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
@@ -23,17 +25,24 @@ func (_m *MockAudioConverter) EXPECT() *MockAudioConverter_Expecter {
 	return &MockAudioConverter_Expecter{mock: &_m.Mock}
 }
 
-// Convert provides a mock function with given fields: ctx, inputPath, outputPath, outputFormat
-func (_m *MockAudioConverter) Convert(ctx context.Context, inputPath string, outputPath string, outputFormat string) error {
-	ret := _m.Called(ctx, inputPath, outputPath, outputFormat)
+// Convert provides a mock function with given fields: ctx, inputPath, outputPath, outputFormat, filters
+func (_m *MockAudioConverter) Convert(ctx context.Context, inputPath string, outputPath string, outputFormat string, filters ...entity.Filter) error {
+	_va := make([]interface{}, len(filters))
+	for _i := range filters {
+		_va[_i] = filters[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, inputPath, outputPath, outputFormat)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Convert")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
-		r0 = rf(ctx, inputPath, outputPath, outputFormat)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, ...entity.Filter) error); ok {
+		r0 = rf(ctx, inputPath, outputPath, outputFormat, filters...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -51,13 +60,21 @@ type MockAudioConverter_Convert_Call struct {
 //   - inputPath string
 //   - outputPath string
 //   - outputFormat string
-func (_e *MockAudioConverter_Expecter) Convert(ctx interface{}, inputPath interface{}, outputPath interface{}, outputFormat interface{}) *MockAudioConverter_Convert_Call {
-	return &MockAudioConverter_Convert_Call{Call: _e.mock.On("Convert", ctx, inputPath, outputPath, outputFormat)}
+//   - filters ...entity.Filter
+func (_e *MockAudioConverter_Expecter) Convert(ctx interface{}, inputPath interface{}, outputPath interface{}, outputFormat interface{}, filters ...interface{}) *MockAudioConverter_Convert_Call {
+	return &MockAudioConverter_Convert_Call{Call: _e.mock.On("Convert",
+		append([]interface{}{ctx, inputPath, outputPath, outputFormat}, filters...)...)}
 }
 
-func (_c *MockAudioConverter_Convert_Call) Run(run func(ctx context.Context, inputPath string, outputPath string, outputFormat string)) *MockAudioConverter_Convert_Call {
+func (_c *MockAudioConverter_Convert_Call) Run(run func(ctx context.Context, inputPath string, outputPath string, outputFormat string, filters ...entity.Filter)) *MockAudioConverter_Convert_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+		variadicArgs := make([]entity.Filter, len(args)-4)
+		for i, a := range args[4:] {
+			if a != nil {
+				variadicArgs[i] = a.(entity.Filter)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), variadicArgs...)
 	})
 	return _c
 }
@@ -67,14 +84,21 @@ func (_c *MockAudioConverter_Convert_Call) Return(_a0 error) *MockAudioConverter
 	return _c
 }
 
-func (_c *MockAudioConverter_Convert_Call) RunAndReturn(run func(context.Context, string, string, string) error) *MockAudioConverter_Convert_Call {
+func (_c *MockAudioConverter_Convert_Call) RunAndReturn(run func(context.Context, string, string, string, ...entity.Filter) error) *MockAudioConverter_Convert_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ConvertFromReader provides a mock function with given fields: ctx, reader, originalFormat, outputFormat
-func (_m *MockAudioConverter) ConvertFromReader(ctx context.Context, reader io.Reader, originalFormat string, outputFormat string) (io.ReadCloser, error) {
-	ret := _m.Called(ctx, reader, originalFormat, outputFormat)
+// ConvertFromReader provides a mock function with given fields: ctx, reader, originalFormat, outputFormat, filters
+func (_m *MockAudioConverter) ConvertFromReader(ctx context.Context, reader io.Reader, originalFormat string, outputFormat string, filters ...entity.Filter) (io.ReadCloser, error) {
+	_va := make([]interface{}, len(filters))
+	for _i := range filters {
+		_va[_i] = filters[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, reader, originalFormat, outputFormat)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ConvertFromReader")
@@ -82,19 +106,19 @@ func (_m *MockAudioConverter) ConvertFromReader(ctx context.Context, reader io.R
 
 	var r0 io.ReadCloser
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string, string) (io.ReadCloser, error)); ok {
-		return rf(ctx, reader, originalFormat, outputFormat)
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string, string, ...entity.Filter) (io.ReadCloser, error)); ok {
+		return rf(ctx, reader, originalFormat, outputFormat, filters...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string, string) io.ReadCloser); ok {
-		r0 = rf(ctx, reader, originalFormat, outputFormat)
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, string, string, ...entity.Filter) io.ReadCloser); ok {
+		r0 = rf(ctx, reader, originalFormat, outputFormat, filters...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(io.ReadCloser)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, string, string) error); ok {
-		r1 = rf(ctx, reader, originalFormat, outputFormat)
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, string, string, ...entity.Filter) error); ok {
+		r1 = rf(ctx, reader, originalFormat, outputFormat, filters...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -112,13 +136,21 @@ type MockAudioConverter_ConvertFromReader_Call struct {
 //   - reader io.Reader
 //   - originalFormat string
 //   - outputFormat string
-func (_e *MockAudioConverter_Expecter) ConvertFromReader(ctx interface{}, reader interface{}, originalFormat interface{}, outputFormat interface{}) *MockAudioConverter_ConvertFromReader_Call {
-	return &MockAudioConverter_ConvertFromReader_Call{Call: _e.mock.On("ConvertFromReader", ctx, reader, originalFormat, outputFormat)}
+//   - filters ...entity.Filter
+func (_e *MockAudioConverter_Expecter) ConvertFromReader(ctx interface{}, reader interface{}, originalFormat interface{}, outputFormat interface{}, filters ...interface{}) *MockAudioConverter_ConvertFromReader_Call {
+	return &MockAudioConverter_ConvertFromReader_Call{Call: _e.mock.On("ConvertFromReader",
+		append([]interface{}{ctx, reader, originalFormat, outputFormat}, filters...)...)}
 }
 
-func (_c *MockAudioConverter_ConvertFromReader_Call) Run(run func(ctx context.Context, reader io.Reader, originalFormat string, outputFormat string)) *MockAudioConverter_ConvertFromReader_Call {
+func (_c *MockAudioConverter_ConvertFromReader_Call) Run(run func(ctx context.Context, reader io.Reader, originalFormat string, outputFormat string, filters ...entity.Filter)) *MockAudioConverter_ConvertFromReader_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(io.Reader), args[2].(string), args[3].(string))
+		variadicArgs := make([]entity.Filter, len(args)-4)
+		for i, a := range args[4:] {
+			if a != nil {
+				variadicArgs[i] = a.(entity.Filter)
+			}
+		}
+		run(args[0].(context.Context), args[1].(io.Reader), args[2].(string), args[3].(string), variadicArgs...)
 	})
 	return _c
 }
@@ -128,7 +160,176 @@ func (_c *MockAudioConverter_ConvertFromReader_Call) Return(_a0 io.ReadCloser, _
 	return _c
 }
 
-func (_c *MockAudioConverter_ConvertFromReader_Call) RunAndReturn(run func(context.Context, io.Reader, string, string) (io.ReadCloser, error)) *MockAudioConverter_ConvertFromReader_Call {
+func (_c *MockAudioConverter_ConvertFromReader_Call) RunAndReturn(run func(context.Context, io.Reader, string, string, ...entity.Filter) (io.ReadCloser, error)) *MockAudioConverter_ConvertFromReader_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MeasureLoudness provides a mock function with given fields: ctx, inputPath, target
+func (_m *MockAudioConverter) MeasureLoudness(ctx context.Context, inputPath string, target entity.LoudnessTarget) (*entity.Loudness, error) {
+	ret := _m.Called(ctx, inputPath, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MeasureLoudness")
+	}
+
+	var r0 *entity.Loudness
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.LoudnessTarget) (*entity.Loudness, error)); ok {
+		return rf(ctx, inputPath, target)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.LoudnessTarget) *entity.Loudness); ok {
+		r0 = rf(ctx, inputPath, target)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Loudness)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.LoudnessTarget) error); ok {
+		r1 = rf(ctx, inputPath, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioConverter_MeasureLoudness_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MeasureLoudness'
+type MockAudioConverter_MeasureLoudness_Call struct {
+	*mock.Call
+}
+
+// MeasureLoudness is a helper method to define mock.On call
+//   - ctx context.Context
+//   - inputPath string
+//   - target entity.LoudnessTarget
+func (_e *MockAudioConverter_Expecter) MeasureLoudness(ctx interface{}, inputPath interface{}, target interface{}) *MockAudioConverter_MeasureLoudness_Call {
+	return &MockAudioConverter_MeasureLoudness_Call{Call: _e.mock.On("MeasureLoudness", ctx, inputPath, target)}
+}
+
+func (_c *MockAudioConverter_MeasureLoudness_Call) Run(run func(ctx context.Context, inputPath string, target entity.LoudnessTarget)) *MockAudioConverter_MeasureLoudness_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(entity.LoudnessTarget))
+	})
+	return _c
+}
+
+func (_c *MockAudioConverter_MeasureLoudness_Call) Return(_a0 *entity.Loudness, _a1 error) *MockAudioConverter_MeasureLoudness_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioConverter_MeasureLoudness_Call) RunAndReturn(run func(context.Context, string, entity.LoudnessTarget) (*entity.Loudness, error)) *MockAudioConverter_MeasureLoudness_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NormalizeLoudness provides a mock function with given fields: ctx, inputPath, outputPath, target, measured
+func (_m *MockAudioConverter) NormalizeLoudness(ctx context.Context, inputPath string, outputPath string, target entity.LoudnessTarget, measured *entity.Loudness) error {
+	ret := _m.Called(ctx, inputPath, outputPath, target, measured)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NormalizeLoudness")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.LoudnessTarget, *entity.Loudness) error); ok {
+		r0 = rf(ctx, inputPath, outputPath, target, measured)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAudioConverter_NormalizeLoudness_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NormalizeLoudness'
+type MockAudioConverter_NormalizeLoudness_Call struct {
+	*mock.Call
+}
+
+// NormalizeLoudness is a helper method to define mock.On call
+//   - ctx context.Context
+//   - inputPath string
+//   - outputPath string
+//   - target entity.LoudnessTarget
+//   - measured *entity.Loudness
+func (_e *MockAudioConverter_Expecter) NormalizeLoudness(ctx interface{}, inputPath interface{}, outputPath interface{}, target interface{}, measured interface{}) *MockAudioConverter_NormalizeLoudness_Call {
+	return &MockAudioConverter_NormalizeLoudness_Call{Call: _e.mock.On("NormalizeLoudness", ctx, inputPath, outputPath, target, measured)}
+}
+
+func (_c *MockAudioConverter_NormalizeLoudness_Call) Run(run func(ctx context.Context, inputPath string, outputPath string, target entity.LoudnessTarget, measured *entity.Loudness)) *MockAudioConverter_NormalizeLoudness_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(entity.LoudnessTarget), args[4].(*entity.Loudness))
+	})
+	return _c
+}
+
+func (_c *MockAudioConverter_NormalizeLoudness_Call) Return(_a0 error) *MockAudioConverter_NormalizeLoudness_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAudioConverter_NormalizeLoudness_Call) RunAndReturn(run func(context.Context, string, string, entity.LoudnessTarget, *entity.Loudness) error) *MockAudioConverter_NormalizeLoudness_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Probe provides a mock function with given fields: ctx, inputPath
+func (_m *MockAudioConverter) Probe(ctx context.Context, inputPath string) (*entity.AudioMetadata, error) {
+	ret := _m.Called(ctx, inputPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Probe")
+	}
+
+	var r0 *entity.AudioMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.AudioMetadata, error)); ok {
+		return rf(ctx, inputPath)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.AudioMetadata); ok {
+		r0 = rf(ctx, inputPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AudioMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, inputPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioConverter_Probe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Probe'
+type MockAudioConverter_Probe_Call struct {
+	*mock.Call
+}
+
+// Probe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - inputPath string
+func (_e *MockAudioConverter_Expecter) Probe(ctx interface{}, inputPath interface{}) *MockAudioConverter_Probe_Call {
+	return &MockAudioConverter_Probe_Call{Call: _e.mock.On("Probe", ctx, inputPath)}
+}
+
+func (_c *MockAudioConverter_Probe_Call) Run(run func(ctx context.Context, inputPath string)) *MockAudioConverter_Probe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAudioConverter_Probe_Call) Return(_a0 *entity.AudioMetadata, _a1 error) *MockAudioConverter_Probe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioConverter_Probe_Call) RunAndReturn(run func(context.Context, string) (*entity.AudioMetadata, error)) *MockAudioConverter_Probe_Call {
 	_c.Call.Return(run)
 	return _c
 }
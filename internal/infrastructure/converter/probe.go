@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// probeOutput is the subset of `ffprobe -show_format -show_streams -of json` we use.
+type probeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+		Size       string `json:"size"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType  string `json:"codec_type"`
+		CodecName  string `json:"codec_name"`
+		SampleRate string `json:"sample_rate"`
+		Channels   int    `json:"channels"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"streams"`
+}
+
+func (ac *AudioConverter) Probe(ctx context.Context, inputPath string) (*entity.AudioMetadata, error) {
+	out, err := ffmpeg.Probe(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe audio: %v", err)
+	}
+	return parseProbeOutput(out)
+}
+
+// parseProbeOutput extracts audio metadata from ffprobe's JSON output.
+func parseProbeOutput(data string) (*entity.AudioMetadata, error) {
+	var out probeOutput
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse probe output: %v", err)
+	}
+
+	meta := &entity.AudioMetadata{
+		Format:   containerFormat(out.Format.FormatName),
+		Duration: parseFloat(out.Format.Duration),
+		BitRate:  parseInt(out.Format.BitRate),
+		FileSize: parseInt(out.Format.Size),
+	}
+	if meta.Format == "" {
+		return nil, fmt.Errorf("failed to probe audio: unknown container")
+	}
+
+	for _, stream := range out.Streams {
+		if stream.CodecType != "audio" {
+			continue
+		}
+		meta.Codec = stream.CodecName
+		meta.SampleRate = int(parseInt(stream.SampleRate))
+		meta.Channels = stream.Channels
+		if meta.BitRate == 0 {
+			meta.BitRate = parseInt(stream.BitRate)
+		}
+		return meta, nil
+	}
+	return nil, fmt.Errorf("failed to probe audio: no audio stream found")
+}
+
+// containerFormat maps ffprobe's format_name, which may list several
+// demuxer aliases, to the file extension we use for that container.
+func containerFormat(formatName string) string {
+	names := strings.Split(formatName, ",")
+	for _, name := range names {
+		switch name {
+		case "m4a":
+			return "m4a"
+		case "webm":
+			return "webm"
+		}
+	}
+	return names[0]
+}
+
+func parseFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+func parseInt(s string) int64 {
+	v, _ := strconv.ParseInt(s, 10, 64)
+	return v
+}
@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProbeOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected *entity.AudioMetadata
+		wantErr  bool
+	}{
+		{
+			name: "m4a with aac stream",
+			output: `{
+				"streams": [{"codec_type": "audio", "codec_name": "aac", "sample_rate": "48000", "channels": 2, "bit_rate": "127999"}],
+				"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "2.500000", "bit_rate": "130000", "size": "40625"}
+			}`,
+			expected: &entity.AudioMetadata{
+				Format:     "m4a",
+				Duration:   2.5,
+				SampleRate: 48000,
+				Channels:   2,
+				Codec:      "aac",
+				BitRate:    130000,
+				FileSize:   40625,
+			},
+		},
+		{
+			name: "wav falls back to stream bit rate",
+			output: `{
+				"streams": [{"codec_type": "audio", "codec_name": "pcm_s16le", "sample_rate": "44100", "channels": 1, "bit_rate": "705600"}],
+				"format": {"format_name": "wav", "duration": "1.000000", "size": "88244"}
+			}`,
+			expected: &entity.AudioMetadata{
+				Format:     "wav",
+				Duration:   1,
+				SampleRate: 44100,
+				Channels:   1,
+				Codec:      "pcm_s16le",
+				BitRate:    705600,
+				FileSize:   88244,
+			},
+		},
+		{
+			name: "skips non-audio streams",
+			output: `{
+				"streams": [
+					{"codec_type": "video", "codec_name": "mjpeg"},
+					{"codec_type": "audio", "codec_name": "mp3", "sample_rate": "44100", "channels": 2}
+				],
+				"format": {"format_name": "mp3", "duration": "3.0", "bit_rate": "192000", "size": "72000"}
+			}`,
+			expected: &entity.AudioMetadata{
+				Format:     "mp3",
+				Duration:   3,
+				SampleRate: 44100,
+				Channels:   2,
+				Codec:      "mp3",
+				BitRate:    192000,
+				FileSize:   72000,
+			},
+		},
+		{
+			name:    "no audio stream",
+			output:  `{"streams": [{"codec_type": "video"}], "format": {"format_name": "image2"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			output:  `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := parseProbeOutput(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, meta)
+		})
+	}
+}
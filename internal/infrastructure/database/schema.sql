@@ -8,21 +8,203 @@ CREATE TABLE IF NOT EXISTS audios (
     updated_at DATETIME NOT NULL,
     error TEXT,
     user_id INTEGER NOT NULL,
-    phrase_id INTEGER NOT NULL
+    phrase_id INTEGER NOT NULL,
+    duration REAL NOT NULL DEFAULT 0,
+    sample_rate INTEGER NOT NULL DEFAULT 0,
+    channels INTEGER NOT NULL DEFAULT 0,
+    codec TEXT NOT NULL DEFAULT '',
+    bit_rate INTEGER NOT NULL DEFAULT 0,
+    file_size INTEGER NOT NULL DEFAULT 0,
+    loudness_integrated REAL,
+    loudness_true_peak REAL,
+    loudness_range REAL,
+    loudness_threshold REAL,
+    normalized_path TEXT NOT NULL DEFAULT '',
+    trim_start REAL,
+    trim_end REAL,
+    needs_review BOOLEAN NOT NULL DEFAULT 0,
+    similarity_score REAL,
+    stale BOOLEAN NOT NULL DEFAULT 0,
+    session_id INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE TABLE IF NOT EXISTS users (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     name TEXT NOT NULL,
     created_at DATETIME NOT NULL,
-    updated_at DATETIME NOT NULL
+    updated_at DATETIME NOT NULL,
+    processing_profile TEXT NOT NULL DEFAULT '',
+    attributes TEXT NOT NULL DEFAULT '{}',
+    deleting BOOLEAN NOT NULL DEFAULT 0
 );
 
 CREATE TABLE IF NOT EXISTS phrases (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     phrase TEXT NOT NULL,
     created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    reference_path TEXT NOT NULL DEFAULT '',
+    user_id INTEGER NOT NULL DEFAULT 0,
+    revision INTEGER NOT NULL DEFAULT 0,
+    language TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE TABLE IF NOT EXISTS phrase_revisions (
+    phrase_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    phrase TEXT NOT NULL,
+    replaced_at DATETIME NOT NULL,
+    PRIMARY KEY (phrase_id, revision)
+);
+
+CREATE TABLE IF NOT EXISTS phrase_shares (
+    phrase_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (phrase_id, user_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_phrase ON audios (user_id, phrase_id);
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nonce TEXT NOT NULL UNIQUE,
+    audio_id INTEGER NOT NULL,
+    format TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    single_use BOOLEAN NOT NULL DEFAULT 0,
+    ip TEXT NOT NULL DEFAULT '',
+    used_at DATETIME,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS uploads (
+    id TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    phrase_id INTEGER NOT NULL,
+    filename TEXT NOT NULL,
+    length INTEGER NOT NULL,
+    upload_offset INTEGER NOT NULL DEFAULT 0,
+    metadata TEXT NOT NULL DEFAULT '',
+    audio_id INTEGER,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    session_id INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS compilations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    phrase_ids TEXT NOT NULL,
+    format TEXT NOT NULL,
+    gap REAL NOT NULL DEFAULT 0,
+    crossfade REAL NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    storage_path TEXT NOT NULL DEFAULT '',
+    manifest_path TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS audio_analysis (
+    audio_id INTEGER PRIMARY KEY,
+    peak_db REAL NOT NULL,
+    rms_db REAL NOT NULL,
+    clipped_ratio REAL NOT NULL,
+    noise_floor_db REAL NOT NULL,
+    snr_db REAL NOT NULL,
+    speech_ratio REAL NOT NULL,
+    problems TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL,
     updated_at DATETIME NOT NULL
 );
 
-CREATE UNIQUE INDEX IF NOT EXISTS idx_user_phrase ON audios (user_id, phrase_id); 
\ No newline at end of file
+CREATE TABLE IF NOT EXISTS audio_reviews (
+    audio_id INTEGER PRIMARY KEY,
+    status TEXT NOT NULL,
+    reviewer_id INTEGER NOT NULL,
+    reasons TEXT NOT NULL DEFAULT '[]',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audio_reviews_status ON audio_reviews (status);
+
+CREATE TABLE IF NOT EXISTS annotations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    audio_id INTEGER NOT NULL,
+    tier TEXT NOT NULL,
+    label TEXT NOT NULL,
+    start_time REAL NOT NULL,
+    end_time REAL NOT NULL,
+    attributes TEXT NOT NULL DEFAULT '{}',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_annotations_audio ON annotations (audio_id, tier, start_time);
+
+CREATE TABLE IF NOT EXISTS user_deletions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    phrases INTEGER NOT NULL DEFAULT 0,
+    audio INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    language TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS collection_phrases (
+    collection_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    phrase_id INTEGER NOT NULL,
+    PRIMARY KEY (collection_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_phrases_phrase ON collection_phrases (phrase_id);
+
+CREATE TABLE IF NOT EXISTS collection_assignments (
+    collection_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (collection_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS recording_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    device TEXT NOT NULL DEFAULT '',
+    client TEXT NOT NULL DEFAULT '',
+    sample_rate INTEGER NOT NULL DEFAULT 0,
+    microphone TEXT NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    closed_at DATETIME,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_recording_sessions_user ON recording_sessions (user_id);
+
+CREATE TABLE IF NOT EXISTS session_reviews (
+    session_id INTEGER PRIMARY KEY,
+    status TEXT NOT NULL,
+    reviewer_id INTEGER NOT NULL,
+    reasons TEXT NOT NULL DEFAULT '[]',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
@@ -11,6 +11,68 @@ import (
 //go:embed schema.sql
 var schemaFS embed.FS
 
+// columnMigration adds a column that databases created by an older schema lack.
+type columnMigration struct {
+	table      string
+	column     string
+	definition string
+}
+
+// columnMigrations must mirror the columns added to schema.sql over time.
+var columnMigrations = []columnMigration{
+	{"audios", "duration", "REAL NOT NULL DEFAULT 0"},
+	{"audios", "sample_rate", "INTEGER NOT NULL DEFAULT 0"},
+	{"audios", "channels", "INTEGER NOT NULL DEFAULT 0"},
+	{"audios", "codec", "TEXT NOT NULL DEFAULT ''"},
+	{"audios", "bit_rate", "INTEGER NOT NULL DEFAULT 0"},
+	{"audios", "file_size", "INTEGER NOT NULL DEFAULT 0"},
+	{"audios", "loudness_integrated", "REAL"},
+	{"audios", "loudness_true_peak", "REAL"},
+	{"audios", "loudness_range", "REAL"},
+	{"audios", "loudness_threshold", "REAL"},
+	{"audios", "normalized_path", "TEXT NOT NULL DEFAULT ''"},
+	{"audios", "trim_start", "REAL"},
+	{"audios", "trim_end", "REAL"},
+	{"audios", "needs_review", "BOOLEAN NOT NULL DEFAULT 0"},
+	{"users", "processing_profile", "TEXT NOT NULL DEFAULT ''"},
+	{"audios", "similarity_score", "REAL"},
+	{"phrases", "reference_path", "TEXT NOT NULL DEFAULT ''"},
+	{"phrases", "user_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"phrases", "revision", "INTEGER NOT NULL DEFAULT 0"},
+	{"audios", "stale", "BOOLEAN NOT NULL DEFAULT 0"},
+	{"phrases", "language", "TEXT NOT NULL DEFAULT ''"},
+	{"phrases", "tags", "TEXT NOT NULL DEFAULT '[]'"},
+	{"users", "attributes", "TEXT NOT NULL DEFAULT '{}'"},
+	{"audios", "session_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"uploads", "session_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"users", "deleting", "BOOLEAN NOT NULL DEFAULT 0"},
+}
+
+// dataMigrations run after the column migrations on every start, so they must be idempotent.
+var dataMigrations = []string{
+	// Formats used to be stored with the leading dot of the file extension.
+	`UPDATE audios SET current_format = substr(current_format, 2) WHERE current_format LIKE '.%'`,
+	// Indexes on migrated columns are created here, once the columns exist.
+	`CREATE INDEX IF NOT EXISTS idx_audios_session ON audios (session_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_phrases_user ON phrases (user_id)`,
+}
+
+// columnBackfills run once, right after the column they are keyed by is
+// added, to fill it in for existing rows.
+var columnBackfills = map[string][]string{
+	// Phrase owners used to be discarded. Existing phrases are given to the
+	// first user who recorded them and shared with everyone else who did, so
+	// nobody loses access to their takes. Phrases nobody recorded keep owner
+	// 0 until a user claims them.
+	"phrases.user_id": {
+		`UPDATE phrases SET user_id = (SELECT user_id FROM audios WHERE phrase_id = phrases.id ORDER BY id LIMIT 1)
+		WHERE EXISTS (SELECT 1 FROM audios WHERE phrase_id = phrases.id)`,
+		`INSERT OR IGNORE INTO phrase_shares (phrase_id, user_id, created_at)
+		SELECT DISTINCT audios.phrase_id, audios.user_id, CURRENT_TIMESTAMP FROM audios
+		JOIN phrases ON phrases.id = audios.phrase_id WHERE audios.user_id != phrases.user_id`,
+	},
+}
+
 // InitDB initializes and returns a new SQLite database connection
 func InitDB(dbPath string) (*sqlx.DB, error) {
 	db, err := sqlx.Connect("sqlite3", dbPath)
@@ -21,6 +83,10 @@ func InitDB(dbPath string) (*sqlx.DB, error) {
 		db.Close() // Close the db connection if table creation fails
 		return nil, fmt.Errorf("failed to create table: %v", err)
 	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %v", err)
+	}
 	return db, nil
 }
 
@@ -41,3 +107,40 @@ func createTable(db *sqlx.DB) error {
 	}
 	return nil
 }
+
+// migrate brings a database created by an older schema up to date.
+func migrate(db *sqlx.DB) error {
+	for _, m := range columnMigrations {
+		exists, err := columnExists(db, m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.definition)); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %v", m.table, m.column, err)
+		}
+		for _, stmt := range columnBackfills[m.table+"."+m.column] {
+			if _, err := db.Exec(stmt); err != nil {
+				return fmt.Errorf("failed to backfill column %s.%s: %v", m.table, m.column, err)
+			}
+		}
+	}
+
+	for _, stmt := range dataMigrations {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to run data migration: %v", err)
+		}
+	}
+	return nil
+}
+
+func columnExists(db *sqlx.DB, table, column string) (bool, error) {
+	var count int
+	err := db.Get(&count, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %v", table, err)
+	}
+	return count > 0, nil
+}
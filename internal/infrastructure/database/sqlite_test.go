@@ -149,3 +149,104 @@ func TestCreateTable_ErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestInitDB_MigratesOldSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+
+	// Create a database with the original audios table and a legacy row.
+	old, err := sqlx.Connect("sqlite3", dbPath)
+	require.NoError(t, err)
+	_, err = old.Exec(`
+		CREATE TABLE audios (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			original_name TEXT NOT NULL,
+			current_format TEXT NOT NULL,
+			storage_path TEXT,
+			status TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			error TEXT,
+			user_id INTEGER NOT NULL,
+			phrase_id INTEGER NOT NULL
+		);
+		INSERT INTO audios (original_name, current_format, storage_path, status, created_at, updated_at, user_id, phrase_id)
+		VALUES ('a.m4a', '.m4a', 'audio/original/1-1.m4a', 'pending', '2024-01-01', '2024-01-01', 1, 1);
+	`)
+	require.NoError(t, err)
+	require.NoError(t, old.Close())
+
+	db, err := InitDB(dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, m := range columnMigrations {
+		exists, err := columnExists(db, m.table, m.column)
+		require.NoError(t, err)
+		assert.True(t, exists, "column %s.%s should exist", m.table, m.column)
+	}
+
+	var format string
+	require.NoError(t, db.Get(&format, `SELECT current_format FROM audios WHERE id = 1`))
+	assert.Equal(t, "m4a", format)
+
+	// Running the migrations again must be a no-op.
+	require.NoError(t, migrate(db))
+}
+
+func TestInitDB_BackfillsPhraseOwners(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+
+	// Create a database whose phrases table predates phrase ownership.
+	old, err := sqlx.Connect("sqlite3", dbPath)
+	require.NoError(t, err)
+	_, err = old.Exec(`
+		CREATE TABLE phrases (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			phrase TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+		CREATE TABLE audios (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			original_name TEXT NOT NULL,
+			current_format TEXT NOT NULL,
+			storage_path TEXT,
+			status TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			error TEXT,
+			user_id INTEGER NOT NULL,
+			phrase_id INTEGER NOT NULL
+		);
+		INSERT INTO phrases (phrase, created_at, updated_at) VALUES ('recorded', '2024-01-01', '2024-01-01'), ('unrecorded', '2024-01-01', '2024-01-01');
+		INSERT INTO audios (original_name, current_format, status, created_at, updated_at, user_id, phrase_id)
+		VALUES ('a.m4a', 'm4a', 'completed', '2024-01-01', '2024-01-01', 2, 1), ('b.m4a', 'm4a', 'completed', '2024-01-01', '2024-01-01', 3, 1);
+	`)
+	require.NoError(t, err)
+	require.NoError(t, old.Close())
+
+	db, err := InitDB(dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var owners []uint
+	require.NoError(t, db.Select(&owners, `SELECT user_id FROM phrases ORDER BY id`))
+	// The unrecorded phrase has no owner until a user claims it.
+	assert.Equal(t, []uint{2, 0}, owners)
+
+	var indexes int
+	require.NoError(t, db.Get(&indexes, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_phrases_user'`))
+	assert.Equal(t, 1, indexes)
+
+	var shared []uint
+	require.NoError(t, db.Select(&shared, `SELECT user_id FROM phrase_shares WHERE phrase_id = 1`))
+	assert.Equal(t, []uint{3}, shared)
+
+	// A share removed later is not restored on the next start.
+	_, err = db.Exec(`DELETE FROM phrase_shares`)
+	require.NoError(t, err)
+	require.NoError(t, migrate(db))
+	var count int
+	require.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM phrase_shares`))
+	assert.Zero(t, count)
+}
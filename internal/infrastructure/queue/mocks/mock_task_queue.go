@@ -22,6 +22,53 @@ func (_m *MockTaskQueue) EXPECT() *MockTaskQueue_Expecter {
 	return &MockTaskQueue_Expecter{mock: &_m.Mock}
 }
 
+// Cancel provides a mock function with given fields: ctx, payload
+func (_m *MockTaskQueue) Cancel(ctx context.Context, payload uint) error {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTaskQueue_Cancel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Cancel'
+type MockTaskQueue_Cancel_Call struct {
+	*mock.Call
+}
+
+// Cancel is a helper method to define mock.On call
+//   - ctx context.Context
+//   - payload uint
+func (_e *MockTaskQueue_Expecter) Cancel(ctx interface{}, payload interface{}) *MockTaskQueue_Cancel_Call {
+	return &MockTaskQueue_Cancel_Call{Call: _e.mock.On("Cancel", ctx, payload)}
+}
+
+func (_c *MockTaskQueue_Cancel_Call) Run(run func(ctx context.Context, payload uint)) *MockTaskQueue_Cancel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockTaskQueue_Cancel_Call) Return(_a0 error) *MockTaskQueue_Cancel_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTaskQueue_Cancel_Call) RunAndReturn(run func(context.Context, uint) error) *MockTaskQueue_Cancel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Complete provides a mock function with given fields: ctx, taskID
 func (_m *MockTaskQueue) Complete(ctx context.Context, taskID string) error {
 	ret := _m.Called(ctx, taskID)
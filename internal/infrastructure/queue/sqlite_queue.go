@@ -18,6 +18,7 @@ import (
 // SQLiteQueue implements queue.TaskQueue using goqite
 type SQLiteQueue struct {
 	queue *goqite.Queue
+	name  string
 	db    *sqlx.DB // Used for failed tasks table operations
 }
 
@@ -40,6 +41,7 @@ func NewSQLiteQueue(db *sqlx.DB, queueName string) (*SQLiteQueue, error) {
 
 	return &SQLiteQueue{
 		queue: q,
+		name:  queueName,
 		db:    db,
 	}, nil
 }
@@ -92,3 +94,13 @@ func (q *SQLiteQueue) Fail(ctx context.Context, taskID string, errMsg string) er
 	}
 	return nil
 }
+
+// Cancel deletes the messages of this queue with the given payload from the
+// goqite table, whether or not they have been received.
+func (q *SQLiteQueue) Cancel(ctx context.Context, payload uint) error {
+	_, err := q.db.ExecContext(ctx, `DELETE FROM goqite WHERE queue = ? AND body = ?`, q.name, []byte(fmt.Sprintf("%d", payload)))
+	if err != nil {
+		return fmt.Errorf("failed to cancel messages: %v", err)
+	}
+	return nil
+}
@@ -152,4 +152,28 @@ func TestSQLiteQueueOperations(t *testing.T) {
 			require.NoError(t, err)
 		}
 	})
+
+	t.Run("cancel tasks", func(t *testing.T) {
+		queueName := fmt.Sprintf("test_queue_%s_cancel", t.Name())
+		queue, err := NewSQLiteQueue(db, queueName)
+		require.NoError(t, err)
+		other, err := NewSQLiteQueue(db, queueName+"_other")
+		require.NoError(t, err)
+
+		require.NoError(t, queue.Enqueue(ctx, 7))
+		require.NoError(t, queue.Enqueue(ctx, 8))
+		require.NoError(t, other.Enqueue(ctx, 7))
+
+		require.NoError(t, queue.Cancel(ctx, 7))
+
+		task, err := queue.Dequeue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint(8), task.Payload)
+		require.NoError(t, queue.Complete(ctx, task.ID))
+
+		// Other queues keep their tasks with the same payload.
+		task, err = other.Dequeue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), task.Payload)
+	})
 }
@@ -0,0 +1,308 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAnnotationRepository is an autogenerated mock type for the AnnotationRepository type
+type MockAnnotationRepository struct {
+	mock.Mock
+}
+
+type MockAnnotationRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAnnotationRepository) EXPECT() *MockAnnotationRepository_Expecter {
+	return &MockAnnotationRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, annotation
+func (_m *MockAnnotationRepository) Create(ctx context.Context, annotation *entity.Annotation) (*entity.Annotation, error) {
+	ret := _m.Called(ctx, annotation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entity.Annotation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Annotation) (*entity.Annotation, error)); ok {
+		return rf(ctx, annotation)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Annotation) *entity.Annotation); ok {
+		r0 = rf(ctx, annotation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Annotation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Annotation) error); ok {
+		r1 = rf(ctx, annotation)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAnnotationRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockAnnotationRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - annotation *entity.Annotation
+func (_e *MockAnnotationRepository_Expecter) Create(ctx interface{}, annotation interface{}) *MockAnnotationRepository_Create_Call {
+	return &MockAnnotationRepository_Create_Call{Call: _e.mock.On("Create", ctx, annotation)}
+}
+
+func (_c *MockAnnotationRepository_Create_Call) Run(run func(ctx context.Context, annotation *entity.Annotation)) *MockAnnotationRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Annotation))
+	})
+	return _c
+}
+
+func (_c *MockAnnotationRepository_Create_Call) Return(_a0 *entity.Annotation, _a1 error) *MockAnnotationRepository_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAnnotationRepository_Create_Call) RunAndReturn(run func(context.Context, *entity.Annotation) (*entity.Annotation, error)) *MockAnnotationRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockAnnotationRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAnnotationRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockAnnotationRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockAnnotationRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockAnnotationRepository_Delete_Call {
+	return &MockAnnotationRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockAnnotationRepository_Delete_Call) Run(run func(ctx context.Context, id uint)) *MockAnnotationRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAnnotationRepository_Delete_Call) Return(_a0 error) *MockAnnotationRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAnnotationRepository_Delete_Call) RunAndReturn(run func(context.Context, uint) error) *MockAnnotationRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockAnnotationRepository) GetByID(ctx context.Context, id uint) (*entity.Annotation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entity.Annotation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.Annotation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.Annotation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Annotation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAnnotationRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockAnnotationRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockAnnotationRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockAnnotationRepository_GetByID_Call {
+	return &MockAnnotationRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockAnnotationRepository_GetByID_Call) Run(run func(ctx context.Context, id uint)) *MockAnnotationRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAnnotationRepository_GetByID_Call) Return(_a0 *entity.Annotation, _a1 error) *MockAnnotationRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAnnotationRepository_GetByID_Call) RunAndReturn(run func(context.Context, uint) (*entity.Annotation, error)) *MockAnnotationRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByAudioID provides a mock function with given fields: ctx, audioID
+func (_m *MockAnnotationRepository) ListByAudioID(ctx context.Context, audioID uint) ([]*entity.Annotation, error) {
+	ret := _m.Called(ctx, audioID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByAudioID")
+	}
+
+	var r0 []*entity.Annotation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Annotation, error)); ok {
+		return rf(ctx, audioID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Annotation); ok {
+		r0 = rf(ctx, audioID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Annotation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, audioID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAnnotationRepository_ListByAudioID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByAudioID'
+type MockAnnotationRepository_ListByAudioID_Call struct {
+	*mock.Call
+}
+
+// ListByAudioID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - audioID uint
+func (_e *MockAnnotationRepository_Expecter) ListByAudioID(ctx interface{}, audioID interface{}) *MockAnnotationRepository_ListByAudioID_Call {
+	return &MockAnnotationRepository_ListByAudioID_Call{Call: _e.mock.On("ListByAudioID", ctx, audioID)}
+}
+
+func (_c *MockAnnotationRepository_ListByAudioID_Call) Run(run func(ctx context.Context, audioID uint)) *MockAnnotationRepository_ListByAudioID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAnnotationRepository_ListByAudioID_Call) Return(_a0 []*entity.Annotation, _a1 error) *MockAnnotationRepository_ListByAudioID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAnnotationRepository_ListByAudioID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Annotation, error)) *MockAnnotationRepository_ListByAudioID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, annotation
+func (_m *MockAnnotationRepository) Update(ctx context.Context, annotation *entity.Annotation) error {
+	ret := _m.Called(ctx, annotation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Annotation) error); ok {
+		r0 = rf(ctx, annotation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAnnotationRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockAnnotationRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - annotation *entity.Annotation
+func (_e *MockAnnotationRepository_Expecter) Update(ctx interface{}, annotation interface{}) *MockAnnotationRepository_Update_Call {
+	return &MockAnnotationRepository_Update_Call{Call: _e.mock.On("Update", ctx, annotation)}
+}
+
+func (_c *MockAnnotationRepository_Update_Call) Run(run func(ctx context.Context, annotation *entity.Annotation)) *MockAnnotationRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Annotation))
+	})
+	return _c
+}
+
+func (_c *MockAnnotationRepository_Update_Call) Return(_a0 error) *MockAnnotationRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAnnotationRepository_Update_Call) RunAndReturn(run func(context.Context, *entity.Annotation) error) *MockAnnotationRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAnnotationRepository creates a new instance of MockAnnotationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAnnotationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAnnotationRepository {
+	mock := &MockAnnotationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
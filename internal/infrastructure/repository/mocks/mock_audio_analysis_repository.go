@@ -0,0 +1,143 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAudioAnalysisRepository is an autogenerated mock type for the AudioAnalysisRepository type
+type MockAudioAnalysisRepository struct {
+	mock.Mock
+}
+
+type MockAudioAnalysisRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAudioAnalysisRepository) EXPECT() *MockAudioAnalysisRepository_Expecter {
+	return &MockAudioAnalysisRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetByAudioID provides a mock function with given fields: ctx, audioID
+func (_m *MockAudioAnalysisRepository) GetByAudioID(ctx context.Context, audioID uint) (*entity.AudioAnalysis, error) {
+	ret := _m.Called(ctx, audioID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAudioID")
+	}
+
+	var r0 *entity.AudioAnalysis
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.AudioAnalysis, error)); ok {
+		return rf(ctx, audioID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.AudioAnalysis); ok {
+		r0 = rf(ctx, audioID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AudioAnalysis)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, audioID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioAnalysisRepository_GetByAudioID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByAudioID'
+type MockAudioAnalysisRepository_GetByAudioID_Call struct {
+	*mock.Call
+}
+
+// GetByAudioID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - audioID uint
+func (_e *MockAudioAnalysisRepository_Expecter) GetByAudioID(ctx interface{}, audioID interface{}) *MockAudioAnalysisRepository_GetByAudioID_Call {
+	return &MockAudioAnalysisRepository_GetByAudioID_Call{Call: _e.mock.On("GetByAudioID", ctx, audioID)}
+}
+
+func (_c *MockAudioAnalysisRepository_GetByAudioID_Call) Run(run func(ctx context.Context, audioID uint)) *MockAudioAnalysisRepository_GetByAudioID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioAnalysisRepository_GetByAudioID_Call) Return(_a0 *entity.AudioAnalysis, _a1 error) *MockAudioAnalysisRepository_GetByAudioID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioAnalysisRepository_GetByAudioID_Call) RunAndReturn(run func(context.Context, uint) (*entity.AudioAnalysis, error)) *MockAudioAnalysisRepository_GetByAudioID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, analysis
+func (_m *MockAudioAnalysisRepository) Save(ctx context.Context, analysis *entity.AudioAnalysis) error {
+	ret := _m.Called(ctx, analysis)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AudioAnalysis) error); ok {
+		r0 = rf(ctx, analysis)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAudioAnalysisRepository_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockAudioAnalysisRepository_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - analysis *entity.AudioAnalysis
+func (_e *MockAudioAnalysisRepository_Expecter) Save(ctx interface{}, analysis interface{}) *MockAudioAnalysisRepository_Save_Call {
+	return &MockAudioAnalysisRepository_Save_Call{Call: _e.mock.On("Save", ctx, analysis)}
+}
+
+func (_c *MockAudioAnalysisRepository_Save_Call) Run(run func(ctx context.Context, analysis *entity.AudioAnalysis)) *MockAudioAnalysisRepository_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.AudioAnalysis))
+	})
+	return _c
+}
+
+func (_c *MockAudioAnalysisRepository_Save_Call) Return(_a0 error) *MockAudioAnalysisRepository_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAudioAnalysisRepository_Save_Call) RunAndReturn(run func(context.Context, *entity.AudioAnalysis) error) *MockAudioAnalysisRepository_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAudioAnalysisRepository creates a new instance of MockAudioAnalysisRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAudioAnalysisRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAudioAnalysisRepository {
+	mock := &MockAudioAnalysisRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
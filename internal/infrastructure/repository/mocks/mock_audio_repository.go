@@ -22,6 +22,53 @@ func (_m *MockAudioRepository) EXPECT() *MockAudioRepository_Expecter {
 	return &MockAudioRepository_Expecter{mock: &_m.Mock}
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockAudioRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAudioRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockAudioRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockAudioRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockAudioRepository_Delete_Call {
+	return &MockAudioRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockAudioRepository_Delete_Call) Run(run func(ctx context.Context, id uint)) *MockAudioRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioRepository_Delete_Call) Return(_a0 error) *MockAudioRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAudioRepository_Delete_Call) RunAndReturn(run func(context.Context, uint) error) *MockAudioRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *MockAudioRepository) GetByID(ctx context.Context, id uint) (*entity.Audio, error) {
 	ret := _m.Called(ctx, id)
@@ -141,6 +188,350 @@ func (_c *MockAudioRepository_GetByUserIDAndPhraseID_Call) RunAndReturn(run func
 	return _c
 }
 
+// ListByPhraseID provides a mock function with given fields: ctx, phraseID
+func (_m *MockAudioRepository) ListByPhraseID(ctx context.Context, phraseID uint) ([]*entity.Audio, error) {
+	ret := _m.Called(ctx, phraseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByPhraseID")
+	}
+
+	var r0 []*entity.Audio
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Audio, error)); ok {
+		return rf(ctx, phraseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Audio); ok {
+		r0 = rf(ctx, phraseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Audio)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, phraseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioRepository_ListByPhraseID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByPhraseID'
+type MockAudioRepository_ListByPhraseID_Call struct {
+	*mock.Call
+}
+
+// ListByPhraseID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - phraseID uint
+func (_e *MockAudioRepository_Expecter) ListByPhraseID(ctx interface{}, phraseID interface{}) *MockAudioRepository_ListByPhraseID_Call {
+	return &MockAudioRepository_ListByPhraseID_Call{Call: _e.mock.On("ListByPhraseID", ctx, phraseID)}
+}
+
+func (_c *MockAudioRepository_ListByPhraseID_Call) Run(run func(ctx context.Context, phraseID uint)) *MockAudioRepository_ListByPhraseID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioRepository_ListByPhraseID_Call) Return(_a0 []*entity.Audio, _a1 error) *MockAudioRepository_ListByPhraseID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioRepository_ListByPhraseID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Audio, error)) *MockAudioRepository_ListByPhraseID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListBySessionID provides a mock function with given fields: ctx, sessionID
+func (_m *MockAudioRepository) ListBySessionID(ctx context.Context, sessionID uint) ([]*entity.Audio, error) {
+	ret := _m.Called(ctx, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBySessionID")
+	}
+
+	var r0 []*entity.Audio
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Audio, error)); ok {
+		return rf(ctx, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Audio); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Audio)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioRepository_ListBySessionID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBySessionID'
+type MockAudioRepository_ListBySessionID_Call struct {
+	*mock.Call
+}
+
+// ListBySessionID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionID uint
+func (_e *MockAudioRepository_Expecter) ListBySessionID(ctx interface{}, sessionID interface{}) *MockAudioRepository_ListBySessionID_Call {
+	return &MockAudioRepository_ListBySessionID_Call{Call: _e.mock.On("ListBySessionID", ctx, sessionID)}
+}
+
+func (_c *MockAudioRepository_ListBySessionID_Call) Run(run func(ctx context.Context, sessionID uint)) *MockAudioRepository_ListBySessionID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioRepository_ListBySessionID_Call) Return(_a0 []*entity.Audio, _a1 error) *MockAudioRepository_ListBySessionID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioRepository_ListBySessionID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Audio, error)) *MockAudioRepository_ListBySessionID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *MockAudioRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Audio, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUserID")
+	}
+
+	var r0 []*entity.Audio
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Audio, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Audio); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Audio)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioRepository_ListByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUserID'
+type MockAudioRepository_ListByUserID_Call struct {
+	*mock.Call
+}
+
+// ListByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MockAudioRepository_Expecter) ListByUserID(ctx interface{}, userID interface{}) *MockAudioRepository_ListByUserID_Call {
+	return &MockAudioRepository_ListByUserID_Call{Call: _e.mock.On("ListByUserID", ctx, userID)}
+}
+
+func (_c *MockAudioRepository_ListByUserID_Call) Run(run func(ctx context.Context, userID uint)) *MockAudioRepository_ListByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioRepository_ListByUserID_Call) Return(_a0 []*entity.Audio, _a1 error) *MockAudioRepository_ListByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioRepository_ListByUserID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Audio, error)) *MockAudioRepository_ListByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Progress provides a mock function with given fields: ctx, userID
+func (_m *MockAudioRepository) Progress(ctx context.Context, userID uint) (*entity.RecordingProgress, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Progress")
+	}
+
+	var r0 *entity.RecordingProgress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.RecordingProgress, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.RecordingProgress); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RecordingProgress)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioRepository_Progress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Progress'
+type MockAudioRepository_Progress_Call struct {
+	*mock.Call
+}
+
+// Progress is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MockAudioRepository_Expecter) Progress(ctx interface{}, userID interface{}) *MockAudioRepository_Progress_Call {
+	return &MockAudioRepository_Progress_Call{Call: _e.mock.On("Progress", ctx, userID)}
+}
+
+func (_c *MockAudioRepository_Progress_Call) Run(run func(ctx context.Context, userID uint)) *MockAudioRepository_Progress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioRepository_Progress_Call) Return(_a0 *entity.RecordingProgress, _a1 error) *MockAudioRepository_Progress_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioRepository_Progress_Call) RunAndReturn(run func(context.Context, uint) (*entity.RecordingProgress, error)) *MockAudioRepository_Progress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Replace provides a mock function with given fields: ctx, oldID, audio
+func (_m *MockAudioRepository) Replace(ctx context.Context, oldID uint, audio *entity.Audio) (*entity.Audio, error) {
+	ret := _m.Called(ctx, oldID, audio)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replace")
+	}
+
+	var r0 *entity.Audio
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *entity.Audio) (*entity.Audio, error)); ok {
+		return rf(ctx, oldID, audio)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *entity.Audio) *entity.Audio); ok {
+		r0 = rf(ctx, oldID, audio)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Audio)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *entity.Audio) error); ok {
+		r1 = rf(ctx, oldID, audio)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioRepository_Replace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Replace'
+type MockAudioRepository_Replace_Call struct {
+	*mock.Call
+}
+
+// Replace is a helper method to define mock.On call
+//   - ctx context.Context
+//   - oldID uint
+//   - audio *entity.Audio
+func (_e *MockAudioRepository_Expecter) Replace(ctx interface{}, oldID interface{}, audio interface{}) *MockAudioRepository_Replace_Call {
+	return &MockAudioRepository_Replace_Call{Call: _e.mock.On("Replace", ctx, oldID, audio)}
+}
+
+func (_c *MockAudioRepository_Replace_Call) Run(run func(ctx context.Context, oldID uint, audio *entity.Audio)) *MockAudioRepository_Replace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(*entity.Audio))
+	})
+	return _c
+}
+
+func (_c *MockAudioRepository_Replace_Call) Return(_a0 *entity.Audio, _a1 error) *MockAudioRepository_Replace_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioRepository_Replace_Call) RunAndReturn(run func(context.Context, uint, *entity.Audio) (*entity.Audio, error)) *MockAudioRepository_Replace_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetSimilarityScore provides a mock function with given fields: ctx, id, score
+func (_m *MockAudioRepository) SetSimilarityScore(ctx context.Context, id uint, score *float64) error {
+	ret := _m.Called(ctx, id, score)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSimilarityScore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *float64) error); ok {
+		r0 = rf(ctx, id, score)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAudioRepository_SetSimilarityScore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSimilarityScore'
+type MockAudioRepository_SetSimilarityScore_Call struct {
+	*mock.Call
+}
+
+// SetSimilarityScore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+//   - score *float64
+func (_e *MockAudioRepository_Expecter) SetSimilarityScore(ctx interface{}, id interface{}, score interface{}) *MockAudioRepository_SetSimilarityScore_Call {
+	return &MockAudioRepository_SetSimilarityScore_Call{Call: _e.mock.On("SetSimilarityScore", ctx, id, score)}
+}
+
+func (_c *MockAudioRepository_SetSimilarityScore_Call) Run(run func(ctx context.Context, id uint, score *float64)) *MockAudioRepository_SetSimilarityScore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(*float64))
+	})
+	return _c
+}
+
+func (_c *MockAudioRepository_SetSimilarityScore_Call) Return(_a0 error) *MockAudioRepository_SetSimilarityScore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAudioRepository_SetSimilarityScore_Call) RunAndReturn(run func(context.Context, uint, *float64) error) *MockAudioRepository_SetSimilarityScore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Store provides a mock function with given fields: ctx, audio
 func (_m *MockAudioRepository) Store(ctx context.Context, audio *entity.Audio) (*entity.Audio, error) {
 	ret := _m.Called(ctx, audio)
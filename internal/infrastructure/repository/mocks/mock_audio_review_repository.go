@@ -0,0 +1,400 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// MockAudioReviewRepository is an autogenerated mock type for the AudioReviewRepository type
+type MockAudioReviewRepository struct {
+	mock.Mock
+}
+
+type MockAudioReviewRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAudioReviewRepository) EXPECT() *MockAudioReviewRepository_Expecter {
+	return &MockAudioReviewRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetByAudioID provides a mock function with given fields: ctx, audioID
+func (_m *MockAudioReviewRepository) GetByAudioID(ctx context.Context, audioID uint) (*entity.AudioReview, error) {
+	ret := _m.Called(ctx, audioID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAudioID")
+	}
+
+	var r0 *entity.AudioReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.AudioReview, error)); ok {
+		return rf(ctx, audioID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.AudioReview); ok {
+		r0 = rf(ctx, audioID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AudioReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, audioID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioReviewRepository_GetByAudioID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByAudioID'
+type MockAudioReviewRepository_GetByAudioID_Call struct {
+	*mock.Call
+}
+
+// GetByAudioID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - audioID uint
+func (_e *MockAudioReviewRepository_Expecter) GetByAudioID(ctx interface{}, audioID interface{}) *MockAudioReviewRepository_GetByAudioID_Call {
+	return &MockAudioReviewRepository_GetByAudioID_Call{Call: _e.mock.On("GetByAudioID", ctx, audioID)}
+}
+
+func (_c *MockAudioReviewRepository_GetByAudioID_Call) Run(run func(ctx context.Context, audioID uint)) *MockAudioReviewRepository_GetByAudioID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_GetByAudioID_Call) Return(_a0 *entity.AudioReview, _a1 error) *MockAudioReviewRepository_GetByAudioID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_GetByAudioID_Call) RunAndReturn(run func(context.Context, uint) (*entity.AudioReview, error)) *MockAudioReviewRepository_GetByAudioID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBySessionID provides a mock function with given fields: ctx, sessionID
+func (_m *MockAudioReviewRepository) GetBySessionID(ctx context.Context, sessionID uint) (*entity.SessionReview, error) {
+	ret := _m.Called(ctx, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBySessionID")
+	}
+
+	var r0 *entity.SessionReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.SessionReview, error)); ok {
+		return rf(ctx, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.SessionReview); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.SessionReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioReviewRepository_GetBySessionID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBySessionID'
+type MockAudioReviewRepository_GetBySessionID_Call struct {
+	*mock.Call
+}
+
+// GetBySessionID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionID uint
+func (_e *MockAudioReviewRepository_Expecter) GetBySessionID(ctx interface{}, sessionID interface{}) *MockAudioReviewRepository_GetBySessionID_Call {
+	return &MockAudioReviewRepository_GetBySessionID_Call{Call: _e.mock.On("GetBySessionID", ctx, sessionID)}
+}
+
+func (_c *MockAudioReviewRepository_GetBySessionID_Call) Run(run func(ctx context.Context, sessionID uint)) *MockAudioReviewRepository_GetBySessionID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_GetBySessionID_Call) Return(_a0 *entity.SessionReview, _a1 error) *MockAudioReviewRepository_GetBySessionID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_GetBySessionID_Call) RunAndReturn(run func(context.Context, uint) (*entity.SessionReview, error)) *MockAudioReviewRepository_GetBySessionID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRejected provides a mock function with given fields: ctx, userID
+func (_m *MockAudioReviewRepository) ListRejected(ctx context.Context, userID uint) ([]*entity.RejectedTake, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRejected")
+	}
+
+	var r0 []*entity.RejectedTake
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.RejectedTake, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.RejectedTake); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.RejectedTake)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioReviewRepository_ListRejected_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRejected'
+type MockAudioReviewRepository_ListRejected_Call struct {
+	*mock.Call
+}
+
+// ListRejected is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MockAudioReviewRepository_Expecter) ListRejected(ctx interface{}, userID interface{}) *MockAudioReviewRepository_ListRejected_Call {
+	return &MockAudioReviewRepository_ListRejected_Call{Call: _e.mock.On("ListRejected", ctx, userID)}
+}
+
+func (_c *MockAudioReviewRepository_ListRejected_Call) Run(run func(ctx context.Context, userID uint)) *MockAudioReviewRepository_ListRejected_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_ListRejected_Call) Return(_a0 []*entity.RejectedTake, _a1 error) *MockAudioReviewRepository_ListRejected_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_ListRejected_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.RejectedTake, error)) *MockAudioReviewRepository_ListRejected_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUnreviewed provides a mock function with given fields: ctx, filter
+func (_m *MockAudioReviewRepository) ListUnreviewed(ctx context.Context, filter repository.ReviewQueueFilter) ([]*entity.Audio, int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnreviewed")
+	}
+
+	var r0 []*entity.Audio
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ReviewQueueFilter) ([]*entity.Audio, int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ReviewQueueFilter) []*entity.Audio); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Audio)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.ReviewQueueFilter) int); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.ReviewQueueFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockAudioReviewRepository_ListUnreviewed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUnreviewed'
+type MockAudioReviewRepository_ListUnreviewed_Call struct {
+	*mock.Call
+}
+
+// ListUnreviewed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.ReviewQueueFilter
+func (_e *MockAudioReviewRepository_Expecter) ListUnreviewed(ctx interface{}, filter interface{}) *MockAudioReviewRepository_ListUnreviewed_Call {
+	return &MockAudioReviewRepository_ListUnreviewed_Call{Call: _e.mock.On("ListUnreviewed", ctx, filter)}
+}
+
+func (_c *MockAudioReviewRepository_ListUnreviewed_Call) Run(run func(ctx context.Context, filter repository.ReviewQueueFilter)) *MockAudioReviewRepository_ListUnreviewed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.ReviewQueueFilter))
+	})
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_ListUnreviewed_Call) Return(_a0 []*entity.Audio, _a1 int, _a2 error) *MockAudioReviewRepository_ListUnreviewed_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_ListUnreviewed_Call) RunAndReturn(run func(context.Context, repository.ReviewQueueFilter) ([]*entity.Audio, int, error)) *MockAudioReviewRepository_ListUnreviewed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, review
+func (_m *MockAudioReviewRepository) Save(ctx context.Context, review *entity.AudioReview) (*entity.AudioReview, error) {
+	ret := _m.Called(ctx, review)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 *entity.AudioReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AudioReview) (*entity.AudioReview, error)); ok {
+		return rf(ctx, review)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AudioReview) *entity.AudioReview); ok {
+		r0 = rf(ctx, review)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AudioReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.AudioReview) error); ok {
+		r1 = rf(ctx, review)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioReviewRepository_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockAudioReviewRepository_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - review *entity.AudioReview
+func (_e *MockAudioReviewRepository_Expecter) Save(ctx interface{}, review interface{}) *MockAudioReviewRepository_Save_Call {
+	return &MockAudioReviewRepository_Save_Call{Call: _e.mock.On("Save", ctx, review)}
+}
+
+func (_c *MockAudioReviewRepository_Save_Call) Run(run func(ctx context.Context, review *entity.AudioReview)) *MockAudioReviewRepository_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.AudioReview))
+	})
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_Save_Call) Return(_a0 *entity.AudioReview, _a1 error) *MockAudioReviewRepository_Save_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_Save_Call) RunAndReturn(run func(context.Context, *entity.AudioReview) (*entity.AudioReview, error)) *MockAudioReviewRepository_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveSession provides a mock function with given fields: ctx, review
+func (_m *MockAudioReviewRepository) SaveSession(ctx context.Context, review *entity.SessionReview) ([]*entity.AudioReview, error) {
+	ret := _m.Called(ctx, review)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSession")
+	}
+
+	var r0 []*entity.AudioReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SessionReview) ([]*entity.AudioReview, error)); ok {
+		return rf(ctx, review)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SessionReview) []*entity.AudioReview); ok {
+		r0 = rf(ctx, review)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.AudioReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.SessionReview) error); ok {
+		r1 = rf(ctx, review)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAudioReviewRepository_SaveSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSession'
+type MockAudioReviewRepository_SaveSession_Call struct {
+	*mock.Call
+}
+
+// SaveSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - review *entity.SessionReview
+func (_e *MockAudioReviewRepository_Expecter) SaveSession(ctx interface{}, review interface{}) *MockAudioReviewRepository_SaveSession_Call {
+	return &MockAudioReviewRepository_SaveSession_Call{Call: _e.mock.On("SaveSession", ctx, review)}
+}
+
+func (_c *MockAudioReviewRepository_SaveSession_Call) Run(run func(ctx context.Context, review *entity.SessionReview)) *MockAudioReviewRepository_SaveSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.SessionReview))
+	})
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_SaveSession_Call) Return(_a0 []*entity.AudioReview, _a1 error) *MockAudioReviewRepository_SaveSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAudioReviewRepository_SaveSession_Call) RunAndReturn(run func(context.Context, *entity.SessionReview) ([]*entity.AudioReview, error)) *MockAudioReviewRepository_SaveSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAudioReviewRepository creates a new instance of MockAudioReviewRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAudioReviewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAudioReviewRepository {
+	mock := &MockAudioReviewRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,548 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCollectionRepository is an autogenerated mock type for the CollectionRepository type
+type MockCollectionRepository struct {
+	mock.Mock
+}
+
+type MockCollectionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCollectionRepository) EXPECT() *MockCollectionRepository_Expecter {
+	return &MockCollectionRepository_Expecter{mock: &_m.Mock}
+}
+
+// Assign provides a mock function with given fields: ctx, collectionID, userID
+func (_m *MockCollectionRepository) Assign(ctx context.Context, collectionID uint, userID uint) error {
+	ret := _m.Called(ctx, collectionID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Assign")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, collectionID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCollectionRepository_Assign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Assign'
+type MockCollectionRepository_Assign_Call struct {
+	*mock.Call
+}
+
+// Assign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - collectionID uint
+//   - userID uint
+func (_e *MockCollectionRepository_Expecter) Assign(ctx interface{}, collectionID interface{}, userID interface{}) *MockCollectionRepository_Assign_Call {
+	return &MockCollectionRepository_Assign_Call{Call: _e.mock.On("Assign", ctx, collectionID, userID)}
+}
+
+func (_c *MockCollectionRepository_Assign_Call) Run(run func(ctx context.Context, collectionID uint, userID uint)) *MockCollectionRepository_Assign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_Assign_Call) Return(_a0 error) *MockCollectionRepository_Assign_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCollectionRepository_Assign_Call) RunAndReturn(run func(context.Context, uint, uint) error) *MockCollectionRepository_Assign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, collection, texts
+func (_m *MockCollectionRepository) Create(ctx context.Context, collection *entity.Collection, texts []string) (*entity.Collection, error) {
+	ret := _m.Called(ctx, collection, texts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entity.Collection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Collection, []string) (*entity.Collection, error)); ok {
+		return rf(ctx, collection, texts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Collection, []string) *entity.Collection); ok {
+		r0 = rf(ctx, collection, texts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Collection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Collection, []string) error); ok {
+		r1 = rf(ctx, collection, texts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCollectionRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockCollectionRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - collection *entity.Collection
+//   - texts []string
+func (_e *MockCollectionRepository_Expecter) Create(ctx interface{}, collection interface{}, texts interface{}) *MockCollectionRepository_Create_Call {
+	return &MockCollectionRepository_Create_Call{Call: _e.mock.On("Create", ctx, collection, texts)}
+}
+
+func (_c *MockCollectionRepository_Create_Call) Run(run func(ctx context.Context, collection *entity.Collection, texts []string)) *MockCollectionRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Collection), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_Create_Call) Return(_a0 *entity.Collection, _a1 error) *MockCollectionRepository_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCollectionRepository_Create_Call) RunAndReturn(run func(context.Context, *entity.Collection, []string) (*entity.Collection, error)) *MockCollectionRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockCollectionRepository) GetByID(ctx context.Context, id uint) (*entity.Collection, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entity.Collection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.Collection, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.Collection); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Collection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCollectionRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockCollectionRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockCollectionRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockCollectionRepository_GetByID_Call {
+	return &MockCollectionRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockCollectionRepository_GetByID_Call) Run(run func(ctx context.Context, id uint)) *MockCollectionRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_GetByID_Call) Return(_a0 *entity.Collection, _a1 error) *MockCollectionRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCollectionRepository_GetByID_Call) RunAndReturn(run func(context.Context, uint) (*entity.Collection, error)) *MockCollectionRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPhrase provides a mock function with given fields: ctx, collectionID, position
+func (_m *MockCollectionRepository) GetPhrase(ctx context.Context, collectionID uint, position int) (*entity.CollectionPhrase, error) {
+	ret := _m.Called(ctx, collectionID, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPhrase")
+	}
+
+	var r0 *entity.CollectionPhrase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) (*entity.CollectionPhrase, error)); ok {
+		return rf(ctx, collectionID, position)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) *entity.CollectionPhrase); ok {
+		r0 = rf(ctx, collectionID, position)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CollectionPhrase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int) error); ok {
+		r1 = rf(ctx, collectionID, position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCollectionRepository_GetPhrase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPhrase'
+type MockCollectionRepository_GetPhrase_Call struct {
+	*mock.Call
+}
+
+// GetPhrase is a helper method to define mock.On call
+//   - ctx context.Context
+//   - collectionID uint
+//   - position int
+func (_e *MockCollectionRepository_Expecter) GetPhrase(ctx interface{}, collectionID interface{}, position interface{}) *MockCollectionRepository_GetPhrase_Call {
+	return &MockCollectionRepository_GetPhrase_Call{Call: _e.mock.On("GetPhrase", ctx, collectionID, position)}
+}
+
+func (_c *MockCollectionRepository_GetPhrase_Call) Run(run func(ctx context.Context, collectionID uint, position int)) *MockCollectionRepository_GetPhrase_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_GetPhrase_Call) Return(_a0 *entity.CollectionPhrase, _a1 error) *MockCollectionRepository_GetPhrase_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCollectionRepository_GetPhrase_Call) RunAndReturn(run func(context.Context, uint, int) (*entity.CollectionPhrase, error)) *MockCollectionRepository_GetPhrase_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAssigned provides a mock function with given fields: ctx, userID
+func (_m *MockCollectionRepository) ListAssigned(ctx context.Context, userID uint) ([]*entity.Collection, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAssigned")
+	}
+
+	var r0 []*entity.Collection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Collection, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Collection); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Collection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCollectionRepository_ListAssigned_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAssigned'
+type MockCollectionRepository_ListAssigned_Call struct {
+	*mock.Call
+}
+
+// ListAssigned is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MockCollectionRepository_Expecter) ListAssigned(ctx interface{}, userID interface{}) *MockCollectionRepository_ListAssigned_Call {
+	return &MockCollectionRepository_ListAssigned_Call{Call: _e.mock.On("ListAssigned", ctx, userID)}
+}
+
+func (_c *MockCollectionRepository_ListAssigned_Call) Run(run func(ctx context.Context, userID uint)) *MockCollectionRepository_ListAssigned_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_ListAssigned_Call) Return(_a0 []*entity.Collection, _a1 error) *MockCollectionRepository_ListAssigned_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCollectionRepository_ListAssigned_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Collection, error)) *MockCollectionRepository_ListAssigned_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAssignees provides a mock function with given fields: ctx, collectionID
+func (_m *MockCollectionRepository) ListAssignees(ctx context.Context, collectionID uint) ([]uint, error) {
+	ret := _m.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAssignees")
+	}
+
+	var r0 []uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]uint, error)); ok {
+		return rf(ctx, collectionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []uint); ok {
+		r0 = rf(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCollectionRepository_ListAssignees_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAssignees'
+type MockCollectionRepository_ListAssignees_Call struct {
+	*mock.Call
+}
+
+// ListAssignees is a helper method to define mock.On call
+//   - ctx context.Context
+//   - collectionID uint
+func (_e *MockCollectionRepository_Expecter) ListAssignees(ctx interface{}, collectionID interface{}) *MockCollectionRepository_ListAssignees_Call {
+	return &MockCollectionRepository_ListAssignees_Call{Call: _e.mock.On("ListAssignees", ctx, collectionID)}
+}
+
+func (_c *MockCollectionRepository_ListAssignees_Call) Run(run func(ctx context.Context, collectionID uint)) *MockCollectionRepository_ListAssignees_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_ListAssignees_Call) Return(_a0 []uint, _a1 error) *MockCollectionRepository_ListAssignees_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCollectionRepository_ListAssignees_Call) RunAndReturn(run func(context.Context, uint) ([]uint, error)) *MockCollectionRepository_ListAssignees_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *MockCollectionRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Collection, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUserID")
+	}
+
+	var r0 []*entity.Collection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Collection, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Collection); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Collection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCollectionRepository_ListByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUserID'
+type MockCollectionRepository_ListByUserID_Call struct {
+	*mock.Call
+}
+
+// ListByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MockCollectionRepository_Expecter) ListByUserID(ctx interface{}, userID interface{}) *MockCollectionRepository_ListByUserID_Call {
+	return &MockCollectionRepository_ListByUserID_Call{Call: _e.mock.On("ListByUserID", ctx, userID)}
+}
+
+func (_c *MockCollectionRepository_ListByUserID_Call) Run(run func(ctx context.Context, userID uint)) *MockCollectionRepository_ListByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_ListByUserID_Call) Return(_a0 []*entity.Collection, _a1 error) *MockCollectionRepository_ListByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCollectionRepository_ListByUserID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Collection, error)) *MockCollectionRepository_ListByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPhrases provides a mock function with given fields: ctx, collectionID
+func (_m *MockCollectionRepository) ListPhrases(ctx context.Context, collectionID uint) ([]*entity.CollectionPhrase, error) {
+	ret := _m.Called(ctx, collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPhrases")
+	}
+
+	var r0 []*entity.CollectionPhrase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.CollectionPhrase, error)); ok {
+		return rf(ctx, collectionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.CollectionPhrase); ok {
+		r0 = rf(ctx, collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.CollectionPhrase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCollectionRepository_ListPhrases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPhrases'
+type MockCollectionRepository_ListPhrases_Call struct {
+	*mock.Call
+}
+
+// ListPhrases is a helper method to define mock.On call
+//   - ctx context.Context
+//   - collectionID uint
+func (_e *MockCollectionRepository_Expecter) ListPhrases(ctx interface{}, collectionID interface{}) *MockCollectionRepository_ListPhrases_Call {
+	return &MockCollectionRepository_ListPhrases_Call{Call: _e.mock.On("ListPhrases", ctx, collectionID)}
+}
+
+func (_c *MockCollectionRepository_ListPhrases_Call) Run(run func(ctx context.Context, collectionID uint)) *MockCollectionRepository_ListPhrases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_ListPhrases_Call) Return(_a0 []*entity.CollectionPhrase, _a1 error) *MockCollectionRepository_ListPhrases_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCollectionRepository_ListPhrases_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.CollectionPhrase, error)) *MockCollectionRepository_ListPhrases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unassign provides a mock function with given fields: ctx, collectionID, userID
+func (_m *MockCollectionRepository) Unassign(ctx context.Context, collectionID uint, userID uint) error {
+	ret := _m.Called(ctx, collectionID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unassign")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, collectionID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCollectionRepository_Unassign_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unassign'
+type MockCollectionRepository_Unassign_Call struct {
+	*mock.Call
+}
+
+// Unassign is a helper method to define mock.On call
+//   - ctx context.Context
+//   - collectionID uint
+//   - userID uint
+func (_e *MockCollectionRepository_Expecter) Unassign(ctx interface{}, collectionID interface{}, userID interface{}) *MockCollectionRepository_Unassign_Call {
+	return &MockCollectionRepository_Unassign_Call{Call: _e.mock.On("Unassign", ctx, collectionID, userID)}
+}
+
+func (_c *MockCollectionRepository_Unassign_Call) Run(run func(ctx context.Context, collectionID uint, userID uint)) *MockCollectionRepository_Unassign_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCollectionRepository_Unassign_Call) Return(_a0 error) *MockCollectionRepository_Unassign_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCollectionRepository_Unassign_Call) RunAndReturn(run func(context.Context, uint, uint) error) *MockCollectionRepository_Unassign_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCollectionRepository creates a new instance of MockCollectionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCollectionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCollectionRepository {
+	mock := &MockCollectionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
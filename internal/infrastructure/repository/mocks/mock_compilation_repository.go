@@ -0,0 +1,261 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCompilationRepository is an autogenerated mock type for the CompilationRepository type
+type MockCompilationRepository struct {
+	mock.Mock
+}
+
+type MockCompilationRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCompilationRepository) EXPECT() *MockCompilationRepository_Expecter {
+	return &MockCompilationRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, compilation
+func (_m *MockCompilationRepository) Create(ctx context.Context, compilation *entity.Compilation) (*entity.Compilation, error) {
+	ret := _m.Called(ctx, compilation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entity.Compilation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Compilation) (*entity.Compilation, error)); ok {
+		return rf(ctx, compilation)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Compilation) *entity.Compilation); ok {
+		r0 = rf(ctx, compilation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Compilation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Compilation) error); ok {
+		r1 = rf(ctx, compilation)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCompilationRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockCompilationRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - compilation *entity.Compilation
+func (_e *MockCompilationRepository_Expecter) Create(ctx interface{}, compilation interface{}) *MockCompilationRepository_Create_Call {
+	return &MockCompilationRepository_Create_Call{Call: _e.mock.On("Create", ctx, compilation)}
+}
+
+func (_c *MockCompilationRepository_Create_Call) Run(run func(ctx context.Context, compilation *entity.Compilation)) *MockCompilationRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Compilation))
+	})
+	return _c
+}
+
+func (_c *MockCompilationRepository_Create_Call) Return(_a0 *entity.Compilation, _a1 error) *MockCompilationRepository_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCompilationRepository_Create_Call) RunAndReturn(run func(context.Context, *entity.Compilation) (*entity.Compilation, error)) *MockCompilationRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockCompilationRepository) GetByID(ctx context.Context, id uint) (*entity.Compilation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entity.Compilation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.Compilation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.Compilation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Compilation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCompilationRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockCompilationRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockCompilationRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockCompilationRepository_GetByID_Call {
+	return &MockCompilationRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockCompilationRepository_GetByID_Call) Run(run func(ctx context.Context, id uint)) *MockCompilationRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCompilationRepository_GetByID_Call) Return(_a0 *entity.Compilation, _a1 error) *MockCompilationRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCompilationRepository_GetByID_Call) RunAndReturn(run func(context.Context, uint) (*entity.Compilation, error)) *MockCompilationRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *MockCompilationRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Compilation, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUserID")
+	}
+
+	var r0 []*entity.Compilation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Compilation, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Compilation); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Compilation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCompilationRepository_ListByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUserID'
+type MockCompilationRepository_ListByUserID_Call struct {
+	*mock.Call
+}
+
+// ListByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MockCompilationRepository_Expecter) ListByUserID(ctx interface{}, userID interface{}) *MockCompilationRepository_ListByUserID_Call {
+	return &MockCompilationRepository_ListByUserID_Call{Call: _e.mock.On("ListByUserID", ctx, userID)}
+}
+
+func (_c *MockCompilationRepository_ListByUserID_Call) Run(run func(ctx context.Context, userID uint)) *MockCompilationRepository_ListByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockCompilationRepository_ListByUserID_Call) Return(_a0 []*entity.Compilation, _a1 error) *MockCompilationRepository_ListByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCompilationRepository_ListByUserID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Compilation, error)) *MockCompilationRepository_ListByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, compilation
+func (_m *MockCompilationRepository) Update(ctx context.Context, compilation *entity.Compilation) error {
+	ret := _m.Called(ctx, compilation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Compilation) error); ok {
+		r0 = rf(ctx, compilation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCompilationRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockCompilationRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - compilation *entity.Compilation
+func (_e *MockCompilationRepository_Expecter) Update(ctx interface{}, compilation interface{}) *MockCompilationRepository_Update_Call {
+	return &MockCompilationRepository_Update_Call{Call: _e.mock.On("Update", ctx, compilation)}
+}
+
+func (_c *MockCompilationRepository_Update_Call) Run(run func(ctx context.Context, compilation *entity.Compilation)) *MockCompilationRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Compilation))
+	})
+	return _c
+}
+
+func (_c *MockCompilationRepository_Update_Call) Return(_a0 error) *MockCompilationRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCompilationRepository_Update_Call) RunAndReturn(run func(context.Context, *entity.Compilation) error) *MockCompilationRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCompilationRepository creates a new instance of MockCompilationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCompilationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCompilationRepository {
+	mock := &MockCompilationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
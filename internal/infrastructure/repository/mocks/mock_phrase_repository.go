@@ -7,6 +7,8 @@ import (
 
 	entity "github.com/ardfard/sb-test/internal/domain/entity"
 	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/ardfard/sb-test/internal/domain/repository"
 )
 
 // MockPhraseRepository is an autogenerated mock type for the PhraseRepository type
@@ -22,6 +24,54 @@ func (_m *MockPhraseRepository) EXPECT() *MockPhraseRepository_Expecter {
 	return &MockPhraseRepository_Expecter{mock: &_m.Mock}
 }
 
+// Claim provides a mock function with given fields: ctx, phraseID, userID
+func (_m *MockPhraseRepository) Claim(ctx context.Context, phraseID uint, userID uint) error {
+	ret := _m.Called(ctx, phraseID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, phraseID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPhraseRepository_Claim_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Claim'
+type MockPhraseRepository_Claim_Call struct {
+	*mock.Call
+}
+
+// Claim is a helper method to define mock.On call
+//   - ctx context.Context
+//   - phraseID uint
+//   - userID uint
+func (_e *MockPhraseRepository_Expecter) Claim(ctx interface{}, phraseID interface{}, userID interface{}) *MockPhraseRepository_Claim_Call {
+	return &MockPhraseRepository_Claim_Call{Call: _e.mock.On("Claim", ctx, phraseID, userID)}
+}
+
+func (_c *MockPhraseRepository_Claim_Call) Run(run func(ctx context.Context, phraseID uint, userID uint)) *MockPhraseRepository_Claim_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_Claim_Call) Return(_a0 error) *MockPhraseRepository_Claim_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPhraseRepository_Claim_Call) RunAndReturn(run func(context.Context, uint, uint) error) *MockPhraseRepository_Claim_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, phrase
 func (_m *MockPhraseRepository) Create(ctx context.Context, phrase *entity.Phrase) (*entity.Phrase, error) {
 	ret := _m.Called(ctx, phrase)
@@ -81,6 +131,53 @@ func (_c *MockPhraseRepository_Create_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockPhraseRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPhraseRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockPhraseRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockPhraseRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockPhraseRepository_Delete_Call {
+	return &MockPhraseRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockPhraseRepository_Delete_Call) Run(run func(ctx context.Context, id uint)) *MockPhraseRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_Delete_Call) Return(_a0 error) *MockPhraseRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPhraseRepository_Delete_Call) RunAndReturn(run func(context.Context, uint) error) *MockPhraseRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *MockPhraseRepository) GetByID(ctx context.Context, id uint) (*entity.Phrase, error) {
 	ret := _m.Called(ctx, id)
@@ -140,6 +237,571 @@ func (_c *MockPhraseRepository_GetByID_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// Import provides a mock function with given fields: ctx, created, changed
+func (_m *MockPhraseRepository) Import(ctx context.Context, created []*entity.Phrase, changed []*entity.Phrase) ([]*entity.Phrase, error) {
+	ret := _m.Called(ctx, created, changed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 []*entity.Phrase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.Phrase, []*entity.Phrase) ([]*entity.Phrase, error)); ok {
+		return rf(ctx, created, changed)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.Phrase, []*entity.Phrase) []*entity.Phrase); ok {
+		r0 = rf(ctx, created, changed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Phrase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*entity.Phrase, []*entity.Phrase) error); ok {
+		r1 = rf(ctx, created, changed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPhraseRepository_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type MockPhraseRepository_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ctx context.Context
+//   - created []*entity.Phrase
+//   - changed []*entity.Phrase
+func (_e *MockPhraseRepository_Expecter) Import(ctx interface{}, created interface{}, changed interface{}) *MockPhraseRepository_Import_Call {
+	return &MockPhraseRepository_Import_Call{Call: _e.mock.On("Import", ctx, created, changed)}
+}
+
+func (_c *MockPhraseRepository_Import_Call) Run(run func(ctx context.Context, created []*entity.Phrase, changed []*entity.Phrase)) *MockPhraseRepository_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*entity.Phrase), args[2].([]*entity.Phrase))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_Import_Call) Return(_a0 []*entity.Phrase, _a1 error) *MockPhraseRepository_Import_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPhraseRepository_Import_Call) RunAndReturn(run func(context.Context, []*entity.Phrase, []*entity.Phrase) ([]*entity.Phrase, error)) *MockPhraseRepository_Import_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsSharedWith provides a mock function with given fields: ctx, phraseID, userID
+func (_m *MockPhraseRepository) IsSharedWith(ctx context.Context, phraseID uint, userID uint) (bool, error) {
+	ret := _m.Called(ctx, phraseID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsSharedWith")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (bool, error)); ok {
+		return rf(ctx, phraseID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) bool); ok {
+		r0 = rf(ctx, phraseID, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, phraseID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPhraseRepository_IsSharedWith_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsSharedWith'
+type MockPhraseRepository_IsSharedWith_Call struct {
+	*mock.Call
+}
+
+// IsSharedWith is a helper method to define mock.On call
+//   - ctx context.Context
+//   - phraseID uint
+//   - userID uint
+func (_e *MockPhraseRepository_Expecter) IsSharedWith(ctx interface{}, phraseID interface{}, userID interface{}) *MockPhraseRepository_IsSharedWith_Call {
+	return &MockPhraseRepository_IsSharedWith_Call{Call: _e.mock.On("IsSharedWith", ctx, phraseID, userID)}
+}
+
+func (_c *MockPhraseRepository_IsSharedWith_Call) Run(run func(ctx context.Context, phraseID uint, userID uint)) *MockPhraseRepository_IsSharedWith_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_IsSharedWith_Call) Return(_a0 bool, _a1 error) *MockPhraseRepository_IsSharedWith_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPhraseRepository_IsSharedWith_Call) RunAndReturn(run func(context.Context, uint, uint) (bool, error)) *MockPhraseRepository_IsSharedWith_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *MockPhraseRepository) List(ctx context.Context, filter repository.PhraseFilter) ([]*entity.Phrase, int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*entity.Phrase
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.PhraseFilter) ([]*entity.Phrase, int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.PhraseFilter) []*entity.Phrase); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Phrase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.PhraseFilter) int); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.PhraseFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockPhraseRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockPhraseRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.PhraseFilter
+func (_e *MockPhraseRepository_Expecter) List(ctx interface{}, filter interface{}) *MockPhraseRepository_List_Call {
+	return &MockPhraseRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *MockPhraseRepository_List_Call) Run(run func(ctx context.Context, filter repository.PhraseFilter)) *MockPhraseRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.PhraseFilter))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_List_Call) Return(_a0 []*entity.Phrase, _a1 int, _a2 error) *MockPhraseRepository_List_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockPhraseRepository_List_Call) RunAndReturn(run func(context.Context, repository.PhraseFilter) ([]*entity.Phrase, int, error)) *MockPhraseRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *MockPhraseRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Phrase, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUserID")
+	}
+
+	var r0 []*entity.Phrase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Phrase, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Phrase); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Phrase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPhraseRepository_ListByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUserID'
+type MockPhraseRepository_ListByUserID_Call struct {
+	*mock.Call
+}
+
+// ListByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MockPhraseRepository_Expecter) ListByUserID(ctx interface{}, userID interface{}) *MockPhraseRepository_ListByUserID_Call {
+	return &MockPhraseRepository_ListByUserID_Call{Call: _e.mock.On("ListByUserID", ctx, userID)}
+}
+
+func (_c *MockPhraseRepository_ListByUserID_Call) Run(run func(ctx context.Context, userID uint)) *MockPhraseRepository_ListByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_ListByUserID_Call) Return(_a0 []*entity.Phrase, _a1 error) *MockPhraseRepository_ListByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPhraseRepository_ListByUserID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Phrase, error)) *MockPhraseRepository_ListByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRevisions provides a mock function with given fields: ctx, phraseID
+func (_m *MockPhraseRepository) ListRevisions(ctx context.Context, phraseID uint) ([]*entity.PhraseRevision, error) {
+	ret := _m.Called(ctx, phraseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRevisions")
+	}
+
+	var r0 []*entity.PhraseRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.PhraseRevision, error)); ok {
+		return rf(ctx, phraseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.PhraseRevision); ok {
+		r0 = rf(ctx, phraseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.PhraseRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, phraseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPhraseRepository_ListRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRevisions'
+type MockPhraseRepository_ListRevisions_Call struct {
+	*mock.Call
+}
+
+// ListRevisions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - phraseID uint
+func (_e *MockPhraseRepository_Expecter) ListRevisions(ctx interface{}, phraseID interface{}) *MockPhraseRepository_ListRevisions_Call {
+	return &MockPhraseRepository_ListRevisions_Call{Call: _e.mock.On("ListRevisions", ctx, phraseID)}
+}
+
+func (_c *MockPhraseRepository_ListRevisions_Call) Run(run func(ctx context.Context, phraseID uint)) *MockPhraseRepository_ListRevisions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_ListRevisions_Call) Return(_a0 []*entity.PhraseRevision, _a1 error) *MockPhraseRepository_ListRevisions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPhraseRepository_ListRevisions_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.PhraseRevision, error)) *MockPhraseRepository_ListRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NextToRecord provides a mock function with given fields: ctx, userID, strategy
+func (_m *MockPhraseRepository) NextToRecord(ctx context.Context, userID uint, strategy repository.NextPhraseStrategy) (*entity.Phrase, error) {
+	ret := _m.Called(ctx, userID, strategy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextToRecord")
+	}
+
+	var r0 *entity.Phrase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, repository.NextPhraseStrategy) (*entity.Phrase, error)); ok {
+		return rf(ctx, userID, strategy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, repository.NextPhraseStrategy) *entity.Phrase); ok {
+		r0 = rf(ctx, userID, strategy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Phrase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, repository.NextPhraseStrategy) error); ok {
+		r1 = rf(ctx, userID, strategy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPhraseRepository_NextToRecord_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NextToRecord'
+type MockPhraseRepository_NextToRecord_Call struct {
+	*mock.Call
+}
+
+// NextToRecord is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - strategy repository.NextPhraseStrategy
+func (_e *MockPhraseRepository_Expecter) NextToRecord(ctx interface{}, userID interface{}, strategy interface{}) *MockPhraseRepository_NextToRecord_Call {
+	return &MockPhraseRepository_NextToRecord_Call{Call: _e.mock.On("NextToRecord", ctx, userID, strategy)}
+}
+
+func (_c *MockPhraseRepository_NextToRecord_Call) Run(run func(ctx context.Context, userID uint, strategy repository.NextPhraseStrategy)) *MockPhraseRepository_NextToRecord_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(repository.NextPhraseStrategy))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_NextToRecord_Call) Return(_a0 *entity.Phrase, _a1 error) *MockPhraseRepository_NextToRecord_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPhraseRepository_NextToRecord_Call) RunAndReturn(run func(context.Context, uint, repository.NextPhraseStrategy) (*entity.Phrase, error)) *MockPhraseRepository_NextToRecord_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revise provides a mock function with given fields: ctx, id, text
+func (_m *MockPhraseRepository) Revise(ctx context.Context, id uint, text string) (*entity.Phrase, error) {
+	ret := _m.Called(ctx, id, text)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revise")
+	}
+
+	var r0 *entity.Phrase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) (*entity.Phrase, error)); ok {
+		return rf(ctx, id, text)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) *entity.Phrase); ok {
+		r0 = rf(ctx, id, text)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Phrase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, id, text)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPhraseRepository_Revise_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revise'
+type MockPhraseRepository_Revise_Call struct {
+	*mock.Call
+}
+
+// Revise is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+//   - text string
+func (_e *MockPhraseRepository_Expecter) Revise(ctx interface{}, id interface{}, text interface{}) *MockPhraseRepository_Revise_Call {
+	return &MockPhraseRepository_Revise_Call{Call: _e.mock.On("Revise", ctx, id, text)}
+}
+
+func (_c *MockPhraseRepository_Revise_Call) Run(run func(ctx context.Context, id uint, text string)) *MockPhraseRepository_Revise_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_Revise_Call) Return(_a0 *entity.Phrase, _a1 error) *MockPhraseRepository_Revise_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPhraseRepository_Revise_Call) RunAndReturn(run func(context.Context, uint, string) (*entity.Phrase, error)) *MockPhraseRepository_Revise_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Share provides a mock function with given fields: ctx, phraseID, userID
+func (_m *MockPhraseRepository) Share(ctx context.Context, phraseID uint, userID uint) error {
+	ret := _m.Called(ctx, phraseID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Share")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, phraseID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPhraseRepository_Share_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Share'
+type MockPhraseRepository_Share_Call struct {
+	*mock.Call
+}
+
+// Share is a helper method to define mock.On call
+//   - ctx context.Context
+//   - phraseID uint
+//   - userID uint
+func (_e *MockPhraseRepository_Expecter) Share(ctx interface{}, phraseID interface{}, userID interface{}) *MockPhraseRepository_Share_Call {
+	return &MockPhraseRepository_Share_Call{Call: _e.mock.On("Share", ctx, phraseID, userID)}
+}
+
+func (_c *MockPhraseRepository_Share_Call) Run(run func(ctx context.Context, phraseID uint, userID uint)) *MockPhraseRepository_Share_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_Share_Call) Return(_a0 error) *MockPhraseRepository_Share_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPhraseRepository_Share_Call) RunAndReturn(run func(context.Context, uint, uint) error) *MockPhraseRepository_Share_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unshare provides a mock function with given fields: ctx, phraseID, userID
+func (_m *MockPhraseRepository) Unshare(ctx context.Context, phraseID uint, userID uint) error {
+	ret := _m.Called(ctx, phraseID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unshare")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, phraseID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPhraseRepository_Unshare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unshare'
+type MockPhraseRepository_Unshare_Call struct {
+	*mock.Call
+}
+
+// Unshare is a helper method to define mock.On call
+//   - ctx context.Context
+//   - phraseID uint
+//   - userID uint
+func (_e *MockPhraseRepository_Expecter) Unshare(ctx interface{}, phraseID interface{}, userID interface{}) *MockPhraseRepository_Unshare_Call {
+	return &MockPhraseRepository_Unshare_Call{Call: _e.mock.On("Unshare", ctx, phraseID, userID)}
+}
+
+func (_c *MockPhraseRepository_Unshare_Call) Run(run func(ctx context.Context, phraseID uint, userID uint)) *MockPhraseRepository_Unshare_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(uint))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_Unshare_Call) Return(_a0 error) *MockPhraseRepository_Unshare_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPhraseRepository_Unshare_Call) RunAndReturn(run func(context.Context, uint, uint) error) *MockPhraseRepository_Unshare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, phrase
+func (_m *MockPhraseRepository) Update(ctx context.Context, phrase *entity.Phrase) error {
+	ret := _m.Called(ctx, phrase)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Phrase) error); ok {
+		r0 = rf(ctx, phrase)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPhraseRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockPhraseRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - phrase *entity.Phrase
+func (_e *MockPhraseRepository_Expecter) Update(ctx interface{}, phrase interface{}) *MockPhraseRepository_Update_Call {
+	return &MockPhraseRepository_Update_Call{Call: _e.mock.On("Update", ctx, phrase)}
+}
+
+func (_c *MockPhraseRepository_Update_Call) Run(run func(ctx context.Context, phrase *entity.Phrase)) *MockPhraseRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Phrase))
+	})
+	return _c
+}
+
+func (_c *MockPhraseRepository_Update_Call) Return(_a0 error) *MockPhraseRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPhraseRepository_Update_Call) RunAndReturn(run func(context.Context, *entity.Phrase) error) *MockPhraseRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockPhraseRepository creates a new instance of MockPhraseRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockPhraseRepository(t interface {
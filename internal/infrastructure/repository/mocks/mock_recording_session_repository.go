@@ -0,0 +1,285 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/ardfard/sb-test/internal/domain/repository"
+
+	time "time"
+)
+
+// MockRecordingSessionRepository is an autogenerated mock type for the RecordingSessionRepository type
+type MockRecordingSessionRepository struct {
+	mock.Mock
+}
+
+type MockRecordingSessionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRecordingSessionRepository) EXPECT() *MockRecordingSessionRepository_Expecter {
+	return &MockRecordingSessionRepository_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function with given fields: ctx, id, closedAt
+func (_m *MockRecordingSessionRepository) Close(ctx context.Context, id uint, closedAt time.Time) (*entity.RecordingSession, error) {
+	ret := _m.Called(ctx, id, closedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 *entity.RecordingSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) (*entity.RecordingSession, error)); ok {
+		return rf(ctx, id, closedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) *entity.RecordingSession); ok {
+		r0 = rf(ctx, id, closedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RecordingSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, time.Time) error); ok {
+		r1 = rf(ctx, id, closedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRecordingSessionRepository_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type MockRecordingSessionRepository_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+//   - closedAt time.Time
+func (_e *MockRecordingSessionRepository_Expecter) Close(ctx interface{}, id interface{}, closedAt interface{}) *MockRecordingSessionRepository_Close_Call {
+	return &MockRecordingSessionRepository_Close_Call{Call: _e.mock.On("Close", ctx, id, closedAt)}
+}
+
+func (_c *MockRecordingSessionRepository_Close_Call) Run(run func(ctx context.Context, id uint, closedAt time.Time)) *MockRecordingSessionRepository_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockRecordingSessionRepository_Close_Call) Return(_a0 *entity.RecordingSession, _a1 error) *MockRecordingSessionRepository_Close_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRecordingSessionRepository_Close_Call) RunAndReturn(run func(context.Context, uint, time.Time) (*entity.RecordingSession, error)) *MockRecordingSessionRepository_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, session
+func (_m *MockRecordingSessionRepository) Create(ctx context.Context, session *entity.RecordingSession) (*entity.RecordingSession, error) {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entity.RecordingSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RecordingSession) (*entity.RecordingSession, error)); ok {
+		return rf(ctx, session)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RecordingSession) *entity.RecordingSession); ok {
+		r0 = rf(ctx, session)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RecordingSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.RecordingSession) error); ok {
+		r1 = rf(ctx, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRecordingSessionRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockRecordingSessionRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session *entity.RecordingSession
+func (_e *MockRecordingSessionRepository_Expecter) Create(ctx interface{}, session interface{}) *MockRecordingSessionRepository_Create_Call {
+	return &MockRecordingSessionRepository_Create_Call{Call: _e.mock.On("Create", ctx, session)}
+}
+
+func (_c *MockRecordingSessionRepository_Create_Call) Run(run func(ctx context.Context, session *entity.RecordingSession)) *MockRecordingSessionRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.RecordingSession))
+	})
+	return _c
+}
+
+func (_c *MockRecordingSessionRepository_Create_Call) Return(_a0 *entity.RecordingSession, _a1 error) *MockRecordingSessionRepository_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRecordingSessionRepository_Create_Call) RunAndReturn(run func(context.Context, *entity.RecordingSession) (*entity.RecordingSession, error)) *MockRecordingSessionRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockRecordingSessionRepository) GetByID(ctx context.Context, id uint) (*entity.RecordingSession, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entity.RecordingSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.RecordingSession, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.RecordingSession); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RecordingSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRecordingSessionRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockRecordingSessionRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockRecordingSessionRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockRecordingSessionRepository_GetByID_Call {
+	return &MockRecordingSessionRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockRecordingSessionRepository_GetByID_Call) Run(run func(ctx context.Context, id uint)) *MockRecordingSessionRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockRecordingSessionRepository_GetByID_Call) Return(_a0 *entity.RecordingSession, _a1 error) *MockRecordingSessionRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRecordingSessionRepository_GetByID_Call) RunAndReturn(run func(context.Context, uint) (*entity.RecordingSession, error)) *MockRecordingSessionRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *MockRecordingSessionRepository) List(ctx context.Context, filter repository.RecordingSessionFilter) ([]*entity.RecordingSession, int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*entity.RecordingSession
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.RecordingSessionFilter) ([]*entity.RecordingSession, int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.RecordingSessionFilter) []*entity.RecordingSession); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.RecordingSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.RecordingSessionFilter) int); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.RecordingSessionFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockRecordingSessionRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockRecordingSessionRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.RecordingSessionFilter
+func (_e *MockRecordingSessionRepository_Expecter) List(ctx interface{}, filter interface{}) *MockRecordingSessionRepository_List_Call {
+	return &MockRecordingSessionRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *MockRecordingSessionRepository_List_Call) Run(run func(ctx context.Context, filter repository.RecordingSessionFilter)) *MockRecordingSessionRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.RecordingSessionFilter))
+	})
+	return _c
+}
+
+func (_c *MockRecordingSessionRepository_List_Call) Return(_a0 []*entity.RecordingSession, _a1 int, _a2 error) *MockRecordingSessionRepository_List_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockRecordingSessionRepository_List_Call) RunAndReturn(run func(context.Context, repository.RecordingSessionFilter) ([]*entity.RecordingSession, int, error)) *MockRecordingSessionRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRecordingSessionRepository creates a new instance of MockRecordingSessionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRecordingSessionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRecordingSessionRepository {
+	mock := &MockRecordingSessionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,153 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockShareLinkRepository is an autogenerated mock type for the ShareLinkRepository type
+type MockShareLinkRepository struct {
+	mock.Mock
+}
+
+type MockShareLinkRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockShareLinkRepository) EXPECT() *MockShareLinkRepository_Expecter {
+	return &MockShareLinkRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, link
+func (_m *MockShareLinkRepository) Create(ctx context.Context, link *entity.ShareLink) (*entity.ShareLink, error) {
+	ret := _m.Called(ctx, link)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entity.ShareLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ShareLink) (*entity.ShareLink, error)); ok {
+		return rf(ctx, link)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ShareLink) *entity.ShareLink); ok {
+		r0 = rf(ctx, link)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ShareLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.ShareLink) error); ok {
+		r1 = rf(ctx, link)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockShareLinkRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockShareLinkRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - link *entity.ShareLink
+func (_e *MockShareLinkRepository_Expecter) Create(ctx interface{}, link interface{}) *MockShareLinkRepository_Create_Call {
+	return &MockShareLinkRepository_Create_Call{Call: _e.mock.On("Create", ctx, link)}
+}
+
+func (_c *MockShareLinkRepository_Create_Call) Run(run func(ctx context.Context, link *entity.ShareLink)) *MockShareLinkRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.ShareLink))
+	})
+	return _c
+}
+
+func (_c *MockShareLinkRepository_Create_Call) Return(_a0 *entity.ShareLink, _a1 error) *MockShareLinkRepository_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockShareLinkRepository_Create_Call) RunAndReturn(run func(context.Context, *entity.ShareLink) (*entity.ShareLink, error)) *MockShareLinkRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkUsed provides a mock function with given fields: ctx, nonce
+func (_m *MockShareLinkRepository) MarkUsed(ctx context.Context, nonce string) (bool, error) {
+	ret := _m.Called(ctx, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, nonce)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockShareLinkRepository_MarkUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkUsed'
+type MockShareLinkRepository_MarkUsed_Call struct {
+	*mock.Call
+}
+
+// MarkUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nonce string
+func (_e *MockShareLinkRepository_Expecter) MarkUsed(ctx interface{}, nonce interface{}) *MockShareLinkRepository_MarkUsed_Call {
+	return &MockShareLinkRepository_MarkUsed_Call{Call: _e.mock.On("MarkUsed", ctx, nonce)}
+}
+
+func (_c *MockShareLinkRepository_MarkUsed_Call) Run(run func(ctx context.Context, nonce string)) *MockShareLinkRepository_MarkUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockShareLinkRepository_MarkUsed_Call) Return(_a0 bool, _a1 error) *MockShareLinkRepository_MarkUsed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockShareLinkRepository_MarkUsed_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockShareLinkRepository_MarkUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockShareLinkRepository creates a new instance of MockShareLinkRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockShareLinkRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockShareLinkRepository {
+	mock := &MockShareLinkRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
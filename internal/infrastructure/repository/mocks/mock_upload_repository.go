@@ -0,0 +1,428 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockUploadRepository is an autogenerated mock type for the UploadRepository type
+type MockUploadRepository struct {
+	mock.Mock
+}
+
+type MockUploadRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUploadRepository) EXPECT() *MockUploadRepository_Expecter {
+	return &MockUploadRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, upload
+func (_m *MockUploadRepository) Create(ctx context.Context, upload *entity.Upload) (*entity.Upload, error) {
+	ret := _m.Called(ctx, upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entity.Upload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Upload) (*entity.Upload, error)); ok {
+		return rf(ctx, upload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Upload) *entity.Upload); ok {
+		r0 = rf(ctx, upload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Upload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Upload) error); ok {
+		r1 = rf(ctx, upload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUploadRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockUploadRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - upload *entity.Upload
+func (_e *MockUploadRepository_Expecter) Create(ctx interface{}, upload interface{}) *MockUploadRepository_Create_Call {
+	return &MockUploadRepository_Create_Call{Call: _e.mock.On("Create", ctx, upload)}
+}
+
+func (_c *MockUploadRepository_Create_Call) Run(run func(ctx context.Context, upload *entity.Upload)) *MockUploadRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Upload))
+	})
+	return _c
+}
+
+func (_c *MockUploadRepository_Create_Call) Return(_a0 *entity.Upload, _a1 error) *MockUploadRepository_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUploadRepository_Create_Call) RunAndReturn(run func(context.Context, *entity.Upload) (*entity.Upload, error)) *MockUploadRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockUploadRepository) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUploadRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockUploadRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockUploadRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockUploadRepository_Delete_Call {
+	return &MockUploadRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockUploadRepository_Delete_Call) Run(run func(ctx context.Context, id string)) *MockUploadRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUploadRepository_Delete_Call) Return(_a0 error) *MockUploadRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUploadRepository_Delete_Call) RunAndReturn(run func(context.Context, string) error) *MockUploadRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockUploadRepository) GetByID(ctx context.Context, id string) (*entity.Upload, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entity.Upload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Upload, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Upload); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Upload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUploadRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockUploadRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockUploadRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockUploadRepository_GetByID_Call {
+	return &MockUploadRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockUploadRepository_GetByID_Call) Run(run func(ctx context.Context, id string)) *MockUploadRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUploadRepository_GetByID_Call) Return(_a0 *entity.Upload, _a1 error) *MockUploadRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUploadRepository_GetByID_Call) RunAndReturn(run func(context.Context, string) (*entity.Upload, error)) *MockUploadRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByPhraseID provides a mock function with given fields: ctx, phraseID
+func (_m *MockUploadRepository) ListByPhraseID(ctx context.Context, phraseID uint) ([]*entity.Upload, error) {
+	ret := _m.Called(ctx, phraseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByPhraseID")
+	}
+
+	var r0 []*entity.Upload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Upload, error)); ok {
+		return rf(ctx, phraseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Upload); ok {
+		r0 = rf(ctx, phraseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Upload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, phraseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUploadRepository_ListByPhraseID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByPhraseID'
+type MockUploadRepository_ListByPhraseID_Call struct {
+	*mock.Call
+}
+
+// ListByPhraseID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - phraseID uint
+func (_e *MockUploadRepository_Expecter) ListByPhraseID(ctx interface{}, phraseID interface{}) *MockUploadRepository_ListByPhraseID_Call {
+	return &MockUploadRepository_ListByPhraseID_Call{Call: _e.mock.On("ListByPhraseID", ctx, phraseID)}
+}
+
+func (_c *MockUploadRepository_ListByPhraseID_Call) Run(run func(ctx context.Context, phraseID uint)) *MockUploadRepository_ListByPhraseID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockUploadRepository_ListByPhraseID_Call) Return(_a0 []*entity.Upload, _a1 error) *MockUploadRepository_ListByPhraseID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUploadRepository_ListByPhraseID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Upload, error)) *MockUploadRepository_ListByPhraseID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *MockUploadRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Upload, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUserID")
+	}
+
+	var r0 []*entity.Upload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entity.Upload, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entity.Upload); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Upload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUploadRepository_ListByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUserID'
+type MockUploadRepository_ListByUserID_Call struct {
+	*mock.Call
+}
+
+// ListByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MockUploadRepository_Expecter) ListByUserID(ctx interface{}, userID interface{}) *MockUploadRepository_ListByUserID_Call {
+	return &MockUploadRepository_ListByUserID_Call{Call: _e.mock.On("ListByUserID", ctx, userID)}
+}
+
+func (_c *MockUploadRepository_ListByUserID_Call) Run(run func(ctx context.Context, userID uint)) *MockUploadRepository_ListByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockUploadRepository_ListByUserID_Call) Return(_a0 []*entity.Upload, _a1 error) *MockUploadRepository_ListByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUploadRepository_ListByUserID_Call) RunAndReturn(run func(context.Context, uint) ([]*entity.Upload, error)) *MockUploadRepository_ListByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListStale provides a mock function with given fields: ctx, before
+func (_m *MockUploadRepository) ListStale(ctx context.Context, before time.Time) ([]*entity.Upload, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStale")
+	}
+
+	var r0 []*entity.Upload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]*entity.Upload, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []*entity.Upload); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Upload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUploadRepository_ListStale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListStale'
+type MockUploadRepository_ListStale_Call struct {
+	*mock.Call
+}
+
+// ListStale is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockUploadRepository_Expecter) ListStale(ctx interface{}, before interface{}) *MockUploadRepository_ListStale_Call {
+	return &MockUploadRepository_ListStale_Call{Call: _e.mock.On("ListStale", ctx, before)}
+}
+
+func (_c *MockUploadRepository_ListStale_Call) Run(run func(ctx context.Context, before time.Time)) *MockUploadRepository_ListStale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUploadRepository_ListStale_Call) Return(_a0 []*entity.Upload, _a1 error) *MockUploadRepository_ListStale_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUploadRepository_ListStale_Call) RunAndReturn(run func(context.Context, time.Time) ([]*entity.Upload, error)) *MockUploadRepository_ListStale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, upload
+func (_m *MockUploadRepository) Update(ctx context.Context, upload *entity.Upload) error {
+	ret := _m.Called(ctx, upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Upload) error); ok {
+		r0 = rf(ctx, upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUploadRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockUploadRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - upload *entity.Upload
+func (_e *MockUploadRepository_Expecter) Update(ctx interface{}, upload interface{}) *MockUploadRepository_Update_Call {
+	return &MockUploadRepository_Update_Call{Call: _e.mock.On("Update", ctx, upload)}
+}
+
+func (_c *MockUploadRepository_Update_Call) Run(run func(ctx context.Context, upload *entity.Upload)) *MockUploadRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Upload))
+	})
+	return _c
+}
+
+func (_c *MockUploadRepository_Update_Call) Return(_a0 error) *MockUploadRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUploadRepository_Update_Call) RunAndReturn(run func(context.Context, *entity.Upload) error) *MockUploadRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUploadRepository creates a new instance of MockUploadRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUploadRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUploadRepository {
+	mock := &MockUploadRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,202 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/ardfard/sb-test/internal/domain/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockUserDeletionRepository is an autogenerated mock type for the UserDeletionRepository type
+type MockUserDeletionRepository struct {
+	mock.Mock
+}
+
+type MockUserDeletionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserDeletionRepository) EXPECT() *MockUserDeletionRepository_Expecter {
+	return &MockUserDeletionRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, deletion
+func (_m *MockUserDeletionRepository) Create(ctx context.Context, deletion *entity.UserDeletion) (*entity.UserDeletion, error) {
+	ret := _m.Called(ctx, deletion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entity.UserDeletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UserDeletion) (*entity.UserDeletion, error)); ok {
+		return rf(ctx, deletion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UserDeletion) *entity.UserDeletion); ok {
+		r0 = rf(ctx, deletion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UserDeletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.UserDeletion) error); ok {
+		r1 = rf(ctx, deletion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserDeletionRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockUserDeletionRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deletion *entity.UserDeletion
+func (_e *MockUserDeletionRepository_Expecter) Create(ctx interface{}, deletion interface{}) *MockUserDeletionRepository_Create_Call {
+	return &MockUserDeletionRepository_Create_Call{Call: _e.mock.On("Create", ctx, deletion)}
+}
+
+func (_c *MockUserDeletionRepository_Create_Call) Run(run func(ctx context.Context, deletion *entity.UserDeletion)) *MockUserDeletionRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.UserDeletion))
+	})
+	return _c
+}
+
+func (_c *MockUserDeletionRepository_Create_Call) Return(_a0 *entity.UserDeletion, _a1 error) *MockUserDeletionRepository_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserDeletionRepository_Create_Call) RunAndReturn(run func(context.Context, *entity.UserDeletion) (*entity.UserDeletion, error)) *MockUserDeletionRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockUserDeletionRepository) GetByID(ctx context.Context, id uint) (*entity.UserDeletion, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entity.UserDeletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entity.UserDeletion, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entity.UserDeletion); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UserDeletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserDeletionRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockUserDeletionRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockUserDeletionRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockUserDeletionRepository_GetByID_Call {
+	return &MockUserDeletionRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockUserDeletionRepository_GetByID_Call) Run(run func(ctx context.Context, id uint)) *MockUserDeletionRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockUserDeletionRepository_GetByID_Call) Return(_a0 *entity.UserDeletion, _a1 error) *MockUserDeletionRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserDeletionRepository_GetByID_Call) RunAndReturn(run func(context.Context, uint) (*entity.UserDeletion, error)) *MockUserDeletionRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, deletion
+func (_m *MockUserDeletionRepository) Update(ctx context.Context, deletion *entity.UserDeletion) error {
+	ret := _m.Called(ctx, deletion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UserDeletion) error); ok {
+		r0 = rf(ctx, deletion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserDeletionRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockUserDeletionRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deletion *entity.UserDeletion
+func (_e *MockUserDeletionRepository_Expecter) Update(ctx interface{}, deletion interface{}) *MockUserDeletionRepository_Update_Call {
+	return &MockUserDeletionRepository_Update_Call{Call: _e.mock.On("Update", ctx, deletion)}
+}
+
+func (_c *MockUserDeletionRepository_Update_Call) Run(run func(ctx context.Context, deletion *entity.UserDeletion)) *MockUserDeletionRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.UserDeletion))
+	})
+	return _c
+}
+
+func (_c *MockUserDeletionRepository_Update_Call) Return(_a0 error) *MockUserDeletionRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserDeletionRepository_Update_Call) RunAndReturn(run func(context.Context, *entity.UserDeletion) error) *MockUserDeletionRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserDeletionRepository creates a new instance of MockUserDeletionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserDeletionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserDeletionRepository {
+	mock := &MockUserDeletionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
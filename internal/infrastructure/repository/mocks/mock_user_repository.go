@@ -7,6 +7,8 @@ import (
 
 	entity "github.com/ardfard/sb-test/internal/domain/entity"
 	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/ardfard/sb-test/internal/domain/repository"
 )
 
 // MockUserRepository is an autogenerated mock type for the UserRepository type
@@ -22,6 +24,65 @@ func (_m *MockUserRepository) EXPECT() *MockUserRepository_Expecter {
 	return &MockUserRepository_Expecter{mock: &_m.Mock}
 }
 
+// AttributeStats provides a mock function with given fields: ctx, attribute
+func (_m *MockUserRepository) AttributeStats(ctx context.Context, attribute string) ([]*entity.SpeakerAttributeStats, error) {
+	ret := _m.Called(ctx, attribute)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttributeStats")
+	}
+
+	var r0 []*entity.SpeakerAttributeStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.SpeakerAttributeStats, error)); ok {
+		return rf(ctx, attribute)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.SpeakerAttributeStats); ok {
+		r0 = rf(ctx, attribute)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.SpeakerAttributeStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, attribute)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_AttributeStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttributeStats'
+type MockUserRepository_AttributeStats_Call struct {
+	*mock.Call
+}
+
+// AttributeStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attribute string
+func (_e *MockUserRepository_Expecter) AttributeStats(ctx interface{}, attribute interface{}) *MockUserRepository_AttributeStats_Call {
+	return &MockUserRepository_AttributeStats_Call{Call: _e.mock.On("AttributeStats", ctx, attribute)}
+}
+
+func (_c *MockUserRepository_AttributeStats_Call) Run(run func(ctx context.Context, attribute string)) *MockUserRepository_AttributeStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_AttributeStats_Call) Return(_a0 []*entity.SpeakerAttributeStats, _a1 error) *MockUserRepository_AttributeStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_AttributeStats_Call) RunAndReturn(run func(context.Context, string) ([]*entity.SpeakerAttributeStats, error)) *MockUserRepository_AttributeStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, user
 func (_m *MockUserRepository) Create(ctx context.Context, user *entity.User) (*entity.User, error) {
 	ret := _m.Called(ctx, user)
@@ -81,6 +142,53 @@ func (_c *MockUserRepository_Create_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockUserRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockUserRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockUserRepository_Delete_Call {
+	return &MockUserRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockUserRepository_Delete_Call) Run(run func(ctx context.Context, id uint)) *MockUserRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_Delete_Call) Return(_a0 error) *MockUserRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_Delete_Call) RunAndReturn(run func(context.Context, uint) error) *MockUserRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *MockUserRepository) GetByID(ctx context.Context, id uint) (*entity.User, error) {
 	ret := _m.Called(ctx, id)
@@ -140,6 +248,166 @@ func (_c *MockUserRepository_GetByID_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// List provides a mock function with given fields: ctx, filter
+func (_m *MockUserRepository) List(ctx context.Context, filter repository.UserFilter) ([]*entity.User, int, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*entity.User
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UserFilter) ([]*entity.User, int, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UserFilter) []*entity.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.UserFilter) int); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.UserFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockUserRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockUserRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.UserFilter
+func (_e *MockUserRepository_Expecter) List(ctx interface{}, filter interface{}) *MockUserRepository_List_Call {
+	return &MockUserRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *MockUserRepository_List_Call) Run(run func(ctx context.Context, filter repository.UserFilter)) *MockUserRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.UserFilter))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_List_Call) Return(_a0 []*entity.User, _a1 int, _a2 error) *MockUserRepository_List_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockUserRepository_List_Call) RunAndReturn(run func(context.Context, repository.UserFilter) ([]*entity.User, int, error)) *MockUserRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkDeleting provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) MarkDeleting(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkDeleting")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_MarkDeleting_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkDeleting'
+type MockUserRepository_MarkDeleting_Call struct {
+	*mock.Call
+}
+
+// MarkDeleting is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockUserRepository_Expecter) MarkDeleting(ctx interface{}, id interface{}) *MockUserRepository_MarkDeleting_Call {
+	return &MockUserRepository_MarkDeleting_Call{Call: _e.mock.On("MarkDeleting", ctx, id)}
+}
+
+func (_c *MockUserRepository_MarkDeleting_Call) Run(run func(ctx context.Context, id uint)) *MockUserRepository_MarkDeleting_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_MarkDeleting_Call) Return(_a0 error) *MockUserRepository_MarkDeleting_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_MarkDeleting_Call) RunAndReturn(run func(context.Context, uint) error) *MockUserRepository_MarkDeleting_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, user
+func (_m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockUserRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *entity.User
+func (_e *MockUserRepository_Expecter) Update(ctx interface{}, user interface{}) *MockUserRepository_Update_Call {
+	return &MockUserRepository_Update_Call{Call: _e.mock.On("Update", ctx, user)}
+}
+
+func (_c *MockUserRepository_Update_Call) Run(run func(ctx context.Context, user *entity.User)) *MockUserRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.User))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_Update_Call) Return(_a0 error) *MockUserRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_Update_Call) RunAndReturn(run func(context.Context, *entity.User) error) *MockUserRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockUserRepository creates a new instance of MockUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUserRepository(t interface {
@@ -0,0 +1,129 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const annotationColumns = `id, audio_id, tier, label, start_time, end_time, attributes, created_at, updated_at`
+
+// annotationRow stores the attributes of an annotation as a JSON object.
+type annotationRow struct {
+	entity.Annotation
+	Attributes string `db:"attributes"`
+}
+
+func (row *annotationRow) toEntity() (*entity.Annotation, error) {
+	annotation := row.Annotation
+	if err := json.Unmarshal([]byte(row.Attributes), &annotation.Attributes); err != nil {
+		return nil, fmt.Errorf("failed to decode attributes of annotation %d: %w", row.ID, err)
+	}
+	return &annotation, nil
+}
+
+func encodeAttributes(attributes map[string]interface{}) (string, error) {
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode attributes: %w", err)
+	}
+	return string(encoded), nil
+}
+
+type AnnotationRepository struct {
+	db *sqlx.DB
+}
+
+func NewAnnotationRepository(db *sqlx.DB) (*AnnotationRepository, error) {
+	return &AnnotationRepository{db: db}, nil
+}
+
+func (r *AnnotationRepository) Create(ctx context.Context, annotation *entity.Annotation) (*entity.Annotation, error) {
+	attributes, err := encodeAttributes(annotation.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO annotations (audio_id, tier, label, start_time, end_time, attributes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + annotationColumns
+	var row annotationRow
+	err = r.db.GetContext(ctx, &row, query, annotation.AudioID, annotation.Tier, annotation.Label, annotation.Start, annotation.End,
+		attributes, annotation.CreatedAt, annotation.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create annotation: %w", err)
+	}
+	return row.toEntity()
+}
+
+func (r *AnnotationRepository) GetByID(ctx context.Context, id uint) (*entity.Annotation, error) {
+	query := `SELECT ` + annotationColumns + ` FROM annotations WHERE id = ?`
+	var row annotationRow
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get annotation %d: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get annotation: %w", err)
+	}
+	return row.toEntity()
+}
+
+func (r *AnnotationRepository) ListByAudioID(ctx context.Context, audioID uint) ([]*entity.Annotation, error) {
+	query := `SELECT ` + annotationColumns + ` FROM annotations WHERE audio_id = ? ORDER BY tier, start_time, id`
+	var rows []annotationRow
+	if err := r.db.SelectContext(ctx, &rows, query, audioID); err != nil {
+		return nil, fmt.Errorf("failed to list annotations: %w", err)
+	}
+	annotations := make([]*entity.Annotation, 0, len(rows))
+	for i := range rows {
+		annotation, err := rows[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations, nil
+}
+
+func (r *AnnotationRepository) Update(ctx context.Context, annotation *entity.Annotation) error {
+	attributes, err := encodeAttributes(annotation.Attributes)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE annotations SET tier = ?, label = ?, start_time = ?, end_time = ?, attributes = ?, updated_at = ? WHERE id = ?`,
+		annotation.Tier, annotation.Label, annotation.Start, annotation.End, attributes, annotation.UpdatedAt, annotation.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update annotation: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update annotation %d: %w", annotation.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *AnnotationRepository) Delete(ctx context.Context, id uint) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM annotations WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete annotation: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to delete annotation %d: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
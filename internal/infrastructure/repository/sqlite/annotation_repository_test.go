@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAnnotationRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+	create := func(audioID uint, tier string, start, end float64) *entity.Annotation {
+		annotation, err := repo.Create(ctx, &entity.Annotation{AudioID: audioID, Tier: tier, Label: "label", Start: start, End: end,
+			CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		return annotation
+	}
+
+	word, err := repo.Create(ctx, &entity.Annotation{
+		AudioID:    1,
+		Tier:       "words",
+		Label:      "hello",
+		Start:      0.5,
+		End:        0.9,
+		Attributes: map[string]interface{}{"confidence": 0.8, "speaker": "a"},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, word.ID)
+	assert.Equal(t, map[string]interface{}{"confidence": 0.8, "speaker": "a"}, word.Attributes)
+
+	stored, err := repo.GetByID(ctx, word.ID)
+	require.NoError(t, err)
+	assert.Equal(t, word, stored)
+
+	create(1, "words", 0.1, 0.4)
+	noise := create(1, "noise", 1, 2)
+	create(2, "words", 0, 1)
+
+	t.Run("list", func(t *testing.T) {
+		annotations, err := repo.ListByAudioID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, annotations, 3)
+		assert.Equal(t, noise.ID, annotations[0].ID)
+		assert.Equal(t, 0.1, annotations[1].Start)
+		assert.Equal(t, word.ID, annotations[2].ID)
+		assert.Equal(t, map[string]interface{}{}, annotations[0].Attributes)
+
+		annotations, err = repo.ListByAudioID(ctx, 3)
+		require.NoError(t, err)
+		assert.Empty(t, annotations)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		word.Label = "hullo"
+		word.End = 1.2
+		word.Attributes = nil
+		require.NoError(t, repo.Update(ctx, word))
+
+		stored, err := repo.GetByID(ctx, word.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hullo", stored.Label)
+		assert.Equal(t, 1.2, stored.End)
+		assert.Empty(t, stored.Attributes)
+
+		assert.ErrorIs(t, repo.Update(ctx, &entity.Annotation{ID: 999}), repository.ErrNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, noise.ID))
+		_, err := repo.GetByID(ctx, noise.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.ErrorIs(t, repo.Delete(ctx, noise.ID), repository.ErrNotFound)
+	})
+}
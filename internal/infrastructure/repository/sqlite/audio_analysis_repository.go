@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const audioAnalysisColumns = `audio_id, peak_db, rms_db, clipped_ratio, noise_floor_db, snr_db, speech_ratio, problems, created_at, updated_at`
+
+// audioAnalysisRow stores the problems of an analysis as a JSON array.
+type audioAnalysisRow struct {
+	entity.AudioAnalysis
+	Problems string `db:"problems"`
+}
+
+func (row *audioAnalysisRow) toEntity() (*entity.AudioAnalysis, error) {
+	analysis := row.AudioAnalysis
+	if err := json.Unmarshal([]byte(row.Problems), &analysis.Problems); err != nil {
+		return nil, fmt.Errorf("failed to decode problems of audio %d: %w", row.AudioID, err)
+	}
+	return &analysis, nil
+}
+
+type AudioAnalysisRepository struct {
+	db *sqlx.DB
+}
+
+func NewAudioAnalysisRepository(db *sqlx.DB) (*AudioAnalysisRepository, error) {
+	return &AudioAnalysisRepository{db: db}, nil
+}
+
+func (r *AudioAnalysisRepository) Save(ctx context.Context, analysis *entity.AudioAnalysis) error {
+	problems := analysis.Problems
+	if problems == nil {
+		problems = []string{}
+	}
+	encoded, err := json.Marshal(problems)
+	if err != nil {
+		return fmt.Errorf("failed to encode problems: %w", err)
+	}
+	query := `
+	INSERT INTO audio_analysis (audio_id, peak_db, rms_db, clipped_ratio, noise_floor_db, snr_db, speech_ratio, problems, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	ON CONFLICT (audio_id) DO UPDATE SET
+		peak_db = excluded.peak_db,
+		rms_db = excluded.rms_db,
+		clipped_ratio = excluded.clipped_ratio,
+		noise_floor_db = excluded.noise_floor_db,
+		snr_db = excluded.snr_db,
+		speech_ratio = excluded.speech_ratio,
+		problems = excluded.problems,
+		updated_at = excluded.updated_at`
+	_, err = r.db.ExecContext(ctx, query,
+		analysis.AudioID,
+		analysis.PeakDB,
+		analysis.RMSDB,
+		analysis.ClippedRatio,
+		analysis.NoiseFloorDB,
+		analysis.SNRDB,
+		analysis.SpeechRatio,
+		string(encoded),
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save audio analysis: %w", err)
+	}
+	return nil
+}
+
+func (r *AudioAnalysisRepository) GetByAudioID(ctx context.Context, audioID uint) (*entity.AudioAnalysis, error) {
+	query := `SELECT ` + audioAnalysisColumns + ` FROM audio_analysis WHERE audio_id = ?`
+	var row audioAnalysisRow
+	if err := r.db.GetContext(ctx, &row, query, audioID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get analysis of audio %d: %w", audioID, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get audio analysis: %w", err)
+	}
+	return row.toEntity()
+}
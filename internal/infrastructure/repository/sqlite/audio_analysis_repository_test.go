@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudioAnalysisRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAudioAnalysisRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	require.NoError(t, repo.Save(ctx, &entity.AudioAnalysis{
+		AudioID:      1,
+		PeakDB:       -0.1,
+		ClippedRatio: 0.02,
+		NoiseFloorDB: -62,
+		SNRDB:        48,
+		SpeechRatio:  0.7,
+		Problems:     []string{"clipping"},
+	}))
+
+	stored, err := repo.GetByAudioID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0.02, stored.ClippedRatio)
+	assert.Equal(t, -62.0, stored.NoiseFloorDB)
+	assert.Equal(t, []string{"clipping"}, stored.Problems)
+	assert.False(t, stored.CreatedAt.IsZero())
+
+	t.Run("save replaces the analysis", func(t *testing.T) {
+		require.NoError(t, repo.Save(ctx, &entity.AudioAnalysis{AudioID: 1, NoiseFloorDB: -70, SpeechRatio: 0.7}))
+
+		stored, err := repo.GetByAudioID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, -70.0, stored.NoiseFloorDB)
+		assert.Empty(t, stored.Problems)
+	})
+
+	t.Run("get unknown audio", func(t *testing.T) {
+		_, err := repo.GetByAudioID(ctx, 999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
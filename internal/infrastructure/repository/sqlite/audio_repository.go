@@ -2,15 +2,24 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+const audioColumns = `id, original_name, current_format, storage_path, status,
+	created_at, updated_at, error, user_id, phrase_id,
+	duration, sample_rate, channels, codec, bit_rate, file_size,
+	loudness_integrated, loudness_true_peak, loudness_range, loudness_threshold, normalized_path,
+	trim_start, trim_end, needs_review, similarity_score, stale, session_id`
+
 // SQLiteAudioRepository is a repository for audio operations using SQLite.
 type AudioRepository struct {
 	db *sqlx.DB
@@ -26,16 +35,87 @@ func NewAudioRepository(db *sqlx.DB) (*AudioRepository, error) {
 
 // Store stores an audio entity in the database.
 func (r *AudioRepository) Store(ctx context.Context, audio *entity.Audio) (*entity.Audio, error) {
+	return insertAudio(ctx, r.db, audio)
+}
+
+// takeCascade deletes, in order, a take and the rows that refer to it. Every
+// placeholder is bound to the audio ID.
+var takeCascade = []string{
+	`DELETE FROM share_links WHERE audio_id = ?`,
+	`DELETE FROM audio_analysis WHERE audio_id = ?`,
+	`DELETE FROM audio_reviews WHERE audio_id = ?`,
+	`DELETE FROM annotations WHERE audio_id = ?`,
+	`DELETE FROM audios WHERE id = ?`,
+}
+
+// Replace stores audio in place of the take with ID oldID, which is deleted
+// with its share links, analysis, review and annotations.
+func (r *AudioRepository) Replace(ctx context.Context, oldID uint, audio *entity.Audio) (*entity.Audio, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range takeCascade {
+		if _, err := tx.ExecContext(ctx, stmt, oldID); err != nil {
+			return nil, fmt.Errorf("failed to delete take %d: %w", oldID, err)
+		}
+	}
+	stored, err := insertAudio(ctx, tx, audio)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return stored, nil
+}
+
+// Delete removes a take with its share links, analysis, review and
+// annotations.
+func (r *AudioRepository) Delete(ctx context.Context, id uint) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	for _, stmt := range takeCascade {
+		if result, err = tx.ExecContext(ctx, stmt, id); err != nil {
+			return fmt.Errorf("failed to delete take %d: %w", id, err)
+		}
+	}
+	// The audio row is deleted last
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to delete audio %d: %w", id, repository.ErrNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func insertAudio(ctx context.Context, q sqlx.QueryerContext, audio *entity.Audio) (*entity.Audio, error) {
 	query := `
 	INSERT INTO audios (
 		original_name, current_format, storage_path, 
 		status, created_at, updated_at, error,
-		user_id, phrase_id
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
-	RETURNING id, original_name, current_format, storage_path, status, 
-		created_at, updated_at, error, user_id, phrase_id`
+		user_id, phrase_id,
+		duration, sample_rate, channels, codec, bit_rate, file_size,
+		loudness_integrated, loudness_true_peak, loudness_range, loudness_threshold, normalized_path,
+		trim_start, trim_end, needs_review, similarity_score, stale, session_id
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26) 
+	RETURNING ` + audioColumns
 	var createdAudio entity.Audio
-	err := r.db.GetContext(ctx, &createdAudio, query,
+	err := sqlx.GetContext(ctx, q, &createdAudio, query,
 		audio.OriginalName,
 		audio.CurrentFormat,
 		audio.StoragePath,
@@ -45,6 +125,23 @@ func (r *AudioRepository) Store(ctx context.Context, audio *entity.Audio) (*enti
 		audio.Error,
 		audio.UserID,
 		audio.PhraseID,
+		audio.Duration,
+		audio.SampleRate,
+		audio.Channels,
+		audio.Codec,
+		audio.BitRate,
+		audio.FileSize,
+		audio.LoudnessIntegrated,
+		audio.LoudnessTruePeak,
+		audio.LoudnessRange,
+		audio.LoudnessThreshold,
+		audio.NormalizedPath,
+		audio.TrimStart,
+		audio.TrimEnd,
+		audio.NeedsReview,
+		audio.SimilarityScore,
+		audio.Stale,
+		audio.SessionID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to store audio: %v", err)
@@ -54,12 +151,12 @@ func (r *AudioRepository) Store(ctx context.Context, audio *entity.Audio) (*enti
 
 // GetByID retrieves an audio entity from the database by its ID.
 func (r *AudioRepository) GetByID(ctx context.Context, id uint) (*entity.Audio, error) {
-	query := `
-	SELECT id, original_name, current_format, storage_path, status, 
-		created_at, updated_at, error, user_id, phrase_id
-	FROM audios WHERE id = ?`
+	query := `SELECT ` + audioColumns + ` FROM audios WHERE id = ?`
 	audio := &entity.Audio{}
 	if err := r.db.GetContext(ctx, audio, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get audio %d: %w", id, repository.ErrNotFound)
+		}
 		return nil, fmt.Errorf("failed to get audio: %v", err)
 	}
 	return audio, nil
@@ -74,18 +171,50 @@ func (r *AudioRepository) Update(ctx context.Context, audio *entity.Audio) error
 		storage_path = :storage_path,
 		status = :status,
 		updated_at = :updated_at,
-		error = :error
+		error = :error,
+		duration = :duration,
+		sample_rate = :sample_rate,
+		channels = :channels,
+		codec = :codec,
+		bit_rate = :bit_rate,
+		file_size = :file_size,
+		loudness_integrated = :loudness_integrated,
+		loudness_true_peak = :loudness_true_peak,
+		loudness_range = :loudness_range,
+		loudness_threshold = :loudness_threshold,
+		normalized_path = :normalized_path,
+		trim_start = :trim_start,
+		trim_end = :trim_end,
+		needs_review = :needs_review,
+		similarity_score = :similarity_score,
+		stale = :stale
 	WHERE id = :id`
 	// update the updated timestamp
 	audio.UpdatedAt = time.Now()
 	result, err := r.db.NamedExecContext(ctx, query, map[string]interface{}{
-		"id":             audio.ID,
-		"original_name":  audio.OriginalName,
-		"current_format": audio.CurrentFormat,
-		"storage_path":   audio.StoragePath,
-		"status":         audio.Status,
-		"updated_at":     audio.UpdatedAt.Format(time.RFC3339),
-		"error":          audio.Error,
+		"id":                  audio.ID,
+		"original_name":       audio.OriginalName,
+		"current_format":      audio.CurrentFormat,
+		"storage_path":        audio.StoragePath,
+		"status":              audio.Status,
+		"updated_at":          audio.UpdatedAt.Format(time.RFC3339),
+		"error":               audio.Error,
+		"duration":            audio.Duration,
+		"sample_rate":         audio.SampleRate,
+		"channels":            audio.Channels,
+		"codec":               audio.Codec,
+		"bit_rate":            audio.BitRate,
+		"file_size":           audio.FileSize,
+		"loudness_integrated": audio.LoudnessIntegrated,
+		"loudness_true_peak":  audio.LoudnessTruePeak,
+		"loudness_range":      audio.LoudnessRange,
+		"loudness_threshold":  audio.LoudnessThreshold,
+		"normalized_path":     audio.NormalizedPath,
+		"trim_start":          audio.TrimStart,
+		"trim_end":            audio.TrimEnd,
+		"needs_review":        audio.NeedsReview,
+		"similarity_score":    audio.SimilarityScore,
+		"stale":               audio.Stale,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update audio: %v", err)
@@ -97,25 +226,93 @@ func (r *AudioRepository) Update(ctx context.Context, audio *entity.Audio) error
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("audio not found: %d", audio.ID)
+		return fmt.Errorf("failed to update audio %d: %w", audio.ID, repository.ErrNotFound)
 	}
 
 	return nil
 }
 
 // GetByUserIDAndPhraseID retrieves an audio entity from the database by user ID and phrase ID.
+func (r *AudioRepository) SetSimilarityScore(ctx context.Context, id uint, score *float64) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE audios SET similarity_score = ? WHERE id = ?`, score, id)
+	if err != nil {
+		return fmt.Errorf("failed to set similarity score: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to set similarity score of audio %d: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
 func (r *AudioRepository) GetByUserIDAndPhraseID(ctx context.Context, userID uint, phraseID uint) (*entity.Audio, error) {
-	query := `
-	SELECT id, original_name, current_format, storage_path, status, 
-		created_at, updated_at, error, user_id, phrase_id
-	FROM audios WHERE user_id = ? AND phrase_id = ?`
+	query := `SELECT ` + audioColumns + ` FROM audios WHERE user_id = ? AND phrase_id = ?`
 	audio := &entity.Audio{}
 	if err := r.db.GetContext(ctx, audio, query, userID, phraseID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get audio of user %d for phrase %d: %w", userID, phraseID, repository.ErrNotFound)
+		}
 		return nil, fmt.Errorf("failed to get audio: %v", err)
 	}
 	return audio, nil
 }
 
+// ListByPhraseID retrieves all audio entities recorded for a phrase, oldest first.
+func (r *AudioRepository) ListByPhraseID(ctx context.Context, phraseID uint) ([]*entity.Audio, error) {
+	query := `SELECT ` + audioColumns + ` FROM audios WHERE phrase_id = ? ORDER BY id`
+	var audios []*entity.Audio
+	if err := r.db.SelectContext(ctx, &audios, query, phraseID); err != nil {
+		return nil, fmt.Errorf("failed to list audio of phrase %d: %v", phraseID, err)
+	}
+	return audios, nil
+}
+
+// ListByUserID retrieves all audio entities recorded by a user, oldest first.
+func (r *AudioRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Audio, error) {
+	query := `SELECT ` + audioColumns + ` FROM audios WHERE user_id = ? ORDER BY id`
+	var audios []*entity.Audio
+	if err := r.db.SelectContext(ctx, &audios, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list audio of user %d: %v", userID, err)
+	}
+	return audios, nil
+}
+
+// ListBySessionID retrieves all audio entities uploaded in a recording session, oldest first.
+func (r *AudioRepository) ListBySessionID(ctx context.Context, sessionID uint) ([]*entity.Audio, error) {
+	query := `SELECT ` + audioColumns + ` FROM audios WHERE session_id = ? ORDER BY id`
+	var audios []*entity.Audio
+	if err := r.db.SelectContext(ctx, &audios, query, sessionID); err != nil {
+		return nil, fmt.Errorf("failed to list audio of session %d: %v", sessionID, err)
+	}
+	return audios, nil
+}
+
+// Progress counts the phrases userID may record by the status of their take.
+// A user has at most one take per phrase.
+func (r *AudioRepository) Progress(ctx context.Context, userID uint) (*entity.RecordingProgress, error) {
+	query := `SELECT COUNT(*) AS phrases,
+		COUNT(*) - COUNT(audios.id) AS no_audio,
+		COUNT(CASE WHEN audios.status = ? THEN 1 END) AS pending,
+		COUNT(CASE WHEN audios.status = ? THEN 1 END) AS converting,
+		COUNT(CASE WHEN audios.status = ? THEN 1 END) AS completed,
+		COUNT(CASE WHEN audios.status = ? THEN 1 END) AS failed,
+		COALESCE(SUM(CASE WHEN audios.status = ? THEN audios.duration END), 0) AS recorded_duration
+		FROM phrases
+		LEFT JOIN audios ON audios.phrase_id = phrases.id AND audios.user_id = ?
+		WHERE phrases.id IN (` + recordablePhrases + `)`
+	var progress entity.RecordingProgress
+	err := r.db.GetContext(ctx, &progress, query,
+		entity.AudioStatusPending, entity.AudioStatusConverting, entity.AudioStatusCompleted, entity.AudioStatusFailed,
+		entity.AudioStatusCompleted, userID, userID, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recording progress of user %d: %v", userID, err)
+	}
+	return &progress, nil
+}
+
 // Close closes the SQLite database connection.
 func (r *AudioRepository) Close() error {
 	return r.db.Close()
@@ -2,11 +2,15 @@ package sqlite
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSQLiteAudioRepository(t *testing.T) {
@@ -22,7 +26,7 @@ func TestSQLiteAudioRepository(t *testing.T) {
 				audio := &entity.Audio{
 					ID:            1,
 					OriginalName:  "test.m4a",
-					CurrentFormat: ".m4a",
+					CurrentFormat: "m4a",
 					StoragePath:   "original/test.m4a",
 					Status:        entity.AudioStatusPending,
 					CreatedAt:     time.Now().UTC(),
@@ -50,7 +54,7 @@ func TestSQLiteAudioRepository(t *testing.T) {
 				audio := &entity.Audio{
 					ID:            2,
 					OriginalName:  "test2.m4a",
-					CurrentFormat: ".m4a",
+					CurrentFormat: "m4a",
 					StoragePath:   "original/test2.m4a",
 					Status:        entity.AudioStatusPending,
 					CreatedAt:     time.Now().UTC(),
@@ -79,6 +83,139 @@ func TestSQLiteAudioRepository(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Store and update audio metadata",
+			setup: func(repo *AudioRepository) (*entity.Audio, error) {
+				audio := &entity.Audio{
+					OriginalName:  "test3.m4a",
+					CurrentFormat: "m4a",
+					StoragePath:   "original/test3.m4a",
+					Status:        entity.AudioStatusPending,
+					CreatedAt:     time.Now().UTC(),
+					UpdatedAt:     time.Now().UTC(),
+					UserID:        1,
+					PhraseID:      2,
+					Duration:      1.5,
+					SampleRate:    48000,
+					Channels:      2,
+					Codec:         "aac",
+					BitRate:       128000,
+					FileSize:      24000,
+				}
+				audio, err := repo.Store(context.Background(), audio)
+				if err != nil {
+					return nil, err
+				}
+				if audio.SampleRate != 48000 || audio.Codec != "aac" {
+					return nil, fmt.Errorf("stored metadata mismatch: %+v", audio)
+				}
+				audio.ApplyMetadata(&entity.AudioMetadata{
+					Format:     "wav",
+					Duration:   1.5,
+					SampleRate: 44100,
+					Channels:   2,
+					Codec:      "pcm_s16le",
+					BitRate:    1411200,
+					FileSize:   264644,
+				})
+				err = repo.Update(context.Background(), audio)
+				return audio, err
+			},
+			check: func(t *testing.T, repo *AudioRepository, audio *entity.Audio) {
+				updated, err := repo.GetByUserIDAndPhraseID(context.Background(), 1, 2)
+				if err != nil {
+					t.Fatalf("GetByUserIDAndPhraseID failed: %v", err)
+				}
+				if updated.CurrentFormat != "wav" || updated.SampleRate != 44100 || updated.Codec != "pcm_s16le" ||
+					updated.Duration != 1.5 || updated.Channels != 2 || updated.BitRate != 1411200 || updated.FileSize != 264644 {
+					t.Errorf("Updated metadata mismatch: %+v", updated)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Update audio loudness and trim",
+			setup: func(repo *AudioRepository) (*entity.Audio, error) {
+				audio, err := repo.Store(context.Background(), &entity.Audio{
+					OriginalName:  "test4.m4a",
+					CurrentFormat: "m4a",
+					Status:        entity.AudioStatusPending,
+					CreatedAt:     time.Now().UTC(),
+					UpdatedAt:     time.Now().UTC(),
+					UserID:        1,
+					PhraseID:      3,
+				})
+				if err != nil {
+					return nil, err
+				}
+				if audio.LoudnessIntegrated != nil {
+					return nil, fmt.Errorf("expected unmeasured loudness, got %v", *audio.LoudnessIntegrated)
+				}
+				audio.ApplyLoudness(&entity.Loudness{Integrated: -31.5, TruePeak: -12, Range: 3.5, Threshold: -41.75})
+				audio.NormalizedPath = "audio/normalized/4.wav"
+				audio.ApplyTrim(0.45, 1.55)
+				err = repo.Update(context.Background(), audio)
+				return audio, err
+			},
+			check: func(t *testing.T, repo *AudioRepository, audio *entity.Audio) {
+				updated, err := repo.GetByID(context.Background(), audio.ID)
+				if err != nil {
+					t.Fatalf("GetByID failed: %v", err)
+				}
+				if updated.LoudnessIntegrated == nil || *updated.LoudnessIntegrated != -31.5 ||
+					updated.LoudnessThreshold == nil || *updated.LoudnessThreshold != -41.75 ||
+					updated.NormalizedPath != "audio/normalized/4.wav" ||
+					updated.TrimStart == nil || *updated.TrimStart != 0.45 || updated.TrimEnd == nil || *updated.TrimEnd != 1.55 {
+					t.Errorf("Updated loudness and trim mismatch: %+v", updated)
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "List audio of a phrase with similarity scores",
+			setup: func(repo *AudioRepository) (*entity.Audio, error) {
+				score := 0.75
+				for userID := uint(1); userID <= 2; userID++ {
+					audio, err := repo.Store(context.Background(), &entity.Audio{
+						OriginalName:  "take.m4a",
+						CurrentFormat: "m4a",
+						Status:        entity.AudioStatusPending,
+						CreatedAt:     time.Now().UTC(),
+						UpdatedAt:     time.Now().UTC(),
+						UserID:        userID,
+						PhraseID:      5,
+					})
+					if err != nil {
+						return nil, err
+					}
+					if userID == 2 {
+						audio.SimilarityScore = &score
+						return audio, repo.Update(context.Background(), audio)
+					}
+				}
+				return nil, nil
+			},
+			check: func(t *testing.T, repo *AudioRepository, audio *entity.Audio) {
+				audios, err := repo.ListByPhraseID(context.Background(), 5)
+				if err != nil {
+					t.Fatalf("ListByPhraseID failed: %v", err)
+				}
+				if len(audios) != 2 || audios[0].UserID != 1 || audios[1].ID != audio.ID {
+					t.Fatalf("unexpected audio of phrase: %+v", audios)
+				}
+				if audios[0].SimilarityScore != nil || audios[1].SimilarityScore == nil || *audios[1].SimilarityScore != 0.75 {
+					t.Errorf("similarity score mismatch: %v, %v", audios[0].SimilarityScore, audios[1].SimilarityScore)
+				}
+				if none, err := repo.ListByPhraseID(context.Background(), 6); err != nil || len(none) != 0 {
+					t.Errorf("expected no audio for another phrase, got %v, %v", none, err)
+				}
+				byUser, err := repo.ListByUserID(context.Background(), 2)
+				if err != nil || len(byUser) != 1 || byUser[0].ID != audio.ID {
+					t.Errorf("unexpected audio of user: %v, %v", byUser, err)
+				}
+			},
+			wantErr: false,
+		},
 		{
 			name: "Get non-existent audio",
 			setup: func(repo *AudioRepository) (*entity.Audio, error) {
@@ -98,7 +235,7 @@ func TestSQLiteAudioRepository(t *testing.T) {
 				audio := &entity.Audio{
 					ID:            9999,
 					OriginalName:  "nonexistent.m4a",
-					CurrentFormat: ".m4a",
+					CurrentFormat: "m4a",
 					Status:        entity.AudioStatusCompleted,
 				}
 				err := repo.Update(context.Background(), audio)
@@ -135,3 +272,168 @@ func TestSQLiteAudioRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestAudioRepository_Progress(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+	phraseRepo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+	collectionRepo, err := NewCollectionRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var ids []uint
+	for _, text := range []string{"completed", "failed", "pending", "untaken"} {
+		phrase, err := phraseRepo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: text, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+		ids = append(ids, phrase.ID)
+	}
+	takes := []struct {
+		userID, phraseID uint
+		status           entity.AudioStatus
+		duration         float64
+	}{
+		{1, ids[0], entity.AudioStatusCompleted, 2.5},
+		{1, ids[1], entity.AudioStatusFailed, 4},
+		{1, ids[2], entity.AudioStatusPending, 1},
+		{2, ids[3], entity.AudioStatusCompleted, 8}, // another speaker
+	}
+	for _, take := range takes {
+		_, err = repo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: take.status, Duration: take.duration,
+			UserID: take.userID, PhraseID: take.phraseID, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+	}
+
+	// Phrases of other users count once shared or assigned
+	shared, err := phraseRepo.Create(ctx, &entity.Phrase{UserID: 2, Phrase: "shared", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, phraseRepo.Share(ctx, shared.ID, 1))
+	collection, err := collectionRepo.Create(ctx, &entity.Collection{UserID: 2, Name: "assigned", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		[]string{"assigned"})
+	require.NoError(t, err)
+	require.NoError(t, collectionRepo.Assign(ctx, collection.ID, 1))
+	_, err = phraseRepo.Create(ctx, &entity.Phrase{UserID: 2, Phrase: "private", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+
+	progress, err := repo.Progress(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.RecordingProgress{
+		Phrases:          6,
+		NoAudio:          3,
+		Pending:          1,
+		Completed:        1,
+		Failed:           1,
+		RecordedDuration: 2.5,
+	}, progress)
+
+	progress, err = repo.Progress(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.RecordingProgress{}, progress)
+}
+
+func TestAudioRepository_Replace(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+	reviewRepo, err := NewAudioReviewRepository(db)
+	require.NoError(t, err)
+	annotationRepo, err := NewAnnotationRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	newTake := func() *entity.Audio {
+		return &entity.Audio{
+			OriginalName: "take.m4a", CurrentFormat: "m4a", StoragePath: "audio/original/1-1.m4a",
+			Status: entity.AudioStatusPending, UserID: 1, PhraseID: 1, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		}
+	}
+
+	old, err := repo.Store(ctx, newTake())
+	require.NoError(t, err)
+	_, err = reviewRepo.Save(ctx, &entity.AudioReview{AudioID: old.ID, Status: entity.ReviewStatusNeedsRerecord, ReviewerID: 2})
+	require.NoError(t, err)
+	_, err = annotationRepo.Create(ctx, &entity.Annotation{AudioID: old.ID, Tier: "words", Label: "hello", End: 1})
+	require.NoError(t, err)
+
+	// The user and phrase of a take are unique, so storing another fails.
+	_, err = repo.Store(ctx, newTake())
+	require.Error(t, err)
+
+	replaced, err := repo.Replace(ctx, old.ID, newTake())
+	require.NoError(t, err)
+	assert.NotEqual(t, old.ID, replaced.ID)
+
+	_, err = repo.GetByID(ctx, old.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = reviewRepo.GetByAudioID(ctx, old.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	var annotations int
+	require.NoError(t, db.Get(&annotations, `SELECT COUNT(*) FROM annotations WHERE audio_id = ?`, old.ID))
+	assert.Zero(t, annotations)
+
+	current, err := repo.GetByUserIDAndPhraseID(ctx, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, replaced.ID, current.ID)
+}
+
+func TestAudioRepository_SetSimilarityScore(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	stored, err := repo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: entity.AudioStatusCompleted, UserID: 1, PhraseID: 1,
+		CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+
+	score := 0.75
+	require.NoError(t, repo.SetSimilarityScore(ctx, stored.ID, &score))
+	got, err := repo.GetByID(ctx, stored.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.SimilarityScore)
+	assert.Equal(t, score, *got.SimilarityScore)
+	assert.Equal(t, entity.AudioStatusCompleted, got.Status)
+
+	require.NoError(t, repo.SetSimilarityScore(ctx, stored.ID, nil))
+	got, err = repo.GetByID(ctx, stored.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.SimilarityScore)
+
+	assert.ErrorIs(t, repo.SetSimilarityScore(ctx, 999, &score), repository.ErrNotFound)
+}
+
+func TestAudioRepository_Delete(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+	reviewRepo, err := NewAudioReviewRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	stored, err := repo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: entity.AudioStatusPending, UserID: 1, PhraseID: 1,
+		CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	_, err = reviewRepo.Save(ctx, &entity.AudioReview{AudioID: stored.ID, Status: entity.ReviewStatusApproved, ReviewerID: 2})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Delete(ctx, stored.ID))
+	_, err = repo.GetByID(ctx, stored.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = reviewRepo.GetByAudioID(ctx, stored.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	assert.ErrorIs(t, repo.Delete(ctx, stored.ID), repository.ErrNotFound)
+}
@@ -0,0 +1,230 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const audioReviewColumns = `audio_id, status, reviewer_id, reasons, notes, created_at, updated_at`
+
+// audioReviewRow stores the reasons of a review as a JSON array.
+type audioReviewRow struct {
+	entity.AudioReview
+	Reasons string `db:"reasons"`
+}
+
+func (row *audioReviewRow) toEntity() (*entity.AudioReview, error) {
+	review := row.AudioReview
+	if err := json.Unmarshal([]byte(row.Reasons), &review.Reasons); err != nil {
+		return nil, fmt.Errorf("failed to decode reasons of review of audio %d: %w", row.AudioID, err)
+	}
+	return &review, nil
+}
+
+// sessionReviewRow stores the reasons of a review as a JSON array.
+type sessionReviewRow struct {
+	entity.SessionReview
+	Reasons string `db:"reasons"`
+}
+
+// rejectedTakeRow stores the reasons of a review as a JSON array.
+type rejectedTakeRow struct {
+	entity.RejectedTake
+	Reasons string `db:"reasons"`
+}
+
+type AudioReviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewAudioReviewRepository(db *sqlx.DB) (*AudioReviewRepository, error) {
+	return &AudioReviewRepository{db: db}, nil
+}
+
+// encodeReasons stores the reasons of a review as a JSON array.
+func encodeReasons(reasons []string) (string, error) {
+	if reasons == nil {
+		reasons = []string{}
+	}
+	encoded, err := json.Marshal(reasons)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode reasons: %w", err)
+	}
+	return string(encoded), nil
+}
+
+func (r *AudioReviewRepository) Save(ctx context.Context, review *entity.AudioReview) (*entity.AudioReview, error) {
+	encoded, err := encodeReasons(review.Reasons)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+	INSERT INTO audio_reviews (audio_id, status, reviewer_id, reasons, notes, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $6)
+	ON CONFLICT (audio_id) DO UPDATE SET
+		status = excluded.status,
+		reviewer_id = excluded.reviewer_id,
+		reasons = excluded.reasons,
+		notes = excluded.notes,
+		updated_at = excluded.updated_at
+	RETURNING ` + audioReviewColumns
+	var row audioReviewRow
+	err = r.db.GetContext(ctx, &row, query, review.AudioID, review.Status, review.ReviewerID, encoded, review.Notes, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save audio review: %w", err)
+	}
+	return row.toEntity()
+}
+
+func (r *AudioReviewRepository) SaveSession(ctx context.Context, review *entity.SessionReview) ([]*entity.AudioReview, error) {
+	encoded, err := encodeReasons(review.Reasons)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO session_reviews (session_id, status, reviewer_id, reasons, notes, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $6)
+	ON CONFLICT (session_id) DO UPDATE SET
+		status = excluded.status,
+		reviewer_id = excluded.reviewer_id,
+		reasons = excluded.reasons,
+		notes = excluded.notes,
+		updated_at = excluded.updated_at`
+	if _, err := tx.ExecContext(ctx, query, review.SessionID, review.Status, review.ReviewerID, encoded, review.Notes, now); err != nil {
+		return nil, fmt.Errorf("failed to save review of session %d: %w", review.SessionID, err)
+	}
+
+	// WHERE true keeps SQLite from reading ON CONFLICT as a join constraint.
+	query = `
+	INSERT INTO audio_reviews (audio_id, status, reviewer_id, reasons, notes, created_at, updated_at)
+	SELECT id, $1, $2, $3, $4, $5, $5 FROM audios WHERE session_id = $6 AND status = $7 AND true
+	ON CONFLICT (audio_id) DO UPDATE SET
+		status = excluded.status,
+		reviewer_id = excluded.reviewer_id,
+		reasons = excluded.reasons,
+		notes = excluded.notes,
+		updated_at = excluded.updated_at`
+	_, err = tx.ExecContext(ctx, query, review.Status, review.ReviewerID, encoded, review.Notes, now, review.SessionID, entity.AudioStatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reviews of takes of session %d: %w", review.SessionID, err)
+	}
+
+	query = `SELECT ` + audioReviewColumns + ` FROM audio_reviews
+		WHERE audio_id IN (SELECT id FROM audios WHERE session_id = ? AND status = ?) ORDER BY audio_id`
+	var rows []audioReviewRow
+	if err := tx.SelectContext(ctx, &rows, query, review.SessionID, entity.AudioStatusCompleted); err != nil {
+		return nil, fmt.Errorf("failed to list reviews of takes of session %d: %w", review.SessionID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	reviews := make([]*entity.AudioReview, 0, len(rows))
+	for i := range rows {
+		review, err := rows[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
+
+func (r *AudioReviewRepository) GetBySessionID(ctx context.Context, sessionID uint) (*entity.SessionReview, error) {
+	query := `SELECT session_id, status, reviewer_id, reasons, notes, created_at, updated_at FROM session_reviews WHERE session_id = ?`
+	var row sessionReviewRow
+	if err := r.db.GetContext(ctx, &row, query, sessionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get review of session %d: %w", sessionID, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get session review: %w", err)
+	}
+	review := row.SessionReview
+	if err := json.Unmarshal([]byte(row.Reasons), &review.Reasons); err != nil {
+		return nil, fmt.Errorf("failed to decode reasons of review of session %d: %w", sessionID, err)
+	}
+	return &review, nil
+}
+
+func (r *AudioReviewRepository) GetByAudioID(ctx context.Context, audioID uint) (*entity.AudioReview, error) {
+	query := `SELECT ` + audioReviewColumns + ` FROM audio_reviews WHERE audio_id = ?`
+	var row audioReviewRow
+	if err := r.db.GetContext(ctx, &row, query, audioID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get review of audio %d: %w", audioID, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get audio review: %w", err)
+	}
+	return row.toEntity()
+}
+
+func (r *AudioReviewRepository) ListUnreviewed(ctx context.Context, filter repository.ReviewQueueFilter) ([]*entity.Audio, int, error) {
+	conditions := []string{`status = ?`, `NOT EXISTS (SELECT 1 FROM audio_reviews WHERE audio_reviews.audio_id = audios.id)`}
+	args := []interface{}{entity.AudioStatusCompleted}
+	if filter.UserID != 0 {
+		conditions = append(conditions, `user_id = ?`)
+		args = append(args, filter.UserID)
+	}
+	if filter.PhraseID != 0 {
+		conditions = append(conditions, `phrase_id = ?`)
+		args = append(args, filter.PhraseID)
+	}
+	if filter.SessionID != 0 {
+		conditions = append(conditions, `session_id = ?`)
+		args = append(args, filter.SessionID)
+	}
+	where := ` WHERE ` + strings.Join(conditions, ` AND `)
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM audios`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count unreviewed audio: %w", err)
+	}
+
+	query := `SELECT ` + audioColumns + ` FROM audios` + where + ` ORDER BY needs_review DESC, id LIMIT ? OFFSET ?`
+	audios := []*entity.Audio{}
+	if err := r.db.SelectContext(ctx, &audios, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list unreviewed audio: %w", err)
+	}
+	return audios, total, nil
+}
+
+func (r *AudioReviewRepository) ListRejected(ctx context.Context, userID uint) ([]*entity.RejectedTake, error) {
+	query := `SELECT audio_reviews.audio_id, audio_reviews.status, audio_reviews.reviewer_id, audio_reviews.reasons,
+		audio_reviews.notes, audio_reviews.created_at, audio_reviews.updated_at, audios.phrase_id, phrases.phrase
+		FROM audio_reviews
+		JOIN audios ON audios.id = audio_reviews.audio_id
+		JOIN phrases ON phrases.id = audios.phrase_id
+		WHERE audios.user_id = ? AND audio_reviews.status IN (?, ?)
+		ORDER BY audio_reviews.updated_at DESC, audio_reviews.audio_id DESC`
+	var rows []rejectedTakeRow
+	err := r.db.SelectContext(ctx, &rows, query, userID, entity.ReviewStatusRejected, entity.ReviewStatusNeedsRerecord)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rejected takes of user %d: %w", userID, err)
+	}
+	takes := make([]*entity.RejectedTake, 0, len(rows))
+	for i := range rows {
+		take := rows[i].RejectedTake
+		if err := json.Unmarshal([]byte(rows[i].Reasons), &take.Reasons); err != nil {
+			return nil, fmt.Errorf("failed to decode reasons of review of audio %d: %w", take.AudioID, err)
+		}
+		takes = append(takes, &take)
+	}
+	return takes, nil
+}
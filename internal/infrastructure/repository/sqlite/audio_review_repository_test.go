@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudioReviewRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAudioReviewRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	stored, err := repo.Save(ctx, &entity.AudioReview{
+		AudioID:    1,
+		Status:     entity.ReviewStatusRejected,
+		ReviewerID: 7,
+		Reasons:    []string{entity.ReviewReasonNoise},
+		Notes:      "traffic in the background",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReviewStatusRejected, stored.Status)
+	assert.Equal(t, []string{entity.ReviewReasonNoise}, stored.Reasons)
+	assert.False(t, stored.CreatedAt.IsZero())
+
+	t.Run("save replaces the review", func(t *testing.T) {
+		saved, err := repo.Save(ctx, &entity.AudioReview{AudioID: 1, Status: entity.ReviewStatusApproved, ReviewerID: 8})
+		require.NoError(t, err)
+
+		got, err := repo.GetByAudioID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, saved, got)
+		assert.Equal(t, entity.ReviewStatusApproved, got.Status)
+		assert.Equal(t, uint(8), got.ReviewerID)
+		assert.Empty(t, got.Reasons)
+		assert.Empty(t, got.Notes)
+		assert.Equal(t, stored.CreatedAt, got.CreatedAt)
+	})
+
+	t.Run("get unknown audio", func(t *testing.T) {
+		_, err := repo.GetByAudioID(ctx, 999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestAudioReviewRepository_Lists(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAudioReviewRepository(db)
+	require.NoError(t, err)
+	audioRepo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+	phraseRepo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var phrases []*entity.Phrase
+	for _, text := range []string{"one", "two", "three", "four"} {
+		phrase, err := phraseRepo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: text, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+		phrases = append(phrases, phrase)
+	}
+	takes := []struct {
+		userID      uint
+		phrase      *entity.Phrase
+		status      entity.AudioStatus
+		needsReview bool
+	}{
+		{1, phrases[0], entity.AudioStatusCompleted, false},
+		{1, phrases[1], entity.AudioStatusCompleted, true},
+		{1, phrases[2], entity.AudioStatusFailed, false},
+		{1, phrases[3], entity.AudioStatusCompleted, false},
+		{2, phrases[0], entity.AudioStatusCompleted, false},
+	}
+	var audios []*entity.Audio
+	for _, take := range takes {
+		audio, err := audioRepo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: take.status, NeedsReview: take.needsReview,
+			UserID: take.userID, PhraseID: take.phrase.ID, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+		audios = append(audios, audio)
+	}
+	_, err = repo.Save(ctx, &entity.AudioReview{AudioID: audios[3].ID, Status: entity.ReviewStatusNeedsRerecord, ReviewerID: 9,
+		Reasons: []string{entity.ReviewReasonTruncated}})
+	require.NoError(t, err)
+
+	t.Run("unreviewed", func(t *testing.T) {
+		queue, total, err := repo.ListUnreviewed(ctx, repository.ReviewQueueFilter{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		require.Len(t, queue, 3)
+		// Takes flagged by analysis come first
+		assert.Equal(t, []uint{audios[1].ID, audios[0].ID, audios[4].ID}, []uint{queue[0].ID, queue[1].ID, queue[2].ID})
+
+		queue, total, err = repo.ListUnreviewed(ctx, repository.ReviewQueueFilter{UserID: 1, Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, queue, 1)
+		assert.Equal(t, audios[0].ID, queue[0].ID)
+
+		queue, total, err = repo.ListUnreviewed(ctx, repository.ReviewQueueFilter{PhraseID: phrases[0].ID, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, queue, 2)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		_, err := repo.Save(ctx, &entity.AudioReview{AudioID: audios[0].ID, Status: entity.ReviewStatusApproved, ReviewerID: 9})
+		require.NoError(t, err)
+		_, err = repo.Save(ctx, &entity.AudioReview{AudioID: audios[1].ID, Status: entity.ReviewStatusRejected, ReviewerID: 9,
+			Reasons: []string{entity.ReviewReasonClipping, entity.ReviewReasonOther}, Notes: "distorted"})
+		require.NoError(t, err)
+
+		takes, err := repo.ListRejected(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, takes, 2)
+		assert.Equal(t, audios[1].ID, takes[0].AudioID)
+		assert.Equal(t, phrases[1].ID, takes[0].PhraseID)
+		assert.Equal(t, "two", takes[0].Phrase)
+		assert.Equal(t, []string{entity.ReviewReasonClipping, entity.ReviewReasonOther}, takes[0].Reasons)
+		assert.Equal(t, "distorted", takes[0].Notes)
+		assert.Equal(t, audios[3].ID, takes[1].AudioID)
+		assert.Equal(t, entity.ReviewStatusNeedsRerecord, takes[1].Status)
+
+		takes, err = repo.ListRejected(ctx, 2)
+		require.NoError(t, err)
+		assert.Empty(t, takes)
+	})
+}
+
+func TestAudioReviewRepository_SaveSession(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewAudioReviewRepository(db)
+	require.NoError(t, err)
+	audioRepo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var audios []*entity.Audio
+	for i, status := range []entity.AudioStatus{entity.AudioStatusCompleted, entity.AudioStatusConverting, entity.AudioStatusCompleted} {
+		audio, err := audioRepo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: status, UserID: 1, PhraseID: uint(i + 1),
+			SessionID: 4, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+		audios = append(audios, audio)
+	}
+	other, err := audioRepo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: entity.AudioStatusCompleted, UserID: 1, PhraseID: 4,
+		SessionID: 5, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	_, err = repo.Save(ctx, &entity.AudioReview{AudioID: audios[2].ID, Status: entity.ReviewStatusApproved, ReviewerID: 8})
+	require.NoError(t, err)
+
+	reviews, err := repo.SaveSession(ctx, &entity.SessionReview{SessionID: 4, Status: entity.ReviewStatusRejected, ReviewerID: 9,
+		Reasons: []string{entity.ReviewReasonClipping}, Notes: "broken mic"})
+	require.NoError(t, err)
+	require.Len(t, reviews, 2)
+	for i, review := range reviews {
+		assert.Equal(t, []uint{audios[0].ID, audios[2].ID}[i], review.AudioID)
+		assert.Equal(t, entity.ReviewStatusRejected, review.Status)
+		assert.Equal(t, uint(9), review.ReviewerID)
+		assert.Equal(t, []string{entity.ReviewReasonClipping}, review.Reasons)
+		assert.Equal(t, "broken mic", review.Notes)
+	}
+
+	verdict, err := repo.GetBySessionID(ctx, 4)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReviewStatusRejected, verdict.Status)
+	assert.Equal(t, []string{entity.ReviewReasonClipping}, verdict.Reasons)
+
+	_, err = repo.GetByAudioID(ctx, audios[1].ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repo.GetByAudioID(ctx, other.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repo.GetBySessionID(ctx, 5)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
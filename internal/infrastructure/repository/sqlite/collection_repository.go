@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const collectionColumns = `id, user_id, name, language, tags, created_at, updated_at`
+
+// collectionRow stores the tags of a collection as a JSON array.
+type collectionRow struct {
+	entity.Collection
+	Tags string `db:"tags"`
+}
+
+func (row *collectionRow) toEntity() (*entity.Collection, error) {
+	collection := row.Collection
+	if err := json.Unmarshal([]byte(row.Tags), &collection.Tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags of collection %d: %w", row.ID, err)
+	}
+	return &collection, nil
+}
+
+// collectionPhraseRow is a phraseRow at a position of a collection.
+type collectionPhraseRow struct {
+	Position int `db:"position"`
+	phraseRow
+}
+
+func (row *collectionPhraseRow) toEntity() (*entity.CollectionPhrase, error) {
+	phrase, err := row.phraseRow.toEntity()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.CollectionPhrase{Position: row.Position, Phrase: *phrase}, nil
+}
+
+func collectionsFromRows(rows []collectionRow) ([]*entity.Collection, error) {
+	collections := make([]*entity.Collection, 0, len(rows))
+	for i := range rows {
+		collection, err := rows[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, nil
+}
+
+type CollectionRepository struct {
+	db *sqlx.DB
+}
+
+func NewCollectionRepository(db *sqlx.DB) (*CollectionRepository, error) {
+	return &CollectionRepository{db: db}, nil
+}
+
+func (r *CollectionRepository) Create(ctx context.Context, collection *entity.Collection, texts []string) (*entity.Collection, error) {
+	tags, err := json.Marshal(collection.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tags: %w", err)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	var row collectionRow
+	err = tx.GetContext(ctx, &row, `INSERT INTO collections (user_id, name, language, tags, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?) RETURNING `+collectionColumns,
+		collection.UserID, collection.Name, collection.Language, string(tags), now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	for i, text := range texts {
+		var phraseID uint
+		err := tx.GetContext(ctx, &phraseID, `INSERT INTO phrases (user_id, phrase, created_at, updated_at) VALUES (?, ?, ?, ?) RETURNING id`,
+			collection.UserID, text, now, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create phrase %d of collection: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO collection_phrases (collection_id, position, phrase_id) VALUES (?, ?, ?)`,
+			row.ID, i+1, phraseID); err != nil {
+			return nil, fmt.Errorf("failed to add phrase %d to collection: %w", i+1, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return row.toEntity()
+}
+
+func (r *CollectionRepository) GetByID(ctx context.Context, id uint) (*entity.Collection, error) {
+	var row collectionRow
+	if err := r.db.GetContext(ctx, &row, `SELECT `+collectionColumns+` FROM collections WHERE id = ?`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get collection %d: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return row.toEntity()
+}
+
+// ListByUserID retrieves the collections a user owns, oldest first.
+func (r *CollectionRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Collection, error) {
+	var rows []collectionRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT `+collectionColumns+` FROM collections WHERE user_id = ? ORDER BY id`, userID); err != nil {
+		return nil, fmt.Errorf("failed to list collections of user %d: %w", userID, err)
+	}
+	return collectionsFromRows(rows)
+}
+
+// ListAssigned retrieves the collections assigned to a user, oldest first.
+func (r *CollectionRepository) ListAssigned(ctx context.Context, userID uint) ([]*entity.Collection, error) {
+	query := `SELECT ` + collectionColumns + ` FROM collections
+		WHERE id IN (SELECT collection_id FROM collection_assignments WHERE user_id = ?) ORDER BY id`
+	var rows []collectionRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list collections assigned to user %d: %w", userID, err)
+	}
+	return collectionsFromRows(rows)
+}
+
+func (r *CollectionRepository) ListPhrases(ctx context.Context, collectionID uint) ([]*entity.CollectionPhrase, error) {
+	query := `SELECT collection_phrases.position, ` + qualifiedPhraseColumns + ` FROM collection_phrases
+		JOIN phrases ON phrases.id = collection_phrases.phrase_id
+		WHERE collection_phrases.collection_id = ? ORDER BY collection_phrases.position`
+	var rows []collectionPhraseRow
+	if err := r.db.SelectContext(ctx, &rows, query, collectionID); err != nil {
+		return nil, fmt.Errorf("failed to list phrases of collection %d: %w", collectionID, err)
+	}
+	phrases := make([]*entity.CollectionPhrase, 0, len(rows))
+	for i := range rows {
+		phrase, err := rows[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		phrases = append(phrases, phrase)
+	}
+	return phrases, nil
+}
+
+func (r *CollectionRepository) GetPhrase(ctx context.Context, collectionID uint, position int) (*entity.CollectionPhrase, error) {
+	query := `SELECT collection_phrases.position, ` + qualifiedPhraseColumns + ` FROM collection_phrases
+		JOIN phrases ON phrases.id = collection_phrases.phrase_id
+		WHERE collection_phrases.collection_id = ? AND collection_phrases.position = ?`
+	var row collectionPhraseRow
+	if err := r.db.GetContext(ctx, &row, query, collectionID, position); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get phrase %d of collection %d: %w", position, collectionID, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get collection phrase: %w", err)
+	}
+	return row.toEntity()
+}
+
+func (r *CollectionRepository) Assign(ctx context.Context, collectionID, userID uint) error {
+	_, err := r.db.ExecContext(ctx, `INSERT OR IGNORE INTO collection_assignments (collection_id, user_id, created_at) VALUES (?, ?, ?)`,
+		collectionID, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to assign collection: %v", err)
+	}
+	return nil
+}
+
+func (r *CollectionRepository) Unassign(ctx context.Context, collectionID, userID uint) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM collection_assignments WHERE collection_id = ? AND user_id = ?`, collectionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unassign collection: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("collection %d is not assigned to user %d: %w", collectionID, userID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// ListAssignees retrieves the IDs of the users a collection is assigned to,
+// in ascending order.
+func (r *CollectionRepository) ListAssignees(ctx context.Context, collectionID uint) ([]uint, error) {
+	userIDs := []uint{}
+	if err := r.db.SelectContext(ctx, &userIDs, `SELECT user_id FROM collection_assignments WHERE collection_id = ? ORDER BY user_id`, collectionID); err != nil {
+		return nil, fmt.Errorf("failed to list assignees of collection %d: %w", collectionID, err)
+	}
+	return userIDs, nil
+}
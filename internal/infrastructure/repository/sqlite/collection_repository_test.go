@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewCollectionRepository(db)
+	require.NoError(t, err)
+	phraseRepo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	created, err := repo.Create(ctx, &entity.Collection{UserID: 1, Name: "Greetings", Language: "en-US", Tags: []string{"news", "short"}},
+		[]string{"Hello", "Good morning", "Goodbye"})
+	require.NoError(t, err)
+	assert.Equal(t, "Greetings", created.Name)
+	assert.Equal(t, []string{"news", "short"}, created.Tags)
+
+	t.Run("get", func(t *testing.T) {
+		stored, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), stored.UserID)
+		assert.Equal(t, "en-US", stored.Language)
+		assert.Equal(t, []string{"news", "short"}, stored.Tags)
+
+		_, err = repo.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("phrases", func(t *testing.T) {
+		phrases, err := repo.ListPhrases(ctx, created.ID)
+		require.NoError(t, err)
+		require.Len(t, phrases, 3)
+		for i, text := range []string{"Hello", "Good morning", "Goodbye"} {
+			assert.Equal(t, i+1, phrases[i].Position)
+			assert.Equal(t, text, phrases[i].Phrase.Phrase)
+			assert.Equal(t, uint(1), phrases[i].UserID)
+		}
+
+		second, err := repo.GetPhrase(ctx, created.ID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, phrases[1].ID, second.ID)
+		_, err = repo.GetPhrase(ctx, created.ID, 4)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("assign", func(t *testing.T) {
+		phrases, err := repo.ListPhrases(ctx, created.ID)
+		require.NoError(t, err)
+		shared, err := phraseRepo.IsSharedWith(ctx, phrases[0].ID, 2)
+		require.NoError(t, err)
+		assert.False(t, shared)
+
+		require.NoError(t, repo.Assign(ctx, created.ID, 2))
+		// Assigning twice is a no-op.
+		require.NoError(t, repo.Assign(ctx, created.ID, 2))
+		require.NoError(t, repo.Assign(ctx, created.ID, 3))
+
+		assignees, err := repo.ListAssignees(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{2, 3}, assignees)
+		assigned, err := repo.ListAssigned(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, assigned, 1)
+		assert.Equal(t, created.ID, assigned[0].ID)
+		shared, err = phraseRepo.IsSharedWith(ctx, phrases[0].ID, 2)
+		require.NoError(t, err)
+		assert.True(t, shared)
+
+		require.NoError(t, repo.Unassign(ctx, created.ID, 2))
+		assert.ErrorIs(t, repo.Unassign(ctx, created.ID, 2), repository.ErrNotFound)
+		shared, err = phraseRepo.IsSharedWith(ctx, phrases[0].ID, 2)
+		require.NoError(t, err)
+		assert.False(t, shared)
+	})
+
+	t.Run("list by user", func(t *testing.T) {
+		_, err := repo.Create(ctx, &entity.Collection{UserID: 5, Name: "Other"}, []string{"One"})
+		require.NoError(t, err)
+		owned, err := repo.ListByUserID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, owned, 1)
+		assert.Equal(t, created.ID, owned[0].ID)
+	})
+
+	t.Run("deleted phrase leaves a gap", func(t *testing.T) {
+		first, err := repo.GetPhrase(ctx, created.ID, 1)
+		require.NoError(t, err)
+		require.NoError(t, phraseRepo.Delete(ctx, first.ID))
+
+		phrases, err := repo.ListPhrases(ctx, created.ID)
+		require.NoError(t, err)
+		require.Len(t, phrases, 2)
+		assert.Equal(t, 2, phrases[0].Position)
+		_, err = repo.GetPhrase(ctx, created.ID, 1)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestUserRepository_DeleteCollections(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewCollectionRepository(db)
+	require.NoError(t, err)
+	userRepo, err := NewUserRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	owner, err := userRepo.Create(ctx, &entity.User{Name: "owner", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	speaker, err := userRepo.Create(ctx, &entity.User{Name: "speaker", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	owned, err := repo.Create(ctx, &entity.Collection{UserID: owner.ID, Name: "Owned"}, []string{"One"})
+	require.NoError(t, err)
+	other, err := repo.Create(ctx, &entity.Collection{UserID: speaker.ID, Name: "Other"}, []string{"Two"})
+	require.NoError(t, err)
+	require.NoError(t, repo.Assign(ctx, owned.ID, speaker.ID))
+	require.NoError(t, repo.Assign(ctx, other.ID, owner.ID))
+
+	require.NoError(t, userRepo.Delete(ctx, owner.ID))
+
+	_, err = repo.GetByID(ctx, owned.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assigned, err := repo.ListAssigned(ctx, speaker.ID)
+	require.NoError(t, err)
+	assert.Empty(t, assigned)
+	assignees, err := repo.ListAssignees(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Empty(t, assignees)
+	phrases, err := repo.ListPhrases(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Len(t, phrases, 1)
+}
@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const compilationColumns = `id, user_id, phrase_ids, format, gap, crossfade, status, storage_path, manifest_path, error, created_at, updated_at`
+
+// compilationRow stores the phrase IDs of a compilation as a JSON array.
+type compilationRow struct {
+	entity.Compilation
+	PhraseIDs string `db:"phrase_ids"`
+}
+
+func (row *compilationRow) toEntity() (*entity.Compilation, error) {
+	compilation := row.Compilation
+	if err := json.Unmarshal([]byte(row.PhraseIDs), &compilation.PhraseIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode phrase ids of compilation %d: %w", row.ID, err)
+	}
+	return &compilation, nil
+}
+
+type CompilationRepository struct {
+	db *sqlx.DB
+}
+
+func NewCompilationRepository(db *sqlx.DB) (*CompilationRepository, error) {
+	return &CompilationRepository{db: db}, nil
+}
+
+func (r *CompilationRepository) Create(ctx context.Context, compilation *entity.Compilation) (*entity.Compilation, error) {
+	phraseIDs, err := json.Marshal(compilation.PhraseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode phrase ids: %w", err)
+	}
+	query := `
+	INSERT INTO compilations (user_id, phrase_ids, format, gap, crossfade, status, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING ` + compilationColumns
+	now := time.Now().UTC()
+	var row compilationRow
+	err = r.db.GetContext(ctx, &row, query,
+		compilation.UserID,
+		string(phraseIDs),
+		compilation.Format,
+		compilation.Gap,
+		compilation.Crossfade,
+		compilation.Status,
+		now,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compilation: %w", err)
+	}
+	return row.toEntity()
+}
+
+func (r *CompilationRepository) GetByID(ctx context.Context, id uint) (*entity.Compilation, error) {
+	query := `SELECT ` + compilationColumns + ` FROM compilations WHERE id = ?`
+	var row compilationRow
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get compilation %d: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get compilation: %w", err)
+	}
+	return row.toEntity()
+}
+
+// ListByUserID retrieves the compilations of a user, oldest first.
+func (r *CompilationRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Compilation, error) {
+	query := `SELECT ` + compilationColumns + ` FROM compilations WHERE user_id = ? ORDER BY id`
+	var rows []compilationRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list compilations of user %d: %w", userID, err)
+	}
+	compilations := make([]*entity.Compilation, 0, len(rows))
+	for _, row := range rows {
+		compilation, err := row.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		compilations = append(compilations, compilation)
+	}
+	return compilations, nil
+}
+
+// Update saves the processing state of a compilation; its inputs are immutable.
+func (r *CompilationRepository) Update(ctx context.Context, compilation *entity.Compilation) error {
+	compilation.UpdatedAt = time.Now().UTC()
+	query := `UPDATE compilations SET status = $1, storage_path = $2, manifest_path = $3, error = $4, updated_at = $5 WHERE id = $6`
+	result, err := r.db.ExecContext(ctx, query,
+		compilation.Status,
+		compilation.StoragePath,
+		compilation.ManifestPath,
+		compilation.Error,
+		compilation.UpdatedAt,
+		compilation.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update compilation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update compilation %d: %w", compilation.ID, repository.ErrNotFound)
+	}
+	return nil
+}
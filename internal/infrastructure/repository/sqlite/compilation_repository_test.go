@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilationRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewCompilationRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &entity.Compilation{
+		UserID:    1,
+		PhraseIDs: []uint{3, 1, 2},
+		Format:    "mp3",
+		Gap:       0.5,
+		Status:    entity.CompilationStatusPending,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, []uint{3, 1, 2}, created.PhraseIDs)
+	assert.Equal(t, 0.5, created.Gap)
+	assert.Equal(t, entity.CompilationStatusPending, created.Status)
+
+	t.Run("update", func(t *testing.T) {
+		created.Status = entity.CompilationStatusCompleted
+		created.StoragePath = "audio/compilations/1.mp3"
+		created.ManifestPath = "audio/compilations/1.json"
+		require.NoError(t, repo.Update(ctx, created))
+
+		stored, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.CompilationStatusCompleted, stored.Status)
+		assert.Equal(t, "audio/compilations/1.mp3", stored.StoragePath)
+		assert.Equal(t, "audio/compilations/1.json", stored.ManifestPath)
+		assert.Equal(t, []uint{3, 1, 2}, stored.PhraseIDs)
+	})
+
+	t.Run("list by user", func(t *testing.T) {
+		compilations, err := repo.ListByUserID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, compilations, 1)
+		assert.Equal(t, created.ID, compilations[0].ID)
+		assert.Equal(t, []uint{3, 1, 2}, compilations[0].PhraseIDs)
+	})
+
+	t.Run("get unknown compilation", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("update unknown compilation", func(t *testing.T) {
+		err := repo.Update(ctx, &entity.Compilation{ID: 999})
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
@@ -2,12 +2,88 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/jmoiron/sqlx"
 )
 
+const phraseColumns = `id, user_id, phrase, created_at, updated_at, reference_path, revision, language, tags`
+
+// qualifiedPhraseColumns are phraseColumns for queries joining other tables.
+const qualifiedPhraseColumns = `phrases.id, phrases.user_id, phrases.phrase, phrases.created_at, phrases.updated_at,
+	phrases.reference_path, phrases.revision, phrases.language, phrases.tags`
+
+// phraseRow stores the tags of a phrase as a JSON array.
+type phraseRow struct {
+	entity.Phrase
+	Tags string `db:"tags"`
+}
+
+func (row *phraseRow) toEntity() (*entity.Phrase, error) {
+	phrase := row.Phrase
+	if err := json.Unmarshal([]byte(row.Tags), &phrase.Tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags of phrase %d: %w", row.ID, err)
+	}
+	return &phrase, nil
+}
+
+func phrasesFromRows(rows []phraseRow) ([]*entity.Phrase, error) {
+	phrases := make([]*entity.Phrase, 0, len(rows))
+	for i := range rows {
+		phrase, err := rows[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		phrases = append(phrases, phrase)
+	}
+	return phrases, nil
+}
+
+// encodeTags encodes tags as a JSON array, empty rather than null when there
+// are none.
+func encodeTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	encoded, err := json.Marshal(tags)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tags: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// getPhrase runs a query selecting phraseColumns of at most one phrase, on
+// the database or in a transaction.
+func getPhrase(ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) (*entity.Phrase, error) {
+	var row phraseRow
+	if err := sqlx.GetContext(ctx, q, &row, query, args...); err != nil {
+		return nil, err
+	}
+	return row.toEntity()
+}
+
+func insertPhrase(ctx context.Context, q sqlx.QueryerContext, phrase *entity.Phrase) (*entity.Phrase, error) {
+	tags, err := encodeTags(phrase.Tags)
+	if err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO phrases (user_id, phrase, created_at, updated_at, reference_path, language, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + phraseColumns
+	created, err := getPhrase(ctx, q, query, phrase.UserID, phrase.Phrase, phrase.CreatedAt, phrase.UpdatedAt, phrase.ReferencePath,
+		phrase.Language, tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create phrase: %w", err)
+	}
+	return created, nil
+}
+
 type PhraseRepository struct {
 	db *sqlx.DB
 }
@@ -17,21 +93,322 @@ func NewPhraseRepository(db *sqlx.DB) (*PhraseRepository, error) {
 }
 
 func (r *PhraseRepository) Create(ctx context.Context, phrase *entity.Phrase) (*entity.Phrase, error) {
-	query := `INSERT INTO phrases (phrase, created_at, updated_at) VALUES ($1, $2, $3) RETURNING id, phrase, created_at, updated_at`
-	var createdPhrase entity.Phrase
-	err := r.db.GetContext(ctx, &createdPhrase, query, phrase.Phrase, phrase.CreatedAt, phrase.UpdatedAt)
+	return insertPhrase(ctx, r.db, phrase)
+}
+
+func (r *PhraseRepository) GetByID(ctx context.Context, id uint) (*entity.Phrase, error) {
+	phrase, err := getPhrase(ctx, r.db, `SELECT `+phraseColumns+` FROM phrases WHERE id = ?`, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create phrase: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get phrase %d: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get phrase: %w", err)
 	}
-	return &createdPhrase, nil
+	return phrase, nil
 }
 
-func (r *PhraseRepository) GetByID(ctx context.Context, id uint) (*entity.Phrase, error) {
-	query := `SELECT id, phrase, created_at, updated_at FROM phrases WHERE id = ?`
-	var phrase entity.Phrase
-	err := r.db.GetContext(ctx, &phrase, query, id)
+// ListByUserID retrieves the phrases a user owns, oldest first.
+func (r *PhraseRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Phrase, error) {
+	query := `SELECT ` + phraseColumns + ` FROM phrases WHERE user_id = ? ORDER BY id`
+	var rows []phraseRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list phrases of user %d: %w", userID, err)
+	}
+	return phrasesFromRows(rows)
+}
+
+func (r *PhraseRepository) List(ctx context.Context, filter repository.PhraseFilter) ([]*entity.Phrase, int, error) {
+	conditions, args := []string{`user_id = ?`}, []interface{}{filter.UserID}
+	if filter.HasAudio != nil {
+		exists := `EXISTS (SELECT 1 FROM audios WHERE audios.phrase_id = phrases.id)`
+		if !*filter.HasAudio {
+			exists = `NOT ` + exists
+		}
+		conditions = append(conditions, exists)
+	}
+	if filter.AudioStatus != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM audios WHERE audios.phrase_id = phrases.id AND audios.status = ?)`)
+		args = append(args, filter.AudioStatus)
+	}
+	where := ` WHERE ` + strings.Join(conditions, ` AND `)
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM phrases`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count phrases: %w", err)
+	}
+
+	query := `SELECT ` + phraseColumns + ` FROM phrases` + where + ` ORDER BY id LIMIT ? OFFSET ?`
+	var rows []phraseRow
+	if err := r.db.SelectContext(ctx, &rows, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list phrases: %w", err)
+	}
+	phrases, err := phrasesFromRows(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return phrases, total, nil
+}
+
+func (r *PhraseRepository) Update(ctx context.Context, phrase *entity.Phrase) error {
+	tags, err := encodeTags(phrase.Tags)
+	if err != nil {
+		return err
+	}
+	phrase.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, `UPDATE phrases SET phrase = ?, reference_path = ?, language = ?, tags = ?, updated_at = ? WHERE id = ?`,
+		phrase.Phrase, phrase.ReferencePath, phrase.Language, tags, phrase.UpdatedAt, phrase.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update phrase: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update phrase %d: %w", phrase.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *PhraseRepository) Claim(ctx context.Context, phraseID, userID uint) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE phrases SET user_id = ?, updated_at = ? WHERE id = ? AND user_id = 0`,
+		userID, time.Now(), phraseID)
+	if err != nil {
+		return fmt.Errorf("failed to claim phrase: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("phrase %d without an owner: %w", phraseID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *PhraseRepository) Share(ctx context.Context, phraseID, userID uint) error {
+	_, err := r.db.ExecContext(ctx, `INSERT OR IGNORE INTO phrase_shares (phrase_id, user_id, created_at) VALUES (?, ?, ?)`,
+		phraseID, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to share phrase: %v", err)
+	}
+	return nil
+}
+
+func (r *PhraseRepository) Unshare(ctx context.Context, phraseID, userID uint) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM phrase_shares WHERE phrase_id = ? AND user_id = ?`, phraseID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unshare phrase: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("phrase %d is not shared with user %d: %w", phraseID, userID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *PhraseRepository) IsSharedWith(ctx context.Context, phraseID, userID uint) (bool, error) {
+	var shared bool
+	query := `SELECT EXISTS (SELECT 1 FROM phrase_shares WHERE phrase_id = ? AND user_id = ?)
+		OR EXISTS (SELECT 1 FROM collection_phrases JOIN collection_assignments USING (collection_id)
+			WHERE collection_phrases.phrase_id = ? AND collection_assignments.user_id = ?)`
+	err := r.db.GetContext(ctx, &shared, query, phraseID, userID, phraseID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check phrase share: %v", err)
+	}
+	return shared, nil
+}
+
+// recordablePhrases selects the IDs of the phrases a user may record: those
+// they own, and those shared with them or in a collection assigned to them.
+// Every placeholder is bound to the user ID.
+const recordablePhrases = `SELECT id FROM phrases WHERE user_id = ?
+	UNION SELECT phrase_id FROM phrase_shares WHERE user_id = ?
+	UNION SELECT collection_phrases.phrase_id FROM collection_phrases
+		JOIN collection_assignments USING (collection_id) WHERE collection_assignments.user_id = ?`
+
+// nextPhraseOrder is the ORDER BY clause of each next phrase strategy, over
+// phrases joined with the take of the user, if any.
+var nextPhraseOrder = map[repository.NextPhraseStrategy]string{
+	repository.NextPhraseInOrder:     `phrases.id`,
+	repository.NextPhraseRandom:      `RANDOM()`,
+	repository.NextPhraseFailedFirst: `audios.id IS NULL, phrases.id`,
+}
+
+func (r *PhraseRepository) NextToRecord(ctx context.Context, userID uint, strategy repository.NextPhraseStrategy) (*entity.Phrase, error) {
+	order, ok := nextPhraseOrder[strategy]
+	if !ok {
+		return nil, fmt.Errorf("unknown next phrase strategy %q", strategy)
+	}
+	// A phrase is left to record when the user has no take of it or one an
+	// upload would replace: a failed take, or a completed one that is stale
+	// or was sent back by a reviewer.
+	rerecord := strings.TrimSuffix(strings.Repeat("?, ", len(entity.RerecordStatuses)), ", ")
+	query := `SELECT ` + qualifiedPhraseColumns + ` FROM phrases
+		LEFT JOIN audios ON audios.phrase_id = phrases.id AND audios.user_id = ?
+		LEFT JOIN audio_reviews ON audio_reviews.audio_id = audios.id
+		WHERE phrases.id IN (` + recordablePhrases + `)
+		AND (audios.id IS NULL OR audios.status = ?
+			OR (audios.status = ? AND (audios.stale OR audio_reviews.status IN (` + rerecord + `))))
+		ORDER BY ` + order + ` LIMIT 1`
+	args := []interface{}{userID, userID, userID, userID, entity.AudioStatusFailed, entity.AudioStatusCompleted}
+	for _, status := range entity.RerecordStatuses {
+		args = append(args, status)
+	}
+	phrase, err := getPhrase(ctx, r.db, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no phrase left to record for user %d: %w", userID, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get next phrase: %v", err)
+	}
+	return phrase, nil
+}
+
+func (r *PhraseRepository) Revise(ctx context.Context, id uint, text string) (*entity.Phrase, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	phrase, err := getPhrase(ctx, tx, `SELECT `+phraseColumns+` FROM phrases WHERE id = ?`, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get phrase %d: %w", id, repository.ErrNotFound)
+		}
 		return nil, fmt.Errorf("failed to get phrase: %w", err)
 	}
-	return &phrase, nil
+	if err := revise(ctx, tx, phrase, text); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return phrase, nil
+}
+
+// revise replaces the text of phrase within tx, keeping the old one as a
+// revision and flagging the takes of the phrase as stale.
+func revise(ctx context.Context, tx *sqlx.Tx, phrase *entity.Phrase, text string) error {
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `INSERT INTO phrase_revisions (phrase_id, revision, phrase, replaced_at) VALUES (?, ?, ?, ?)`,
+		phrase.ID, phrase.Revision, phrase.Phrase, now); err != nil {
+		return fmt.Errorf("failed to store phrase revision: %w", err)
+	}
+	phrase.Phrase = text
+	phrase.Revision++
+	phrase.UpdatedAt = now
+	if _, err := tx.ExecContext(ctx, `UPDATE phrases SET phrase = ?, revision = ?, updated_at = ? WHERE id = ?`,
+		phrase.Phrase, phrase.Revision, phrase.UpdatedAt, phrase.ID); err != nil {
+		return fmt.Errorf("failed to update phrase: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE audios SET stale = 1 WHERE phrase_id = ?`, phrase.ID); err != nil {
+		return fmt.Errorf("failed to flag stale audio: %w", err)
+	}
+	return nil
+}
+
+func (r *PhraseRepository) Import(ctx context.Context, created, changed []*entity.Phrase) ([]*entity.Phrase, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stored := make([]*entity.Phrase, 0, len(created))
+	for _, phrase := range created {
+		phrase, err := insertPhrase(ctx, tx, phrase)
+		if err != nil {
+			return nil, err
+		}
+		stored = append(stored, phrase)
+	}
+
+	for _, phrase := range changed {
+		current, err := getPhrase(ctx, tx, `SELECT `+phraseColumns+` FROM phrases WHERE id = ?`, phrase.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("failed to get phrase %d: %w", phrase.ID, repository.ErrNotFound)
+			}
+			return nil, fmt.Errorf("failed to get phrase: %w", err)
+		}
+		if current.Phrase != phrase.Phrase {
+			if err := revise(ctx, tx, current, phrase.Phrase); err != nil {
+				return nil, err
+			}
+		}
+		tags, err := encodeTags(phrase.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE phrases SET language = ?, tags = ?, updated_at = ? WHERE id = ?`,
+			phrase.Language, tags, time.Now(), phrase.ID); err != nil {
+			return nil, fmt.Errorf("failed to update phrase: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return stored, nil
+}
+
+// ListRevisions retrieves the earlier texts of a phrase, oldest first.
+func (r *PhraseRepository) ListRevisions(ctx context.Context, phraseID uint) ([]*entity.PhraseRevision, error) {
+	query := `SELECT phrase_id, revision, phrase, replaced_at FROM phrase_revisions WHERE phrase_id = ? ORDER BY revision`
+	revisions := []*entity.PhraseRevision{}
+	if err := r.db.SelectContext(ctx, &revisions, query, phraseID); err != nil {
+		return nil, fmt.Errorf("failed to list revisions of phrase %d: %w", phraseID, err)
+	}
+	return revisions, nil
+}
+
+// phraseCascade deletes, in order, the rows that go with a phrase. Every
+// placeholder is bound to the phrase ID.
+var phraseCascade = []string{
+	`DELETE FROM share_links WHERE audio_id IN (SELECT id FROM audios WHERE phrase_id = ?)`,
+	`DELETE FROM audio_analysis WHERE audio_id IN (SELECT id FROM audios WHERE phrase_id = ?)`,
+	`DELETE FROM audio_reviews WHERE audio_id IN (SELECT id FROM audios WHERE phrase_id = ?)`,
+	`DELETE FROM annotations WHERE audio_id IN (SELECT id FROM audios WHERE phrase_id = ?)`,
+	`DELETE FROM audios WHERE phrase_id = ?`,
+	`DELETE FROM uploads WHERE phrase_id = ?`,
+	`DELETE FROM phrase_shares WHERE phrase_id = ?`,
+	`DELETE FROM phrase_revisions WHERE phrase_id = ?`,
+	`DELETE FROM collection_phrases WHERE phrase_id = ?`,
+}
+
+func (r *PhraseRepository) Delete(ctx context.Context, id uint) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM phrases WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete phrase: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to delete phrase %d: %w", id, repository.ErrNotFound)
+	}
+
+	for _, stmt := range phraseCascade {
+		if _, err := tx.ExecContext(ctx, stmt, id); err != nil {
+			return fmt.Errorf("failed to delete rows of phrase %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,374 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhraseRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &entity.Phrase{UserID: 7, Phrase: "hello world", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), created.UserID)
+	assert.Empty(t, created.ReferencePath)
+
+	t.Run("update reference", func(t *testing.T) {
+		created.ReferencePath = "audio/references/1.wav"
+		require.NoError(t, repo.Update(ctx, created))
+
+		stored, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", stored.Phrase)
+		assert.Equal(t, uint(7), stored.UserID)
+		assert.Equal(t, "audio/references/1.wav", stored.ReferencePath)
+	})
+
+	t.Run("share", func(t *testing.T) {
+		shared, err := repo.IsSharedWith(ctx, created.ID, 8)
+		require.NoError(t, err)
+		assert.False(t, shared)
+
+		require.NoError(t, repo.Share(ctx, created.ID, 8))
+		// Sharing twice is a no-op.
+		require.NoError(t, repo.Share(ctx, created.ID, 8))
+		shared, err = repo.IsSharedWith(ctx, created.ID, 8)
+		require.NoError(t, err)
+		assert.True(t, shared)
+
+		require.NoError(t, repo.Unshare(ctx, created.ID, 8))
+		shared, err = repo.IsSharedWith(ctx, created.ID, 8)
+		require.NoError(t, err)
+		assert.False(t, shared)
+		assert.ErrorIs(t, repo.Unshare(ctx, created.ID, 8), repository.ErrNotFound)
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		unowned, err := repo.Create(ctx, &entity.Phrase{Phrase: "legacy", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Claim(ctx, unowned.ID, 8))
+		stored, err := repo.GetByID(ctx, unowned.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(8), stored.UserID)
+
+		// Owned phrases cannot be claimed.
+		assert.ErrorIs(t, repo.Claim(ctx, unowned.ID, 9), repository.ErrNotFound)
+		assert.ErrorIs(t, repo.Claim(ctx, created.ID, 9), repository.ErrNotFound)
+	})
+
+	t.Run("list by user", func(t *testing.T) {
+		other, err := repo.Create(ctx, &entity.Phrase{UserID: 9, Phrase: "goodbye", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+
+		phrases, err := repo.ListByUserID(ctx, 9)
+		require.NoError(t, err)
+		require.Len(t, phrases, 1)
+		assert.Equal(t, other.ID, phrases[0].ID)
+	})
+
+	t.Run("unknown phrase", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.ErrorIs(t, repo.Update(ctx, &entity.Phrase{ID: 999}), repository.ErrNotFound)
+		_, err = repo.Revise(ctx, 999, "text")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.ErrorIs(t, repo.Delete(ctx, 999), repository.ErrNotFound)
+	})
+}
+
+func TestPhraseRepository_List(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+	audioRepo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var ids []uint
+	for _, text := range []string{"one", "two", "three"} {
+		phrase, err := repo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: text, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+		ids = append(ids, phrase.ID)
+	}
+	_, err = repo.Create(ctx, &entity.Phrase{UserID: 2, Phrase: "other", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	for i, status := range []entity.AudioStatus{entity.AudioStatusCompleted, entity.AudioStatusFailed} {
+		_, err = audioRepo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: status, UserID: 1, PhraseID: ids[i], CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name          string
+		filter        repository.PhraseFilter
+		expectedIDs   []uint
+		expectedTotal int
+	}{
+		{"all", repository.PhraseFilter{UserID: 1, Limit: 10}, ids, 3},
+		{"page", repository.PhraseFilter{UserID: 1, Limit: 1, Offset: 1}, ids[1:2], 3},
+		{"with audio", repository.PhraseFilter{UserID: 1, HasAudio: &yes, Limit: 10}, ids[:2], 2},
+		{"without audio", repository.PhraseFilter{UserID: 1, HasAudio: &no, Limit: 10}, ids[2:], 1},
+		{"audio status", repository.PhraseFilter{UserID: 1, AudioStatus: entity.AudioStatusFailed, Limit: 10}, ids[1:2], 1},
+		{"unknown user", repository.PhraseFilter{UserID: 9, Limit: 10}, []uint{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phrases, total, err := repo.List(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTotal, total)
+			gotIDs := []uint{}
+			for _, phrase := range phrases {
+				gotIDs = append(gotIDs, phrase.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, gotIDs)
+		})
+	}
+}
+
+func TestPhraseRepository_Revise(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+	audioRepo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	phrase, err := repo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: "helo", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	take, err := audioRepo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: entity.AudioStatusCompleted, UserID: 1, PhraseID: phrase.ID, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.False(t, take.Stale)
+
+	revised, err := repo.Revise(ctx, phrase.ID, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", revised.Phrase)
+	assert.Equal(t, 1, revised.Revision)
+	_, err = repo.Revise(ctx, phrase.ID, "hello there")
+	require.NoError(t, err)
+
+	stored, err := repo.GetByID(ctx, phrase.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hello there", stored.Phrase)
+	assert.Equal(t, 2, stored.Revision)
+
+	revisions, err := repo.ListRevisions(ctx, phrase.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 0, revisions[0].Revision)
+	assert.Equal(t, "helo", revisions[0].Phrase)
+	assert.Equal(t, 1, revisions[1].Revision)
+	assert.Equal(t, "hello", revisions[1].Phrase)
+
+	take, err = audioRepo.GetByID(ctx, take.ID)
+	require.NoError(t, err)
+	assert.True(t, take.Stale)
+}
+
+func TestPhraseRepository_Delete(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+	audioRepo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	phrase, err := repo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: "gone", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	kept, err := repo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: "kept", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	for _, phraseID := range []uint{phrase.ID, kept.ID} {
+		_, err = audioRepo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: entity.AudioStatusCompleted, UserID: 2, PhraseID: phraseID, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.Share(ctx, phrase.ID, 2))
+	_, err = repo.Revise(ctx, phrase.ID, "gone soon")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Delete(ctx, phrase.ID))
+
+	_, err = repo.GetByID(ctx, phrase.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	takes, err := audioRepo.ListByUserID(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, takes, 1)
+	assert.Equal(t, kept.ID, takes[0].PhraseID)
+	shared, err := repo.IsSharedWith(ctx, phrase.ID, 2)
+	require.NoError(t, err)
+	assert.False(t, shared)
+	revisions, err := repo.ListRevisions(ctx, phrase.ID)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
+
+func TestPhraseRepository_NextToRecord(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+	audioRepo, err := NewAudioRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var ids []uint
+	for _, text := range []string{"pending", "completed", "untaken", "failed"} {
+		phrase, err := repo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: text, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+		ids = append(ids, phrase.ID)
+	}
+	for i, status := range map[int]entity.AudioStatus{0: entity.AudioStatusPending, 1: entity.AudioStatusCompleted, 3: entity.AudioStatusFailed} {
+		_, err = audioRepo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: status, UserID: 1, PhraseID: ids[i], CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		require.NoError(t, err)
+	}
+	shared, err := repo.Create(ctx, &entity.Phrase{UserID: 2, Phrase: "shared", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, repo.Share(ctx, shared.ID, 1))
+
+	next, err := repo.NextToRecord(ctx, 1, repository.NextPhraseInOrder)
+	require.NoError(t, err)
+	assert.Equal(t, ids[2], next.ID)
+
+	next, err = repo.NextToRecord(ctx, 1, repository.NextPhraseFailedFirst)
+	require.NoError(t, err)
+	assert.Equal(t, ids[3], next.ID)
+
+	next, err = repo.NextToRecord(ctx, 1, repository.NextPhraseRandom)
+	require.NoError(t, err)
+	assert.Contains(t, []uint{ids[2], ids[3], shared.ID}, next.ID)
+
+	_, err = repo.NextToRecord(ctx, 3, repository.NextPhraseInOrder)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = repo.NextToRecord(ctx, 1, "alphabetical")
+	assert.Error(t, err)
+}
+
+func TestPhraseRepository_NextToRecordTakeStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   entity.AudioStatus
+		stale    bool
+		review   entity.ReviewStatus
+		expected bool
+	}{
+		{name: "pending", status: entity.AudioStatusPending},
+		{name: "converting", status: entity.AudioStatusConverting},
+		{name: "failed", status: entity.AudioStatusFailed, expected: true},
+		{name: "completed", status: entity.AudioStatusCompleted},
+		{name: "stale", status: entity.AudioStatusCompleted, stale: true, expected: true},
+		{name: "approved", status: entity.AudioStatusCompleted, review: entity.ReviewStatusApproved},
+		{name: "rejected", status: entity.AudioStatusCompleted, review: entity.ReviewStatusRejected, expected: true},
+		{name: "needs rerecord", status: entity.AudioStatusCompleted, review: entity.ReviewStatusNeedsRerecord, expected: true},
+		// Takes still being converted are never replaced, whatever their flags.
+		{name: "stale pending", status: entity.AudioStatusPending, stale: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := database.InitDB(":memory:")
+			require.NoError(t, err)
+			defer db.Close()
+			repo, err := NewPhraseRepository(db)
+			require.NoError(t, err)
+			audioRepo, err := NewAudioRepository(db)
+			require.NoError(t, err)
+			reviewRepo, err := NewAudioReviewRepository(db)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			phrase, err := repo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: "hello", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+			require.NoError(t, err)
+			take, err := audioRepo.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: tt.status, Stale: tt.stale,
+				UserID: 1, PhraseID: phrase.ID, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+			require.NoError(t, err)
+			if tt.review != "" {
+				_, err = reviewRepo.Save(ctx, &entity.AudioReview{AudioID: take.ID, Status: tt.review, ReviewerID: 2})
+				require.NoError(t, err)
+			}
+
+			for _, strategy := range []repository.NextPhraseStrategy{repository.NextPhraseInOrder, repository.NextPhraseRandom, repository.NextPhraseFailedFirst} {
+				next, err := repo.NextToRecord(ctx, 1, strategy)
+				if !tt.expected {
+					assert.ErrorIs(t, err, repository.ErrNotFound, strategy)
+					continue
+				}
+				require.NoError(t, err, strategy)
+				assert.Equal(t, phrase.ID, next.ID, strategy)
+			}
+		})
+	}
+}
+
+func TestPhraseRepository_Import(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	kept, err := repo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: "kept", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.Empty(t, kept.Tags)
+	edited, err := repo.Create(ctx, &entity.Phrase{UserID: 1, Phrase: "helo", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+
+	created, err := repo.Import(ctx,
+		[]*entity.Phrase{{UserID: 1, Phrase: "new", Language: "en", Tags: []string{"short"}, CreatedAt: time.Now(), UpdatedAt: time.Now()}},
+		[]*entity.Phrase{
+			{ID: kept.ID, Phrase: "kept", Language: "fr"},
+			{ID: edited.ID, Phrase: "hello", Tags: []string{"greeting"}},
+		})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, "en", created[0].Language)
+	assert.Equal(t, []string{"short"}, created[0].Tags)
+
+	phrases, err := repo.ListByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, phrases, 3)
+	assert.Equal(t, "fr", phrases[0].Language)
+	assert.Equal(t, 0, phrases[0].Revision)
+	assert.Equal(t, "hello", phrases[1].Phrase)
+	assert.Equal(t, []string{"greeting"}, phrases[1].Tags)
+	assert.Equal(t, 1, phrases[1].Revision)
+	revisions, err := repo.ListRevisions(ctx, edited.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "helo", revisions[0].Phrase)
+
+	// A missing phrase rolls back the whole import
+	_, err = repo.Import(ctx,
+		[]*entity.Phrase{{UserID: 1, Phrase: "rolled back", CreatedAt: time.Now(), UpdatedAt: time.Now()}},
+		[]*entity.Phrase{{ID: 99, Phrase: "missing"}})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	phrases, err = repo.ListByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, phrases, 3)
+}
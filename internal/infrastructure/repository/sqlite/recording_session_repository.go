@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const recordingSessionColumns = `id, user_id, device, client, sample_rate, microphone, location, closed_at, created_at, updated_at`
+
+type RecordingSessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewRecordingSessionRepository(db *sqlx.DB) (*RecordingSessionRepository, error) {
+	return &RecordingSessionRepository{db: db}, nil
+}
+
+func (r *RecordingSessionRepository) Create(ctx context.Context, session *entity.RecordingSession) (*entity.RecordingSession, error) {
+	query := `INSERT INTO recording_sessions (user_id, device, client, sample_rate, microphone, location, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + recordingSessionColumns
+	var created entity.RecordingSession
+	err := r.db.GetContext(ctx, &created, query, session.UserID, session.Device, session.Client, session.SampleRate,
+		session.Microphone, session.Location, session.CreatedAt, session.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording session: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *RecordingSessionRepository) GetByID(ctx context.Context, id uint) (*entity.RecordingSession, error) {
+	query := `SELECT ` + recordingSessionColumns + ` FROM recording_sessions WHERE id = ?`
+	var session entity.RecordingSession
+	if err := r.db.GetContext(ctx, &session, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get recording session %d: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get recording session: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *RecordingSessionRepository) List(ctx context.Context, filter repository.RecordingSessionFilter) ([]*entity.RecordingSession, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM recording_sessions WHERE user_id = ?`, filter.UserID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count recording sessions: %w", err)
+	}
+
+	query := `SELECT ` + recordingSessionColumns + ` FROM recording_sessions WHERE user_id = ? ORDER BY id DESC LIMIT ? OFFSET ?`
+	sessions := []*entity.RecordingSession{}
+	if err := r.db.SelectContext(ctx, &sessions, query, filter.UserID, filter.Limit, filter.Offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to list recording sessions: %w", err)
+	}
+	return sessions, total, nil
+}
+
+func (r *RecordingSessionRepository) Close(ctx context.Context, id uint, closedAt time.Time) (*entity.RecordingSession, error) {
+	query := `UPDATE recording_sessions
+		SET updated_at = CASE WHEN closed_at IS NULL THEN $1 ELSE updated_at END, closed_at = COALESCE(closed_at, $1)
+		WHERE id = $2 RETURNING ` + recordingSessionColumns
+	var session entity.RecordingSession
+	if err := r.db.GetContext(ctx, &session, query, closedAt, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to close recording session %d: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to close recording session: %w", err)
+	}
+	return &session, nil
+}
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordingSessionRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewRecordingSessionRepository(db)
+	require.NoError(t, err)
+	audios, err := NewAudioRepository(db)
+	require.NoError(t, err)
+	reviews, err := NewAudioReviewRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	session, err := repo.Create(ctx, &entity.RecordingSession{
+		UserID:     1,
+		Device:     "Pixel 8",
+		Client:     "Chrome 129",
+		SampleRate: 48000,
+		Microphone: "Built-in",
+		Location:   "studio-a",
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, session.ID)
+	assert.Nil(t, session.ClosedAt)
+
+	stored, err := repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, session, stored)
+
+	_, err = repo.GetByID(ctx, session.ID+100)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	t.Run("list", func(t *testing.T) {
+		later, err := repo.Create(ctx, &entity.RecordingSession{UserID: 1, CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, &entity.RecordingSession{UserID: 2, CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+
+		sessions, total, err := repo.List(ctx, repository.RecordingSessionFilter{UserID: 1, Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, later.ID, sessions[0].ID)
+	})
+
+	t.Run("takes", func(t *testing.T) {
+		store := func(phraseID, sessionID uint, status entity.AudioStatus) *entity.Audio {
+			audio, err := audios.Store(ctx, &entity.Audio{OriginalName: "take.m4a", Status: status, UserID: 1, PhraseID: phraseID,
+				SessionID: sessionID, CreatedAt: now, UpdatedAt: now})
+			require.NoError(t, err)
+			return audio
+		}
+		first := store(1, session.ID, entity.AudioStatusCompleted)
+		second := store(2, session.ID, entity.AudioStatusPending)
+		store(3, 0, entity.AudioStatusCompleted)
+
+		takes, err := audios.ListBySessionID(ctx, session.ID)
+		require.NoError(t, err)
+		require.Len(t, takes, 2)
+		assert.Equal(t, first.ID, takes[0].ID)
+		assert.Equal(t, session.ID, takes[0].SessionID)
+		assert.Equal(t, second.ID, takes[1].ID)
+
+		queue, total, err := reviews.ListUnreviewed(ctx, repository.ReviewQueueFilter{SessionID: session.ID, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, queue, 1)
+		assert.Equal(t, first.ID, queue[0].ID)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		closedAt := now.Add(time.Hour)
+		closed, err := repo.Close(ctx, session.ID, closedAt)
+		require.NoError(t, err)
+		require.NotNil(t, closed.ClosedAt)
+		assert.True(t, closedAt.Equal(*closed.ClosedAt))
+		assert.True(t, closedAt.Equal(closed.UpdatedAt))
+
+		// Closing again keeps the original time
+		again, err := repo.Close(ctx, session.ID, closedAt.Add(time.Hour))
+		require.NoError(t, err)
+		assert.True(t, closedAt.Equal(*again.ClosedAt))
+		assert.True(t, closedAt.Equal(again.UpdatedAt))
+
+		_, err = repo.Close(ctx, session.ID+100, closedAt)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
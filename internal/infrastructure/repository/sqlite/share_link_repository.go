@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type ShareLinkRepository struct {
+	db *sqlx.DB
+}
+
+func NewShareLinkRepository(db *sqlx.DB) (*ShareLinkRepository, error) {
+	return &ShareLinkRepository{db: db}, nil
+}
+
+func (r *ShareLinkRepository) Create(ctx context.Context, link *entity.ShareLink) (*entity.ShareLink, error) {
+	query := `
+	INSERT INTO share_links (nonce, audio_id, format, expires_at, single_use, ip, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, nonce, audio_id, format, expires_at, single_use, ip, used_at, created_at`
+	var created entity.ShareLink
+	err := r.db.GetContext(ctx, &created, query,
+		link.Nonce,
+		link.AudioID,
+		link.Format,
+		link.ExpiresAt.UTC(),
+		link.SingleUse,
+		link.IP,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *ShareLinkRepository) MarkUsed(ctx context.Context, nonce string) (bool, error) {
+	query := `UPDATE share_links SET used_at = $1 WHERE nonce = $2 AND used_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, time.Now().UTC(), nonce)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark share link used: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows == 1, nil
+}
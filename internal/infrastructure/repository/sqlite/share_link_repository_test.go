@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLinkRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewShareLinkRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &entity.ShareLink{
+		Nonce:     "abc",
+		AudioID:   1,
+		Format:    "mp3",
+		ExpiresAt: time.Now().Add(time.Hour),
+		SingleUse: true,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "abc", created.Nonce)
+	assert.True(t, created.SingleUse)
+	assert.Nil(t, created.UsedAt)
+
+	t.Run("first redemption succeeds", func(t *testing.T) {
+		ok, err := repo.MarkUsed(ctx, "abc")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("second redemption fails", func(t *testing.T) {
+		ok, err := repo.MarkUsed(ctx, "abc")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("unknown nonce", func(t *testing.T) {
+		ok, err := repo.MarkUsed(ctx, "unknown")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("duplicate nonce", func(t *testing.T) {
+		_, err := repo.Create(ctx, &entity.ShareLink{Nonce: "abc", AudioID: 2, Format: "wav", ExpiresAt: time.Now()})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const uploadColumns = `id, user_id, phrase_id, filename, length, upload_offset, metadata, audio_id, created_at, updated_at, session_id`
+
+type UploadRepository struct {
+	db *sqlx.DB
+}
+
+func NewUploadRepository(db *sqlx.DB) (*UploadRepository, error) {
+	return &UploadRepository{db: db}, nil
+}
+
+func (r *UploadRepository) Create(ctx context.Context, upload *entity.Upload) (*entity.Upload, error) {
+	query := `
+	INSERT INTO uploads (id, user_id, phrase_id, filename, length, upload_offset, metadata, created_at, updated_at, session_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING ` + uploadColumns
+	now := time.Now().UTC()
+	var created entity.Upload
+	err := r.db.GetContext(ctx, &created, query,
+		upload.ID,
+		upload.UserID,
+		upload.PhraseID,
+		upload.Filename,
+		upload.Length,
+		upload.Offset,
+		upload.Metadata,
+		now,
+		now,
+		upload.SessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *UploadRepository) GetByID(ctx context.Context, id string) (*entity.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE id = ?`
+	var upload entity.Upload
+	if err := r.db.GetContext(ctx, &upload, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get upload %s: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get upload: %w", err)
+	}
+	return &upload, nil
+}
+
+// ListByUserID retrieves the uploads of a user, oldest first.
+func (r *UploadRepository) ListByUserID(ctx context.Context, userID uint) ([]*entity.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE user_id = ? ORDER BY created_at, id`
+	var uploads []*entity.Upload
+	if err := r.db.SelectContext(ctx, &uploads, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list uploads of user %d: %w", userID, err)
+	}
+	return uploads, nil
+}
+
+// ListByPhraseID retrieves the uploads of a phrase by any user, oldest first.
+func (r *UploadRepository) ListByPhraseID(ctx context.Context, phraseID uint) ([]*entity.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE phrase_id = ? ORDER BY created_at, id`
+	var uploads []*entity.Upload
+	if err := r.db.SelectContext(ctx, &uploads, query, phraseID); err != nil {
+		return nil, fmt.Errorf("failed to list uploads of phrase %d: %w", phraseID, err)
+	}
+	return uploads, nil
+}
+
+// ListStale retrieves the uploads without an audio that were last updated
+// before the given time, oldest first.
+func (r *UploadRepository) ListStale(ctx context.Context, before time.Time) ([]*entity.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE audio_id IS NULL AND updated_at < ? ORDER BY updated_at, id`
+	var uploads []*entity.Upload
+	if err := r.db.SelectContext(ctx, &uploads, query, before.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to list stale uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+func (r *UploadRepository) Update(ctx context.Context, upload *entity.Upload) error {
+	upload.UpdatedAt = time.Now().UTC()
+	query := `UPDATE uploads SET upload_offset = $1, audio_id = $2, updated_at = $3 WHERE id = $4`
+	result, err := r.db.ExecContext(ctx, query, upload.Offset, upload.AudioID, upload.UpdatedAt, upload.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update upload: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update upload %s: %w", upload.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *UploadRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM uploads WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete upload: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewUploadRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &entity.Upload{
+		ID:       "01TEST",
+		UserID:   1,
+		PhraseID: 2,
+		Filename: "take.m4a",
+		Length:   100,
+		Metadata: "filename dGFrZS5tNGE=",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "01TEST", created.ID)
+	assert.Equal(t, int64(0), created.Offset)
+	assert.Nil(t, created.AudioID)
+
+	t.Run("update offset and audio", func(t *testing.T) {
+		audioID := uint(5)
+		created.Offset = 100
+		created.AudioID = &audioID
+		require.NoError(t, repo.Update(ctx, created))
+
+		stored, err := repo.GetByID(ctx, "01TEST")
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), stored.Offset)
+		require.NotNil(t, stored.AudioID)
+		assert.Equal(t, uint(5), *stored.AudioID)
+		assert.True(t, stored.Completed())
+	})
+
+	t.Run("update unknown upload", func(t *testing.T) {
+		err := repo.Update(ctx, &entity.Upload{ID: "missing"})
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("list by user", func(t *testing.T) {
+		uploads, err := repo.ListByUserID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, uploads, 1)
+		assert.Equal(t, "01TEST", uploads[0].ID)
+
+		none, err := repo.ListByUserID(ctx, 2)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("list by phrase", func(t *testing.T) {
+		uploads, err := repo.ListByPhraseID(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, uploads, 1)
+		assert.Equal(t, "01TEST", uploads[0].ID)
+
+		none, err := repo.ListByPhraseID(ctx, 4)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("list stale", func(t *testing.T) {
+		_, err := repo.Create(ctx, &entity.Upload{ID: "01IDLE", UserID: 1, PhraseID: 3, Filename: "take.m4a", Length: 100})
+		require.NoError(t, err)
+
+		stale, err := repo.ListStale(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Len(t, stale, 1, "completed uploads are not stale")
+		assert.Equal(t, "01IDLE", stale[0].ID)
+
+		stale, err = repo.ListStale(ctx, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, stale)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, "01TEST"))
+		_, err := repo.GetByID(ctx, "01TEST")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const userDeletionColumns = `id, user_id, status, phrases, audio, error, created_at, updated_at`
+
+type UserDeletionRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserDeletionRepository(db *sqlx.DB) (*UserDeletionRepository, error) {
+	return &UserDeletionRepository{db: db}, nil
+}
+
+func (r *UserDeletionRepository) Create(ctx context.Context, deletion *entity.UserDeletion) (*entity.UserDeletion, error) {
+	query := `INSERT INTO user_deletions (user_id, status, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING ` + userDeletionColumns
+	now := time.Now().UTC()
+	var created entity.UserDeletion
+	if err := r.db.GetContext(ctx, &created, query, deletion.UserID, deletion.Status, now, now); err != nil {
+		return nil, fmt.Errorf("failed to create user deletion: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *UserDeletionRepository) GetByID(ctx context.Context, id uint) (*entity.UserDeletion, error) {
+	query := `SELECT ` + userDeletionColumns + ` FROM user_deletions WHERE id = ?`
+	var deletion entity.UserDeletion
+	if err := r.db.GetContext(ctx, &deletion, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get user deletion %d: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get user deletion: %w", err)
+	}
+	return &deletion, nil
+}
+
+// Update saves the progress of a deletion; the user it removes is immutable.
+func (r *UserDeletionRepository) Update(ctx context.Context, deletion *entity.UserDeletion) error {
+	deletion.UpdatedAt = time.Now().UTC()
+	query := `UPDATE user_deletions SET status = $1, phrases = $2, audio = $3, error = $4, updated_at = $5 WHERE id = $6`
+	result, err := r.db.ExecContext(ctx, query,
+		deletion.Status,
+		deletion.Phrases,
+		deletion.Audio,
+		deletion.Error,
+		deletion.UpdatedAt,
+		deletion.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user deletion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update user deletion %d: %w", deletion.ID, repository.ErrNotFound)
+	}
+	return nil
+}
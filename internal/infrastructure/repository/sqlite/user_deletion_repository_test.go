@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserDeletionRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewUserDeletionRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &entity.UserDeletion{UserID: 4, Status: entity.UserDeletionStatusPending})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, uint(4), created.UserID)
+	assert.Equal(t, entity.UserDeletionStatusPending, created.Status)
+
+	t.Run("update", func(t *testing.T) {
+		created.Status = entity.UserDeletionStatusCompleted
+		created.Phrases, created.Audio = 1, 2
+		require.NoError(t, repo.Update(ctx, created))
+
+		stored, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.UserDeletionStatusCompleted, stored.Status)
+		assert.Equal(t, 1, stored.Phrases)
+		assert.Equal(t, 2, stored.Audio)
+	})
+
+	t.Run("unknown deletion", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.ErrorIs(t, repo.Update(ctx, &entity.UserDeletion{ID: 999}), repository.ErrNotFound)
+	})
+}
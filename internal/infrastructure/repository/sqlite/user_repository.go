@@ -2,12 +2,45 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/jmoiron/sqlx"
 )
 
+const userColumns = `id, name, created_at, updated_at, processing_profile, attributes, deleting`
+
+// userRow stores the speaker attributes of a user as a JSON object.
+type userRow struct {
+	entity.User
+	Attributes string `db:"attributes"`
+}
+
+func (row *userRow) toEntity() (*entity.User, error) {
+	user := row.User
+	if err := json.Unmarshal([]byte(row.Attributes), &user.Attributes); err != nil {
+		return nil, fmt.Errorf("failed to decode attributes of user %d: %w", row.ID, err)
+	}
+	return &user, nil
+}
+
+func encodeSpeakerAttributes(attributes map[string]string) (string, error) {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode speaker attributes: %w", err)
+	}
+	return string(encoded), nil
+}
+
 type UserRepository struct {
 	db *sqlx.DB
 }
@@ -17,21 +50,180 @@ func NewUserRepository(db *sqlx.DB) (*UserRepository, error) {
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *entity.User) (*entity.User, error) {
-	query := `INSERT INTO users (name, created_at, updated_at) VALUES ($1, $2, $3) RETURNING id, name, created_at, updated_at`
-	var createdUser entity.User
-	err := r.db.GetContext(ctx, &createdUser, query, user.Name, user.CreatedAt, user.UpdatedAt)
+	attributes, err := encodeSpeakerAttributes(user.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO users (name, created_at, updated_at, processing_profile, attributes) VALUES ($1, $2, $3, $4, $5) RETURNING ` + userColumns
+	var row userRow
+	err = r.db.GetContext(ctx, &row, query, user.Name, user.CreatedAt, user.UpdatedAt, user.ProcessingProfile, attributes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
-	return &createdUser, nil
+	return row.toEntity()
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id uint) (*entity.User, error) {
-	query := `SELECT id, name, created_at, updated_at FROM users WHERE id = ?`
-	var user entity.User
-	err := r.db.GetContext(ctx, &user, query, id)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+	var row userRow
+	err := r.db.GetContext(ctx, &row, query, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get user %d: %w", id, repository.ErrNotFound)
+		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-	return &user, nil
+	return row.toEntity()
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
+	attributes, err := encodeSpeakerAttributes(user.Attributes)
+	if err != nil {
+		return err
+	}
+	user.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET name = ?, processing_profile = ?, attributes = ?, updated_at = ? WHERE id = ?`,
+		user.Name, user.ProcessingProfile, attributes, user.UpdatedAt, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to update user %d: %w", user.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *UserRepository) MarkDeleting(ctx context.Context, id uint) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET deleting = 1, updated_at = ? WHERE id = ?`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark user as deleting: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to mark user %d as deleting: %w", id, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern with backslashes.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *UserRepository) List(ctx context.Context, filter repository.UserFilter) ([]*entity.User, int, error) {
+	where, args := ``, []interface{}{}
+	if filter.Name != "" {
+		// LIKE is case-insensitive for ASCII in SQLite.
+		where = ` WHERE name LIKE ? ESCAPE '\'`
+		args = append(args, "%"+likeEscaper.Replace(filter.Name)+"%")
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM users`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users` + where + ` ORDER BY id LIMIT ? OFFSET ?`
+	var rows []userRow
+	if err := r.db.SelectContext(ctx, &rows, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	users := make([]*entity.User, 0, len(rows))
+	for i := range rows {
+		user, err := rows[i].toEntity()
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+	return users, total, nil
+}
+
+// AttributeStats groups the users by their value of a speaker attribute, and
+// sums the completed takes each group recorded.
+func (r *UserRepository) AttributeStats(ctx context.Context, attribute string) ([]*entity.SpeakerAttributeStats, error) {
+	query := `SELECT COALESCE(json_extract(users.attributes, ?), '') AS value,
+		COUNT(DISTINCT users.id) AS speakers,
+		COUNT(audios.id) AS recordings,
+		COALESCE(SUM(audios.duration), 0) AS recorded_duration
+		FROM users
+		LEFT JOIN audios ON audios.user_id = users.id AND audios.status = ?
+		GROUP BY value ORDER BY value`
+	stats := []*entity.SpeakerAttributeStats{}
+	path := `$."` + attribute + `"`
+	if err := r.db.SelectContext(ctx, &stats, query, path, entity.AudioStatusCompleted); err != nil {
+		return nil, fmt.Errorf("failed to get stats of speaker attribute %q: %v", attribute, err)
+	}
+	return stats, nil
+}
+
+// userCascade deletes, in order, the rows that go with a user, and keeps the
+// reviews they made without their ID. Phrases of the user that others
+// recorded are first handed to the first of them and shared with the rest,
+// as the phrase owner backfill does, so their takes are kept. Every
+// placeholder is bound to the user ID.
+var userCascade = []string{
+	`INSERT OR IGNORE INTO phrase_shares (phrase_id, user_id, created_at)
+	SELECT DISTINCT audios.phrase_id, audios.user_id, CURRENT_TIMESTAMP FROM audios
+	JOIN phrases ON phrases.id = audios.phrase_id WHERE phrases.user_id = ? AND audios.user_id != ?`,
+	`UPDATE phrases SET user_id = (SELECT user_id FROM audios WHERE phrase_id = phrases.id AND user_id != ? ORDER BY id LIMIT 1)
+	WHERE user_id = ? AND EXISTS (SELECT 1 FROM audios WHERE phrase_id = phrases.id AND user_id != ?)`,
+	`DELETE FROM phrase_shares WHERE EXISTS (SELECT 1 FROM phrases WHERE phrases.id = phrase_shares.phrase_id AND phrases.user_id = phrase_shares.user_id)`,
+	`DELETE FROM share_links WHERE audio_id IN (SELECT id FROM audios WHERE user_id = ? OR phrase_id IN (SELECT id FROM phrases WHERE user_id = ?))`,
+	`DELETE FROM audio_analysis WHERE audio_id IN (SELECT id FROM audios WHERE user_id = ? OR phrase_id IN (SELECT id FROM phrases WHERE user_id = ?))`,
+	`DELETE FROM audio_reviews WHERE audio_id IN (SELECT id FROM audios WHERE user_id = ? OR phrase_id IN (SELECT id FROM phrases WHERE user_id = ?))`,
+	`UPDATE audio_reviews SET reviewer_id = 0 WHERE reviewer_id = ?`,
+	`DELETE FROM annotations WHERE audio_id IN (SELECT id FROM audios WHERE user_id = ? OR phrase_id IN (SELECT id FROM phrases WHERE user_id = ?))`,
+	`DELETE FROM audios WHERE user_id = ? OR phrase_id IN (SELECT id FROM phrases WHERE user_id = ?)`,
+	`DELETE FROM uploads WHERE user_id = ? OR phrase_id IN (SELECT id FROM phrases WHERE user_id = ?)`,
+	`DELETE FROM phrase_shares WHERE user_id = ? OR phrase_id IN (SELECT id FROM phrases WHERE user_id = ?)`,
+	`DELETE FROM phrase_revisions WHERE phrase_id IN (SELECT id FROM phrases WHERE user_id = ?)`,
+	`DELETE FROM compilations WHERE user_id = ?`,
+	`DELETE FROM session_reviews WHERE session_id IN (SELECT id FROM recording_sessions WHERE user_id = ?)`,
+	`UPDATE session_reviews SET reviewer_id = 0 WHERE reviewer_id = ?`,
+	`DELETE FROM recording_sessions WHERE user_id = ?`,
+	`DELETE FROM collection_assignments WHERE user_id = ? OR collection_id IN (SELECT id FROM collections WHERE user_id = ?)`,
+	`DELETE FROM collection_phrases WHERE collection_id IN (SELECT id FROM collections WHERE user_id = ?) OR phrase_id IN (SELECT id FROM phrases WHERE user_id = ?)`,
+	`DELETE FROM collections WHERE user_id = ?`,
+	`DELETE FROM phrases WHERE user_id = ?`,
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id uint) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to delete user %d: %w", id, repository.ErrNotFound)
+	}
+
+	for _, stmt := range userCascade {
+		args := make([]interface{}, strings.Count(stmt, "?"))
+		for i := range args {
+			args[i] = id
+		}
+		if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+			return fmt.Errorf("failed to delete rows of user %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
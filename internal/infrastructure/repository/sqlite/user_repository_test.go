@@ -0,0 +1,263 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewUserRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &entity.User{Name: "alice", CreatedAt: time.Now(), UpdatedAt: time.Now(), ProcessingProfile: "clean"})
+	require.NoError(t, err)
+	assert.Equal(t, "clean", created.ProcessingProfile)
+
+	t.Run("update processing profile", func(t *testing.T) {
+		created.ProcessingProfile = "telephone"
+		require.NoError(t, repo.Update(ctx, created))
+
+		stored, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", stored.Name)
+		assert.Equal(t, "telephone", stored.ProcessingProfile)
+	})
+
+	t.Run("update speaker attributes", func(t *testing.T) {
+		assert.Equal(t, map[string]string{}, created.Attributes)
+		created.Attributes = map[string]string{"gender": "female", "accent": "Scottish"}
+		require.NoError(t, repo.Update(ctx, created))
+
+		stored, err := repo.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"gender": "female", "accent": "Scottish"}, stored.Attributes)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.ErrorIs(t, repo.Update(ctx, &entity.User{ID: 999}), repository.ErrNotFound)
+		assert.ErrorIs(t, repo.MarkDeleting(ctx, 999), repository.ErrNotFound)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		for _, name := range []string{"Bob", "bobby_tables", "carol"} {
+			_, err := repo.Create(ctx, &entity.User{Name: name, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+			require.NoError(t, err)
+		}
+
+		users, total, err := repo.List(ctx, repository.UserFilter{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		require.Len(t, users, 2)
+		assert.Equal(t, "alice", users[0].Name)
+
+		users, total, err = repo.List(ctx, repository.UserFilter{Limit: 2, Offset: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		require.Len(t, users, 2)
+		assert.Equal(t, "bobby_tables", users[0].Name)
+
+		users, total, err = repo.List(ctx, repository.UserFilter{Name: "BOB", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, users, 2)
+
+		// Wildcards in the search are matched literally.
+		users, total, err = repo.List(ctx, repository.UserFilter{Name: "_", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, users, 1)
+		assert.Equal(t, "bobby_tables", users[0].Name)
+	})
+}
+
+func TestUserRepository_AttributeStats(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	users, err := NewUserRepository(db)
+	require.NoError(t, err)
+	audios, err := NewAudioRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+	create := func(attributes map[string]string, takes ...entity.AudioStatus) {
+		user, err := users.Create(ctx, &entity.User{Name: "speaker", Attributes: attributes, CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		for i, status := range takes {
+			_, err := audios.Store(ctx, &entity.Audio{
+				OriginalName: "take.m4a", CurrentFormat: "m4a", Status: status, Duration: 1800,
+				CreatedAt: now, UpdatedAt: now, UserID: user.ID, PhraseID: uint(i + 1),
+			})
+			require.NoError(t, err)
+		}
+	}
+	create(map[string]string{"gender": "female"}, entity.AudioStatusCompleted, entity.AudioStatusCompleted)
+	create(map[string]string{"gender": "female", "accent": "Geordie"}, entity.AudioStatusCompleted, entity.AudioStatusFailed)
+	create(map[string]string{"gender": "male"})
+	create(nil, entity.AudioStatusCompleted)
+
+	stats, err := users.AttributeStats(ctx, "gender")
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.SpeakerAttributeStats{
+		{Value: "", Speakers: 1, Recordings: 1, RecordedDuration: 1800},
+		{Value: "female", Speakers: 2, Recordings: 3, RecordedDuration: 5400},
+		{Value: "male", Speakers: 1},
+	}, stats)
+
+	stats, err = users.AttributeStats(ctx, "accent")
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.SpeakerAttributeStats{
+		{Value: "", Speakers: 3, Recordings: 3, RecordedDuration: 5400},
+		{Value: "Geordie", Speakers: 1, Recordings: 1, RecordedDuration: 1800},
+	}, stats)
+}
+
+func TestUserRepository_Delete(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	users, err := NewUserRepository(db)
+	require.NoError(t, err)
+	phrases, err := NewPhraseRepository(db)
+	require.NoError(t, err)
+	audios, err := NewAudioRepository(db)
+	require.NoError(t, err)
+	reviews, err := NewAudioReviewRepository(db)
+	require.NoError(t, err)
+	sessions, err := NewRecordingSessionRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+	alice, err := users.Create(ctx, &entity.User{Name: "alice", CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	bob, err := users.Create(ctx, &entity.User{Name: "bob", CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	carol, err := users.Create(ctx, &entity.User{Name: "carol", CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+
+	owned, err := phrases.Create(ctx, &entity.Phrase{UserID: alice.ID, Phrase: "alice's", CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	other, err := phrases.Create(ctx, &entity.Phrase{UserID: bob.ID, Phrase: "bob's", CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	unrecorded, err := phrases.Create(ctx, &entity.Phrase{UserID: alice.ID, Phrase: "alice's too", CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	require.NoError(t, phrases.Share(ctx, owned.ID, bob.ID))
+	require.NoError(t, phrases.Share(ctx, other.ID, alice.ID))
+	require.NoError(t, phrases.Share(ctx, unrecorded.ID, carol.ID))
+
+	store := func(userID, phraseID uint) *entity.Audio {
+		audio, err := audios.Store(ctx, &entity.Audio{
+			OriginalName: "take.m4a", CurrentFormat: "m4a", Status: entity.AudioStatusPending,
+			CreatedAt: now, UpdatedAt: now, UserID: userID, PhraseID: phraseID,
+		})
+		require.NoError(t, err)
+		return audio
+	}
+	aliceTake := store(alice.ID, other.ID)
+	bobTakeOfAlice := store(bob.ID, owned.ID)
+	bobTake := store(bob.ID, other.ID)
+	carolTakeOfAlice := store(carol.ID, owned.ID)
+	_, err = db.Exec(`INSERT INTO share_links (nonce, audio_id, format, expires_at, created_at) VALUES ('n', ?, 'wav', ?, ?)`, aliceTake.ID, now, now)
+	require.NoError(t, err)
+	_, err = reviews.Save(ctx, &entity.AudioReview{AudioID: aliceTake.ID, Status: entity.ReviewStatusApproved, ReviewerID: bob.ID})
+	require.NoError(t, err)
+	_, err = reviews.Save(ctx, &entity.AudioReview{AudioID: bobTake.ID, Status: entity.ReviewStatusApproved, ReviewerID: alice.ID})
+	require.NoError(t, err)
+
+	aliceSession, err := sessions.Create(ctx, &entity.RecordingSession{UserID: alice.ID, CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	bobSession, err := sessions.Create(ctx, &entity.RecordingSession{UserID: bob.ID, CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+
+	require.NoError(t, users.Delete(ctx, alice.ID))
+
+	_, err = users.GetByID(ctx, alice.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = phrases.GetByID(ctx, unrecorded.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = audios.GetByID(ctx, aliceTake.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	shared, err := phrases.IsSharedWith(ctx, other.ID, alice.ID)
+	require.NoError(t, err)
+	assert.False(t, shared)
+	var links int
+	require.NoError(t, db.Get(&links, `SELECT COUNT(*) FROM share_links`))
+	assert.Zero(t, links)
+	_, err = reviews.GetByAudioID(ctx, aliceTake.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = sessions.GetByID(ctx, aliceSession.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// Alice's phrase recorded by others goes to the first of them, Bob, and
+	// stays shared with Carol; their takes are kept.
+	handedOver, err := phrases.GetByID(ctx, owned.ID)
+	require.NoError(t, err)
+	assert.Equal(t, bob.ID, handedOver.UserID)
+	for _, id := range []uint{bobTakeOfAlice.ID, carolTakeOfAlice.ID} {
+		_, err = audios.GetByID(ctx, id)
+		assert.NoError(t, err)
+	}
+	shared, err = phrases.IsSharedWith(ctx, owned.ID, carol.ID)
+	require.NoError(t, err)
+	assert.True(t, shared)
+	var ownerShares int
+	require.NoError(t, db.Get(&ownerShares, `SELECT COUNT(*) FROM phrase_shares WHERE phrase_id = ? AND user_id = ?`, owned.ID, bob.ID))
+	assert.Zero(t, ownerShares)
+
+	// Bob's own phrase and take are kept, and so is the review Alice made.
+	_, err = phrases.GetByID(ctx, other.ID)
+	assert.NoError(t, err)
+	_, err = audios.GetByID(ctx, bobTake.ID)
+	assert.NoError(t, err)
+	_, err = sessions.GetByID(ctx, bobSession.ID)
+	assert.NoError(t, err)
+	review, err := reviews.GetByAudioID(ctx, bobTake.ID)
+	require.NoError(t, err)
+	assert.Zero(t, review.ReviewerID)
+
+	assert.ErrorIs(t, users.Delete(ctx, alice.ID), repository.ErrNotFound)
+}
+
+func TestUserRepository_MarkDeleting(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewUserRepository(db)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	user, err := repo.Create(ctx, &entity.User{Name: "alice", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.False(t, user.Deleting)
+
+	require.NoError(t, repo.MarkDeleting(ctx, user.ID))
+	stored, err := repo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Deleting)
+
+	// Editing the user does not clear the mark.
+	require.NoError(t, repo.Update(ctx, stored))
+	stored, err = repo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Deleting)
+}
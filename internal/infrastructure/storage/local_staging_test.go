@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStagingArea(t *testing.T) {
+	staging, err := NewLocalStagingArea(t.TempDir())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	id := "upload-1"
+
+	require.NoError(t, staging.Create(ctx, id))
+
+	t.Run("create twice fails", func(t *testing.T) {
+		assert.Error(t, staging.Create(ctx, id))
+	})
+
+	t.Run("append chunks in order", func(t *testing.T) {
+		n, err := staging.Append(ctx, id, 0, strings.NewReader("hello "))
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), n)
+
+		n, err = staging.Append(ctx, id, 6, strings.NewReader("world"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), n)
+	})
+
+	t.Run("append at wrong offset fails", func(t *testing.T) {
+		_, err := staging.Append(ctx, id, 3, strings.NewReader("x"))
+		assert.Error(t, err)
+	})
+
+	t.Run("truncate discards tail", func(t *testing.T) {
+		_, err := staging.Append(ctx, id, 11, strings.NewReader("!!!"))
+		require.NoError(t, err)
+		require.NoError(t, staging.Truncate(ctx, id, 11))
+	})
+
+	t.Run("open returns assembled content", func(t *testing.T) {
+		reader, err := staging.Open(ctx, id)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(content))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, staging.Delete(ctx, id))
+		_, err := staging.Open(ctx, id)
+		assert.Error(t, err)
+		assert.NoError(t, staging.Delete(ctx, id))
+	})
+}
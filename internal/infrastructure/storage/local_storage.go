@@ -2,10 +2,16 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+
+	"github.com/ardfard/sb-test/internal/domain/storage"
 )
 
 // LocalStorage implements the Storage interface using the local filesystem.
@@ -48,6 +54,9 @@ func (ls *LocalStorage) Download(ctx context.Context, objectName string) (io.Rea
 	filePath := filepath.Join(ls.directory, objectName)
 	file, err := os.Open(filePath)
 	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to open file %s: %w", objectName, storage.ErrObjectNotFound)
+		}
 		return nil, fmt.Errorf("failed to open file: %v", err)
 	}
 	return file, nil
@@ -57,7 +66,33 @@ func (ls *LocalStorage) Download(ctx context.Context, objectName string) (io.Rea
 func (ls *LocalStorage) Delete(ctx context.Context, objectName string) error {
 	filePath := filepath.Join(ls.directory, objectName)
 	if err := os.Remove(filePath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete file %s: %w", objectName, storage.ErrObjectNotFound)
+		}
 		return fmt.Errorf("failed to delete file: %v", err)
 	}
 	return nil
 }
+
+// DeletePrefix deletes the files whose object name starts with prefix.
+func (ls *LocalStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	// Matches can only lie below the directory the prefix ends in.
+	root := filepath.Join(ls.directory, filepath.FromSlash(path.Dir(prefix)))
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(ls.directory, filePath)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(filepath.ToSlash(rel), prefix) {
+			return nil
+		}
+		return os.Remove(filePath)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete files: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockStagingArea is an autogenerated mock type for the StagingArea type
+type MockStagingArea struct {
+	mock.Mock
+}
+
+type MockStagingArea_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockStagingArea) EXPECT() *MockStagingArea_Expecter {
+	return &MockStagingArea_Expecter{mock: &_m.Mock}
+}
+
+// Append provides a mock function with given fields: ctx, id, offset, reader
+func (_m *MockStagingArea) Append(ctx context.Context, id string, offset int64, reader io.Reader) (int64, error) {
+	ret := _m.Called(ctx, id, offset, reader)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Append")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader) (int64, error)); ok {
+		return rf(ctx, id, offset, reader)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader) int64); ok {
+		r0 = rf(ctx, id, offset, reader)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, io.Reader) error); ok {
+		r1 = rf(ctx, id, offset, reader)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStagingArea_Append_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Append'
+type MockStagingArea_Append_Call struct {
+	*mock.Call
+}
+
+// Append is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - offset int64
+//   - reader io.Reader
+func (_e *MockStagingArea_Expecter) Append(ctx interface{}, id interface{}, offset interface{}, reader interface{}) *MockStagingArea_Append_Call {
+	return &MockStagingArea_Append_Call{Call: _e.mock.On("Append", ctx, id, offset, reader)}
+}
+
+func (_c *MockStagingArea_Append_Call) Run(run func(ctx context.Context, id string, offset int64, reader io.Reader)) *MockStagingArea_Append_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(io.Reader))
+	})
+	return _c
+}
+
+func (_c *MockStagingArea_Append_Call) Return(_a0 int64, _a1 error) *MockStagingArea_Append_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStagingArea_Append_Call) RunAndReturn(run func(context.Context, string, int64, io.Reader) (int64, error)) *MockStagingArea_Append_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, id
+func (_m *MockStagingArea) Create(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStagingArea_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockStagingArea_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockStagingArea_Expecter) Create(ctx interface{}, id interface{}) *MockStagingArea_Create_Call {
+	return &MockStagingArea_Create_Call{Call: _e.mock.On("Create", ctx, id)}
+}
+
+func (_c *MockStagingArea_Create_Call) Run(run func(ctx context.Context, id string)) *MockStagingArea_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStagingArea_Create_Call) Return(_a0 error) *MockStagingArea_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStagingArea_Create_Call) RunAndReturn(run func(context.Context, string) error) *MockStagingArea_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockStagingArea) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStagingArea_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockStagingArea_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockStagingArea_Expecter) Delete(ctx interface{}, id interface{}) *MockStagingArea_Delete_Call {
+	return &MockStagingArea_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockStagingArea_Delete_Call) Run(run func(ctx context.Context, id string)) *MockStagingArea_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStagingArea_Delete_Call) Return(_a0 error) *MockStagingArea_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStagingArea_Delete_Call) RunAndReturn(run func(context.Context, string) error) *MockStagingArea_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Open provides a mock function with given fields: ctx, id
+func (_m *MockStagingArea) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (io.ReadCloser, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStagingArea_Open_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Open'
+type MockStagingArea_Open_Call struct {
+	*mock.Call
+}
+
+// Open is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockStagingArea_Expecter) Open(ctx interface{}, id interface{}) *MockStagingArea_Open_Call {
+	return &MockStagingArea_Open_Call{Call: _e.mock.On("Open", ctx, id)}
+}
+
+func (_c *MockStagingArea_Open_Call) Run(run func(ctx context.Context, id string)) *MockStagingArea_Open_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStagingArea_Open_Call) Return(_a0 io.ReadCloser, _a1 error) *MockStagingArea_Open_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStagingArea_Open_Call) RunAndReturn(run func(context.Context, string) (io.ReadCloser, error)) *MockStagingArea_Open_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Truncate provides a mock function with given fields: ctx, id, size
+func (_m *MockStagingArea) Truncate(ctx context.Context, id string, size int64) error {
+	ret := _m.Called(ctx, id, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Truncate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, id, size)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStagingArea_Truncate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Truncate'
+type MockStagingArea_Truncate_Call struct {
+	*mock.Call
+}
+
+// Truncate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - size int64
+func (_e *MockStagingArea_Expecter) Truncate(ctx interface{}, id interface{}, size interface{}) *MockStagingArea_Truncate_Call {
+	return &MockStagingArea_Truncate_Call{Call: _e.mock.On("Truncate", ctx, id, size)}
+}
+
+func (_c *MockStagingArea_Truncate_Call) Run(run func(ctx context.Context, id string, size int64)) *MockStagingArea_Truncate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockStagingArea_Truncate_Call) Return(_a0 error) *MockStagingArea_Truncate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStagingArea_Truncate_Call) RunAndReturn(run func(context.Context, string, int64) error) *MockStagingArea_Truncate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockStagingArea creates a new instance of MockStagingArea. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockStagingArea(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockStagingArea {
+	mock := &MockStagingArea{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
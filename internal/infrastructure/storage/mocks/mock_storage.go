@@ -69,6 +69,53 @@ func (_c *MockStorage_Delete_Call) RunAndReturn(run func(context.Context, string
 	return _c
 }
 
+// DeletePrefix provides a mock function with given fields: ctx, prefix
+func (_m *MockStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePrefix")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStorage_DeletePrefix_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePrefix'
+type MockStorage_DeletePrefix_Call struct {
+	*mock.Call
+}
+
+// DeletePrefix is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+func (_e *MockStorage_Expecter) DeletePrefix(ctx interface{}, prefix interface{}) *MockStorage_DeletePrefix_Call {
+	return &MockStorage_DeletePrefix_Call{Call: _e.mock.On("DeletePrefix", ctx, prefix)}
+}
+
+func (_c *MockStorage_DeletePrefix_Call) Run(run func(ctx context.Context, prefix string)) *MockStorage_DeletePrefix_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeletePrefix_Call) Return(_a0 error) *MockStorage_DeletePrefix_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStorage_DeletePrefix_Call) RunAndReturn(run func(context.Context, string) error) *MockStorage_DeletePrefix_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Download provides a mock function with given fields: ctx, objectName
 func (_m *MockStorage) Download(ctx context.Context, objectName string) (io.ReadCloser, error) {
 	ret := _m.Called(ctx, objectName)
@@ -3,14 +3,19 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/ardfard/sb-test/internal/domain/storage"
 )
 
 // S3Storage implements the Storage interface for AWS S3.
@@ -81,6 +86,10 @@ func (s *S3Storage) Download(ctx context.Context, objectName string) (io.ReadClo
 	// Download the file into the buffer.
 	_, err := s.downloader.DownloadWithContext(ctx, buf, input)
 	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, fmt.Errorf("failed to download %s from s3: %w", objectName, storage.ErrObjectNotFound)
+		}
 		return nil, fmt.Errorf("failed to download file from s3: %v", err)
 	}
 
@@ -99,3 +108,51 @@ func (s *S3Storage) Delete(ctx context.Context, objectName string) error {
 	}
 	return nil
 }
+
+// DeletePrefix deletes the objects whose key starts with prefix, a page of
+// keys at a time.
+func (s *S3Storage) DeletePrefix(ctx context.Context, prefix string) error {
+	var deleteErr error
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		if len(page.Contents) == 0 {
+			return true
+		}
+		objects := make([]*s3.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: object.Key})
+		}
+		output, err := s.client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err == nil && len(output.Errors) > 0 {
+			err = errors.New(aws.StringValue(output.Errors[0].Message))
+		}
+		deleteErr = err
+		return err == nil
+	})
+	if err == nil {
+		err = deleteErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete files from s3: %v", err)
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GetObject URL for the object.
+func (s *S3Storage) SignedURL(ctx context.Context, objectName string, ttl time.Duration) (string, error) {
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectName),
+	})
+	req.SetContext(ctx)
+	url, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign s3 url: %v", err)
+	}
+	return url, nil
+}
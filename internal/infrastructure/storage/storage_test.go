@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ardfard/sb-test/config"
+	domainstorage "github.com/ardfard/sb-test/internal/domain/storage"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -96,9 +99,34 @@ func TestLocalStorage(t *testing.T) {
 	// Test Download non-existent file
 	t.Run("download non-existent file", func(t *testing.T) {
 		reader, err := localStorage.Download(ctx, "non-existent.txt")
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domainstorage.ErrObjectNotFound)
 		assert.Nil(t, reader)
 	})
+
+	t.Run("delete file", func(t *testing.T) {
+		require.NoError(t, localStorage.Delete(ctx, objectName))
+		assert.ErrorIs(t, localStorage.Delete(ctx, objectName), domainstorage.ErrObjectNotFound)
+	})
+
+	t.Run("delete prefix", func(t *testing.T) {
+		for _, name := range []string{"audio/original/3-7.m4a", "audio/original/3-70.m4a", "audio/spectrogram/4/a.png", "audio/spectrogram/4/b.png"} {
+			require.NoError(t, localStorage.Upload(ctx, name, bytes.NewReader(content)))
+		}
+
+		require.NoError(t, localStorage.DeletePrefix(ctx, "audio/original/3-7."))
+		require.NoError(t, localStorage.DeletePrefix(ctx, "audio/spectrogram/4/"))
+		require.NoError(t, localStorage.DeletePrefix(ctx, "audio/missing/"))
+
+		for name, exists := range map[string]bool{
+			"audio/original/3-7.m4a":    false,
+			"audio/original/3-70.m4a":   true,
+			"audio/spectrogram/4/a.png": false,
+			"audio/spectrogram/4/b.png": false,
+		} {
+			_, err := os.Stat(filepath.Join(tempDir, name))
+			assert.Equal(t, exists, err == nil, name)
+		}
+	})
 }
 
 func TestS3Storage(t *testing.T) {
@@ -156,10 +184,25 @@ func TestS3Storage(t *testing.T) {
 	// Test Download non-existent file
 	t.Run("download non-existent file", func(t *testing.T) {
 		reader, err := s3Storage.Download(ctx, "non-existent.txt")
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domainstorage.ErrObjectNotFound)
 		assert.Nil(t, reader)
 	})
 
+	// Test SignedURL
+	t.Run("signed url", func(t *testing.T) {
+		url, err := s3Storage.SignedURL(ctx, objectName, time.Minute)
+		require.NoError(t, err)
+
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		downloaded, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, content, downloaded)
+	})
+
 	// Cleanup
 	_, err = s3Client.DeleteObject(&s3.DeleteObjectInput{
 		Bucket: aws.String(bucket),
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/pkg/quality"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// AnalysisUseCase measures the technical quality of converted audio and flags
+// takes that violate the thresholds for review. It runs as a post-processor
+// of ConvertAudioUseCase.
+type AnalysisUseCase struct {
+	repo              repository.AudioAnalysisRepository
+	audioRepo         repository.AudioRepository
+	speechThresholdDB float64
+	thresholds        quality.Thresholds
+}
+
+func NewAnalysisUseCase(repo repository.AudioAnalysisRepository, audioRepo repository.AudioRepository, speechThresholdDB float64, thresholds quality.Thresholds) *AnalysisUseCase {
+	return &AnalysisUseCase{
+		repo:              repo,
+		audioRepo:         audioRepo,
+		speechThresholdDB: speechThresholdDB,
+		thresholds:        thresholds,
+	}
+}
+
+// Process analyzes the WAV at path, stores the analysis and sets
+// audio.NeedsReview. The caller persists audio.
+func (uc *AnalysisUseCase) Process(ctx context.Context, audio *entity.Audio, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audio: %v", err)
+	}
+	defer file.Close()
+
+	reader, err := wav.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read audio: %v", err)
+	}
+	report, err := quality.Analyze(reader, uc.speechThresholdDB)
+	if err != nil {
+		return fmt.Errorf("failed to analyze audio: %v", err)
+	}
+
+	analysis := &entity.AudioAnalysis{
+		AudioID:      audio.ID,
+		PeakDB:       report.PeakDB,
+		RMSDB:        report.RMSDB,
+		ClippedRatio: report.ClippedRatio,
+		NoiseFloorDB: report.NoiseFloorDB,
+		SNRDB:        report.SNRDB,
+		SpeechRatio:  report.SpeechRatio,
+		Problems:     uc.thresholds.Check(report),
+	}
+	if err := uc.repo.Save(ctx, analysis); err != nil {
+		return fmt.Errorf("failed to save analysis: %v", err)
+	}
+	audio.NeedsReview = len(analysis.Problems) > 0
+	return nil
+}
+
+// Get returns the analysis of an audio.
+func (uc *AnalysisUseCase) Get(ctx context.Context, audioID uint) (*entity.AudioAnalysis, error) {
+	if _, err := uc.audioRepo.GetByID(ctx, audioID); err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	analysis, err := uc.repo.GetByAudioID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get analysis")
+	}
+	return analysis, nil
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/pkg/quality"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTone writes a second of a 1 kHz sample rate square wave at amplitude
+// and returns its path.
+func writeTone(t *testing.T, amplitude float64) string {
+	samples := make([]float64, 1000)
+	for i := range samples {
+		samples[i] = amplitude
+		if i%2 == 1 {
+			samples[i] = -amplitude
+		}
+	}
+	path := filepath.Join(t.TempDir(), "converted.wav")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	require.NoError(t, wav.Encode(file, 1000, 1, samples))
+	return path
+}
+
+func TestAnalysisUseCase_Process(t *testing.T) {
+	thresholds := quality.Thresholds{MaxClippedRatio: 0.001, MinSpeechRatio: 0.1}
+
+	tests := []struct {
+		name             string
+		amplitude        float64
+		expectedProblems []string
+	}{
+		{name: "clean take", amplitude: 0.5},
+		{name: "clipped take", amplitude: 1, expectedProblems: []string{quality.ProblemClipping}},
+		{name: "empty take", amplitude: 0, expectedProblems: []string{quality.ProblemNoSpeech}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioAnalysisRepository(t)
+			repo.On("Save", mock.Anything, mock.MatchedBy(func(a *entity.AudioAnalysis) bool {
+				return a.AudioID == 1 && assert.ObjectsAreEqual(tt.expectedProblems, a.Problems)
+			})).Return(nil)
+
+			audio := &entity.Audio{ID: 1}
+			uc := NewAnalysisUseCase(repo, repoMocks.NewMockAudioRepository(t), -40, thresholds)
+			require.NoError(t, uc.Process(context.Background(), audio, writeTone(t, tt.amplitude)))
+			assert.Equal(t, len(tt.expectedProblems) > 0, audio.NeedsReview)
+		})
+	}
+}
+
+func TestAnalysisUseCase_Get(t *testing.T) {
+	notFound := fmt.Errorf("no rows: %w", repository.ErrNotFound)
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*repoMocks.MockAudioAnalysisRepository, *repoMocks.MockAudioRepository)
+		expectedError error
+	}{
+		{
+			name: "analyzed audio",
+			setupMocks: func(repo *repoMocks.MockAudioAnalysisRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1}, nil)
+				repo.On("GetByAudioID", mock.Anything, uint(1)).Return(&entity.AudioAnalysis{AudioID: 1}, nil)
+			},
+		},
+		{
+			name: "unknown audio",
+			setupMocks: func(repo *repoMocks.MockAudioAnalysisRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, notFound)
+			},
+			expectedError: ErrNotFound,
+		},
+		{
+			name: "audio not analyzed",
+			setupMocks: func(repo *repoMocks.MockAudioAnalysisRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1}, nil)
+				repo.On("GetByAudioID", mock.Anything, uint(1)).Return(nil, notFound)
+			},
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioAnalysisRepository(t)
+			audioRepo := repoMocks.NewMockAudioRepository(t)
+			tt.setupMocks(repo, audioRepo)
+
+			analysis, err := NewAnalysisUseCase(repo, audioRepo, -40, quality.Thresholds{}).Get(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(1), analysis.AudioID)
+		})
+	}
+}
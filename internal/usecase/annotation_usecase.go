@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/pkg/textgrid"
+	"github.com/ardfard/sb-test/pkg/webvtt"
+)
+
+// Limits on annotations.
+const (
+	DefaultAnnotationTier   = "default"
+	MaxAnnotationTier       = 64  // Characters
+	MaxAnnotationLabel      = 500 // Characters
+	MaxAnnotationAttributes = 50
+)
+
+// AnnotationExportFormat is the file format annotations are exported in.
+type AnnotationExportFormat string
+
+const (
+	// AnnotationExportTextGrid is a Praat TextGrid with an interval tier per
+	// tier.
+	AnnotationExportTextGrid AnnotationExportFormat = "textgrid"
+	// AnnotationExportVTT is a WebVTT file with a cue per annotation.
+	AnnotationExportVTT  AnnotationExportFormat = "vtt"
+	AnnotationExportJSON AnnotationExportFormat = "json"
+)
+
+// AnnotationInput is a new annotation. An empty tier selects
+// DefaultAnnotationTier.
+type AnnotationInput struct {
+	Tier       string
+	Label      string
+	Start      float64 // Seconds
+	End        float64 // Seconds
+	Attributes map[string]interface{}
+}
+
+// AnnotationUpdate holds the fields to change; nil fields are left as they
+// are.
+type AnnotationUpdate struct {
+	Tier       *string
+	Label      *string
+	Start      *float64
+	End        *float64
+	Attributes map[string]interface{}
+}
+
+// annotationDocument is the JSON export of the annotations of an audio.
+type annotationDocument struct {
+	AudioID     uint                     `json:"audio_id"`
+	Duration    float64                  `json:"duration"`
+	Annotations []annotationDocumentItem `json:"annotations"`
+}
+
+type annotationDocumentItem struct {
+	ID         uint                   `json:"id"`
+	Tier       string                 `json:"tier"`
+	Label      string                 `json:"label"`
+	Start      float64                `json:"start"`
+	End        float64                `json:"end"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// AnnotationUseCase manages time-aligned annotations of completed recordings.
+type AnnotationUseCase struct {
+	annotationRepository repository.AnnotationRepository
+	audioRepository      repository.AudioRepository
+}
+
+func NewAnnotationUseCase(annotationRepository repository.AnnotationRepository, audioRepository repository.AudioRepository) *AnnotationUseCase {
+	return &AnnotationUseCase{
+		annotationRepository: annotationRepository,
+		audioRepository:      audioRepository,
+	}
+}
+
+// ParseAnnotationExportFormat returns the format named s.
+func ParseAnnotationExportFormat(s string) (AnnotationExportFormat, error) {
+	switch format := AnnotationExportFormat(strings.ToLower(s)); format {
+	case AnnotationExportTextGrid, AnnotationExportVTT, AnnotationExportJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown export format %q: %w", s, ErrInvalidArgument)
+}
+
+// Create adds an annotation to a completed audio. It must lie within the
+// audio and not overlap another annotation of its tier.
+func (uc *AnnotationUseCase) Create(ctx context.Context, audioID uint, input AnnotationInput) (*entity.Annotation, error) {
+	now := time.Now()
+	annotation := &entity.Annotation{
+		AudioID:    audioID,
+		Tier:       strings.TrimSpace(input.Tier),
+		Label:      strings.TrimSpace(input.Label),
+		Start:      input.Start,
+		End:        input.End,
+		Attributes: input.Attributes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if annotation.Tier == "" {
+		annotation.Tier = DefaultAnnotationTier
+	}
+	if err := validateAnnotation(annotation); err != nil {
+		return nil, err
+	}
+
+	audio, err := uc.annotatableAudio(ctx, audioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkPlacement(ctx, audio, annotation); err != nil {
+		return nil, err
+	}
+	created, err := uc.annotationRepository.Create(ctx, annotation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create annotation: %v", err)
+	}
+	return created, nil
+}
+
+// Get returns an annotation of an audio.
+func (uc *AnnotationUseCase) Get(ctx context.Context, audioID, annotationID uint) (*entity.Annotation, error) {
+	annotation, err := uc.annotationRepository.GetByID(ctx, annotationID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get annotation")
+	}
+	if annotation.AudioID != audioID {
+		return nil, fmt.Errorf("audio %d has no annotation %d: %w", audioID, annotationID, ErrNotFound)
+	}
+	return annotation, nil
+}
+
+// List returns the annotations of an audio ordered by tier and start, only
+// those of tier if it is not empty.
+func (uc *AnnotationUseCase) List(ctx context.Context, audioID uint, tier string) ([]*entity.Annotation, error) {
+	if _, err := uc.audioRepository.GetByID(ctx, audioID); err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	return uc.list(ctx, audioID, tier)
+}
+
+// Update applies the update to an annotation and returns the result, which
+// must still lie within the audio and not overlap another annotation of its
+// tier.
+func (uc *AnnotationUseCase) Update(ctx context.Context, audioID, annotationID uint, update AnnotationUpdate) (*entity.Annotation, error) {
+	annotation, err := uc.Get(ctx, audioID, annotationID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Tier != nil {
+		annotation.Tier = strings.TrimSpace(*update.Tier)
+	}
+	if update.Label != nil {
+		annotation.Label = strings.TrimSpace(*update.Label)
+	}
+	if update.Start != nil {
+		annotation.Start = *update.Start
+	}
+	if update.End != nil {
+		annotation.End = *update.End
+	}
+	if update.Attributes != nil {
+		annotation.Attributes = update.Attributes
+	}
+	if err := validateAnnotation(annotation); err != nil {
+		return nil, err
+	}
+
+	audio, err := uc.annotatableAudio(ctx, audioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkPlacement(ctx, audio, annotation); err != nil {
+		return nil, err
+	}
+	annotation.UpdatedAt = time.Now()
+	if err := uc.annotationRepository.Update(ctx, annotation); err != nil {
+		return nil, wrapRepoError(err, "failed to update annotation")
+	}
+	return annotation, nil
+}
+
+// Delete removes an annotation of an audio.
+func (uc *AnnotationUseCase) Delete(ctx context.Context, audioID, annotationID uint) error {
+	if _, err := uc.Get(ctx, audioID, annotationID); err != nil {
+		return err
+	}
+	if err := uc.annotationRepository.Delete(ctx, annotationID); err != nil {
+		return wrapRepoError(err, "failed to delete annotation")
+	}
+	return nil
+}
+
+// Export renders the annotations of a completed audio, only those of tier if
+// it is not empty, in format.
+func (uc *AnnotationUseCase) Export(ctx context.Context, audioID uint, tier string, format AnnotationExportFormat) ([]byte, error) {
+	audio, err := uc.annotatableAudio(ctx, audioID)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := uc.list(ctx, audioID, tier)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case AnnotationExportTextGrid:
+		var tiers []textgrid.Tier
+		if tier != "" {
+			tiers = append(tiers, textgrid.Tier{Name: tier})
+		}
+		for _, annotation := range annotations {
+			if len(tiers) == 0 || tiers[len(tiers)-1].Name != annotation.Tier {
+				tiers = append(tiers, textgrid.Tier{Name: annotation.Tier})
+			}
+			last := &tiers[len(tiers)-1]
+			last.Intervals = append(last.Intervals, textgrid.Interval{Start: annotation.Start, End: annotation.End, Text: annotation.Label})
+		}
+		err = textgrid.Write(&buf, audio.Duration, tiers)
+	case AnnotationExportVTT:
+		cues := make([]webvtt.Cue, 0, len(annotations))
+		for _, annotation := range annotations {
+			cues = append(cues, webvtt.Cue{
+				ID:    strconv.FormatUint(uint64(annotation.ID), 10),
+				Start: annotation.Start,
+				End:   annotation.End,
+				Text:  annotation.Label,
+			})
+		}
+		slices.SortStableFunc(cues, func(a, b webvtt.Cue) int {
+			if c := cmp.Compare(a.Start, b.Start); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.End, b.End)
+		})
+		err = webvtt.Write(&buf, cues)
+	case AnnotationExportJSON:
+		document := annotationDocument{AudioID: audioID, Duration: audio.Duration, Annotations: make([]annotationDocumentItem, 0, len(annotations))}
+		for _, annotation := range annotations {
+			attributes := annotation.Attributes
+			if attributes == nil {
+				attributes = map[string]interface{}{}
+			}
+			document.Annotations = append(document.Annotations, annotationDocumentItem{
+				ID:         annotation.ID,
+				Tier:       annotation.Tier,
+				Label:      annotation.Label,
+				Start:      annotation.Start,
+				End:        annotation.End,
+				Attributes: attributes,
+			})
+		}
+		err = json.NewEncoder(&buf).Encode(document)
+	default:
+		return nil, fmt.Errorf("unknown export format %q: %w", format, ErrInvalidArgument)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to export annotations: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (uc *AnnotationUseCase) list(ctx context.Context, audioID uint, tier string) ([]*entity.Annotation, error) {
+	annotations, err := uc.annotationRepository.ListByAudioID(ctx, audioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list annotations: %v", err)
+	}
+	if tier == "" {
+		return annotations, nil
+	}
+	matching := []*entity.Annotation{}
+	for _, annotation := range annotations {
+		if annotation.Tier == tier {
+			matching = append(matching, annotation)
+		}
+	}
+	return matching, nil
+}
+
+// annotatableAudio returns the audio if it is completed, as only then is its
+// duration known.
+func (uc *AnnotationUseCase) annotatableAudio(ctx context.Context, audioID uint) (*entity.Audio, error) {
+	audio, err := uc.audioRepository.GetByID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	if audio.Status != entity.AudioStatusCompleted || audio.Duration <= 0 {
+		return nil, fmt.Errorf("audio %d is %s, only completed audio is annotated: %w", audioID, audio.Status, ErrConflict)
+	}
+	return audio, nil
+}
+
+// checkPlacement reports whether the annotation lies within the audio and
+// does not overlap another annotation of its tier.
+func (uc *AnnotationUseCase) checkPlacement(ctx context.Context, audio *entity.Audio, annotation *entity.Annotation) error {
+	if annotation.End > audio.Duration {
+		return fmt.Errorf("end %v is beyond the %v seconds of audio %d: %w", annotation.End, audio.Duration, audio.ID, ErrInvalidArgument)
+	}
+	existing, err := uc.annotationRepository.ListByAudioID(ctx, audio.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list annotations: %v", err)
+	}
+	for _, other := range existing {
+		if other.ID != annotation.ID && other.Tier == annotation.Tier && annotation.Start < other.End && other.Start < annotation.End {
+			return fmt.Errorf("annotation overlaps annotation %d of tier %q from %v to %v: %w",
+				other.ID, other.Tier, other.Start, other.End, ErrConflict)
+		}
+	}
+	return nil
+}
+
+func validateAnnotation(annotation *entity.Annotation) error {
+	if annotation.Tier == "" {
+		return fmt.Errorf("tier must not be empty: %w", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(annotation.Tier) > MaxAnnotationTier || strings.IndexFunc(annotation.Tier, unicode.IsControl) >= 0 {
+		return fmt.Errorf("tier must be at most %d characters without control characters: %w", MaxAnnotationTier, ErrInvalidArgument)
+	}
+	if annotation.Label == "" {
+		return fmt.Errorf("label must not be empty: %w", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(annotation.Label) > MaxAnnotationLabel || strings.IndexFunc(annotation.Label, unicode.IsControl) >= 0 {
+		return fmt.Errorf("label must be at most %d characters without control characters: %w", MaxAnnotationLabel, ErrInvalidArgument)
+	}
+	if annotation.Start < 0 || annotation.End <= annotation.Start {
+		return fmt.Errorf("annotation must end after it starts at or after 0, got %v to %v: %w", annotation.Start, annotation.End, ErrInvalidArgument)
+	}
+	if len(annotation.Attributes) > MaxAnnotationAttributes {
+		return fmt.Errorf("an annotation has at most %d attributes, got %d: %w", MaxAnnotationAttributes, len(annotation.Attributes), ErrInvalidArgument)
+	}
+	return nil
+}
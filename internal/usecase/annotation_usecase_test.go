@@ -0,0 +1,246 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationUseCase_Create(t *testing.T) {
+	completed := &entity.Audio{ID: 5, Status: entity.AudioStatusCompleted, Duration: 3}
+	existing := []*entity.Annotation{{ID: 1, AudioID: 5, Tier: "words", Label: "hello", Start: 0.5, End: 1}}
+	tests := []struct {
+		name          string
+		input         usecase.AnnotationInput
+		mockSetup     func(*repoMocks.MockAnnotationRepository, *repoMocks.MockAudioRepository)
+		expectedTier  string
+		expectedError error
+	}{
+		{
+			name:  "next to another annotation",
+			input: usecase.AnnotationInput{Tier: " words ", Label: "world", Start: 1, End: 1.5, Attributes: map[string]interface{}{"stress": true}},
+			mockSetup: func(annotationRepo *repoMocks.MockAnnotationRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(completed, nil)
+				annotationRepo.On("ListByAudioID", mock.Anything, uint(5)).Return(existing, nil)
+				annotationRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *entity.Annotation) bool {
+					return a.AudioID == 5 && a.Tier == "words" && a.Label == "world" && a.Attributes["stress"] == true
+				})).Return(&entity.Annotation{ID: 2, Tier: "words"}, nil)
+			},
+			expectedTier: "words",
+		},
+		{
+			name:  "overlap on another tier",
+			input: usecase.AnnotationInput{Label: "cough", Start: 0.8, End: 1.2},
+			mockSetup: func(annotationRepo *repoMocks.MockAnnotationRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(completed, nil)
+				annotationRepo.On("ListByAudioID", mock.Anything, uint(5)).Return(existing, nil)
+				annotationRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *entity.Annotation) bool {
+					return a.Tier == usecase.DefaultAnnotationTier
+				})).Return(&entity.Annotation{ID: 2, Tier: usecase.DefaultAnnotationTier}, nil)
+			},
+			expectedTier: usecase.DefaultAnnotationTier,
+		},
+		{
+			name:  "overlap on the same tier",
+			input: usecase.AnnotationInput{Tier: "words", Label: "lo", Start: 0.8, End: 1.2},
+			mockSetup: func(annotationRepo *repoMocks.MockAnnotationRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(completed, nil)
+				annotationRepo.On("ListByAudioID", mock.Anything, uint(5)).Return(existing, nil)
+			},
+			expectedError: usecase.ErrConflict,
+		},
+		{
+			name:  "beyond the audio",
+			input: usecase.AnnotationInput{Label: "tail", Start: 2.5, End: 3.5},
+			mockSetup: func(annotationRepo *repoMocks.MockAnnotationRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(completed, nil)
+			},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:  "audio not converted",
+			input: usecase.AnnotationInput{Label: "word", Start: 0, End: 1},
+			mockSetup: func(annotationRepo *repoMocks.MockAnnotationRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(&entity.Audio{ID: 5, Status: entity.AudioStatusPending}, nil)
+			},
+			expectedError: usecase.ErrConflict,
+		},
+		{
+			name:  "unknown audio",
+			input: usecase.AnnotationInput{Label: "word", Start: 0, End: 1},
+			mockSetup: func(annotationRepo *repoMocks.MockAnnotationRepository, audioRepo *repoMocks.MockAudioRepository) {
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(nil, fmt.Errorf("failed to get audio 5: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+		{
+			name:          "end before start",
+			input:         usecase.AnnotationInput{Label: "word", Start: 1, End: 0.5},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "negative start",
+			input:         usecase.AnnotationInput{Label: "word", Start: -0.5, End: 0.5},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "empty label",
+			input:         usecase.AnnotationInput{Label: "  ", Start: 0, End: 1},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "label with line break",
+			input:         usecase.AnnotationInput{Label: "two\nlines", Start: 0, End: 1},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "tier too long",
+			input:         usecase.AnnotationInput{Tier: strings.Repeat("t", usecase.MaxAnnotationTier+1), Label: "word", Start: 0, End: 1},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotationRepo := repoMocks.NewMockAnnotationRepository(t)
+			audioRepo := repoMocks.NewMockAudioRepository(t)
+			if tt.mockSetup != nil {
+				tt.mockSetup(annotationRepo, audioRepo)
+			}
+			uc := usecase.NewAnnotationUseCase(annotationRepo, audioRepo)
+
+			annotation, err := uc.Create(context.Background(), 5, tt.input)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTier, annotation.Tier)
+		})
+	}
+}
+
+func TestAnnotationUseCase_Update(t *testing.T) {
+	newUseCase := func(t *testing.T) (*usecase.AnnotationUseCase, *repoMocks.MockAnnotationRepository, *repoMocks.MockAudioRepository) {
+		annotationRepo := repoMocks.NewMockAnnotationRepository(t)
+		audioRepo := repoMocks.NewMockAudioRepository(t)
+		annotationRepo.On("GetByID", mock.Anything, uint(1)).
+			Return(&entity.Annotation{ID: 1, AudioID: 5, Tier: "words", Label: "hello", Start: 0.5, End: 1}, nil)
+		return usecase.NewAnnotationUseCase(annotationRepo, audioRepo), annotationRepo, audioRepo
+	}
+
+	t.Run("move within its own span", func(t *testing.T) {
+		uc, annotationRepo, audioRepo := newUseCase(t)
+		audioRepo.On("GetByID", mock.Anything, uint(5)).Return(&entity.Audio{ID: 5, Status: entity.AudioStatusCompleted, Duration: 3}, nil)
+		annotationRepo.On("ListByAudioID", mock.Anything, uint(5)).
+			Return([]*entity.Annotation{{ID: 1, AudioID: 5, Tier: "words", Start: 0.5, End: 1}}, nil)
+		annotationRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Annotation")).Return(nil)
+
+		end := 1.2
+		label := "hullo"
+		annotation, err := uc.Update(context.Background(), 5, 1, usecase.AnnotationUpdate{End: &end, Label: &label})
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, annotation.Start)
+		assert.Equal(t, 1.2, annotation.End)
+		assert.Equal(t, "hullo", annotation.Label)
+	})
+
+	t.Run("annotation of another audio", func(t *testing.T) {
+		uc, _, _ := newUseCase(t)
+		_, err := uc.Update(context.Background(), 6, 1, usecase.AnnotationUpdate{})
+		assert.ErrorIs(t, err, usecase.ErrNotFound)
+	})
+
+	t.Run("start after end", func(t *testing.T) {
+		uc, _, _ := newUseCase(t)
+		start := 2.0
+		_, err := uc.Update(context.Background(), 5, 1, usecase.AnnotationUpdate{Start: &start})
+		assert.ErrorIs(t, err, usecase.ErrInvalidArgument)
+	})
+}
+
+func TestAnnotationUseCase_Delete(t *testing.T) {
+	annotationRepo := repoMocks.NewMockAnnotationRepository(t)
+	uc := usecase.NewAnnotationUseCase(annotationRepo, repoMocks.NewMockAudioRepository(t))
+
+	annotationRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Annotation{ID: 1, AudioID: 5}, nil)
+	annotationRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+	assert.NoError(t, uc.Delete(context.Background(), 5, 1))
+
+	annotationRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("failed to get annotation 2: %w", repository.ErrNotFound))
+	assert.ErrorIs(t, uc.Delete(context.Background(), 5, 2), usecase.ErrNotFound)
+}
+
+func TestAnnotationUseCase_Export(t *testing.T) {
+	annotations := []*entity.Annotation{
+		{ID: 3, AudioID: 5, Tier: "noise", Label: "cough", Start: 1, End: 1.5},
+		{ID: 1, AudioID: 5, Tier: "words", Label: "hello", Start: 0.5, End: 1},
+		{ID: 2, AudioID: 5, Tier: "words", Label: "world", Start: 1.25, End: 2, Attributes: map[string]interface{}{"stress": true}},
+	}
+	newUseCase := func(t *testing.T) *usecase.AnnotationUseCase {
+		annotationRepo := repoMocks.NewMockAnnotationRepository(t)
+		audioRepo := repoMocks.NewMockAudioRepository(t)
+		audioRepo.On("GetByID", mock.Anything, uint(5)).Return(&entity.Audio{ID: 5, Status: entity.AudioStatusCompleted, Duration: 2.5}, nil)
+		annotationRepo.On("ListByAudioID", mock.Anything, uint(5)).Return(annotations, nil)
+		return usecase.NewAnnotationUseCase(annotationRepo, audioRepo)
+	}
+
+	t.Run("textgrid", func(t *testing.T) {
+		content, err := newUseCase(t).Export(context.Background(), 5, "", usecase.AnnotationExportTextGrid)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "xmax = 2.5\ntiers? <exists>\nsize = 2\n")
+		assert.Contains(t, string(content), `name = "noise"`)
+		assert.Contains(t, string(content), "xmin = 1\n            xmax = 1.25\n            text = \"\"\n")
+		assert.Contains(t, string(content), `text = "world"`)
+	})
+
+	t.Run("textgrid of an unused tier", func(t *testing.T) {
+		content, err := newUseCase(t).Export(context.Background(), 5, "phones", usecase.AnnotationExportTextGrid)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "size = 1\n")
+		assert.Contains(t, string(content), `name = "phones"`)
+	})
+
+	t.Run("vtt", func(t *testing.T) {
+		content, err := newUseCase(t).Export(context.Background(), 5, "", usecase.AnnotationExportVTT)
+		require.NoError(t, err)
+		assert.Equal(t, "WEBVTT\n\n1\n00:00:00.500 --> 00:00:01.000\nhello\n\n3\n00:00:01.000 --> 00:00:01.500\ncough\n\n"+
+			"2\n00:00:01.250 --> 00:00:02.000\nworld\n", string(content))
+	})
+
+	t.Run("json of one tier", func(t *testing.T) {
+		content, err := newUseCase(t).Export(context.Background(), 5, "words", usecase.AnnotationExportJSON)
+		require.NoError(t, err)
+		var document struct {
+			AudioID     uint    `json:"audio_id"`
+			Duration    float64 `json:"duration"`
+			Annotations []struct {
+				ID         uint                   `json:"id"`
+				Attributes map[string]interface{} `json:"attributes"`
+			} `json:"annotations"`
+		}
+		require.NoError(t, json.Unmarshal(content, &document))
+		assert.Equal(t, 2.5, document.Duration)
+		require.Len(t, document.Annotations, 2)
+		assert.Equal(t, map[string]interface{}{}, document.Annotations[0].Attributes)
+		assert.Equal(t, uint(2), document.Annotations[1].ID)
+	})
+
+	t.Run("format", func(t *testing.T) {
+		format, err := usecase.ParseAnnotationExportFormat("TextGrid")
+		assert.NoError(t, err)
+		assert.Equal(t, usecase.AnnotationExportTextGrid, format)
+		_, err = usecase.ParseAnnotationExportFormat("srt")
+		assert.ErrorIs(t, err, usecase.ErrInvalidArgument)
+	})
+}
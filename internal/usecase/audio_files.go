@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+)
+
+// audioFiles returns the storage paths of the files derived from an audio,
+// and the prefixes of those whose exact path is not known.
+func audioFiles(audio *entity.Audio) (paths, prefixes []string) {
+	paths = append(paths, audio.StoragePath, waveformPath(audio.ID), pitchPath(audio.ID))
+	if converted := convertedPath(audio.ID); converted != audio.StoragePath {
+		paths = append(paths, converted)
+	}
+	if audio.NormalizedPath != "" {
+		paths = append(paths, audio.NormalizedPath)
+	}
+	// The format of the original is lost once the audio is converted.
+	prefixes = append(prefixes, originalPath(audio.UserID, audio.PhraseID, ""), spectrogramDir(audio.ID))
+	return paths, prefixes
+}
+
+// deleteFiles removes the files at paths, which need not exist, and every
+// file under prefixes.
+func deleteFiles(ctx context.Context, store storage.Storage, paths, prefixes []string) error {
+	for _, path := range paths {
+		if err := store.Delete(ctx, path); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			return fmt.Errorf("failed to delete %s: %v", path, err)
+		}
+	}
+	for _, prefix := range prefixes {
+		if err := store.DeletePrefix(ctx, prefix); err != nil {
+			return fmt.Errorf("failed to delete %s*: %v", prefix, err)
+		}
+	}
+	return nil
+}
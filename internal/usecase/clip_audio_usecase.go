@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ardfard/sb-test/internal/domain/converter"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// ClipAudioUseCase extracts a time range of a converted audio.
+type ClipAudioUseCase struct {
+	repo      repository.AudioRepository
+	storage   storage.Storage
+	converter converter.AudioConverter
+}
+
+func NewClipAudioUseCase(repo repository.AudioRepository, storage storage.Storage, converter converter.AudioConverter) *ClipAudioUseCase {
+	return &ClipAudioUseCase{
+		repo:      repo,
+		storage:   storage,
+		converter: converter,
+	}
+}
+
+// Clip returns the part of the canonical audio between start and end
+// seconds in outputFormat. The range is cut from the PCM samples, so it is
+// sample accurate; only the clip is transcoded.
+func (uc *ClipAudioUseCase) Clip(ctx context.Context, audioID uint, start, end float64, outputFormat string) (io.ReadCloser, error) {
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("clip range %v-%v must be non-negative and not empty: %w", start, end, ErrInvalidArgument)
+	}
+	if !supportedFormats[outputFormat] {
+		return nil, fmt.Errorf("unsupported format %q: %w", outputFormat, ErrInvalidArgument)
+	}
+
+	audio, err := uc.repo.GetByID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	if audio.Status != entity.AudioStatusCompleted {
+		return nil, fmt.Errorf("audio %d is %s: %w", audioID, audio.Status, ErrConflict)
+	}
+	if end > audio.Duration {
+		return nil, fmt.Errorf("clip end %v is past the audio duration %v: %w", end, audio.Duration, ErrInvalidArgument)
+	}
+
+	reader, err := uc.storage.Download(ctx, audio.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download audio: %v", err)
+	}
+	defer reader.Close()
+
+	clip, err := wav.DecodeRange(reader, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %v", err)
+	}
+	var out bytes.Buffer
+	if err := wav.Encode(&out, clip.SampleRate, clip.Channels, clip.Samples); err != nil {
+		return nil, fmt.Errorf("failed to encode clip: %v", err)
+	}
+
+	if outputFormat == "wav" {
+		return io.NopCloser(&out), nil
+	}
+	output, err := uc.converter.ConvertFromReader(ctx, &out, "wav", outputFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert clip: %v", err)
+	}
+	return output, nil
+}
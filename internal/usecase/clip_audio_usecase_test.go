@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClipAudioUseCase_Clip(t *testing.T) {
+	var source bytes.Buffer
+	require.NoError(t, wav.Encode(&source, 8, 1, []float64{0, 0.125, 0.25, 0.375, 0.5, 0.625, 0.75, 0.875}))
+	completed := &entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/1.wav", CurrentFormat: "wav", Duration: 1}
+
+	tests := []struct {
+		name          string
+		start, end    float64
+		format        string
+		setupMocks    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage, *converterMocks.MockAudioConverter)
+		check         func(*testing.T, io.ReadCloser)
+		expectedError error
+	}{
+		{
+			name:  "pcm clip is sample accurate",
+			start: 0.25, end: 0.625,
+			format: "wav",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage, c *converterMocks.MockAudioConverter) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+			},
+			check: func(t *testing.T, r io.ReadCloser) {
+				clip, err := wav.Decode(r)
+				require.NoError(t, err)
+				assert.InDeltaSlice(t, []float64{0.25, 0.375, 0.5}, clip.Samples, 1.0/32767)
+			},
+		},
+		{
+			name:  "transcodes only the clip",
+			start: 0, end: 0.25,
+			format: "mp3",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage, c *converterMocks.MockAudioConverter) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+				c.On("ConvertFromReader", mock.Anything, mock.MatchedBy(func(r io.Reader) bool {
+					clip, err := wav.Decode(r)
+					return err == nil && clip.Frames() == 2
+				}), "wav", "mp3").Return(io.NopCloser(strings.NewReader("mp3 clip")), nil)
+			},
+			check: func(t *testing.T, r io.ReadCloser) {
+				content, err := io.ReadAll(r)
+				require.NoError(t, err)
+				assert.Equal(t, "mp3 clip", string(content))
+			},
+		},
+		{
+			name:  "end past duration",
+			start: 0.5, end: 1.5,
+			format: "wav",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage, c *converterMocks.MockAudioConverter) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+			},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:  "empty range",
+			start: 0.5, end: 0.5,
+			format:        "wav",
+			setupMocks:    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage, *converterMocks.MockAudioConverter) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:  "unsupported format",
+			start: 0, end: 0.5,
+			format:        "exe",
+			setupMocks:    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage, *converterMocks.MockAudioConverter) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:  "not converted yet",
+			start: 0, end: 0.5,
+			format: "wav",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage, c *converterMocks.MockAudioConverter) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusPending}, nil)
+			},
+			expectedError: ErrConflict,
+		},
+		{
+			name:  "unknown audio",
+			start: 0, end: 0.5,
+			format: "wav",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage, c *converterMocks.MockAudioConverter) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("get: %w", repository.ErrNotFound))
+			},
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			c := converterMocks.NewMockAudioConverter(t)
+			tt.setupMocks(repo, s, c)
+
+			reader, err := NewClipAudioUseCase(repo, s, c).Clip(context.Background(), 1, tt.start, tt.end, tt.format)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, reader)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// Limits on collection requests.
+const (
+	MaxCollectionPhrases = 5000
+	MaxCollectionTags    = 20
+)
+
+// CollectionRequest describes a collection to create.
+type CollectionRequest struct {
+	Name     string
+	Language string
+	Tags     []string
+	Phrases  []string // Texts of the phrases, in order
+}
+
+func (r CollectionRequest) validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("name must not be empty: %w", ErrInvalidArgument)
+	}
+	if len(r.Phrases) == 0 {
+		return fmt.Errorf("at least one phrase is required: %w", ErrInvalidArgument)
+	}
+	if len(r.Phrases) > MaxCollectionPhrases {
+		return fmt.Errorf("a collection holds at most %d phrases, got %d: %w", MaxCollectionPhrases, len(r.Phrases), ErrInvalidArgument)
+	}
+	for i, text := range r.Phrases {
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("phrase %d is empty: %w", i+1, ErrInvalidArgument)
+		}
+	}
+	if len(r.Tags) > MaxCollectionTags {
+		return fmt.Errorf("a collection has at most %d tags, got %d: %w", MaxCollectionTags, len(r.Tags), ErrInvalidArgument)
+	}
+	for _, tag := range r.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tags must not be empty: %w", ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+// CollectionDetails is a collection with its phrases and the users it is
+// assigned to.
+type CollectionDetails struct {
+	*entity.Collection
+	Phrases   []*entity.CollectionPhrase
+	Assignees []uint
+}
+
+// CollectionUseCase manages collections: scripts of phrases owned by one user
+// and recorded by every user the collection is assigned to. The phrases are
+// regular phrases, so each speaker's take is stored as their audio of it.
+type CollectionUseCase struct {
+	repo            repository.CollectionRepository
+	userRepo        repository.UserRepository
+	uploadUseCase   *UploadAudioUseCase
+	downloadUseCase *DownloadAudioUseCase
+}
+
+// NewCollectionUseCase creates a CollectionUseCase. Takes of the phrases are
+// stored by uploadUseCase and served by downloadUseCase.
+func NewCollectionUseCase(
+	repo repository.CollectionRepository,
+	userRepo repository.UserRepository,
+	uploadUseCase *UploadAudioUseCase,
+	downloadUseCase *DownloadAudioUseCase,
+) *CollectionUseCase {
+	return &CollectionUseCase{
+		repo:            repo,
+		userRepo:        userRepo,
+		uploadUseCase:   uploadUseCase,
+		downloadUseCase: downloadUseCase,
+	}
+}
+
+// Policy returns the limits applied to uploaded takes.
+func (uc *CollectionUseCase) Policy() UploadPolicy {
+	return uc.uploadUseCase.Policy()
+}
+
+// Create stores a collection owned by userID, creating its phrases.
+func (uc *CollectionUseCase) Create(ctx context.Context, userID uint, req CollectionRequest) (*entity.Collection, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+
+	tags := make([]string, len(req.Tags))
+	for i, tag := range req.Tags {
+		tags[i] = strings.TrimSpace(tag)
+	}
+	collection, err := uc.repo.Create(ctx, &entity.Collection{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Language:  strings.TrimSpace(req.Language),
+		Tags:      tags,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, req.Phrases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store collection: %v", err)
+	}
+	return collection, nil
+}
+
+// Get returns a collection with its phrases and assignees.
+func (uc *CollectionUseCase) Get(ctx context.Context, collectionID uint) (*CollectionDetails, error) {
+	collection, err := uc.repo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get collection")
+	}
+	phrases, err := uc.repo.ListPhrases(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection phrases: %v", err)
+	}
+	assignees, err := uc.repo.ListAssignees(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection assignees: %v", err)
+	}
+	return &CollectionDetails{Collection: collection, Phrases: phrases, Assignees: assignees}, nil
+}
+
+// List returns the collections a user owns and those assigned to them.
+func (uc *CollectionUseCase) List(ctx context.Context, userID uint) (owned, assigned []*entity.Collection, err error) {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, nil, wrapRepoError(err, "failed to get user")
+	}
+	if owned, err = uc.repo.ListByUserID(ctx, userID); err != nil {
+		return nil, nil, fmt.Errorf("failed to list owned collections: %v", err)
+	}
+	if assigned, err = uc.repo.ListAssigned(ctx, userID); err != nil {
+		return nil, nil, fmt.Errorf("failed to list assigned collections: %v", err)
+	}
+	return owned, assigned, nil
+}
+
+// Assign lets the owner of a collection have userID record and download
+// every phrase of it. Assigning a collection twice is a no-op.
+func (uc *CollectionUseCase) Assign(ctx context.Context, ownerID, collectionID, userID uint) error {
+	collection, err := uc.ownedCollection(ctx, collectionID, ownerID)
+	if err != nil {
+		return err
+	}
+	if collection.UserID == userID {
+		return fmt.Errorf("collection %d is owned by user %d: %w", collectionID, userID, ErrInvalidArgument)
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return wrapRepoError(err, "failed to get user")
+	}
+	if err := uc.repo.Assign(ctx, collectionID, userID); err != nil {
+		return fmt.Errorf("failed to assign collection: %v", err)
+	}
+	return nil
+}
+
+// Unassign lets the owner of a collection revoke an assignment. Recordings
+// the user already made are kept, but the user can no longer download them.
+func (uc *CollectionUseCase) Unassign(ctx context.Context, ownerID, collectionID, userID uint) error {
+	if _, err := uc.ownedCollection(ctx, collectionID, ownerID); err != nil {
+		return err
+	}
+	if err := uc.repo.Unassign(ctx, collectionID, userID); err != nil {
+		return wrapRepoError(err, "failed to unassign collection")
+	}
+	return nil
+}
+
+// ownedCollection returns a collection if ownerID owns it, which is required
+// to assign it.
+func (uc *CollectionUseCase) ownedCollection(ctx context.Context, collectionID, ownerID uint) (*entity.Collection, error) {
+	collection, err := uc.repo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get collection")
+	}
+	if collection.UserID != ownerID {
+		return nil, fmt.Errorf("collection %d is not owned by user %d: %w", collectionID, ownerID, ErrForbidden)
+	}
+	return collection, nil
+}
+
+// Upload stores the user's take of the phrase at a position of a
+// collection, counted from 1, as UploadAudioUseCase.Upload does for a phrase.
+func (uc *CollectionUseCase) Upload(ctx context.Context, filename string, content io.Reader, userID, collectionID uint, position int, sessionID uint) (*entity.Audio, error) {
+	phrase, err := uc.phrase(ctx, collectionID, position)
+	if err != nil {
+		return nil, err
+	}
+	return uc.uploadUseCase.Upload(ctx, filename, content, userID, phrase.ID, sessionID)
+}
+
+// Download returns the user's take of the phrase at a position of a
+// collection, as DownloadAudioUseCase.Download does for a phrase.
+func (uc *CollectionUseCase) Download(ctx context.Context, userID, collectionID uint, position int, outputFormat string, opts DownloadOptions) (io.ReadCloser, error) {
+	phrase, err := uc.phrase(ctx, collectionID, position)
+	if err != nil {
+		return nil, err
+	}
+	return uc.downloadUseCase.Download(ctx, userID, phrase.ID, outputFormat, opts)
+}
+
+// phrase returns the phrase at a position of a collection, counted from 1.
+func (uc *CollectionUseCase) phrase(ctx context.Context, collectionID uint, position int) (*entity.CollectionPhrase, error) {
+	phrase, err := uc.repo.GetPhrase(ctx, collectionID, position)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get collection phrase")
+	}
+	return phrase, nil
+}
@@ -0,0 +1,153 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionUseCase_Create(t *testing.T) {
+	valid := usecase.CollectionRequest{Name: " Greetings ", Language: "en-US", Tags: []string{" news "}, Phrases: []string{"Hello", "Goodbye"}}
+	tests := []struct {
+		name          string
+		modify        func(*usecase.CollectionRequest)
+		expectedError error
+	}{
+		{name: "valid", modify: func(*usecase.CollectionRequest) {}},
+		{name: "no name", modify: func(r *usecase.CollectionRequest) { r.Name = " " }, expectedError: usecase.ErrInvalidArgument},
+		{name: "no phrases", modify: func(r *usecase.CollectionRequest) { r.Phrases = nil }, expectedError: usecase.ErrInvalidArgument},
+		{name: "empty phrase", modify: func(r *usecase.CollectionRequest) { r.Phrases = []string{"Hello", ""} }, expectedError: usecase.ErrInvalidArgument},
+		{name: "empty tag", modify: func(r *usecase.CollectionRequest) { r.Tags = []string{""} }, expectedError: usecase.ErrInvalidArgument},
+		{
+			name: "too many phrases",
+			modify: func(r *usecase.CollectionRequest) {
+				r.Phrases = strings.Split(strings.Repeat("a,", usecase.MaxCollectionPhrases), ",")
+			},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockCollectionRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			if tt.expectedError == nil {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(c *entity.Collection) bool {
+					return c.UserID == 1 && c.Name == "Greetings" && c.Language == "en-US" && len(c.Tags) == 1 && c.Tags[0] == "news"
+				}), []string{"Hello", "Goodbye"}).Return(&entity.Collection{ID: 4, UserID: 1, Name: "Greetings"}, nil)
+			}
+			uc := usecase.NewCollectionUseCase(repo, userRepo, nil, nil)
+
+			req := valid
+			tt.modify(&req)
+			collection, err := uc.Create(context.Background(), 1, req)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(4), collection.ID)
+		})
+	}
+}
+
+func TestCollectionUseCase_Assign(t *testing.T) {
+	tests := []struct {
+		name          string
+		ownerID       uint
+		userID        uint
+		setupMocks    func(*repoMocks.MockCollectionRepository, *repoMocks.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name:    "assign",
+			ownerID: 1,
+			userID:  2,
+			setupMocks: func(repo *repoMocks.MockCollectionRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				repo.On("Assign", mock.Anything, uint(4), uint(2)).Return(nil)
+			},
+		},
+		{
+			name:          "assign to owner",
+			ownerID:       1,
+			userID:        1,
+			setupMocks:    func(*repoMocks.MockCollectionRepository, *repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:    "unknown user",
+			ownerID: 1,
+			userID:  3,
+			setupMocks: func(repo *repoMocks.MockCollectionRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(3)).Return(nil, fmt.Errorf("failed to get user 3: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+		{
+			name:          "caller does not own the collection",
+			ownerID:       2,
+			userID:        2,
+			setupMocks:    func(*repoMocks.MockCollectionRepository, *repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockCollectionRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1}, nil)
+			tt.setupMocks(repo, userRepo)
+			uc := usecase.NewCollectionUseCase(repo, userRepo, nil, nil)
+
+			err := uc.Assign(context.Background(), tt.ownerID, 4, tt.userID)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCollectionUseCase_Unassign(t *testing.T) {
+	repo := repoMocks.NewMockCollectionRepository(t)
+	repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1}, nil)
+	repo.On("Unassign", mock.Anything, uint(4), uint(2)).Return(nil).Once()
+	uc := usecase.NewCollectionUseCase(repo, repoMocks.NewMockUserRepository(t), nil, nil)
+
+	require.NoError(t, uc.Unassign(context.Background(), 1, 4, 2))
+	assert.ErrorIs(t, uc.Unassign(context.Background(), 3, 4, 2), usecase.ErrForbidden)
+}
+
+func TestCollectionUseCase_Get(t *testing.T) {
+	repo := repoMocks.NewMockCollectionRepository(t)
+	repo.On("GetByID", mock.Anything, uint(4)).Return(&entity.Collection{ID: 4, UserID: 1, Name: "Greetings"}, nil)
+	repo.On("ListPhrases", mock.Anything, uint(4)).Return([]*entity.CollectionPhrase{{Position: 1, Phrase: entity.Phrase{ID: 7, Phrase: "Hello"}}}, nil)
+	repo.On("ListAssignees", mock.Anything, uint(4)).Return([]uint{2, 3}, nil)
+	repo.On("GetByID", mock.Anything, uint(5)).Return(nil, fmt.Errorf("failed to get collection 5: %w", repository.ErrNotFound))
+	repo.On("GetPhrase", mock.Anything, uint(4), 9).Return(nil, fmt.Errorf("failed to get phrase 9 of collection 4: %w", repository.ErrNotFound))
+	uc := usecase.NewCollectionUseCase(repo, repoMocks.NewMockUserRepository(t), nil, nil)
+
+	details, err := uc.Get(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Equal(t, "Greetings", details.Name)
+	assert.Len(t, details.Phrases, 1)
+	assert.Equal(t, []uint{2, 3}, details.Assignees)
+
+	_, err = uc.Get(context.Background(), 5)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+	_, err = uc.Download(context.Background(), 2, 4, 9, "wav", usecase.DownloadOptions{})
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+}
@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/converter"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// Limits on compilation requests.
+const (
+	MaxCompilationPhrases   = 500
+	MaxCompilationGap       = 10.0 // Seconds
+	MaxCompilationCrossfade = 5.0  // Seconds
+)
+
+// CompilationRequest describes a compilation to build.
+type CompilationRequest struct {
+	PhraseIDs []uint
+	Format    string  // Output format; wav when empty
+	Gap       float64 // Seconds of silence between phrases
+	Crossfade float64 // Seconds adjacent phrases overlap; exclusive with Gap
+}
+
+func (r CompilationRequest) validate() error {
+	if len(r.PhraseIDs) == 0 {
+		return fmt.Errorf("at least one phrase is required: %w", ErrInvalidArgument)
+	}
+	if len(r.PhraseIDs) > MaxCompilationPhrases {
+		return fmt.Errorf("at most %d phrases can be compiled, got %d: %w", MaxCompilationPhrases, len(r.PhraseIDs), ErrInvalidArgument)
+	}
+	seen := make(map[uint]bool, len(r.PhraseIDs))
+	for _, id := range r.PhraseIDs {
+		if seen[id] {
+			return fmt.Errorf("phrase %d is listed more than once: %w", id, ErrInvalidArgument)
+		}
+		seen[id] = true
+	}
+	if r.Gap < 0 || r.Gap > MaxCompilationGap {
+		return fmt.Errorf("gap must be between 0 and %v seconds, got %v: %w", MaxCompilationGap, r.Gap, ErrInvalidArgument)
+	}
+	if r.Format != "" && !supportedFormats[r.Format] {
+		return fmt.Errorf("unsupported format %q: %w", r.Format, ErrInvalidArgument)
+	}
+	if r.Crossfade < 0 || r.Crossfade > MaxCompilationCrossfade {
+		return fmt.Errorf("crossfade must be between 0 and %v seconds, got %v: %w", MaxCompilationCrossfade, r.Crossfade, ErrInvalidArgument)
+	}
+	if r.Gap > 0 && r.Crossfade > 0 {
+		return fmt.Errorf("gap and crossfade cannot be combined: %w", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// CompilationUseCase concatenates the canonical audio of several phrases of
+// a user into one file. Compilations are built asynchronously by a worker
+// consuming the queue the use case enqueues to.
+type CompilationUseCase struct {
+	repo      repository.CompilationRepository
+	audioRepo repository.AudioRepository
+	userRepo  repository.UserRepository
+	storage   storage.Storage
+	converter converter.AudioConverter
+	queue     queue.TaskQueue
+}
+
+func NewCompilationUseCase(
+	repo repository.CompilationRepository,
+	audioRepo repository.AudioRepository,
+	userRepo repository.UserRepository,
+	storage storage.Storage,
+	converter converter.AudioConverter,
+	queue queue.TaskQueue,
+) *CompilationUseCase {
+	return &CompilationUseCase{
+		repo:      repo,
+		audioRepo: audioRepo,
+		userRepo:  userRepo,
+		storage:   storage,
+		converter: converter,
+		queue:     queue,
+	}
+}
+
+func compilationPath(compilationID uint, ext string) string {
+	return fmt.Sprintf("%s/compilations/%d.%s", basePath, compilationID, ext)
+}
+
+// Create validates the request and queues the compilation. Every phrase
+// must have a converted recording by the user.
+func (uc *CompilationUseCase) Create(ctx context.Context, userID uint, req CompilationRequest) (*entity.Compilation, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if req.Format == "" {
+		req.Format = "wav"
+	}
+
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	for _, phraseID := range req.PhraseIDs {
+		if _, err := uc.completedAudio(ctx, userID, phraseID); err != nil {
+			return nil, err
+		}
+	}
+
+	compilation, err := uc.repo.Create(ctx, &entity.Compilation{
+		UserID:    userID,
+		PhraseIDs: req.PhraseIDs,
+		Format:    req.Format,
+		Gap:       req.Gap,
+		Crossfade: req.Crossfade,
+		Status:    entity.CompilationStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store compilation: %v", err)
+	}
+
+	if err := uc.queue.Enqueue(ctx, compilation.ID); err != nil {
+		return nil, fmt.Errorf("failed to enqueue compilation: %v", err)
+	}
+	return compilation, nil
+}
+
+func (uc *CompilationUseCase) completedAudio(ctx context.Context, userID, phraseID uint) (*entity.Audio, error) {
+	audio, err := uc.audioRepo.GetByUserIDAndPhraseID(ctx, userID, phraseID)
+	if err != nil {
+		return nil, wrapRepoError(err, fmt.Sprintf("failed to get audio for phrase %d", phraseID))
+	}
+	if audio.Status != entity.AudioStatusCompleted {
+		return nil, fmt.Errorf("audio for phrase %d is %s: %w", phraseID, audio.Status, ErrConflict)
+	}
+	return audio, nil
+}
+
+// Get returns a compilation.
+func (uc *CompilationUseCase) Get(ctx context.Context, compilationID uint) (*entity.Compilation, error) {
+	compilation, err := uc.repo.GetByID(ctx, compilationID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get compilation")
+	}
+	return compilation, nil
+}
+
+// Process builds a queued compilation and stores it with its manifest.
+func (uc *CompilationUseCase) Process(ctx context.Context, compilationID uint) error {
+	compilation, err := uc.repo.GetByID(ctx, compilationID)
+	if err != nil {
+		return fmt.Errorf("failed to get compilation: %v", err)
+	}
+
+	compilation.Status = entity.CompilationStatusProcessing
+	if err := uc.repo.Update(ctx, compilation); err != nil {
+		return fmt.Errorf("failed to update compilation status: %v", err)
+	}
+
+	if err := uc.build(ctx, compilation); err != nil {
+		compilation.Status = entity.CompilationStatusFailed
+		compilation.Error = err.Error()
+		if err := uc.repo.Update(ctx, compilation); err != nil {
+			return fmt.Errorf("failed to update compilation status: %v", err)
+		}
+		return err
+	}
+
+	compilation.Status = entity.CompilationStatusCompleted
+	compilation.Error = ""
+	if err := uc.repo.Update(ctx, compilation); err != nil {
+		return fmt.Errorf("failed to update compilation status: %v", err)
+	}
+	return nil
+}
+
+// build streams the recordings into a temporary WAV file one after another,
+// so that only a chunk and the crossfade are held in memory, and stores the
+// result with its manifest.
+func (uc *CompilationUseCase) build(ctx context.Context, compilation *entity.Compilation) error {
+	audios := make([]*entity.Audio, 0, len(compilation.PhraseIDs))
+	for _, phraseID := range compilation.PhraseIDs {
+		audio, err := uc.completedAudio(ctx, compilation.UserID, phraseID)
+		if err != nil {
+			return err
+		}
+		audios = append(audios, audio)
+	}
+	formats, err := uc.formats(ctx, audios)
+	if err != nil {
+		return err
+	}
+	sampleRate, channels := formats[0].SampleRate, 0
+	for _, format := range formats {
+		channels = max(channels, format.Channels)
+	}
+
+	file, err := os.CreateTemp("", "compilation-*.wav")
+	if err != nil {
+		return fmt.Errorf("failed to create compilation file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	writer, err := wav.NewWriter(file, sampleRate, channels)
+	if err != nil {
+		return fmt.Errorf("failed to encode compilation: %v", err)
+	}
+	joiner := wav.NewJoiner(writer, compilation.Gap, compilation.Crossfade)
+
+	rate := float64(sampleRate)
+	entries := make([]entity.CompilationManifestEntry, 0, len(audios))
+	for i, audio := range audios {
+		start, frames, err := uc.join(ctx, joiner, audio, formats[i].SampleRate != sampleRate, sampleRate)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entity.CompilationManifestEntry{
+			PhraseID: audio.PhraseID,
+			AudioID:  audio.ID,
+			Start:    float64(start) / rate,
+			End:      float64(start+frames) / rate,
+		})
+	}
+	if err := joiner.Close(); err != nil {
+		return fmt.Errorf("failed to encode compilation: %v", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read compilation file: %v", err)
+	}
+
+	var output io.Reader = file
+	if compilation.Format != "wav" {
+		converted, err := uc.converter.ConvertFromReader(ctx, file, "wav", compilation.Format)
+		if err != nil {
+			return fmt.Errorf("failed to convert compilation: %v", err)
+		}
+		defer converted.Close()
+		output = converted
+	}
+
+	audioPath := compilationPath(compilation.ID, compilation.Format)
+	if err := uc.storage.Upload(ctx, audioPath, output); err != nil {
+		return fmt.Errorf("failed to upload compilation: %v", err)
+	}
+
+	manifest, err := json.Marshal(entity.CompilationManifest{
+		Format:     compilation.Format,
+		SampleRate: sampleRate,
+		Channels:   channels,
+		Duration:   float64(writer.Frames()) / rate,
+		Phrases:    entries,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %v", err)
+	}
+	manifestPath := compilationPath(compilation.ID, "json")
+	if err := uc.storage.Upload(ctx, manifestPath, bytes.NewReader(manifest)); err != nil {
+		return fmt.Errorf("failed to upload manifest: %v", err)
+	}
+
+	compilation.StoragePath = audioPath
+	compilation.ManifestPath = manifestPath
+	return nil
+}
+
+// formats reads the header of each canonical audio. The compilation has the
+// sample rate of the first and as many channels as the widest.
+func (uc *CompilationUseCase) formats(ctx context.Context, audios []*entity.Audio) ([]wav.Format, error) {
+	formats := make([]wav.Format, 0, len(audios))
+	for _, audio := range audios {
+		reader, err := uc.storage.Download(ctx, audio.StoragePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download audio %d: %v", audio.ID, err)
+		}
+		clip, err := wav.NewReader(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audio %d: %v", audio.ID, err)
+		}
+		formats = append(formats, clip.Format)
+	}
+	return formats, nil
+}
+
+// join streams the canonical audio into the compilation, resampling it to
+// sampleRate on the way if asked to, and returns where it starts and its
+// length in frames.
+func (uc *CompilationUseCase) join(ctx context.Context, joiner *wav.Joiner, audio *entity.Audio, resample bool, sampleRate int) (int64, int64, error) {
+	reader, err := uc.storage.Download(ctx, audio.StoragePath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to download audio %d: %v", audio.ID, err)
+	}
+	defer reader.Close()
+
+	source := io.Reader(reader)
+	if resample {
+		resampled, err := uc.converter.ConvertFromReader(ctx, reader, "wav", "wav", entity.Filter{
+			Name:   "aresample",
+			Params: map[string]string{"osr": strconv.Itoa(sampleRate)},
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to resample audio %d: %v", audio.ID, err)
+		}
+		defer resampled.Close()
+		source = resampled
+	}
+
+	clip, err := wav.NewReader(source)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read audio %d: %v", audio.ID, err)
+	}
+	start, frames, err := joiner.Append(clip)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to join audio %d: %v", audio.ID, err)
+	}
+	return start, frames, nil
+}
+
+// Download returns the file of a completed compilation.
+func (uc *CompilationUseCase) Download(ctx context.Context, compilationID uint) (io.ReadCloser, *entity.Compilation, error) {
+	compilation, err := uc.completed(ctx, compilationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, err := uc.storage.Download(ctx, compilation.StoragePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to download compilation: %v", err)
+	}
+	return reader, compilation, nil
+}
+
+// Manifest returns the JSON manifest of a completed compilation.
+func (uc *CompilationUseCase) Manifest(ctx context.Context, compilationID uint) (io.ReadCloser, error) {
+	compilation, err := uc.completed(ctx, compilationID)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := uc.storage.Download(ctx, compilation.ManifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download manifest: %v", err)
+	}
+	return reader, nil
+}
+
+func (uc *CompilationUseCase) completed(ctx context.Context, compilationID uint) (*entity.Compilation, error) {
+	compilation, err := uc.Get(ctx, compilationID)
+	if err != nil {
+		return nil, err
+	}
+	switch compilation.Status {
+	case entity.CompilationStatusCompleted:
+		return compilation, nil
+	case entity.CompilationStatusFailed:
+		return nil, fmt.Errorf("compilation %d failed: %s: %w", compilationID, compilation.Error, ErrConflict)
+	default:
+		return nil, fmt.Errorf("compilation %d is %s: %w", compilationID, compilation.Status, ErrConflict)
+	}
+}
@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type compilationMocks struct {
+	repo      *repoMocks.MockCompilationRepository
+	audioRepo *repoMocks.MockAudioRepository
+	userRepo  *repoMocks.MockUserRepository
+	storage   *storageMocks.MockStorage
+	converter *converterMocks.MockAudioConverter
+	queue     *queueMocks.MockTaskQueue
+}
+
+func newCompilationTestUseCase(t *testing.T) (*CompilationUseCase, compilationMocks) {
+	m := compilationMocks{
+		repo:      repoMocks.NewMockCompilationRepository(t),
+		audioRepo: repoMocks.NewMockAudioRepository(t),
+		userRepo:  repoMocks.NewMockUserRepository(t),
+		storage:   storageMocks.NewMockStorage(t),
+		converter: converterMocks.NewMockAudioConverter(t),
+		queue:     queueMocks.NewMockTaskQueue(t),
+	}
+	return NewCompilationUseCase(m.repo, m.audioRepo, m.userRepo, m.storage, m.converter, m.queue), m
+}
+
+func encodedWAV(t *testing.T, sampleRate, channels int, samples ...float64) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, sampleRate, channels, samples))
+	return buf.Bytes()
+}
+
+// storedWAV returns a Download result serving the encoded samples afresh on
+// every call, as the compilation reads each recording twice.
+func storedWAV(t *testing.T, sampleRate, channels int, samples ...float64) func(context.Context, string) (io.ReadCloser, error) {
+	data := encodedWAV(t, sampleRate, channels, samples...)
+	return func(context.Context, string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+func TestCompilationUseCase_Create(t *testing.T) {
+	completed := func(phraseID uint) *entity.Audio {
+		return &entity.Audio{ID: phraseID + 10, UserID: 1, PhraseID: phraseID, Status: entity.AudioStatusCompleted}
+	}
+
+	tests := []struct {
+		name          string
+		req           CompilationRequest
+		setupMocks    func(compilationMocks)
+		expectedError error
+	}{
+		{
+			name: "queues compilation",
+			req:  CompilationRequest{PhraseIDs: []uint{2, 1}, Gap: 0.5},
+			setupMocks: func(m compilationMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(2)).Return(completed(2), nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(completed(1), nil)
+				m.repo.On("Create", mock.Anything, mock.MatchedBy(func(c *entity.Compilation) bool {
+					return c.UserID == 1 && c.Format == "wav" && c.Gap == 0.5 &&
+						c.Status == entity.CompilationStatusPending && assert.ObjectsAreEqual([]uint{2, 1}, c.PhraseIDs)
+				})).Return(&entity.Compilation{ID: 7}, nil)
+				m.queue.On("Enqueue", mock.Anything, uint(7)).Return(nil)
+			},
+		},
+		{
+			name:          "no phrases",
+			req:           CompilationRequest{},
+			setupMocks:    func(compilationMocks) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:          "duplicate phrase",
+			req:           CompilationRequest{PhraseIDs: []uint{1, 1}},
+			setupMocks:    func(compilationMocks) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:          "gap and crossfade",
+			req:           CompilationRequest{PhraseIDs: []uint{1}, Gap: 0.5, Crossfade: 0.1},
+			setupMocks:    func(compilationMocks) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:          "unsupported format",
+			req:           CompilationRequest{PhraseIDs: []uint{1}, Format: "exe"},
+			setupMocks:    func(compilationMocks) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:          "crossfade too long",
+			req:           CompilationRequest{PhraseIDs: []uint{1}, Crossfade: MaxCompilationCrossfade + 1},
+			setupMocks:    func(compilationMocks) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name: "unknown user",
+			req:  CompilationRequest{PhraseIDs: []uint{1}},
+			setupMocks: func(m compilationMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, repository.ErrNotFound)
+			},
+			expectedError: ErrNotFound,
+		},
+		{
+			name: "phrase not recorded",
+			req:  CompilationRequest{PhraseIDs: []uint{1}},
+			setupMocks: func(m compilationMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+			},
+			expectedError: ErrNotFound,
+		},
+		{
+			name: "phrase still converting",
+			req:  CompilationRequest{PhraseIDs: []uint{1}},
+			setupMocks: func(m compilationMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(&entity.Audio{Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedError: ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newCompilationTestUseCase(t)
+			tt.setupMocks(m)
+
+			compilation, err := uc.Create(context.Background(), 1, tt.req)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(7), compilation.ID)
+		})
+	}
+}
+
+func TestCompilationUseCase_Process(t *testing.T) {
+	first := &entity.Audio{ID: 11, UserID: 1, PhraseID: 1, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/11.wav"}
+	second := &entity.Audio{ID: 12, UserID: 1, PhraseID: 2, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/12.wav"}
+
+	t.Run("joins phrases and writes manifest", func(t *testing.T) {
+		uc, m := newCompilationTestUseCase(t)
+		compilation := &entity.Compilation{ID: 3, UserID: 1, PhraseIDs: []uint{1, 2}, Format: "wav", Gap: 0.5}
+
+		m.repo.On("GetByID", mock.Anything, uint(3)).Return(compilation, nil)
+		m.repo.On("Update", mock.Anything, mock.MatchedBy(func(c *entity.Compilation) bool {
+			return c.Status == entity.CompilationStatusProcessing
+		})).Return(nil).Once()
+		m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(first, nil)
+		m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(2)).Return(second, nil)
+		m.storage.On("Download", mock.Anything, first.StoragePath).Return(storedWAV(t, 4, 1, 0.5, 0.5))
+		m.storage.On("Download", mock.Anything, second.StoragePath).Return(storedWAV(t, 4, 2, 0.25, 0.25, 0.25, 0.25))
+
+		var stored []byte
+		m.storage.On("Upload", mock.Anything, "audio/compilations/3.wav", mock.Anything).Run(func(args mock.Arguments) {
+			stored, _ = io.ReadAll(args.Get(2).(io.Reader))
+		}).Return(nil)
+		var manifest entity.CompilationManifest
+		m.storage.On("Upload", mock.Anything, "audio/compilations/3.json", mock.Anything).Run(func(args mock.Arguments) {
+			require.NoError(t, json.NewDecoder(args.Get(2).(io.Reader)).Decode(&manifest))
+		}).Return(nil)
+		m.repo.On("Update", mock.Anything, mock.MatchedBy(func(c *entity.Compilation) bool {
+			return c.Status == entity.CompilationStatusCompleted &&
+				c.StoragePath == "audio/compilations/3.wav" && c.ManifestPath == "audio/compilations/3.json"
+		})).Return(nil).Once()
+
+		require.NoError(t, uc.Process(context.Background(), 3))
+
+		joined, err := wav.Decode(bytes.NewReader(stored))
+		require.NoError(t, err)
+		assert.Equal(t, 2, joined.Channels)
+		assert.InDeltaSlice(t, []float64{0.5, 0.5, 0.5, 0.5, 0, 0, 0, 0, 0.25, 0.25, 0.25, 0.25}, joined.Samples, 1.0/32767)
+
+		assert.Equal(t, 4, manifest.SampleRate)
+		assert.Equal(t, 2, manifest.Channels)
+		assert.Equal(t, 1.5, manifest.Duration)
+		assert.Equal(t, []entity.CompilationManifestEntry{
+			{PhraseID: 1, AudioID: 11, Start: 0, End: 0.5},
+			{PhraseID: 2, AudioID: 12, Start: 1, End: 1.5},
+		}, manifest.Phrases)
+	})
+
+	t.Run("resamples mismatched audio and converts output", func(t *testing.T) {
+		uc, m := newCompilationTestUseCase(t)
+		compilation := &entity.Compilation{ID: 3, UserID: 1, PhraseIDs: []uint{1, 2}, Format: "mp3"}
+
+		m.repo.On("GetByID", mock.Anything, uint(3)).Return(compilation, nil)
+		m.repo.On("Update", mock.Anything, mock.Anything).Return(nil).Twice()
+		m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(first, nil)
+		m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(2)).Return(second, nil)
+		m.storage.On("Download", mock.Anything, first.StoragePath).Return(storedWAV(t, 4, 1, 0.5))
+		m.storage.On("Download", mock.Anything, second.StoragePath).Return(storedWAV(t, 8, 1, 0.25, 0.25))
+		m.converter.On("ConvertFromReader", mock.Anything, mock.Anything, "wav", "wav", entity.Filter{
+			Name:   "aresample",
+			Params: map[string]string{"osr": "4"},
+		}).Return(io.NopCloser(bytes.NewReader(encodedWAV(t, 4, 1, 0.25))), nil)
+		m.converter.On("ConvertFromReader", mock.Anything, mock.Anything, "wav", "mp3").Return(io.NopCloser(bytes.NewReader([]byte("mp3"))), nil)
+		m.storage.On("Upload", mock.Anything, "audio/compilations/3.mp3", mock.Anything).Return(nil)
+		m.storage.On("Upload", mock.Anything, "audio/compilations/3.json", mock.Anything).Return(nil)
+
+		require.NoError(t, uc.Process(context.Background(), 3))
+		assert.Equal(t, entity.CompilationStatusCompleted, compilation.Status)
+	})
+
+	t.Run("mixes sample rates at the rate of the first take", func(t *testing.T) {
+		uc, m := newCompilationTestUseCase(t)
+		compilation := &entity.Compilation{ID: 3, UserID: 1, PhraseIDs: []uint{1, 2}, Format: "wav"}
+
+		m.repo.On("GetByID", mock.Anything, uint(3)).Return(compilation, nil)
+		m.repo.On("Update", mock.Anything, mock.Anything).Return(nil).Twice()
+		m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(first, nil)
+		m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(2)).Return(second, nil)
+		m.storage.On("Download", mock.Anything, first.StoragePath).Return(storedWAV(t, 4, 1, 0.5, 0.5))
+		m.storage.On("Download", mock.Anything, second.StoragePath).Return(storedWAV(t, 8, 1, 0.25, 0.25, 0.25, 0.25))
+		// Only the take at another rate is resampled, to the rate of the first.
+		m.converter.On("ConvertFromReader", mock.Anything, mock.Anything, "wav", "wav", entity.Filter{
+			Name:   "aresample",
+			Params: map[string]string{"osr": "4"},
+		}).Return(io.NopCloser(bytes.NewReader(encodedWAV(t, 4, 1, 0.25, 0.25))), nil).Once()
+
+		var stored []byte
+		m.storage.On("Upload", mock.Anything, "audio/compilations/3.wav", mock.Anything).Run(func(args mock.Arguments) {
+			stored, _ = io.ReadAll(args.Get(2).(io.Reader))
+		}).Return(nil)
+		var manifest entity.CompilationManifest
+		m.storage.On("Upload", mock.Anything, "audio/compilations/3.json", mock.Anything).Run(func(args mock.Arguments) {
+			require.NoError(t, json.NewDecoder(args.Get(2).(io.Reader)).Decode(&manifest))
+		}).Return(nil)
+
+		require.NoError(t, uc.Process(context.Background(), 3))
+
+		joined, err := wav.Decode(bytes.NewReader(stored))
+		require.NoError(t, err)
+		assert.Equal(t, 4, joined.SampleRate)
+		assert.InDeltaSlice(t, []float64{0.5, 0.5, 0.25, 0.25}, joined.Samples, 1.0/32767)
+		assert.Equal(t, 4, manifest.SampleRate)
+		assert.Equal(t, []entity.CompilationManifestEntry{
+			{PhraseID: 1, AudioID: 11, Start: 0, End: 0.5},
+			{PhraseID: 2, AudioID: 12, Start: 0.5, End: 1},
+		}, manifest.Phrases)
+	})
+
+	t.Run("records failure", func(t *testing.T) {
+		uc, m := newCompilationTestUseCase(t)
+		compilation := &entity.Compilation{ID: 3, UserID: 1, PhraseIDs: []uint{1}, Format: "wav"}
+
+		m.repo.On("GetByID", mock.Anything, uint(3)).Return(compilation, nil)
+		m.repo.On("Update", mock.Anything, mock.Anything).Return(nil).Twice()
+		m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(first, nil)
+		m.storage.On("Download", mock.Anything, first.StoragePath).Return(nil, fmt.Errorf("storage down"))
+
+		err := uc.Process(context.Background(), 3)
+		require.Error(t, err)
+		assert.Equal(t, entity.CompilationStatusFailed, compilation.Status)
+		assert.Contains(t, compilation.Error, "storage down")
+	})
+}
+
+func TestCompilationUseCase_Download(t *testing.T) {
+	tests := []struct {
+		name          string
+		compilation   *entity.Compilation
+		expectedError error
+	}{
+		{
+			name:        "completed",
+			compilation: &entity.Compilation{ID: 3, Status: entity.CompilationStatusCompleted, StoragePath: "audio/compilations/3.wav"},
+		},
+		{
+			name:          "still processing",
+			compilation:   &entity.Compilation{ID: 3, Status: entity.CompilationStatusProcessing},
+			expectedError: ErrConflict,
+		},
+		{
+			name:          "failed",
+			compilation:   &entity.Compilation{ID: 3, Status: entity.CompilationStatusFailed, Error: "boom"},
+			expectedError: ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newCompilationTestUseCase(t)
+			m.repo.On("GetByID", mock.Anything, uint(3)).Return(tt.compilation, nil)
+			if tt.expectedError == nil {
+				m.storage.On("Download", mock.Anything, tt.compilation.StoragePath).Return(io.NopCloser(bytes.NewReader(nil)), nil)
+			}
+
+			reader, compilation, err := uc.Download(context.Background(), 3)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			defer reader.Close()
+			assert.Equal(t, tt.compilation, compilation)
+		})
+	}
+
+	t.Run("unknown compilation", func(t *testing.T) {
+		uc, m := newCompilationTestUseCase(t)
+		m.repo.On("GetByID", mock.Anything, uint(3)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+
+		_, _, err := uc.Download(context.Background(), 3)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -4,33 +4,43 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/ardfard/sb-test/internal/domain/converter"
 	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/ardfard/sb-test/pkg/util"
 )
 
 type ConvertAudioUseCase struct {
-	repo      repository.AudioRepository
-	storage   storage.Storage
-	converter converter.AudioConverter
+	repo           repository.AudioRepository
+	storage        storage.Storage
+	converter      converter.AudioConverter
+	postProcessors []AudioPostProcessor
 }
 
 func NewConvertAudioUseCase(
 	repo repository.AudioRepository,
 	storage storage.Storage,
 	converter converter.AudioConverter,
+	postProcessors ...AudioPostProcessor,
 ) *ConvertAudioUseCase {
 	return &ConvertAudioUseCase{
-		repo:      repo,
-		storage:   storage,
-		converter: converter,
+		repo:           repo,
+		storage:        storage,
+		converter:      converter,
+		postProcessors: postProcessors,
 	}
 }
 
 const targetFormat = "wav"
 
+func convertedPath(audioID uint) string {
+	return fmt.Sprintf("%s/converted/%d.%s", basePath, audioID, targetFormat)
+}
+
 func (uc *ConvertAudioUseCase) Convert(ctx context.Context, audioID uint) error {
 	audio, err := uc.repo.GetByID(ctx, audioID)
 	if err != nil {
@@ -41,6 +51,9 @@ func (uc *ConvertAudioUseCase) Convert(ctx context.Context, audioID uint) error
 	// Update status to converting
 	audio.Status = entity.AudioStatusConverting
 	if err := uc.repo.Update(ctx, audio); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
 		return fmt.Errorf("failed to update audio status: %v", err)
 	}
 
@@ -58,17 +71,47 @@ func (uc *ConvertAudioUseCase) Convert(ctx context.Context, audioID uint) error
 	}
 	defer output.Close()
 
+	// Probe the converted file so the stored metadata describes what we keep
+	convertedFile, err := util.WriteTempFile(output, targetFormat)
+	if err != nil {
+		return uc.handleError(ctx, audio, fmt.Sprintf("failed to spool converted file: %v", err))
+	}
+	defer os.Remove(convertedFile)
+
+	meta, err := uc.converter.Probe(ctx, convertedFile)
+	if err != nil {
+		return uc.handleError(ctx, audio, fmt.Sprintf("failed to probe converted file: %v", err))
+	}
+
+	converted, err := os.Open(convertedFile)
+	if err != nil {
+		return uc.handleError(ctx, audio, fmt.Sprintf("failed to open converted file: %v", err))
+	}
+	defer converted.Close()
+
 	// Upload converted file
-	convertedPath := fmt.Sprintf("%s/converted/%d.%s", basePath, audio.ID, targetFormat)
-	if err := uc.storage.Upload(ctx, convertedPath, output); err != nil {
+	if err := uc.storage.Upload(ctx, convertedPath(audio.ID), converted); err != nil {
 		return uc.handleError(ctx, audio, fmt.Sprintf("failed to upload converted file: %v", err))
 	}
 
 	// Update audio status to completed
 	audio.Status = entity.AudioStatusCompleted
-	audio.StoragePath = convertedPath
-	audio.CurrentFormat = targetFormat
+	audio.StoragePath = convertedPath(audio.ID)
+	audio.ApplyMetadata(meta)
+
+	// Derived artifacts can be regenerated on demand, so a failing
+	// post-processor does not fail the conversion.
+	for _, p := range uc.postProcessors {
+		if err := p.Process(ctx, audio, convertedFile); err != nil {
+			logger.Errorf("Post-processing audio %d failed: %v", audio.ID, err)
+		}
+	}
 	if err := uc.repo.Update(ctx, audio); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// The audio was deleted while it was converted, along with
+			// the files it had then; drop the ones made since.
+			return uc.discard(ctx, audio, originalPath)
+		}
 		return fmt.Errorf("failed to update audio status: %v", err)
 	}
 
@@ -80,6 +123,18 @@ func (uc *ConvertAudioUseCase) Convert(ctx context.Context, audioID uint) error
 	return nil
 }
 
+// discard removes the files of an audio whose row is gone. The original is
+// removed by its path: a prefix could match a newer take of the phrase.
+func (uc *ConvertAudioUseCase) discard(ctx context.Context, audio *entity.Audio, originalPath string) error {
+	paths, _ := audioFiles(audio)
+	paths = append(paths, originalPath)
+	if err := deleteFiles(ctx, uc.storage, paths, []string{spectrogramDir(audio.ID)}); err != nil {
+		return fmt.Errorf("failed to discard files of deleted audio %d: %v", audio.ID, err)
+	}
+	logger.Infof("Audio %d was deleted during its conversion; discarded its files", audio.ID)
+	return nil
+}
+
 func (uc *ConvertAudioUseCase) handleError(ctx context.Context, audio *entity.Audio, errMsg string) error {
 	audio.Status = entity.AudioStatusFailed
 	audio.Error = errMsg
@@ -1,20 +1,27 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/ardfard/sb-test/internal/infrastructure/converter"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
 	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
 	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
 	"github.com/ardfard/sb-test/pkg/projectpath"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/ardfard/sb-test/pkg/waveform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConvertAudioUseCase_Convert(t *testing.T) {
@@ -107,3 +114,87 @@ func TestConvertAudioUseCase_Convert(t *testing.T) {
 		})
 	}
 }
+
+type postProcessorFunc func(ctx context.Context, audio *entity.Audio, path string) error
+
+func (f postProcessorFunc) Process(ctx context.Context, audio *entity.Audio, path string) error {
+	return f(ctx, audio, path)
+}
+
+func TestConvertAudioUseCase_PostProcessors(t *testing.T) {
+	repo := repoMocks.NewMockAudioRepository(t)
+	storage := storageMocks.NewMockStorage(t)
+	conv := converterMocks.NewMockAudioConverter(t)
+
+	var converted bytes.Buffer
+	require.NoError(t, wav.Encode(&converted, 8000, 1, make([]float64, 800)))
+
+	audio := &entity.Audio{ID: 7, CurrentFormat: "m4a", Status: entity.AudioStatusPending, StoragePath: "audio/original/1-1.m4a"}
+	repo.On("GetByID", mock.Anything, uint(7)).Return(audio, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	storage.On("Download", mock.Anything, "audio/original/1-1.m4a").Return(io.NopCloser(strings.NewReader("original")), nil)
+	storage.On("Upload", mock.Anything, "audio/converted/7.wav", mock.Anything).Return(nil)
+	storage.On("Upload", mock.Anything, "audio/waveform/7.dat", mock.Anything).Return(nil)
+	storage.On("Delete", mock.Anything, "audio/original/1-1.m4a").Return(nil)
+	conv.On("ConvertFromReader", mock.Anything, mock.Anything, "m4a", "wav").Return(io.NopCloser(&converted), nil)
+	conv.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "wav", SampleRate: 8000, Channels: 1}, nil)
+
+	var seenPath string
+	failing := postProcessorFunc(func(_ context.Context, a *entity.Audio, path string) error {
+		seenPath = path
+		assert.Equal(t, uint(7), a.ID)
+		assert.FileExists(t, path)
+		return assert.AnError
+	})
+	waveformUseCase := NewWaveformUseCase(repo, storage, waveform.Options{PixelsPerSecond: 10, Bits: 8, Channels: 1})
+
+	uc := NewConvertAudioUseCase(repo, storage, conv, failing, waveformUseCase)
+	require.NoError(t, uc.Convert(context.Background(), 7))
+
+	assert.NotEmpty(t, seenPath)
+	assert.NoFileExists(t, seenPath, "converted temp file should be removed")
+	assert.Equal(t, entity.AudioStatusCompleted, audio.Status)
+}
+
+func TestConvertAudioUseCase_DeletedAudio(t *testing.T) {
+	t.Run("before the conversion", func(t *testing.T) {
+		repo := repoMocks.NewMockAudioRepository(t)
+		audio := &entity.Audio{ID: 7, CurrentFormat: "m4a", Status: entity.AudioStatusPending, StoragePath: "audio/original/1-1.m4a"}
+		repo.On("GetByID", mock.Anything, uint(7)).Return(audio, nil)
+		repo.On("Update", mock.Anything, mock.Anything).Return(repository.ErrNotFound)
+
+		uc := NewConvertAudioUseCase(repo, storageMocks.NewMockStorage(t), converterMocks.NewMockAudioConverter(t))
+		assert.NoError(t, uc.Convert(context.Background(), 7))
+	})
+
+	t.Run("during the conversion", func(t *testing.T) {
+		repo := repoMocks.NewMockAudioRepository(t)
+		storage := storageMocks.NewMockStorage(t)
+		conv := converterMocks.NewMockAudioConverter(t)
+
+		var converted bytes.Buffer
+		require.NoError(t, wav.Encode(&converted, 8000, 1, make([]float64, 800)))
+
+		audio := &entity.Audio{ID: 7, UserID: 1, PhraseID: 1, CurrentFormat: "m4a", Status: entity.AudioStatusPending, StoragePath: "audio/original/1-1.m4a"}
+		repo.On("GetByID", mock.Anything, uint(7)).Return(audio, nil)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(a *entity.Audio) bool {
+			return a.Status == entity.AudioStatusConverting
+		})).Return(nil).Once()
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(a *entity.Audio) bool {
+			return a.Status == entity.AudioStatusCompleted
+		})).Return(fmt.Errorf("failed to update audio 7: %w", repository.ErrNotFound)).Once()
+		storage.On("Download", mock.Anything, "audio/original/1-1.m4a").Return(io.NopCloser(strings.NewReader("original")), nil)
+		storage.On("Upload", mock.Anything, "audio/converted/7.wav", mock.Anything).Return(nil)
+		var deleted []string
+		storage.On("Delete", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			deleted = append(deleted, args.String(1))
+		}).Return(nil)
+		storage.On("DeletePrefix", mock.Anything, "audio/spectrogram/7/").Return(nil)
+		conv.On("ConvertFromReader", mock.Anything, mock.Anything, "m4a", "wav").Return(io.NopCloser(&converted), nil)
+		conv.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "wav", SampleRate: 8000, Channels: 1}, nil)
+
+		uc := NewConvertAudioUseCase(repo, storage, conv)
+		require.NoError(t, uc.Convert(context.Background(), 7))
+		assert.Subset(t, deleted, []string{"audio/converted/7.wav", "audio/original/1-1.m4a"})
+	})
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/internal/domain/repository"
@@ -9,18 +10,27 @@ import (
 
 type CreatePhraseUseCase struct {
 	phraseRepository repository.PhraseRepository
+	userRepository   repository.UserRepository
 }
 
-func NewCreatePhraseUseCase(phraseRepository repository.PhraseRepository) *CreatePhraseUseCase {
+func NewCreatePhraseUseCase(phraseRepository repository.PhraseRepository, userRepository repository.UserRepository) *CreatePhraseUseCase {
 	return &CreatePhraseUseCase{
 		phraseRepository: phraseRepository,
+		userRepository:   userRepository,
 	}
 }
 
+// Create stores a new phrase owned by userID.
 func (uc *CreatePhraseUseCase) Create(ctx context.Context, text string, userID uint) (*entity.Phrase, error) {
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+
 	phrase := &entity.Phrase{
-		Phrase: text,
-		UserID: userID,
+		Phrase:    text,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 
 	phrase, err := uc.phraseRepository.Create(ctx, phrase)
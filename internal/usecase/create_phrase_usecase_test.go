@@ -3,9 +3,11 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
 	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
 	"github.com/ardfard/sb-test/internal/usecase"
 	"github.com/stretchr/testify/assert"
@@ -74,31 +76,24 @@ func TestCreatePhraseUseCase_Create(t *testing.T) {
 			},
 		},
 		{
-			name:   "zero user ID",
-			text:   "Test Phrase",
-			userID: 0,
-			mockSetup: func(repo *repoMocks.MockPhraseRepository) {
-				repo.On("Create", mock.Anything, mock.MatchedBy(func(phrase *entity.Phrase) bool {
-					return phrase.Phrase == "Test Phrase" && phrase.UserID == uint(0)
-				})).Return(&entity.Phrase{
-					ID:     1,
-					UserID: 0,
-					Phrase: "Test Phrase",
-				}, nil)
-			},
-			expectedError: nil,
-			expectedPhrase: &entity.Phrase{
-				ID:     1,
-				UserID: 0,
-				Phrase: "Test Phrase",
-			},
+			name:          "user not found",
+			text:          "Test Phrase",
+			userID:        9,
+			mockSetup:     func(repo *repoMocks.MockPhraseRepository) {},
+			expectedError: usecase.ErrNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Initialize mock repository
+			// Initialize mock repositories
 			mockPhraseRepo := repoMocks.NewMockPhraseRepository(t)
+			mockUserRepo := repoMocks.NewMockUserRepository(t)
+			if tt.userID == 1 {
+				mockUserRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+			} else {
+				mockUserRepo.On("GetByID", mock.Anything, tt.userID).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+			}
 
 			// Set up mock expectations
 			if tt.mockSetup != nil {
@@ -106,13 +101,15 @@ func TestCreatePhraseUseCase_Create(t *testing.T) {
 			}
 
 			// Create use case
-			useCase := usecase.NewCreatePhraseUseCase(mockPhraseRepo)
+			useCase := usecase.NewCreatePhraseUseCase(mockPhraseRepo, mockUserRepo)
 
 			// Execute use case
 			phrase, err := useCase.Create(context.Background(), tt.text, tt.userID)
 
 			// Check error
-			if tt.expectedError != nil {
+			if errors.Is(tt.expectedError, usecase.ErrNotFound) {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError.Error(), err.Error())
 			} else {
@@ -130,6 +127,7 @@ func TestCreatePhraseUseCase_Create(t *testing.T) {
 
 func TestNewCreatePhraseUseCase(t *testing.T) {
 	mockPhraseRepo := repoMocks.NewMockPhraseRepository(t)
-	useCase := usecase.NewCreatePhraseUseCase(mockPhraseRepo)
+	mockUserRepo := repoMocks.NewMockUserRepository(t)
+	useCase := usecase.NewCreatePhraseUseCase(mockPhraseRepo, mockUserRepo)
 	assert.NotNil(t, useCase)
 }
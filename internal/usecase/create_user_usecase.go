@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/internal/domain/repository"
@@ -9,20 +11,38 @@ import (
 
 type CreateUserUseCase struct {
 	userRepository repository.UserRepository
+	profiles       ProcessingProfiles
+	speakerSchema  SpeakerSchema
 }
 
-func NewCreateUserUseCase(userRepository repository.UserRepository) *CreateUserUseCase {
+func NewCreateUserUseCase(userRepository repository.UserRepository, profiles ProcessingProfiles, speakerSchema SpeakerSchema) *CreateUserUseCase {
 	return &CreateUserUseCase{
 		userRepository: userRepository,
+		profiles:       profiles,
+		speakerSchema:  speakerSchema,
 	}
 }
 
-func (uc *CreateUserUseCase) Create(ctx context.Context, name string) (*entity.User, error) {
+// Create adds a user, optionally described by speaker attributes of the
+// configured schema.
+func (uc *CreateUserUseCase) Create(ctx context.Context, name, processingProfile string, attributes map[string]string) (*entity.User, error) {
+	if !uc.profiles.Has(processingProfile) {
+		return nil, fmt.Errorf("unknown processing profile %q: %w", processingProfile, ErrInvalidArgument)
+	}
+	attributes, err := uc.speakerSchema.normalize(attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	user := &entity.User{
-		Name: name,
+		Name:              name,
+		ProcessingProfile: processingProfile,
+		Attributes:        attributes,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
 	}
 
-	user, err := uc.userRepository.Create(ctx, user)
+	user, err = uc.userRepository.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -112,10 +112,10 @@ func TestCreateUserUseCase_Create(t *testing.T) {
 			}
 
 			// Create use case
-			useCase := usecase.NewCreateUserUseCase(mockUserRepo)
+			useCase := usecase.NewCreateUserUseCase(mockUserRepo, nil, nil)
 
 			// Execute use case
-			user, err := useCase.Create(context.Background(), tt.userName)
+			user, err := useCase.Create(context.Background(), tt.userName, "", nil)
 
 			// Check error
 			if tt.expectedError != nil {
@@ -136,6 +136,43 @@ func TestCreateUserUseCase_Create(t *testing.T) {
 
 func TestNewCreateUserUseCase(t *testing.T) {
 	mockUserRepo := repoMocks.NewMockUserRepository(t)
-	useCase := usecase.NewCreateUserUseCase(mockUserRepo)
+	useCase := usecase.NewCreateUserUseCase(mockUserRepo, nil, nil)
 	assert.NotNil(t, useCase)
 }
+
+func TestCreateUserUseCase_CreateWithProcessingProfile(t *testing.T) {
+	profiles := usecase.ProcessingProfiles{"clean": {{Name: "highpass", Params: map[string]string{"f": "80"}}}}
+	mockUserRepo := repoMocks.NewMockUserRepository(t)
+	mockUserRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+		return user.ProcessingProfile == "clean"
+	})).Return(&entity.User{ID: 1, Name: "John Doe", ProcessingProfile: "clean"}, nil)
+
+	useCase := usecase.NewCreateUserUseCase(mockUserRepo, profiles, nil)
+
+	user, err := useCase.Create(context.Background(), "John Doe", "clean", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "clean", user.ProcessingProfile)
+
+	_, err = useCase.Create(context.Background(), "John Doe", "radio", nil)
+	assert.ErrorIs(t, err, usecase.ErrInvalidArgument)
+}
+
+func TestCreateUserUseCase_CreateWithSpeakerAttributes(t *testing.T) {
+	speakerSchema := usecase.SpeakerSchema{"age_range": {Values: []string{"18-24", "25-34"}}, "native_language": {}}
+	mockUserRepo := repoMocks.NewMockUserRepository(t)
+	mockUserRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+		return assert.ObjectsAreEqual(map[string]string{"age_range": "25-34", "native_language": "Welsh"}, user.Attributes)
+	})).Return(&entity.User{ID: 1, Name: "John Doe", Attributes: map[string]string{"age_range": "25-34", "native_language": "Welsh"}}, nil)
+
+	useCase := usecase.NewCreateUserUseCase(mockUserRepo, nil, speakerSchema)
+
+	user, err := useCase.Create(context.Background(), "John Doe", "", map[string]string{"age_range": "25-34", "native_language": " Welsh\t"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Welsh", user.Attributes["native_language"])
+
+	_, err = useCase.Create(context.Background(), "John Doe", "", map[string]string{"age_range": "40-49"})
+	assert.ErrorIs(t, err, usecase.ErrInvalidArgument)
+
+	_, err = useCase.Create(context.Background(), "John Doe", "", map[string]string{"native_language": "Wel\x00sh"})
+	assert.ErrorIs(t, err, usecase.ErrInvalidArgument)
+}
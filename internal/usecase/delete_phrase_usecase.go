@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+)
+
+// DeletePhraseUseCase removes a phrase with every take of it, by any user,
+// and their stored files, along with the pending resumable uploads of it.
+type DeletePhraseUseCase struct {
+	phraseRepository repository.PhraseRepository
+	audioRepository  repository.AudioRepository
+	uploadRepository repository.UploadRepository
+	storage          storage.Storage
+	stagingArea      storage.StagingArea
+	conversionQueue  queue.TaskQueue
+}
+
+func NewDeletePhraseUseCase(
+	phraseRepository repository.PhraseRepository,
+	audioRepository repository.AudioRepository,
+	uploadRepository repository.UploadRepository,
+	storage storage.Storage,
+	stagingArea storage.StagingArea,
+	conversionQueue queue.TaskQueue,
+) *DeletePhraseUseCase {
+	return &DeletePhraseUseCase{
+		phraseRepository: phraseRepository,
+		audioRepository:  audioRepository,
+		uploadRepository: uploadRepository,
+		storage:          storage,
+		stagingArea:      stagingArea,
+		conversionQueue:  conversionQueue,
+	}
+}
+
+// Delete lets the owner of a phrase remove it. The files of the phrase are
+// removed first and its rows last, so a failed deletion can be retried.
+func (uc *DeletePhraseUseCase) Delete(ctx context.Context, ownerID, phraseID uint) error {
+	phrase, err := ownedPhrase(ctx, uc.phraseRepository, phraseID, ownerID)
+	if err != nil {
+		return err
+	}
+	audios, err := uc.audioRepository.ListByPhraseID(ctx, phraseID)
+	if err != nil {
+		return fmt.Errorf("failed to list audio: %v", err)
+	}
+	uploads, err := uc.uploadRepository.ListByPhraseID(ctx, phraseID)
+	if err != nil {
+		return fmt.Errorf("failed to list uploads: %v", err)
+	}
+
+	var paths, prefixes []string
+	for _, audio := range audios {
+		if err := uc.conversionQueue.Cancel(ctx, audio.ID); err != nil {
+			return fmt.Errorf("failed to cancel conversion of audio %d: %v", audio.ID, err)
+		}
+		audioPaths, audioPrefixes := audioFiles(audio)
+		paths = append(paths, audioPaths...)
+		prefixes = append(prefixes, audioPrefixes...)
+	}
+	if phrase.ReferencePath != "" {
+		paths = append(paths, phrase.ReferencePath)
+	}
+	if err := deleteFiles(ctx, uc.storage, paths, prefixes); err != nil {
+		return err
+	}
+	for _, upload := range uploads {
+		if err := uc.stagingArea.Delete(ctx, upload.ID); err != nil {
+			return fmt.Errorf("failed to delete staged upload %s: %v", upload.ID, err)
+		}
+	}
+
+	if err := uc.phraseRepository.Delete(ctx, phraseID); err != nil {
+		return wrapRepoError(err, "failed to delete phrase")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletePhraseUseCase_Delete(t *testing.T) {
+	t.Run("removes takes, files and staged uploads", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		audioRepo := repoMocks.NewMockAudioRepository(t)
+		uploadRepo := repoMocks.NewMockUploadRepository(t)
+		store := storageMocks.NewMockStorage(t)
+		stagingArea := storageMocks.NewMockStagingArea(t)
+		conversionQueue := queueMocks.NewMockTaskQueue(t)
+
+		phraseRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Phrase{ID: 3, UserID: 1, ReferencePath: referencePath(3)}, nil)
+		audioRepo.On("ListByPhraseID", mock.Anything, uint(3)).Return([]*entity.Audio{
+			{ID: 10, UserID: 1, PhraseID: 3, StoragePath: convertedPath(10)},
+			{ID: 11, UserID: 2, PhraseID: 3, StoragePath: originalPath(2, 3, "m4a")},
+		}, nil)
+		// Another speaker is still uploading a take of the phrase.
+		uploadRepo.On("ListByPhraseID", mock.Anything, uint(3)).Return([]*entity.Upload{{ID: "01UP", UserID: 4, PhraseID: 3}}, nil)
+		conversionQueue.On("Cancel", mock.Anything, uint(10)).Return(nil)
+		conversionQueue.On("Cancel", mock.Anything, uint(11)).Return(nil)
+		for _, path := range []string{
+			convertedPath(10), waveformPath(10), pitchPath(10),
+			originalPath(2, 3, "m4a"), waveformPath(11), pitchPath(11), convertedPath(11),
+			referencePath(3),
+		} {
+			store.On("Delete", mock.Anything, path).Return(fmt.Errorf("gone: %w", storage.ErrObjectNotFound))
+		}
+		for _, prefix := range []string{originalPath(1, 3, ""), spectrogramDir(10), originalPath(2, 3, ""), spectrogramDir(11)} {
+			store.On("DeletePrefix", mock.Anything, prefix).Return(nil)
+		}
+		stagingArea.On("Delete", mock.Anything, "01UP").Return(nil)
+		phraseRepo.On("Delete", mock.Anything, uint(3)).Return(nil)
+
+		uc := NewDeletePhraseUseCase(phraseRepo, audioRepo, uploadRepo, store, stagingArea, conversionQueue)
+		require.NoError(t, uc.Delete(context.Background(), 1, 3))
+	})
+
+	t.Run("not the owner", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Phrase{ID: 3, UserID: 1}, nil)
+
+		// Other speakers' takes of the phrase do not make it theirs
+		uc := NewDeletePhraseUseCase(phraseRepo, repoMocks.NewMockAudioRepository(t), repoMocks.NewMockUploadRepository(t),
+			storageMocks.NewMockStorage(t), storageMocks.NewMockStagingArea(t), queueMocks.NewMockTaskQueue(t))
+		assert.ErrorIs(t, uc.Delete(context.Background(), 2, 3), ErrForbidden)
+	})
+
+	t.Run("unknown phrase", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(3)).Return(nil, fmt.Errorf("failed to get phrase 3: %w", repository.ErrNotFound))
+
+		uc := NewDeletePhraseUseCase(phraseRepo, repoMocks.NewMockAudioRepository(t), repoMocks.NewMockUploadRepository(t),
+			storageMocks.NewMockStorage(t), storageMocks.NewMockStagingArea(t), queueMocks.NewMockTaskQueue(t))
+		assert.ErrorIs(t, uc.Delete(context.Background(), 1, 3), ErrNotFound)
+	})
+}
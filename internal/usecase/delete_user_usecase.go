@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+)
+
+// DeleteUserUseCase removes a user with the audio they recorded, the
+// phrases they own that nobody else recorded, the queued tasks for that
+// audio and every stored file. Phrases other users recorded are handed to
+// the first of them, so their takes are kept. The removal is carried out by
+// a worker consuming the queue the use case enqueues to, and tracked as an
+// entity.UserDeletion.
+type DeleteUserUseCase struct {
+	repo             repository.UserDeletionRepository
+	userRepo         repository.UserRepository
+	phraseRepo       repository.PhraseRepository
+	audioRepo        repository.AudioRepository
+	compilationRepo  repository.CompilationRepository
+	uploadRepo       repository.UploadRepository
+	storage          storage.Storage
+	stagingArea      storage.StagingArea
+	queue            queue.TaskQueue
+	conversionQueue  queue.TaskQueue
+	compilationQueue queue.TaskQueue
+}
+
+func NewDeleteUserUseCase(
+	repo repository.UserDeletionRepository,
+	userRepo repository.UserRepository,
+	phraseRepo repository.PhraseRepository,
+	audioRepo repository.AudioRepository,
+	compilationRepo repository.CompilationRepository,
+	uploadRepo repository.UploadRepository,
+	storage storage.Storage,
+	stagingArea storage.StagingArea,
+	queue queue.TaskQueue,
+	conversionQueue queue.TaskQueue,
+	compilationQueue queue.TaskQueue,
+) *DeleteUserUseCase {
+	return &DeleteUserUseCase{
+		repo:             repo,
+		userRepo:         userRepo,
+		phraseRepo:       phraseRepo,
+		audioRepo:        audioRepo,
+		compilationRepo:  compilationRepo,
+		uploadRepo:       uploadRepo,
+		storage:          storage,
+		stagingArea:      stagingArea,
+		queue:            queue,
+		conversionQueue:  conversionQueue,
+		compilationQueue: compilationQueue,
+	}
+}
+
+// Delete queues the removal of a user. The user is marked as being deleted
+// first, so that no takes are uploaded for them while the removal waits.
+func (uc *DeleteUserUseCase) Delete(ctx context.Context, userID uint) (*entity.UserDeletion, error) {
+	if err := uc.userRepo.MarkDeleting(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to mark user as deleting")
+	}
+
+	deletion, err := uc.repo.Create(ctx, &entity.UserDeletion{
+		UserID:    userID,
+		Status:    entity.UserDeletionStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store user deletion: %v", err)
+	}
+
+	if err := uc.queue.Enqueue(ctx, deletion.ID); err != nil {
+		return nil, fmt.Errorf("failed to enqueue user deletion: %v", err)
+	}
+	return deletion, nil
+}
+
+// Get returns a user deletion.
+func (uc *DeleteUserUseCase) Get(ctx context.Context, deletionID uint) (*entity.UserDeletion, error) {
+	deletion, err := uc.repo.GetByID(ctx, deletionID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get user deletion")
+	}
+	return deletion, nil
+}
+
+// Process carries out a queued user deletion.
+func (uc *DeleteUserUseCase) Process(ctx context.Context, deletionID uint) error {
+	deletion, err := uc.repo.GetByID(ctx, deletionID)
+	if err != nil {
+		return fmt.Errorf("failed to get user deletion: %v", err)
+	}
+
+	deletion.Status = entity.UserDeletionStatusProcessing
+	if err := uc.repo.Update(ctx, deletion); err != nil {
+		return fmt.Errorf("failed to update user deletion status: %v", err)
+	}
+
+	if err := uc.purge(ctx, deletion); err != nil {
+		deletion.Status = entity.UserDeletionStatusFailed
+		deletion.Error = err.Error()
+		if err := uc.repo.Update(ctx, deletion); err != nil {
+			return fmt.Errorf("failed to update user deletion status: %v", err)
+		}
+		return err
+	}
+
+	deletion.Status = entity.UserDeletionStatusCompleted
+	deletion.Error = ""
+	if err := uc.repo.Update(ctx, deletion); err != nil {
+		return fmt.Errorf("failed to update user deletion status: %v", err)
+	}
+	return nil
+}
+
+// purge removes the files of the user first and their rows last, so a
+// failed deletion can be retried from the start.
+func (uc *DeleteUserUseCase) purge(ctx context.Context, deletion *entity.UserDeletion) error {
+	userID := deletion.UserID
+	owned, err := uc.phraseRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list phrases: %v", err)
+	}
+	audios, err := uc.audioRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list audio: %v", err)
+	}
+	// Phrases recorded by other users outlive the user with their takes;
+	// the repository hands them over when the user is deleted.
+	var phrases []*entity.Phrase
+	for _, phrase := range owned {
+		takes, err := uc.audioRepo.ListByPhraseID(ctx, phrase.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list audio of phrase %d: %v", phrase.ID, err)
+		}
+		if !recordedByOthers(takes, userID) {
+			phrases = append(phrases, phrase)
+		}
+	}
+	compilations, err := uc.compilationRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list compilations: %v", err)
+	}
+	uploads, err := uc.uploadRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list uploads: %v", err)
+	}
+
+	// Cancel queued work first so that nothing writes new files.
+	for _, audio := range audios {
+		if err := uc.conversionQueue.Cancel(ctx, audio.ID); err != nil {
+			return fmt.Errorf("failed to cancel conversion of audio %d: %v", audio.ID, err)
+		}
+	}
+	for _, compilation := range compilations {
+		if err := uc.compilationQueue.Cancel(ctx, compilation.ID); err != nil {
+			return fmt.Errorf("failed to cancel compilation %d: %v", compilation.ID, err)
+		}
+	}
+
+	var paths, prefixes []string
+	for _, audio := range audios {
+		audioPaths, audioPrefixes := audioFiles(audio)
+		paths = append(paths, audioPaths...)
+		prefixes = append(prefixes, audioPrefixes...)
+	}
+	for _, phrase := range phrases {
+		if phrase.ReferencePath != "" {
+			paths = append(paths, phrase.ReferencePath)
+		}
+	}
+	for _, compilation := range compilations {
+		paths = append(paths, compilationPath(compilation.ID, compilation.Format), compilationPath(compilation.ID, "json"))
+	}
+	if err := deleteFiles(ctx, uc.storage, paths, prefixes); err != nil {
+		return err
+	}
+	for _, upload := range uploads {
+		if err := uc.stagingArea.Delete(ctx, upload.ID); err != nil {
+			return fmt.Errorf("failed to delete staged upload %s: %v", upload.ID, err)
+		}
+	}
+
+	if err := uc.userRepo.Delete(ctx, userID); err != nil {
+		return wrapRepoError(err, "failed to delete user")
+	}
+	deletion.Phrases = len(phrases)
+	deletion.Audio = len(audios)
+	return nil
+}
+
+// recordedByOthers reports whether any of the takes is not by userID.
+func recordedByOthers(takes []*entity.Audio, userID uint) bool {
+	for _, take := range takes {
+		if take.UserID != userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type deleteUserMocks struct {
+	repo             *repoMocks.MockUserDeletionRepository
+	userRepo         *repoMocks.MockUserRepository
+	phraseRepo       *repoMocks.MockPhraseRepository
+	audioRepo        *repoMocks.MockAudioRepository
+	compilationRepo  *repoMocks.MockCompilationRepository
+	uploadRepo       *repoMocks.MockUploadRepository
+	storage          *storageMocks.MockStorage
+	stagingArea      *storageMocks.MockStagingArea
+	queue            *queueMocks.MockTaskQueue
+	conversionQueue  *queueMocks.MockTaskQueue
+	compilationQueue *queueMocks.MockTaskQueue
+}
+
+func newDeleteUserTestUseCase(t *testing.T) (*DeleteUserUseCase, deleteUserMocks) {
+	m := deleteUserMocks{
+		repo:             repoMocks.NewMockUserDeletionRepository(t),
+		userRepo:         repoMocks.NewMockUserRepository(t),
+		phraseRepo:       repoMocks.NewMockPhraseRepository(t),
+		audioRepo:        repoMocks.NewMockAudioRepository(t),
+		compilationRepo:  repoMocks.NewMockCompilationRepository(t),
+		uploadRepo:       repoMocks.NewMockUploadRepository(t),
+		storage:          storageMocks.NewMockStorage(t),
+		stagingArea:      storageMocks.NewMockStagingArea(t),
+		queue:            queueMocks.NewMockTaskQueue(t),
+		conversionQueue:  queueMocks.NewMockTaskQueue(t),
+		compilationQueue: queueMocks.NewMockTaskQueue(t),
+	}
+	uc := NewDeleteUserUseCase(m.repo, m.userRepo, m.phraseRepo, m.audioRepo, m.compilationRepo, m.uploadRepo,
+		m.storage, m.stagingArea, m.queue, m.conversionQueue, m.compilationQueue)
+	return uc, m
+}
+
+func TestDeleteUserUseCase_Delete(t *testing.T) {
+	t.Run("queues the deletion", func(t *testing.T) {
+		uc, m := newDeleteUserTestUseCase(t)
+		m.userRepo.On("MarkDeleting", mock.Anything, uint(1)).Return(nil)
+		m.repo.On("Create", mock.Anything, mock.MatchedBy(func(d *entity.UserDeletion) bool {
+			return d.UserID == 1 && d.Status == entity.UserDeletionStatusPending
+		})).Return(&entity.UserDeletion{ID: 5, UserID: 1, Status: entity.UserDeletionStatusPending}, nil)
+		m.queue.On("Enqueue", mock.Anything, uint(5)).Return(nil)
+
+		deletion, err := uc.Delete(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, uint(5), deletion.ID)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		uc, m := newDeleteUserTestUseCase(t)
+		m.userRepo.On("MarkDeleting", mock.Anything, uint(1)).Return(repository.ErrNotFound)
+
+		_, err := uc.Delete(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestDeleteUserUseCase_Process(t *testing.T) {
+	// Phrase 2 was also recorded by user 4 and is handed over to them, phrase
+	// 3 was only recorded by the user and goes with them.
+	recorded := &entity.Phrase{ID: 2, UserID: 1, ReferencePath: "audio/references/2.wav"}
+	phrase := &entity.Phrase{ID: 3, UserID: 1, ReferencePath: "audio/references/3.wav"}
+	own := &entity.Audio{ID: 10, UserID: 1, PhraseID: 3, StoragePath: "audio/converted/10.wav", NormalizedPath: "audio/normalized/10.wav"}
+	othersTake := &entity.Audio{ID: 11, UserID: 4, PhraseID: 2, StoragePath: "audio/original/4-2.m4a"}
+
+	expectLists := func(m deleteUserMocks) {
+		m.phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return([]*entity.Phrase{recorded, phrase}, nil)
+		m.audioRepo.On("ListByUserID", mock.Anything, uint(1)).Return([]*entity.Audio{own}, nil)
+		m.audioRepo.On("ListByPhraseID", mock.Anything, uint(2)).Return([]*entity.Audio{othersTake}, nil)
+		m.audioRepo.On("ListByPhraseID", mock.Anything, uint(3)).Return([]*entity.Audio{own}, nil)
+		m.compilationRepo.On("ListByUserID", mock.Anything, uint(1)).Return([]*entity.Compilation{{ID: 6, UserID: 1, Format: "mp3"}}, nil)
+		m.uploadRepo.On("ListByUserID", mock.Anything, uint(1)).Return([]*entity.Upload{{ID: "01UP", UserID: 1}}, nil)
+		m.conversionQueue.On("Cancel", mock.Anything, uint(10)).Return(nil)
+		m.compilationQueue.On("Cancel", mock.Anything, uint(6)).Return(nil)
+	}
+
+	t.Run("removes files, tasks and rows", func(t *testing.T) {
+		uc, m := newDeleteUserTestUseCase(t)
+		deletion := &entity.UserDeletion{ID: 5, UserID: 1, Status: entity.UserDeletionStatusPending}
+		m.repo.On("GetByID", mock.Anything, uint(5)).Return(deletion, nil)
+		m.repo.On("Update", mock.Anything, mock.MatchedBy(func(d *entity.UserDeletion) bool {
+			return d.Status == entity.UserDeletionStatusProcessing
+		})).Return(nil).Once()
+		expectLists(m)
+
+		var deleted []string
+		m.storage.On("Delete", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			deleted = append(deleted, args.String(1))
+		}).Return(fmt.Errorf("missing: %w", storage.ErrObjectNotFound))
+		var prefixes []string
+		m.storage.On("DeletePrefix", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			prefixes = append(prefixes, args.String(1))
+		}).Return(nil)
+		m.stagingArea.On("Delete", mock.Anything, "01UP").Return(nil)
+		m.userRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+		m.repo.On("Update", mock.Anything, mock.MatchedBy(func(d *entity.UserDeletion) bool {
+			return d.Status == entity.UserDeletionStatusCompleted && d.Phrases == 1 && d.Audio == 1
+		})).Return(nil).Once()
+
+		require.NoError(t, uc.Process(context.Background(), 5))
+
+		assert.Subset(t, deleted, []string{
+			"audio/converted/10.wav", "audio/normalized/10.wav", "audio/waveform/10.dat", "audio/pitch/10.json",
+			"audio/references/3.wav", "audio/compilations/6.mp3", "audio/compilations/6.json",
+		})
+		for _, kept := range []string{"audio/original/4-2.m4a", "audio/converted/11.wav", "audio/references/2.wav"} {
+			assert.NotContains(t, deleted, kept)
+		}
+		assert.ElementsMatch(t, []string{"audio/original/1-3.", "audio/spectrogram/10/"}, prefixes)
+	})
+
+	t.Run("records failure and keeps the rows", func(t *testing.T) {
+		uc, m := newDeleteUserTestUseCase(t)
+		deletion := &entity.UserDeletion{ID: 5, UserID: 1, Status: entity.UserDeletionStatusPending}
+		m.repo.On("GetByID", mock.Anything, uint(5)).Return(deletion, nil)
+		m.repo.On("Update", mock.Anything, mock.Anything).Return(nil).Twice()
+		expectLists(m)
+		m.storage.On("Delete", mock.Anything, mock.Anything).Return(fmt.Errorf("storage down"))
+
+		err := uc.Process(context.Background(), 5)
+		require.Error(t, err)
+		assert.Equal(t, entity.UserDeletionStatusFailed, deletion.Status)
+		assert.Contains(t, deletion.Error, "storage down")
+		m.userRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
@@ -1,21 +1,46 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 
 	"github.com/ardfard/sb-test/internal/domain/converter"
+	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/wav"
 )
 
+// Audio variants that can be downloaded. The canonical variant is the
+// converted audio as recorded; the normalized variant is its loudness
+// normalized copy, when loudness normalization is enabled.
+const (
+	VariantCanonical  = "canonical"
+	VariantNormalized = "normalized"
+)
+
+// DownloadOptions selects what is downloaded for an audio.
+type DownloadOptions struct {
+	// Variant is VariantCanonical (the default when empty) or VariantNormalized.
+	Variant string
+	// Trim cuts the variant to the recorded trim offsets. When nil, audio
+	// with trim offsets is trimmed if trimming is enabled for conversions.
+	Trim *bool
+	// Profile names the processing profile to apply. When empty the user's
+	// profile is used; ProfileNone applies none.
+	Profile string
+}
+
 type DownloadAudioUseCase struct {
 	repo             repository.AudioRepository
 	storage          storage.Storage
 	converter        converter.AudioConverter
 	userRepository   repository.UserRepository
 	phraseRepository repository.PhraseRepository
+	profiles         ProcessingProfiles
+	trimByDefault    bool
 }
 
 func NewDownloadAudioUseCase(
@@ -24,6 +49,8 @@ func NewDownloadAudioUseCase(
 	converter converter.AudioConverter,
 	userRepository repository.UserRepository,
 	phraseRepository repository.PhraseRepository,
+	profiles ProcessingProfiles,
+	trimByDefault bool,
 ) *DownloadAudioUseCase {
 	return &DownloadAudioUseCase{
 		repo:             repo,
@@ -31,41 +58,67 @@ func NewDownloadAudioUseCase(
 		converter:        converter,
 		userRepository:   userRepository,
 		phraseRepository: phraseRepository,
+		profiles:         profiles,
+		trimByDefault:    trimByDefault,
 	}
 }
 
-func (uc *DownloadAudioUseCase) Download(ctx context.Context, userID uint, phraseID uint, outputFormat string) (io.ReadCloser, error) {
-	// check user and phrase exist
+func (uc *DownloadAudioUseCase) Download(ctx context.Context, userID uint, phraseID uint, outputFormat string, opts DownloadOptions) (io.ReadCloser, error) {
+	// check the user exists and may access the phrase
 	user, err := uc.userRepository.GetByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %v", err)
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	if _, err := authorizePhrase(ctx, uc.phraseRepository, phraseID, userID); err != nil {
+		return nil, err
 	}
 
-	phrase, err := uc.phraseRepository.GetByID(ctx, phraseID)
+	filters, err := uc.profiles.filters(opts.Profile, user.ProcessingProfile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get phrase: %v", err)
+		return nil, err
 	}
 
-	if user == nil || phrase == nil {
-		return nil, fmt.Errorf("user or phrase not found")
+	audio, err := uc.repo.GetByUserIDAndPhraseID(ctx, userID, phraseID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
 	}
 
-	audio, err := uc.repo.GetByUserIDAndPhraseID(ctx, userID, phraseID)
+	path, format := audio.StoragePath, audio.CurrentFormat
+	switch opts.Variant {
+	case "", VariantCanonical:
+	case VariantNormalized:
+		if audio.NormalizedPath == "" {
+			return nil, fmt.Errorf("audio %d has no normalized version: %w", audio.ID, ErrNotFound)
+		}
+		path, format = audio.NormalizedPath, "wav"
+	default:
+		return nil, fmt.Errorf("unknown variant %q: %w", opts.Variant, ErrInvalidArgument)
+	}
+
+	trimmed, err := uc.trims(audio, opts.Trim)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get audio: %v", err)
+		return nil, err
 	}
 
-	reader, err := uc.storage.Download(ctx, audio.StoragePath)
+	reader, err := uc.storage.Download(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download original file: %v", err)
 	}
 
-	// If the requested format is the same as the stored format, return the file without conversion
-	if outputFormat == audio.CurrentFormat {
+	if trimmed {
+		reader, err = trim(reader, *audio.TrimStart, *audio.TrimEnd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// If the requested format is the same as the stored format and there is
+	// nothing to process, return the file without conversion
+	if outputFormat == format && len(filters) == 0 {
 		return reader, nil
 	}
 
-	output, err := uc.converter.ConvertFromReader(ctx, reader, audio.CurrentFormat, outputFormat)
+	output, err := uc.converter.ConvertFromReader(ctx, reader, format, outputFormat, filters...)
 	if err != nil {
 		reader.Close()
 		return nil, fmt.Errorf("failed to convert audio: %v", err)
@@ -73,3 +126,55 @@ func (uc *DownloadAudioUseCase) Download(ctx context.Context, userID uint, phras
 
 	return output, nil
 }
+
+// servesStored tells whether downloading audio in outputFormat with opts
+// returns the stored file as it is, which can then be served from storage
+// directly.
+func (uc *DownloadAudioUseCase) servesStored(ctx context.Context, audio *entity.Audio, outputFormat string, opts DownloadOptions) (bool, error) {
+	if outputFormat != audio.CurrentFormat || (opts.Variant != "" && opts.Variant != VariantCanonical) {
+		return false, nil
+	}
+	if trimmed, err := uc.trims(audio, opts.Trim); err != nil || trimmed {
+		return false, err
+	}
+	user, err := uc.userRepository.GetByID(ctx, audio.UserID)
+	if err != nil {
+		return false, wrapRepoError(err, "failed to get user")
+	}
+	filters, err := uc.profiles.filters(opts.Profile, user.ProcessingProfile)
+	if err != nil {
+		return false, err
+	}
+	return len(filters) == 0, nil
+}
+
+// trims tells whether audio is downloaded trimmed when trim is requested.
+// Audio converted before trimming was enabled has no offsets and is then
+// downloaded whole, unless trimming was asked for explicitly.
+func (uc *DownloadAudioUseCase) trims(audio *entity.Audio, trim *bool) (bool, error) {
+	hasOffsets := audio.TrimStart != nil && audio.TrimEnd != nil
+	if trim == nil {
+		return uc.trimByDefault && hasOffsets, nil
+	}
+	if *trim && !hasOffsets {
+		return false, fmt.Errorf("audio %d has no trim offsets: %w", audio.ID, ErrNotFound)
+	}
+	return *trim, nil
+}
+
+// trim cuts the WAV in reader down to the range between start and end
+// seconds, skipping the frames before it rather than decoding them.
+func trim(reader io.ReadCloser, start, end float64) (io.ReadCloser, error) {
+	defer reader.Close()
+
+	kept, err := wav.DecodeRange(reader, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audio: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := wav.Encode(&out, kept.SampleRate, kept.Channels, kept.Samples); err != nil {
+		return nil, fmt.Errorf("failed to encode trimmed audio: %v", err)
+	}
+	return io.NopCloser(&out), nil
+}
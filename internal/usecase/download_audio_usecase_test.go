@@ -1,30 +1,65 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/internal/infrastructure/converter"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
 	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
 	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
 	"github.com/ardfard/sb-test/pkg/projectpath"
+	"github.com/ardfard/sb-test/pkg/wav"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestDownloadAudioUseCase_Download(t *testing.T) {
 	testFile := filepath.Join(projectpath.RootProject, "tests", "fixtures", "test.wav")
+	yes, no := true, false
+	trimmable := func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+		var source bytes.Buffer
+		if err := wav.Encode(&source, 4, 1, []float64{0, 0.5, -0.5, 0.25, 0, 0}); err != nil {
+			t.Fatal(err)
+		}
+		audio := &entity.Audio{
+			ID:            1,
+			Status:        entity.AudioStatusCompleted,
+			StoragePath:   fmt.Sprintf("%s/converted/1.wav", basePath),
+			CurrentFormat: "wav",
+		}
+		audio.ApplyTrim(0.25, 1)
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+		repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(audio, nil)
+		storage.On("Download", mock.Anything, fmt.Sprintf("%s/converted/1.wav", basePath)).
+			Return(io.NopCloser(&source), nil)
+	}
+	expectSamples := func(expected []float64) func(*testing.T, io.ReadCloser, error) {
+		return func(t *testing.T, reader io.ReadCloser, err error) {
+			if !assert.NoError(t, err) {
+				return
+			}
+			decoded, err := wav.Decode(reader)
+			assert.NoError(t, err)
+			assert.InDeltaSlice(t, expected, decoded.Samples, 1.0/32767)
+		}
+	}
 
 	tests := []struct {
 		name          string
 		userID        uint
 		phraseID      uint
 		format        string
+		opts          DownloadOptions
+		trimByDefault bool
 		setupMocks    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage, *repoMocks.MockUserRepository, *repoMocks.MockPhraseRepository)
 		expectedError bool
 		checkResult   func(*testing.T, io.ReadCloser, error)
@@ -47,7 +82,7 @@ func TestDownloadAudioUseCase_Download(t *testing.T) {
 				}
 
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
-				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
 				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(audio, nil)
 				storage.On("Download", mock.Anything, fmt.Sprintf("%s/converted/1.wav", basePath)).
 					Return(inputReader, nil)
@@ -79,7 +114,7 @@ func TestDownloadAudioUseCase_Download(t *testing.T) {
 				}
 
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
-				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
 				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(audio, nil)
 				storage.On("Download", mock.Anything, fmt.Sprintf("%s/original/1.wav", basePath)).
 					Return(inputReader, nil)
@@ -113,7 +148,7 @@ func TestDownloadAudioUseCase_Download(t *testing.T) {
 				}
 
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
-				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
 				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(audio, nil)
 				storage.On("Download", mock.Anything, fmt.Sprintf("%s/original/1.wav", basePath)).
 					Return(inputReader, nil)
@@ -128,6 +163,128 @@ func TestDownloadAudioUseCase_Download(t *testing.T) {
 				assert.NotEmpty(t, content)
 			},
 		},
+		{
+			name:     "download normalized variant",
+			userID:   1,
+			phraseID: 1,
+			format:   "wav",
+			opts:     DownloadOptions{Variant: VariantNormalized},
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				audio := &entity.Audio{
+					ID:             1,
+					Status:         entity.AudioStatusCompleted,
+					StoragePath:    fmt.Sprintf("%s/converted/1.wav", basePath),
+					NormalizedPath: fmt.Sprintf("%s/normalized/1.wav", basePath),
+					CurrentFormat:  "wav",
+				}
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(audio, nil)
+				storage.On("Download", mock.Anything, fmt.Sprintf("%s/normalized/1.wav", basePath)).
+					Return(io.NopCloser(strings.NewReader("normalized")), nil)
+			},
+			checkResult: func(t *testing.T, reader io.ReadCloser, err error) {
+				assert.NoError(t, err)
+				content, err := io.ReadAll(reader)
+				assert.NoError(t, err)
+				assert.Equal(t, "normalized", string(content))
+			},
+		},
+		{
+			name:     "normalized variant not available",
+			userID:   1,
+			phraseID: 1,
+			format:   "wav",
+			opts:     DownloadOptions{Variant: VariantNormalized},
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(&entity.Audio{ID: 1, CurrentFormat: "wav"}, nil)
+			},
+			expectedError: true,
+			checkResult: func(t *testing.T, reader io.ReadCloser, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		{
+			name:     "unknown variant",
+			userID:   1,
+			phraseID: 1,
+			format:   "wav",
+			opts:     DownloadOptions{Variant: "louder"},
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(&entity.Audio{ID: 1, CurrentFormat: "wav"}, nil)
+			},
+			expectedError: true,
+			checkResult: func(t *testing.T, reader io.ReadCloser, err error) {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+			},
+		},
+		{
+			name:        "download trimmed audio",
+			userID:      1,
+			phraseID:    1,
+			format:      "wav",
+			opts:        DownloadOptions{Trim: &yes},
+			setupMocks:  trimmable,
+			checkResult: expectSamples([]float64{0.5, -0.5, 0.25}),
+		},
+		{
+			name:          "trimmed by default when trimming is enabled",
+			userID:        1,
+			phraseID:      1,
+			format:        "wav",
+			trimByDefault: true,
+			setupMocks:    trimmable,
+			checkResult:   expectSamples([]float64{0.5, -0.5, 0.25}),
+		},
+		{
+			name:          "untrimmed on request",
+			userID:        1,
+			phraseID:      1,
+			format:        "wav",
+			opts:          DownloadOptions{Trim: &no},
+			trimByDefault: true,
+			setupMocks:    trimmable,
+			checkResult:   expectSamples([]float64{0, 0.5, -0.5, 0.25, 0, 0}),
+		},
+		{
+			name:          "whole by default without offsets",
+			userID:        1,
+			phraseID:      1,
+			format:        "wav",
+			trimByDefault: true,
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				var source bytes.Buffer
+				if err := wav.Encode(&source, 4, 1, []float64{0, 0.5}); err != nil {
+					t.Fatal(err)
+				}
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).
+					Return(&entity.Audio{ID: 1, StoragePath: "audio/converted/1.wav", CurrentFormat: "wav"}, nil)
+				storage.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(&source), nil)
+			},
+			checkResult: expectSamples([]float64{0, 0.5}),
+		},
+		{
+			name:     "trim without offsets",
+			userID:   1,
+			phraseID: 1,
+			format:   "wav",
+			opts:     DownloadOptions{Trim: &yes},
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(&entity.Audio{ID: 1, CurrentFormat: "wav"}, nil)
+			},
+			expectedError: true,
+			checkResult: func(t *testing.T, reader io.ReadCloser, err error) {
+				assert.ErrorIs(t, err, ErrNotFound)
+			},
+		},
 		{
 			name:     "audio not found",
 			userID:   999,
@@ -135,7 +292,7 @@ func TestDownloadAudioUseCase_Download(t *testing.T) {
 			format:   "wav",
 			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
 				userRepo.On("GetByID", mock.Anything, uint(999)).Return(&entity.User{ID: 999}, nil)
-				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 999}, nil)
 				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(999), uint(1)).Return(nil, assert.AnError)
 			},
 			expectedError: true,
@@ -171,6 +328,22 @@ func TestDownloadAudioUseCase_Download(t *testing.T) {
 				assert.Error(t, err)
 			},
 		},
+		{
+			name:     "phrase not shared with user",
+			userID:   1,
+			phraseID: 2,
+			format:   "wav",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+				phraseRepo.On("IsSharedWith", mock.Anything, uint(2), uint(1)).Return(false, nil)
+			},
+			expectedError: true,
+			checkResult: func(t *testing.T, reader io.ReadCloser, err error) {
+				assert.ErrorIs(t, err, ErrForbidden)
+				assert.Nil(t, reader)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -183,8 +356,8 @@ func TestDownloadAudioUseCase_Download(t *testing.T) {
 
 			tt.setupMocks(repo, storage, userRepo, phraseRepo)
 
-			uc := NewDownloadAudioUseCase(repo, storage, audioConverter, userRepo, phraseRepo)
-			reader, err := uc.Download(context.Background(), tt.userID, tt.phraseID, tt.format)
+			uc := NewDownloadAudioUseCase(repo, storage, audioConverter, userRepo, phraseRepo, nil, tt.trimByDefault)
+			reader, err := uc.Download(context.Background(), tt.userID, tt.phraseID, tt.format, tt.opts)
 
 			tt.checkResult(t, reader, err)
 
@@ -194,3 +367,77 @@ func TestDownloadAudioUseCase_Download(t *testing.T) {
 		})
 	}
 }
+
+func TestDownloadAudioUseCase_Profiles(t *testing.T) {
+	profiles := ProcessingProfiles{
+		"clean":     {{Name: "highpass", Params: map[string]string{"f": "80"}}},
+		"telephone": {{Name: "lowpass", Params: map[string]string{"f": "3400"}}},
+	}
+	audio := &entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/1.wav", CurrentFormat: "wav"}
+
+	tests := []struct {
+		name          string
+		userProfile   string
+		opts          DownloadOptions
+		expected      []entity.Filter
+		expectedError error
+	}{
+		{
+			name:        "user profile",
+			userProfile: "clean",
+			expected:    profiles["clean"],
+		},
+		{
+			name:        "requested profile overrides user profile",
+			userProfile: "clean",
+			opts:        DownloadOptions{Profile: "telephone"},
+			expected:    profiles["telephone"],
+		},
+		{
+			name:        "no profile",
+			userProfile: "clean",
+			opts:        DownloadOptions{Profile: ProfileNone},
+		},
+		{
+			name:          "unknown profile",
+			opts:          DownloadOptions{Profile: "radio"},
+			expectedError: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			storage := storageMocks.NewMockStorage(t)
+			audioConverter := converterMocks.NewMockAudioConverter(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+
+			userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, ProcessingProfile: tt.userProfile}, nil)
+			phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+			if tt.expectedError == nil {
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(audio, nil)
+				storage.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(strings.NewReader("canonical")), nil)
+			}
+			if tt.expected != nil {
+				audioConverter.On("ConvertFromReader", mock.Anything, mock.Anything, "wav", "wav", tt.expected[0]).
+					Return(io.NopCloser(strings.NewReader("processed")), nil)
+			}
+
+			uc := NewDownloadAudioUseCase(repo, storage, audioConverter, userRepo, phraseRepo, profiles, false)
+			reader, err := uc.Download(context.Background(), 1, 1, "wav", tt.opts)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			content, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			if tt.expected != nil {
+				assert.Equal(t, "processed", string(content))
+			} else {
+				assert.Equal(t, "canonical", string(content))
+			}
+		})
+	}
+}
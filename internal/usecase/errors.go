@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// Sentinel errors returned (wrapped) by use cases so that delivery layers can
+// map them to the appropriate response.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrForbidden       = errors.New("forbidden")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrGone            = errors.New("no longer available")
+	ErrConflict        = errors.New("conflict")
+	// ErrTooLarge, ErrUnsupportedMediaType and ErrUnprocessable classify
+	// rejected uploads; see UploadRejectedError.
+	ErrTooLarge             = errors.New("too large")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrUnprocessable        = errors.New("unprocessable")
+)
+
+// wrapRepoError annotates a repository error, translating repository.ErrNotFound
+// into ErrNotFound.
+func wrapRepoError(err error, msg string) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%s: %w", msg, ErrNotFound)
+	}
+	return fmt.Errorf("%s: %v", msg, err)
+}
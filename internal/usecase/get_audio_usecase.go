@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+type GetAudioUseCase struct {
+	repo repository.AudioRepository
+}
+
+func NewGetAudioUseCase(repo repository.AudioRepository) *GetAudioUseCase {
+	return &GetAudioUseCase{
+		repo: repo,
+	}
+}
+
+// Get returns the audio record, including its probed metadata.
+func (uc *GetAudioUseCase) Get(ctx context.Context, audioID uint) (*entity.Audio, error) {
+	audio, err := uc.repo.GetByID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	return audio, nil
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// PhrasePage is one page of a phrase listing.
+type PhrasePage struct {
+	Phrases []*entity.Phrase
+	Total   int // Matching phrases across all pages
+	Limit   int
+	Offset  int
+}
+
+type GetPhraseUseCase struct {
+	phraseRepository repository.PhraseRepository
+	userRepository   repository.UserRepository
+}
+
+func NewGetPhraseUseCase(phraseRepository repository.PhraseRepository, userRepository repository.UserRepository) *GetPhraseUseCase {
+	return &GetPhraseUseCase{
+		phraseRepository: phraseRepository,
+		userRepository:   userRepository,
+	}
+}
+
+// Get returns a phrase.
+func (uc *GetPhraseUseCase) Get(ctx context.Context, phraseID uint) (*entity.Phrase, error) {
+	phrase, err := uc.phraseRepository.GetByID(ctx, phraseID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get phrase")
+	}
+	return phrase, nil
+}
+
+// List returns a page of the phrases the user in the filter owns. A zero
+// limit selects DefaultPageSize.
+func (uc *GetPhraseUseCase) List(ctx context.Context, filter repository.PhraseFilter) (*PhrasePage, error) {
+	limit, err := pageLimit(filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit = limit
+	switch filter.AudioStatus {
+	case "", entity.AudioStatusPending, entity.AudioStatusConverting, entity.AudioStatusCompleted, entity.AudioStatusFailed:
+	default:
+		return nil, fmt.Errorf("unknown audio status %q: %w", filter.AudioStatus, ErrInvalidArgument)
+	}
+
+	if _, err := uc.userRepository.GetByID(ctx, filter.UserID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	phrases, total, err := uc.phraseRepository.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list phrases: %v", err)
+	}
+	return &PhrasePage{Phrases: phrases, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+// ListRevisions returns the texts a phrase had before its edits, oldest
+// first.
+func (uc *GetPhraseUseCase) ListRevisions(ctx context.Context, phraseID uint) ([]*entity.PhraseRevision, error) {
+	if _, err := uc.phraseRepository.GetByID(ctx, phraseID); err != nil {
+		return nil, wrapRepoError(err, "failed to get phrase")
+	}
+	revisions, err := uc.phraseRepository.ListRevisions(ctx, phraseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list phrase revisions: %v", err)
+	}
+	return revisions, nil
+}
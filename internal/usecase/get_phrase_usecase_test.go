@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPhraseUseCase_Get(t *testing.T) {
+	phraseRepo := repoMocks.NewMockPhraseRepository(t)
+	phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, Phrase: "hello"}, nil)
+	phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("failed to get phrase 2: %w", repository.ErrNotFound))
+	uc := usecase.NewGetPhraseUseCase(phraseRepo, repoMocks.NewMockUserRepository(t))
+
+	phrase, err := uc.Get(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", phrase.Phrase)
+
+	_, err = uc.Get(context.Background(), 2)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+	_, err = uc.ListRevisions(context.Background(), 2)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+}
+
+func TestGetPhraseUseCase_List(t *testing.T) {
+	yes := true
+	tests := []struct {
+		name           string
+		filter         repository.PhraseFilter
+		userExists     bool
+		expectedFilter repository.PhraseFilter
+		expectedError  error
+	}{
+		{
+			name:           "default page size",
+			filter:         repository.PhraseFilter{UserID: 1, HasAudio: &yes},
+			userExists:     true,
+			expectedFilter: repository.PhraseFilter{UserID: 1, HasAudio: &yes, Limit: usecase.DefaultPageSize},
+		},
+		{
+			name:           "audio status",
+			filter:         repository.PhraseFilter{UserID: 1, AudioStatus: entity.AudioStatusFailed, Limit: 10, Offset: 20},
+			userExists:     true,
+			expectedFilter: repository.PhraseFilter{UserID: 1, AudioStatus: entity.AudioStatusFailed, Limit: 10, Offset: 20},
+		},
+		{
+			name:          "unknown audio status",
+			filter:        repository.PhraseFilter{UserID: 1, AudioStatus: "lost"},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "limit too large",
+			filter:        repository.PhraseFilter{UserID: 1, Limit: usecase.MaxPageSize + 1},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "unknown user",
+			filter:        repository.PhraseFilter{UserID: 1},
+			expectedError: usecase.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			if tt.userExists {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("List", mock.Anything, tt.expectedFilter).Return([]*entity.Phrase{{ID: 3, UserID: 1}}, 21, nil)
+			} else if tt.expectedError == usecase.ErrNotFound {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get user 1: %w", repository.ErrNotFound))
+			}
+			uc := usecase.NewGetPhraseUseCase(phraseRepo, userRepo)
+
+			page, err := uc.List(context.Background(), tt.filter)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 21, page.Total)
+			assert.Equal(t, tt.expectedFilter.Limit, page.Limit)
+			assert.Len(t, page.Phrases, 1)
+		})
+	}
+}
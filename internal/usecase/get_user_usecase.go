@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// UserPage is one page of a user listing.
+type UserPage struct {
+	Users  []*entity.User
+	Total  int // Matching users across all pages
+	Limit  int
+	Offset int
+}
+
+type GetUserUseCase struct {
+	userRepository repository.UserRepository
+}
+
+func NewGetUserUseCase(userRepository repository.UserRepository) *GetUserUseCase {
+	return &GetUserUseCase{
+		userRepository: userRepository,
+	}
+}
+
+// Get returns a user.
+func (uc *GetUserUseCase) Get(ctx context.Context, userID uint) (*entity.User, error) {
+	user, err := uc.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	return user, nil
+}
+
+// List returns a page of the users whose name contains name, ignoring case.
+// A zero limit selects DefaultPageSize.
+func (uc *GetUserUseCase) List(ctx context.Context, name string, limit, offset int) (*UserPage, error) {
+	limit, err := pageLimit(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	users, total, err := uc.userRepository.List(ctx, repository.UserFilter{Name: name, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %v", err)
+	}
+	return &UserPage{Users: users, Total: total, Limit: limit, Offset: offset}, nil
+}
@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserUseCase_Get(t *testing.T) {
+	repo := repoMocks.NewMockUserRepository(t)
+	repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Name: "John Doe"}, nil)
+	repo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("failed to get user 2: %w", repository.ErrNotFound))
+	uc := usecase.NewGetUserUseCase(repo)
+
+	user, err := uc.Get(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.Name)
+
+	_, err = uc.Get(context.Background(), 2)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+}
+
+func TestGetUserUseCase_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		limit          int
+		offset         int
+		expectedFilter repository.UserFilter
+		expectedError  error
+	}{
+		{
+			name:           "default page size",
+			expectedFilter: repository.UserFilter{Name: "jo", Limit: usecase.DefaultPageSize},
+		},
+		{
+			name:           "explicit page",
+			limit:          10,
+			offset:         20,
+			expectedFilter: repository.UserFilter{Name: "jo", Limit: 10, Offset: 20},
+		},
+		{
+			name:          "limit too large",
+			limit:         usecase.MaxPageSize + 1,
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "negative offset",
+			offset:        -1,
+			expectedError: usecase.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockUserRepository(t)
+			if tt.expectedError == nil {
+				repo.On("List", mock.Anything, tt.expectedFilter).Return([]*entity.User{{ID: 1, Name: "John"}}, 21, nil)
+			}
+			uc := usecase.NewGetUserUseCase(repo)
+
+			page, err := uc.List(context.Background(), "jo", tt.limit, tt.offset)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 21, page.Total)
+			assert.Equal(t, tt.expectedFilter.Limit, page.Limit)
+			assert.Len(t, page.Users, 1)
+		})
+	}
+}
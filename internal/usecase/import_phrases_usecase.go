@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"golang.org/x/text/unicode/norm"
+)
+
+// PhraseImportFormat is the layout of an imported list of phrases.
+type PhraseImportFormat string
+
+const (
+	// PhraseImportCSV and PhraseImportTSV have a header row naming their
+	// columns: text, and optionally id, language and tags.
+	PhraseImportCSV PhraseImportFormat = "csv"
+	PhraseImportTSV PhraseImportFormat = "tsv"
+	// PhraseImportText has one phrase per line.
+	PhraseImportText PhraseImportFormat = "text"
+)
+
+// Limits on imports.
+const (
+	MaxImportPhrases = 10000
+	MaxPhraseTags    = 20
+)
+
+// PhraseImportAction is what an import does with a row.
+type PhraseImportAction string
+
+const (
+	PhraseImportCreated   PhraseImportAction = "created"
+	PhraseImportUpdated   PhraseImportAction = "updated"
+	PhraseImportUnchanged PhraseImportAction = "unchanged"
+	// PhraseImportDuplicate rows have no ID and the text of a phrase the user
+	// already owns or of an earlier row; they are skipped.
+	PhraseImportDuplicate PhraseImportAction = "duplicate"
+)
+
+// PhraseImportRow is the outcome of one row of an import.
+type PhraseImportRow struct {
+	Line int // Line of the row in the input, from 1
+	// PhraseID is the phrase the row created, updated or duplicates. It is
+	// zero for rows that create a phrase, or duplicate one, in a dry run.
+	PhraseID uint
+	Text     string // Normalized text
+	Action   PhraseImportAction
+}
+
+// PhraseImportReport tells what an import changed or, in a dry run, would
+// change.
+type PhraseImportReport struct {
+	DryRun     bool
+	Created    int
+	Updated    int
+	Unchanged  int
+	Duplicates int
+	Rows       []PhraseImportRow
+}
+
+// importRow is a parsed row. Language and tags are only applied to existing
+// phrases when their column is present.
+type importRow struct {
+	line        int
+	id          uint
+	text        string
+	language    string
+	tags        []string
+	hasLanguage bool
+	hasTags     bool
+}
+
+// ImportPhrasesUseCase creates and updates many phrases of a user at once.
+type ImportPhrasesUseCase struct {
+	phraseRepository repository.PhraseRepository
+	userRepository   repository.UserRepository
+}
+
+func NewImportPhrasesUseCase(phraseRepository repository.PhraseRepository, userRepository repository.UserRepository) *ImportPhrasesUseCase {
+	return &ImportPhrasesUseCase{
+		phraseRepository: phraseRepository,
+		userRepository:   userRepository,
+	}
+}
+
+// ParsePhraseImportFormat returns the format named s.
+func ParsePhraseImportFormat(s string) (PhraseImportFormat, error) {
+	switch format := PhraseImportFormat(strings.ToLower(s)); format {
+	case PhraseImportCSV, PhraseImportTSV, PhraseImportText:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown import format %q: %w", s, ErrInvalidArgument)
+}
+
+// Import reads phrases in format from content for userID. Rows with an ID
+// update that phrase of the user; other rows create a phrase unless the user
+// already has one with the same text. Texts and tags are trimmed, have their
+// whitespace collapsed and are normalized to Unicode NFC. Either every row is
+// applied or, if any is invalid, none is; a dry run applies none and reports
+// what would change.
+func (uc *ImportPhrasesUseCase) Import(ctx context.Context, userID uint, format PhraseImportFormat, content io.Reader, dryRun bool) (*PhraseImportReport, error) {
+	rows, err := parsePhraseImport(format, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no phrases to import: %w", ErrInvalidArgument)
+	}
+
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	owned, err := uc.phraseRepository.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list phrases: %v", err)
+	}
+	byID := make(map[uint]*entity.Phrase, len(owned))
+	byText := make(map[string]uint, len(owned))
+	for _, phrase := range owned {
+		byID[phrase.ID] = phrase
+		byText[normalizeText(phrase.Phrase)] = phrase.ID
+	}
+
+	report := &PhraseImportReport{DryRun: dryRun, Rows: make([]PhraseImportRow, 0, len(rows))}
+	var created, changed []*entity.Phrase
+	// createdBy maps the text of each phrase to create to its row.
+	createdBy := map[string]int{}
+	seen := map[uint]int{}
+	now := time.Now()
+	for _, row := range rows {
+		result := PhraseImportRow{Line: row.line, Text: row.text}
+		if row.id == 0 {
+			if id, ok := byText[row.text]; ok {
+				result.PhraseID = id
+				result.Action = PhraseImportDuplicate
+			} else if _, ok := createdBy[row.text]; ok {
+				result.Action = PhraseImportDuplicate
+			} else {
+				createdBy[row.text] = len(report.Rows)
+				result.Action = PhraseImportCreated
+				created = append(created, &entity.Phrase{
+					UserID:    userID,
+					Phrase:    row.text,
+					Language:  row.language,
+					Tags:      row.tags,
+					CreatedAt: now,
+					UpdatedAt: now,
+				})
+			}
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		phrase, ok := byID[row.id]
+		if !ok {
+			return nil, fmt.Errorf("line %d: user %d has no phrase %d: %w", row.line, userID, row.id, ErrInvalidArgument)
+		}
+		if line, ok := seen[row.id]; ok {
+			return nil, fmt.Errorf("line %d: phrase %d is already imported on line %d: %w", row.line, row.id, line, ErrInvalidArgument)
+		}
+		seen[row.id] = row.line
+		result.PhraseID = row.id
+		update := *phrase
+		if row.text != normalizeText(phrase.Phrase) {
+			update.Phrase = row.text
+		}
+		if row.hasLanguage {
+			update.Language = row.language
+		}
+		if row.hasTags {
+			update.Tags = row.tags
+		}
+		if update.Phrase == phrase.Phrase && update.Language == phrase.Language && slices.Equal(update.Tags, phrase.Tags) {
+			result.Action = PhraseImportUnchanged
+		} else {
+			result.Action = PhraseImportUpdated
+			changed = append(changed, &update)
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if !dryRun && (len(created) > 0 || len(changed) > 0) {
+		stored, err := uc.phraseRepository.Import(ctx, created, changed)
+		if err != nil {
+			return nil, wrapRepoError(err, "failed to import phrases")
+		}
+		for _, phrase := range stored {
+			report.Rows[createdBy[phrase.Phrase]].PhraseID = phrase.ID
+		}
+		for i, result := range report.Rows {
+			if result.Action == PhraseImportDuplicate && result.PhraseID == 0 {
+				report.Rows[i].PhraseID = report.Rows[createdBy[result.Text]].PhraseID
+			}
+		}
+	}
+
+	for _, result := range report.Rows {
+		switch result.Action {
+		case PhraseImportCreated:
+			report.Created++
+		case PhraseImportUpdated:
+			report.Updated++
+		case PhraseImportUnchanged:
+			report.Unchanged++
+		case PhraseImportDuplicate:
+			report.Duplicates++
+		}
+	}
+	return report, nil
+}
+
+// normalizeText trims s, collapses its whitespace to single spaces and
+// normalizes it to Unicode NFC.
+func normalizeText(s string) string {
+	return norm.NFC.String(strings.Join(strings.Fields(s), " "))
+}
+
+func parsePhraseImport(format PhraseImportFormat, content io.Reader) ([]importRow, error) {
+	switch format {
+	case PhraseImportCSV:
+		return parsePhraseTable(content, ',')
+	case PhraseImportTSV:
+		return parsePhraseTable(content, '\t')
+	case PhraseImportText:
+		return parsePhraseLines(content)
+	}
+	return nil, fmt.Errorf("unknown import format %q: %w", format, ErrInvalidArgument)
+}
+
+// readError annotates an error reading an import, classifying malformed input
+// as an invalid argument.
+func readError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) || errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("failed to read phrases: %v: %w", err, ErrInvalidArgument)
+	}
+	return fmt.Errorf("failed to read phrases: %w", err)
+}
+
+// parsePhraseLines reads one phrase per line, skipping blank lines.
+func parsePhraseLines(content io.Reader) ([]importRow, error) {
+	var rows []importRow
+	scanner := bufio.NewScanner(content)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		text = normalizeText(text)
+		if text == "" {
+			continue
+		}
+		if len(rows) == MaxImportPhrases {
+			return nil, fmt.Errorf("an import holds at most %d phrases: %w", MaxImportPhrases, ErrInvalidArgument)
+		}
+		rows = append(rows, importRow{line: line, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, readError(err)
+	}
+	return rows, nil
+}
+
+// parsePhraseTable reads a table with a header row. Tags are separated by
+// commas within their cell.
+func parsePhraseTable(content io.Reader, comma rune) ([]importRow, error) {
+	reader := csv.NewReader(content)
+	reader.Comma = comma
+	reader.LazyQuotes = comma == '\t'
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, readError(err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "id", "text", "language", "tags":
+		default:
+			return nil, fmt.Errorf("unknown column %q: %w", name, ErrInvalidArgument)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("duplicate column %q: %w", name, ErrInvalidArgument)
+		}
+		columns[name] = i
+	}
+	textColumn, ok := columns["text"]
+	if !ok {
+		return nil, fmt.Errorf("missing text column: %w", ErrInvalidArgument)
+	}
+	idColumn, hasID := columns["id"]
+	languageColumn, hasLanguage := columns["language"]
+	tagsColumn, hasTags := columns["tags"]
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, readError(err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == MaxImportPhrases {
+			return nil, fmt.Errorf("an import holds at most %d phrases: %w", MaxImportPhrases, ErrInvalidArgument)
+		}
+
+		row := importRow{line: line, text: normalizeText(record[textColumn]), hasLanguage: hasLanguage, hasTags: hasTags}
+		if row.text == "" {
+			return nil, fmt.Errorf("line %d: text is empty: %w", line, ErrInvalidArgument)
+		}
+		if hasID {
+			if cell := strings.TrimSpace(record[idColumn]); cell != "" {
+				id, err := strconv.ParseUint(cell, 10, 64)
+				if err != nil || id == 0 {
+					return nil, fmt.Errorf("line %d: invalid id %q: %w", line, cell, ErrInvalidArgument)
+				}
+				row.id = uint(id)
+			}
+		}
+		if hasLanguage {
+			row.language = strings.TrimSpace(record[languageColumn])
+		}
+		if hasTags {
+			row.tags = []string{}
+			for _, tag := range strings.Split(record[tagsColumn], ",") {
+				if tag = normalizeText(tag); tag != "" && !slices.Contains(row.tags, tag) {
+					row.tags = append(row.tags, tag)
+				}
+			}
+			if len(row.tags) > MaxPhraseTags {
+				return nil, fmt.Errorf("line %d: a phrase has at most %d tags, got %d: %w", line, MaxPhraseTags, len(row.tags), ErrInvalidArgument)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
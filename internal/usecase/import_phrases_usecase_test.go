@@ -0,0 +1,154 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// importCSV creates a decomposed "Café", keeps "Hello", edits phrase 2 and
+// repeats texts that are already there.
+const importCSV = "id,text,language,tags\n" +
+	",  Cafe\u0301 ,fr,\"greeting, short,greeting\"\n" +
+	"1,Hello,,\n" +
+	"2,Good  evening,en-GB,\n" +
+	",hello,,\n" +
+	",Hello,,\n" +
+	",Café,,\n"
+
+func ownedPhrases() []*entity.Phrase {
+	return []*entity.Phrase{
+		{ID: 1, UserID: 1, Phrase: "Hello", Tags: []string{}},
+		{ID: 2, UserID: 1, Phrase: "Good night", Tags: []string{"night"}},
+	}
+}
+
+func TestImportPhrasesUseCase_Import(t *testing.T) {
+	phraseRepo := repoMocks.NewMockPhraseRepository(t)
+	userRepo := repoMocks.NewMockUserRepository(t)
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return(ownedPhrases(), nil)
+	phraseRepo.On("Import", mock.Anything,
+		mock.MatchedBy(func(created []*entity.Phrase) bool {
+			return len(created) == 2 && created[0].Phrase == "Café" && created[0].Language == "fr" &&
+				assert.ObjectsAreEqual([]string{"greeting", "short"}, created[0].Tags) && created[1].Phrase == "hello"
+		}),
+		mock.MatchedBy(func(changed []*entity.Phrase) bool {
+			return len(changed) == 1 && changed[0].ID == 2 && changed[0].Phrase == "Good evening" &&
+				changed[0].Language == "en-GB" && len(changed[0].Tags) == 0
+		}),
+	).Return([]*entity.Phrase{{ID: 10, Phrase: "Café"}, {ID: 11, Phrase: "hello"}}, nil)
+	uc := usecase.NewImportPhrasesUseCase(phraseRepo, userRepo)
+
+	report, err := uc.Import(context.Background(), 1, usecase.PhraseImportCSV, strings.NewReader(importCSV), false)
+	require.NoError(t, err)
+	assert.Equal(t, &usecase.PhraseImportReport{
+		Created:    2,
+		Updated:    1,
+		Unchanged:  1,
+		Duplicates: 2,
+		Rows: []usecase.PhraseImportRow{
+			{Line: 2, PhraseID: 10, Text: "Café", Action: usecase.PhraseImportCreated},
+			{Line: 3, PhraseID: 1, Text: "Hello", Action: usecase.PhraseImportUnchanged},
+			{Line: 4, PhraseID: 2, Text: "Good evening", Action: usecase.PhraseImportUpdated},
+			{Line: 5, PhraseID: 11, Text: "hello", Action: usecase.PhraseImportCreated},
+			{Line: 6, PhraseID: 1, Text: "Hello", Action: usecase.PhraseImportDuplicate},
+			{Line: 7, PhraseID: 10, Text: "Café", Action: usecase.PhraseImportDuplicate},
+		},
+	}, report)
+}
+
+func TestImportPhrasesUseCase_Import_DryRun(t *testing.T) {
+	phraseRepo := repoMocks.NewMockPhraseRepository(t)
+	userRepo := repoMocks.NewMockUserRepository(t)
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return(ownedPhrases(), nil)
+	uc := usecase.NewImportPhrasesUseCase(phraseRepo, userRepo)
+
+	report, err := uc.Import(context.Background(), 1, usecase.PhraseImportText, strings.NewReader("\ufeffGood morning\n\n  Hello \nGood morning\n"), true)
+	require.NoError(t, err)
+	assert.Equal(t, &usecase.PhraseImportReport{
+		DryRun:     true,
+		Created:    1,
+		Duplicates: 2,
+		Rows: []usecase.PhraseImportRow{
+			{Line: 1, Text: "Good morning", Action: usecase.PhraseImportCreated},
+			{Line: 3, PhraseID: 1, Text: "Hello", Action: usecase.PhraseImportDuplicate},
+			{Line: 4, Text: "Good morning", Action: usecase.PhraseImportDuplicate},
+		},
+	}, report)
+}
+
+func TestImportPhrasesUseCase_Import_NormalizedUpdate(t *testing.T) {
+	phraseRepo := repoMocks.NewMockPhraseRepository(t)
+	userRepo := repoMocks.NewMockUserRepository(t)
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return([]*entity.Phrase{
+		{ID: 1, UserID: 1, Phrase: " Good  night ", Tags: []string{}},
+		{ID: 2, UserID: 1, Phrase: "Cafe\u0301", Tags: []string{}},
+	}, nil)
+	uc := usecase.NewImportPhrasesUseCase(phraseRepo, userRepo)
+
+	report, err := uc.Import(context.Background(), 1, usecase.PhraseImportCSV, strings.NewReader("id,text\n1,Good night\n2,Café\n"), false)
+	require.NoError(t, err)
+	assert.Equal(t, &usecase.PhraseImportReport{
+		Unchanged: 2,
+		Rows: []usecase.PhraseImportRow{
+			{Line: 2, PhraseID: 1, Text: "Good night", Action: usecase.PhraseImportUnchanged},
+			{Line: 3, PhraseID: 2, Text: "Café", Action: usecase.PhraseImportUnchanged},
+		},
+	}, report)
+}
+
+func TestImportPhrasesUseCase_Import_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        usecase.PhraseImportFormat
+		content       string
+		userFound     bool
+		expectedError error
+	}{
+		{"empty", usecase.PhraseImportText, "\n \n", false, usecase.ErrInvalidArgument},
+		{"missing text column", usecase.PhraseImportCSV, "id,language\n1,en\n", false, usecase.ErrInvalidArgument},
+		{"unknown column", usecase.PhraseImportTSV, "text\tnotes\nHello\tloud\n", false, usecase.ErrInvalidArgument},
+		{"empty text", usecase.PhraseImportCSV, "text,language\nHello,en\n ,en\n", false, usecase.ErrInvalidArgument},
+		{"invalid id", usecase.PhraseImportCSV, "id,text\nfirst,Hello\n", false, usecase.ErrInvalidArgument},
+		{"too many tags", usecase.PhraseImportCSV, "text,tags\nHello,\"a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t,u\"\n", false, usecase.ErrInvalidArgument},
+		{"phrase of another user", usecase.PhraseImportCSV, "id,text\n3,Hello\n", true, usecase.ErrInvalidArgument},
+		{"phrase imported twice", usecase.PhraseImportCSV, "id,text\n1,Hello\n1,Hi\n", true, usecase.ErrInvalidArgument},
+		{"unknown format", "xlsx", "Hello\n", false, usecase.ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			if tt.userFound {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return(ownedPhrases(), nil)
+			}
+			uc := usecase.NewImportPhrasesUseCase(phraseRepo, userRepo)
+
+			_, err := uc.Import(context.Background(), 1, tt.format, strings.NewReader(tt.content), false)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestImportPhrasesUseCase_Import_UnknownUser(t *testing.T) {
+	userRepo := repoMocks.NewMockUserRepository(t)
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get user 1: %w", repository.ErrNotFound))
+	uc := usecase.NewImportPhrasesUseCase(repoMocks.NewMockPhraseRepository(t), userRepo)
+
+	_, err := uc.Import(context.Background(), 1, usecase.PhraseImportText, strings.NewReader("Hello\n"), false)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ardfard/sb-test/internal/domain/converter"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+)
+
+// LoudnessUseCase measures the loudness of converted audio and stores a copy
+// normalized to a fixed EBU R128 target next to the canonical file. It runs
+// as a post-processor of ConvertAudioUseCase.
+type LoudnessUseCase struct {
+	storage   storage.Storage
+	converter converter.AudioConverter
+	target    entity.LoudnessTarget
+}
+
+func NewLoudnessUseCase(storage storage.Storage, converter converter.AudioConverter, target entity.LoudnessTarget) *LoudnessUseCase {
+	return &LoudnessUseCase{
+		storage:   storage,
+		converter: converter,
+		target:    target,
+	}
+}
+
+func normalizedPath(audioID uint) string {
+	return fmt.Sprintf("%s/normalized/%d.wav", basePath, audioID)
+}
+
+// Process records the input loudness of the WAV at path on audio and uploads
+// its normalized copy. The caller persists audio.
+func (uc *LoudnessUseCase) Process(ctx context.Context, audio *entity.Audio, path string) error {
+	measured, err := uc.converter.MeasureLoudness(ctx, path, uc.target)
+	if err != nil {
+		return err
+	}
+	audio.ApplyLoudness(measured)
+
+	out, err := os.CreateTemp("", "audio_normalized_*.wav")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %v", err)
+	}
+	out.Close()
+	defer os.Remove(out.Name())
+
+	if err := uc.converter.NormalizeLoudness(ctx, path, out.Name(), uc.target, measured); err != nil {
+		return err
+	}
+
+	normalized, err := os.Open(out.Name())
+	if err != nil {
+		return fmt.Errorf("failed to open normalized file: %v", err)
+	}
+	defer normalized.Close()
+
+	storagePath := normalizedPath(audio.ID)
+	if err := uc.storage.Upload(ctx, storagePath, normalized); err != nil {
+		return fmt.Errorf("failed to upload normalized file: %v", err)
+	}
+	audio.NormalizedPath = storagePath
+	return nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoudnessUseCase_Process(t *testing.T) {
+	target := entity.LoudnessTarget{Integrated: -23, TruePeak: -1, Range: 7}
+	measured := &entity.Loudness{Integrated: -31.4, TruePeak: -12.1, Range: 3.6, Threshold: -41.7, TargetOffset: 0.5}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*converterMocks.MockAudioConverter, *storageMocks.MockStorage)
+		expectedError  bool
+		wantMeasured   bool
+		wantNormalized string
+	}{
+		{
+			name: "measures and stores normalized copy",
+			setupMocks: func(c *converterMocks.MockAudioConverter, s *storageMocks.MockStorage) {
+				c.On("MeasureLoudness", mock.Anything, "converted.wav", target).Return(measured, nil)
+				c.On("NormalizeLoudness", mock.Anything, "converted.wav", mock.Anything, target, measured).
+					Return(func(_ context.Context, _, out string, _ entity.LoudnessTarget, _ *entity.Loudness) error {
+						return os.WriteFile(out, []byte("normalized"), 0o600)
+					})
+				s.On("Upload", mock.Anything, "audio/normalized/1.wav", mock.Anything).Return(nil)
+			},
+			wantMeasured:   true,
+			wantNormalized: "audio/normalized/1.wav",
+		},
+		{
+			name: "measurement fails",
+			setupMocks: func(c *converterMocks.MockAudioConverter, s *storageMocks.MockStorage) {
+				c.On("MeasureLoudness", mock.Anything, "converted.wav", target).Return(nil, assert.AnError)
+			},
+			expectedError: true,
+		},
+		{
+			name: "normalization fails keeps measurement",
+			setupMocks: func(c *converterMocks.MockAudioConverter, s *storageMocks.MockStorage) {
+				c.On("MeasureLoudness", mock.Anything, "converted.wav", target).Return(measured, nil)
+				c.On("NormalizeLoudness", mock.Anything, "converted.wav", mock.Anything, target, measured).Return(assert.AnError)
+			},
+			expectedError: true,
+			wantMeasured:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := converterMocks.NewMockAudioConverter(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(c, s)
+
+			audio := &entity.Audio{ID: 1}
+			err := NewLoudnessUseCase(s, c, target).Process(context.Background(), audio, "converted.wav")
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantMeasured {
+				if assert.NotNil(t, audio.LoudnessIntegrated) {
+					assert.Equal(t, -31.4, *audio.LoudnessIntegrated)
+				}
+			} else {
+				assert.Nil(t, audio.LoudnessIntegrated)
+			}
+			assert.Equal(t, tt.wantNormalized, audio.NormalizedPath)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import "fmt"
+
+// Page sizes of listings.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// pageLimit validates a page and returns its limit, DefaultPageSize when
+// limit is zero.
+func pageLimit(limit, offset int) (int, error) {
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	if limit < 0 || limit > MaxPageSize {
+		return 0, fmt.Errorf("limit must be between 1 and %d, got %d: %w", MaxPageSize, limit, ErrInvalidArgument)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("offset must not be negative, got %d: %w", offset, ErrInvalidArgument)
+	}
+	return limit, nil
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// authorizePhrase returns a phrase if userID may record and download it,
+// which is when the user owns the phrase or it is shared with them. Phrases
+// created before owners were stored, and never recorded, have no owner and
+// nobody may record them until a user claims them.
+func authorizePhrase(ctx context.Context, phrases repository.PhraseRepository, phraseID, userID uint) (*entity.Phrase, error) {
+	phrase, err := phrases.GetByID(ctx, phraseID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get phrase")
+	}
+	if phrase.UserID == 0 {
+		return nil, fmt.Errorf("phrase %d has no owner: %w", phraseID, ErrForbidden)
+	}
+	if phrase.UserID == userID {
+		return phrase, nil
+	}
+
+	shared, err := phrases.IsSharedWith(ctx, phraseID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check phrase access: %v", err)
+	}
+	if !shared {
+		return nil, fmt.Errorf("phrase %d is not shared with user %d: %w", phraseID, userID, ErrForbidden)
+	}
+	return phrase, nil
+}
+
+// ownedPhrase returns a phrase if ownerID owns it, which is required to
+// edit, delete or share it.
+func ownedPhrase(ctx context.Context, phrases repository.PhraseRepository, phraseID, ownerID uint) (*entity.Phrase, error) {
+	phrase, err := phrases.GetByID(ctx, phraseID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get phrase")
+	}
+	if phrase.UserID == 0 || phrase.UserID != ownerID {
+		return nil, fmt.Errorf("phrase %d is not owned by user %d: %w", phraseID, ownerID, ErrForbidden)
+	}
+	return phrase, nil
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorizePhrase(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMocks    func(*repoMocks.MockPhraseRepository)
+		expectedError error
+	}{
+		{
+			name: "owner",
+			setupMocks: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+			},
+		},
+		{
+			name: "phrase without owner",
+			setupMocks: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2}, nil)
+			},
+			expectedError: ErrForbidden,
+		},
+		{
+			name: "shared with user",
+			setupMocks: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+				repo.On("IsSharedWith", mock.Anything, uint(2), uint(1)).Return(true, nil)
+			},
+		},
+		{
+			name: "not shared with user",
+			setupMocks: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+				repo.On("IsSharedWith", mock.Anything, uint(2), uint(1)).Return(false, nil)
+			},
+			expectedError: ErrForbidden,
+		},
+		{
+			name: "phrase not found",
+			setupMocks: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+			},
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockPhraseRepository(t)
+			tt.setupMocks(repo)
+
+			phrase, err := authorizePhrase(context.Background(), repo, 2, 1)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, phrase)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(2), phrase.ID)
+		})
+	}
+}
+
+func TestSharePhraseUseCase(t *testing.T) {
+	t.Run("share", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		userRepo := repoMocks.NewMockUserRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		phraseRepo.On("Share", mock.Anything, uint(2), uint(1)).Return(nil)
+
+		assert.NoError(t, NewSharePhraseUseCase(phraseRepo, userRepo).Share(context.Background(), 3, 2, 1))
+	})
+
+	t.Run("share with owner", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+
+		err := NewSharePhraseUseCase(phraseRepo, nil).Share(context.Background(), 1, 2, 1)
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	})
+
+	t.Run("share with unknown user", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		userRepo := repoMocks.NewMockUserRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+
+		err := NewSharePhraseUseCase(phraseRepo, userRepo).Share(context.Background(), 3, 2, 1)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("not the owner", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+		uc := NewSharePhraseUseCase(phraseRepo, nil)
+
+		assert.ErrorIs(t, uc.Share(context.Background(), 1, 2, 1), ErrForbidden)
+		assert.ErrorIs(t, uc.Unshare(context.Background(), 4, 2, 1), ErrForbidden)
+	})
+
+	t.Run("phrase without owner", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2}, nil)
+
+		err := NewSharePhraseUseCase(phraseRepo, nil).Share(context.Background(), 0, 2, 1)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("unshare phrase that is not shared", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil)
+		phraseRepo.On("Unshare", mock.Anything, uint(2), uint(1)).Return(fmt.Errorf("no share: %w", repository.ErrNotFound))
+
+		err := NewSharePhraseUseCase(phraseRepo, nil).Unshare(context.Background(), 3, 2, 1)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("claim phrase without owner", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		userRepo := repoMocks.NewMockUserRepository(t)
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2}, nil)
+		phraseRepo.On("Claim", mock.Anything, uint(2), uint(1)).Return(nil)
+
+		assert.NoError(t, NewSharePhraseUseCase(phraseRepo, userRepo).Claim(context.Background(), 1, 2))
+	})
+
+	t.Run("claim phrase already owned", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		userRepo := repoMocks.NewMockUserRepository(t)
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 3}, nil).Once()
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil).Once()
+		uc := NewSharePhraseUseCase(phraseRepo, userRepo)
+
+		assert.ErrorIs(t, uc.Claim(context.Background(), 1, 2), ErrConflict)
+		// Claiming a phrase the user owns is a no-op.
+		assert.NoError(t, uc.Claim(context.Background(), 1, 2))
+	})
+
+	t.Run("claim phrase claimed concurrently", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		userRepo := repoMocks.NewMockUserRepository(t)
+		userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2}, nil)
+		phraseRepo.On("Claim", mock.Anything, uint(2), uint(1)).Return(fmt.Errorf("no rows: %w", repository.ErrNotFound))
+
+		err := NewSharePhraseUseCase(phraseRepo, userRepo).Claim(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
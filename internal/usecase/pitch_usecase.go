@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/pitch"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// PitchUseCase computes and serves the F0 contour of converted audio. It
+// runs as a post-processor of ConvertAudioUseCase and regenerates missing
+// contours on demand.
+type PitchUseCase struct {
+	repo    repository.AudioRepository
+	storage storage.Storage
+	options pitch.Options
+}
+
+func NewPitchUseCase(repo repository.AudioRepository, storage storage.Storage, options pitch.Options) *PitchUseCase {
+	return &PitchUseCase{
+		repo:    repo,
+		storage: storage,
+		options: options,
+	}
+}
+
+func pitchPath(audioID uint) string {
+	return fmt.Sprintf("%s/pitch/%d.json", basePath, audioID)
+}
+
+// Process tracks the pitch of the converted WAV at path and stores the contour.
+func (uc *PitchUseCase) Process(ctx context.Context, audio *entity.Audio, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audio: %v", err)
+	}
+	defer file.Close()
+
+	_, err = uc.generate(ctx, audio.ID, file)
+	return err
+}
+
+func (uc *PitchUseCase) generate(ctx context.Context, audioID uint, r io.Reader) (*pitch.Contour, error) {
+	reader, err := wav.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %v", err)
+	}
+	contour, err := pitch.Track(reader, uc.options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to track pitch: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(contour); err != nil {
+		return nil, fmt.Errorf("failed to encode pitch contour: %v", err)
+	}
+	if err := uc.storage.Upload(ctx, pitchPath(audioID), &buf); err != nil {
+		return nil, fmt.Errorf("failed to store pitch contour: %v", err)
+	}
+	return contour, nil
+}
+
+// Get returns the stored F0 contour of a converted audio, computing it first
+// if it does not exist yet.
+func (uc *PitchUseCase) Get(ctx context.Context, audioID uint) (*pitch.Contour, error) {
+	audio, err := uc.repo.GetByID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	if audio.Status != entity.AudioStatusCompleted {
+		return nil, fmt.Errorf("audio %d is %s: %w", audioID, audio.Status, ErrConflict)
+	}
+
+	reader, err := uc.storage.Download(ctx, pitchPath(audioID))
+	if err == nil {
+		defer reader.Close()
+		var contour pitch.Contour
+		if err := json.NewDecoder(reader).Decode(&contour); err != nil {
+			return nil, fmt.Errorf("failed to read pitch contour: %v", err)
+		}
+		return &contour, nil
+	}
+	if !errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, fmt.Errorf("failed to download pitch contour: %v", err)
+	}
+
+	// Audio converted before pitch tracking existed: compute from the stored WAV.
+	source, err := uc.storage.Download(ctx, audio.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download audio: %v", err)
+	}
+	defer source.Close()
+
+	return uc.generate(ctx, audioID, source)
+}
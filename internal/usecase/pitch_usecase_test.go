@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/pkg/pitch"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPitchUseCase_Get(t *testing.T) {
+	options := pitch.Options{Hop: 10 * time.Millisecond, MinF0: 75, MaxF0: 500, Threshold: 0.15}
+	completed := &entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, CurrentFormat: "wav", StoragePath: "audio/converted/1.wav"}
+
+	samples := make([]float64, 4000)
+	for i := range samples {
+		samples[i] = 0.5 * math.Sin(2*math.Pi*200*float64(i)/16000)
+	}
+	var source bytes.Buffer
+	require.NoError(t, wav.Encode(&source, 16000, 1, samples))
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expectedMean  float64
+		expectedError error
+	}{
+		{
+			name: "stored contour",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/pitch/1.json").Return(io.NopCloser(bytes.NewReader([]byte(`{"hop":0.01,"points":[],"stats":{"mean_f0":123}}`))), nil)
+			},
+			expectedMean: 123,
+		},
+		{
+			name: "missing contour is generated",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/pitch/1.json").Return(nil, fmt.Errorf("open: %w", storage.ErrObjectNotFound))
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+				s.On("Upload", mock.Anything, "audio/pitch/1.json", mock.Anything).Return(nil)
+			},
+			expectedMean: 200,
+		},
+		{
+			name: "audio not converted yet",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedError: ErrConflict,
+		},
+		{
+			name: "audio not found",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, repository.ErrNotFound)
+			},
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			contour, err := NewPitchUseCase(repo, s, options).Get(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expectedMean, contour.Stats.MeanF0, 1)
+		})
+	}
+}
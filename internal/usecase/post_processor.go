@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+// AudioPostProcessor derives artifacts from the canonical audio once
+// ConvertAudioUseCase has stored it. path is a local copy of the converted
+// WAV file that is only valid for the duration of the call.
+type AudioPostProcessor interface {
+	Process(ctx context.Context, audio *entity.Audio, path string) error
+}
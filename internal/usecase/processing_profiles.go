@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+)
+
+// ProfileNone explicitly asks for no processing profile on a download,
+// overriding the user's default.
+const ProfileNone = "none"
+
+// ProcessingProfiles maps profile names to the filter chain they apply.
+type ProcessingProfiles map[string][]entity.Filter
+
+// Has reports whether name is a known profile. The empty name means no profile.
+func (p ProcessingProfiles) Has(name string) bool {
+	if name == "" {
+		return true
+	}
+	_, ok := p[name]
+	return ok
+}
+
+// filters returns the filter chain for a download asking for requested, falling back
+// to the user's default profile.
+func (p ProcessingProfiles) filters(requested, userDefault string) ([]entity.Filter, error) {
+	name := requested
+	if name == "" {
+		name = userDefault
+	}
+	if name == "" || name == ProfileNone {
+		return nil, nil
+	}
+	filters, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown processing profile %q: %w", name, ErrInvalidArgument)
+	}
+	return filters, nil
+}
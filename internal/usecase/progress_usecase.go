@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// ProgressUseCase tells speakers how far they are through the phrases they
+// may record and which one to record next.
+type ProgressUseCase struct {
+	audioRepository  repository.AudioRepository
+	phraseRepository repository.PhraseRepository
+	userRepository   repository.UserRepository
+	strategy         repository.NextPhraseStrategy
+}
+
+// NewProgressUseCase creates a ProgressUseCase that picks next phrases with
+// strategy unless a request asks for another one.
+func NewProgressUseCase(
+	audioRepository repository.AudioRepository,
+	phraseRepository repository.PhraseRepository,
+	userRepository repository.UserRepository,
+	strategy repository.NextPhraseStrategy,
+) *ProgressUseCase {
+	return &ProgressUseCase{
+		audioRepository:  audioRepository,
+		phraseRepository: phraseRepository,
+		userRepository:   userRepository,
+		strategy:         strategy,
+	}
+}
+
+// Progress counts the phrases the user may record by the status of their
+// take.
+func (uc *ProgressUseCase) Progress(ctx context.Context, userID uint) (*entity.RecordingProgress, error) {
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	progress, err := uc.audioRepository.Progress(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recording progress: %v", err)
+	}
+	return progress, nil
+}
+
+// NextPhrase picks a phrase the user has no take of, or only a failed one,
+// with strategy or, if it is empty, the default strategy. It returns
+// ErrNotFound when nothing is left to record.
+func (uc *ProgressUseCase) NextPhrase(ctx context.Context, userID uint, strategy repository.NextPhraseStrategy) (*entity.Phrase, error) {
+	if strategy == "" {
+		strategy = uc.strategy
+	}
+	if !strategy.Valid() {
+		return nil, fmt.Errorf("unknown strategy %q: %w", strategy, ErrInvalidArgument)
+	}
+
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	phrase, err := uc.phraseRepository.NextToRecord(ctx, userID, strategy)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("user %d has no phrase left to record: %w", userID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next phrase: %v", err)
+	}
+	return phrase, nil
+}
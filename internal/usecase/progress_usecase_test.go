@@ -0,0 +1,119 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProgressUseCase_Progress(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockSetup     func(*repoMocks.MockAudioRepository, *repoMocks.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name: "progress of the user",
+			mockSetup: func(audioRepo *repoMocks.MockAudioRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				audioRepo.On("Progress", mock.Anything, uint(1)).Return(&entity.RecordingProgress{Phrases: 3, NoAudio: 1, Completed: 2, RecordedDuration: 4.5}, nil)
+			},
+		},
+		{
+			name: "unknown user",
+			mockSetup: func(audioRepo *repoMocks.MockAudioRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get user 1: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audioRepo := repoMocks.NewMockAudioRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			tt.mockSetup(audioRepo, userRepo)
+			uc := usecase.NewProgressUseCase(audioRepo, repoMocks.NewMockPhraseRepository(t), userRepo, repository.NextPhraseInOrder)
+
+			progress, err := uc.Progress(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 3, progress.Phrases)
+			assert.Equal(t, 4.5, progress.RecordedDuration)
+		})
+	}
+}
+
+func TestProgressUseCase_NextPhrase(t *testing.T) {
+	tests := []struct {
+		name          string
+		strategy      repository.NextPhraseStrategy
+		mockSetup     func(*repoMocks.MockPhraseRepository, *repoMocks.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name: "default strategy",
+			mockSetup: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("NextToRecord", mock.Anything, uint(1), repository.NextPhraseFailedFirst).Return(&entity.Phrase{ID: 7}, nil)
+			},
+		},
+		{
+			name:     "requested strategy",
+			strategy: repository.NextPhraseRandom,
+			mockSetup: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("NextToRecord", mock.Anything, uint(1), repository.NextPhraseRandom).Return(&entity.Phrase{ID: 7}, nil)
+			},
+		},
+		{
+			name:          "unknown strategy",
+			strategy:      "alphabetical",
+			mockSetup:     func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name: "nothing left to record",
+			mockSetup: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("NextToRecord", mock.Anything, uint(1), repository.NextPhraseFailedFirst).
+					Return(nil, fmt.Errorf("no phrase left to record for user 1: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+		{
+			name: "unknown user",
+			mockSetup: func(phraseRepo *repoMocks.MockPhraseRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get user 1: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			tt.mockSetup(phraseRepo, userRepo)
+			uc := usecase.NewProgressUseCase(repoMocks.NewMockAudioRepository(t), phraseRepo, userRepo, repository.NextPhraseFailedFirst)
+
+			phrase, err := uc.NextPhrase(context.Background(), 1, tt.strategy)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(7), phrase.ID)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+const (
+	// MaxSessionField is the length in characters of the longest device,
+	// client, microphone or location of a recording session.
+	MaxSessionField = 200
+	// MaxSessionSampleRate is the highest sample rate in Hz a session may
+	// declare.
+	MaxSessionSampleRate = 384000
+)
+
+// RecordingSessionInput describes the equipment and place of a session.
+type RecordingSessionInput struct {
+	Device     string
+	Client     string
+	SampleRate int
+	Microphone string
+	Location   string
+}
+
+// RecordingSessionPage is one page of the sessions of a user.
+type RecordingSessionPage struct {
+	Sessions []*entity.RecordingSession
+	Total    int // Sessions across all pages
+	Limit    int
+	Offset   int
+}
+
+// RecordingSessionUseCase manages the sittings speakers record takes in.
+type RecordingSessionUseCase struct {
+	sessionRepository repository.RecordingSessionRepository
+	audioRepository   repository.AudioRepository
+	userRepository    repository.UserRepository
+}
+
+func NewRecordingSessionUseCase(
+	sessionRepository repository.RecordingSessionRepository,
+	audioRepository repository.AudioRepository,
+	userRepository repository.UserRepository,
+) *RecordingSessionUseCase {
+	return &RecordingSessionUseCase{
+		sessionRepository: sessionRepository,
+		audioRepository:   audioRepository,
+		userRepository:    userRepository,
+	}
+}
+
+// Open starts a session of the user, described by input.
+func (uc *RecordingSessionUseCase) Open(ctx context.Context, userID uint, input RecordingSessionInput) (*entity.RecordingSession, error) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"device", &input.Device},
+		{"client", &input.Client},
+		{"microphone", &input.Microphone},
+		{"location", &input.Location},
+	}
+	for _, field := range fields {
+		*field.value = strings.TrimSpace(*field.value)
+		if utf8.RuneCountInString(*field.value) > MaxSessionField {
+			return nil, fmt.Errorf("%s must be at most %d characters: %w", field.name, MaxSessionField, ErrInvalidArgument)
+		}
+		if strings.ContainsFunc(*field.value, unicode.IsControl) {
+			return nil, fmt.Errorf("%s must not contain control characters: %w", field.name, ErrInvalidArgument)
+		}
+	}
+	if input.SampleRate < 0 || input.SampleRate > MaxSessionSampleRate {
+		return nil, fmt.Errorf("sample rate must be between 0 and %d Hz: %w", MaxSessionSampleRate, ErrInvalidArgument)
+	}
+
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+
+	now := time.Now()
+	session, err := uc.sessionRepository.Create(ctx, &entity.RecordingSession{
+		UserID:     userID,
+		Device:     input.Device,
+		Client:     input.Client,
+		SampleRate: input.SampleRate,
+		Microphone: input.Microphone,
+		Location:   input.Location,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording session: %v", err)
+	}
+	return session, nil
+}
+
+// Get returns a session.
+func (uc *RecordingSessionUseCase) Get(ctx context.Context, sessionID uint) (*entity.RecordingSession, error) {
+	session, err := uc.sessionRepository.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get recording session")
+	}
+	return session, nil
+}
+
+// List returns a page of the user's sessions, most recent first. A zero limit
+// selects DefaultPageSize.
+func (uc *RecordingSessionUseCase) List(ctx context.Context, userID uint, limit, offset int) (*RecordingSessionPage, error) {
+	limit, err := pageLimit(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+
+	sessions, total, err := uc.sessionRepository.List(ctx, repository.RecordingSessionFilter{UserID: userID, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recording sessions: %v", err)
+	}
+	return &RecordingSessionPage{Sessions: sessions, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// Close ends a session so no more takes are uploaded in it. Closing a closed
+// session changes nothing.
+func (uc *RecordingSessionUseCase) Close(ctx context.Context, sessionID uint) (*entity.RecordingSession, error) {
+	session, err := uc.sessionRepository.Close(ctx, sessionID, time.Now())
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to close recording session")
+	}
+	return session, nil
+}
+
+// Takes returns the takes uploaded in a session, oldest first.
+func (uc *RecordingSessionUseCase) Takes(ctx context.Context, sessionID uint) ([]*entity.Audio, error) {
+	if _, err := uc.sessionRepository.GetByID(ctx, sessionID); err != nil {
+		return nil, wrapRepoError(err, "failed to get recording session")
+	}
+	audios, err := uc.audioRepository.ListBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list takes of recording session: %v", err)
+	}
+	return audios, nil
+}
+
+// checkSession verifies that takes of userID may be uploaded in a session:
+// it must be theirs and still open. A zero ID means no session.
+func checkSession(ctx context.Context, sessions repository.RecordingSessionRepository, sessionID, userID uint) error {
+	if sessionID == 0 {
+		return nil
+	}
+	session, err := sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return wrapRepoError(err, "failed to get recording session")
+	}
+	if session.UserID != userID {
+		return fmt.Errorf("recording session %d belongs to another user: %w", sessionID, ErrForbidden)
+	}
+	if session.ClosedAt != nil {
+		return fmt.Errorf("recording session %d is closed: %w", sessionID, ErrConflict)
+	}
+	return nil
+}
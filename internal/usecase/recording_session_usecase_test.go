@@ -0,0 +1,124 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordingSessionUseCase_Open(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         usecase.RecordingSessionInput
+		mockSetup     func(*repoMocks.MockRecordingSessionRepository, *repoMocks.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name:  "trimmed",
+			input: usecase.RecordingSessionInput{Device: " Pixel 8 ", Client: "Chrome 129", SampleRate: 48000, Microphone: "USB mic ", Location: "studio-a"},
+			mockSetup: func(sessionRepo *repoMocks.MockRecordingSessionRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				sessionRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *entity.RecordingSession) bool {
+					return s.UserID == 1 && s.Device == "Pixel 8" && s.Microphone == "USB mic" && s.SampleRate == 48000
+				})).Return(func(_ context.Context, s *entity.RecordingSession) *entity.RecordingSession { return s }, nil)
+			},
+		},
+		{
+			name:          "sample rate too high",
+			input:         usecase.RecordingSessionInput{SampleRate: usecase.MaxSessionSampleRate + 1},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "field too long",
+			input:         usecase.RecordingSessionInput{Location: strings.Repeat("a", usecase.MaxSessionField+1)},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "control characters",
+			input:         usecase.RecordingSessionInput{Microphone: "USB\nmic"},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name: "unknown user",
+			mockSetup: func(_ *repoMocks.MockRecordingSessionRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get user 1: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := repoMocks.NewMockRecordingSessionRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			if tt.mockSetup != nil {
+				tt.mockSetup(sessionRepo, userRepo)
+			}
+			uc := usecase.NewRecordingSessionUseCase(sessionRepo, repoMocks.NewMockAudioRepository(t), userRepo)
+
+			session, err := uc.Open(context.Background(), 1, tt.input)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Pixel 8", session.Device)
+			assert.False(t, session.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestRecordingSessionUseCase_List(t *testing.T) {
+	sessionRepo := repoMocks.NewMockRecordingSessionRepository(t)
+	userRepo := repoMocks.NewMockUserRepository(t)
+	uc := usecase.NewRecordingSessionUseCase(sessionRepo, repoMocks.NewMockAudioRepository(t), userRepo)
+
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	sessionRepo.On("List", mock.Anything, repository.RecordingSessionFilter{UserID: 1, Limit: usecase.DefaultPageSize}).
+		Return([]*entity.RecordingSession{{ID: 4, UserID: 1}}, 1, nil)
+	page, err := uc.List(context.Background(), 1, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+	assert.Equal(t, usecase.DefaultPageSize, page.Limit)
+
+	_, err = uc.List(context.Background(), 1, 0, -1)
+	assert.ErrorIs(t, err, usecase.ErrInvalidArgument)
+}
+
+func TestRecordingSessionUseCase_CloseAndTakes(t *testing.T) {
+	sessionRepo := repoMocks.NewMockRecordingSessionRepository(t)
+	audioRepo := repoMocks.NewMockAudioRepository(t)
+	uc := usecase.NewRecordingSessionUseCase(sessionRepo, audioRepo, repoMocks.NewMockUserRepository(t))
+
+	closedAt := time.Now()
+	sessionRepo.On("Close", mock.Anything, uint(4), mock.AnythingOfType("time.Time")).
+		Return(&entity.RecordingSession{ID: 4, ClosedAt: &closedAt}, nil)
+	session, err := uc.Close(context.Background(), 4)
+	require.NoError(t, err)
+	assert.NotNil(t, session.ClosedAt)
+
+	sessionRepo.On("Close", mock.Anything, uint(5), mock.AnythingOfType("time.Time")).
+		Return(nil, fmt.Errorf("failed to close recording session 5: %w", repository.ErrNotFound))
+	_, err = uc.Close(context.Background(), 5)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+
+	sessionRepo.On("GetByID", mock.Anything, uint(4)).Return(&entity.RecordingSession{ID: 4}, nil)
+	audioRepo.On("ListBySessionID", mock.Anything, uint(4)).Return([]*entity.Audio{{ID: 7, SessionID: 4}}, nil)
+	takes, err := uc.Takes(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Len(t, takes, 1)
+
+	sessionRepo.On("GetByID", mock.Anything, uint(5)).Return(nil, fmt.Errorf("failed to get recording session 5: %w", repository.ErrNotFound))
+	_, err = uc.Takes(context.Background(), 5)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+}
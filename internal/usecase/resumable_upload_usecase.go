@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	// ErrUploadOffsetMismatch is returned when a chunk does not start at the current upload offset.
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+	// ErrChecksumMismatch is returned when a chunk does not match its declared checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrUploadTooLarge is returned when an upload exceeds the allowed size.
+	ErrUploadTooLarge = errors.New("upload too large")
+)
+
+// ChecksumAlgorithms lists the algorithms accepted for chunk checksums.
+var ChecksumAlgorithms = []string{"md5", "sha1", "sha256"}
+
+// UploadChecksum is the declared checksum of a single chunk.
+type UploadChecksum struct {
+	Algorithm string
+	Sum       []byte
+}
+
+func (c *UploadChecksum) newHash() (hash.Hash, error) {
+	switch c.Algorithm {
+	case "md5":
+		return md5.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm %q: %w", c.Algorithm, ErrInvalidArgument)
+	}
+}
+
+type ResumableUploadUseCase struct {
+	uploadRepo       repository.UploadRepository
+	staging          storage.StagingArea
+	userRepository   repository.UserRepository
+	phraseRepository repository.PhraseRepository
+	uploadUseCase    *UploadAudioUseCase
+	maxSize          int64
+	expiry           time.Duration
+	locks            uploadLocks
+}
+
+// uploadLocks serializes the requests that change the same upload, so two
+// chunks sent at one offset cannot both be appended.
+type uploadLocks struct {
+	mu    sync.Mutex
+	locks map[string]*uploadLock
+}
+
+type uploadLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock blocks until the upload id is free and returns the function releasing it.
+func (l *uploadLocks) lock(id string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*uploadLock{}
+	}
+	lock, ok := l.locks[id]
+	if !ok {
+		lock = &uploadLock{}
+		l.locks[id] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.mu.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(l.locks, id)
+		}
+		l.mu.Unlock()
+	}
+}
+
+func NewResumableUploadUseCase(
+	uploadRepo repository.UploadRepository,
+	staging storage.StagingArea,
+	userRepository repository.UserRepository,
+	phraseRepository repository.PhraseRepository,
+	uploadUseCase *UploadAudioUseCase,
+	maxSize int64,
+	expiry time.Duration,
+) *ResumableUploadUseCase {
+	return &ResumableUploadUseCase{
+		uploadRepo:       uploadRepo,
+		staging:          staging,
+		userRepository:   userRepository,
+		phraseRepository: phraseRepository,
+		uploadUseCase:    uploadUseCase,
+		maxSize:          maxSize,
+		expiry:           expiry,
+	}
+}
+
+// MaxSize returns the largest accepted upload length, or 0 if unlimited.
+func (uc *ResumableUploadUseCase) MaxSize() int64 {
+	return uc.maxSize
+}
+
+// ExpiresAt returns when an unfinished upload is discarded if it receives no
+// more data. It returns false when uploads do not expire or the upload is done.
+func (uc *ResumableUploadUseCase) ExpiresAt(upload *entity.Upload) (time.Time, bool) {
+	if uc.expiry <= 0 || upload.AudioID != nil {
+		return time.Time{}, false
+	}
+	return upload.UpdatedAt.Add(uc.expiry), true
+}
+
+// Create registers a new resumable upload of length bytes for the given user
+// and phrase, in the recording session sessionID unless it is 0. An empty
+// upload has nothing left to send and is completed right away.
+func (uc *ResumableUploadUseCase) Create(ctx context.Context, userID, phraseID, sessionID uint, length int64, filename, metadata string) (*entity.Upload, error) {
+	if length < 0 {
+		return nil, fmt.Errorf("upload length must not be negative: %w", ErrInvalidArgument)
+	}
+	if uc.maxSize > 0 && length > uc.maxSize {
+		return nil, fmt.Errorf("upload length %d exceeds %d bytes: %w", length, uc.maxSize, ErrUploadTooLarge)
+	}
+
+	if err := checkUploader(ctx, uc.userRepository, userID); err != nil {
+		return nil, err
+	}
+	if _, err := authorizePhrase(ctx, uc.phraseRepository, phraseID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.uploadUseCase.previousTake(ctx, userID, phraseID); err != nil {
+		return nil, err
+	}
+	if err := uc.uploadUseCase.checkSession(ctx, sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	upload := &entity.Upload{
+		ID:        ulid.Make().String(),
+		UserID:    userID,
+		PhraseID:  phraseID,
+		Filename:  filename,
+		Length:    length,
+		Metadata:  metadata,
+		SessionID: sessionID,
+	}
+
+	if err := uc.staging.Create(ctx, upload.ID); err != nil {
+		return nil, fmt.Errorf("failed to create staged file: %v", err)
+	}
+
+	upload, err := uc.uploadRepo.Create(ctx, upload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store upload: %v", err)
+	}
+
+	if upload.Completed() {
+		if _, err := uc.complete(ctx, upload); err != nil {
+			if discardErr := uc.discard(ctx, upload.ID); discardErr != nil {
+				return nil, discardErr
+			}
+			return nil, err
+		}
+	}
+	return upload, nil
+}
+
+// Get returns the current state of an upload.
+func (uc *ResumableUploadUseCase) Get(ctx context.Context, id string) (*entity.Upload, error) {
+	upload, err := uc.uploadRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get upload")
+	}
+	if expiresAt, ok := uc.ExpiresAt(upload); ok && !time.Now().Before(expiresAt) {
+		return nil, fmt.Errorf("upload %s expired at %s: %w", id, expiresAt.Format(time.RFC3339), ErrGone)
+	}
+	return upload, nil
+}
+
+// WriteChunk appends a chunk at offset. When the chunk completes the upload,
+// the assembled file is passed to UploadAudioUseCase and the resulting audio
+// is returned alongside the upload.
+func (uc *ResumableUploadUseCase) WriteChunk(ctx context.Context, id string, offset int64, chunk io.Reader, checksum *UploadChecksum) (*entity.Upload, *entity.Audio, error) {
+	unlock := uc.locks.lock(id)
+	defer unlock()
+
+	upload, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if offset != upload.Offset {
+		return nil, nil, fmt.Errorf("chunk starts at %d but upload is at %d: %w", offset, upload.Offset, ErrUploadOffsetMismatch)
+	}
+
+	var h hash.Hash
+	if checksum != nil {
+		if h, err = checksum.newHash(); err != nil {
+			return nil, nil, err
+		}
+		chunk = io.TeeReader(chunk, h)
+	}
+
+	// Read one byte past the remaining length to detect oversized chunks.
+	remaining := upload.Length - upload.Offset
+	written, err := uc.staging.Append(ctx, id, offset, io.LimitReader(chunk, remaining+1))
+	if err != nil {
+		// Keep whatever was written so the client can resume after a dropped connection.
+		if written > 0 && written <= remaining && checksum == nil {
+			upload.Offset += written
+			if updateErr := uc.uploadRepo.Update(ctx, upload); updateErr != nil {
+				return nil, nil, fmt.Errorf("failed to update upload offset: %v", updateErr)
+			}
+		} else if truncErr := uc.staging.Truncate(ctx, id, offset); truncErr != nil {
+			return nil, nil, fmt.Errorf("failed to discard chunk: %v", truncErr)
+		}
+		return nil, nil, fmt.Errorf("failed to write chunk: %v", err)
+	}
+
+	if written > remaining {
+		if err := uc.staging.Truncate(ctx, id, offset); err != nil {
+			return nil, nil, fmt.Errorf("failed to discard chunk: %v", err)
+		}
+		return nil, nil, fmt.Errorf("chunk exceeds upload length %d: %w", upload.Length, ErrUploadTooLarge)
+	}
+
+	if h != nil && !bytes.Equal(h.Sum(nil), checksum.Sum) {
+		if err := uc.staging.Truncate(ctx, id, offset); err != nil {
+			return nil, nil, fmt.Errorf("failed to discard chunk: %v", err)
+		}
+		return nil, nil, fmt.Errorf("chunk %s digest does not match: %w", checksum.Algorithm, ErrChecksumMismatch)
+	}
+
+	upload.Offset += written
+	if err := uc.uploadRepo.Update(ctx, upload); err != nil {
+		return nil, nil, fmt.Errorf("failed to update upload offset: %v", err)
+	}
+
+	if !upload.Completed() {
+		return upload, nil, nil
+	}
+
+	// A complete upload cannot receive more data, so one that cannot be
+	// handed over is discarded rather than left to fail every later request.
+	audio, err := uc.complete(ctx, upload)
+	if err != nil {
+		if discardErr := uc.discard(ctx, upload.ID); discardErr != nil {
+			return nil, nil, discardErr
+		}
+		return nil, nil, err
+	}
+	return upload, audio, nil
+}
+
+// complete hands the assembled file to the regular upload path.
+func (uc *ResumableUploadUseCase) complete(ctx context.Context, upload *entity.Upload) (*entity.Audio, error) {
+	reader, err := uc.staging.Open(ctx, upload.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open staged file: %v", err)
+	}
+	defer reader.Close()
+
+	audio, err := uc.uploadUseCase.Upload(ctx, upload.Filename, reader, upload.UserID, upload.PhraseID, upload.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	upload.AudioID = &audio.ID
+	if err := uc.uploadRepo.Update(ctx, upload); err != nil {
+		return nil, fmt.Errorf("failed to update upload: %v", err)
+	}
+
+	if err := uc.staging.Delete(ctx, upload.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete staged file: %v", err)
+	}
+	return audio, nil
+}
+
+// Terminate aborts an upload and discards the received data.
+func (uc *ResumableUploadUseCase) Terminate(ctx context.Context, id string) error {
+	unlock := uc.locks.lock(id)
+	defer unlock()
+
+	if _, err := uc.Get(ctx, id); err != nil {
+		return err
+	}
+	return uc.discard(ctx, id)
+}
+
+// ExpireStale discards the unfinished uploads that received no data within
+// the expiry and returns how many were removed.
+func (uc *ResumableUploadUseCase) ExpireStale(ctx context.Context) (int, error) {
+	if uc.expiry <= 0 {
+		return 0, nil
+	}
+	uploads, err := uc.uploadRepo.ListStale(ctx, time.Now().Add(-uc.expiry))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list stale uploads: %v", err)
+	}
+
+	expired := 0
+	for _, upload := range uploads {
+		ok, err := uc.expire(ctx, upload.ID)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// expire discards a stale upload unless a chunk arrived or it was terminated
+// after it was listed, and reports whether it was discarded.
+func (uc *ResumableUploadUseCase) expire(ctx context.Context, id string) (bool, error) {
+	unlock := uc.locks.lock(id)
+	defer unlock()
+
+	_, err := uc.Get(ctx, id)
+	switch {
+	case errors.Is(err, ErrGone):
+		return true, uc.discard(ctx, id)
+	case err == nil, errors.Is(err, ErrNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// discard removes the staged file and the record of an upload.
+func (uc *ResumableUploadUseCase) discard(ctx context.Context, id string) error {
+	if err := uc.staging.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete staged file: %v", err)
+	}
+	if err := uc.uploadRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete upload: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha1"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/infrastructure/storage"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type resumableMocks struct {
+	uploadRepo  *repoMocks.MockUploadRepository
+	audioRepo   *repoMocks.MockAudioRepository
+	converter   *converterMocks.MockAudioConverter
+	userRepo    *repoMocks.MockUserRepository
+	phraseRepo  *repoMocks.MockPhraseRepository
+	sessionRepo *repoMocks.MockRecordingSessionRepository
+	storage     *storageMocks.MockStorage
+	queue       *queueMocks.MockTaskQueue
+}
+
+func newResumableTestUseCase(t *testing.T) (*ResumableUploadUseCase, resumableMocks, *storage.LocalStagingArea) {
+	m := resumableMocks{
+		uploadRepo:  repoMocks.NewMockUploadRepository(t),
+		audioRepo:   repoMocks.NewMockAudioRepository(t),
+		converter:   converterMocks.NewMockAudioConverter(t),
+		userRepo:    repoMocks.NewMockUserRepository(t),
+		phraseRepo:  repoMocks.NewMockPhraseRepository(t),
+		sessionRepo: repoMocks.NewMockRecordingSessionRepository(t),
+		storage:     storageMocks.NewMockStorage(t),
+		queue:       queueMocks.NewMockTaskQueue(t),
+	}
+	staging, err := storage.NewLocalStagingArea(t.TempDir())
+	require.NoError(t, err)
+	// The user has not recorded any of the phrases yet.
+	m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound).Maybe()
+
+	upload := NewUploadAudioUseCase(m.audioRepo, m.storage, m.converter, m.queue, m.userRepo, m.phraseRepo, m.sessionRepo, repoMocks.NewMockAudioReviewRepository(t), UploadPolicy{})
+	return NewResumableUploadUseCase(m.uploadRepo, staging, m.userRepo, m.phraseRepo, upload, 1024, 0), m, staging
+}
+
+func TestResumableUploadUseCase_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		uc, m, _ := newResumableTestUseCase(t)
+		m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+		m.uploadRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *entity.Upload) bool {
+			return u.ID != "" && u.Length == 10 && u.Filename == "take.m4a"
+		})).Return(func(_ context.Context, u *entity.Upload) (*entity.Upload, error) { return u, nil })
+
+		upload, err := uc.Create(context.Background(), 1, 2, 0, 10, "take.m4a", "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), upload.Offset)
+	})
+
+	t.Run("rejected empty upload is discarded", func(t *testing.T) {
+		uc, m, staging := newResumableTestUseCase(t)
+		m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+		var created *entity.Upload
+		m.uploadRepo.On("Create", mock.Anything, mock.Anything).
+			Return(func(_ context.Context, u *entity.Upload) (*entity.Upload, error) { created = u; return u, nil })
+		m.uploadRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+		// An empty upload is complete as soon as it is created, and an empty file is not audio
+		_, err := uc.Create(context.Background(), 1, 2, 0, 0, "take.m4a", "")
+		assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+		require.NotNil(t, created)
+		m.uploadRepo.AssertCalled(t, "Delete", mock.Anything, created.ID)
+		_, err = staging.Open(context.Background(), created.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("negative length", func(t *testing.T) {
+		uc, _, _ := newResumableTestUseCase(t)
+		_, err := uc.Create(context.Background(), 1, 2, 0, -1, "take.m4a", "")
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		uc, _, _ := newResumableTestUseCase(t)
+		_, err := uc.Create(context.Background(), 1, 2, 0, 2048, "take.m4a", "")
+		assert.ErrorIs(t, err, ErrUploadTooLarge)
+	})
+
+	t.Run("in a session", func(t *testing.T) {
+		uc, m, _ := newResumableTestUseCase(t)
+		m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+		m.sessionRepo.On("GetByID", mock.Anything, uint(4)).Return(&entity.RecordingSession{ID: 4, UserID: 1}, nil)
+		m.uploadRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *entity.Upload) bool { return u.SessionID == 4 })).
+			Return(func(_ context.Context, u *entity.Upload) (*entity.Upload, error) { return u, nil })
+
+		upload, err := uc.Create(context.Background(), 1, 2, 4, 10, "take.m4a", "")
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), upload.SessionID)
+	})
+
+	t.Run("unusable session", func(t *testing.T) {
+		closedAt := time.Now()
+		tests := []struct {
+			name          string
+			session       *entity.RecordingSession
+			expectedError error
+		}{
+			{"another user's", &entity.RecordingSession{ID: 4, UserID: 3}, ErrForbidden},
+			{"closed", &entity.RecordingSession{ID: 4, UserID: 1, ClosedAt: &closedAt}, ErrConflict},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				uc, m, _ := newResumableTestUseCase(t)
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+				m.sessionRepo.On("GetByID", mock.Anything, uint(4)).Return(tt.session, nil)
+
+				_, err := uc.Create(context.Background(), 1, 2, 4, 10, "take.m4a", "")
+				assert.ErrorIs(t, err, tt.expectedError)
+			})
+		}
+	})
+
+	t.Run("unknown phrase", func(t *testing.T) {
+		uc, m, _ := newResumableTestUseCase(t)
+		m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, repository.ErrNotFound)
+		_, err := uc.Create(context.Background(), 1, 2, 0, 10, "take.m4a", "")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestResumableUploadUseCase_WriteChunk(t *testing.T) {
+	ctx := context.Background()
+
+	newUpload := func(t *testing.T, staging *storage.LocalStagingArea) *entity.Upload {
+		upload := &entity.Upload{ID: "up", UserID: 1, PhraseID: 2, Filename: "take.m4a", Length: 11}
+		require.NoError(t, staging.Create(ctx, upload.ID))
+		return upload
+	}
+
+	t.Run("chunks are assembled and handed to the upload use case", func(t *testing.T) {
+		uc, m, staging := newResumableTestUseCase(t)
+		upload := newUpload(t, staging)
+		m.uploadRepo.On("GetByID", mock.Anything, "up").Return(upload, nil)
+		m.uploadRepo.On("Update", mock.Anything, upload).Return(nil)
+
+		got, audio, err := uc.WriteChunk(ctx, "up", 0, strings.NewReader("ID3lo "), nil)
+		require.NoError(t, err)
+		assert.Nil(t, audio)
+		assert.Equal(t, int64(6), got.Offset)
+
+		var uploaded string
+		m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		m.phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+		m.converter.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "m4a"}, nil)
+		m.audioRepo.On("Store", mock.Anything, mock.Anything).Return(&entity.Audio{ID: 9}, nil)
+		m.storage.On("Upload", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			content, _ := io.ReadAll(args.Get(2).(io.Reader))
+			uploaded = string(content)
+		}).Return(nil)
+		m.queue.On("Enqueue", mock.Anything, uint(9)).Return(nil)
+
+		sum := sha1.Sum([]byte("world"))
+		got, audio, err = uc.WriteChunk(ctx, "up", 6, strings.NewReader("world"), &UploadChecksum{Algorithm: "sha1", Sum: sum[:]})
+		require.NoError(t, err)
+		require.NotNil(t, audio)
+		assert.Equal(t, uint(9), audio.ID)
+		assert.True(t, got.Completed())
+		require.NotNil(t, got.AudioID)
+		assert.Equal(t, uint(9), *got.AudioID)
+		assert.Equal(t, "ID3lo world", uploaded)
+
+		_, err = staging.Open(ctx, "up")
+		assert.Error(t, err, "staged file should be removed after completion")
+	})
+
+	t.Run("upload that cannot be completed is discarded", func(t *testing.T) {
+		uc, m, staging := newResumableTestUseCase(t)
+		upload := newUpload(t, staging)
+		m.uploadRepo.On("GetByID", mock.Anything, "up").Return(upload, nil)
+		m.uploadRepo.On("Update", mock.Anything, upload).Return(nil)
+		// The user was deleted while uploading.
+		m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, repository.ErrNotFound)
+		m.uploadRepo.On("Delete", mock.Anything, "up").Return(nil)
+
+		_, _, err := uc.WriteChunk(ctx, "up", 0, strings.NewReader("hello world"), nil)
+		assert.ErrorIs(t, err, ErrNotFound)
+		m.uploadRepo.AssertCalled(t, "Delete", mock.Anything, "up")
+		_, err = staging.Open(ctx, "up")
+		assert.Error(t, err, "staged file should be removed")
+	})
+
+	t.Run("offset mismatch", func(t *testing.T) {
+		uc, m, staging := newResumableTestUseCase(t)
+		m.uploadRepo.On("GetByID", mock.Anything, "up").Return(newUpload(t, staging), nil)
+
+		_, _, err := uc.WriteChunk(ctx, "up", 3, strings.NewReader("lo"), nil)
+		assert.ErrorIs(t, err, ErrUploadOffsetMismatch)
+	})
+
+	t.Run("checksum mismatch discards the chunk", func(t *testing.T) {
+		uc, m, staging := newResumableTestUseCase(t)
+		m.uploadRepo.On("GetByID", mock.Anything, "up").Return(newUpload(t, staging), nil)
+
+		sum := sha1.Sum([]byte("other"))
+		_, _, err := uc.WriteChunk(ctx, "up", 0, strings.NewReader("hello"), &UploadChecksum{Algorithm: "sha1", Sum: sum[:]})
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+		reader, err := staging.Open(ctx, "up")
+		require.NoError(t, err)
+		defer reader.Close()
+		content, _ := io.ReadAll(reader)
+		assert.Empty(t, content)
+	})
+
+	t.Run("chunk longer than upload", func(t *testing.T) {
+		uc, m, staging := newResumableTestUseCase(t)
+		m.uploadRepo.On("GetByID", mock.Anything, "up").Return(newUpload(t, staging), nil)
+
+		_, _, err := uc.WriteChunk(ctx, "up", 0, strings.NewReader("hello world!!"), nil)
+		assert.ErrorIs(t, err, ErrUploadTooLarge)
+	})
+
+	t.Run("concurrent chunks at one offset", func(t *testing.T) {
+		uc, m, staging := newResumableTestUseCase(t)
+		upload := newUpload(t, staging)
+		m.uploadRepo.On("GetByID", mock.Anything, "up").Return(upload, nil)
+		m.uploadRepo.On("Update", mock.Anything, upload).Return(nil)
+
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				_, _, err := uc.WriteChunk(ctx, "up", 0, strings.NewReader("hello"), nil)
+				errs <- err
+			}()
+		}
+		var mismatches int
+		for i := 0; i < 2; i++ {
+			if err := <-errs; err != nil {
+				assert.ErrorIs(t, err, ErrUploadOffsetMismatch)
+				mismatches++
+			}
+		}
+		assert.Equal(t, 1, mismatches)
+		assert.Equal(t, int64(5), upload.Offset)
+
+		reader, err := staging.Open(ctx, "up")
+		require.NoError(t, err)
+		defer reader.Close()
+		content, _ := io.ReadAll(reader)
+		assert.Equal(t, "hello", string(content))
+	})
+
+	t.Run("unsupported checksum algorithm", func(t *testing.T) {
+		uc, m, staging := newResumableTestUseCase(t)
+		m.uploadRepo.On("GetByID", mock.Anything, "up").Return(newUpload(t, staging), nil)
+
+		_, _, err := uc.WriteChunk(ctx, "up", 0, strings.NewReader("hello"), &UploadChecksum{Algorithm: "crc32"})
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	})
+}
+
+func TestResumableUploadUseCase_Terminate(t *testing.T) {
+	uc, m, staging := newResumableTestUseCase(t)
+	ctx := context.Background()
+	require.NoError(t, staging.Create(ctx, "up"))
+
+	m.uploadRepo.On("GetByID", mock.Anything, "up").Return(&entity.Upload{ID: "up"}, nil)
+	m.uploadRepo.On("Delete", mock.Anything, "up").Return(nil)
+	m.uploadRepo.On("GetByID", mock.Anything, "missing").Return(nil, repository.ErrNotFound)
+
+	require.NoError(t, uc.Terminate(ctx, "up"))
+	assert.ErrorIs(t, uc.Terminate(ctx, "missing"), ErrNotFound)
+}
+
+func TestResumableUploadUseCase_Expiry(t *testing.T) {
+	ctx := context.Background()
+	newUseCase := func(t *testing.T) (*ResumableUploadUseCase, resumableMocks, *storage.LocalStagingArea) {
+		uc, m, staging := newResumableTestUseCase(t)
+		uc.expiry = time.Hour
+		return uc, m, staging
+	}
+
+	t.Run("expired upload is gone", func(t *testing.T) {
+		uc, m, _ := newUseCase(t)
+		m.uploadRepo.On("GetByID", mock.Anything, "up").
+			Return(&entity.Upload{ID: "up", Length: 11, UpdatedAt: time.Now().Add(-2 * time.Hour)}, nil)
+
+		_, err := uc.Get(ctx, "up")
+		assert.ErrorIs(t, err, ErrGone)
+		_, _, err = uc.WriteChunk(ctx, "up", 0, strings.NewReader("hello"), nil)
+		assert.ErrorIs(t, err, ErrGone)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		uc, _, _ := newUseCase(t)
+		updatedAt := time.Now()
+		expiresAt, ok := uc.ExpiresAt(&entity.Upload{UpdatedAt: updatedAt})
+		require.True(t, ok)
+		assert.Equal(t, updatedAt.Add(time.Hour), expiresAt)
+
+		audioID := uint(9)
+		_, ok = uc.ExpiresAt(&entity.Upload{UpdatedAt: updatedAt, AudioID: &audioID})
+		assert.False(t, ok, "completed uploads do not expire")
+	})
+
+	t.Run("stale uploads are discarded", func(t *testing.T) {
+		uc, m, staging := newUseCase(t)
+		require.NoError(t, staging.Create(ctx, "stale"))
+		require.NoError(t, staging.Create(ctx, "resumed"))
+
+		stale := &entity.Upload{ID: "stale", UpdatedAt: time.Now().Add(-2 * time.Hour)}
+		m.uploadRepo.On("ListStale", mock.Anything, mock.AnythingOfType("time.Time")).
+			Return([]*entity.Upload{stale, {ID: "resumed", UpdatedAt: stale.UpdatedAt}}, nil)
+		m.uploadRepo.On("GetByID", mock.Anything, "stale").Return(stale, nil)
+		// A chunk arrived after the upload was listed
+		m.uploadRepo.On("GetByID", mock.Anything, "resumed").Return(&entity.Upload{ID: "resumed", UpdatedAt: time.Now()}, nil)
+		m.uploadRepo.On("Delete", mock.Anything, "stale").Return(nil)
+
+		expired, err := uc.ExpireStale(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, expired)
+		_, err = staging.Open(ctx, "stale")
+		assert.Error(t, err)
+		reader, err := staging.Open(ctx, "resumed")
+		require.NoError(t, err)
+		reader.Close()
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		uc, _, _ := newResumableTestUseCase(t)
+		expired, err := uc.ExpireStale(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, expired)
+	})
+}
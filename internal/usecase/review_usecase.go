@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// MaxReviewNotes is the length in characters of the longest reviewer notes.
+const MaxReviewNotes = 2000
+
+// ReviewRequest is the verdict of a reviewer on a take.
+type ReviewRequest struct {
+	ReviewerID uint
+	Status     entity.ReviewStatus
+	// Reasons are codes from entity.ReviewReasons. Rejecting a take or asking
+	// for it to be recorded again takes at least one.
+	Reasons []string
+	Notes   string
+}
+
+// ReviewPage is one page of the review queue.
+type ReviewPage struct {
+	Audios []*entity.Audio
+	Total  int // Unreviewed takes across all pages
+	Limit  int
+	Offset int
+}
+
+// ReviewUseCase lets reviewers accept or reject completed takes and tells
+// speakers which of their takes to record again.
+type ReviewUseCase struct {
+	reviewRepository  repository.AudioReviewRepository
+	audioRepository   repository.AudioRepository
+	userRepository    repository.UserRepository
+	sessionRepository repository.RecordingSessionRepository
+}
+
+func NewReviewUseCase(
+	reviewRepository repository.AudioReviewRepository,
+	audioRepository repository.AudioRepository,
+	userRepository repository.UserRepository,
+	sessionRepository repository.RecordingSessionRepository,
+) *ReviewUseCase {
+	return &ReviewUseCase{
+		reviewRepository:  reviewRepository,
+		audioRepository:   audioRepository,
+		userRepository:    userRepository,
+		sessionRepository: sessionRepository,
+	}
+}
+
+// normalize validates the verdict and returns it with reasons deduplicated
+// and notes trimmed.
+func (req ReviewRequest) normalize() (ReviewRequest, error) {
+	switch req.Status {
+	case entity.ReviewStatusApproved, entity.ReviewStatusRejected, entity.ReviewStatusNeedsRerecord:
+	default:
+		return req, fmt.Errorf("unknown review status %q: %w", req.Status, ErrInvalidArgument)
+	}
+	reasons := []string{}
+	for _, reason := range req.Reasons {
+		if !slices.Contains(entity.ReviewReasons, reason) {
+			return req, fmt.Errorf("unknown reason %q: %w", reason, ErrInvalidArgument)
+		}
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	if req.Status != entity.ReviewStatusApproved && len(reasons) == 0 {
+		return req, fmt.Errorf("a %s take needs a reason: %w", req.Status, ErrInvalidArgument)
+	}
+	req.Reasons = reasons
+	req.Notes = strings.TrimSpace(req.Notes)
+	if utf8.RuneCountInString(req.Notes) > MaxReviewNotes {
+		return req, fmt.Errorf("notes are limited to %d characters: %w", MaxReviewNotes, ErrInvalidArgument)
+	}
+	return req, nil
+}
+
+// Review records the verdict of a reviewer on a completed take, replacing any
+// earlier one. Speakers may not review their own takes.
+func (uc *ReviewUseCase) Review(ctx context.Context, audioID uint, req ReviewRequest) (*entity.AudioReview, error) {
+	req, err := req.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.userRepository.GetByID(ctx, req.ReviewerID); err != nil {
+		return nil, wrapRepoError(err, "failed to get reviewer")
+	}
+	audio, err := uc.audioRepository.GetByID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	if audio.UserID == req.ReviewerID {
+		return nil, fmt.Errorf("user %d may not review their own take: %w", req.ReviewerID, ErrForbidden)
+	}
+	if audio.Status != entity.AudioStatusCompleted {
+		return nil, fmt.Errorf("audio %d is %s, only completed takes are reviewed: %w", audioID, audio.Status, ErrConflict)
+	}
+
+	review, err := uc.reviewRepository.Save(ctx, &entity.AudioReview{
+		AudioID:    audioID,
+		Status:     req.Status,
+		ReviewerID: req.ReviewerID,
+		Reasons:    req.Reasons,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save review: %v", err)
+	}
+	return review, nil
+}
+
+// ReviewSession records the verdict of a reviewer on a recording session,
+// such as rejecting every take recorded with a broken microphone, and returns
+// the reviews of its completed takes in take order. Takes that complete later
+// get the same verdict. Speakers may not review their own sessions.
+func (uc *ReviewUseCase) ReviewSession(ctx context.Context, sessionID uint, req ReviewRequest) ([]*entity.AudioReview, error) {
+	req, err := req.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.userRepository.GetByID(ctx, req.ReviewerID); err != nil {
+		return nil, wrapRepoError(err, "failed to get reviewer")
+	}
+	session, err := uc.sessionRepository.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get recording session")
+	}
+	if session.UserID == req.ReviewerID {
+		return nil, fmt.Errorf("user %d may not review their own session: %w", req.ReviewerID, ErrForbidden)
+	}
+
+	reviews, err := uc.reviewRepository.SaveSession(ctx, &entity.SessionReview{
+		SessionID:  sessionID,
+		Status:     req.Status,
+		ReviewerID: req.ReviewerID,
+		Reasons:    req.Reasons,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save review of recording session: %v", err)
+	}
+	return reviews, nil
+}
+
+// Process gives a take that completes the verdict on its recording session,
+// if the session was reviewed. It implements AudioPostProcessor.
+func (uc *ReviewUseCase) Process(ctx context.Context, audio *entity.Audio, _ string) error {
+	if audio.SessionID == 0 {
+		return nil
+	}
+	verdict, err := uc.reviewRepository.GetBySessionID(ctx, audio.SessionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get review of recording session: %v", err)
+	}
+	_, err = uc.reviewRepository.Save(ctx, &entity.AudioReview{
+		AudioID:    audio.ID,
+		Status:     verdict.Status,
+		ReviewerID: verdict.ReviewerID,
+		Reasons:    verdict.Reasons,
+		Notes:      verdict.Notes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save review: %v", err)
+	}
+	return nil
+}
+
+// Get returns the review of a take, which is unreviewed if nobody reviewed it
+// yet.
+func (uc *ReviewUseCase) Get(ctx context.Context, audioID uint) (*entity.AudioReview, error) {
+	if _, err := uc.audioRepository.GetByID(ctx, audioID); err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	review, err := uc.reviewRepository.GetByAudioID(ctx, audioID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return &entity.AudioReview{AudioID: audioID, Status: entity.ReviewStatusUnreviewed, Reasons: []string{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %v", err)
+	}
+	return review, nil
+}
+
+// Queue returns a page of the completed takes nobody reviewed, those flagged
+// by quality analysis first. A zero limit selects DefaultPageSize.
+func (uc *ReviewUseCase) Queue(ctx context.Context, filter repository.ReviewQueueFilter) (*ReviewPage, error) {
+	limit, err := pageLimit(filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit = limit
+
+	audios, total, err := uc.reviewRepository.ListUnreviewed(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unreviewed audio: %v", err)
+	}
+	return &ReviewPage{Audios: audios, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+// Rejected returns the takes of the user that were rejected or have to be
+// recorded again, most recently reviewed first.
+func (uc *ReviewUseCase) Rejected(ctx context.Context, userID uint) ([]*entity.RejectedTake, error) {
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	takes, err := uc.reviewRepository.ListRejected(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rejected takes: %v", err)
+	}
+	return takes, nil
+}
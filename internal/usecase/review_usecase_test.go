@@ -0,0 +1,238 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReviewUseCase_Review(t *testing.T) {
+	completed := &entity.Audio{ID: 5, UserID: 1, Status: entity.AudioStatusCompleted}
+	tests := []struct {
+		name          string
+		req           usecase.ReviewRequest
+		mockSetup     func(*repoMocks.MockAudioReviewRepository, *repoMocks.MockAudioRepository, *repoMocks.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name: "approve",
+			req:  usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusApproved, Notes: "  clean  "},
+			mockSetup: func(reviewRepo *repoMocks.MockAudioReviewRepository, audioRepo *repoMocks.MockAudioRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(completed, nil)
+				reviewRepo.On("Save", mock.Anything, &entity.AudioReview{AudioID: 5, Status: entity.ReviewStatusApproved, ReviewerID: 2, Reasons: []string{}, Notes: "clean"}).
+					Return(&entity.AudioReview{AudioID: 5, Status: entity.ReviewStatusApproved}, nil)
+			},
+		},
+		{
+			name: "reject with repeated reasons",
+			req: usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusRejected,
+				Reasons: []string{entity.ReviewReasonNoise, entity.ReviewReasonNoise, entity.ReviewReasonClipping}},
+			mockSetup: func(reviewRepo *repoMocks.MockAudioReviewRepository, audioRepo *repoMocks.MockAudioRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(completed, nil)
+				reviewRepo.On("Save", mock.Anything, mock.MatchedBy(func(review *entity.AudioReview) bool {
+					return assert.ObjectsAreEqual([]string{entity.ReviewReasonNoise, entity.ReviewReasonClipping}, review.Reasons)
+				})).Return(&entity.AudioReview{AudioID: 5, Status: entity.ReviewStatusRejected}, nil)
+			},
+		},
+		{
+			name:          "unknown status",
+			req:           usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusUnreviewed},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "unknown reason",
+			req:           usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusRejected, Reasons: []string{"boring"}},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "re-record without reason",
+			req:           usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusNeedsRerecord},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "notes too long",
+			req:           usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusApproved, Notes: strings.Repeat("a", usecase.MaxReviewNotes+1)},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name: "unknown reviewer",
+			req:  usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusApproved},
+			mockSetup: func(reviewRepo *repoMocks.MockAudioReviewRepository, audioRepo *repoMocks.MockAudioRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("failed to get user 2: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+		{
+			name: "own take",
+			req:  usecase.ReviewRequest{ReviewerID: 1, Status: entity.ReviewStatusApproved},
+			mockSetup: func(reviewRepo *repoMocks.MockAudioReviewRepository, audioRepo *repoMocks.MockAudioRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(completed, nil)
+			},
+			expectedError: usecase.ErrForbidden,
+		},
+		{
+			name: "take not converted",
+			req:  usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusApproved},
+			mockSetup: func(reviewRepo *repoMocks.MockAudioReviewRepository, audioRepo *repoMocks.MockAudioRepository, userRepo *repoMocks.MockUserRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				audioRepo.On("GetByID", mock.Anything, uint(5)).Return(&entity.Audio{ID: 5, UserID: 1, Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedError: usecase.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviewRepo := repoMocks.NewMockAudioReviewRepository(t)
+			audioRepo := repoMocks.NewMockAudioRepository(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			if tt.mockSetup != nil {
+				tt.mockSetup(reviewRepo, audioRepo, userRepo)
+			}
+			uc := usecase.NewReviewUseCase(reviewRepo, audioRepo, userRepo, repoMocks.NewMockRecordingSessionRepository(t))
+
+			review, err := uc.Review(context.Background(), 5, tt.req)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.req.Status, review.Status)
+		})
+	}
+}
+
+func TestReviewUseCase_ReviewSession(t *testing.T) {
+	rejection := usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusRejected, Reasons: []string{"noise", "noise"}}
+
+	t.Run("flags the session", func(t *testing.T) {
+		reviewRepo := repoMocks.NewMockAudioReviewRepository(t)
+		userRepo := repoMocks.NewMockUserRepository(t)
+		sessionRepo := repoMocks.NewMockRecordingSessionRepository(t)
+		uc := usecase.NewReviewUseCase(reviewRepo, repoMocks.NewMockAudioRepository(t), userRepo, sessionRepo)
+
+		userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+		sessionRepo.On("GetByID", mock.Anything, uint(4)).Return(&entity.RecordingSession{ID: 4, UserID: 1}, nil)
+		reviewRepo.On("SaveSession", mock.Anything, mock.MatchedBy(func(r *entity.SessionReview) bool {
+			return r.SessionID == 4 && r.Status == entity.ReviewStatusRejected && len(r.Reasons) == 1 && r.ReviewerID == 2
+		})).Return([]*entity.AudioReview{{AudioID: 5}, {AudioID: 7}}, nil)
+
+		reviews, err := uc.ReviewSession(context.Background(), 4, rejection)
+		assert.NoError(t, err)
+		assert.Len(t, reviews, 2)
+	})
+
+	t.Run("own session", func(t *testing.T) {
+		userRepo := repoMocks.NewMockUserRepository(t)
+		sessionRepo := repoMocks.NewMockRecordingSessionRepository(t)
+		uc := usecase.NewReviewUseCase(repoMocks.NewMockAudioReviewRepository(t), repoMocks.NewMockAudioRepository(t), userRepo, sessionRepo)
+
+		userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+		sessionRepo.On("GetByID", mock.Anything, uint(4)).Return(&entity.RecordingSession{ID: 4, UserID: 2}, nil)
+		_, err := uc.ReviewSession(context.Background(), 4, rejection)
+		assert.ErrorIs(t, err, usecase.ErrForbidden)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		userRepo := repoMocks.NewMockUserRepository(t)
+		sessionRepo := repoMocks.NewMockRecordingSessionRepository(t)
+		uc := usecase.NewReviewUseCase(repoMocks.NewMockAudioReviewRepository(t), repoMocks.NewMockAudioRepository(t), userRepo, sessionRepo)
+
+		userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+		sessionRepo.On("GetByID", mock.Anything, uint(4)).Return(nil, fmt.Errorf("failed to get recording session 4: %w", repository.ErrNotFound))
+		_, err := uc.ReviewSession(context.Background(), 4, rejection)
+		assert.ErrorIs(t, err, usecase.ErrNotFound)
+	})
+
+	t.Run("missing reason", func(t *testing.T) {
+		uc := usecase.NewReviewUseCase(repoMocks.NewMockAudioReviewRepository(t), repoMocks.NewMockAudioRepository(t),
+			repoMocks.NewMockUserRepository(t), repoMocks.NewMockRecordingSessionRepository(t))
+		_, err := uc.ReviewSession(context.Background(), 4, usecase.ReviewRequest{ReviewerID: 2, Status: entity.ReviewStatusRejected})
+		assert.ErrorIs(t, err, usecase.ErrInvalidArgument)
+	})
+}
+
+func TestReviewUseCase_Process(t *testing.T) {
+	t.Run("take of a reviewed session", func(t *testing.T) {
+		reviewRepo := repoMocks.NewMockAudioReviewRepository(t)
+		uc := usecase.NewReviewUseCase(reviewRepo, repoMocks.NewMockAudioRepository(t), repoMocks.NewMockUserRepository(t), repoMocks.NewMockRecordingSessionRepository(t))
+
+		reviewRepo.On("GetBySessionID", mock.Anything, uint(4)).Return(&entity.SessionReview{SessionID: 4, Status: entity.ReviewStatusRejected,
+			ReviewerID: 2, Reasons: []string{"clipping"}, Notes: "broken mic"}, nil)
+		reviewRepo.On("Save", mock.Anything, &entity.AudioReview{AudioID: 8, Status: entity.ReviewStatusRejected, ReviewerID: 2,
+			Reasons: []string{"clipping"}, Notes: "broken mic"}).Return(&entity.AudioReview{AudioID: 8}, nil)
+		assert.NoError(t, uc.Process(context.Background(), &entity.Audio{ID: 8, SessionID: 4}, ""))
+	})
+
+	t.Run("take of an unreviewed session", func(t *testing.T) {
+		reviewRepo := repoMocks.NewMockAudioReviewRepository(t)
+		uc := usecase.NewReviewUseCase(reviewRepo, repoMocks.NewMockAudioRepository(t), repoMocks.NewMockUserRepository(t), repoMocks.NewMockRecordingSessionRepository(t))
+
+		reviewRepo.On("GetBySessionID", mock.Anything, uint(4)).Return(nil, fmt.Errorf("failed to get review of session 4: %w", repository.ErrNotFound))
+		assert.NoError(t, uc.Process(context.Background(), &entity.Audio{ID: 8, SessionID: 4}, ""))
+	})
+
+	t.Run("take without a session", func(t *testing.T) {
+		uc := usecase.NewReviewUseCase(repoMocks.NewMockAudioReviewRepository(t), repoMocks.NewMockAudioRepository(t),
+			repoMocks.NewMockUserRepository(t), repoMocks.NewMockRecordingSessionRepository(t))
+		assert.NoError(t, uc.Process(context.Background(), &entity.Audio{ID: 8}, ""))
+	})
+}
+
+func TestReviewUseCase_Get(t *testing.T) {
+	reviewRepo := repoMocks.NewMockAudioReviewRepository(t)
+	audioRepo := repoMocks.NewMockAudioRepository(t)
+	uc := usecase.NewReviewUseCase(reviewRepo, audioRepo, repoMocks.NewMockUserRepository(t), repoMocks.NewMockRecordingSessionRepository(t))
+
+	audioRepo.On("GetByID", mock.Anything, uint(5)).Return(&entity.Audio{ID: 5}, nil)
+	reviewRepo.On("GetByAudioID", mock.Anything, uint(5)).Return(nil, fmt.Errorf("failed to get review of audio 5: %w", repository.ErrNotFound))
+	review, err := uc.Get(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ReviewStatusUnreviewed, review.Status)
+
+	audioRepo.On("GetByID", mock.Anything, uint(6)).Return(nil, fmt.Errorf("failed to get audio 6: %w", repository.ErrNotFound))
+	_, err = uc.Get(context.Background(), 6)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+}
+
+func TestReviewUseCase_Queue(t *testing.T) {
+	reviewRepo := repoMocks.NewMockAudioReviewRepository(t)
+	uc := usecase.NewReviewUseCase(reviewRepo, repoMocks.NewMockAudioRepository(t), repoMocks.NewMockUserRepository(t), repoMocks.NewMockRecordingSessionRepository(t))
+
+	reviewRepo.On("ListUnreviewed", mock.Anything, repository.ReviewQueueFilter{UserID: 1, Limit: usecase.DefaultPageSize}).
+		Return([]*entity.Audio{{ID: 5}}, 1, nil)
+	page, err := uc.Queue(context.Background(), repository.ReviewQueueFilter{UserID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+	assert.Equal(t, usecase.DefaultPageSize, page.Limit)
+
+	_, err = uc.Queue(context.Background(), repository.ReviewQueueFilter{Limit: usecase.MaxPageSize + 1})
+	assert.ErrorIs(t, err, usecase.ErrInvalidArgument)
+}
+
+func TestReviewUseCase_Rejected(t *testing.T) {
+	reviewRepo := repoMocks.NewMockAudioReviewRepository(t)
+	userRepo := repoMocks.NewMockUserRepository(t)
+	uc := usecase.NewReviewUseCase(reviewRepo, repoMocks.NewMockAudioRepository(t), userRepo, repoMocks.NewMockRecordingSessionRepository(t))
+
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+	reviewRepo.On("ListRejected", mock.Anything, uint(1)).Return([]*entity.RejectedTake{{PhraseID: 3}}, nil)
+	takes, err := uc.Rejected(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, takes, 1)
+
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, fmt.Errorf("failed to get user 2: %w", repository.ErrNotFound))
+	_, err = uc.Rejected(context.Background(), 2)
+	assert.ErrorIs(t, err, usecase.ErrNotFound)
+}
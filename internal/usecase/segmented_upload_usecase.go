@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ardfard/sb-test/internal/domain/converter"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/ardfard/sb-test/pkg/silence"
+	"github.com/ardfard/sb-test/pkg/util"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// MaxSegmentedPhrases limits the phrases read in a single recording.
+const MaxSegmentedPhrases = 500
+
+// Segment is one part of a segmented recording.
+type Segment struct {
+	PhraseID uint    // Zero for segments beyond the listed phrases
+	Start    float64 // Seconds from the start of the recording
+	End      float64
+	Audio    *entity.Audio // The stored audio, unless the segmentation mismatched
+}
+
+// SegmentationResult describes how a recording was split.
+type SegmentationResult struct {
+	Expected int // Number of phrases listed
+	Segments []Segment
+	// Mismatch is set when the number of segments differs from Expected. In
+	// that case nothing is stored.
+	Mismatch bool
+}
+
+// SegmentedUploadUseCase splits a recording of several phrases, read in
+// order with pauses between them, into one audio per phrase. Each segment
+// goes through the same storage and conversion path as a regular upload.
+type SegmentedUploadUseCase struct {
+	uploads   *UploadAudioUseCase
+	converter converter.AudioConverter
+	options   silence.Options
+}
+
+func NewSegmentedUploadUseCase(uploads *UploadAudioUseCase, converter converter.AudioConverter, options silence.Options) *SegmentedUploadUseCase {
+	return &SegmentedUploadUseCase{
+		uploads:   uploads,
+		converter: converter,
+		options:   options,
+	}
+}
+
+// Options returns the configured silence detection options.
+func (uc *SegmentedUploadUseCase) Options() silence.Options {
+	return uc.options
+}
+
+// Policy returns the restrictions applied to uploads.
+func (uc *SegmentedUploadUseCase) Policy() UploadPolicy {
+	return uc.uploads.Policy()
+}
+
+// Upload splits the recording at silences detected with options and stores
+// the segments as the audio of phraseIDs, in order, in the recording session
+// sessionID unless it is 0. When the number of
+// segments differs from the number of phrases, it stores nothing and returns
+// the detected segments with Mismatch set.
+func (uc *SegmentedUploadUseCase) Upload(ctx context.Context, filename string, content io.Reader, userID uint, phraseIDs []uint, sessionID uint, options silence.Options) (*SegmentationResult, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidArgument)
+	}
+	if err := uc.checkPhrases(ctx, userID, phraseIDs); err != nil {
+		return nil, err
+	}
+	if err := uc.uploads.checkSession(ctx, sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	recording, err := uc.decode(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(recording)
+
+	ranges, err := uc.split(recording, options)
+	if err != nil {
+		return nil, err
+	}
+	result := &SegmentationResult{
+		Expected: len(phraseIDs),
+		Segments: make([]Segment, len(ranges)),
+		Mismatch: len(ranges) != len(phraseIDs),
+	}
+	for i, r := range ranges {
+		result.Segments[i] = Segment{Start: r.Start, End: r.End}
+		if i < len(phraseIDs) {
+			result.Segments[i].PhraseID = phraseIDs[i]
+		}
+	}
+	if result.Mismatch {
+		return result, nil
+	}
+
+	// Check every segment against the policy before storing any of them.
+	paths := make([]string, len(ranges))
+	metas := make([]*entity.AudioMetadata, len(ranges))
+	defer func() {
+		for _, path := range paths {
+			if path != "" {
+				os.Remove(path)
+			}
+		}
+	}()
+	for i, r := range ranges {
+		if paths[i], err = uc.extract(recording, r); err != nil {
+			return nil, err
+		}
+		if metas[i], err = uc.converter.Probe(ctx, paths[i]); err != nil {
+			return nil, fmt.Errorf("failed to probe segment %d: %v", i+1, err)
+		}
+		if err := uc.Policy().checkMetadata(metas[i]); err != nil {
+			return nil, fmt.Errorf("segment %d for phrase %d: %w", i+1, phraseIDs[i], err)
+		}
+	}
+
+	// The segments are stored together or not at all.
+	var stored []*entity.Audio
+	for i := range result.Segments {
+		name := fmt.Sprintf("%s (segment %d of %d)", filename, i+1, len(ranges))
+		audio, err := uc.uploads.store(ctx, userID, phraseIDs[i], sessionID, name, paths[i], metas[i])
+		if err != nil {
+			if discardErr := uc.uploads.discard(ctx, stored); discardErr != nil {
+				logger.Errorf("Failed to discard segments stored before segment %d failed: %v", i+1, discardErr)
+			}
+			return nil, err
+		}
+		stored = append(stored, audio)
+		result.Segments[i].Audio = audio
+	}
+	return result, nil
+}
+
+// checkPhrases verifies that the user exists and that each phrase exists and
+// has no recording by the user yet, other than a failed one.
+func (uc *SegmentedUploadUseCase) checkPhrases(ctx context.Context, userID uint, phraseIDs []uint) error {
+	if len(phraseIDs) == 0 {
+		return fmt.Errorf("at least one phrase is required: %w", ErrInvalidArgument)
+	}
+	if len(phraseIDs) > MaxSegmentedPhrases {
+		return fmt.Errorf("at most %d phrases can be read in one recording, got %d: %w", MaxSegmentedPhrases, len(phraseIDs), ErrInvalidArgument)
+	}
+
+	seen := make(map[uint]bool, len(phraseIDs))
+	for _, phraseID := range phraseIDs {
+		if seen[phraseID] {
+			return fmt.Errorf("phrase %d is listed more than once: %w", phraseID, ErrInvalidArgument)
+		}
+		seen[phraseID] = true
+	}
+
+	if err := uc.uploads.checkUser(ctx, userID); err != nil {
+		return err
+	}
+	for _, phraseID := range phraseIDs {
+		if err := uc.uploads.checkPhrase(ctx, userID, phraseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode checks the recording against the upload policy and converts it to
+// a temporary WAV file, whose path it returns.
+func (uc *SegmentedUploadUseCase) decode(ctx context.Context, content io.Reader) (string, error) {
+	policy := uc.Policy()
+	format, content, err := policy.sniff(content)
+	if err != nil {
+		return "", err
+	}
+	spooled, err := util.WriteTempFile(policy.limit(content), format)
+	if err != nil {
+		return "", fmt.Errorf("failed to spool upload: %v", err)
+	}
+	defer os.Remove(spooled)
+
+	info, err := os.Stat(spooled)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat spooled upload: %v", err)
+	}
+	if err := policy.checkSize(info.Size()); err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(spooled)
+	if err != nil {
+		return "", fmt.Errorf("failed to open spooled upload: %v", err)
+	}
+	defer file.Close()
+
+	output, err := uc.converter.ConvertFromReader(ctx, file, format, "wav")
+	if err != nil {
+		return "", reject(ErrUnprocessable, RejectUnreadableAudio, "%v", err)
+	}
+	defer output.Close()
+	return util.WriteTempFile(output, "wav")
+}
+
+func (uc *SegmentedUploadUseCase) split(recording string, options silence.Options) ([]silence.Range, error) {
+	file, err := os.Open(recording)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %v", err)
+	}
+	defer file.Close()
+
+	reader, err := wav.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %v", err)
+	}
+	ranges, err := silence.Split(reader, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect silence: %v", err)
+	}
+	return ranges, nil
+}
+
+// extract writes the range r of the recording to a temporary WAV file and
+// returns its path.
+func (uc *SegmentedUploadUseCase) extract(recording string, r silence.Range) (string, error) {
+	file, err := os.Open(recording)
+	if err != nil {
+		return "", fmt.Errorf("failed to open recording: %v", err)
+	}
+	defer file.Close()
+
+	segment, err := wav.DecodeRange(file, r.Start, r.End)
+	if err != nil {
+		return "", fmt.Errorf("failed to read segment: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := wav.Encode(&buf, segment.SampleRate, segment.Channels, segment.Samples); err != nil {
+		return "", fmt.Errorf("failed to encode segment: %v", err)
+	}
+	return util.WriteTempFile(&buf, "wav")
+}
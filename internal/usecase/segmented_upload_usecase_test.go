@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/pkg/silence"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// script is a 1 kHz recording of three phrases separated by half a second of silence.
+func script(t *testing.T) []byte {
+	var samples []float64
+	for _, part := range []struct {
+		ms   int
+		loud bool
+	}{{200, false}, {400, true}, {500, false}, {300, true}, {500, false}, {600, true}, {200, false}} {
+		for i := 0; i < part.ms; i++ {
+			v := 0.0
+			if part.loud {
+				v = 0.5 - float64(i%2)
+			}
+			samples = append(samples, v)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, 1000, 1, samples))
+	return buf.Bytes()
+}
+
+type segmentedMocks struct {
+	repo       *repoMocks.MockAudioRepository
+	storage    *storageMocks.MockStorage
+	converter  *converterMocks.MockAudioConverter
+	queue      *queueMocks.MockTaskQueue
+	userRepo   *repoMocks.MockUserRepository
+	phraseRepo *repoMocks.MockPhraseRepository
+}
+
+func TestSegmentedUploadUseCase_Upload(t *testing.T) {
+	options := silence.Options{ThresholdDB: -40, MinDuration: 300 * time.Millisecond, Padding: 100 * time.Millisecond}
+	recording := script(t)
+
+	// expectDecode sets up the checks and conversion that precede splitting.
+	expectDecode := func(m segmentedMocks, phraseIDs ...uint) {
+		m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+		for _, id := range phraseIDs {
+			m.phraseRepo.On("GetByID", mock.Anything, id).Return(&entity.Phrase{ID: id, UserID: 1}, nil)
+			m.repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), id).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+		}
+		m.converter.On("ConvertFromReader", mock.Anything, mock.Anything, "mp3", "wav").Return(io.NopCloser(bytes.NewReader(recording)), nil)
+	}
+
+	tests := []struct {
+		name           string
+		phraseIDs      []uint
+		policy         UploadPolicy
+		setupMocks     func(segmentedMocks)
+		check          func(*testing.T, *SegmentationResult)
+		expectedError  error
+		expectedErrMsg string
+	}{
+		{
+			name:      "stores a segment per phrase",
+			phraseIDs: []uint{7, 5, 9},
+			setupMocks: func(m segmentedMocks) {
+				expectDecode(m, 7, 5, 9)
+				m.converter.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "wav", Duration: 0.5}, nil)
+				for i, id := range []uint{7, 5, 9} {
+					m.repo.On("Store", mock.Anything, mock.MatchedBy(func(a *entity.Audio) bool {
+						return a.UserID == 1 && a.PhraseID == id && a.OriginalName == fmt.Sprintf("script.mp3 (segment %d of 3)", i+1)
+					})).Return(&entity.Audio{ID: uint(i + 1), UserID: 1, PhraseID: id}, nil)
+					m.storage.On("Upload", mock.Anything, fmt.Sprintf("audio/original/1-%d.wav", id), mock.Anything).Return(nil)
+					m.queue.On("Enqueue", mock.Anything, uint(i+1)).Return(nil)
+				}
+			},
+			check: func(t *testing.T, result *SegmentationResult) {
+				assert.False(t, result.Mismatch)
+				require.Len(t, result.Segments, 3)
+				for i, expected := range []Segment{
+					{PhraseID: 7, Start: 0.1, End: 0.7},
+					{PhraseID: 5, Start: 1, End: 1.5},
+					{PhraseID: 9, Start: 1.8, End: 2.6},
+				} {
+					assert.Equal(t, expected.PhraseID, result.Segments[i].PhraseID)
+					assert.InDelta(t, expected.Start, result.Segments[i].Start, 1e-9)
+					assert.InDelta(t, expected.End, result.Segments[i].End, 1e-9)
+					require.NotNil(t, result.Segments[i].Audio)
+					assert.Equal(t, uint(i+1), result.Segments[i].Audio.ID)
+				}
+			},
+		},
+		{
+			name:      "failing segment discards the stored ones",
+			phraseIDs: []uint{7, 5, 9},
+			setupMocks: func(m segmentedMocks) {
+				expectDecode(m, 7, 5, 9)
+				m.converter.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "wav", Duration: 0.5}, nil)
+				for i, id := range []uint{7, 5} {
+					m.repo.On("Store", mock.Anything, mock.MatchedBy(func(a *entity.Audio) bool { return a.PhraseID == id })).
+						Return(&entity.Audio{ID: uint(i + 1), UserID: 1, PhraseID: id, StoragePath: fmt.Sprintf("audio/original/1-%d.wav", id)}, nil)
+					m.queue.On("Cancel", mock.Anything, uint(i+1)).Return(nil)
+					m.repo.On("Delete", mock.Anything, uint(i+1)).Return(nil)
+				}
+				m.storage.On("Upload", mock.Anything, "audio/original/1-7.wav", mock.Anything).Return(nil)
+				m.queue.On("Enqueue", mock.Anything, uint(1)).Return(nil)
+				m.storage.On("Upload", mock.Anything, "audio/original/1-5.wav", mock.Anything).Return(assert.AnError)
+				m.storage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+				m.storage.On("DeletePrefix", mock.Anything, "audio/original/1-7.").Return(nil)
+				m.storage.On("DeletePrefix", mock.Anything, "audio/original/1-5.").Return(nil)
+				m.storage.On("DeletePrefix", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedErrMsg: "failed to upload original file",
+		},
+		{
+			name:      "mismatch stores nothing",
+			phraseIDs: []uint{7, 5},
+			setupMocks: func(m segmentedMocks) {
+				expectDecode(m, 7, 5)
+			},
+			check: func(t *testing.T, result *SegmentationResult) {
+				assert.True(t, result.Mismatch)
+				assert.Equal(t, 2, result.Expected)
+				require.Len(t, result.Segments, 3)
+				assert.Equal(t, uint(5), result.Segments[1].PhraseID)
+				assert.Zero(t, result.Segments[2].PhraseID)
+				assert.Nil(t, result.Segments[0].Audio)
+			},
+		},
+		{
+			name:      "segment violating the policy stores nothing",
+			phraseIDs: []uint{7, 5, 9},
+			policy:    UploadPolicy{MinDuration: time.Second},
+			setupMocks: func(m segmentedMocks) {
+				expectDecode(m, 7, 5, 9)
+				m.converter.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "wav", Duration: 0.6}, nil).Once()
+			},
+			expectedError: ErrUnprocessable,
+		},
+		{
+			name:      "phrase already recorded",
+			phraseIDs: []uint{7},
+			setupMocks: func(m segmentedMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phraseRepo.On("GetByID", mock.Anything, uint(7)).Return(&entity.Phrase{ID: 7, UserID: 1}, nil)
+				m.repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(7)).Return(&entity.Audio{ID: 3}, nil)
+			},
+			expectedError: ErrConflict,
+		},
+		{
+			name:      "unknown phrase",
+			phraseIDs: []uint{7},
+			setupMocks: func(m segmentedMocks) {
+				m.userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				m.phraseRepo.On("GetByID", mock.Anything, uint(7)).Return(nil, fmt.Errorf("no rows: %w", repository.ErrNotFound))
+			},
+			expectedError: ErrNotFound,
+		},
+		{
+			name:          "duplicate phrase",
+			phraseIDs:     []uint{7, 7},
+			setupMocks:    func(segmentedMocks) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:          "no phrases",
+			setupMocks:    func(segmentedMocks) {},
+			expectedError: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := segmentedMocks{
+				repo:       repoMocks.NewMockAudioRepository(t),
+				storage:    storageMocks.NewMockStorage(t),
+				converter:  converterMocks.NewMockAudioConverter(t),
+				queue:      queueMocks.NewMockTaskQueue(t),
+				userRepo:   repoMocks.NewMockUserRepository(t),
+				phraseRepo: repoMocks.NewMockPhraseRepository(t),
+			}
+			tt.setupMocks(m)
+			uploads := NewUploadAudioUseCase(m.repo, m.storage, m.converter, m.queue, m.userRepo, m.phraseRepo, repoMocks.NewMockRecordingSessionRepository(t), repoMocks.NewMockAudioReviewRepository(t), tt.policy)
+			uc := NewSegmentedUploadUseCase(uploads, m.converter, options)
+
+			result, err := uc.Upload(context.Background(), "script.mp3", strings.NewReader(mp3Content), 1, tt.phraseIDs, 0, options)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			if tt.expectedErrMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, result)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// SetProcessingProfileUseCase changes the default processing profile of a user's downloads.
+type SetProcessingProfileUseCase struct {
+	userRepository repository.UserRepository
+	profiles       ProcessingProfiles
+}
+
+func NewSetProcessingProfileUseCase(userRepository repository.UserRepository, profiles ProcessingProfiles) *SetProcessingProfileUseCase {
+	return &SetProcessingProfileUseCase{
+		userRepository: userRepository,
+		profiles:       profiles,
+	}
+}
+
+// Set makes processingProfile the user's default. The empty name clears it.
+func (uc *SetProcessingProfileUseCase) Set(ctx context.Context, userID uint, processingProfile string) (*entity.User, error) {
+	if !uc.profiles.Has(processingProfile) {
+		return nil, fmt.Errorf("unknown processing profile %q: %w", processingProfile, ErrInvalidArgument)
+	}
+
+	user, err := uc.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	user.ProcessingProfile = processingProfile
+	if err := uc.userRepository.Update(ctx, user); err != nil {
+		return nil, wrapRepoError(err, "failed to update user")
+	}
+	return user, nil
+}
@@ -0,0 +1,75 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetProcessingProfileUseCase_Set(t *testing.T) {
+	profiles := usecase.ProcessingProfiles{"clean": {{Name: "highpass", Params: map[string]string{"f": "80"}}}}
+
+	tests := []struct {
+		name          string
+		profile       string
+		mockSetup     func(*repoMocks.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name:    "known profile",
+			profile: "clean",
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Name: "John Doe"}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.ProcessingProfile == "clean"
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "clear profile",
+			profile: "",
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, ProcessingProfile: "clean"}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.ProcessingProfile == ""
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "unknown profile",
+			profile:       "radio",
+			mockSetup:     func(*repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:    "unknown user",
+			profile: "clean",
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("get: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := repoMocks.NewMockUserRepository(t)
+			tt.mockSetup(mockUserRepo)
+
+			user, err := usecase.NewSetProcessingProfileUseCase(mockUserRepo, profiles).Set(context.Background(), 1, tt.profile)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.profile, user.ProcessingProfile)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+)
+
+// supportedFormats lists the output formats the converter can produce.
+var supportedFormats = map[string]bool{
+	"m4a":  true,
+	"flac": true,
+	"mp3":  true,
+	"wav":  true,
+}
+
+// ShareLinkSettings configures how share links are minted and redeemed.
+type ShareLinkSettings struct {
+	Secret     []byte
+	DefaultTTL time.Duration
+	MaxTTL     time.Duration
+	// PresignRedirect redirects to a presigned storage URL instead of
+	// streaming when the storage supports it and no conversion is needed.
+	PresignRedirect bool
+}
+
+// ShareLinkRequest describes the link to mint.
+type ShareLinkRequest struct {
+	Format    string
+	TTL       time.Duration // Zero means the configured default
+	SingleUse bool
+	IP        string // When set, the link can only be redeemed from this address
+}
+
+// SharedAudio is the result of redeeming a share link. Exactly one of Reader
+// and RedirectURL is set.
+type SharedAudio struct {
+	Reader      io.ReadCloser
+	RedirectURL string
+}
+
+type ShareAudioUseCase struct {
+	repo            repository.AudioRepository
+	shareLinkRepo   repository.ShareLinkRepository
+	storage         storage.Storage
+	downloadUseCase *DownloadAudioUseCase
+	settings        ShareLinkSettings
+}
+
+func NewShareAudioUseCase(
+	repo repository.AudioRepository,
+	shareLinkRepo repository.ShareLinkRepository,
+	storage storage.Storage,
+	downloadUseCase *DownloadAudioUseCase,
+	settings ShareLinkSettings,
+) *ShareAudioUseCase {
+	return &ShareAudioUseCase{
+		repo:            repo,
+		shareLinkRepo:   shareLinkRepo,
+		storage:         storage,
+		downloadUseCase: downloadUseCase,
+		settings:        settings,
+	}
+}
+
+// CreateLink mints a new share link for the given audio. The returned link
+// must be signed with Sign before it is handed out.
+func (uc *ShareAudioUseCase) CreateLink(ctx context.Context, audioID uint, req ShareLinkRequest) (*entity.ShareLink, error) {
+	if !supportedFormats[req.Format] {
+		return nil, fmt.Errorf("unsupported format %q: %w", req.Format, ErrInvalidArgument)
+	}
+
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = uc.settings.DefaultTTL
+	}
+	if ttl < 0 || (uc.settings.MaxTTL > 0 && ttl > uc.settings.MaxTTL) {
+		return nil, fmt.Errorf("ttl must be between 0 and %s: %w", uc.settings.MaxTTL, ErrInvalidArgument)
+	}
+
+	if _, err := uc.repo.GetByID(ctx, audioID); err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	link := &entity.ShareLink{
+		Nonce:     nonce,
+		AudioID:   audioID,
+		Format:    req.Format,
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+		SingleUse: req.SingleUse,
+		IP:        req.IP,
+	}
+
+	// Only single-use links need server-side state; everything else is
+	// verified from the signature alone.
+	if link.SingleUse {
+		link, err = uc.shareLinkRepo.Create(ctx, link)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store share link: %v", err)
+		}
+	}
+
+	return link, nil
+}
+
+// Sign returns the signature that authenticates the link's parameters.
+func (uc *ShareAudioUseCase) Sign(link *entity.ShareLink) string {
+	mac := hmac.New(sha256.New, uc.settings.Secret)
+	fmt.Fprintf(mac, "%d\n%s\n%d\n%s\n%t\n%s",
+		link.AudioID, link.Format, link.ExpiresAt.Unix(), link.Nonce, link.SingleUse, link.IP)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Open validates a presented share link and returns the audio it grants access to.
+func (uc *ShareAudioUseCase) Open(ctx context.Context, link *entity.ShareLink, signature, clientIP string) (*SharedAudio, error) {
+	if !hmac.Equal([]byte(uc.Sign(link)), []byte(signature)) {
+		return nil, fmt.Errorf("invalid signature: %w", ErrForbidden)
+	}
+
+	if time.Now().After(link.ExpiresAt) {
+		return nil, fmt.Errorf("link expired at %s: %w", link.ExpiresAt.Format(time.RFC3339), ErrGone)
+	}
+
+	if link.IP != "" && link.IP != clientIP {
+		return nil, fmt.Errorf("link is bound to another address: %w", ErrForbidden)
+	}
+
+	audio, err := uc.repo.GetByID(ctx, link.AudioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+
+	shared, err := uc.deliver(ctx, link, audio)
+	if err != nil {
+		return nil, err
+	}
+
+	// A single-use link is redeemed only once there is something to deliver,
+	// so a failed download does not burn it.
+	if link.SingleUse {
+		ok, err := uc.shareLinkRepo.MarkUsed(ctx, link.Nonce)
+		if err == nil && !ok {
+			err = fmt.Errorf("link already used: %w", ErrGone)
+		} else if err != nil {
+			err = fmt.Errorf("failed to redeem share link: %v", err)
+		}
+		if err != nil {
+			if shared.Reader != nil {
+				shared.Reader.Close()
+			}
+			return nil, err
+		}
+	}
+	return shared, nil
+}
+
+// deliver returns a presigned storage URL for the audio when the download
+// would be the stored file as it is, and a stream of it in the link's format
+// otherwise, such as when the owner's processing profile or trimming
+// applies.
+func (uc *ShareAudioUseCase) deliver(ctx context.Context, link *entity.ShareLink, audio *entity.Audio) (*SharedAudio, error) {
+	if signer, ok := uc.storage.(storage.URLSigner); ok && uc.settings.PresignRedirect {
+		stored, err := uc.downloadUseCase.servesStored(ctx, audio, link.Format, DownloadOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if stored {
+			url, err := signer.SignedURL(ctx, audio.StoragePath, time.Until(link.ExpiresAt))
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign storage url: %v", err)
+			}
+			return &SharedAudio{RedirectURL: url}, nil
+		}
+	}
+
+	reader, err := uc.downloadUseCase.Download(ctx, audio.UserID, audio.PhraseID, link.Format, DownloadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &SharedAudio{Reader: reader}, nil
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
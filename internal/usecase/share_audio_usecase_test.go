@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// signingStorage is a storage mock that can also presign URLs.
+type signingStorage struct {
+	*storageMocks.MockStorage
+}
+
+func (s signingStorage) SignedURL(ctx context.Context, objectName string, ttl time.Duration) (string, error) {
+	return "https://bucket.example.com/" + objectName, nil
+}
+
+type shareMocks struct {
+	audioRepo  *repoMocks.MockAudioRepository
+	shareRepo  *repoMocks.MockShareLinkRepository
+	userRepo   *repoMocks.MockUserRepository
+	phraseRepo *repoMocks.MockPhraseRepository
+	converter  *converterMocks.MockAudioConverter
+}
+
+func newShareTestUseCase(t *testing.T, store storage.Storage, presign bool) (*ShareAudioUseCase, shareMocks) {
+	m := shareMocks{
+		audioRepo:  repoMocks.NewMockAudioRepository(t),
+		shareRepo:  repoMocks.NewMockShareLinkRepository(t),
+		userRepo:   repoMocks.NewMockUserRepository(t),
+		phraseRepo: repoMocks.NewMockPhraseRepository(t),
+		converter:  converterMocks.NewMockAudioConverter(t),
+	}
+	profiles := ProcessingProfiles{"clean": {{Name: "highpass", Params: map[string]string{"f": "80"}}}}
+	download := NewDownloadAudioUseCase(m.audioRepo, store, m.converter, m.userRepo, m.phraseRepo, profiles, false)
+	uc := NewShareAudioUseCase(m.audioRepo, m.shareRepo, store, download, ShareLinkSettings{
+		Secret:          []byte("secret"),
+		DefaultTTL:      time.Hour,
+		MaxTTL:          24 * time.Hour,
+		PresignRedirect: presign,
+	})
+	return uc, m
+}
+
+func TestShareAudioUseCase_CreateLink(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         ShareLinkRequest
+		setupMocks  func(shareMocks)
+		expectedErr error
+		check       func(*testing.T, *entity.ShareLink)
+	}{
+		{
+			name: "default ttl",
+			req:  ShareLinkRequest{Format: "mp3"},
+			setupMocks: func(m shareMocks) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1}, nil)
+			},
+			check: func(t *testing.T, link *entity.ShareLink) {
+				assert.Equal(t, uint(1), link.AudioID)
+				assert.Equal(t, "mp3", link.Format)
+				assert.NotEmpty(t, link.Nonce)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), link.ExpiresAt, 2*time.Second)
+			},
+		},
+		{
+			name: "single use link is persisted",
+			req:  ShareLinkRequest{Format: "wav", SingleUse: true, IP: "10.0.0.1"},
+			setupMocks: func(m shareMocks) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1}, nil)
+				m.shareRepo.On("Create", mock.Anything, mock.MatchedBy(func(l *entity.ShareLink) bool {
+					return l.SingleUse && l.IP == "10.0.0.1"
+				})).Return(func(_ context.Context, l *entity.ShareLink) (*entity.ShareLink, error) {
+					created := *l
+					created.ID = 7
+					return &created, nil
+				})
+			},
+			check: func(t *testing.T, link *entity.ShareLink) {
+				assert.Equal(t, uint(7), link.ID)
+				assert.True(t, link.SingleUse)
+			},
+		},
+		{
+			name:        "unsupported format",
+			req:         ShareLinkRequest{Format: "exe"},
+			setupMocks:  func(m shareMocks) {},
+			expectedErr: ErrInvalidArgument,
+		},
+		{
+			name:        "ttl above maximum",
+			req:         ShareLinkRequest{Format: "mp3", TTL: 48 * time.Hour},
+			setupMocks:  func(m shareMocks) {},
+			expectedErr: ErrInvalidArgument,
+		},
+		{
+			name: "audio not found",
+			req:  ShareLinkRequest{Format: "mp3"},
+			setupMocks: func(m shareMocks) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, repository.ErrNotFound)
+			},
+			expectedErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newShareTestUseCase(t, storageMocks.NewMockStorage(t), false)
+			tt.setupMocks(m)
+
+			link, err := uc.CreateLink(context.Background(), 1, tt.req)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, link)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, link)
+		})
+	}
+}
+
+func TestShareAudioUseCase_Open(t *testing.T) {
+	audio := &entity.Audio{
+		ID:            1,
+		UserID:        2,
+		PhraseID:      3,
+		CurrentFormat: "wav",
+		StoragePath:   "audio/converted/1.wav",
+		Status:        entity.AudioStatusCompleted,
+	}
+
+	validLink := func() *entity.ShareLink {
+		return &entity.ShareLink{AudioID: 1, Format: "wav", Nonce: "n", ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Second)}
+	}
+
+	tests := []struct {
+		name        string
+		link        func() *entity.ShareLink
+		tamper      func(*entity.ShareLink)
+		clientIP    string
+		presign     bool
+		setupMocks  func(shareMocks, *storageMocks.MockStorage)
+		expectedErr error
+		wantErr     bool // Any error, when expectedErr is not specific
+		check       func(*testing.T, *SharedAudio)
+	}{
+		{
+			name: "streams through download use case",
+			link: validLink,
+			setupMocks: func(m shareMocks, st *storageMocks.MockStorage) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(audio, nil)
+				m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				m.phraseRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Phrase{ID: 3, UserID: 2}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(2), uint(3)).Return(audio, nil)
+				st.On("Download", mock.Anything, audio.StoragePath).Return(io.NopCloser(strings.NewReader("data")), nil)
+			},
+			check: func(t *testing.T, shared *SharedAudio) {
+				require.NotNil(t, shared.Reader)
+				content, _ := io.ReadAll(shared.Reader)
+				assert.Equal(t, "data", string(content))
+			},
+		},
+		{
+			name:    "redirects to presigned url when formats match",
+			link:    validLink,
+			presign: true,
+			setupMocks: func(m shareMocks, st *storageMocks.MockStorage) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(audio, nil)
+				m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+			},
+			check: func(t *testing.T, shared *SharedAudio) {
+				assert.Nil(t, shared.Reader)
+				assert.Equal(t, "https://bucket.example.com/audio/converted/1.wav", shared.RedirectURL)
+			},
+		},
+		{
+			name:    "streams when the owner's processing profile applies",
+			link:    validLink,
+			presign: true,
+			setupMocks: func(m shareMocks, st *storageMocks.MockStorage) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(audio, nil)
+				m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2, ProcessingProfile: "clean"}, nil)
+				m.phraseRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Phrase{ID: 3, UserID: 2}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(2), uint(3)).Return(audio, nil)
+				st.On("Download", mock.Anything, audio.StoragePath).Return(io.NopCloser(strings.NewReader("data")), nil)
+				m.converter.On("ConvertFromReader", mock.Anything, mock.Anything, "wav", "wav", mock.Anything).
+					Return(io.NopCloser(strings.NewReader("filtered")), nil)
+			},
+			check: func(t *testing.T, shared *SharedAudio) {
+				assert.Empty(t, shared.RedirectURL)
+				require.NotNil(t, shared.Reader)
+				content, _ := io.ReadAll(shared.Reader)
+				assert.Equal(t, "filtered", string(content))
+			},
+		},
+		{
+			name:        "tampered format",
+			link:        validLink,
+			tamper:      func(l *entity.ShareLink) { l.Format = "mp3" },
+			setupMocks:  func(shareMocks, *storageMocks.MockStorage) {},
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "expired link",
+			link: func() *entity.ShareLink {
+				l := validLink()
+				l.ExpiresAt = time.Now().Add(-time.Minute)
+				return l
+			},
+			setupMocks:  func(shareMocks, *storageMocks.MockStorage) {},
+			expectedErr: ErrGone,
+		},
+		{
+			name: "ip mismatch",
+			link: func() *entity.ShareLink {
+				l := validLink()
+				l.IP = "10.0.0.1"
+				return l
+			},
+			clientIP:    "10.0.0.2",
+			setupMocks:  func(shareMocks, *storageMocks.MockStorage) {},
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "single use link already redeemed",
+			link: func() *entity.ShareLink {
+				l := validLink()
+				l.SingleUse = true
+				return l
+			},
+			setupMocks: func(m shareMocks, st *storageMocks.MockStorage) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(audio, nil)
+				m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				m.phraseRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Phrase{ID: 3, UserID: 2}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(2), uint(3)).Return(audio, nil)
+				st.On("Download", mock.Anything, audio.StoragePath).Return(io.NopCloser(strings.NewReader("data")), nil)
+				m.shareRepo.On("MarkUsed", mock.Anything, "n").Return(false, nil)
+			},
+			expectedErr: ErrGone,
+		},
+		{
+			name: "single use link redeemed once delivered",
+			link: func() *entity.ShareLink {
+				l := validLink()
+				l.SingleUse = true
+				return l
+			},
+			presign: true,
+			setupMocks: func(m shareMocks, st *storageMocks.MockStorage) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(audio, nil)
+				m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				m.shareRepo.On("MarkUsed", mock.Anything, "n").Return(true, nil)
+			},
+			check: func(t *testing.T, shared *SharedAudio) {
+				assert.NotEmpty(t, shared.RedirectURL)
+			},
+		},
+		{
+			// MarkUsed is not expected: the link stays redeemable.
+			name: "single use link kept when the download fails",
+			link: func() *entity.ShareLink {
+				l := validLink()
+				l.SingleUse = true
+				return l
+			},
+			setupMocks: func(m shareMocks, st *storageMocks.MockStorage) {
+				m.audioRepo.On("GetByID", mock.Anything, uint(1)).Return(audio, nil)
+				m.userRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.User{ID: 2}, nil)
+				m.phraseRepo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Phrase{ID: 3, UserID: 2}, nil)
+				m.audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(2), uint(3)).Return(audio, nil)
+				st.On("Download", mock.Anything, audio.StoragePath).Return(nil, errors.New("storage unavailable"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := storageMocks.NewMockStorage(t)
+			var store storage.Storage = mockStorage
+			if tt.presign {
+				store = signingStorage{mockStorage}
+			}
+			uc, m := newShareTestUseCase(t, store, tt.presign)
+			tt.setupMocks(m, mockStorage)
+
+			link := tt.link()
+			signature := uc.Sign(link)
+			if tt.tamper != nil {
+				tt.tamper(link)
+			}
+
+			shared, err := uc.Open(context.Background(), link, signature, tt.clientIP)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr), "expected %v, got %v", tt.expectedErr, err)
+				return
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, shared)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// SharePhraseUseCase lets users other than the owner of a phrase record and
+// download it, and lets users claim phrases that have no owner.
+type SharePhraseUseCase struct {
+	phraseRepository repository.PhraseRepository
+	userRepository   repository.UserRepository
+}
+
+func NewSharePhraseUseCase(phraseRepository repository.PhraseRepository, userRepository repository.UserRepository) *SharePhraseUseCase {
+	return &SharePhraseUseCase{
+		phraseRepository: phraseRepository,
+		userRepository:   userRepository,
+	}
+}
+
+// Share lets the owner of a phrase give userID access to it. Sharing a
+// phrase twice is a no-op.
+func (uc *SharePhraseUseCase) Share(ctx context.Context, ownerID, phraseID, userID uint) error {
+	phrase, err := ownedPhrase(ctx, uc.phraseRepository, phraseID, ownerID)
+	if err != nil {
+		return err
+	}
+	if phrase.UserID == userID {
+		return fmt.Errorf("phrase %d is owned by user %d: %w", phraseID, userID, ErrInvalidArgument)
+	}
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return wrapRepoError(err, "failed to get user")
+	}
+	if err := uc.phraseRepository.Share(ctx, phraseID, userID); err != nil {
+		return fmt.Errorf("failed to share phrase: %v", err)
+	}
+	return nil
+}
+
+// Claim makes userID the owner of a phrase created before owners were
+// stored and never recorded, so it can be recorded, shared and edited again.
+// Claiming a phrase the user already owns is a no-op.
+func (uc *SharePhraseUseCase) Claim(ctx context.Context, userID, phraseID uint) error {
+	if _, err := uc.userRepository.GetByID(ctx, userID); err != nil {
+		return wrapRepoError(err, "failed to get user")
+	}
+	phrase, err := uc.phraseRepository.GetByID(ctx, phraseID)
+	if err != nil {
+		return wrapRepoError(err, "failed to get phrase")
+	}
+	if phrase.UserID == userID {
+		return nil
+	}
+	if phrase.UserID != 0 {
+		return fmt.Errorf("phrase %d is owned by user %d: %w", phraseID, phrase.UserID, ErrConflict)
+	}
+	if err := uc.phraseRepository.Claim(ctx, phraseID, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("phrase %d was claimed by another user: %w", phraseID, ErrConflict)
+		}
+		return fmt.Errorf("failed to claim phrase: %v", err)
+	}
+	return nil
+}
+
+// Unshare lets the owner of a phrase revoke the access of userID to it.
+// Recordings the user already made are kept, but the user can no longer
+// download them.
+func (uc *SharePhraseUseCase) Unshare(ctx context.Context, ownerID, phraseID, userID uint) error {
+	if _, err := ownedPhrase(ctx, uc.phraseRepository, phraseID, ownerID); err != nil {
+		return err
+	}
+	if err := uc.phraseRepository.Unshare(ctx, phraseID, userID); err != nil {
+		return wrapRepoError(err, "failed to unshare phrase")
+	}
+	return nil
+}
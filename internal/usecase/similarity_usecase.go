@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/ardfard/sb-test/internal/domain/converter"
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/ardfard/sb-test/pkg/similarity"
+	"github.com/ardfard/sb-test/pkg/util"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// Orders accepted by SimilarityUseCase.List. Takes without a score come last
+// in both.
+const (
+	SortSimilarity     = "similarity"  // Least similar first
+	SortSimilarityDesc = "-similarity" // Most similar first
+)
+
+// SimilarityUseCase manages the reference recordings of phrases and scores
+// takes against them. It runs as a post-processor of ConvertAudioUseCase.
+// When the reference of a phrase changes, the phrase is queued and Rescore
+// scores its takes again, run by a worker consuming the queue.
+type SimilarityUseCase struct {
+	repo         repository.AudioRepository
+	phraseRepo   repository.PhraseRepository
+	storage      storage.Storage
+	converter    converter.AudioConverter
+	rescoreQueue queue.TaskQueue
+	policy       UploadPolicy
+}
+
+func NewSimilarityUseCase(
+	repo repository.AudioRepository,
+	phraseRepo repository.PhraseRepository,
+	storage storage.Storage,
+	converter converter.AudioConverter,
+	rescoreQueue queue.TaskQueue,
+	policy UploadPolicy,
+) *SimilarityUseCase {
+	return &SimilarityUseCase{
+		repo:         repo,
+		phraseRepo:   phraseRepo,
+		storage:      storage,
+		converter:    converter,
+		rescoreQueue: rescoreQueue,
+		policy:       policy,
+	}
+}
+
+func referencePath(phraseID uint) string {
+	return fmt.Sprintf("%s/references/%d.%s", basePath, phraseID, targetFormat)
+}
+
+// Policy returns the restrictions applied to reference uploads.
+func (uc *SimilarityUseCase) Policy() UploadPolicy {
+	return uc.policy
+}
+
+// SetReference stores content as the reference recording of a phrase,
+// replacing any previous one, and queues the phrase to rescore its completed
+// takes.
+func (uc *SimilarityUseCase) SetReference(ctx context.Context, phraseID uint, content io.Reader) (*entity.Phrase, error) {
+	phrase, err := uc.phraseRepo.GetByID(ctx, phraseID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get phrase")
+	}
+
+	reference, err := uc.decode(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	path := referencePath(phraseID)
+	if err := uc.storage.Upload(ctx, path, bytes.NewReader(reference)); err != nil {
+		return nil, fmt.Errorf("failed to store reference: %v", err)
+	}
+	phrase.ReferencePath = path
+	if err := uc.phraseRepo.Update(ctx, phrase); err != nil {
+		return nil, fmt.Errorf("failed to update phrase: %v", err)
+	}
+
+	if err := uc.rescoreQueue.Enqueue(ctx, phraseID); err != nil {
+		return nil, fmt.Errorf("failed to enqueue rescoring: %v", err)
+	}
+	return phrase, nil
+}
+
+// DeleteReference removes the reference recording of a phrase and queues
+// the phrase to clear the scores of its takes.
+func (uc *SimilarityUseCase) DeleteReference(ctx context.Context, phraseID uint) error {
+	phrase, err := uc.phraseRepo.GetByID(ctx, phraseID)
+	if err != nil {
+		return wrapRepoError(err, "failed to get phrase")
+	}
+	if phrase.ReferencePath == "" {
+		return fmt.Errorf("phrase %d has no reference: %w", phraseID, ErrNotFound)
+	}
+
+	if err := uc.storage.Delete(ctx, phrase.ReferencePath); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		return fmt.Errorf("failed to delete reference: %v", err)
+	}
+	phrase.ReferencePath = ""
+	if err := uc.phraseRepo.Update(ctx, phrase); err != nil {
+		return fmt.Errorf("failed to update phrase: %v", err)
+	}
+	if err := uc.rescoreQueue.Enqueue(ctx, phraseID); err != nil {
+		return fmt.Errorf("failed to enqueue rescoring: %v", err)
+	}
+	return nil
+}
+
+// Process scores the converted WAV at path against the reference recording
+// of its phrase and sets audio.SimilarityScore. The caller persists audio.
+func (uc *SimilarityUseCase) Process(ctx context.Context, audio *entity.Audio, path string) error {
+	phrase, err := uc.phraseRepo.GetByID(ctx, audio.PhraseID)
+	if err != nil {
+		return fmt.Errorf("failed to get phrase: %v", err)
+	}
+	audio.SimilarityScore = nil
+	if phrase.ReferencePath == "" {
+		return nil
+	}
+
+	reader, err := uc.storage.Download(ctx, phrase.ReferencePath)
+	if err != nil {
+		return fmt.Errorf("failed to download reference: %v", err)
+	}
+	defer reader.Close()
+	reference, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read reference: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audio: %v", err)
+	}
+	defer file.Close()
+
+	score, err := uc.score(reference, file)
+	if err != nil {
+		return err
+	}
+	audio.SimilarityScore = &score
+	return nil
+}
+
+// List returns the takes of a phrase in recording order, or ordered by
+// similarity score when sortBy is SortSimilarity or SortSimilarityDesc.
+func (uc *SimilarityUseCase) List(ctx context.Context, phraseID uint, sortBy string) ([]*entity.Audio, error) {
+	if sortBy != "" && sortBy != SortSimilarity && sortBy != SortSimilarityDesc {
+		return nil, fmt.Errorf("unknown sort order %q: %w", sortBy, ErrInvalidArgument)
+	}
+	if _, err := uc.phraseRepo.GetByID(ctx, phraseID); err != nil {
+		return nil, wrapRepoError(err, "failed to get phrase")
+	}
+	audios, err := uc.repo.ListByPhraseID(ctx, phraseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audio: %v", err)
+	}
+	if sortBy == "" {
+		return audios, nil
+	}
+
+	sort.SliceStable(audios, func(i, j int) bool {
+		a, b := audios[i].SimilarityScore, audios[j].SimilarityScore
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		if sortBy == SortSimilarityDesc {
+			return *a > *b
+		}
+		return *a < *b
+	})
+	return audios, nil
+}
+
+// decode checks content against the upload policy and converts it to WAV.
+func (uc *SimilarityUseCase) decode(ctx context.Context, content io.Reader) ([]byte, error) {
+	format, content, err := uc.policy.sniff(content)
+	if err != nil {
+		return nil, err
+	}
+	spooled, err := util.WriteTempFile(uc.policy.limit(content), format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool upload: %v", err)
+	}
+	defer os.Remove(spooled)
+
+	info, err := os.Stat(spooled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat spooled upload: %v", err)
+	}
+	if err := uc.policy.checkSize(info.Size()); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(spooled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spooled upload: %v", err)
+	}
+	defer file.Close()
+
+	output, err := uc.converter.ConvertFromReader(ctx, file, format, targetFormat)
+	if err != nil {
+		return nil, reject(ErrUnprocessable, RejectUnreadableAudio, "%v", err)
+	}
+	defer output.Close()
+	converted, err := io.ReadAll(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read converted reference: %v", err)
+	}
+	if _, err := wav.NewReader(bytes.NewReader(converted)); err != nil {
+		return nil, reject(ErrUnprocessable, RejectUnreadableAudio, "%v", err)
+	}
+	return converted, nil
+}
+
+// Rescore scores the completed takes of a queued phrase against its current
+// reference recording, or clears their scores when it has none. Only the
+// scores are written, so takes converted or reviewed meanwhile are kept as
+// they are. Failures to score a take are logged, as takes are scored again
+// when they are next converted.
+func (uc *SimilarityUseCase) Rescore(ctx context.Context, phraseID uint) error {
+	phrase, err := uc.phraseRepo.GetByID(ctx, phraseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		// The phrase was deleted along with its takes
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get phrase: %v", err)
+	}
+
+	var reference []byte
+	if phrase.ReferencePath != "" {
+		reader, err := uc.storage.Download(ctx, phrase.ReferencePath)
+		if err != nil {
+			return fmt.Errorf("failed to download reference: %v", err)
+		}
+		defer reader.Close()
+		if reference, err = io.ReadAll(reader); err != nil {
+			return fmt.Errorf("failed to read reference: %v", err)
+		}
+	}
+
+	audios, err := uc.repo.ListByPhraseID(ctx, phraseID)
+	if err != nil {
+		return fmt.Errorf("failed to list audio: %v", err)
+	}
+	for _, audio := range audios {
+		if audio.Status != entity.AudioStatusCompleted {
+			continue
+		}
+		if err := uc.rescoreAudio(ctx, audio, reference); err != nil {
+			logger.Errorf("Failed to rescore audio %d: %v", audio.ID, err)
+		}
+	}
+	return nil
+}
+
+func (uc *SimilarityUseCase) rescoreAudio(ctx context.Context, audio *entity.Audio, reference []byte) error {
+	if reference == nil {
+		if audio.SimilarityScore == nil {
+			return nil
+		}
+		return uc.setScore(ctx, audio, nil)
+	}
+
+	reader, err := uc.storage.Download(ctx, audio.StoragePath)
+	if err != nil {
+		return fmt.Errorf("failed to download audio: %v", err)
+	}
+	defer reader.Close()
+
+	score, err := uc.score(reference, reader)
+	if err != nil {
+		return err
+	}
+	return uc.setScore(ctx, audio, &score)
+}
+
+// setScore stores the score of a take. A take deleted meanwhile is skipped.
+func (uc *SimilarityUseCase) setScore(ctx context.Context, audio *entity.Audio, score *float64) error {
+	err := uc.repo.SetSimilarityScore(ctx, audio.ID, score)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// score compares a take with the reference. A take with nothing audible
+// scores zero.
+func (uc *SimilarityUseCase) score(reference []byte, take io.Reader) (float64, error) {
+	referenceReader, err := wav.NewReader(bytes.NewReader(reference))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read reference: %v", err)
+	}
+	takeReader, err := wav.NewReader(take)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read audio: %v", err)
+	}
+	score, err := similarity.Compare(referenceReader, takeReader)
+	if errors.Is(err, similarity.ErrNoSpeech) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare with reference: %v", err)
+	}
+	return score, nil
+}
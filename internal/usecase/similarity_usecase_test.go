@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeSpeech returns half a second of a voice-like tone at f0 Hz as WAV.
+func encodeSpeech(t *testing.T, f0 float64) []byte {
+	samples := make([]float64, 8000)
+	for i := range samples {
+		phase := 2 * math.Pi * f0 * float64(i) / 16000
+		samples[i] = 0.4*math.Sin(phase) + 0.2*math.Sin(2*phase) + 0.1*math.Sin(3*phase)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, 16000, 1, samples))
+	return buf.Bytes()
+}
+
+func TestSimilarityUseCase_Process(t *testing.T) {
+	take := encodeSpeech(t, 200)
+	path := filepath.Join(t.TempDir(), "converted.wav")
+	require.NoError(t, os.WriteFile(path, take, 0o644))
+
+	t.Run("phrase without reference", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+
+		score := 0.5
+		audio := &entity.Audio{ID: 1, PhraseID: 2, SimilarityScore: &score}
+		uc := NewSimilarityUseCase(nil, phraseRepo, nil, nil, nil, UploadPolicy{})
+		require.NoError(t, uc.Process(context.Background(), audio, path))
+		assert.Nil(t, audio.SimilarityScore)
+	})
+
+	t.Run("identical to reference", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, ReferencePath: "audio/references/2.wav"}, nil)
+		s := storageMocks.NewMockStorage(t)
+		s.On("Download", mock.Anything, "audio/references/2.wav").Return(io.NopCloser(bytes.NewReader(take)), nil)
+
+		audio := &entity.Audio{ID: 1, PhraseID: 2}
+		uc := NewSimilarityUseCase(nil, phraseRepo, s, nil, nil, UploadPolicy{})
+		require.NoError(t, uc.Process(context.Background(), audio, path))
+		require.NotNil(t, audio.SimilarityScore)
+		assert.InDelta(t, 1, *audio.SimilarityScore, 1e-9)
+	})
+}
+
+func TestSimilarityUseCase_SetReference(t *testing.T) {
+	reference := encodeSpeech(t, 200)
+
+	t.Run("stores reference and queues rescoring", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		s := storageMocks.NewMockStorage(t)
+		c := converterMocks.NewMockAudioConverter(t)
+		q := queueMocks.NewMockTaskQueue(t)
+
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+		c.On("ConvertFromReader", mock.Anything, mock.Anything, "wav", "wav").Return(io.NopCloser(bytes.NewReader(reference)), nil)
+		s.On("Upload", mock.Anything, "audio/references/2.wav", mock.Anything).Return(nil)
+		phraseRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *entity.Phrase) bool {
+			return p.ReferencePath == "audio/references/2.wav"
+		})).Return(nil)
+		q.On("Enqueue", mock.Anything, uint(2)).Return(nil)
+
+		uc := NewSimilarityUseCase(nil, phraseRepo, s, c, q, UploadPolicy{})
+		phrase, err := uc.SetReference(context.Background(), 2, bytes.NewReader(reference))
+		require.NoError(t, err)
+		assert.Equal(t, "audio/references/2.wav", phrase.ReferencePath)
+	})
+
+	t.Run("unrecognized file", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+
+		uc := NewSimilarityUseCase(nil, phraseRepo, nil, nil, nil, UploadPolicy{})
+		_, err := uc.SetReference(context.Background(), 2, bytes.NewReader([]byte("not audio")))
+		assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+	})
+
+	t.Run("phrase not found", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, repository.ErrNotFound)
+
+		uc := NewSimilarityUseCase(nil, phraseRepo, nil, nil, nil, UploadPolicy{})
+		_, err := uc.SetReference(context.Background(), 2, bytes.NewReader(reference))
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestSimilarityUseCase_DeleteReference(t *testing.T) {
+	t.Run("clears reference and queues rescoring", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		s := storageMocks.NewMockStorage(t)
+		q := queueMocks.NewMockTaskQueue(t)
+
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, ReferencePath: "audio/references/2.wav"}, nil)
+		s.On("Delete", mock.Anything, "audio/references/2.wav").Return(nil)
+		phraseRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *entity.Phrase) bool { return p.ReferencePath == "" })).Return(nil)
+		q.On("Enqueue", mock.Anything, uint(2)).Return(nil)
+
+		uc := NewSimilarityUseCase(nil, phraseRepo, s, nil, q, UploadPolicy{})
+		require.NoError(t, uc.DeleteReference(context.Background(), 2))
+	})
+
+	t.Run("no reference", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+
+		uc := NewSimilarityUseCase(nil, phraseRepo, nil, nil, nil, UploadPolicy{})
+		assert.ErrorIs(t, uc.DeleteReference(context.Background(), 2), ErrNotFound)
+	})
+}
+
+func TestSimilarityUseCase_Rescore(t *testing.T) {
+	t.Run("scores completed takes", func(t *testing.T) {
+		repo := repoMocks.NewMockAudioRepository(t)
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		s := storageMocks.NewMockStorage(t)
+
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, ReferencePath: "audio/references/2.wav"}, nil)
+		s.On("Download", mock.Anything, "audio/references/2.wav").Return(io.NopCloser(bytes.NewReader(encodeSpeech(t, 200))), nil)
+		repo.On("ListByPhraseID", mock.Anything, uint(2)).Return([]*entity.Audio{
+			{ID: 1, PhraseID: 2, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/1.wav"},
+			{ID: 2, PhraseID: 2, Status: entity.AudioStatusPending},
+			{ID: 3, PhraseID: 2, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/3.wav"},
+		}, nil)
+		s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(encodeSpeech(t, 200))), nil)
+		s.On("Download", mock.Anything, "audio/converted/3.wav").Return(io.NopCloser(bytes.NewReader(encodeSpeech(t, 200))), nil)
+		repo.On("SetSimilarityScore", mock.Anything, uint(1), mock.MatchedBy(func(score *float64) bool {
+			return score != nil && math.Abs(*score-1) < 1e-9
+		})).Return(nil)
+		// Take 3 was deleted meanwhile
+		repo.On("SetSimilarityScore", mock.Anything, uint(3), mock.Anything).Return(repository.ErrNotFound)
+
+		uc := NewSimilarityUseCase(repo, phraseRepo, s, nil, nil, UploadPolicy{})
+		require.NoError(t, uc.Rescore(context.Background(), 2))
+	})
+
+	t.Run("clears scores without a reference", func(t *testing.T) {
+		repo := repoMocks.NewMockAudioRepository(t)
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+
+		score := 0.8
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2}, nil)
+		repo.On("ListByPhraseID", mock.Anything, uint(2)).Return([]*entity.Audio{
+			{ID: 1, Status: entity.AudioStatusCompleted, SimilarityScore: &score},
+			{ID: 2, Status: entity.AudioStatusCompleted},
+		}, nil)
+		repo.On("SetSimilarityScore", mock.Anything, uint(1), (*float64)(nil)).Return(nil).Once()
+
+		uc := NewSimilarityUseCase(repo, phraseRepo, nil, nil, nil, UploadPolicy{})
+		require.NoError(t, uc.Rescore(context.Background(), 2))
+	})
+
+	t.Run("deleted phrase", func(t *testing.T) {
+		phraseRepo := repoMocks.NewMockPhraseRepository(t)
+		phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, repository.ErrNotFound)
+
+		uc := NewSimilarityUseCase(nil, phraseRepo, nil, nil, nil, UploadPolicy{})
+		require.NoError(t, uc.Rescore(context.Background(), 2))
+	})
+}
+
+func TestSimilarityUseCase_List(t *testing.T) {
+	low, high := 0.2, 0.9
+	takes := func() []*entity.Audio {
+		return []*entity.Audio{{ID: 1}, {ID: 2, SimilarityScore: &high}, {ID: 3, SimilarityScore: &low}}
+	}
+
+	tests := []struct {
+		name          string
+		sortBy        string
+		expectedIDs   []uint
+		expectedError error
+	}{
+		{name: "recording order", expectedIDs: []uint{1, 2, 3}},
+		{name: "least similar first", sortBy: SortSimilarity, expectedIDs: []uint{3, 2, 1}},
+		{name: "most similar first", sortBy: SortSimilarityDesc, expectedIDs: []uint{2, 3, 1}},
+		{name: "unknown order", sortBy: "duration", expectedError: ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+			if tt.expectedError == nil {
+				phraseRepo.On("GetByID", mock.Anything, uint(2)).Return(&entity.Phrase{ID: 2, UserID: 1}, nil)
+				repo.On("ListByPhraseID", mock.Anything, uint(2)).Return(takes(), nil)
+			}
+
+			audios, err := NewSimilarityUseCase(repo, phraseRepo, nil, nil, nil, UploadPolicy{}).List(context.Background(), 2, tt.sortBy)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var ids []uint
+			for _, a := range audios {
+				ids = append(ids, a.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
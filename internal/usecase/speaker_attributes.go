@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxSpeakerAttributeLength bounds free-text attribute values, in characters,
+// unless the attribute sets its own limit.
+const MaxSpeakerAttributeLength = 100
+
+var speakerAttributeName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// SpeakerAttribute describes an optional attribute of speakers.
+type SpeakerAttribute struct {
+	Values    []string // Allowed values; any text when empty
+	MaxLength int      // Of free-text values in characters, MaxSpeakerAttributeLength when 0
+}
+
+// SpeakerSchema maps attribute names to the attributes speakers may be
+// described with.
+type SpeakerSchema map[string]SpeakerAttribute
+
+// Validate checks the attribute names and their allowed values.
+func (s SpeakerSchema) Validate() error {
+	for name, attribute := range s {
+		if !speakerAttributeName.MatchString(name) {
+			return fmt.Errorf("attribute name %q must be lowercase letters, digits and underscores, starting with a letter", name)
+		}
+		if attribute.MaxLength < 0 {
+			return fmt.Errorf("attribute %q: max length must not be negative", name)
+		}
+		for i, value := range attribute.Values {
+			if strings.TrimSpace(value) != value || value == "" {
+				return fmt.Errorf("attribute %q: value %q must be non-empty without surrounding spaces", name, value)
+			}
+			if slices.Contains(attribute.Values[:i], value) {
+				return fmt.Errorf("attribute %q: duplicate value %q", name, value)
+			}
+		}
+	}
+	return nil
+}
+
+// Names returns the attribute names in order.
+func (s SpeakerSchema) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// normalize validates the attributes of a speaker and returns them trimmed,
+// with allowed values in their configured case. Empty values leave the
+// attribute unset.
+func (s SpeakerSchema) normalize(attributes map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(attributes))
+	for name, value := range attributes {
+		attribute, ok := s[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown speaker attribute %q: %w", name, ErrInvalidArgument)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if len(attribute.Values) > 0 {
+			i := slices.IndexFunc(attribute.Values, func(allowed string) bool { return strings.EqualFold(allowed, value) })
+			if i < 0 {
+				return nil, fmt.Errorf("speaker attribute %q must be one of %s: %w",
+					name, strings.Join(attribute.Values, ", "), ErrInvalidArgument)
+			}
+			normalized[name] = attribute.Values[i]
+			continue
+		}
+		maxLength := attribute.MaxLength
+		if maxLength == 0 {
+			maxLength = MaxSpeakerAttributeLength
+		}
+		if utf8.RuneCountInString(value) > maxLength {
+			return nil, fmt.Errorf("speaker attribute %q must be at most %d characters: %w", name, maxLength, ErrInvalidArgument)
+		}
+		if strings.ContainsFunc(value, unicode.IsControl) {
+			return nil, fmt.Errorf("speaker attribute %q must not contain control characters: %w", name, ErrInvalidArgument)
+		}
+		normalized[name] = value
+	}
+	return normalized, nil
+}
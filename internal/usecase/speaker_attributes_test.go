@@ -0,0 +1,46 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeakerSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  usecase.SpeakerSchema
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			schema: usecase.SpeakerSchema{
+				"age_range":       {Values: []string{"18-24", "25-34"}},
+				"native_language": {MaxLength: 50},
+			},
+		},
+		{name: "empty", schema: usecase.SpeakerSchema{}},
+		{name: "uppercase name", schema: usecase.SpeakerSchema{"Gender": {}}, wantErr: true},
+		{name: "quoted name", schema: usecase.SpeakerSchema{`a"b`: {}}, wantErr: true},
+		{name: "negative max length", schema: usecase.SpeakerSchema{"accent": {MaxLength: -1}}, wantErr: true},
+		{name: "blank value", schema: usecase.SpeakerSchema{"gender": {Values: []string{"female", " "}}}, wantErr: true},
+		{name: "duplicate value", schema: usecase.SpeakerSchema{"gender": {Values: []string{"female", "female"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSpeakerSchema_Names(t *testing.T) {
+	schema := usecase.SpeakerSchema{"gender": {}, "accent": {}, "age_range": {}}
+	assert.Equal(t, []string{"accent", "age_range", "gender"}, schema.Names())
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// SpeakerAttributeReport summarizes the speakers and their completed takes by
+// their values of one attribute.
+type SpeakerAttributeReport struct {
+	Attribute string
+	// Values lists the allowed values of the attribute in their configured
+	// order, including those no speaker has, then any value no longer
+	// allowed, then the empty value for speakers without the attribute.
+	Values []*entity.SpeakerAttributeStats
+}
+
+// SpeakerReportUseCase reports how the speakers and their recordings are
+// spread over the values of the speaker attributes, to help balance datasets.
+type SpeakerReportUseCase struct {
+	userRepository repository.UserRepository
+	speakerSchema  SpeakerSchema
+}
+
+func NewSpeakerReportUseCase(userRepository repository.UserRepository, speakerSchema SpeakerSchema) *SpeakerReportUseCase {
+	return &SpeakerReportUseCase{
+		userRepository: userRepository,
+		speakerSchema:  speakerSchema,
+	}
+}
+
+// Schema returns the attributes speakers may be described with.
+func (uc *SpeakerReportUseCase) Schema() SpeakerSchema {
+	return uc.speakerSchema
+}
+
+// Report returns the report of every attribute, ordered by name.
+func (uc *SpeakerReportUseCase) Report(ctx context.Context) ([]*SpeakerAttributeReport, error) {
+	reports := make([]*SpeakerAttributeReport, 0, len(uc.speakerSchema))
+	for _, name := range uc.speakerSchema.Names() {
+		report, err := uc.Attribute(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Attribute returns the report of one attribute.
+func (uc *SpeakerReportUseCase) Attribute(ctx context.Context, name string) (*SpeakerAttributeReport, error) {
+	attribute, ok := uc.speakerSchema[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown speaker attribute %q: %w", name, ErrNotFound)
+	}
+	stats, err := uc.userRepository.AttributeStats(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get speaker stats: %v", err)
+	}
+
+	values := make([]*entity.SpeakerAttributeStats, 0, len(attribute.Values)+len(stats))
+	for _, value := range attribute.Values {
+		values = append(values, &entity.SpeakerAttributeStats{Value: value})
+	}
+	var unspecified *entity.SpeakerAttributeStats
+	for _, s := range stats {
+		if s.Value == "" {
+			unspecified = s
+			continue
+		}
+		if i := slices.Index(attribute.Values, s.Value); i >= 0 {
+			values[i] = s
+			continue
+		}
+		values = append(values, s)
+	}
+	if unspecified != nil {
+		values = append(values, unspecified)
+	}
+	return &SpeakerAttributeReport{Attribute: name, Values: values}, nil
+}
@@ -0,0 +1,111 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var reportSchema = usecase.SpeakerSchema{
+	"gender": {Values: []string{"female", "male", "non_binary"}},
+	"accent": {},
+}
+
+func TestSpeakerReportUseCase_Attribute(t *testing.T) {
+	tests := []struct {
+		name           string
+		attribute      string
+		mockSetup      func(*repoMocks.MockUserRepository)
+		expectedValues []*entity.SpeakerAttributeStats
+		expectedError  error
+	}{
+		{
+			name:      "allowed values in configured order",
+			attribute: "gender",
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("AttributeStats", mock.Anything, "gender").Return([]*entity.SpeakerAttributeStats{
+					{Value: "", Speakers: 4, Recordings: 2, RecordedDuration: 10},
+					{Value: "female", Speakers: 2, Recordings: 5, RecordedDuration: 30},
+					{Value: "male", Speakers: 1, Recordings: 1, RecordedDuration: 3},
+					{Value: "other", Speakers: 1},
+				}, nil)
+			},
+			expectedValues: []*entity.SpeakerAttributeStats{
+				{Value: "female", Speakers: 2, Recordings: 5, RecordedDuration: 30},
+				{Value: "male", Speakers: 1, Recordings: 1, RecordedDuration: 3},
+				{Value: "non_binary"},
+				{Value: "other", Speakers: 1},
+				{Value: "", Speakers: 4, Recordings: 2, RecordedDuration: 10},
+			},
+		},
+		{
+			name:      "free text",
+			attribute: "accent",
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("AttributeStats", mock.Anything, "accent").Return([]*entity.SpeakerAttributeStats{
+					{Value: "Geordie", Speakers: 1},
+				}, nil)
+			},
+			expectedValues: []*entity.SpeakerAttributeStats{{Value: "Geordie", Speakers: 1}},
+		},
+		{
+			name:          "unknown attribute",
+			attribute:     "height",
+			expectedError: usecase.ErrNotFound,
+		},
+		{
+			name:      "repository error",
+			attribute: "accent",
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("AttributeStats", mock.Anything, "accent").Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("failed to get speaker stats: database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockUserRepository(t)
+			if tt.mockSetup != nil {
+				tt.mockSetup(repo)
+			}
+			uc := usecase.NewSpeakerReportUseCase(repo, reportSchema)
+
+			report, err := uc.Attribute(context.Background(), tt.attribute)
+			if tt.expectedError != nil {
+				if errors.Is(tt.expectedError, usecase.ErrNotFound) {
+					assert.ErrorIs(t, err, tt.expectedError)
+				} else {
+					assert.EqualError(t, err, tt.expectedError.Error())
+				}
+				assert.Nil(t, report)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.attribute, report.Attribute)
+			assert.Equal(t, tt.expectedValues, report.Values)
+		})
+	}
+}
+
+func TestSpeakerReportUseCase_Report(t *testing.T) {
+	repo := repoMocks.NewMockUserRepository(t)
+	repo.On("AttributeStats", mock.Anything, "accent").Return([]*entity.SpeakerAttributeStats{}, nil)
+	repo.On("AttributeStats", mock.Anything, "gender").Return([]*entity.SpeakerAttributeStats{}, nil)
+	uc := usecase.NewSpeakerReportUseCase(repo, reportSchema)
+
+	reports, err := uc.Report(context.Background())
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "accent", reports[0].Attribute)
+	assert.Empty(t, reports[0].Values)
+	assert.Equal(t, "gender", reports[1].Attribute)
+	assert.Len(t, reports[1].Values, 3)
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/ardfard/sb-test/pkg/spectrogram"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// SpectrogramUseCase renders spectrogram images of converted audio. Rendered
+// images are cached in storage per audio and options.
+type SpectrogramUseCase struct {
+	repo    repository.AudioRepository
+	storage storage.Storage
+	options spectrogram.Options
+}
+
+func NewSpectrogramUseCase(repo repository.AudioRepository, storage storage.Storage, options spectrogram.Options) *SpectrogramUseCase {
+	return &SpectrogramUseCase{
+		repo:    repo,
+		storage: storage,
+		options: options,
+	}
+}
+
+// Options returns the configured spectrogram options.
+func (uc *SpectrogramUseCase) Options() spectrogram.Options {
+	return uc.options
+}
+
+// spectrogramDir holds the spectrograms of an audio rendered with any options.
+func spectrogramDir(audioID uint) string {
+	return fmt.Sprintf("%s/spectrogram/%d/", basePath, audioID)
+}
+
+func spectrogramPath(audioID uint, options spectrogram.Options) string {
+	return spectrogramDir(audioID) + options.Key() + ".png"
+}
+
+// Get returns the spectrogram of a converted audio as a PNG image, rendering
+// it first if it is not cached yet.
+func (uc *SpectrogramUseCase) Get(ctx context.Context, audioID uint, options spectrogram.Options) (io.ReadCloser, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidArgument)
+	}
+
+	audio, err := uc.repo.GetByID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	if audio.Status != entity.AudioStatusCompleted {
+		return nil, fmt.Errorf("audio %d is %s: %w", audioID, audio.Status, ErrConflict)
+	}
+
+	path := spectrogramPath(audioID, options)
+	cached, err := uc.storage.Download(ctx, path)
+	if err == nil {
+		return cached, nil
+	}
+	if !errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, fmt.Errorf("failed to download spectrogram: %v", err)
+	}
+
+	source, err := uc.storage.Download(ctx, audio.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download audio: %v", err)
+	}
+	defer source.Close()
+
+	reader, err := wav.NewReader(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %v", err)
+	}
+	img, err := spectrogram.Render(reader, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render spectrogram: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode spectrogram: %v", err)
+	}
+
+	// The image can be rendered again, so failing to cache it is not fatal.
+	if err := uc.storage.Upload(ctx, path, bytes.NewReader(buf.Bytes())); err != nil {
+		logger.Errorf("Failed to cache spectrogram of audio %d: %v", audioID, err)
+	}
+	return io.NopCloser(&buf), nil
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/pkg/spectrogram"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpectrogramUseCase_Get(t *testing.T) {
+	options := spectrogram.Options{FFTSize: 64, Hop: 32, Scale: spectrogram.ScaleLinear, MinDB: -90, MaxDB: 0, ColorMap: "gray", Width: 10, Height: 8}
+	cachePath := "audio/spectrogram/1/fft64-hop32-linear-db-90_0-gray-10x8.png"
+	completed := &entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, CurrentFormat: "wav", StoragePath: "audio/converted/1.wav"}
+
+	var source bytes.Buffer
+	require.NoError(t, wav.Encode(&source, 8000, 1, make([]float64, 800)))
+
+	tests := []struct {
+		name          string
+		options       spectrogram.Options
+		setupMocks    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expectPNG     bool
+		expectedError error
+	}{
+		{
+			name:    "cached image",
+			options: options,
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, cachePath).Return(io.NopCloser(bytes.NewReader([]byte("cached"))), nil)
+			},
+		},
+		{
+			name:    "missing image is rendered and cached",
+			options: options,
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, cachePath).Return(nil, fmt.Errorf("open: %w", storage.ErrObjectNotFound))
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+				s.On("Upload", mock.Anything, cachePath, mock.Anything).Return(assert.AnError)
+			},
+			expectPNG: true,
+		},
+		{
+			name:          "invalid options",
+			options:       spectrogram.Options{},
+			setupMocks:    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:    "audio not converted yet",
+			options: options,
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedError: ErrConflict,
+		},
+		{
+			name:    "audio not found",
+			options: options,
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, repository.ErrNotFound)
+			},
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			reader, err := NewSpectrogramUseCase(repo, s, options).Get(context.Background(), 1, tt.options)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			defer reader.Close()
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			if !tt.expectPNG {
+				assert.Equal(t, "cached", string(content))
+				return
+			}
+			img, err := png.Decode(bytes.NewReader(content))
+			require.NoError(t, err)
+			assert.Equal(t, 10, img.Bounds().Dx())
+			assert.Equal(t, 8, img.Bounds().Dy())
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/silence"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// TrimUseCase detects leading and trailing silence in converted audio and
+// records the trim offsets on it. It runs as a post-processor of
+// ConvertAudioUseCase; the canonical file is left untouched and the trim is
+// applied when downloading.
+type TrimUseCase struct {
+	repo    repository.AudioRepository
+	storage storage.Storage
+	options silence.Options
+}
+
+func NewTrimUseCase(repo repository.AudioRepository, storage storage.Storage, options silence.Options) *TrimUseCase {
+	return &TrimUseCase{
+		repo:    repo,
+		storage: storage,
+		options: options,
+	}
+}
+
+// Options returns the configured silence detection options.
+func (uc *TrimUseCase) Options() silence.Options {
+	return uc.options
+}
+
+// Process records the trim offsets of the WAV at path on audio. The caller persists audio.
+func (uc *TrimUseCase) Process(ctx context.Context, audio *entity.Audio, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audio: %v", err)
+	}
+	defer file.Close()
+
+	return detectTrim(audio, file, uc.options)
+}
+
+// Retrim re-runs silence detection on a converted audio with different options.
+func (uc *TrimUseCase) Retrim(ctx context.Context, audioID uint, options silence.Options) (*entity.Audio, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidArgument)
+	}
+
+	audio, err := uc.repo.GetByID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	if audio.Status != entity.AudioStatusCompleted {
+		return nil, fmt.Errorf("audio %d is %s: %w", audioID, audio.Status, ErrConflict)
+	}
+
+	reader, err := uc.storage.Download(ctx, audio.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download audio: %v", err)
+	}
+	defer reader.Close()
+
+	if err := detectTrim(audio, reader, options); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Update(ctx, audio); err != nil {
+		return nil, fmt.Errorf("failed to update audio: %v", err)
+	}
+	return audio, nil
+}
+
+func detectTrim(audio *entity.Audio, r io.Reader, options silence.Options) error {
+	reader, err := wav.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read audio: %v", err)
+	}
+	keep, err := silence.Detect(reader, options)
+	if err != nil {
+		return fmt.Errorf("failed to detect silence: %v", err)
+	}
+	audio.ApplyTrim(keep.Start, keep.End)
+	return nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/pkg/silence"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// paddedTone is 1 kHz mono audio: 500ms of silence, 1s of tone, 500ms of silence.
+func paddedTone(t *testing.T) []byte {
+	samples := make([]float64, 2000)
+	for i := 500; i < 1500; i++ {
+		samples[i] = 0.5
+		if i%2 == 1 {
+			samples[i] = -0.5
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, 1000, 1, samples))
+	return buf.Bytes()
+}
+
+func TestTrimUseCase_Retrim(t *testing.T) {
+	source := paddedTone(t)
+	completed := func() *entity.Audio {
+		return &entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, CurrentFormat: "wav", StoragePath: "audio/converted/1.wav"}
+	}
+
+	tests := []struct {
+		name          string
+		options       silence.Options
+		setupMocks    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expectedStart float64
+		expectedEnd   float64
+		expectedError error
+	}{
+		{
+			name:    "records new offsets",
+			options: silence.Options{ThresholdDB: -40, MinDuration: 200 * time.Millisecond, Padding: 100 * time.Millisecond},
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed(), nil)
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source)), nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(a *entity.Audio) bool {
+					return a.TrimStart != nil && *a.TrimStart == 0.4
+				})).Return(nil)
+			},
+			expectedStart: 0.4,
+			expectedEnd:   1.6,
+		},
+		{
+			name:    "silence shorter than minimum is kept",
+			options: silence.Options{ThresholdDB: -40, MinDuration: time.Second},
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed(), nil)
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source)), nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStart: 0,
+			expectedEnd:   2,
+		},
+		{
+			name:          "invalid options",
+			options:       silence.Options{ThresholdDB: 3},
+			setupMocks:    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage) {},
+			expectedError: ErrInvalidArgument,
+		},
+		{
+			name:    "audio not converted yet",
+			options: silence.Options{ThresholdDB: -40},
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusPending}, nil)
+			},
+			expectedError: ErrConflict,
+		},
+		{
+			name:    "audio not found",
+			options: silence.Options{ThresholdDB: -40},
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("get: %w", repository.ErrNotFound))
+			},
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			audio, err := NewTrimUseCase(repo, s, silence.Options{ThresholdDB: -50}).Retrim(context.Background(), 1, tt.options)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, audio.TrimStart)
+			require.NotNil(t, audio.TrimEnd)
+			assert.InDelta(t, tt.expectedStart, *audio.TrimStart, 1e-9)
+			assert.InDelta(t, tt.expectedEnd, *audio.TrimEnd, 1e-9)
+		})
+	}
+}
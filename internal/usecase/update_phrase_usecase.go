@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+type UpdatePhraseUseCase struct {
+	phraseRepository repository.PhraseRepository
+}
+
+func NewUpdatePhraseUseCase(phraseRepository repository.PhraseRepository) *UpdatePhraseUseCase {
+	return &UpdatePhraseUseCase{
+		phraseRepository: phraseRepository,
+	}
+}
+
+// Update lets the owner of a phrase change its text. The previous text is
+// kept as a revision and the takes recorded so far are flagged as stale;
+// setting the text a phrase already has changes nothing.
+func (uc *UpdatePhraseUseCase) Update(ctx context.Context, ownerID, phraseID uint, text string) (*entity.Phrase, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("text must not be empty: %w", ErrInvalidArgument)
+	}
+
+	phrase, err := ownedPhrase(ctx, uc.phraseRepository, phraseID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if phrase.Phrase == text {
+		return phrase, nil
+	}
+
+	phrase, err = uc.phraseRepository.Revise(ctx, phraseID, text)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to update phrase")
+	}
+	return phrase, nil
+}
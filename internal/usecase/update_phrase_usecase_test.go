@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdatePhraseUseCase_Update(t *testing.T) {
+	tests := []struct {
+		name             string
+		text             string
+		mockSetup        func(*repoMocks.MockPhraseRepository)
+		expectedRevision int
+		expectedError    error
+	}{
+		{
+			name: "new text is revised",
+			text: "hello",
+			mockSetup: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 2, Phrase: "helo"}, nil)
+				repo.On("Revise", mock.Anything, uint(1), "hello").Return(&entity.Phrase{ID: 1, Phrase: "hello", Revision: 1}, nil)
+			},
+			expectedRevision: 1,
+		},
+		{
+			name: "same text is kept",
+			text: "hello",
+			mockSetup: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 2, Phrase: "hello"}, nil)
+			},
+		},
+		{
+			name:          "blank text",
+			text:          " ",
+			mockSetup:     func(repo *repoMocks.MockPhraseRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name: "not the owner",
+			text: "hello",
+			mockSetup: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 3, Phrase: "helo"}, nil)
+			},
+			expectedError: usecase.ErrForbidden,
+		},
+		{
+			name: "unknown phrase",
+			text: "hello",
+			mockSetup: func(repo *repoMocks.MockPhraseRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get phrase 1: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockPhraseRepository(t)
+			tt.mockSetup(repo)
+			uc := usecase.NewUpdatePhraseUseCase(repo)
+
+			phrase, err := uc.Update(context.Background(), 2, 1, tt.text)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.text, phrase.Phrase)
+			assert.Equal(t, tt.expectedRevision, phrase.Revision)
+		})
+	}
+}
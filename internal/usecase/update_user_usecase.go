@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+)
+
+// UserUpdate holds the fields to change; nil fields are left as they are.
+type UserUpdate struct {
+	Name              *string
+	ProcessingProfile *string
+	Attributes        map[string]string // Replaces all speaker attributes
+}
+
+type UpdateUserUseCase struct {
+	userRepository repository.UserRepository
+	profiles       ProcessingProfiles
+	speakerSchema  SpeakerSchema
+}
+
+func NewUpdateUserUseCase(userRepository repository.UserRepository, profiles ProcessingProfiles, speakerSchema SpeakerSchema) *UpdateUserUseCase {
+	return &UpdateUserUseCase{
+		userRepository: userRepository,
+		profiles:       profiles,
+		speakerSchema:  speakerSchema,
+	}
+}
+
+// Update applies the update to a user and returns the result.
+func (uc *UpdateUserUseCase) Update(ctx context.Context, userID uint, update UserUpdate) (*entity.User, error) {
+	if update.Name != nil && strings.TrimSpace(*update.Name) == "" {
+		return nil, fmt.Errorf("name must not be empty: %w", ErrInvalidArgument)
+	}
+	if update.ProcessingProfile != nil && !uc.profiles.Has(*update.ProcessingProfile) {
+		return nil, fmt.Errorf("unknown processing profile %q: %w", *update.ProcessingProfile, ErrInvalidArgument)
+	}
+	var attributes map[string]string
+	if update.Attributes != nil {
+		var err error
+		if attributes, err = uc.speakerSchema.normalize(update.Attributes); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := uc.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get user")
+	}
+	if update.Name != nil {
+		user.Name = *update.Name
+	}
+	if update.ProcessingProfile != nil {
+		user.ProcessingProfile = *update.ProcessingProfile
+	}
+	if attributes != nil {
+		user.Attributes = attributes
+	}
+	if err := uc.userRepository.Update(ctx, user); err != nil {
+		return nil, wrapRepoError(err, "failed to update user")
+	}
+	return user, nil
+}
@@ -0,0 +1,125 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateUserUseCase_Update(t *testing.T) {
+	profiles := usecase.ProcessingProfiles{"clean": {{Name: "highpass", Params: map[string]string{"f": "80"}}}}
+	speakerSchema := usecase.SpeakerSchema{"gender": {Values: []string{"female", "male"}}, "accent": {MaxLength: 10}}
+	name := func(s string) *string { return &s }
+
+	tests := []struct {
+		name          string
+		update        usecase.UserUpdate
+		mockSetup     func(*repoMocks.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name:   "rename keeps profile",
+			update: usecase.UserUpdate{Name: name("Jane Doe")},
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Name: "John Doe", ProcessingProfile: "clean"}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Name == "Jane Doe" && user.ProcessingProfile == "clean"
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "clear profile",
+			update: usecase.UserUpdate{ProcessingProfile: name("")},
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Name: "John Doe", ProcessingProfile: "clean"}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Name == "John Doe" && user.ProcessingProfile == ""
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "replace speaker attributes",
+			update: usecase.UserUpdate{Attributes: map[string]string{"gender": "FEMALE", "accent": ""}},
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).
+					Return(&entity.User{ID: 1, Name: "John Doe", Attributes: map[string]string{"accent": "Geordie"}}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return assert.ObjectsAreEqual(map[string]string{"gender": "female"}, user.Attributes)
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "rename keeps speaker attributes",
+			update: usecase.UserUpdate{Name: name("Jane Doe")},
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).
+					Return(&entity.User{ID: 1, Name: "John Doe", Attributes: map[string]string{"accent": "Geordie"}}, nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Attributes["accent"] == "Geordie"
+				})).Return(nil)
+			},
+		},
+		{
+			name:          "unknown speaker attribute",
+			update:        usecase.UserUpdate{Attributes: map[string]string{"height": "tall"}},
+			mockSetup:     func(repo *repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "disallowed speaker attribute value",
+			update:        usecase.UserUpdate{Attributes: map[string]string{"gender": "robot"}},
+			mockSetup:     func(repo *repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "speaker attribute too long",
+			update:        usecase.UserUpdate{Attributes: map[string]string{"accent": "Received Pronunciation"}},
+			mockSetup:     func(repo *repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "blank name",
+			update:        usecase.UserUpdate{Name: name("  ")},
+			mockSetup:     func(repo *repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:          "unknown profile",
+			update:        usecase.UserUpdate{ProcessingProfile: name("studio")},
+			mockSetup:     func(repo *repoMocks.MockUserRepository) {},
+			expectedError: usecase.ErrInvalidArgument,
+		},
+		{
+			name:   "user not found",
+			update: usecase.UserUpdate{Name: name("Jane Doe")},
+			mockSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, fmt.Errorf("failed to get user 1: %w", repository.ErrNotFound))
+			},
+			expectedError: usecase.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockUserRepository(t)
+			tt.mockSetup(repo)
+			uc := usecase.NewUpdateUserUseCase(repo, profiles, speakerSchema)
+
+			user, err := uc.Update(context.Background(), 1, tt.update)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, user)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, user)
+		})
+	}
+}
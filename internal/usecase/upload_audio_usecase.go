@@ -2,87 +2,277 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"path/filepath"
+	"os"
 	"time"
 
+	"github.com/ardfard/sb-test/internal/domain/converter"
 	"github.com/ardfard/sb-test/internal/domain/entity"
 	"github.com/ardfard/sb-test/internal/domain/queue"
 	"github.com/ardfard/sb-test/internal/domain/repository"
 	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/logger"
+	"github.com/ardfard/sb-test/pkg/util"
 )
 
 const (
 	basePath = "audio"
 )
 
+// originalPath is where an uploaded file is kept until it is converted.
+func originalPath(userID, phraseID uint, format string) string {
+	return fmt.Sprintf("%s/original/%d-%d.%s", basePath, userID, phraseID, format)
+}
+
 type UploadAudioUseCase struct {
-	repo             repository.AudioRepository
-	storage          storage.Storage
-	userRepository   repository.UserRepository
-	phraseRepository repository.PhraseRepository
-	queue            queue.TaskQueue
+	repo              repository.AudioRepository
+	storage           storage.Storage
+	converter         converter.AudioConverter
+	userRepository    repository.UserRepository
+	phraseRepository  repository.PhraseRepository
+	sessionRepository repository.RecordingSessionRepository
+	reviewRepository  repository.AudioReviewRepository
+	queue             queue.TaskQueue
+	policy            UploadPolicy
 }
 
 func NewUploadAudioUseCase(
 	repo repository.AudioRepository,
 	storage storage.Storage,
+	converter converter.AudioConverter,
 	queue queue.TaskQueue,
 	userRepository repository.UserRepository,
 	phraseRepository repository.PhraseRepository,
+	sessionRepository repository.RecordingSessionRepository,
+	reviewRepository repository.AudioReviewRepository,
+	policy UploadPolicy,
 ) *UploadAudioUseCase {
 	return &UploadAudioUseCase{
-		repo:             repo,
-		storage:          storage,
-		userRepository:   userRepository,
-		phraseRepository: phraseRepository,
-		queue:            queue,
+		repo:              repo,
+		storage:           storage,
+		converter:         converter,
+		userRepository:    userRepository,
+		phraseRepository:  phraseRepository,
+		sessionRepository: sessionRepository,
+		reviewRepository:  reviewRepository,
+		queue:             queue,
+		policy:            policy,
 	}
 }
 
-func (uc *UploadAudioUseCase) Upload(ctx context.Context, filename string, content io.Reader, userID uint, phraseID uint) (*entity.Audio, error) {
-	originalPath := fmt.Sprintf("%s/original/%d-%d.%s", basePath, userID, phraseID, filepath.Ext(filename))
-	audio := &entity.Audio{
-		OriginalName:  filename,
-		CurrentFormat: filepath.Ext(filename),
-		StoragePath:   originalPath,
-		Status:        entity.AudioStatusPending,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-		UserID:        userID,
-		PhraseID:      phraseID,
-	}
-
-	// check user and phrase exist
-	user, err := uc.userRepository.GetByID(ctx, userID)
+// Policy returns the restrictions applied to uploads.
+func (uc *UploadAudioUseCase) Policy() UploadPolicy {
+	return uc.policy
+}
+
+// Upload stores a take of the phrase by the user, in the recording session
+// sessionID unless it is 0, and queues its conversion.
+func (uc *UploadAudioUseCase) Upload(ctx context.Context, filename string, content io.Reader, userID, phraseID, sessionID uint) (*entity.Audio, error) {
+	// check the user exists and may record the phrase
+	if err := uc.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := uc.checkPhrase(ctx, userID, phraseID); err != nil {
+		return nil, err
+	}
+	if err := uc.checkSession(ctx, sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	// Reject anything that is not an allowed audio container before touching disk
+	format, content, err := uc.policy.sniff(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Spool the upload to disk so it can be probed before it is stored
+	tempPath, err := util.WriteTempFile(uc.policy.limit(content), format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool upload: %v", err)
+	}
+	defer os.Remove(tempPath)
+
+	info, err := os.Stat(tempPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat spooled upload: %v", err)
+	}
+	if err := uc.policy.checkSize(info.Size()); err != nil {
+		return nil, err
+	}
+
+	meta, err := uc.converter.Probe(ctx, tempPath)
+	if err != nil {
+		return nil, reject(ErrUnprocessable, RejectUnreadableAudio, "%v", err)
+	}
+	if err := uc.policy.checkMetadata(meta); err != nil {
+		return nil, err
+	}
+
+	return uc.store(ctx, userID, phraseID, sessionID, filename, tempPath, meta)
+}
+
+// checkUploader verifies that the user exists and is not being deleted.
+func checkUploader(ctx context.Context, users repository.UserRepository, userID uint) error {
+	user, err := users.GetByID(ctx, userID)
+	if err != nil {
+		return wrapRepoError(err, "failed to get user")
+	}
+	if user.Deleting {
+		return fmt.Errorf("user %d is being deleted: %w", userID, ErrGone)
+	}
+	return nil
+}
+
+// checkUser verifies that the user exists and may upload.
+func (uc *UploadAudioUseCase) checkUser(ctx context.Context, userID uint) error {
+	return checkUploader(ctx, uc.userRepository, userID)
+}
+
+// checkPhrase verifies that the user may record the phrase and has no take
+// of it yet that an upload cannot replace.
+func (uc *UploadAudioUseCase) checkPhrase(ctx context.Context, userID, phraseID uint) error {
+	if _, err := authorizePhrase(ctx, uc.phraseRepository, phraseID, userID); err != nil {
+		return err
+	}
+	_, err := uc.previousTake(ctx, userID, phraseID)
+	return err
+}
+
+// previousTake returns the take of the phrase by the user that a new upload
+// replaces, or nil if there is none. A take can be replaced once it failed,
+// or once it completed and is stale or was rejected or sent back by a
+// reviewer; takes still being converted are never replaced.
+func (uc *UploadAudioUseCase) previousTake(ctx context.Context, userID, phraseID uint) (*entity.Audio, error) {
+	take, err := uc.repo.GetByUserIDAndPhraseID(ctx, userID, phraseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audio: %v", err)
+	}
+	replaceable, err := uc.replaceable(ctx, take)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %v", err)
+		return nil, err
 	}
+	if !replaceable {
+		return nil, fmt.Errorf("phrase %d already has a recording: %w", phraseID, ErrConflict)
+	}
+	return take, nil
+}
 
-	phrase, err := uc.phraseRepository.GetByID(ctx, phraseID)
+// replaceable must agree with the phrases PhraseRepository.NextToRecord
+// offers again.
+func (uc *UploadAudioUseCase) replaceable(ctx context.Context, take *entity.Audio) (bool, error) {
+	switch {
+	case take.Status == entity.AudioStatusFailed:
+		return true, nil
+	case take.Status != entity.AudioStatusCompleted:
+		return false, nil
+	case take.Stale:
+		return true, nil
+	}
+	review, err := uc.reviewRepository.GetByAudioID(ctx, take.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get phrase: %v", err)
+		return false, fmt.Errorf("failed to get review: %v", err)
 	}
+	return review.Status.RequiresRerecord(), nil
+}
+
+// checkSession verifies that takes of userID may be uploaded in the session.
+func (uc *UploadAudioUseCase) checkSession(ctx context.Context, sessionID, userID uint) error {
+	return checkSession(ctx, uc.sessionRepository, sessionID, userID)
+}
 
-	if user == nil || phrase == nil {
-		return nil, fmt.Errorf("user or phrase not found")
+// store saves the validated recording spooled at path as a new audio and
+// queues its conversion. A replaceable take of the phrase is replaced along
+// with its review, analysis and annotations, and its files are deleted first:
+// the new original may be stored at the same path.
+func (uc *UploadAudioUseCase) store(ctx context.Context, userID, phraseID, sessionID uint, filename, path string, meta *entity.AudioMetadata) (*entity.Audio, error) {
+	// Resumable and segmented uploads reach here long after they were
+	// checked, and the user may have been marked for deletion since.
+	if err := uc.checkUser(ctx, userID); err != nil {
+		return nil, err
 	}
+	storagePath := originalPath(userID, phraseID, meta.Format)
+	audio := &entity.Audio{
+		OriginalName: filename,
+		StoragePath:  storagePath,
+		Status:       entity.AudioStatusPending,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		UserID:       userID,
+		PhraseID:     phraseID,
+		SessionID:    sessionID,
+	}
+	audio.ApplyMetadata(meta)
 
-	audio, err = uc.repo.Store(ctx, audio)
+	previous, err := uc.previousTake(ctx, userID, phraseID)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		audio, err = uc.repo.Store(ctx, audio)
+	} else {
+		paths, prefixes := audioFiles(previous)
+		if err := deleteFiles(ctx, uc.storage, paths, prefixes); err != nil {
+			return nil, fmt.Errorf("failed to delete replaced take %d: %v", previous.ID, err)
+		}
+		audio, err = uc.repo.Replace(ctx, previous.ID, audio)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to store audio metadata: %v", err)
 	}
 
+	if err := uc.send(ctx, audio.ID, storagePath, path); err != nil {
+		// Drop the take, so the phrase can be uploaded again
+		if discardErr := uc.discard(ctx, []*entity.Audio{audio}); discardErr != nil {
+			logger.Errorf("Failed to discard audio %d: %v", audio.ID, discardErr)
+		}
+		return nil, err
+	}
+	return audio, nil
+}
+
+// send uploads the original file of a stored audio from path to storagePath
+// and queues its conversion.
+func (uc *UploadAudioUseCase) send(ctx context.Context, audioID uint, storagePath, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open spooled upload: %v", err)
+	}
+	defer file.Close()
+
 	// Upload original file to storage
-	if err := uc.storage.Upload(ctx, originalPath, content); err != nil {
-		return nil, fmt.Errorf("failed to upload original file: %v", err)
+	if err := uc.storage.Upload(ctx, storagePath, file); err != nil {
+		return fmt.Errorf("failed to upload original file: %v", err)
 	}
 
 	// Enqueue conversion task
-	if err := uc.queue.Enqueue(ctx, audio.ID); err != nil {
-		return nil, fmt.Errorf("failed to enqueue conversion: %v", err)
+	if err := uc.queue.Enqueue(ctx, audioID); err != nil {
+		return fmt.Errorf("failed to enqueue conversion: %v", err)
 	}
+	return nil
+}
 
-	return audio, nil
+// discard removes takes stored by an upload that failed later on, with
+// their files and queued conversions, so the phrases can be uploaded again.
+func (uc *UploadAudioUseCase) discard(ctx context.Context, audios []*entity.Audio) error {
+	var paths, prefixes []string
+	for _, audio := range audios {
+		if err := uc.queue.Cancel(ctx, audio.ID); err != nil {
+			return fmt.Errorf("failed to cancel conversion of audio %d: %v", audio.ID, err)
+		}
+		if err := uc.repo.Delete(ctx, audio.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("failed to delete audio %d: %v", audio.ID, err)
+		}
+		audioPaths, audioPrefixes := audioFiles(audio)
+		paths = append(paths, audioPaths...)
+		prefixes = append(prefixes, audioPrefixes...)
+	}
+	return deleteFiles(ctx, uc.storage, paths, prefixes)
 }
@@ -5,106 +5,208 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	converterMocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
 	queueMocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
 	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
 	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// mp3Content starts with an ID3 tag so that it sniffs as mp3.
+const mp3Content = "ID3\x04\x00\x00test content"
+
 func TestUploadAudioUseCase_Upload(t *testing.T) {
 	tests := []struct {
 		name          string
 		filename      string
-		setupMocks    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage, *queueMocks.MockTaskQueue, *repoMocks.MockUserRepository, *repoMocks.MockPhraseRepository)
+		setupMocks    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage, *converterMocks.MockAudioConverter, *queueMocks.MockTaskQueue, *repoMocks.MockUserRepository, *repoMocks.MockPhraseRepository)
 		expectedError bool
 	}{
 		{
 			name:     "successful upload",
 			filename: "test.mp3",
-			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				conv.On("Probe", mock.Anything, mock.MatchedBy(func(path string) bool {
+					return strings.HasSuffix(path, ".mp3")
+				})).Return(&entity.AudioMetadata{Format: "mp3", Duration: 1.5, SampleRate: 44100, Channels: 2, Codec: "mp3"}, nil)
+
 				repo.On("Store", mock.Anything, mock.MatchedBy(func(audio *entity.Audio) bool {
-					return audio.OriginalName == "test.mp3" && audio.CurrentFormat == ".mp3"
-				})).Return(&entity.Audio{ID: 1, OriginalName: "test.mp3", CurrentFormat: ".mp3"}, nil)
+					return audio.OriginalName == "test.mp3" && audio.CurrentFormat == "mp3" &&
+						audio.Duration == 1.5 && audio.SampleRate == 44100 && audio.Channels == 2 && audio.Codec == "mp3"
+				})).Return(&entity.Audio{ID: 1, OriginalName: "test.mp3", CurrentFormat: "mp3"}, nil)
 
-				storage.On("Upload", mock.Anything, mock.MatchedBy(func(path string) bool {
-					return strings.HasPrefix(path, fmt.Sprintf("%s/original/", basePath)) && strings.HasSuffix(path, ".mp3")
-				}), mock.Anything).Return(nil)
+				storage.On("Upload", mock.Anything, fmt.Sprintf("%s/original/1-1.mp3", basePath), mock.Anything).Return(nil)
 
 				queue.On("Enqueue", mock.Anything, mock.AnythingOfType("uint")).Return(nil)
 
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
-				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
 			},
 			expectedError: false,
 		},
+		{
+			name:     "detected container overrides extension",
+			filename: "take.m4a",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				conv.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "mp3", Codec: "mp3"}, nil)
+				repo.On("Store", mock.Anything, mock.MatchedBy(func(audio *entity.Audio) bool {
+					return audio.CurrentFormat == "mp3" && audio.StoragePath == fmt.Sprintf("%s/original/1-1.mp3", basePath)
+				})).Return(&entity.Audio{ID: 1, OriginalName: "take.m4a", CurrentFormat: "mp3"}, nil)
+				storage.On("Upload", mock.Anything, fmt.Sprintf("%s/original/1-1.mp3", basePath), mock.Anything).Return(nil)
+				queue.On("Enqueue", mock.Anything, uint(1)).Return(nil)
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name:     "unreadable audio",
+			filename: "test.mp3",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				conv.On("Probe", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+			},
+			expectedError: true,
+		},
 		{
 			name:     "repository error",
 			filename: "test.mp3",
-			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				conv.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "mp3"}, nil)
 				repo.On("Store", mock.Anything, mock.Anything).Return(nil, assert.AnError)
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
-				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
 			},
 			expectedError: true,
 		},
 		{
 			name:     "storage error",
 			filename: "test.mp3",
-			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
-				repo.On("Store", mock.Anything, mock.Anything).Return(&entity.Audio{ID: 1, OriginalName: "test.mp3", CurrentFormat: ".mp3"}, nil)
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				conv.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "mp3"}, nil)
+				repo.On("Store", mock.Anything, mock.Anything).Return(&entity.Audio{ID: 1, OriginalName: "test.mp3", CurrentFormat: "mp3"}, nil)
 				storage.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
+				// The take is dropped, so the phrase can be uploaded again
+				queue.On("Cancel", mock.Anything, uint(1)).Return(nil)
+				repo.On("Delete", mock.Anything, uint(1)).Return(nil)
+				storage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+				storage.On("DeletePrefix", mock.Anything, mock.Anything).Return(nil)
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
-				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
 			},
 			expectedError: true,
 		},
 		{
 			name:     "queue error",
 			filename: "test.mp3",
-			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
-				repo.On("Store", mock.Anything, mock.Anything).Return(&entity.Audio{ID: 1, OriginalName: "test.mp3", CurrentFormat: ".mp3"}, nil)
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				conv.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "mp3"}, nil)
+				repo.On("Store", mock.Anything, mock.Anything).Return(&entity.Audio{ID: 1, OriginalName: "test.mp3", CurrentFormat: "mp3"}, nil)
 				storage.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 				queue.On("Enqueue", mock.Anything, mock.Anything).Return(assert.AnError)
+				// The take is dropped, so the phrase can be uploaded again
+				queue.On("Cancel", mock.Anything, uint(1)).Return(nil)
+				repo.On("Delete", mock.Anything, uint(1)).Return(nil)
+				storage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+				storage.On("DeletePrefix", mock.Anything, mock.Anything).Return(nil)
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+			},
+			expectedError: true,
+		},
+		{
+			name:     "failed take is replaced",
+			filename: "test.mp3",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(&entity.Audio{
+					ID: 9, UserID: 1, PhraseID: 1, Status: entity.AudioStatusFailed, StoragePath: "audio/original/1-1.m4a",
+				}, nil)
+				conv.On("Probe", mock.Anything, mock.Anything).Return(&entity.AudioMetadata{Format: "mp3"}, nil)
+				// The files of the failed take go before the new original is stored.
+				storage.On("Delete", mock.Anything, "audio/original/1-1.m4a").Return(nil).Once()
+				storage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+				storage.On("DeletePrefix", mock.Anything, mock.Anything).Return(nil)
+				repo.On("Replace", mock.Anything, uint(9), mock.MatchedBy(func(audio *entity.Audio) bool {
+					return audio.UserID == 1 && audio.PhraseID == 1 && audio.Status == entity.AudioStatusPending
+				})).Return(&entity.Audio{ID: 10, OriginalName: "test.mp3", CurrentFormat: "mp3"}, nil)
+				storage.On("Upload", mock.Anything, fmt.Sprintf("%s/original/1-1.mp3", basePath), mock.Anything).Return(nil)
+				queue.On("Enqueue", mock.Anything, uint(10)).Return(nil)
+			},
+			expectedError: false,
+		},
+		{
+			name:     "phrase already recorded",
+			filename: "test.mp3",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
-				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(&entity.Audio{
+					ID: 9, UserID: 1, PhraseID: 1, Status: entity.AudioStatusConverting,
+				}, nil)
 			},
 			expectedError: true,
 		},
 		{
 			name:     "user not found",
 			filename: "test.mp3",
-			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, assert.AnError)
 			},
 			expectedError: true,
 		},
+		{
+			name:     "user being deleted",
+			filename: "test.mp3",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1, Deleting: true}, nil)
+			},
+			expectedError: true,
+		},
 		{
 			name:     "phrase not found",
 			filename: "test.mp3",
-			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
 				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
 				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(nil, assert.AnError)
 			},
 			expectedError: true,
 		},
+		{
+			name:     "phrase of another user",
+			filename: "test.mp3",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, storage *storageMocks.MockStorage, conv *converterMocks.MockAudioConverter, queue *queueMocks.MockTaskQueue, userRepo *repoMocks.MockUserRepository, phraseRepo *repoMocks.MockPhraseRepository) {
+				userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+				phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 2}, nil)
+				phraseRepo.On("IsSharedWith", mock.Anything, uint(1), uint(1)).Return(false, nil)
+			},
+			expectedError: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &repoMocks.MockAudioRepository{}
 			storage := &storageMocks.MockStorage{}
+			conv := &converterMocks.MockAudioConverter{}
 			queue := &queueMocks.MockTaskQueue{}
 			userRepo := &repoMocks.MockUserRepository{}
 			phraseRepo := &repoMocks.MockPhraseRepository{}
 
-			tt.setupMocks(repo, storage, queue, userRepo, phraseRepo)
+			tt.setupMocks(repo, storage, conv, queue, userRepo, phraseRepo)
+			repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(nil, repository.ErrNotFound).Maybe()
 
-			uc := NewUploadAudioUseCase(repo, storage, queue, userRepo, phraseRepo)
-			content := strings.NewReader("test content")
-			audio, err := uc.Upload(context.Background(), tt.filename, content, 1, 1)
+			uc := NewUploadAudioUseCase(repo, storage, conv, queue, userRepo, phraseRepo, repoMocks.NewMockRecordingSessionRepository(t), repoMocks.NewMockAudioReviewRepository(t), UploadPolicy{})
+			content := strings.NewReader(mp3Content)
+			audio, err := uc.Upload(context.Background(), tt.filename, content, 1, 1, 0)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -117,9 +219,152 @@ func TestUploadAudioUseCase_Upload(t *testing.T) {
 
 			repo.AssertExpectations(t)
 			storage.AssertExpectations(t)
+			conv.AssertExpectations(t)
 			queue.AssertExpectations(t)
 			userRepo.AssertExpectations(t)
 			phraseRepo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestUploadAudioUseCase_UploadPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		policy         UploadPolicy
+		probe          *entity.AudioMetadata
+		expectedKind   error
+		expectedReason string
+	}{
+		{
+			name:           "renamed document",
+			content:        "%PDF-1.7 not audio",
+			expectedKind:   ErrUnsupportedMediaType,
+			expectedReason: RejectUnsupportedFormat,
+		},
+		{
+			name:           "format not allowed",
+			content:        mp3Content,
+			policy:         UploadPolicy{AllowedFormats: []string{"m4a", "wav"}},
+			expectedKind:   ErrUnsupportedMediaType,
+			expectedReason: RejectFormatNotAllowed,
+		},
+		{
+			name:           "file too large",
+			content:        mp3Content,
+			policy:         UploadPolicy{MaxSize: 8},
+			expectedKind:   ErrTooLarge,
+			expectedReason: RejectFileTooLarge,
+		},
+		{
+			name:           "duration too short",
+			content:        mp3Content,
+			policy:         UploadPolicy{MinDuration: time.Second},
+			probe:          &entity.AudioMetadata{Format: "mp3", Duration: 0.5, SampleRate: 44100},
+			expectedKind:   ErrUnprocessable,
+			expectedReason: RejectDurationTooShort,
+		},
+		{
+			name:           "duration too long",
+			content:        mp3Content,
+			policy:         UploadPolicy{MaxDuration: time.Minute},
+			probe:          &entity.AudioMetadata{Format: "mp3", Duration: 61, SampleRate: 44100},
+			expectedKind:   ErrUnprocessable,
+			expectedReason: RejectDurationTooLong,
+		},
+		{
+			name:           "sample rate too low",
+			content:        mp3Content,
+			policy:         UploadPolicy{MinSampleRate: 16000},
+			probe:          &entity.AudioMetadata{Format: "mp3", Duration: 1, SampleRate: 8000},
+			expectedKind:   ErrUnprocessable,
+			expectedReason: RejectSampleRateTooLow,
+		},
+		{
+			name:           "sample rate too high",
+			content:        mp3Content,
+			policy:         UploadPolicy{MaxSampleRate: 48000},
+			probe:          &entity.AudioMetadata{Format: "mp3", Duration: 1, SampleRate: 96000},
+			expectedKind:   ErrUnprocessable,
+			expectedReason: RejectSampleRateTooHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			storage := storageMocks.NewMockStorage(t)
+			conv := converterMocks.NewMockAudioConverter(t)
+			queue := queueMocks.NewMockTaskQueue(t)
+			userRepo := repoMocks.NewMockUserRepository(t)
+			phraseRepo := repoMocks.NewMockPhraseRepository(t)
+
+			userRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.User{ID: 1}, nil)
+			phraseRepo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Phrase{ID: 1, UserID: 1}, nil)
+			repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(1)).Return(nil, repository.ErrNotFound)
+			if tt.probe != nil {
+				conv.On("Probe", mock.Anything, mock.Anything).Return(tt.probe, nil)
+			}
+
+			uc := NewUploadAudioUseCase(repo, storage, conv, queue, userRepo, phraseRepo, repoMocks.NewMockRecordingSessionRepository(t), repoMocks.NewMockAudioReviewRepository(t), tt.policy)
+			audio, err := uc.Upload(context.Background(), "test.mp3", strings.NewReader(tt.content), 1, 1, 0)
+
+			assert.Nil(t, audio)
+			assert.ErrorIs(t, err, tt.expectedKind)
+			var rejected *UploadRejectedError
+			if assert.ErrorAs(t, err, &rejected) {
+				assert.Equal(t, tt.expectedReason, rejected.Reason)
+			}
+		})
+	}
+}
+
+func TestUploadAudioUseCase_PreviousTake(t *testing.T) {
+	tests := []struct {
+		name        string
+		take        *entity.Audio
+		review      entity.ReviewStatus // No review when empty
+		wantReplace bool
+	}{
+		{name: "no take", wantReplace: false},
+		{name: "failed take", take: &entity.Audio{ID: 9, Status: entity.AudioStatusFailed}, wantReplace: true},
+		{name: "take being converted", take: &entity.Audio{ID: 9, Status: entity.AudioStatusConverting, Stale: true}},
+		{name: "unreviewed take", take: &entity.Audio{ID: 9, Status: entity.AudioStatusCompleted}},
+		{name: "approved take", take: &entity.Audio{ID: 9, Status: entity.AudioStatusCompleted}, review: entity.ReviewStatusApproved},
+		{name: "stale take", take: &entity.Audio{ID: 9, Status: entity.AudioStatusCompleted, Stale: true}, wantReplace: true},
+		{name: "rejected take", take: &entity.Audio{ID: 9, Status: entity.AudioStatusCompleted}, review: entity.ReviewStatusRejected, wantReplace: true},
+		{name: "take to record again", take: &entity.Audio{ID: 9, Status: entity.AudioStatusCompleted}, review: entity.ReviewStatusNeedsRerecord, wantReplace: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			reviewRepo := repoMocks.NewMockAudioReviewRepository(t)
+			if tt.take == nil {
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(2)).Return(nil, repository.ErrNotFound)
+			} else {
+				repo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(2)).Return(tt.take, nil)
+			}
+			if tt.review != "" {
+				reviewRepo.On("GetByAudioID", mock.Anything, uint(9)).Return(&entity.AudioReview{AudioID: 9, Status: tt.review}, nil)
+			} else {
+				reviewRepo.On("GetByAudioID", mock.Anything, uint(9)).Return(nil, repository.ErrNotFound).Maybe()
+			}
+
+			uc := NewUploadAudioUseCase(repo, storageMocks.NewMockStorage(t), converterMocks.NewMockAudioConverter(t), queueMocks.NewMockTaskQueue(t),
+				repoMocks.NewMockUserRepository(t), repoMocks.NewMockPhraseRepository(t), repoMocks.NewMockRecordingSessionRepository(t), reviewRepo, UploadPolicy{})
+			previous, err := uc.previousTake(context.Background(), 1, 2)
+
+			switch {
+			case tt.wantReplace:
+				require.NoError(t, err)
+				assert.Equal(t, tt.take, previous)
+			case tt.take == nil:
+				require.NoError(t, err)
+				assert.Nil(t, previous)
+			default:
+				assert.ErrorIs(t, err, ErrConflict)
+			}
+		})
+	}
+}
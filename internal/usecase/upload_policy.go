@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/pkg/sniff"
+)
+
+// UploadPolicy restricts what UploadAudioUseCase accepts. Zero values disable
+// the corresponding check.
+type UploadPolicy struct {
+	AllowedFormats []string // Container formats as reported by sniffing, e.g. "m4a"
+	MaxSize        int64    // Bytes
+	MinDuration    time.Duration
+	MaxDuration    time.Duration
+	MinSampleRate  int // Hz
+	MaxSampleRate  int // Hz
+}
+
+// Machine-readable reasons carried by UploadRejectedError.
+const (
+	RejectFileTooLarge      = "file_too_large"
+	RejectUnsupportedFormat = "unsupported_format"
+	RejectFormatNotAllowed  = "format_not_allowed"
+	RejectUnreadableAudio   = "unreadable_audio"
+	RejectDurationTooShort  = "duration_too_short"
+	RejectDurationTooLong   = "duration_too_long"
+	RejectSampleRateTooLow  = "sample_rate_too_low"
+	RejectSampleRateTooHigh = "sample_rate_too_high"
+)
+
+// UploadRejectedError reports an upload that violates the UploadPolicy. It
+// wraps ErrTooLarge, ErrUnsupportedMediaType or ErrUnprocessable.
+type UploadRejectedError struct {
+	Reason  string
+	Message string
+	kind    error
+}
+
+func (e *UploadRejectedError) Error() string {
+	return fmt.Sprintf("upload rejected (%s): %s", e.Reason, e.Message)
+}
+
+func (e *UploadRejectedError) Unwrap() error {
+	return e.kind
+}
+
+func reject(kind error, reason, format string, args ...interface{}) error {
+	return &UploadRejectedError{Reason: reason, Message: fmt.Sprintf(format, args...), kind: kind}
+}
+
+// sniff reads the leading bytes of content to identify its container. It
+// returns the detected format and a reader that yields the full content.
+func (p UploadPolicy) sniff(content io.Reader) (string, io.Reader, error) {
+	header := make([]byte, sniff.HeaderSize)
+	n, err := io.ReadFull(content, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", nil, fmt.Errorf("failed to read upload: %v", err)
+	}
+	header = header[:n]
+	content = io.MultiReader(bytes.NewReader(header), content)
+
+	format := sniff.AudioFormat(header)
+	if format == "" {
+		return "", nil, reject(ErrUnsupportedMediaType, RejectUnsupportedFormat, "file is not a recognized audio container")
+	}
+	if len(p.AllowedFormats) > 0 && !slices.Contains(p.AllowedFormats, format) {
+		return "", nil, reject(ErrUnsupportedMediaType, RejectFormatNotAllowed, "%s uploads are not accepted", format)
+	}
+	return format, content, nil
+}
+
+// limit caps content one byte past MaxSize so oversized uploads can be detected.
+func (p UploadPolicy) limit(content io.Reader) io.Reader {
+	if p.MaxSize <= 0 {
+		return content
+	}
+	return io.LimitReader(content, p.MaxSize+1)
+}
+
+func (p UploadPolicy) checkSize(size int64) error {
+	if p.MaxSize > 0 && size > p.MaxSize {
+		return reject(ErrTooLarge, RejectFileTooLarge, "file exceeds %d bytes", p.MaxSize)
+	}
+	return nil
+}
+
+// checkMetadata validates probed properties against the policy.
+func (p UploadPolicy) checkMetadata(meta *entity.AudioMetadata) error {
+	duration := time.Duration(meta.Duration * float64(time.Second))
+	switch {
+	case p.MinDuration > 0 && duration < p.MinDuration:
+		return reject(ErrUnprocessable, RejectDurationTooShort, "duration %s is shorter than %s", duration, p.MinDuration)
+	case p.MaxDuration > 0 && duration > p.MaxDuration:
+		return reject(ErrUnprocessable, RejectDurationTooLong, "duration %s is longer than %s", duration, p.MaxDuration)
+	case p.MinSampleRate > 0 && meta.SampleRate < p.MinSampleRate:
+		return reject(ErrUnprocessable, RejectSampleRateTooLow, "sample rate %d Hz is below %d Hz", meta.SampleRate, p.MinSampleRate)
+	case p.MaxSampleRate > 0 && meta.SampleRate > p.MaxSampleRate:
+		return reject(ErrUnprocessable, RejectSampleRateTooHigh, "sample rate %d Hz is above %d Hz", meta.SampleRate, p.MaxSampleRate)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	"github.com/ardfard/sb-test/pkg/util"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/ardfard/sb-test/pkg/waveform"
+)
+
+// WaveformUseCase computes and serves min/max peak data for converted audio.
+// It runs as a post-processor of ConvertAudioUseCase and regenerates missing
+// artifacts on demand.
+type WaveformUseCase struct {
+	repo    repository.AudioRepository
+	storage storage.Storage
+	options waveform.Options
+}
+
+func NewWaveformUseCase(repo repository.AudioRepository, storage storage.Storage, options waveform.Options) *WaveformUseCase {
+	return &WaveformUseCase{
+		repo:    repo,
+		storage: storage,
+		options: options,
+	}
+}
+
+func waveformPath(audioID uint) string {
+	return fmt.Sprintf("%s/waveform/%d.dat", basePath, audioID)
+}
+
+// Process computes the waveform of the converted WAV at path and stores it.
+func (uc *WaveformUseCase) Process(ctx context.Context, audio *entity.Audio, path string) error {
+	_, err := uc.generate(ctx, audio.ID, path)
+	return err
+}
+
+func (uc *WaveformUseCase) generate(ctx context.Context, audioID uint, path string) (*waveform.Waveform, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio: %v", err)
+	}
+	defer file.Close()
+
+	reader, err := wav.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %v", err)
+	}
+	w, err := waveform.Compute(reader, uc.options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute waveform: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	if err := uc.storage.Upload(ctx, waveformPath(audioID), &buf); err != nil {
+		return nil, fmt.Errorf("failed to store waveform: %v", err)
+	}
+	return w, nil
+}
+
+// Get returns the stored waveform for a converted audio, computing it first
+// if it does not exist yet.
+func (uc *WaveformUseCase) Get(ctx context.Context, audioID uint) (*waveform.Waveform, error) {
+	audio, err := uc.repo.GetByID(ctx, audioID)
+	if err != nil {
+		return nil, wrapRepoError(err, "failed to get audio")
+	}
+	if audio.Status != entity.AudioStatusCompleted {
+		return nil, fmt.Errorf("audio %d is %s: %w", audioID, audio.Status, ErrConflict)
+	}
+
+	reader, err := uc.storage.Download(ctx, waveformPath(audioID))
+	if err == nil {
+		defer reader.Close()
+		w, err := waveform.Read(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read waveform: %v", err)
+		}
+		return w, nil
+	}
+	if !errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, fmt.Errorf("failed to download waveform: %v", err)
+	}
+
+	// Audio converted before waveforms existed: compute from the stored WAV.
+	source, err := uc.storage.Download(ctx, audio.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download audio: %v", err)
+	}
+	defer source.Close()
+
+	path, err := util.WriteTempFile(source, audio.CurrentFormat)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+
+	return uc.generate(ctx, audioID, path)
+}
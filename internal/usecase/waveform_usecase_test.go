@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/repository"
+	"github.com/ardfard/sb-test/internal/domain/storage"
+	repoMocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storageMocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/ardfard/sb-test/pkg/waveform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaveformUseCase_Get(t *testing.T) {
+	options := waveform.Options{PixelsPerSecond: 4, Bits: 8, Channels: 1}
+	completed := &entity.Audio{ID: 1, Status: entity.AudioStatusCompleted, CurrentFormat: "wav", StoragePath: "audio/converted/1.wav"}
+
+	stored := &waveform.Waveform{Version: 2, Channels: 1, SampleRate: 8, SamplesPerPixel: 2, Bits: 8, Length: 1, Data: []int16{-5, 5}}
+	var storedBytes bytes.Buffer
+	_, err := stored.WriteTo(&storedBytes)
+	require.NoError(t, err)
+
+	var source bytes.Buffer
+	require.NoError(t, wav.Encode(&source, 8, 1, []float64{0, 0.5, -0.5, 0}))
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*repoMocks.MockAudioRepository, *storageMocks.MockStorage)
+		expected      *waveform.Waveform
+		expectedError error
+	}{
+		{
+			name: "stored waveform",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/waveform/1.dat").Return(io.NopCloser(bytes.NewReader(storedBytes.Bytes())), nil)
+			},
+			expected: stored,
+		},
+		{
+			name: "missing waveform is generated",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(completed, nil)
+				s.On("Download", mock.Anything, "audio/waveform/1.dat").Return(nil, fmt.Errorf("open: %w", storage.ErrObjectNotFound))
+				s.On("Download", mock.Anything, "audio/converted/1.wav").Return(io.NopCloser(bytes.NewReader(source.Bytes())), nil)
+				s.On("Upload", mock.Anything, "audio/waveform/1.dat", mock.Anything).Return(nil)
+			},
+			expected: &waveform.Waveform{Version: 2, Channels: 1, SampleRate: 8, SamplesPerPixel: 2, Bits: 8, Length: 2, Data: []int16{0, 64, -64, 0}},
+		},
+		{
+			name: "audio not converted yet",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(&entity.Audio{ID: 1, Status: entity.AudioStatusConverting}, nil)
+			},
+			expectedError: ErrConflict,
+		},
+		{
+			name: "audio not found",
+			setupMocks: func(repo *repoMocks.MockAudioRepository, s *storageMocks.MockStorage) {
+				repo.On("GetByID", mock.Anything, uint(1)).Return(nil, repository.ErrNotFound)
+			},
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewMockAudioRepository(t)
+			s := storageMocks.NewMockStorage(t)
+			tt.setupMocks(repo, s)
+
+			uc := NewWaveformUseCase(repo, s, options)
+			got, err := uc.Get(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package worker
+
+import (
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	"github.com/ardfard/sb-test/internal/usecase"
+)
+
+// NewCompilationWorker creates a worker that builds phrase compilations.
+func NewCompilationWorker(
+	queue queue.TaskQueue,
+	compilationUseCase *usecase.CompilationUseCase,
+) *QueueWorker {
+	return NewQueueWorker(queue, "build compilation", compilationUseCase.Process)
+}
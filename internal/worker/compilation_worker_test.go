@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	convertermocks "github.com/ardfard/sb-test/internal/infrastructure/converter/mocks"
+	queuemocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repomocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storagemocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilationWorker_ProcessNextMessage(t *testing.T) {
+	var source bytes.Buffer
+	require.NoError(t, wav.Encode(&source, 8, 1, []float64{0.5, 0.5}))
+	audio := &entity.Audio{ID: 4, UserID: 1, PhraseID: 2, Status: entity.AudioStatusCompleted, StoragePath: "audio/converted/4.wav"}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*queuemocks.MockTaskQueue, *repomocks.MockCompilationRepository, *repomocks.MockAudioRepository, *storagemocks.MockStorage)
+		expectedErrMsg string
+	}{
+		{
+			name: "Success",
+			setupMocks: func(q *queuemocks.MockTaskQueue, repo *repomocks.MockCompilationRepository, audioRepo *repomocks.MockAudioRepository, s *storagemocks.MockStorage) {
+				q.On("Dequeue", mock.Anything).Return(&queue.Task{ID: "1", Payload: 3}, nil)
+				repo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Compilation{ID: 3, UserID: 1, PhraseIDs: []uint{2}, Format: "wav"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+				audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(2)).Return(audio, nil)
+				s.On("Download", mock.Anything, audio.StoragePath).Return(func(context.Context, string) (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader(source.Bytes())), nil
+				})
+				s.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				q.On("Complete", mock.Anything, "1").Return(nil)
+			},
+		},
+		{
+			name: "Build failure fails the task",
+			setupMocks: func(q *queuemocks.MockTaskQueue, repo *repomocks.MockCompilationRepository, audioRepo *repomocks.MockAudioRepository, s *storagemocks.MockStorage) {
+				q.On("Dequeue", mock.Anything).Return(&queue.Task{ID: "1", Payload: 3}, nil)
+				repo.On("GetByID", mock.Anything, uint(3)).Return(&entity.Compilation{ID: 3, UserID: 1, PhraseIDs: []uint{2}, Format: "wav"}, nil)
+				repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+				audioRepo.On("GetByUserIDAndPhraseID", mock.Anything, uint(1), uint(2)).Return(audio, nil)
+				s.On("Download", mock.Anything, audio.StoragePath).Return(nil, errors.New("storage down"))
+				q.On("Fail", mock.Anything, "1", mock.AnythingOfType("string")).Return(nil)
+			},
+			expectedErrMsg: "failed to build compilation",
+		},
+		{
+			name: "Dequeue error",
+			setupMocks: func(q *queuemocks.MockTaskQueue, repo *repomocks.MockCompilationRepository, audioRepo *repomocks.MockAudioRepository, s *storagemocks.MockStorage) {
+				q.On("Dequeue", mock.Anything).Return(nil, errors.New("queue closed"))
+			},
+			expectedErrMsg: "failed to dequeue message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQueue := queuemocks.NewMockTaskQueue(t)
+			mockRepo := repomocks.NewMockCompilationRepository(t)
+			mockAudioRepo := repomocks.NewMockAudioRepository(t)
+			mockStorage := storagemocks.NewMockStorage(t)
+			tt.setupMocks(mockQueue, mockRepo, mockAudioRepo, mockStorage)
+
+			useCase := usecase.NewCompilationUseCase(mockRepo, mockAudioRepo, repomocks.NewMockUserRepository(t), mockStorage, convertermocks.NewMockAudioConverter(t), mockQueue)
+			worker := NewCompilationWorker(mockQueue, useCase)
+
+			err := worker.processNextMessage()
+			if tt.expectedErrMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -65,7 +65,7 @@ func TestConversionWorker_ProcessNextMessage(t *testing.T) {
 					return a.ID == 123 && a.Status == "converting"
 				})).Return(nil).Once()
 				mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *entity.Audio) bool {
-					return a.ID == 123 && a.Status == "completed"
+					return a.ID == 123 && a.Status == "completed" && a.CurrentFormat == "wav" && a.SampleRate == 44100
 				})).Return(nil).Once()
 
 				// Mock storage calls
@@ -75,6 +75,12 @@ func TestConversionWorker_ProcessNextMessage(t *testing.T) {
 
 				// Mock converter calls
 				mockConverter.On("ConvertFromReader", mock.Anything, mock.Anything, audio.CurrentFormat, "wav").Return(newMockReadCloser("converted data"), nil)
+				mockConverter.On("Probe", mock.Anything, mock.AnythingOfType("string")).Return(&entity.AudioMetadata{
+					Format:     "wav",
+					SampleRate: 44100,
+					Channels:   1,
+					Codec:      "pcm_s16le",
+				}, nil)
 
 				// Mock queue calls
 				mockQueue.On("Dequeue", mock.Anything).Return(task, nil)
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	"github.com/ardfard/sb-test/pkg/logger"
+)
+
+// QueueWorker takes the tasks of a queue one at a time and hands the ID each
+// carries to a handler. A task is completed when the handler succeeds and
+// failed otherwise.
+type QueueWorker struct {
+	queue    queue.TaskQueue
+	action   string // What the handler does, for error messages
+	handle   func(ctx context.Context, id uint) error
+	stopChan chan struct{}
+}
+
+// NewQueueWorker creates a new QueueWorker. action describes what handle
+// does, e.g. "convert audio".
+func NewQueueWorker(queue queue.TaskQueue, action string, handle func(ctx context.Context, id uint) error) *QueueWorker {
+	return &QueueWorker{
+		queue:    queue,
+		action:   action,
+		handle:   handle,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start starts the worker in a new goroutine.
+func (w *QueueWorker) Start() {
+	go w.run()
+}
+
+// Stop stops the worker.
+func (w *QueueWorker) Stop() {
+	close(w.stopChan)
+}
+
+// run is the main loop for the worker.
+// It continuously processes messages from the queue.
+func (w *QueueWorker) run() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			if err := w.processNextMessage(); err != nil {
+				logger.Errorf("Error processing message: %v", err)
+			}
+		}
+	}
+}
+
+// processNextMessage processes the next message from the queue.
+func (w *QueueWorker) processNextMessage() (err error) {
+	ctx := context.Background()
+
+	task, err := w.queue.Dequeue(ctx)
+	defer func() {
+		if err != nil && task != nil {
+			if err := w.queue.Fail(ctx, task.ID, err.Error()); err != nil {
+				logger.Errorf("Failed to fail task: %v", err)
+			}
+		}
+	}()
+
+	if err != nil {
+		err = fmt.Errorf("failed to dequeue message: %v", err)
+		return
+	}
+
+	if err = w.handle(ctx, task.Payload); err != nil {
+		err = fmt.Errorf("failed to %s: %v", w.action, err)
+		return
+	}
+
+	// Mark message as completed
+	if err := w.queue.Complete(ctx, task.ID); err != nil {
+		return fmt.Errorf("failed to complete message: %v", err)
+	}
+
+	return nil
+}
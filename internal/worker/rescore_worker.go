@@ -0,0 +1,15 @@
+package worker
+
+import (
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	"github.com/ardfard/sb-test/internal/usecase"
+)
+
+// NewRescoreWorker creates a worker that scores the takes of phrases whose
+// reference recording changed.
+func NewRescoreWorker(
+	queue queue.TaskQueue,
+	similarityUseCase *usecase.SimilarityUseCase,
+) *QueueWorker {
+	return NewQueueWorker(queue, "rescore takes", similarityUseCase.Rescore)
+}
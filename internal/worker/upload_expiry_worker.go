@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/ardfard/sb-test/pkg/logger"
+)
+
+// UploadExpiryWorker periodically discards abandoned resumable uploads.
+type UploadExpiryWorker struct {
+	useCase  *usecase.ResumableUploadUseCase
+	interval time.Duration
+	stopChan chan struct{}
+}
+
+// NewUploadExpiryWorker creates a new UploadExpiryWorker that sweeps every interval.
+func NewUploadExpiryWorker(resumableUseCase *usecase.ResumableUploadUseCase, interval time.Duration) *UploadExpiryWorker {
+	return &UploadExpiryWorker{
+		useCase:  resumableUseCase,
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start starts the worker in a new goroutine.
+func (w *UploadExpiryWorker) Start() {
+	go w.run()
+}
+
+// Stop stops the worker.
+func (w *UploadExpiryWorker) Stop() {
+	close(w.stopChan)
+}
+
+func (w *UploadExpiryWorker) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			w.sweep()
+		}
+	}
+}
+
+// sweep discards the uploads that expired since the last run.
+func (w *UploadExpiryWorker) sweep() {
+	expired, err := w.useCase.ExpireStale(context.Background())
+	if expired > 0 {
+		logger.Infof("Discarded %d expired uploads", expired)
+	}
+	if err != nil {
+		logger.Errorf("Failed to expire uploads: %v", err)
+	}
+}
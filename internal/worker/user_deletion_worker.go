@@ -0,0 +1,14 @@
+package worker
+
+import (
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	"github.com/ardfard/sb-test/internal/usecase"
+)
+
+// NewUserDeletionWorker creates a worker that carries out user deletions.
+func NewUserDeletionWorker(
+	queue queue.TaskQueue,
+	deleteUserUseCase *usecase.DeleteUserUseCase,
+) *QueueWorker {
+	return NewQueueWorker(queue, "delete user", deleteUserUseCase.Process)
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ardfard/sb-test/internal/domain/entity"
+	"github.com/ardfard/sb-test/internal/domain/queue"
+	queuemocks "github.com/ardfard/sb-test/internal/infrastructure/queue/mocks"
+	repomocks "github.com/ardfard/sb-test/internal/infrastructure/repository/mocks"
+	storagemocks "github.com/ardfard/sb-test/internal/infrastructure/storage/mocks"
+	"github.com/ardfard/sb-test/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type userDeletionWorkerMocks struct {
+	queue           *queuemocks.MockTaskQueue
+	repo            *repomocks.MockUserDeletionRepository
+	userRepo        *repomocks.MockUserRepository
+	phraseRepo      *repomocks.MockPhraseRepository
+	audioRepo       *repomocks.MockAudioRepository
+	compilationRepo *repomocks.MockCompilationRepository
+	uploadRepo      *repomocks.MockUploadRepository
+}
+
+func TestUserDeletionWorker_ProcessNextMessage(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(userDeletionWorkerMocks)
+		expectedErrMsg string
+	}{
+		{
+			name: "Success",
+			setupMocks: func(m userDeletionWorkerMocks) {
+				m.queue.On("Dequeue", mock.Anything).Return(&queue.Task{ID: "1", Payload: 3}, nil)
+				m.repo.On("GetByID", mock.Anything, uint(3)).Return(&entity.UserDeletion{ID: 3, UserID: 1}, nil)
+				m.repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+				m.phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return(nil, nil)
+				m.audioRepo.On("ListByUserID", mock.Anything, uint(1)).Return(nil, nil)
+				m.compilationRepo.On("ListByUserID", mock.Anything, uint(1)).Return(nil, nil)
+				m.uploadRepo.On("ListByUserID", mock.Anything, uint(1)).Return(nil, nil)
+				m.userRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+				m.queue.On("Complete", mock.Anything, "1").Return(nil)
+			},
+		},
+		{
+			name: "Deletion failure fails the task",
+			setupMocks: func(m userDeletionWorkerMocks) {
+				m.queue.On("Dequeue", mock.Anything).Return(&queue.Task{ID: "1", Payload: 3}, nil)
+				m.repo.On("GetByID", mock.Anything, uint(3)).Return(&entity.UserDeletion{ID: 3, UserID: 1}, nil)
+				m.repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+				m.phraseRepo.On("ListByUserID", mock.Anything, uint(1)).Return(nil, errors.New("database locked"))
+				m.queue.On("Fail", mock.Anything, "1", mock.AnythingOfType("string")).Return(nil)
+			},
+			expectedErrMsg: "failed to delete user",
+		},
+		{
+			name: "Dequeue error",
+			setupMocks: func(m userDeletionWorkerMocks) {
+				m.queue.On("Dequeue", mock.Anything).Return(nil, errors.New("queue closed"))
+			},
+			expectedErrMsg: "failed to dequeue message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := userDeletionWorkerMocks{
+				queue:           queuemocks.NewMockTaskQueue(t),
+				repo:            repomocks.NewMockUserDeletionRepository(t),
+				userRepo:        repomocks.NewMockUserRepository(t),
+				phraseRepo:      repomocks.NewMockPhraseRepository(t),
+				audioRepo:       repomocks.NewMockAudioRepository(t),
+				compilationRepo: repomocks.NewMockCompilationRepository(t),
+				uploadRepo:      repomocks.NewMockUploadRepository(t),
+			}
+			tt.setupMocks(m)
+
+			useCase := usecase.NewDeleteUserUseCase(m.repo, m.userRepo, m.phraseRepo, m.audioRepo, m.compilationRepo, m.uploadRepo,
+				storagemocks.NewMockStorage(t), storagemocks.NewMockStagingArea(t), m.queue, queuemocks.NewMockTaskQueue(t), queuemocks.NewMockTaskQueue(t))
+			worker := NewUserDeletionWorker(m.queue, useCase)
+
+			err := worker.processNextMessage()
+			if tt.expectedErrMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
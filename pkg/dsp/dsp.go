@@ -0,0 +1,62 @@
+// Package dsp holds the signal processing building blocks shared by the
+// audio analyses: the FFT, window functions and the mel scale.
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// FFT transforms x in place. len(x) must be a power of two.
+func FFT(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a, b := x[start+k], x[start+k+size/2]*w
+				x[start+k], x[start+k+size/2] = a+b, a-b
+				w *= step
+			}
+		}
+	}
+}
+
+// Hann returns a periodic Hann window of n samples.
+func Hann(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n))
+	}
+	return w
+}
+
+// Hamming returns a periodic Hamming window of n samples.
+func Hamming(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.54 - 0.46*math.Cos(2*math.Pi*float64(i)/float64(n))
+	}
+	return w
+}
+
+// HzToMel converts a frequency to the mel scale.
+func HzToMel(f float64) float64 {
+	return 2595 * math.Log10(1+f/700)
+}
+
+// MelToHz converts a mel value back to a frequency.
+func MelToHz(m float64) float64 {
+	return 700 * (math.Pow(10, m/2595) - 1)
+}
@@ -0,0 +1,35 @@
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFFT(t *testing.T) {
+	x := make([]complex128, 16)
+	for i := range x {
+		x[i] = complex(math.Sin(float64(i))+0.25*float64(i%3), 0)
+	}
+	expected := make([]complex128, len(x))
+	for k := range expected {
+		for n, v := range x {
+			expected[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(k*n)/float64(len(x))))
+		}
+	}
+
+	FFT(x)
+	for k := range x {
+		assert.InDelta(t, real(expected[k]), real(x[k]), 1e-9)
+		assert.InDelta(t, imag(expected[k]), imag(x[k]), 1e-9)
+	}
+}
+
+func TestMel(t *testing.T) {
+	assert.InDelta(t, 1000, HzToMel(1000), 0.5)
+	for _, f := range []float64{0, 440, 8000} {
+		assert.InDelta(t, f, MelToHz(HzToMel(f)), 1e-9)
+	}
+}
@@ -0,0 +1,281 @@
+// Package pitch tracks the fundamental frequency (F0) of speech with the YIN
+// algorithm and summarizes the resulting contour.
+package pitch
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+const (
+	// analysisRate is the highest sample rate the audio is analyzed at;
+	// higher rates are decimated first, which F0 estimation does not need.
+	analysisRate = 16000
+	// silenceDB is the RMS level in dBFS below which a frame is unvoiced.
+	silenceDB = -60
+)
+
+// Options controls the pitch tracker.
+type Options struct {
+	Hop       time.Duration // Time between estimates
+	MinF0     float64       // Lowest detectable F0 in Hz
+	MaxF0     float64       // Highest detectable F0 in Hz
+	Threshold float64       // YIN aperiodicity threshold below which a frame is voiced, 0 to 1
+}
+
+// Validate reports whether the options are usable.
+func (o Options) Validate() error {
+	if o.Hop < time.Millisecond {
+		return fmt.Errorf("hop must be at least 1ms, got %v", o.Hop)
+	}
+	if o.MinF0 < 20 {
+		return fmt.Errorf("minimum F0 must be at least 20 Hz, got %v", o.MinF0)
+	}
+	if o.MaxF0 <= o.MinF0 || o.MaxF0 > analysisRate/4 {
+		return fmt.Errorf("maximum F0 must be above the minimum and at most %d Hz, got %v", analysisRate/4, o.MaxF0)
+	}
+	if o.Threshold <= 0 || o.Threshold >= 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got %v", o.Threshold)
+	}
+	return nil
+}
+
+// Point is one estimate of the contour. F0 is zero for unvoiced frames.
+type Point struct {
+	Time   float64 `json:"time"` // Seconds from the start to the middle of the analyzed frame
+	F0     float64 `json:"f0"`   // Hz
+	Voiced bool    `json:"voiced"`
+}
+
+// Stats summarizes a contour. The F0 statistics cover voiced frames only and
+// are zero when there are none.
+type Stats struct {
+	MeanF0      float64 `json:"mean_f0"`
+	MinF0       float64 `json:"min_f0"`
+	MaxF0       float64 `json:"max_f0"`
+	VoicedRatio float64 `json:"voiced_ratio"` // Fraction of the frames that are voiced
+	// VoicedSegments counts runs of voiced frames, roughly one per syllable.
+	VoicedSegments int `json:"voiced_segments"`
+	// SegmentRate is VoicedSegments per second, a proxy for speaking rate.
+	SegmentRate float64 `json:"segment_rate"`
+}
+
+// Contour is the F0 track of a recording.
+type Contour struct {
+	Hop      float64 `json:"hop"`      // Seconds between points
+	Duration float64 `json:"duration"` // Seconds
+	Points   []Point `json:"points"`
+	Stats    Stats   `json:"stats"`
+}
+
+// Track reads all samples from r, mixed down to mono, and estimates the F0
+// every Hop.
+func Track(r *wav.Reader, opts Options) (*Contour, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	factor := max(1, r.SampleRate/analysisRate)
+	rate := float64(r.SampleRate) / float64(factor)
+	tauMin := max(2, int(rate/opts.MaxF0))
+	tauMax := int(math.Ceil(rate / opts.MinF0))
+	// The integration window covers the longest detectable period.
+	window := tauMax
+	frameLen := window + tauMax + 1
+	hop := max(1, int(math.Round(opts.Hop.Seconds()*rate)))
+
+	c := &Contour{Hop: float64(hop) / rate, Points: []Point{}}
+	d := &decimator{r: r, factor: factor, buf: make([]float64, 4096*r.Channels)}
+	frame := make([]float64, frameLen)
+	diff := make([]float64, tauMax+1)
+
+	n, err := d.read(frame)
+	for start := 0; n > 0 && err == nil; start += hop {
+		point := Point{Time: (float64(start) + float64(frameLen)/2) / rate}
+		if tau, ok := yin(frame, window, tauMin, tauMax, opts.Threshold, diff); ok {
+			point.F0, point.Voiced = rate/tau, true
+		}
+		c.Points = append(c.Points, point)
+
+		if hop < frameLen {
+			copy(frame, frame[hop:])
+			n, err = d.read(frame[frameLen-hop:])
+		} else {
+			if err = d.skip(hop - frameLen); err == nil {
+				n, err = d.read(frame)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Duration = float64(d.count) / rate
+	c.Stats = summarize(c.Points, c.Duration)
+	return c, nil
+}
+
+// yin returns the period in samples of the frame, with sub-sample precision,
+// and whether the frame is voiced. diff is scratch space of tauMax+1 values.
+func yin(frame []float64, window, tauMin, tauMax int, threshold float64, diff []float64) (float64, bool) {
+	var energy float64
+	for _, v := range frame[:window] {
+		energy += v * v
+	}
+	if rms := math.Sqrt(energy / float64(window)); rms == 0 || 20*math.Log10(rms) < silenceDB {
+		return 0, false
+	}
+
+	// Cumulative mean normalized difference function.
+	diff[0] = 1
+	var sum float64
+	for tau := 1; tau <= tauMax; tau++ {
+		var d float64
+		for j := 0; j < window; j++ {
+			delta := frame[j] - frame[j+tau]
+			d += delta * delta
+		}
+		sum += d
+		if sum == 0 {
+			diff[tau] = 1
+			continue
+		}
+		diff[tau] = d * float64(tau) / sum
+	}
+
+	// The first dip below the threshold, followed down to its minimum.
+	tau := -1
+	for t := tauMin; t <= tauMax; t++ {
+		if diff[t] < threshold {
+			for t+1 <= tauMax && diff[t+1] < diff[t] {
+				t++
+			}
+			tau = t
+			break
+		}
+	}
+	if tau < 0 {
+		return 0, false
+	}
+
+	// Parabolic interpolation around the minimum.
+	if tau > 1 && tau < tauMax {
+		a, b, c := diff[tau-1], diff[tau], diff[tau+1]
+		if denom := a - 2*b + c; denom > 0 {
+			return float64(tau) + (a-c)/(2*denom), true
+		}
+	}
+	return float64(tau), true
+}
+
+func summarize(points []Point, duration float64) Stats {
+	var s Stats
+	voiced := 0
+	previous := false
+	for _, p := range points {
+		if p.Voiced {
+			if voiced == 0 {
+				s.MinF0, s.MaxF0 = p.F0, p.F0
+			}
+			voiced++
+			s.MeanF0 += p.F0
+			s.MinF0 = math.Min(s.MinF0, p.F0)
+			s.MaxF0 = math.Max(s.MaxF0, p.F0)
+			if !previous {
+				s.VoicedSegments++
+			}
+		}
+		previous = p.Voiced
+	}
+	if voiced > 0 {
+		s.MeanF0 /= float64(voiced)
+		s.VoicedRatio = float64(voiced) / float64(len(points))
+	}
+	if duration > 0 {
+		s.SegmentRate = float64(s.VoicedSegments) / duration
+	}
+	return s
+}
+
+// WriteCSV writes the points of the contour as CSV with a header row.
+func (c *Contour) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "f0", "voiced"}); err != nil {
+		return err
+	}
+	for _, p := range c.Points {
+		record := []string{
+			strconv.FormatFloat(p.Time, 'f', 4, 64),
+			strconv.FormatFloat(p.F0, 'f', 2, 64),
+			strconv.FormatBool(p.Voiced),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// decimator reads frames from a wav.Reader mixed down to mono, averaging
+// each group of factor frames into one.
+type decimator struct {
+	r       *wav.Reader
+	factor  int
+	buf     []float64
+	pending []float64
+	eof     bool
+	count   int64 // Values read so far
+}
+
+// read fills dst and returns the number of values read, which is less than
+// len(dst) only at the end of the audio. The rest of dst is zeroed.
+func (d *decimator) read(dst []float64) (int, error) {
+	n := 0
+	frameSize := d.r.Channels * d.factor
+	for n < len(dst) {
+		if len(d.pending) < frameSize {
+			if d.eof {
+				break
+			}
+			// Keep a partial group for the next read.
+			kept := copy(d.buf, d.pending)
+			read, err := d.r.ReadSamples(d.buf[kept : len(d.buf)-len(d.buf)%frameSize])
+			if err == io.EOF {
+				d.eof = true
+			} else if err != nil {
+				return n, fmt.Errorf("failed to read samples: %v", err)
+			}
+			d.pending = d.buf[:kept+read]
+			continue
+		}
+		var sum float64
+		for _, v := range d.pending[:frameSize] {
+			sum += v
+		}
+		dst[n] = sum / float64(frameSize)
+		d.pending = d.pending[frameSize:]
+		n++
+	}
+	d.count += int64(n)
+	clear(dst[n:])
+	return n, nil
+}
+
+// skip discards n values.
+func (d *decimator) skip(n int) error {
+	scratch := make([]float64, min(n, 4096))
+	for n > 0 {
+		read, err := d.read(scratch[:min(n, len(scratch))])
+		if err != nil || read == 0 {
+			return err
+		}
+		n -= read
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package pitch
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// part is a stretch of a harmonic tone at f0 Hz, silent when f0 is zero.
+type part struct {
+	ms int
+	f0 float64
+}
+
+// speech returns a recording of the given parts at sampleRate.
+func speech(t *testing.T, sampleRate, channels int, parts ...part) *wav.Reader {
+	var samples []float64
+	for _, p := range parts {
+		for i := 0; i < p.ms*sampleRate/1000; i++ {
+			phase := 2 * math.Pi * p.f0 * float64(i) / float64(sampleRate)
+			// A few harmonics, so the tone is voice-like rather than a pure sine.
+			v := 0.4*math.Sin(phase) + 0.2*math.Sin(2*phase) + 0.1*math.Sin(3*phase)
+			for c := 0; c < channels; c++ {
+				samples = append(samples, v)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, sampleRate, channels, samples))
+	r, err := wav.NewReader(&buf)
+	require.NoError(t, err)
+	return r
+}
+
+func TestTrack(t *testing.T) {
+	opts := Options{Hop: 10 * time.Millisecond, MinF0: 75, MaxF0: 500, Threshold: 0.15}
+
+	t.Run("steady tone", func(t *testing.T) {
+		contour, err := Track(speech(t, 16000, 1, part{500, 220}), opts)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.01, contour.Hop, 1e-9)
+		assert.InDelta(t, 0.5, contour.Duration, 1e-9)
+		require.NotEmpty(t, contour.Points)
+		for _, p := range contour.Points[:len(contour.Points)-5] {
+			assert.True(t, p.Voiced, "unvoiced at %v", p.Time)
+			assert.InDelta(t, 220, p.F0, 1, "at %v", p.Time)
+		}
+		assert.InDelta(t, 220, contour.Stats.MeanF0, 1)
+	})
+
+	t.Run("decimated stereo", func(t *testing.T) {
+		contour, err := Track(speech(t, 48000, 2, part{300, 150}), opts)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.3, contour.Duration, 1e-9)
+		assert.InDelta(t, 150, contour.Stats.MeanF0, 1)
+	})
+
+	t.Run("syllables", func(t *testing.T) {
+		contour, err := Track(speech(t, 16000, 1, part{200, 0}, part{200, 180}, part{200, 0}, part{200, 260}, part{200, 0}), opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, contour.Stats.VoicedSegments)
+		assert.InDelta(t, 2, contour.Stats.SegmentRate, 1e-9)
+		assert.InDelta(t, 180, contour.Stats.MinF0, 2)
+		assert.InDelta(t, 260, contour.Stats.MaxF0, 2)
+		assert.InDelta(t, 0.4, contour.Stats.VoicedRatio, 0.05)
+	})
+
+	t.Run("silence", func(t *testing.T) {
+		contour, err := Track(speech(t, 16000, 1, part{300, 0}), opts)
+		require.NoError(t, err)
+		for _, p := range contour.Points {
+			assert.False(t, p.Voiced)
+			assert.Zero(t, p.F0)
+		}
+		assert.Equal(t, Stats{}, contour.Stats)
+	})
+
+	t.Run("hop longer than a frame", func(t *testing.T) {
+		long := opts
+		long.Hop = 100 * time.Millisecond
+		contour, err := Track(speech(t, 16000, 1, part{1000, 220}), long)
+		require.NoError(t, err)
+		assert.Len(t, contour.Points, 10)
+		assert.InDelta(t, 220, contour.Stats.MeanF0, 1)
+	})
+}
+
+func TestOptions_Validate(t *testing.T) {
+	valid := Options{Hop: 10 * time.Millisecond, MinF0: 75, MaxF0: 500, Threshold: 0.15}
+	require.NoError(t, valid.Validate())
+
+	for name, modify := range map[string]func(*Options){
+		"no hop":              func(o *Options) { o.Hop = 0 },
+		"minimum F0 too low":  func(o *Options) { o.MinF0 = 10 },
+		"maximum below min":   func(o *Options) { o.MaxF0 = 50 },
+		"maximum F0 too high": func(o *Options) { o.MaxF0 = 8000 },
+		"threshold of one":    func(o *Options) { o.Threshold = 1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := valid
+			modify(&opts)
+			assert.Error(t, opts.Validate())
+		})
+	}
+}
+
+func TestContour_WriteCSV(t *testing.T) {
+	contour := &Contour{Points: []Point{{Time: 0.0125, F0: 219.87654, Voiced: true}, {Time: 0.0225}}}
+	var buf strings.Builder
+	require.NoError(t, contour.WriteCSV(&buf))
+	assert.Equal(t, "time,f0,voiced\n0.0125,219.88,true\n0.0225,0.00,false\n", buf.String())
+}
@@ -0,0 +1,169 @@
+// Package quality measures technical problems in a recording: clipping, a
+// high noise floor, a poor signal-to-noise ratio and takes without speech.
+package quality
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+const (
+	// window is the length of audio whose RMS level is measured.
+	window = 10 * time.Millisecond
+	// FloorDB is reported for digital silence, whose level has no finite value.
+	FloorDB = -120.0
+	// clipLevel is the absolute sample value treated as clipped.
+	clipLevel = 0.999
+)
+
+// Report holds the measurements of a recording. Levels are in dBFS.
+type Report struct {
+	PeakDB       float64 // Highest absolute sample
+	RMSDB        float64 // Level of the whole recording
+	ClippedRatio float64 // Fraction of samples at full scale
+	NoiseFloorDB float64 // Level of the quietest tenth of the windows
+	SNRDB        float64 // Level of the loudest tenth of the windows above the noise floor, in dB
+	SpeechRatio  float64 // Fraction of the windows at or above the speech threshold
+}
+
+// Analyze reads all samples from r. Windows with an RMS level of at least
+// speechThresholdDB count as speech.
+func Analyze(r *wav.Reader, speechThresholdDB float64) (Report, error) {
+	windowFrames := int(int64(r.SampleRate) * int64(window) / int64(time.Second))
+	if windowFrames < 1 {
+		windowFrames = 1
+	}
+
+	var (
+		peak, total, windowSum float64
+		samples, clipped       int64
+		inWindow               int
+		windowLevels           []float64
+	)
+	flush := func() {
+		if inWindow == 0 {
+			return
+		}
+		windowLevels = append(windowLevels, toDB(math.Sqrt(windowSum/float64(inWindow*r.Channels))))
+		windowSum, inWindow = 0, 0
+	}
+
+	buf := make([]float64, 4096*r.Channels)
+	for {
+		n, err := r.ReadSamples(buf)
+		for i := 0; i < n; i += r.Channels {
+			for _, v := range buf[i : i+r.Channels] {
+				a := math.Abs(v)
+				peak = math.Max(peak, a)
+				if a >= clipLevel {
+					clipped++
+				}
+				total += v * v
+				windowSum += v * v
+				samples++
+			}
+			inWindow++
+			if inWindow == windowFrames {
+				flush()
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Report{}, fmt.Errorf("failed to read samples: %v", err)
+		}
+	}
+	flush()
+	if samples == 0 {
+		return Report{PeakDB: FloorDB, RMSDB: FloorDB, NoiseFloorDB: FloorDB}, nil
+	}
+
+	speech := 0
+	for _, level := range windowLevels {
+		if level >= speechThresholdDB {
+			speech++
+		}
+	}
+	slices.Sort(windowLevels)
+	noiseFloor := percentile(windowLevels, 0.1)
+	return Report{
+		PeakDB:       toDB(peak),
+		RMSDB:        toDB(math.Sqrt(total / float64(samples))),
+		ClippedRatio: float64(clipped) / float64(samples),
+		NoiseFloorDB: noiseFloor,
+		SNRDB:        percentile(windowLevels, 0.9) - noiseFloor,
+		SpeechRatio:  float64(speech) / float64(len(windowLevels)),
+	}, nil
+}
+
+// Problems a report can be flagged for.
+const (
+	ProblemClipping   = "clipping"
+	ProblemNoiseFloor = "high_noise_floor"
+	ProblemLowSNR     = "low_snr"
+	ProblemNoSpeech   = "no_speech"
+)
+
+// Thresholds decide which reports need a listener's attention. Zero values
+// disable a check.
+type Thresholds struct {
+	MaxClippedRatio float64 // Fraction of samples, 0 to 1
+	MaxNoiseFloorDB float64 // dBFS, below 0
+	MinSNRDB        float64 // dB
+	MinSpeechRatio  float64 // Fraction of windows, 0 to 1
+}
+
+// Validate reports whether the thresholds are usable.
+func (t Thresholds) Validate() error {
+	if t.MaxClippedRatio < 0 || t.MaxClippedRatio > 1 {
+		return fmt.Errorf("maximum clipped ratio must be between 0 and 1, got %v", t.MaxClippedRatio)
+	}
+	if t.MaxNoiseFloorDB > 0 {
+		return fmt.Errorf("maximum noise floor must not be above 0 dBFS, got %v", t.MaxNoiseFloorDB)
+	}
+	if t.MinSNRDB < 0 {
+		return fmt.Errorf("minimum SNR must not be negative, got %v", t.MinSNRDB)
+	}
+	if t.MinSpeechRatio < 0 || t.MinSpeechRatio > 1 {
+		return fmt.Errorf("minimum speech ratio must be between 0 and 1, got %v", t.MinSpeechRatio)
+	}
+	return nil
+}
+
+// Check returns the problems found in the report, in a fixed order. A take
+// without speech is not also reported for its SNR, which is meaningless then.
+func (t Thresholds) Check(r Report) []string {
+	var problems []string
+	if t.MaxClippedRatio > 0 && r.ClippedRatio > t.MaxClippedRatio {
+		problems = append(problems, ProblemClipping)
+	}
+	if t.MaxNoiseFloorDB < 0 && r.NoiseFloorDB > t.MaxNoiseFloorDB {
+		problems = append(problems, ProblemNoiseFloor)
+	}
+	noSpeech := t.MinSpeechRatio > 0 && r.SpeechRatio < t.MinSpeechRatio
+	if t.MinSNRDB > 0 && r.SNRDB < t.MinSNRDB && !noSpeech {
+		problems = append(problems, ProblemLowSNR)
+	}
+	if noSpeech {
+		problems = append(problems, ProblemNoSpeech)
+	}
+	return problems
+}
+
+func toDB(amplitude float64) float64 {
+	if amplitude <= 0 {
+		return FloorDB
+	}
+	return math.Max(FloorDB, 20*math.Log10(amplitude))
+}
+
+// percentile returns the value at fraction p of the sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	return sorted[int(p*float64(len(sorted)-1))]
+}
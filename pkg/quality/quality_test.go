@@ -0,0 +1,111 @@
+package quality
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segment is ms milliseconds of a square wave at amplitude.
+type segment struct {
+	ms        int
+	amplitude float64
+}
+
+// signal returns a 1 kHz sample rate mono recording of the given segments.
+func signal(t *testing.T, segments ...segment) *wav.Reader {
+	var samples []float64
+	for _, s := range segments {
+		for i := 0; i < s.ms; i++ {
+			v := s.amplitude
+			if i%2 == 1 {
+				v = -v
+			}
+			samples = append(samples, v)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, 1000, 1, samples))
+	r, err := wav.NewReader(&buf)
+	require.NoError(t, err)
+	return r
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []segment
+		expected Report
+	}{
+		{
+			name:     "speech over quiet noise",
+			segments: []segment{{200, 0.001}, {800, 0.5}},
+			expected: Report{PeakDB: -6, RMSDB: -7, NoiseFloorDB: -60, SNRDB: 54, SpeechRatio: 0.8},
+		},
+		{
+			name:     "clipped",
+			segments: []segment{{900, 1}, {100, 0.5}},
+			expected: Report{PeakDB: 0, RMSDB: -0.3, ClippedRatio: 0.9, NoiseFloorDB: -6, SNRDB: 6, SpeechRatio: 1},
+		},
+		{
+			name:     "digital silence",
+			segments: []segment{{1000, 0}},
+			expected: Report{PeakDB: FloorDB, RMSDB: FloorDB, NoiseFloorDB: FloorDB},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Analyze(signal(t, tt.segments...), -40)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected.PeakDB, report.PeakDB, 0.1)
+			assert.InDelta(t, tt.expected.RMSDB, report.RMSDB, 0.1)
+			assert.InDelta(t, tt.expected.ClippedRatio, report.ClippedRatio, 1e-9)
+			assert.InDelta(t, tt.expected.NoiseFloorDB, report.NoiseFloorDB, 0.1)
+			assert.InDelta(t, tt.expected.SNRDB, report.SNRDB, 0.1)
+			assert.InDelta(t, tt.expected.SpeechRatio, report.SpeechRatio, 1e-9)
+		})
+	}
+}
+
+func TestThresholds_Check(t *testing.T) {
+	thresholds := Thresholds{MaxClippedRatio: 0.001, MaxNoiseFloorDB: -50, MinSNRDB: 20, MinSpeechRatio: 0.1}
+
+	tests := []struct {
+		name       string
+		thresholds Thresholds
+		report     Report
+		expected   []string
+	}{
+		{
+			name:       "clean take",
+			thresholds: thresholds,
+			report:     Report{NoiseFloorDB: -70, SNRDB: 50, SpeechRatio: 0.6},
+		},
+		{
+			name:       "clipped and noisy",
+			thresholds: thresholds,
+			report:     Report{ClippedRatio: 0.01, NoiseFloorDB: -35, SNRDB: 15, SpeechRatio: 0.6},
+			expected:   []string{ProblemClipping, ProblemNoiseFloor, ProblemLowSNR},
+		},
+		{
+			name:       "muted microphone",
+			thresholds: thresholds,
+			report:     Report{NoiseFloorDB: FloorDB, SNRDB: 0},
+			expected:   []string{ProblemNoSpeech},
+		},
+		{
+			name:   "zero thresholds disable checks",
+			report: Report{ClippedRatio: 0.5, NoiseFloorDB: -10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.thresholds.Check(tt.report))
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Package silence finds silence in PCM audio, to trim it from the ends of a
+// recording or to split a recording at it.
+package silence
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// window is the length of audio whose energy is compared against the threshold.
+const window = 10 * time.Millisecond
+
+// Options controls what counts as silence and how much of it is kept.
+type Options struct {
+	ThresholdDB float64       // Windows with an RMS level below this many dBFS are silent
+	MinDuration time.Duration // Shorter leading or trailing silence is left alone
+	Padding     time.Duration // Silence kept next to the audible part when trimming
+}
+
+// Validate reports whether the options are usable.
+func (o Options) Validate() error {
+	if o.ThresholdDB >= 0 {
+		return fmt.Errorf("threshold must be below 0 dBFS, got %v", o.ThresholdDB)
+	}
+	if o.MinDuration < 0 {
+		return fmt.Errorf("minimum duration must not be negative, got %v", o.MinDuration)
+	}
+	if o.Padding < 0 {
+		return fmt.Errorf("padding must not be negative, got %v", o.Padding)
+	}
+	return nil
+}
+
+// Range is the part of the audio to keep, in seconds from the start.
+type Range struct {
+	Start float64
+	End   float64
+}
+
+// Detect reads all samples from r and returns the range left after removing
+// leading and trailing silence. Audio that is silent throughout is kept whole.
+func Detect(r *wav.Reader, opts Options) (Range, error) {
+	if err := opts.Validate(); err != nil {
+		return Range{}, err
+	}
+	l, err := scan(r, opts.ThresholdDB)
+	if err != nil {
+		return Range{}, err
+	}
+
+	total := l.seconds(l.frames)
+	first := slices.Index(l.loud, true)
+	if first < 0 {
+		return Range{Start: 0, End: total}, nil
+	}
+	last := len(l.loud) - 1
+	for !l.loud[last] {
+		last--
+	}
+	firstLoud, lastLoud := l.start(first), l.end(last)
+
+	minSilence := opts.MinDuration.Seconds()
+	padding := opts.Padding.Seconds()
+	keep := Range{Start: 0, End: total}
+	if leading := firstLoud; leading >= minSilence && leading > 0 {
+		keep.Start = math.Max(0, leading-padding)
+	}
+	if trailing := total - lastLoud; trailing >= minSilence && trailing > 0 {
+		keep.End = math.Min(total, lastLoud+padding)
+	}
+	return keep, nil
+}
+
+// Split reads all samples from r and returns the audible parts separated by
+// silence of at least MinDuration, in order. Each part is padded by up to
+// Padding, without reaching past the middle of the silence around it. Audio
+// that is silent throughout has no parts.
+func Split(r *wav.Reader, opts Options) ([]Range, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	l, err := scan(r, opts.ThresholdDB)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect runs of audible windows, in frames, merging those separated by
+	// short silence.
+	type run struct{ start, end int64 }
+	var runs []run
+	minSilence := int64(math.Round(opts.MinDuration.Seconds() * float64(l.sampleRate)))
+	for i := 0; i < len(l.loud); i++ {
+		if !l.loud[i] {
+			continue
+		}
+		r := run{start: int64(i) * l.windowFrames}
+		for i < len(l.loud) && l.loud[i] {
+			i++
+		}
+		r.end = min(int64(i)*l.windowFrames, l.frames)
+		if n := len(runs); n > 0 && r.start-runs[n-1].end < minSilence {
+			runs[n-1].end = r.end
+			continue
+		}
+		runs = append(runs, r)
+	}
+	parts := make([]Range, len(runs))
+	for i, r := range runs {
+		parts[i] = Range{Start: l.seconds(r.start), End: l.seconds(r.end)}
+	}
+
+	total := l.seconds(l.frames)
+	padding := opts.Padding.Seconds()
+	padded := make([]Range, len(parts))
+	for i, p := range parts {
+		lower, upper := 0.0, total
+		if i > 0 {
+			lower = (parts[i-1].End + p.Start) / 2
+		}
+		if i < len(parts)-1 {
+			upper = (p.End + parts[i+1].Start) / 2
+		}
+		padded[i] = Range{Start: math.Max(lower, p.Start-padding), End: math.Min(upper, p.End+padding)}
+	}
+	return padded, nil
+}
+
+// levels records which windows of a recording are audible.
+type levels struct {
+	loud         []bool
+	windowFrames int64
+	frames       int64
+	sampleRate   int
+}
+
+func (l levels) seconds(frames int64) float64 {
+	return float64(frames) / float64(l.sampleRate)
+}
+
+// start and end return the bounds of window i in seconds.
+func (l levels) start(i int) float64 {
+	return l.seconds(int64(i) * l.windowFrames)
+}
+
+func (l levels) end(i int) float64 {
+	return l.seconds(min(int64(i+1)*l.windowFrames, l.frames))
+}
+
+// scan reads all samples from r and compares the RMS level of each window
+// against thresholdDB.
+func scan(r *wav.Reader, thresholdDB float64) (levels, error) {
+	windowFrames := int(int64(r.SampleRate) * int64(window) / int64(time.Second))
+	if windowFrames < 1 {
+		windowFrames = 1
+	}
+	threshold := math.Pow(10, thresholdDB/20)
+
+	l := levels{windowFrames: int64(windowFrames), sampleRate: r.SampleRate}
+	var (
+		sumSquares float64
+		inWindow   int
+	)
+	flush := func() {
+		if inWindow == 0 {
+			return
+		}
+		rms := math.Sqrt(sumSquares / float64(inWindow*r.Channels))
+		l.loud = append(l.loud, rms >= threshold)
+		sumSquares, inWindow = 0, 0
+	}
+
+	buf := make([]float64, 4096*r.Channels)
+	for {
+		n, err := r.ReadSamples(buf)
+		for i := 0; i < n; i += r.Channels {
+			for _, v := range buf[i : i+r.Channels] {
+				sumSquares += v * v
+			}
+			l.frames++
+			inWindow++
+			if inWindow == windowFrames {
+				flush()
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return levels{}, fmt.Errorf("failed to read samples: %v", err)
+		}
+	}
+	flush()
+	return l, nil
+}
@@ -0,0 +1,141 @@
+package silence
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segment is ms milliseconds of a square wave at amplitude.
+type segment struct {
+	ms        int
+	amplitude float64
+}
+
+// signal returns a 1 kHz sample rate mono recording of the given segments.
+func signal(t *testing.T, segments ...segment) *wav.Reader {
+	var samples []float64
+	for _, s := range segments {
+		for i := 0; i < s.ms; i++ {
+			v := s.amplitude
+			if i%2 == 1 {
+				v = -v
+			}
+			samples = append(samples, v)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, 1000, 1, samples))
+	r, err := wav.NewReader(&buf)
+	require.NoError(t, err)
+	return r
+}
+
+func TestDetect(t *testing.T) {
+	opts := Options{ThresholdDB: -40, MinDuration: 200 * time.Millisecond, Padding: 50 * time.Millisecond}
+
+	tests := []struct {
+		name     string
+		segments []segment
+		opts     Options
+		expected Range
+	}{
+		{
+			name:     "leading and trailing silence",
+			segments: []segment{{500, 0}, {1000, 0.5}, {300, 0.001}},
+			opts:     opts,
+			expected: Range{Start: 0.45, End: 1.55},
+		},
+		{
+			name:     "short silence is kept",
+			segments: []segment{{100, 0}, {1000, 0.5}, {500, 0}},
+			opts:     opts,
+			expected: Range{Start: 0, End: 1.15},
+		},
+		{
+			name:     "padding is clamped",
+			segments: []segment{{300, 0}, {500, 0.5}, {300, 0}},
+			opts:     Options{ThresholdDB: -40, MinDuration: 100 * time.Millisecond, Padding: time.Second},
+			expected: Range{Start: 0, End: 1.1},
+		},
+		{
+			name:     "all silent",
+			segments: []segment{{800, 0}},
+			opts:     opts,
+			expected: Range{Start: 0, End: 0.8},
+		},
+		{
+			name:     "no silence",
+			segments: []segment{{600, 0.5}},
+			opts:     opts,
+			expected: Range{Start: 0, End: 0.6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Detect(signal(t, tt.segments...), tt.opts)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected.Start, r.Start, 1e-9)
+			assert.InDelta(t, tt.expected.End, r.End, 1e-9)
+		})
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	assert.NoError(t, Options{ThresholdDB: -50}.Validate())
+	assert.Error(t, Options{ThresholdDB: 0}.Validate())
+	assert.Error(t, Options{ThresholdDB: -50, MinDuration: -time.Second}.Validate())
+	assert.Error(t, Options{ThresholdDB: -50, Padding: -time.Second}.Validate())
+}
+
+func TestSplit(t *testing.T) {
+	opts := Options{ThresholdDB: -40, MinDuration: 200 * time.Millisecond, Padding: 50 * time.Millisecond}
+
+	tests := []struct {
+		name     string
+		segments []segment
+		opts     Options
+		expected []Range
+	}{
+		{
+			name:     "three phrases",
+			segments: []segment{{300, 0}, {500, 0.5}, {400, 0}, {200, 0.5}, {300, 0}, {400, 0.5}, {100, 0}},
+			opts:     opts,
+			expected: []Range{{0.25, 0.85}, {1.15, 1.45}, {1.65, 2.15}},
+		},
+		{
+			name:     "short pauses stay inside a phrase",
+			segments: []segment{{500, 0.5}, {100, 0}, {500, 0.5}, {300, 0}, {500, 0.5}},
+			opts:     opts,
+			expected: []Range{{0, 1.15}, {1.35, 1.9}},
+		},
+		{
+			name:     "padding stops halfway into the silence",
+			segments: []segment{{500, 0.5}, {200, 0}, {500, 0.5}},
+			opts:     Options{ThresholdDB: -40, MinDuration: 200 * time.Millisecond, Padding: time.Second},
+			expected: []Range{{0, 0.6}, {0.6, 1.2}},
+		},
+		{
+			name:     "all silent",
+			segments: []segment{{800, 0}},
+			opts:     opts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := Split(signal(t, tt.segments...), tt.opts)
+			require.NoError(t, err)
+			require.Len(t, parts, len(tt.expected))
+			for i, p := range parts {
+				assert.InDelta(t, tt.expected[i].Start, p.Start, 1e-9)
+				assert.InDelta(t, tt.expected[i].End, p.End, 1e-9)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Package similarity compares two recordings of the same phrase. Both are
+// reduced to MFCC features and aligned with dynamic time warping, so takes
+// spoken at a different pace can still score high.
+package similarity
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ardfard/sb-test/pkg/dsp"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+const (
+	frameDuration = 0.025 // Seconds per analysis frame
+	hopDuration   = 0.010 // Seconds between frames
+	preEmphasis   = 0.97
+	melFilters    = 26
+	coefficients  = 12 // Cepstral coefficients kept, excluding c0
+	maxHz         = 8000
+	// Leading and trailing frames more than marginDB below the loudest frame,
+	// or below floorDB, are dropped as silence.
+	marginDB = 40
+	floorDB  = -60
+	// scale maps the mean per-step alignment distance to a score: a distance
+	// of scale scores about 0.37.
+	scale = 10
+)
+
+// ErrNoSpeech is returned when a recording has no frames to compare.
+var ErrNoSpeech = errors.New("recording has no audible frames")
+
+// Compare returns how similar the recordings read from a and b are, from 0
+// to 1, where 1 means identical features. The recordings may differ in
+// sample rate and channel count.
+func Compare(a, b *wav.Reader) (float64, error) {
+	sa, err := readMono(a)
+	if err != nil {
+		return 0, err
+	}
+	sb, err := readMono(b)
+	if err != nil {
+		return 0, err
+	}
+	// Compare the same band of both, limited by the lower sample rate.
+	upper := math.Min(maxHz, float64(min(a.SampleRate, b.SampleRate))/2)
+
+	fa := trim(mfcc(sa, a.SampleRate, upper))
+	fb := trim(mfcc(sb, b.SampleRate, upper))
+	if len(fa) == 0 || len(fb) == 0 {
+		return 0, ErrNoSpeech
+	}
+	return math.Exp(-dtw(normalize(fa), normalize(fb)) / scale), nil
+}
+
+// frame is the features of one analysis frame.
+type frame struct {
+	level  float64 // Mean power in dBFS
+	coeffs []float64
+}
+
+// mfcc returns the features of every frame of samples.
+func mfcc(samples []float64, sampleRate int, upper float64) []frame {
+	size := int(frameDuration * float64(sampleRate))
+	hop := int(hopDuration * float64(sampleRate))
+	fftSize := 1
+	for fftSize < size {
+		fftSize <<= 1
+	}
+	window := dsp.Hamming(size)
+	filters := filterbank(fftSize, sampleRate, upper)
+
+	// Pre-emphasis boosts the high frequencies that carry articulation.
+	emphasized := make([]float64, len(samples))
+	for i, v := range samples {
+		emphasized[i] = v
+		if i > 0 {
+			emphasized[i] -= preEmphasis * samples[i-1]
+		}
+	}
+
+	var frames []frame
+	spectrum := make([]complex128, fftSize)
+	power := make([]float64, fftSize/2+1)
+	energies := make([]float64, melFilters)
+	for start := 0; start+size <= len(emphasized); start += hop {
+		clear(spectrum)
+		var energy float64
+		for i, w := range window {
+			v := emphasized[start+i]
+			energy += v * v
+			spectrum[i] = complex(v*w, 0)
+		}
+		dsp.FFT(spectrum)
+		for k := range power {
+			re, im := real(spectrum[k]), imag(spectrum[k])
+			power[k] = (re*re + im*im) / float64(fftSize)
+		}
+		for m, filter := range filters {
+			var sum float64
+			for k, weight := range filter.weights {
+				sum += weight * power[filter.first+k]
+			}
+			energies[m] = math.Log(math.Max(sum, 1e-10))
+		}
+		frames = append(frames, frame{
+			level:  10 * math.Log10(math.Max(energy/float64(size), 1e-12)),
+			coeffs: dct(energies),
+		})
+	}
+	return frames
+}
+
+// filter is a triangular mel filter over consecutive FFT bins.
+type filter struct {
+	first   int
+	weights []float64
+}
+
+// filterbank returns melFilters triangular filters evenly spaced on the mel
+// scale up to upper Hz.
+func filterbank(fftSize, sampleRate int, upper float64) []filter {
+	top := dsp.HzToMel(upper)
+	edges := make([]float64, melFilters+2)
+	for i := range edges {
+		edges[i] = dsp.MelToHz(top*float64(i)/float64(melFilters+1)) * float64(fftSize) / float64(sampleRate)
+	}
+
+	filters := make([]filter, melFilters)
+	for m := range filters {
+		low, center, high := edges[m], edges[m+1], edges[m+2]
+		first := int(math.Ceil(low))
+		last := min(int(math.Floor(high)), fftSize/2)
+		f := filter{first: first}
+		for k := first; k <= last; k++ {
+			bin := float64(k)
+			weight := (bin - low) / (center - low)
+			if bin > center {
+				weight = (high - bin) / (high - center)
+			}
+			f.weights = append(f.weights, math.Max(0, weight))
+		}
+		filters[m] = f
+	}
+	return filters
+}
+
+// dct returns the coefficients c1 to c12 of the type II discrete cosine
+// transform of the log filter energies.
+func dct(energies []float64) []float64 {
+	n := float64(len(energies))
+	coeffs := make([]float64, coefficients)
+	for c := range coeffs {
+		var sum float64
+		for m, e := range energies {
+			sum += e * math.Cos(math.Pi*float64(c+1)*(float64(m)+0.5)/n)
+		}
+		coeffs[c] = sum * math.Sqrt(2/n)
+	}
+	return coeffs
+}
+
+// trim drops the leading and trailing silent frames.
+func trim(frames []frame) []frame {
+	loudest := math.Inf(-1)
+	for _, f := range frames {
+		loudest = math.Max(loudest, f.level)
+	}
+	threshold := math.Max(loudest-marginDB, floorDB)
+	start, end := 0, len(frames)
+	for start < end && frames[start].level < threshold {
+		start++
+	}
+	for end > start && frames[end-1].level < threshold {
+		end--
+	}
+	return frames[start:end]
+}
+
+// normalize subtracts the mean of each coefficient, which removes the
+// constant coloring of the microphone and room.
+func normalize(frames []frame) [][]float64 {
+	mean := make([]float64, coefficients)
+	for _, f := range frames {
+		for c, v := range f.coeffs {
+			mean[c] += v / float64(len(frames))
+		}
+	}
+	features := make([][]float64, len(frames))
+	for i, f := range frames {
+		features[i] = make([]float64, coefficients)
+		for c, v := range f.coeffs {
+			features[i][c] = v - mean[c]
+		}
+	}
+	return features
+}
+
+// dtw returns the cost of the cheapest alignment of a and b, divided by the
+// length of the path so that longer recordings are not penalized.
+func dtw(a, b [][]float64) float64 {
+	// Only two rows of the cost matrix are kept; steps counts the length of
+	// the cheapest path to each cell.
+	prev, cur := make([]float64, len(b)+1), make([]float64, len(b)+1)
+	prevSteps, curSteps := make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := 1; j <= len(b); j++ {
+		prev[j] = math.Inf(1)
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = math.Inf(1)
+		for j := 1; j <= len(b); j++ {
+			cost, steps := prev[j-1], prevSteps[j-1]
+			if prev[j] < cost {
+				cost, steps = prev[j], prevSteps[j]
+			}
+			if cur[j-1] < cost {
+				cost, steps = cur[j-1], curSteps[j-1]
+			}
+			cur[j], curSteps[j] = cost+distance(a[i-1], b[j-1]), steps+1
+		}
+		prev, cur = cur, prev
+		prevSteps, curSteps = curSteps, prevSteps
+	}
+	return prev[len(b)] / float64(prevSteps[len(b)])
+}
+
+func distance(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// readMono reads all samples from r mixed down to one channel.
+func readMono(r *wav.Reader) ([]float64, error) {
+	var mono []float64
+	buf := make([]float64, 4096*r.Channels)
+	for {
+		n, err := r.ReadSamples(buf)
+		for i := 0; i+r.Channels <= n; i += r.Channels {
+			var sum float64
+			for _, v := range buf[i : i+r.Channels] {
+				sum += v
+			}
+			mono = append(mono, sum/float64(r.Channels))
+		}
+		if err == io.EOF {
+			return mono, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read samples: %v", err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package similarity
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vowel is a stretch of a harmonic tone whose spectrum peaks at formant Hz,
+// silent when f0 is zero.
+type vowel struct {
+	ms      int
+	f0      float64
+	formant float64
+}
+
+// recording returns the vowels spoken in order at sampleRate.
+func recording(t *testing.T, sampleRate, channels int, vowels ...vowel) *wav.Reader {
+	var samples []float64
+	for _, v := range vowels {
+		for i := 0; i < v.ms*sampleRate/1000; i++ {
+			var s float64
+			for h := 1; v.f0 > 0 && float64(h)*v.f0 < float64(sampleRate)/2; h++ {
+				f := float64(h) * v.f0
+				gain := math.Exp(-math.Abs(f-v.formant) / 300)
+				s += 0.1 * gain * math.Sin(2*math.Pi*f*float64(i)/float64(sampleRate))
+			}
+			for c := 0; c < channels; c++ {
+				samples = append(samples, s)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, sampleRate, channels, samples))
+	r, err := wav.NewReader(&buf)
+	require.NoError(t, err)
+	return r
+}
+
+func TestCompare(t *testing.T) {
+	phrase := []vowel{{200, 0, 0}, {250, 120, 700}, {200, 130, 2300}, {250, 110, 1200}, {200, 0, 0}}
+	// The same phrase spoken slower, with longer pauses.
+	slow := []vowel{{400, 0, 0}, {400, 120, 700}, {300, 130, 2300}, {350, 110, 1200}, {100, 0, 0}}
+	other := []vowel{{200, 0, 0}, {250, 110, 1200}, {200, 120, 300}, {250, 130, 2800}, {200, 0, 0}}
+
+	t.Run("identical", func(t *testing.T) {
+		score, err := Compare(recording(t, 16000, 1, phrase...), recording(t, 16000, 1, phrase...))
+		require.NoError(t, err)
+		assert.InDelta(t, 1, score, 1e-9)
+	})
+
+	t.Run("slower take scores above a different phrase", func(t *testing.T) {
+		same, err := Compare(recording(t, 16000, 1, phrase...), recording(t, 16000, 1, slow...))
+		require.NoError(t, err)
+		different, err := Compare(recording(t, 16000, 1, phrase...), recording(t, 16000, 1, other...))
+		require.NoError(t, err)
+		assert.Greater(t, same, different)
+		assert.Greater(t, same, 0.5)
+	})
+
+	t.Run("different sample rate and channels", func(t *testing.T) {
+		score, err := Compare(recording(t, 16000, 1, phrase...), recording(t, 44100, 2, phrase...))
+		require.NoError(t, err)
+		assert.Greater(t, score, 0.8)
+	})
+
+	t.Run("silence", func(t *testing.T) {
+		_, err := Compare(recording(t, 16000, 1, phrase...), recording(t, 16000, 1, vowel{500, 0, 0}))
+		assert.ErrorIs(t, err, ErrNoSpeech)
+	})
+}
+
+func TestDTW(t *testing.T) {
+	a := [][]float64{{0}, {1}, {2}}
+	// b repeats the middle frame, which the alignment absorbs for free.
+	b := [][]float64{{0}, {1}, {1}, {2}}
+	assert.InDelta(t, 0, dtw(a, b), 1e-12)
+	// Shifted by one: the cheapest path pays 1 at both ends over 4 steps.
+	assert.InDelta(t, 0.5, dtw(a, [][]float64{{1}, {2}, {3}}), 1e-12)
+}
@@ -0,0 +1,38 @@
+// Package sniff identifies audio containers from their leading bytes.
+package sniff
+
+import "bytes"
+
+// HeaderSize is the number of leading bytes AudioFormat needs to inspect.
+const HeaderSize = 512
+
+// AudioFormat returns the container format of an audio file given its first
+// bytes, using the same names as the converter's probe ("wav", "mp3", ...),
+// or "" if the header does not match a known audio container.
+func AudioFormat(header []byte) string {
+	switch {
+	case len(header) >= 12 && bytes.Equal(header[0:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return "wav"
+	case len(header) >= 12 && bytes.Equal(header[0:4], []byte("FORM")) &&
+		(bytes.Equal(header[8:12], []byte("AIFF")) || bytes.Equal(header[8:12], []byte("AIFC"))):
+		return "aiff"
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return "flac"
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return "ogg"
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return "webm"
+	case len(header) >= 8 && bytes.Equal(header[4:8], []byte("ftyp")):
+		return "m4a"
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return "mp3"
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0:
+		// MPEG audio frame sync. ADTS AAC shares the sync word but has
+		// layer bits of 00, which MPEG audio reserves.
+		if header[1]&0x06 == 0 {
+			return "aac"
+		}
+		return "mp3"
+	}
+	return ""
+}
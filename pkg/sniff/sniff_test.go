@@ -0,0 +1,44 @@
+package sniff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ardfard/sb-test/pkg/projectpath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudioFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"wav", []byte("RIFF\x24\x08\x00\x00WAVEfmt "), "wav"},
+		{"aiff", []byte("FORM\x00\x00\x00\x00AIFF"), "aiff"},
+		{"flac", []byte("fLaC\x00\x00\x00\x22"), "flac"},
+		{"ogg", []byte("OggS\x00\x02"), "ogg"},
+		{"webm", []byte{0x1A, 0x45, 0xDF, 0xA3, 0x9F}, "webm"},
+		{"m4a", []byte("\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00"), "m4a"},
+		{"mp3 with id3", []byte("ID3\x04\x00\x00"), "mp3"},
+		{"mp3 frame sync", []byte{0xFF, 0xFB, 0x90, 0x64}, "mp3"},
+		{"adts aac", []byte{0xFF, 0xF1, 0x50, 0x80}, "aac"},
+		{"pdf", []byte("%PDF-1.7\n"), ""},
+		{"empty", nil, ""},
+		{"truncated riff", []byte("RIFF"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AudioFormat(tt.header))
+		})
+	}
+}
+
+func TestAudioFormat_Fixture(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(projectpath.RootProject, "tests", "fixtures", "test.m4a"))
+	require.NoError(t, err)
+	assert.Equal(t, "m4a", AudioFormat(data[:HeaderSize]))
+}
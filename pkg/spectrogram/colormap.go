@@ -0,0 +1,43 @@
+package spectrogram
+
+import (
+	"image/color"
+	"sort"
+)
+
+// colorMap is a gradient through evenly spaced colors.
+type colorMap []color.RGBA
+
+// at returns the color at position t, from 0 to 1.
+func (m colorMap) at(t float64) color.RGBA {
+	pos := t * float64(len(m)-1)
+	i := min(int(pos), len(m)-2)
+	frac := pos - float64(i)
+	lerp := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*frac + 0.5)
+	}
+	a, b := m[i], m[i+1]
+	return color.RGBA{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B), A: 255}
+}
+
+var colorMaps = map[string]colorMap{
+	"gray": {{0, 0, 0, 255}, {255, 255, 255, 255}},
+	"viridis": {
+		{68, 1, 84, 255}, {71, 44, 122, 255}, {59, 81, 139, 255}, {44, 113, 142, 255}, {33, 144, 141, 255},
+		{39, 173, 129, 255}, {92, 200, 99, 255}, {170, 220, 50, 255}, {253, 231, 37, 255},
+	},
+	"inferno": {
+		{0, 0, 4, 255}, {31, 12, 72, 255}, {85, 15, 109, 255}, {136, 34, 106, 255}, {186, 54, 85, 255},
+		{227, 89, 51, 255}, {249, 140, 10, 255}, {249, 201, 50, 255}, {252, 255, 164, 255},
+	},
+}
+
+// ColorMaps returns the names of the available color maps, sorted.
+func ColorMaps() []string {
+	names := make([]string, 0, len(colorMaps))
+	for name := range colorMaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
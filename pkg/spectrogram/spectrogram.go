@@ -0,0 +1,230 @@
+// Package spectrogram renders the short-time Fourier transform of PCM audio
+// as an image.
+package spectrogram
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"math/cmplx"
+
+	"github.com/ardfard/sb-test/pkg/dsp"
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+// Frequency scales of the vertical axis.
+const (
+	ScaleLinear = "linear"
+	ScaleMel    = "mel"
+)
+
+// MaxDimension limits the width and height of a rendered image.
+const MaxDimension = 4096
+
+// Options controls the analysis and the rendered image.
+type Options struct {
+	FFTSize  int     // Samples per transform, a power of two from 64 to 16384
+	Hop      int     // Samples between the starts of consecutive transforms
+	Scale    string  // ScaleLinear or ScaleMel
+	MinDB    float64 // Level in dBFS drawn with the lowest color
+	MaxDB    float64 // Level in dBFS drawn with the highest color
+	ColorMap string  // One of ColorMaps()
+	Width    int     // Pixels; time runs left to right
+	Height   int     // Pixels; frequency runs bottom to top
+}
+
+// Validate reports whether the options are usable.
+func (o Options) Validate() error {
+	if o.FFTSize < 64 || o.FFTSize > 16384 || o.FFTSize&(o.FFTSize-1) != 0 {
+		return fmt.Errorf("FFT size must be a power of two from 64 to 16384, got %d", o.FFTSize)
+	}
+	if o.Hop < 1 || o.Hop > o.FFTSize {
+		return fmt.Errorf("hop must be between 1 and the FFT size, got %d", o.Hop)
+	}
+	if o.Scale != ScaleLinear && o.Scale != ScaleMel {
+		return fmt.Errorf("scale must be %q or %q, got %q", ScaleLinear, ScaleMel, o.Scale)
+	}
+	if !isFinite(o.MinDB) || !isFinite(o.MaxDB) {
+		return fmt.Errorf("levels must be finite, got %v and %v", o.MinDB, o.MaxDB)
+	}
+	if o.MinDB >= o.MaxDB {
+		return fmt.Errorf("minimum level must be below the maximum, got %v and %v", o.MinDB, o.MaxDB)
+	}
+	if _, ok := colorMaps[o.ColorMap]; !ok {
+		return fmt.Errorf("unknown color map %q", o.ColorMap)
+	}
+	if o.Width < 1 || o.Width > MaxDimension || o.Height < 1 || o.Height > MaxDimension {
+		return fmt.Errorf("dimensions must be between 1 and %d pixels, got %dx%d", MaxDimension, o.Width, o.Height)
+	}
+	return nil
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// Key identifies the options, for caching rendered images.
+func (o Options) Key() string {
+	return fmt.Sprintf("fft%d-hop%d-%s-db%g_%g-%s-%dx%d", o.FFTSize, o.Hop, o.Scale, o.MinDB, o.MaxDB, o.ColorMap, o.Width, o.Height)
+}
+
+// Render reads all samples from r, mixed down to mono, and draws their
+// spectrogram. Each pixel shows the loudest level among the transforms and
+// frequency bins it covers.
+func Render(r *wav.Reader, opts Options) (*image.RGBA, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	rows := rowBins(opts, r.SampleRate)
+	window := dsp.Hann(opts.FFTSize)
+	var windowSum float64
+	for _, w := range window {
+		windowSum += w
+	}
+
+	// The length of the audio may be unknown, so transforms are collected in
+	// groups of stride consecutive ones. Once there are twice as many groups
+	// as columns, neighbouring groups are merged and the stride doubles.
+	var (
+		groups  [][]float64
+		stride  = 1
+		inGroup = 0
+	)
+	samples := make([]float64, opts.FFTSize)
+	spectrum := make([]complex128, opts.FFTSize)
+	dB := make([]float64, opts.FFTSize/2+1)
+	reader := monoReader{r: r, buf: make([]float64, 4096*r.Channels)}
+
+	// The first transform reads a full window, later ones a hop each.
+	n, err := reader.read(samples)
+	for n > 0 && err == nil {
+		for i, v := range samples {
+			spectrum[i] = complex(v*window[i], 0)
+		}
+		dsp.FFT(spectrum)
+		for k := range dB {
+			dB[k] = 20 * math.Log10(2*cmplx.Abs(spectrum[k])/windowSum)
+		}
+
+		if inGroup == 0 {
+			group := make([]float64, len(rows))
+			for y := range group {
+				group[y] = math.Inf(-1)
+			}
+			groups = append(groups, group)
+		}
+		group := groups[len(groups)-1]
+		for y, bins := range rows {
+			for _, v := range dB[bins[0]:bins[1]] {
+				group[y] = math.Max(group[y], v)
+			}
+		}
+		if inGroup++; inGroup == stride {
+			inGroup = 0
+			if len(groups) == 2*opts.Width {
+				for i := 0; i < opts.Width; i++ {
+					groups[i] = maxLevels(groups[2*i], groups[2*i+1])
+				}
+				groups = groups[:opts.Width]
+				stride *= 2
+			}
+		}
+
+		copy(samples, samples[opts.Hop:])
+		n, err = reader.read(samples[opts.FFTSize-opts.Hop:])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	palette := colorMaps[opts.ColorMap]
+	for x := 0; x < opts.Width; x++ {
+		// Short audio spreads each group over several columns.
+		first := x * len(groups) / opts.Width
+		last := max(first+1, (x+1)*len(groups)/opts.Width)
+		for y := 0; y < opts.Height; y++ {
+			level := math.Inf(-1)
+			for _, group := range groups[min(first, len(groups)):min(last, len(groups))] {
+				level = math.Max(level, group[y])
+			}
+			t := (level - opts.MinDB) / (opts.MaxDB - opts.MinDB)
+			img.Set(x, y, palette.at(math.Max(0, math.Min(1, t))))
+		}
+	}
+	return img, nil
+}
+
+func maxLevels(a, b []float64) []float64 {
+	for i := range a {
+		a[i] = math.Max(a[i], b[i])
+	}
+	return a
+}
+
+// rowBins returns, for each row from the top, the half-open range of FFT
+// bins it covers.
+func rowBins(opts Options, sampleRate int) [][2]int {
+	bins := opts.FFTSize/2 + 1
+	binHz := float64(sampleRate) / float64(opts.FFTSize)
+	nyquist := float64(sampleRate) / 2
+
+	toScale, fromScale := func(f float64) float64 { return f }, func(v float64) float64 { return v }
+	if opts.Scale == ScaleMel {
+		toScale, fromScale = dsp.HzToMel, dsp.MelToHz
+	}
+	top := toScale(nyquist)
+
+	rows := make([][2]int, opts.Height)
+	for y := range rows {
+		// Row y covers the band between its lower and upper edge on the scale.
+		low := fromScale(top * float64(opts.Height-y-1) / float64(opts.Height))
+		high := fromScale(top * float64(opts.Height-y) / float64(opts.Height))
+		first := int(math.Round(low / binHz))
+		last := int(math.Round(high / binHz))
+		first = min(first, bins-1)
+		last = max(min(last, bins-1), first)
+		rows[y] = [2]int{first, last + 1}
+	}
+	return rows
+}
+
+// monoReader reads frames from a wav.Reader mixed down to one channel.
+type monoReader struct {
+	r       *wav.Reader
+	buf     []float64
+	pending []float64
+	eof     bool
+}
+
+// read fills dst and returns the number of frames read, which is less than
+// len(dst) only at the end of the audio.
+func (m *monoReader) read(dst []float64) (int, error) {
+	n := 0
+	for n < len(dst) {
+		if len(m.pending) == 0 {
+			if m.eof {
+				break
+			}
+			read, err := m.r.ReadSamples(m.buf)
+			if err == io.EOF {
+				m.eof = true
+			} else if err != nil {
+				return n, fmt.Errorf("failed to read samples: %v", err)
+			}
+			m.pending = m.buf[:read]
+			continue
+		}
+		var sum float64
+		for _, v := range m.pending[:m.r.Channels] {
+			sum += v
+		}
+		dst[n] = sum / float64(m.r.Channels)
+		m.pending = m.pending[m.r.Channels:]
+		n++
+	}
+	clear(dst[n:])
+	return n, nil
+}
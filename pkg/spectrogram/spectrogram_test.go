@@ -0,0 +1,115 @@
+package spectrogram
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"testing"
+
+	"github.com/ardfard/sb-test/pkg/dsp"
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tone returns seconds of a sine wave at freq Hz, at an 8 kHz sample rate.
+func tone(t *testing.T, freq, seconds float64, channels int) *wav.Reader {
+	const sampleRate = 8000
+	frames := int(seconds * sampleRate)
+	samples := make([]float64, 0, frames*channels)
+	for i := 0; i < frames; i++ {
+		v := 0.5 * math.Sin(2*math.Pi*freq*float64(i)/sampleRate)
+		for c := 0; c < channels; c++ {
+			samples = append(samples, v)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, sampleRate, channels, samples))
+	r, err := wav.NewReader(&buf)
+	require.NoError(t, err)
+	return r
+}
+
+// brightestRow returns the row of column x with the highest luminance.
+func brightestRow(img *image.RGBA, x int) int {
+	best, bestY := -1, 0
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		if v := int(img.RGBAAt(x, y).R); v > best {
+			best, bestY = v, y
+		}
+	}
+	return bestY
+}
+
+func TestRender(t *testing.T) {
+	opts := Options{FFTSize: 512, Hop: 128, Scale: ScaleLinear, MinDB: -90, MaxDB: 0, ColorMap: "gray", Width: 40, Height: 100}
+
+	t.Run("linear scale", func(t *testing.T) {
+		img, err := Render(tone(t, 2000, 1, 1), opts)
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 40, 100), img.Bounds())
+		// 2 kHz is half of the 4 kHz Nyquist frequency.
+		assert.InDelta(t, 50, brightestRow(img, 20), 1)
+	})
+
+	t.Run("mel scale", func(t *testing.T) {
+		mel := opts
+		mel.Scale = ScaleMel
+		img, err := Render(tone(t, 1000, 1, 2), mel)
+		require.NoError(t, err)
+		expected := 100 * (1 - dsp.HzToMel(1000)/dsp.HzToMel(4000))
+		assert.InDelta(t, expected, brightestRow(img, 20), 1)
+	})
+
+	t.Run("long audio is decimated", func(t *testing.T) {
+		narrow := opts
+		narrow.Width = 3
+		img, err := Render(tone(t, 2000, 5, 1), narrow)
+		require.NoError(t, err)
+		for x := 0; x < 3; x++ {
+			assert.InDelta(t, 50, brightestRow(img, x), 1)
+		}
+	})
+
+	t.Run("short audio is stretched", func(t *testing.T) {
+		img, err := Render(tone(t, 2000, 0.1, 1), opts)
+		require.NoError(t, err)
+		assert.InDelta(t, 50, brightestRow(img, 39), 1)
+	})
+
+	t.Run("silence uses the lowest color", func(t *testing.T) {
+		img, err := Render(tone(t, 0, 0.5, 1), opts)
+		require.NoError(t, err)
+		assert.Equal(t, uint8(0), img.RGBAAt(20, 50).R)
+	})
+}
+
+func TestOptions_Validate(t *testing.T) {
+	valid := Options{FFTSize: 1024, Hop: 256, Scale: ScaleMel, MinDB: -100, MaxDB: 0, ColorMap: "viridis", Width: 800, Height: 256}
+	require.NoError(t, valid.Validate())
+
+	for name, modify := range map[string]func(*Options){
+		"fft size not a power of two": func(o *Options) { o.FFTSize = 1000 },
+		"hop above fft size":          func(o *Options) { o.Hop = 2048 },
+		"unknown scale":               func(o *Options) { o.Scale = "log" },
+		"empty level range":           func(o *Options) { o.MinDB = 0 },
+		"minimum level not a number":  func(o *Options) { o.MinDB = math.NaN() },
+		"maximum level not a number":  func(o *Options) { o.MaxDB = math.NaN() },
+		"infinite minimum level":      func(o *Options) { o.MinDB = math.Inf(-1) },
+		"infinite maximum level":      func(o *Options) { o.MaxDB = math.Inf(1) },
+		"unknown color map":           func(o *Options) { o.ColorMap = "jet" },
+		"too wide":                    func(o *Options) { o.Width = MaxDimension + 1 },
+		"no height":                   func(o *Options) { o.Height = 0 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := valid
+			modify(&opts)
+			assert.Error(t, opts.Validate())
+		})
+	}
+}
+
+func TestOptions_Key(t *testing.T) {
+	opts := Options{FFTSize: 1024, Hop: 256, Scale: ScaleMel, MinDB: -100, MaxDB: 0, ColorMap: "viridis", Width: 800, Height: 256}
+	assert.Equal(t, "fft1024-hop256-mel-db-100_0-viridis-800x256", opts.Key())
+}
@@ -0,0 +1,93 @@
+// Package textgrid writes Praat TextGrid files.
+package textgrid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Interval is a labelled part of the audio, in seconds from the start.
+type Interval struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+// Tier is a named layer of intervals, sorted by start and not overlapping.
+type Tier struct {
+	Name      string
+	Intervals []Interval
+}
+
+// Write writes a TextGrid spanning 0 to duration seconds in Praat's long text
+// format. Each tier becomes an interval tier whose gaps are filled with empty
+// intervals, as Praat requires intervals to cover the whole tier.
+func Write(w io.Writer, duration float64, tiers []Tier) error {
+	if duration <= 0 {
+		return fmt.Errorf("duration must be positive, got %v", duration)
+	}
+	filled := make([][]Interval, len(tiers))
+	for i, tier := range tiers {
+		intervals, err := fill(tier.Intervals, duration)
+		if err != nil {
+			return fmt.Errorf("tier %q: %w", tier.Name, err)
+		}
+		filled[i] = intervals
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "File type = \"ooTextFile\"\nObject class = \"TextGrid\"\n\n")
+	fmt.Fprintf(b, "xmin = 0\nxmax = %s\n", number(duration))
+	if len(tiers) == 0 {
+		fmt.Fprintf(b, "tiers? <absent>\n")
+		return b.Flush()
+	}
+	fmt.Fprintf(b, "tiers? <exists>\nsize = %d\nitem []:\n", len(tiers))
+	for i, tier := range tiers {
+		fmt.Fprintf(b, "    item [%d]:\n", i+1)
+		fmt.Fprintf(b, "        class = \"IntervalTier\"\n")
+		fmt.Fprintf(b, "        name = %s\n", quote(tier.Name))
+		fmt.Fprintf(b, "        xmin = 0\n        xmax = %s\n", number(duration))
+		fmt.Fprintf(b, "        intervals: size = %d\n", len(filled[i]))
+		for j, interval := range filled[i] {
+			fmt.Fprintf(b, "        intervals [%d]:\n", j+1)
+			fmt.Fprintf(b, "            xmin = %s\n", number(interval.Start))
+			fmt.Fprintf(b, "            xmax = %s\n", number(interval.End))
+			fmt.Fprintf(b, "            text = %s\n", quote(interval.Text))
+		}
+	}
+	return b.Flush()
+}
+
+// fill returns intervals with empty intervals inserted before, between and
+// after them so that they cover 0 to duration.
+func fill(intervals []Interval, duration float64) ([]Interval, error) {
+	filled := make([]Interval, 0, 2*len(intervals)+1)
+	at := 0.0
+	for _, interval := range intervals {
+		if interval.Start < at || interval.End <= interval.Start || interval.End > duration {
+			return nil, fmt.Errorf("interval %v to %v is out of order, empty or beyond %v", interval.Start, interval.End, duration)
+		}
+		if interval.Start > at {
+			filled = append(filled, Interval{Start: at, End: interval.Start})
+		}
+		filled = append(filled, interval)
+		at = interval.End
+	}
+	if at < duration {
+		filled = append(filled, Interval{Start: at, End: duration})
+	}
+	return filled, nil
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// quote quotes s the way Praat does, doubling the quotes in it.
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
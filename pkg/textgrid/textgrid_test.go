@@ -0,0 +1,79 @@
+package textgrid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, 2.5, []Tier{
+		{Name: "words", Intervals: []Interval{{Start: 0.25, End: 1, Text: `say "hi"`}, {Start: 1, End: 2.5, Text: "there"}}},
+		{Name: "noise"},
+	}))
+
+	assert.Equal(t, `File type = "ooTextFile"
+Object class = "TextGrid"
+
+xmin = 0
+xmax = 2.5
+tiers? <exists>
+size = 2
+item []:
+    item [1]:
+        class = "IntervalTier"
+        name = "words"
+        xmin = 0
+        xmax = 2.5
+        intervals: size = 3
+        intervals [1]:
+            xmin = 0
+            xmax = 0.25
+            text = ""
+        intervals [2]:
+            xmin = 0.25
+            xmax = 1
+            text = "say ""hi"""
+        intervals [3]:
+            xmin = 1
+            xmax = 2.5
+            text = "there"
+    item [2]:
+        class = "IntervalTier"
+        name = "noise"
+        xmin = 0
+        xmax = 2.5
+        intervals: size = 1
+        intervals [1]:
+            xmin = 0
+            xmax = 2.5
+            text = ""
+`, buf.String())
+}
+
+func TestWrite_NoTiers(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, 1, nil))
+	assert.Contains(t, buf.String(), "tiers? <absent>\n")
+}
+
+func TestWrite_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		duration  float64
+		intervals []Interval
+	}{
+		{"no duration", 0, nil},
+		{"overlap", 2, []Interval{{Start: 0, End: 1}, {Start: 0.5, End: 1.5}}},
+		{"empty interval", 2, []Interval{{Start: 1, End: 1}}},
+		{"beyond duration", 2, []Interval{{Start: 1, End: 2.1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, Write(&bytes.Buffer{}, tt.duration, []Tier{{Name: "words", Intervals: tt.intervals}}))
+		})
+	}
+}
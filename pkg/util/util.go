@@ -16,6 +16,22 @@ func CreateTemporaryFiles(originalFormat, targetFormat string) (string, string,
 	return inputPath, outputPath, nil
 }
 
+// WriteTempFile copies content into a new temporary file with the given
+// extension and returns its path. The caller is responsible for removing it.
+func WriteTempFile(content io.Reader, format string) (string, error) {
+	file, err := os.CreateTemp("", fmt.Sprintf("audio_%s_*.%s", ulid.Make(), format))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, content); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write temp file: %v", err)
+	}
+	return file.Name(), nil
+}
+
 type CleanupReadCloser struct {
 	io.ReadCloser
 	Cleanup func() error
@@ -52,6 +52,19 @@ func TestCreateTemporaryFiles(t *testing.T) {
 	}
 }
 
+func TestWriteTempFile(t *testing.T) {
+	path, err := WriteTempFile(strings.NewReader("test content"), "m4a")
+	assert.NoError(t, err)
+	defer os.Remove(path)
+
+	assert.Contains(t, path, os.TempDir())
+	assert.Equal(t, ".m4a", path[len(path)-4:])
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "test content", string(data))
+}
+
 func TestCleanupReadCloser(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -0,0 +1,567 @@
+// Package wav reads and writes RIFF/WAVE files holding PCM or IEEE float samples.
+package wav
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	formatPCM        = 0x0001
+	formatFloat      = 0x0003
+	formatExtensible = 0xFFFE
+
+	// unknownSize is written by encoders that stream without seeking back.
+	unknownSize = 0xFFFFFFFF
+)
+
+// ErrInvalid is returned for input that is not a supported WAV file.
+var ErrInvalid = errors.New("invalid wav")
+
+// Format describes the sample layout of a WAV file.
+type Format struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int
+	Float         bool
+}
+
+// Reader decodes samples from a WAV stream.
+type Reader struct {
+	Format
+	r         *bufio.Reader
+	remaining int64 // Bytes left in the data chunk, or -1 if unknown
+	frameSize int
+}
+
+// NewReader parses the WAV header from r and positions it at the first sample.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+
+	var riff [12]byte
+	if _, err := io.ReadFull(br, riff[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("%w: missing RIFF/WAVE header", ErrInvalid)
+	}
+
+	wr := &Reader{r: br}
+	haveFormat := false
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(br, header[:]); err != nil {
+			return nil, fmt.Errorf("%w: no data chunk: %v", ErrInvalid, err)
+		}
+		id := string(header[0:4])
+		size := binary.LittleEndian.Uint32(header[4:8])
+
+		switch id {
+		case "fmt ":
+			if err := wr.readFormat(size); err != nil {
+				return nil, err
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, fmt.Errorf("%w: data chunk before fmt chunk", ErrInvalid)
+			}
+			wr.remaining = int64(size)
+			if size == unknownSize {
+				wr.remaining = -1
+			}
+			return wr, nil
+		default:
+			// Chunks are padded to an even size.
+			if _, err := br.Discard(int(size + size%2)); err != nil {
+				return nil, fmt.Errorf("%w: truncated %q chunk: %v", ErrInvalid, id, err)
+			}
+		}
+	}
+}
+
+func (wr *Reader) readFormat(size uint32) error {
+	if size < 16 {
+		return fmt.Errorf("%w: fmt chunk too small", ErrInvalid)
+	}
+	buf := make([]byte, size+size%2)
+	if _, err := io.ReadFull(wr.r, buf); err != nil {
+		return fmt.Errorf("%w: truncated fmt chunk: %v", ErrInvalid, err)
+	}
+
+	tag := binary.LittleEndian.Uint16(buf[0:2])
+	wr.Channels = int(binary.LittleEndian.Uint16(buf[2:4]))
+	wr.SampleRate = int(binary.LittleEndian.Uint32(buf[4:8]))
+	wr.BitsPerSample = int(binary.LittleEndian.Uint16(buf[14:16]))
+	if tag == formatExtensible {
+		if size < 40 {
+			return fmt.Errorf("%w: extensible fmt chunk too small", ErrInvalid)
+		}
+		// The sub-format GUID starts with the actual format tag.
+		tag = binary.LittleEndian.Uint16(buf[24:26])
+	}
+
+	switch {
+	case tag == formatPCM && (wr.BitsPerSample == 8 || wr.BitsPerSample == 16 || wr.BitsPerSample == 24 || wr.BitsPerSample == 32):
+	case tag == formatFloat && (wr.BitsPerSample == 32 || wr.BitsPerSample == 64):
+		wr.Float = true
+	default:
+		return fmt.Errorf("%w: unsupported format tag %#x with %d bits", ErrInvalid, tag, wr.BitsPerSample)
+	}
+	if wr.Channels < 1 || wr.SampleRate < 1 {
+		return fmt.Errorf("%w: %d channels at %d Hz", ErrInvalid, wr.Channels, wr.SampleRate)
+	}
+	wr.frameSize = wr.Channels * wr.BitsPerSample / 8
+	return nil
+}
+
+// Frames returns the number of frames in the data chunk, or -1 if the
+// header does not record it.
+func (wr *Reader) Frames() int64 {
+	if wr.remaining < 0 {
+		return -1
+	}
+	return wr.remaining / int64(wr.frameSize)
+}
+
+// Skip discards the next frames frames.
+func (wr *Reader) Skip(frames int64) error {
+	n := frames * int64(wr.frameSize)
+	if wr.remaining >= 0 && n > wr.remaining {
+		n = wr.remaining
+	}
+	skipped, err := io.CopyN(io.Discard, wr.r, n)
+	if wr.remaining >= 0 {
+		wr.remaining -= skipped
+	}
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to skip samples: %v", err)
+	}
+	return nil
+}
+
+// ReadSamples reads interleaved samples scaled to [-1, 1] into buf, whose
+// length must be a multiple of Channels. It returns the number of samples
+// read, which is always a whole number of frames, and io.EOF at the end.
+func (wr *Reader) ReadSamples(buf []float64) (int, error) {
+	bytesPerSample := wr.BitsPerSample / 8
+	frames := len(buf) / wr.Channels
+	want := int64(frames * wr.frameSize)
+	if wr.remaining >= 0 && want > wr.remaining {
+		want = wr.remaining - wr.remaining%int64(wr.frameSize)
+	}
+	if want == 0 {
+		return 0, io.EOF
+	}
+
+	raw := make([]byte, want)
+	n, err := io.ReadFull(wr.r, raw)
+	n -= n % wr.frameSize
+	if wr.remaining >= 0 {
+		wr.remaining -= int64(n)
+	}
+	if n == 0 {
+		if err == nil || err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return 0, err
+	}
+
+	count := n / bytesPerSample
+	for i := 0; i < count; i++ {
+		buf[i] = wr.decode(raw[i*bytesPerSample:])
+	}
+	return count, nil
+}
+
+func (wr *Reader) decode(b []byte) float64 {
+	switch {
+	case wr.Float && wr.BitsPerSample == 32:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	case wr.Float:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case wr.BitsPerSample == 8:
+		return (float64(b[0]) - 128) / 128
+	case wr.BitsPerSample == 16:
+		return float64(int16(binary.LittleEndian.Uint16(b))) / 32768
+	case wr.BitsPerSample == 24:
+		v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+		return float64(v) / 8388608
+	default:
+		return float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648
+	}
+}
+
+// Buffer holds a fully decoded WAV file.
+type Buffer struct {
+	Format
+	Samples []float64 // Interleaved, scaled to [-1, 1]
+}
+
+// Frames returns the number of frames in the buffer.
+func (b *Buffer) Frames() int {
+	return len(b.Samples) / b.Channels
+}
+
+// Duration returns the length of the buffer in seconds.
+func (b *Buffer) Duration() float64 {
+	return float64(b.Frames()) / float64(b.SampleRate)
+}
+
+// Slice returns the part of the buffer between start and end seconds,
+// clamped to its bounds. The samples are shared with b.
+func (b *Buffer) Slice(start, end float64) *Buffer {
+	frames := b.Frames()
+	clamp := func(seconds float64) int {
+		f := int(math.Round(seconds * float64(b.SampleRate)))
+		return min(max(f, 0), frames)
+	}
+	from, to := clamp(start), clamp(end)
+	if to < from {
+		to = from
+	}
+	return &Buffer{Format: b.Format, Samples: b.Samples[from*b.Channels : to*b.Channels]}
+}
+
+// Mono returns the buffer's samples averaged across channels.
+func (b *Buffer) Mono() []float64 {
+	if b.Channels == 1 {
+		return b.Samples
+	}
+	mono := make([]float64, b.Frames())
+	for i := range mono {
+		var sum float64
+		for c := 0; c < b.Channels; c++ {
+			sum += b.Samples[i*b.Channels+c]
+		}
+		mono[i] = sum / float64(b.Channels)
+	}
+	return mono
+}
+
+// Remix returns the buffer with the given number of channels. A buffer with
+// a different channel count is mixed down to mono and copied to every
+// output channel.
+func (b *Buffer) Remix(channels int) *Buffer {
+	if b.Channels == channels {
+		return b
+	}
+	mono := b.Mono()
+	out := &Buffer{Format: b.Format, Samples: make([]float64, len(mono)*channels)}
+	out.Channels = channels
+	for i, v := range mono {
+		for c := 0; c < channels; c++ {
+			out.Samples[i*channels+c] = v
+		}
+	}
+	return out
+}
+
+// Decode reads a whole WAV file into memory.
+func Decode(r io.Reader) (*Buffer, error) {
+	wr, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &Buffer{Format: wr.Format}
+	if frames := wr.Frames(); frames > 0 {
+		buf.Samples = make([]float64, 0, frames*int64(wr.Channels))
+	}
+	chunk := make([]float64, 4096*wr.Channels)
+	for {
+		n, err := wr.ReadSamples(chunk)
+		buf.Samples = append(buf.Samples, chunk[:n]...)
+		if err == io.EOF {
+			return buf, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read samples: %v", err)
+		}
+	}
+}
+
+// DecodeRange reads the frames between start and end seconds of a WAV file
+// into memory without decoding the rest. The range is clamped to the file.
+func DecodeRange(r io.Reader, start, end float64) (*Buffer, error) {
+	wr, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	from := int64(math.Round(start * float64(wr.SampleRate)))
+	to := int64(math.Round(end * float64(wr.SampleRate)))
+	if from < 0 {
+		from = 0
+	}
+	if err := wr.Skip(from); err != nil {
+		return nil, err
+	}
+
+	buf := &Buffer{Format: wr.Format}
+	chunk := make([]float64, 4096*wr.Channels)
+	for frames := to - from; frames > 0; {
+		want := int64(len(chunk) / wr.Channels)
+		if want > frames {
+			want = frames
+		}
+		n, err := wr.ReadSamples(chunk[:want*int64(wr.Channels)])
+		buf.Samples = append(buf.Samples, chunk[:n]...)
+		frames -= int64(n / wr.Channels)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read samples: %v", err)
+		}
+	}
+	return buf, nil
+}
+
+// Encode writes samples as a 16-bit PCM WAV file. Samples outside [-1, 1]
+// are clipped.
+func Encode(w io.Writer, sampleRate, channels int, samples []float64) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(header(sampleRate, channels, len(samples)*2)); err != nil {
+		return fmt.Errorf("failed to write wav header: %v", err)
+	}
+	if err := writeSamples(bw, samples); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// header returns the 44 byte header of a 16-bit PCM WAV file with dataSize
+// bytes of samples.
+func header(sampleRate, channels, dataSize int) []byte {
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(36+dataSize))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], formatPCM)
+	binary.LittleEndian.PutUint16(header[22:24], uint16(channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(sampleRate*channels*2))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(channels*2))
+	binary.LittleEndian.PutUint16(header[34:36], 16)
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(dataSize))
+	return header
+}
+
+func writeSamples(bw *bufio.Writer, samples []float64) error {
+	var sample [2]byte
+	for _, v := range samples {
+		binary.LittleEndian.PutUint16(sample[:], uint16(ToInt16(v)))
+		if _, err := bw.Write(sample[:]); err != nil {
+			return fmt.Errorf("failed to write wav samples: %v", err)
+		}
+	}
+	return nil
+}
+
+// Writer encodes samples as a 16-bit PCM WAV file as they come, so that the
+// file never has to be held in memory. The sizes in the header are filled in
+// when the writer is closed.
+type Writer struct {
+	Format
+	w      io.WriteSeeker
+	bw     *bufio.Writer
+	frames int64
+}
+
+// NewWriter writes the header of a WAV file to w.
+func NewWriter(w io.WriteSeeker, sampleRate, channels int) (*Writer, error) {
+	ww := &Writer{
+		Format: Format{SampleRate: sampleRate, Channels: channels, BitsPerSample: 16},
+		w:      w,
+		bw:     bufio.NewWriter(w),
+	}
+	if _, err := ww.bw.Write(header(sampleRate, channels, 0)); err != nil {
+		return nil, fmt.Errorf("failed to write wav header: %v", err)
+	}
+	return ww, nil
+}
+
+// WriteSamples appends interleaved samples, whose length must be a multiple
+// of Channels. Samples outside [-1, 1] are clipped.
+func (ww *Writer) WriteSamples(samples []float64) error {
+	if err := writeSamples(ww.bw, samples); err != nil {
+		return err
+	}
+	ww.frames += int64(len(samples) / ww.Channels)
+	return nil
+}
+
+// Frames returns the number of frames written so far.
+func (ww *Writer) Frames() int64 {
+	return ww.frames
+}
+
+// Close completes the header. It leaves the underlying writer at the end of
+// the file and does not close it.
+func (ww *Writer) Close() error {
+	if err := ww.bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write wav samples: %v", err)
+	}
+	dataSize := ww.frames * int64(ww.Channels) * 2
+	if 36+dataSize > math.MaxUint32 {
+		return fmt.Errorf("%d bytes of samples do not fit in a wav file", dataSize)
+	}
+	if _, err := ww.w.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to wav header: %v", err)
+	}
+	if _, err := ww.w.Write(header(ww.SampleRate, ww.Channels, int(dataSize))); err != nil {
+		return fmt.Errorf("failed to write wav header: %v", err)
+	}
+	if _, err := ww.w.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("failed to seek to end of wav file: %v", err)
+	}
+	return nil
+}
+
+// Joiner streams clips one after another into a Writer, like Concat: they
+// are separated by gap seconds of silence or, instead, overlapped by a linear
+// crossfade of crossfade seconds. Only the crossfade is held in memory.
+type Joiner struct {
+	w          *Writer
+	gapFrames  int
+	fadeFrames int
+	clips      int
+	frames     int64     // Frames joined so far, held ones included
+	held       []float64 // End of the last clip, kept back for the crossfade
+}
+
+// NewJoiner returns a Joiner writing to w. The gap is ignored when there is
+// a crossfade.
+func NewJoiner(w *Writer, gap, crossfade float64) *Joiner {
+	j := &Joiner{w: w}
+	if crossfade > 0 {
+		j.fadeFrames = int(math.Round(crossfade * float64(w.SampleRate)))
+	} else {
+		j.gapFrames = int(math.Round(gap * float64(w.SampleRate)))
+	}
+	return j
+}
+
+// Append reads a clip to its end and joins it. The clip must have the sample
+// rate of the output; a different channel count is remixed as Buffer.Remix
+// does. Append returns the frame the clip starts at and its length in frames.
+func (j *Joiner) Append(r *Reader) (start, frames int64, err error) {
+	if r.SampleRate != j.w.SampleRate {
+		return 0, 0, fmt.Errorf("cannot join %d Hz audio to %d Hz audio", r.SampleRate, j.w.SampleRate)
+	}
+	channels := j.w.Channels
+	remix := func(samples []float64) []float64 {
+		return (&Buffer{Format: r.Format, Samples: samples}).Remix(channels).Samples
+	}
+
+	// Read as much of the clip as the crossfade may overlap.
+	chunk := make([]float64, 4096*r.Channels)
+	var head []float64
+	done := false
+	for !done && len(head) < j.fadeFrames*channels {
+		want := min(len(chunk)/r.Channels, j.fadeFrames-len(head)/channels)
+		n, err := r.ReadSamples(chunk[:want*r.Channels])
+		head = append(head, remix(chunk[:n])...)
+		if err == io.EOF {
+			done = true
+		} else if err != nil {
+			return 0, 0, fmt.Errorf("failed to read samples: %v", err)
+		}
+	}
+
+	heldFrames := len(j.held) / channels
+	overlap := min(heldFrames, len(head)/channels)
+	start = j.frames - int64(overlap)
+	if j.clips > 0 {
+		start += int64(j.gapFrames)
+	}
+
+	// Write the part of the previous clip that is not faded, then the gap.
+	kept := j.held[:(heldFrames-overlap)*channels]
+	faded := j.held[len(kept):]
+	if err := j.w.WriteSamples(kept); err != nil {
+		return 0, 0, err
+	}
+	if j.clips > 0 && j.gapFrames > 0 {
+		if err := j.w.WriteSamples(make([]float64, j.gapFrames*channels)); err != nil {
+			return 0, 0, err
+		}
+	}
+	mixed := make([]float64, len(faded))
+	for f := 0; f < overlap; f++ {
+		t := (float64(f) + 0.5) / float64(overlap)
+		for c := 0; c < channels; c++ {
+			i := f*channels + c
+			mixed[i] = faded[i]*(1-t) + head[i]*t
+		}
+	}
+	j.held = nil
+	if err := j.push(mixed); err != nil {
+		return 0, 0, err
+	}
+	if err := j.push(head[len(mixed):]); err != nil {
+		return 0, 0, err
+	}
+	frames = int64(len(head) / channels)
+
+	for !done {
+		n, err := r.ReadSamples(chunk)
+		if err := j.push(remix(chunk[:n])); err != nil {
+			return 0, 0, err
+		}
+		frames += int64(n / r.Channels)
+		if err == io.EOF {
+			done = true
+		} else if err != nil {
+			return 0, 0, fmt.Errorf("failed to read samples: %v", err)
+		}
+	}
+
+	j.clips++
+	j.frames = start + frames
+	return start, frames, nil
+}
+
+// push appends samples to the held ones and writes all but the last
+// crossfade of them.
+func (j *Joiner) push(samples []float64) error {
+	j.held = append(j.held, samples...)
+	keep := j.fadeFrames * j.w.Channels
+	if len(j.held) <= keep {
+		return nil
+	}
+	if err := j.w.WriteSamples(j.held[:len(j.held)-keep]); err != nil {
+		return err
+	}
+	j.held = append(j.held[:0], j.held[len(j.held)-keep:]...)
+	return nil
+}
+
+// Close writes the end of the last clip and closes the writer.
+func (j *Joiner) Close() error {
+	if err := j.w.WriteSamples(j.held); err != nil {
+		return err
+	}
+	j.held = nil
+	return j.w.Close()
+}
+
+// ToInt16 converts a sample in [-1, 1] to 16-bit PCM, clipping out of range values.
+func ToInt16(v float64) int16 {
+	v = math.Round(v * 32767)
+	switch {
+	case v > math.MaxInt16:
+		return math.MaxInt16
+	case v < math.MinInt16:
+		return math.MinInt16
+	}
+	return int16(v)
+}
@@ -0,0 +1,311 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	samples := []float64{0, 0.5, -0.5, 1, -1, 0.25}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, 8000, 2, samples))
+	assert.Equal(t, 44+len(samples)*2, buf.Len())
+
+	decoded, err := Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, Format{SampleRate: 8000, Channels: 2, BitsPerSample: 16}, decoded.Format)
+	assert.Equal(t, 3, decoded.Frames())
+	require.Len(t, decoded.Samples, len(samples))
+	for i, want := range samples {
+		assert.InDelta(t, want, decoded.Samples[i], 1.0/32767)
+	}
+	assert.InDeltaSlice(t, []float64{0.25, 0.25, -0.375}, decoded.Mono(), 1.0/32767)
+}
+
+func TestEncode_Clips(t *testing.T) {
+	assert.Equal(t, int16(32767), ToInt16(2))
+	assert.Equal(t, int16(-32768), ToInt16(-2))
+}
+
+func TestReader_SkipsUnknownChunksAndReadsFloat(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF\x00\x00\x00\x00WAVE")
+	// An odd-sized LIST chunk that must be skipped including its pad byte.
+	buf.WriteString("LIST\x03\x00\x00\x00abc\x00")
+	fmtChunk := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtChunk[0:2], formatFloat)
+	binary.LittleEndian.PutUint16(fmtChunk[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtChunk[4:8], 16000)
+	binary.LittleEndian.PutUint16(fmtChunk[14:16], 32)
+	buf.WriteString("fmt \x10\x00\x00\x00")
+	buf.Write(fmtChunk)
+	buf.WriteString("data\xff\xff\xff\xff")
+	for _, v := range []float32{0.5, -0.25} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+
+	r, err := NewReader(&buf)
+	require.NoError(t, err)
+	assert.True(t, r.Float)
+	assert.Equal(t, int64(-1), r.Frames())
+
+	samples := make([]float64, 8)
+	n, err := r.ReadSamples(samples)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.5, -0.25}, samples[:n])
+
+	_, err = r.ReadSamples(samples)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestNewReader_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"not riff", "ID3\x04\x00\x00\x00\x00\x00\x00\x00\x00"},
+		{"no data chunk", "RIFF\x00\x00\x00\x00WAVE"},
+		{"data before fmt", "RIFF\x00\x00\x00\x00WAVEdata\x00\x00\x00\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(bytes.NewReader([]byte(tt.input)))
+			assert.True(t, errors.Is(err, ErrInvalid), "got %v", err)
+		})
+	}
+}
+
+func TestBuffer_Slice(t *testing.T) {
+	b := &Buffer{Format: Format{SampleRate: 4, Channels: 2}, Samples: []float64{0, 1, 2, 3, 4, 5, 6, 7}}
+
+	assert.Equal(t, []float64{2, 3, 4, 5}, b.Slice(0.25, 0.75).Samples)
+	assert.Equal(t, b.Samples, b.Slice(-1, 10).Samples)
+	assert.Empty(t, b.Slice(0.75, 0.25).Samples)
+}
+
+func TestDecodeRange(t *testing.T) {
+	samples := []float64{0, 0.125, 0.25, 0.375, 0.5, 0.625, 0.75, 0.875}
+	var encoded bytes.Buffer
+	require.NoError(t, Encode(&encoded, 4, 2, samples))
+
+	decoded, err := DecodeRange(bytes.NewReader(encoded.Bytes()), 0.25, 0.75)
+	require.NoError(t, err)
+	assert.Equal(t, Format{SampleRate: 4, Channels: 2, BitsPerSample: 16}, decoded.Format)
+	assert.InDeltaSlice(t, []float64{0.25, 0.375, 0.5, 0.625}, decoded.Samples, 1.0/32767)
+
+	decoded, err = DecodeRange(bytes.NewReader(encoded.Bytes()), 0.5, 5)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.5, 0.625, 0.75, 0.875}, decoded.Samples, 1.0/32767)
+}
+
+func TestBuffer_Remix(t *testing.T) {
+	mono := &Buffer{Format: Format{SampleRate: 4, Channels: 1}, Samples: []float64{0.5, -0.5}}
+	stereo := mono.Remix(2)
+	assert.Equal(t, 2, stereo.Channels)
+	assert.Equal(t, []float64{0.5, 0.5, -0.5, -0.5}, stereo.Samples)
+	assert.Same(t, stereo, stereo.Remix(2))
+	assert.Equal(t, []float64{0.5, -0.5}, stereo.Remix(1).Samples)
+}
+
+// Concat joins whole buffers the way Joiner streams them, separating them by
+// gap seconds of silence or overlapping them by a linear crossfade of
+// crossfade seconds. It is the reference Joiner is tested against, and
+// returns the joined buffer and the frame each input starts at.
+func Concat(buffers []*Buffer, gap, crossfade float64) (*Buffer, []int, error) {
+	if len(buffers) == 0 {
+		return nil, nil, errors.New("nothing to concatenate")
+	}
+	format := buffers[0].Format
+	for _, b := range buffers[1:] {
+		if b.SampleRate != format.SampleRate || b.Channels != format.Channels {
+			return nil, nil, fmt.Errorf("cannot concatenate %d Hz/%d channel audio with %d Hz/%d channel audio",
+				b.SampleRate, b.Channels, format.SampleRate, format.Channels)
+		}
+	}
+
+	gapFrames := int(math.Round(gap * float64(format.SampleRate)))
+	fadeFrames := int(math.Round(crossfade * float64(format.SampleRate)))
+	channels := format.Channels
+
+	out := &Buffer{Format: format}
+	starts := make([]int, len(buffers))
+	prevFrames := 0
+	for i, b := range buffers {
+		frames := b.Frames()
+		start := out.Frames()
+		overlap := 0
+		if i > 0 {
+			start += gapFrames
+			overlap = min(fadeFrames, frames, prevFrames)
+			start -= overlap
+		}
+		starts[i] = start
+
+		// Mix the overlapping frames, then append the rest.
+		for f := 0; f < overlap; f++ {
+			t := (float64(f) + 0.5) / float64(overlap)
+			for c := 0; c < channels; c++ {
+				j := (start+f)*channels + c
+				out.Samples[j] = out.Samples[j]*(1-t) + b.Samples[f*channels+c]*t
+			}
+		}
+		if i > 0 {
+			out.Samples = append(out.Samples, make([]float64, gapFrames*channels)...)
+		}
+		out.Samples = append(out.Samples, b.Samples[overlap*channels:]...)
+		prevFrames = frames
+	}
+	return out, starts, nil
+}
+
+func TestConcat(t *testing.T) {
+	format := Format{SampleRate: 4, Channels: 1}
+	a := &Buffer{Format: format, Samples: []float64{1, 1, 1, 1}}
+	b := &Buffer{Format: format, Samples: []float64{-1, -1}}
+
+	t.Run("gap", func(t *testing.T) {
+		out, starts, err := Concat([]*Buffer{a, b}, 0.5, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 1, 1, 1, 0, 0, -1, -1}, out.Samples)
+		assert.Equal(t, []int{0, 6}, starts)
+	})
+
+	t.Run("crossfade", func(t *testing.T) {
+		out, starts, err := Concat([]*Buffer{a, b}, 0, 0.5)
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 1, 0.5, -0.5}, out.Samples)
+		assert.Equal(t, []int{0, 2}, starts)
+	})
+
+	t.Run("crossfade longer than buffer", func(t *testing.T) {
+		out, starts, err := Concat([]*Buffer{a, b}, 0, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 4, out.Frames())
+		assert.Equal(t, []int{0, 2}, starts)
+	})
+
+	t.Run("mismatched formats", func(t *testing.T) {
+		stereo := &Buffer{Format: Format{SampleRate: 4, Channels: 2}, Samples: []float64{0, 0}}
+		_, _, err := Concat([]*Buffer{a, stereo}, 0, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, _, err := Concat(nil, 0, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "*.wav")
+	require.NoError(t, err)
+	defer f.Close()
+
+	w, err := NewWriter(f, 8000, 2)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteSamples([]float64{0.5, -0.5}))
+	require.NoError(t, w.WriteSamples([]float64{0.25, -0.25, 0, 0}))
+	assert.Equal(t, int64(3), w.Frames())
+	require.NoError(t, w.Close())
+
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	buf, err := Decode(f)
+	require.NoError(t, err)
+	assert.Equal(t, Format{SampleRate: 8000, Channels: 2, BitsPerSample: 16}, buf.Format)
+	assert.InDeltaSlice(t, []float64{0.5, -0.5, 0.25, -0.25, 0, 0}, buf.Samples, 1.0/32767)
+}
+
+// join streams the buffers through a Joiner and decodes the result. The
+// samples are quantized to 16 bits on the way in and again on the way out.
+func join(t *testing.T, buffers []*Buffer, channels int, gap, crossfade float64) (*Buffer, []int) {
+	f, err := os.CreateTemp(t.TempDir(), "*.wav")
+	require.NoError(t, err)
+	defer f.Close()
+
+	w, err := NewWriter(f, buffers[0].SampleRate, channels)
+	require.NoError(t, err)
+	j := NewJoiner(w, gap, crossfade)
+	var starts []int
+	for _, b := range buffers {
+		var encoded bytes.Buffer
+		require.NoError(t, Encode(&encoded, b.SampleRate, b.Channels, b.Samples))
+		r, err := NewReader(&encoded)
+		require.NoError(t, err)
+		start, frames, err := j.Append(r)
+		require.NoError(t, err)
+		assert.Equal(t, int64(b.Frames()), frames)
+		starts = append(starts, int(start))
+	}
+	require.NoError(t, j.Close())
+
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	out, err := Decode(f)
+	require.NoError(t, err)
+	return out, starts
+}
+
+func TestJoiner(t *testing.T) {
+	format := Format{SampleRate: 4, Channels: 1}
+	a := &Buffer{Format: format, Samples: []float64{1, 1, 1, 1}}
+	b := &Buffer{Format: format, Samples: []float64{-1, -1}}
+	long := &Buffer{Format: format, Samples: make([]float64, 20000)}
+	for i := range long.Samples {
+		long.Samples[i] = float64(i%7)/7 - 0.5
+	}
+
+	// The joined file matches what Concat builds in memory.
+	for _, tt := range []struct {
+		name           string
+		buffers        []*Buffer
+		gap, crossfade float64
+	}{
+		{"gap", []*Buffer{a, b}, 0.5, 0},
+		{"crossfade", []*Buffer{a, b}, 0, 0.5},
+		{"crossfade longer than buffer", []*Buffer{a, b}, 0, 2},
+		{"clips longer than a chunk", []*Buffer{long, a, long}, 0, 1.5},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			want, wantStarts, err := Concat(tt.buffers, tt.gap, tt.crossfade)
+			require.NoError(t, err)
+			got, starts := join(t, tt.buffers, 1, tt.gap, tt.crossfade)
+			assert.InDeltaSlice(t, want.Samples, got.Samples, 1e-3)
+			assert.Equal(t, wantStarts, starts)
+		})
+	}
+
+	t.Run("remixes channels", func(t *testing.T) {
+		stereo := &Buffer{Format: Format{SampleRate: 4, Channels: 2}, Samples: []float64{0.5, 0.5}}
+		got, starts := join(t, []*Buffer{b, stereo}, 2, 0, 0)
+		assert.InDeltaSlice(t, []float64{-1, -1, -1, -1, 0.5, 0.5}, got.Samples, 1e-3)
+		assert.Equal(t, []int{0, 2}, starts)
+	})
+
+	t.Run("mismatched sample rate", func(t *testing.T) {
+		f, err := os.CreateTemp(t.TempDir(), "*.wav")
+		require.NoError(t, err)
+		defer f.Close()
+		w, err := NewWriter(f, 8, 1)
+		require.NoError(t, err)
+
+		var encoded bytes.Buffer
+		require.NoError(t, Encode(&encoded, 4, 1, a.Samples))
+		r, err := NewReader(&encoded)
+		require.NoError(t, err)
+		_, _, err = NewJoiner(w, 0, 0).Append(r)
+		assert.Error(t, err)
+	})
+}
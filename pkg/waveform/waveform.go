@@ -0,0 +1,215 @@
+// Package waveform computes min/max peak data in the audiowaveform format
+// used by peaks.js, and reads and writes its binary (.dat) representation.
+package waveform
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+)
+
+const (
+	version = 2
+
+	// flag8Bit marks 8-bit data in the binary header.
+	flag8Bit = 1
+)
+
+// Options controls the resolution of computed peaks.
+type Options struct {
+	PixelsPerSecond int // Min/max pairs per second of audio
+	Bits            int // 8 or 16
+	Channels        int // 1 mixes all channels down, 2 keeps up to two channels
+}
+
+// Validate reports whether the options are usable.
+func (o Options) Validate() error {
+	if o.PixelsPerSecond < 1 {
+		return fmt.Errorf("pixels per second must be positive, got %d", o.PixelsPerSecond)
+	}
+	if o.Bits != 8 && o.Bits != 16 {
+		return fmt.Errorf("bits must be 8 or 16, got %d", o.Bits)
+	}
+	if o.Channels != 1 && o.Channels != 2 {
+		return fmt.Errorf("channels must be 1 or 2, got %d", o.Channels)
+	}
+	return nil
+}
+
+// Waveform is peak data in audiowaveform's JSON layout. Data holds, for each
+// pixel, a min and max value per channel.
+type Waveform struct {
+	Version         int     `json:"version"`
+	Channels        int     `json:"channels"`
+	SampleRate      int     `json:"sample_rate"`
+	SamplesPerPixel int     `json:"samples_per_pixel"`
+	Bits            int     `json:"bits"`
+	Length          int     `json:"length"`
+	Data            []int16 `json:"data"`
+}
+
+// Compute reads all samples from r and reduces them to min/max peaks.
+func Compute(r *wav.Reader, opts Options) (*Waveform, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	channels := opts.Channels
+	if r.Channels < channels {
+		channels = r.Channels
+	}
+	samplesPerPixel := r.SampleRate / opts.PixelsPerSecond
+	if samplesPerPixel < 1 {
+		samplesPerPixel = 1
+	}
+
+	w := &Waveform{
+		Version:         version,
+		Channels:        channels,
+		SampleRate:      r.SampleRate,
+		SamplesPerPixel: samplesPerPixel,
+		Bits:            opts.Bits,
+	}
+
+	mins := make([]float64, channels)
+	maxs := make([]float64, channels)
+	reset := func() {
+		for c := range mins {
+			mins[c], maxs[c] = math.Inf(1), math.Inf(-1)
+		}
+	}
+	flush := func() {
+		for c := range mins {
+			w.Data = append(w.Data, w.scale(mins[c]), w.scale(maxs[c]))
+		}
+		w.Length++
+		reset()
+	}
+	reset()
+
+	buf := make([]float64, 4096*r.Channels)
+	inPixel := 0
+	for {
+		n, err := r.ReadSamples(buf)
+		for i := 0; i < n; i += r.Channels {
+			frame := buf[i : i+r.Channels]
+			for c := 0; c < channels; c++ {
+				v := frame[c]
+				if channels == 1 && r.Channels > 1 {
+					v = mix(frame)
+				}
+				mins[c] = math.Min(mins[c], v)
+				maxs[c] = math.Max(maxs[c], v)
+			}
+			inPixel++
+			if inPixel == samplesPerPixel {
+				flush()
+				inPixel = 0
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read samples: %v", err)
+		}
+	}
+	if inPixel > 0 {
+		flush()
+	}
+	return w, nil
+}
+
+func mix(frame []float64) float64 {
+	var sum float64
+	for _, v := range frame {
+		sum += v
+	}
+	return sum / float64(len(frame))
+}
+
+func (w *Waveform) scale(v float64) int16 {
+	if w.Bits == 8 {
+		return int16(math.Max(-128, math.Min(127, math.Round(v*127))))
+	}
+	return wav.ToInt16(v)
+}
+
+// header is the binary (.dat) version 2 header.
+type header struct {
+	Version         int32
+	Flags           uint32
+	SampleRate      int32
+	SamplesPerPixel int32
+	Length          uint32
+	Channels        int32
+}
+
+// WriteTo writes the waveform in audiowaveform's binary format.
+func (w *Waveform) WriteTo(out io.Writer) (int64, error) {
+	h := header{
+		Version:         version,
+		SampleRate:      int32(w.SampleRate),
+		SamplesPerPixel: int32(w.SamplesPerPixel),
+		Length:          uint32(w.Length),
+		Channels:        int32(w.Channels),
+	}
+	var data interface{} = w.Data
+	size := int64(len(w.Data) * 2)
+	if w.Bits == 8 {
+		h.Flags = flag8Bit
+		data8 := make([]int8, len(w.Data))
+		for i, v := range w.Data {
+			data8[i] = int8(v)
+		}
+		data = data8
+		size = int64(len(w.Data))
+	}
+
+	if err := binary.Write(out, binary.LittleEndian, h); err != nil {
+		return 0, fmt.Errorf("failed to write waveform header: %v", err)
+	}
+	if err := binary.Write(out, binary.LittleEndian, data); err != nil {
+		return 0, fmt.Errorf("failed to write waveform data: %v", err)
+	}
+	return int64(binary.Size(h)) + size, nil
+}
+
+// Read parses a waveform in audiowaveform's binary format.
+func Read(in io.Reader) (*Waveform, error) {
+	var h header
+	if err := binary.Read(in, binary.LittleEndian, &h); err != nil {
+		return nil, fmt.Errorf("failed to read waveform header: %v", err)
+	}
+	if h.Version != version {
+		return nil, fmt.Errorf("unsupported waveform version %d", h.Version)
+	}
+
+	w := &Waveform{
+		Version:         version,
+		Channels:        int(h.Channels),
+		SampleRate:      int(h.SampleRate),
+		SamplesPerPixel: int(h.SamplesPerPixel),
+		Bits:            16,
+		Length:          int(h.Length),
+		Data:            make([]int16, int(h.Length)*int(h.Channels)*2),
+	}
+	if h.Flags&flag8Bit != 0 {
+		w.Bits = 8
+		data8 := make([]int8, len(w.Data))
+		if err := binary.Read(in, binary.LittleEndian, data8); err != nil {
+			return nil, fmt.Errorf("failed to read waveform data: %v", err)
+		}
+		for i, v := range data8 {
+			w.Data[i] = int16(v)
+		}
+		return w, nil
+	}
+	if err := binary.Read(in, binary.LittleEndian, w.Data); err != nil {
+		return nil, fmt.Errorf("failed to read waveform data: %v", err)
+	}
+	return w, nil
+}
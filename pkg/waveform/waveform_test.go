@@ -0,0 +1,92 @@
+package waveform
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ardfard/sb-test/pkg/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReader(t *testing.T, sampleRate, channels int, samples []float64) *wav.Reader {
+	var buf bytes.Buffer
+	require.NoError(t, wav.Encode(&buf, sampleRate, channels, samples))
+	r, err := wav.NewReader(&buf)
+	require.NoError(t, err)
+	return r
+}
+
+func TestCompute(t *testing.T) {
+	// Two channels at 8 Hz: the left channel ramps up, the right is silent.
+	stereo := []float64{
+		0, 0, 0.5, 0, -0.5, 0, 1, 0,
+		0.25, 0, -1, 0, 0.5, 0, 0, 0,
+		0.75, 0,
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected *Waveform
+	}{
+		{
+			name: "mono 16-bit",
+			opts: Options{PixelsPerSecond: 2, Bits: 16, Channels: 1},
+			expected: &Waveform{
+				Version: 2, Channels: 1, SampleRate: 8, SamplesPerPixel: 4, Bits: 16, Length: 3,
+				Data: []int16{-8192, 16383, -16383, 8192, 12287, 12287},
+			},
+		},
+		{
+			name: "stereo 8-bit",
+			opts: Options{PixelsPerSecond: 2, Bits: 8, Channels: 2},
+			expected: &Waveform{
+				Version: 2, Channels: 2, SampleRate: 8, SamplesPerPixel: 4, Bits: 8, Length: 3,
+				Data: []int16{-64, 127, 0, 0, -127, 64, 0, 0, 95, 95, 0, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := Compute(newReader(t, 8, 2, stereo), tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, w)
+		})
+	}
+}
+
+func TestCompute_StereoOptionWithMonoInput(t *testing.T) {
+	w, err := Compute(newReader(t, 4, 1, []float64{0.5, -0.5}), Options{PixelsPerSecond: 100, Bits: 8, Channels: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 1, w.Channels)
+	assert.Equal(t, 1, w.SamplesPerPixel)
+	assert.Equal(t, []int16{64, 64, -64, -64}, w.Data)
+}
+
+func TestOptions_Validate(t *testing.T) {
+	assert.NoError(t, Options{PixelsPerSecond: 100, Bits: 8, Channels: 1}.Validate())
+	assert.Error(t, Options{PixelsPerSecond: 0, Bits: 8, Channels: 1}.Validate())
+	assert.Error(t, Options{PixelsPerSecond: 100, Bits: 12, Channels: 1}.Validate())
+	assert.Error(t, Options{PixelsPerSecond: 100, Bits: 8, Channels: 3}.Validate())
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, bits := range []int{8, 16} {
+		w := &Waveform{
+			Version: 2, Channels: 2, SampleRate: 44100, SamplesPerPixel: 441, Bits: bits, Length: 2,
+			Data: []int16{-10, 20, -30, 40, -50, 60, -70, 80},
+		}
+
+		var buf bytes.Buffer
+		n, err := w.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+		assert.Equal(t, int64(24+len(w.Data)*bits/8), n)
+
+		got, err := Read(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, w, got)
+	}
+}
@@ -0,0 +1,53 @@
+// Package webvtt writes WebVTT caption files.
+package webvtt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Cue is a caption shown from Start to End, in seconds from the start.
+type Cue struct {
+	ID    string // Optional identifier, without line breaks or "-->"
+	Start float64
+	End   float64
+	Text  string
+}
+
+// escaper escapes the characters that start markup in cue text.
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Write writes cues in order as a WebVTT file. Blank lines in the text of a
+// cue, which would end it early, are dropped.
+func Write(w io.Writer, cues []Cue) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("WEBVTT\n")
+	for _, cue := range cues {
+		if strings.ContainsAny(cue.ID, "\r\n") || strings.Contains(cue.ID, "-->") {
+			return fmt.Errorf("invalid cue identifier %q", cue.ID)
+		}
+		if cue.Start < 0 || cue.End < cue.Start {
+			return fmt.Errorf("cue %q: invalid times %v to %v", cue.ID, cue.Start, cue.End)
+		}
+		b.WriteString("\n")
+		if cue.ID != "" {
+			b.WriteString(cue.ID + "\n")
+		}
+		fmt.Fprintf(b, "%s --> %s\n", timestamp(cue.Start), timestamp(cue.End))
+		for _, line := range strings.Split(strings.ReplaceAll(cue.Text, "\r\n", "\n"), "\n") {
+			if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+				b.WriteString(escaper.Replace(line) + "\n")
+			}
+		}
+	}
+	return b.Flush()
+}
+
+// timestamp formats seconds as hh:mm:ss.ttt.
+func timestamp(seconds float64) string {
+	ms := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
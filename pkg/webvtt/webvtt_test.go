@@ -0,0 +1,40 @@
+package webvtt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, []Cue{
+		{ID: "1", Start: 0.25, End: 1.0005, Text: "<noise> & hum"},
+		{Start: 3725.5, End: 3726, Text: "two\n\nlines\r\nhere"},
+	}))
+
+	assert.Equal(t, `WEBVTT
+
+1
+00:00:00.250 --> 00:00:01.001
+&lt;noise&gt; &amp; hum
+
+01:02:05.500 --> 01:02:06.000
+two
+lines
+here
+`, buf.String())
+}
+
+func TestWrite_Invalid(t *testing.T) {
+	for _, cue := range []Cue{
+		{ID: "a --> b", End: 1},
+		{ID: "a\nb", End: 1},
+		{Start: 2, End: 1},
+		{Start: -1, End: 1},
+	} {
+		assert.Error(t, Write(&bytes.Buffer{}, []Cue{cue}), cue)
+	}
+}
@@ -24,6 +24,7 @@ docker run -d \
     --rm \
     --name "${CONTAINER_NAME}" \
     -p 8080:8080 \
+    -e SHARE_SECRET="$(head -c 32 /dev/urandom | od -An -tx1 | tr -d ' \n')" \
     "${IMAGE_NAME}:${IMAGE_TAG}"
 
 # Wait for the service to be ready